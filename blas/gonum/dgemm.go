@@ -5,7 +5,6 @@
 package gonum
 
 import (
-	"runtime"
 	"sync"
 
 	"gonum.org/v1/gonum/blas"
@@ -151,16 +150,19 @@ func dgemmParallel(aTrans, bTrans bool, m, n, k int, a []float64, lda int, b []f
 
 	maxKLen := k
 	parBlocks := blocks(m, blockSize) * blocks(n, blockSize)
-	if parBlocks < minParBlock {
+	nWorkers := workers()
+	if parBlocks < minParBlock || nWorkers == 1 {
 		// The matrix multiplication is small in the dimensions where it can be
-		// computed concurrently. Just do it in serial.
+		// computed concurrently, or concurrency has been disabled. Just do it
+		// in serial.
 		dgemmSerial(aTrans, bTrans, m, n, k, a, lda, b, ldb, c, ldc, alpha)
 		return
 	}
 
 	// workerLimit acts a number of maximum concurrent workers,
-	// with the limit set to the number of procs available.
-	workerLimit := make(chan struct{}, runtime.GOMAXPROCS(0))
+	// with the limit set by SetMaxWorkers or to the number of procs
+	// available.
+	workerLimit := make(chan struct{}, nWorkers)
 
 	// wg is used to wait for all
 	var wg sync.WaitGroup
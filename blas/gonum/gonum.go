@@ -8,12 +8,40 @@ package gonum
 
 import (
 	"math"
+	"runtime"
+	"sync/atomic"
 
 	"gonum.org/v1/gonum/internal/math32"
 )
 
 type Implementation struct{}
 
+// maxWorkers is the maximum number of concurrent workers used by the
+// parallel [SD]gemm kernels. If it is not positive, GOMAXPROCS is used.
+var maxWorkers int32
+
+// SetMaxWorkers sets the maximum number of goroutines that Dgemm and Sgemm
+// run concurrently when multiplying large matrices to n, and returns the
+// previous value. If n is zero or less, the limit is the value of
+// runtime.GOMAXPROCS, which is the default. Setting n to one computes all
+// products serially. SetMaxWorkers is safe for concurrent use, and the limit
+// applies to subsequent calls.
+func SetMaxWorkers(n int) (prev int) {
+	if n < 0 {
+		n = 0
+	}
+	return int(atomic.SwapInt32(&maxWorkers, int32(n)))
+}
+
+// workers returns the maximum number of concurrent workers for the parallel
+// [SD]gemm kernels.
+func workers() int {
+	if n := atomic.LoadInt32(&maxWorkers); n > 0 {
+		return int(n)
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
 // [SD]gemm behavior constants. These are kept here to keep them out of the
 // way during single precision code genration.
 const (
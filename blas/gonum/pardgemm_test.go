@@ -5,6 +5,7 @@
 package gonum
 
 import (
+	"runtime"
 	"testing"
 
 	"golang.org/x/exp/rand"
@@ -127,6 +128,26 @@ func TestDgemmParallel(t *testing.T) {
 	}
 }
 
+func TestSetMaxWorkers(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	defer SetMaxWorkers(SetMaxWorkers(0))
+	for _, n := range []int{1, 2, 3, 0, -1} {
+		SetMaxWorkers(n)
+		want := n
+		if n <= 0 {
+			want = runtime.GOMAXPROCS(0)
+		}
+		if got := workers(); got != want {
+			t.Errorf("unexpected number of workers for SetMaxWorkers(%d): got %d want %d", n, got, want)
+		}
+		size := blockSize*minParBlock + 3
+		testMatchParallelSerial(t, rnd, n, blas.NoTrans, blas.Trans, size, size, blockSize+1, 1.5)
+	}
+	if prev := SetMaxWorkers(4); prev != 0 {
+		t.Errorf("unexpected previous limit: got %d want 0", prev)
+	}
+}
+
 func testMatchParallelSerial(t *testing.T, rnd *rand.Rand, i int, tA, tB blas.Transpose, m, n, k int, alpha float64) {
 	var (
 		rowA, colA int
@@ -0,0 +1,121 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmplxs
+
+import "gonum.org/v1/gonum/internal/asm/c128"
+
+// Add adds, element-wise, the elements of s and dst, and stores in dst.
+// Panics if the lengths of dst and s do not match.
+func Add(dst, s []complex128) {
+	if len(dst) != len(s) {
+		panic("cmplxs: length of the slices do not match")
+	}
+	c128.AxpyUnitaryTo(dst, 1, s, dst)
+}
+
+// AddTo adds, element-wise, the elements of s and t and
+// stores the result in dst. Panics if the lengths of s, t and dst do not match.
+func AddTo(dst, s, t []complex128) []complex128 {
+	if len(s) != len(t) {
+		panic("cmplxs: length of adders do not match")
+	}
+	if len(dst) != len(s) {
+		panic("cmplxs: length of destination does not match length of adder")
+	}
+	c128.AxpyUnitaryTo(dst, 1, s, t)
+	return dst
+}
+
+// AddScaled performs dst = dst + alpha * s.
+// It panics if the lengths of dst and s are not equal.
+func AddScaled(dst []complex128, alpha complex128, s []complex128) {
+	if len(dst) != len(s) {
+		panic("cmplxs: length of destination and source to not match")
+	}
+	c128.AxpyUnitaryTo(dst, alpha, s, dst)
+}
+
+// AddScaledTo performs dst = y + alpha * s, where alpha is a scalar,
+// and dst, y and s are all slices.
+// It panics if the lengths of dst, y, and s are not equal.
+//
+// At the return of the function, dst[i] = y[i] + alpha * s[i]
+func AddScaledTo(dst, y []complex128, alpha complex128, s []complex128) []complex128 {
+	if len(dst) != len(s) || len(dst) != len(y) {
+		panic("cmplxs: lengths of slices do not match")
+	}
+	c128.AxpyUnitaryTo(dst, alpha, s, y)
+	return dst
+}
+
+// Dot computes the conjugate dot product of s and t, i.e.
+// sum_{i = 1}^N conj(s[i])*t[i].
+// Consequently Dot(s, t) == cmplx.Conj(Dot(t, s)).
+// A panic will occur if lengths of arguments do not match.
+func Dot(s, t []complex128) complex128 {
+	if len(s) != len(t) {
+		panic("cmplxs: lengths of the slices do not match")
+	}
+	return c128.DotcUnitary(s, t)
+}
+
+// DotUnconj computes the unconjugated dot product of s and t, i.e.
+// sum_{i = 1}^N s[i]*t[i].
+// A panic will occur if lengths of arguments do not match.
+func DotUnconj(s, t []complex128) complex128 {
+	if len(s) != len(t) {
+		panic("cmplxs: lengths of the slices do not match")
+	}
+	return c128.DotuUnitary(s, t)
+}
+
+// Scale multiplies every element in dst by the scalar c.
+func Scale(c complex128, dst []complex128) {
+	if len(dst) > 0 {
+		c128.ScalUnitary(c, dst)
+	}
+}
+
+// ScaleTo multiplies the elements in s by c and stores the result in dst.
+func ScaleTo(dst []complex128, c complex128, s []complex128) []complex128 {
+	if len(dst) != len(s) {
+		panic("cmplxs: lengths of slices do not match")
+	}
+	if len(dst) > 0 {
+		c128.ScalUnitaryTo(dst, c, s)
+	}
+	return dst
+}
+
+// Sub subtracts, element-wise, the elements of s from dst. Panics if
+// the lengths of dst and s do not match.
+func Sub(dst, s []complex128) {
+	if len(dst) != len(s) {
+		panic("cmplxs: length of the slices do not match")
+	}
+	c128.AxpyUnitaryTo(dst, -1, s, dst)
+}
+
+// SubTo subtracts, element-wise, the elements of t from s and
+// stores the result in dst. Panics if the lengths of s, t and dst do not match.
+func SubTo(dst, s, t []complex128) []complex128 {
+	if len(s) != len(t) {
+		panic("cmplxs: length of subtractor and subtractee do not match")
+	}
+	if len(dst) != len(s) {
+		panic("cmplxs: length of destination does not match length of subtractor")
+	}
+	c128.AxpyUnitaryTo(dst, -1, t, s)
+	return dst
+}
+
+// Sum returns the sum of the elements of the slice.
+func Sum(s []complex128) complex128 {
+	var sum complex128
+	for _, v := range s {
+		sum += v
+	}
+	return sum
+}
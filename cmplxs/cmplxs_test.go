@@ -0,0 +1,144 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmplxs
+
+import (
+	"math/cmplx"
+	"testing"
+)
+
+func equal(s, t []complex128) bool {
+	if len(s) != len(t) {
+		return false
+	}
+	for i, v := range s {
+		if v != t[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return false
+}
+
+func TestAdd(t *testing.T) {
+	t.Parallel()
+	a := []complex128{1 + 2i, 3 - 1i, -2i}
+	b := []complex128{4 - 1i, 1i, 5}
+	want := []complex128{5 + 1i, 3, 5 - 2i}
+	dst := make([]complex128, len(a))
+	AddTo(dst, a, b)
+	if !equal(dst, want) {
+		t.Errorf("unexpected AddTo result: got %v, want %v", dst, want)
+	}
+	Add(a, b)
+	if !equal(a, want) {
+		t.Errorf("unexpected in-place Add result: got %v, want %v", a, want)
+	}
+	if !panics(func() { Add(a, b[:2]) }) {
+		t.Errorf("no panic with length mismatch")
+	}
+	if !panics(func() { AddTo(dst[:1], a, b) }) {
+		t.Errorf("no panic with length mismatch")
+	}
+}
+
+func TestSub(t *testing.T) {
+	t.Parallel()
+	a := []complex128{1 + 2i, 3 - 1i, -2i}
+	b := []complex128{4 - 1i, 1i, 5}
+	want := []complex128{-3 + 3i, 3 - 2i, -5 - 2i}
+	dst := make([]complex128, len(a))
+	SubTo(dst, a, b)
+	if !equal(dst, want) {
+		t.Errorf("unexpected SubTo result: got %v, want %v", dst, want)
+	}
+	Sub(a, b)
+	if !equal(a, want) {
+		t.Errorf("unexpected in-place Sub result: got %v, want %v", a, want)
+	}
+	if !panics(func() { Sub(a, b[:2]) }) {
+		t.Errorf("no panic with length mismatch")
+	}
+}
+
+func TestAddScaled(t *testing.T) {
+	t.Parallel()
+	alpha := 2 - 1i
+	a := []complex128{1 + 2i, 3 - 1i}
+	b := []complex128{1i, 2}
+	want := []complex128{2 + 4i, 7 - 3i}
+	dst := make([]complex128, len(a))
+	AddScaledTo(dst, a, alpha, b)
+	if !equal(dst, want) {
+		t.Errorf("unexpected AddScaledTo result: got %v, want %v", dst, want)
+	}
+	AddScaled(a, alpha, b)
+	if !equal(a, want) {
+		t.Errorf("unexpected in-place AddScaled result: got %v, want %v", a, want)
+	}
+	if !panics(func() { AddScaled(a, alpha, b[:1]) }) {
+		t.Errorf("no panic with length mismatch")
+	}
+}
+
+func TestScale(t *testing.T) {
+	t.Parallel()
+	a := []complex128{1 + 2i, 3 - 1i, 0}
+	want := []complex128{-1 + 3i, 4 + 2i, 0}
+	dst := make([]complex128, len(a))
+	ScaleTo(dst, 1i+1, a)
+	if !equal(dst, want) {
+		t.Errorf("unexpected ScaleTo result: got %v, want %v", dst, want)
+	}
+	Scale(1i+1, a)
+	if !equal(a, want) {
+		t.Errorf("unexpected in-place Scale result: got %v, want %v", a, want)
+	}
+	Scale(2, nil)
+}
+
+func TestDot(t *testing.T) {
+	t.Parallel()
+	s := []complex128{1 + 2i, 3 - 1i, -2i}
+	u := []complex128{4 - 1i, 1i, 5}
+	got := Dot(s, u)
+	var want complex128
+	for i := range s {
+		want += cmplx.Conj(s[i]) * u[i]
+	}
+	if got != want {
+		t.Errorf("unexpected Dot result: got %v, want %v", got, want)
+	}
+	if rev := Dot(u, s); got != cmplx.Conj(rev) {
+		t.Errorf("Dot is not conjugate symmetric: %v != conj(%v)", got, rev)
+	}
+	if norm := Dot(s, s); imag(norm) != 0 || real(norm) != 19 {
+		t.Errorf("unexpected squared norm: got %v, want 19", norm)
+	}
+	want = 0
+	for i := range s {
+		want += s[i] * u[i]
+	}
+	if got := DotUnconj(s, u); got != want {
+		t.Errorf("unexpected DotUnconj result: got %v, want %v", got, want)
+	}
+	if !panics(func() { Dot(s, u[:1]) }) {
+		t.Errorf("no panic with length mismatch")
+	}
+}
+
+func TestSum(t *testing.T) {
+	t.Parallel()
+	if got := Sum([]complex128{1 + 2i, 3 - 1i, -2i}); got != 4-1i {
+		t.Errorf("unexpected Sum result: got %v, want %v", got, 4-1i)
+	}
+}
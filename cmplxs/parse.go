@@ -0,0 +1,344 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmplxs
+
+import (
+	"fmt"
+	"math/cmplx"
+	"strconv"
+	"strings"
+)
+
+// Format returns the string representation of c in the parenthesized form
+// (±N±Ni), for example (1+4i) or (1-4i). Both parts are always written, and
+// the shortest representation that exactly represents each part is used, so
+// that Parse(Format(c)) returns c. Infinite and NaN parts are written as Inf
+// and NaN.
+func Format(c complex128) string {
+	re := strconv.FormatFloat(real(c), 'g', -1, 64)
+	im := strconv.FormatFloat(imag(c), 'g', -1, 64)
+	if im[0] != '+' && im[0] != '-' {
+		im = "+" + im
+	}
+	return "(" + re + im + "i)"
+}
+
+// Parse converts the string s to a complex128. The string may be parenthesized
+// and has the format [±]N±Ni. The order of the components is not strict.
+// The strings "NaN" and "Inf", in any case, are parsed as cmplx.NaN() and
+// cmplx.Inf() respectively.
+func Parse(s string) (complex128, error) {
+	if len(s) == 0 {
+		return 0, parseError{state: -1}
+	}
+	orig := s
+
+	wantClose := s[0] == '('
+	if wantClose {
+		if s[len(s)-1] != ')' {
+			return 0, parseError{string: orig, state: -1}
+		}
+		s = s[1 : len(s)-1]
+	}
+	if len(s) == 0 {
+		return 0, parseError{string: orig, state: -1}
+	}
+	switch s[0] {
+	case 'n', 'N':
+		if strings.ToLower(s) == "nan" {
+			return cmplx.NaN(), nil
+		}
+	case 'i', 'I':
+		if strings.ToLower(s) == "inf" {
+			return cmplx.Inf(), nil
+		}
+	}
+
+	var re, im float64
+	var parts byte
+	for i := 0; i < 2; i++ {
+		beg, end, p, err := floatPart(s)
+		if err != nil {
+			return 0, parseError{string: orig, state: -1}
+		}
+		if parts&(1<<p) != 0 {
+			return 0, parseError{string: orig, state: -1}
+		}
+		parts |= 1 << p
+		var v float64
+		switch s[:end] {
+		case "-":
+			if len(s[end:]) == 0 {
+				return 0, parseError{string: orig, state: -1}
+			}
+			v = -1
+		case "+":
+			if len(s[end:]) == 0 {
+				return 0, parseError{string: orig, state: -1}
+			}
+			v = 1
+		default:
+			v, err = strconv.ParseFloat(s[beg:end], 64)
+			if err != nil {
+				return 0, err
+			}
+		}
+		s = s[end:]
+		switch p {
+		case 0:
+			re = v
+		case 1:
+			im = v
+			s = s[1:]
+		}
+		if len(s) == 0 {
+			return complex(re, im), nil
+		}
+		if !isSign(rune(s[0])) {
+			return 0, parseError{string: orig, state: -1}
+		}
+	}
+
+	return 0, parseError{string: orig, state: -1}
+}
+
+// ParseSlice converts the string s to a []complex128. The elements of s are
+// in the format accepted by Parse and are separated by commas and/or white
+// space. The sequence may be enclosed in square brackets and may end with
+// a trailing separator. An empty sequence returns an empty, non-nil slice.
+//
+// If an element fails to parse, the returned error identifies the index and
+// the text of the element.
+func ParseSlice(s string) ([]complex128, error) {
+	orig := s
+	s = strings.TrimSpace(s)
+	if len(s) != 0 && s[0] == '[' {
+		if s[len(s)-1] != ']' {
+			return nil, parseError{string: orig, state: -1}
+		}
+		s = s[1 : len(s)-1]
+	}
+
+	v := []complex128{}
+	fields := strings.Split(s, ",")
+	for i, f := range fields {
+		elems := strings.Fields(f)
+		if len(elems) == 0 {
+			// Only a trailing separator or an entirely empty
+			// sequence is allowed to produce an empty field.
+			if i == len(fields)-1 {
+				break
+			}
+			return nil, parseError{string: f, state: -1, elem: true, index: len(v)}
+		}
+		for _, e := range elems {
+			c, err := Parse(e)
+			if err != nil {
+				return nil, parseError{string: e, state: -1, elem: true, index: len(v)}
+			}
+			v = append(v, c)
+		}
+	}
+	return v, nil
+}
+
+func floatPart(s string) (beg, end int, part uint, err error) {
+	const (
+		wantMantSign = iota
+		wantMantIntInit
+		wantMantInt
+		wantMantFrac
+		wantExpSign
+		wantExpInt
+
+		wantInfN
+		wantInfF
+		wantCloseInf
+
+		wantNaNA
+		wantNaNN
+		wantCloseNaN
+	)
+	var i, state int
+	var r rune
+	for i, r = range s {
+		switch state {
+		case wantMantSign:
+			switch {
+			default:
+				return 0, i, 0, parseError{string: s, state: state, rune: r}
+			case isSign(r):
+				state = wantMantIntInit
+			case isDigit(r):
+				state = wantMantInt
+			case isDot(r):
+				state = wantMantFrac
+			case r == 'i', r == 'I':
+				state = wantInfN
+			case r == 'n', r == 'N':
+				state = wantNaNA
+			}
+
+		case wantMantIntInit:
+			switch {
+			default:
+				return 0, i, 0, parseError{string: s, state: state, rune: r}
+			case isDigit(r):
+				state = wantMantInt
+			case isDot(r):
+				state = wantMantFrac
+			case r == 'i':
+				// We need to sneak a look-ahead here.
+				if i == len(s)-1 || s[i+1] == '-' || s[i+1] == '+' {
+					return 0, i, 1, nil
+				}
+				fallthrough
+			case r == 'I':
+				state = wantInfN
+			case r == 'n', r == 'N':
+				state = wantNaNA
+			}
+
+		case wantMantInt:
+			switch {
+			default:
+				return 0, i, 0, parseError{string: s, state: state, rune: r}
+			case isDigit(r):
+				// Do nothing
+			case isDot(r):
+				state = wantMantFrac
+			case isExponent(r):
+				state = wantExpSign
+			case isSign(r):
+				return 0, i, 0, nil
+			case r == 'i':
+				return 0, i, 1, nil
+			}
+
+		case wantMantFrac:
+			switch {
+			default:
+				return 0, i, 0, parseError{string: s, state: state, rune: r}
+			case isDigit(r):
+				// Do nothing
+			case isExponent(r):
+				state = wantExpSign
+			case isSign(r):
+				return 0, i, 0, nil
+			case r == 'i':
+				return 0, i, 1, nil
+			}
+
+		case wantExpSign:
+			switch {
+			default:
+				return 0, i, 0, parseError{string: s, state: state, rune: r}
+			case isSign(r) || isDigit(r):
+				state = wantExpInt
+			}
+
+		case wantExpInt:
+			switch {
+			default:
+				return 0, i, 0, parseError{string: s, state: state, rune: r}
+			case isDigit(r):
+				// Do nothing
+			case isSign(r):
+				return 0, i, 0, nil
+			case r == 'i':
+				return 0, i, 1, nil
+			}
+
+		case wantInfN:
+			if r != 'n' && r != 'N' {
+				return 0, i, 0, parseError{string: s, state: state, rune: r}
+			}
+			state = wantInfF
+		case wantInfF:
+			if r != 'f' && r != 'F' {
+				return 0, i, 0, parseError{string: s, state: state, rune: r}
+			}
+			state = wantCloseInf
+		case wantCloseInf:
+			switch {
+			default:
+				return 0, i, 0, parseError{string: s, state: state, rune: r}
+			case isSign(r):
+				return 0, i, 0, nil
+			case r == 'i':
+				return 0, i, 1, nil
+			}
+
+		case wantNaNA:
+			if r != 'a' && r != 'A' {
+				return 0, i, 0, parseError{string: s, state: state, rune: r}
+			}
+			state = wantNaNN
+		case wantNaNN:
+			if r != 'n' && r != 'N' {
+				return 0, i, 0, parseError{string: s, state: state, rune: r}
+			}
+			state = wantCloseNaN
+		case wantCloseNaN:
+			if isSign(rune(s[0])) {
+				beg = 1
+			}
+			switch {
+			default:
+				return beg, i, 0, parseError{string: s, state: state, rune: r}
+			case isSign(r):
+				return beg, i, 0, nil
+			case r == 'i':
+				return beg, i, 1, nil
+			}
+		}
+	}
+	switch state {
+	case wantMantSign, wantExpSign, wantExpInt:
+		if state == wantExpInt && isDigit(r) {
+			break
+		}
+		return 0, i, 0, parseError{string: s, state: state, rune: r}
+	}
+	return 0, len(s), 0, nil
+}
+
+func isSign(r rune) bool {
+	return r == '+' || r == '-'
+}
+
+func isDigit(r rune) bool {
+	return '0' <= r && r <= '9'
+}
+
+func isExponent(r rune) bool {
+	return r == 'e' || r == 'E'
+}
+
+func isDot(r rune) bool {
+	return r == '.'
+}
+
+type parseError struct {
+	string string
+	state  int
+	rune   rune
+
+	// elem and index indicate that the error
+	// occurred when parsing element index of a
+	// slice in ParseSlice.
+	elem  bool
+	index int
+}
+
+func (e parseError) Error() string {
+	if e.elem {
+		return fmt.Sprintf("cmplxs: failed to parse element %d: %q", e.index, e.string)
+	}
+	if e.state < 0 {
+		return fmt.Sprintf("cmplxs: failed to parse: %q", e.string)
+	}
+	return fmt.Sprintf("cmplxs: failed to parse in state %d with %q: %q", e.state, e.rune, e.string)
+}
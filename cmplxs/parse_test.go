@@ -0,0 +1,202 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cmplxs
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+var parseTests = []struct {
+	s       string
+	want    complex128
+	wantErr error
+}{
+	// Simple error states:
+	{s: "", wantErr: parseError{state: -1}},
+	{s: "()", wantErr: parseError{string: "()", state: -1}},
+	{s: "(1", wantErr: parseError{string: "(1", state: -1}},
+	{s: "1)", wantErr: parseError{string: "1)", state: -1}},
+
+	// Ambiguous parse error states:
+	{s: "1+2i+3i", wantErr: parseError{string: "1+2i+3i", state: -1}},
+	{s: "1+2i3", wantErr: parseError{string: "1+2i3", state: -1}},
+	{s: "1+2j", wantErr: parseError{string: "1+2j", state: -1}},
+	{s: "1e-4i-4+", wantErr: parseError{string: "1e-4i-4+", state: -1}},
+	{s: "1e-4i-4-", wantErr: parseError{string: "1e-4i-4-", state: -1}},
+
+	// Valid input:
+	{s: "1", want: 1},
+	{s: "-4i", want: -4i},
+	{s: "1+4i", want: 1 + 4i},
+	{s: "1-4i", want: 1 - 4i},
+	{s: "4i+1", want: 1 + 4i},
+	{s: "+1+4i", want: 1 + 4i},
+	{s: "+4i+1", want: 1 + 4i},
+	{s: "1e-4+i", want: 1e-4 + 1i},
+	{s: "1e-4-i", want: 1e-4 - 1i},
+	{s: "1e-4i-1", want: -1 + 1e-4i},
+	{s: "(1+4i)", want: 1 + 4i},
+	{s: "(4i+1)", want: 1 + 4i},
+	{s: "(+1+4i)", want: 1 + 4i},
+	{s: "(+4i+1)", want: 1 + 4i},
+	{s: "(1e-4-i)", want: 1e-4 - 1i},
+	{s: "(1e-4i+1)", want: 1 + 1e-4i},
+	{s: "NaN", want: cmplx.NaN()},
+	{s: "nan", want: cmplx.NaN()},
+	{s: "Inf", want: cmplx.Inf()},
+	{s: "inf", want: cmplx.Inf()},
+	{s: "(Inf+Infi)", want: complex(math.Inf(1), math.Inf(1))},
+	{s: "(-Inf+Infi)", want: complex(math.Inf(-1), math.Inf(1))},
+	{s: "(+Inf-Infi)", want: complex(math.Inf(1), math.Inf(-1))},
+	{s: "(inf+infi)", want: complex(math.Inf(1), math.Inf(1))},
+	{s: "(-inf+infi)", want: complex(math.Inf(-1), math.Inf(1))},
+	{s: "(+inf-infi)", want: complex(math.Inf(1), math.Inf(-1))},
+	{s: "(nan+nani)", want: complex(math.NaN(), math.NaN())},
+	{s: "(nan-nani)", want: complex(math.NaN(), math.NaN())},
+	{s: "(1+nani)", want: complex(1, math.NaN())},
+	{s: "(nan+1i)", want: complex(math.NaN(), 1)},
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	for _, test := range parseTests {
+		got, err := Parse(test.s)
+		if err != test.wantErr {
+			t.Errorf("unexpected error for Parse(%q): got:%#v, want:%#v", test.s, err, test.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if !same(got, test.want) {
+			t.Errorf("unexpected result for Parse(%q): got:%v, want:%v", test.s, got, test.want)
+		}
+	}
+}
+
+func TestParseSlice(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		s       string
+		want    []complex128
+		wantErr error
+	}{
+		{s: "", want: []complex128{}},
+		{s: "  ", want: []complex128{}},
+		{s: "[]", want: []complex128{}},
+		{s: "[ ]", want: []complex128{}},
+		{s: "1", want: []complex128{1}},
+		{s: "1,", want: []complex128{1}},
+		{s: "1 2i", want: []complex128{1, 2i}},
+		{s: "1, 2i, ", want: []complex128{1, 2i}},
+		{s: "1e-4-i,1e-4i-1", want: []complex128{1e-4 - 1i, -1 + 1e-4i}},
+		{s: "[(1+4i), (4i+1)\t(-1-2i)\n]", want: []complex128{1 + 4i, 1 + 4i, -1 - 2i}},
+		{s: "[1+4i,-2,NaN,]", want: []complex128{1 + 4i, -2, cmplx.NaN()}},
+
+		{s: "[1", wantErr: parseError{string: "[1", state: -1}},
+		{s: "1]", wantErr: parseError{string: "1]", state: -1, elem: true, index: 0}},
+		{s: "1, 2+3j, 4", wantErr: parseError{string: "2+3j", state: -1, elem: true, index: 1}},
+		{s: "(1+4i) (2+i", wantErr: parseError{string: "(2+i", state: -1, elem: true, index: 1}},
+		{s: ",1", wantErr: parseError{string: "", state: -1, elem: true, index: 0}},
+		{s: "1,,2", wantErr: parseError{string: "", state: -1, elem: true, index: 1}},
+		{s: "1, ,2", wantErr: parseError{string: " ", state: -1, elem: true, index: 1}},
+	} {
+		got, err := ParseSlice(test.s)
+		if err != test.wantErr {
+			t.Errorf("unexpected error for ParseSlice(%q): got:%#v, want:%#v", test.s, err, test.wantErr)
+		}
+		if err != nil {
+			if got != nil {
+				t.Errorf("unexpected non-nil result for ParseSlice(%q) with error", test.s)
+			}
+			continue
+		}
+		if got == nil {
+			t.Errorf("unexpected nil result for ParseSlice(%q)", test.s)
+			continue
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("unexpected length of result for ParseSlice(%q): got:%d, want:%d", test.s, len(got), len(test.want))
+			continue
+		}
+		for i, v := range got {
+			if !same(v, test.want[i]) {
+				t.Errorf("unexpected result for ParseSlice(%q): got:%v, want:%v", test.s, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		c    complex128
+		want string
+	}{
+		{c: 1 + 4i, want: "(1+4i)"},
+		{c: 1 - 4i, want: "(1-4i)"},
+		{c: 1, want: "(1+0i)"},
+		{c: -4i, want: "(0-4i)"},
+		{c: complex(math.Copysign(0, -1), 0), want: "(-0+0i)"},
+		{c: 1e-300 + 1.5e300i, want: "(1e-300+1.5e+300i)"},
+		{c: complex(math.Inf(1), math.Inf(-1)), want: "(+Inf-Infi)"},
+		{c: complex(math.NaN(), math.NaN()), want: "(NaN+NaNi)"},
+	} {
+		got := Format(test.c)
+		if got != test.want {
+			t.Errorf("unexpected result for Format(%v): got:%q, want:%q", test.c, got, test.want)
+		}
+	}
+}
+
+func TestFormatParseRoundTrip(t *testing.T) {
+	t.Parallel()
+	values := []complex128{
+		0.1 + 0.2i,
+		math.MaxFloat64 - math.SmallestNonzeroFloat64*1i,
+		complex(math.Copysign(0, -1), math.Copysign(0, -1)),
+		complex(math.Inf(-1), math.NaN()),
+	}
+	for _, test := range parseTests {
+		if test.wantErr == nil {
+			values = append(values, test.want)
+		}
+	}
+	for _, c := range values {
+		s := Format(c)
+		got, err := Parse(s)
+		if err != nil {
+			t.Errorf("unexpected error for Parse(Format(%v)) with %q: %v", c, s, err)
+			continue
+		}
+		if !identical(got, c) {
+			t.Errorf("round trip mismatch for %v: got:%v via %q", c, got, s)
+		}
+	}
+}
+
+// same returns whether a and b are equal, treating NaN parts as equal.
+func same(a, b complex128) bool {
+	return sameFloat(real(a), real(b)) && sameFloat(imag(a), imag(b))
+}
+
+func sameFloat(a, b float64) bool {
+	return a == b || (math.IsNaN(a) && math.IsNaN(b))
+}
+
+// identical returns whether a and b are equal including the signs of zero
+// parts, treating NaN parts as equal.
+func identical(a, b complex128) bool {
+	return identicalFloat(real(a), real(b)) && identicalFloat(imag(a), imag(b))
+}
+
+func identicalFloat(a, b float64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.IsNaN(a) && math.IsNaN(b)
+	}
+	return math.Float64bits(a) == math.Float64bits(b)
+}
@@ -0,0 +1,114 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gonum
+
+import "gonum.org/v1/gonum/blas/blas64"
+
+// Dgbtrf computes an LU factorization of an m×n band matrix A with kl
+// sub-diagonals and ku super-diagonals using partial pivoting with row
+// interchanges. The factorization has the form
+//  A = P * L * U
+// where P is a permutation matrix, L is lower triangular with unit diagonal
+// elements and at most kl non-zero elements below the diagonal in each
+// column, and U is upper triangular with at most kl+ku super-diagonals.
+//
+// On entry, ab contains the matrix A in row-major band storage with the
+// additional kl columns for the fill-in of U, so that ldab must be at least
+// 2*kl+ku+1. The element A[i,j] is stored in ab[i*ldab+kl+j-i], and the
+// elements in the last kl columns of ab need not be set on entry. The band
+// storage scheme is illustrated below when m = n = 6, kl = 2 and ku = 1.
+//   *   *  a11 a12  +   +
+//   *  a21 a22 a23  +   +
+//  a31 a32 a33 a34  +   +
+//  a42 a43 a44 a45  +   +
+//  a53 a54 a55 a56  +   *
+//  a64 a65 a66  *   *   *
+// The elements marked + are the fill-in of U.
+//
+// On return, the elements of U are stored in the upper band of ab and the
+// multipliers of L are stored in the lower band. Row i was interchanged
+// with row ipiv[i] during the factorization, and the interchanges are not
+// applied to the multipliers of the previous columns. ipiv must have length
+// min(m,n), and it is zero-indexed.
+//
+// Dgbtrf returns whether the matrix A is nonsingular. The LU factorization
+// is computed regardless of the singularity of A, but the factor U is
+// singular and must not be used to solve a system of equations.
+func (Implementation) Dgbtrf(m, n, kl, ku int, ab []float64, ldab int, ipiv []int) (ok bool) {
+	switch {
+	case m < 0:
+		panic(mLT0)
+	case n < 0:
+		panic(nLT0)
+	case kl < 0:
+		panic(klLT0)
+	case ku < 0:
+		panic(kuLT0)
+	case ldab < 2*kl+ku+1:
+		panic(badLdA)
+	}
+
+	// Quick return if possible.
+	mn := min(m, n)
+	if mn == 0 {
+		return true
+	}
+
+	switch {
+	case len(ab) < (m-1)*ldab+2*kl+ku+1:
+		panic(shortAB)
+	case len(ipiv) != mn:
+		panic(badLenIpiv)
+	}
+
+	// Zero the fill-in elements.
+	for i := 0; i < m; i++ {
+		for k := kl + ku + 1; k <= 2*kl+ku; k++ {
+			ab[i*ldab+k] = 0
+		}
+	}
+
+	bi := blas64.Implementation()
+	// The elements of a column below the diagonal and the elements of the
+	// submatrix to the lower right of the diagonal are stored with the
+	// stride ldab-1.
+	ld := ldab - 1
+	ok = true
+	// ju is the last column of U affected by the interchanges.
+	var ju int
+	for j := 0; j < mn; j++ {
+		// diag is the position of A[j,j].
+		diag := j*ldab + kl
+		km := min(kl, m-1-j)
+
+		// Find the pivot.
+		jp := 0
+		if km > 0 {
+			jp = bi.Idamax(km+1, ab[diag:], ld)
+		}
+		ipiv[j] = j + jp
+		if ab[diag+jp*ld] == 0 {
+			// The matrix is singular, and the column is already
+			// zero below the diagonal.
+			ok = false
+			continue
+		}
+		ju = max(ju, min(j+ku+jp, n-1))
+
+		// Apply the interchange to columns j to ju.
+		if jp != 0 {
+			bi.Dswap(ju-j+1, ab[diag+jp*ld:], 1, ab[diag:], 1)
+		}
+		if km > 0 {
+			// Compute the multipliers and update the trailing
+			// submatrix.
+			bi.Dscal(km, 1/ab[diag], ab[diag+ld:], ld)
+			if ju > j {
+				bi.Dger(km, ju-j, -1, ab[diag+ld:], ld, ab[diag+1:], 1, ab[diag+ldab:], ld)
+			}
+		}
+	}
+	return ok
+}
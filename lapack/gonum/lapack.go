@@ -11,7 +11,12 @@ import "gonum.org/v1/gonum/lapack"
 // this code is in pure Go, the underlying BLAS implementation may not be.
 type Implementation struct{}
 
-var _ lapack.Float64 = Implementation{}
+var (
+	_ lapack.Float64          = Implementation{}
+	_ lapack.Float64Band      = Implementation{}
+	_ lapack.Float64Schur     = Implementation{}
+	_ lapack.Float64PivotedQR = Implementation{}
+)
 
 func min(a, b int) int {
 	if a < b {
@@ -27,6 +27,16 @@ func TestDhseqr(t *testing.T) {
 	testlapack.DhseqrTest(t, impl)
 }
 
+func TestDgbtrf(t *testing.T) {
+	t.Parallel()
+	testlapack.DgbtrfTest(t, impl)
+}
+
+func TestDgbtrs(t *testing.T) {
+	t.Parallel()
+	testlapack.DgbtrsTest(t, impl)
+}
+
 func TestDgebak(t *testing.T) {
 	t.Parallel()
 	testlapack.DgebakTest(t, impl)
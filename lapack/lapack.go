@@ -37,6 +37,33 @@ type Float64 interface {
 	Dtrtrs(uplo blas.Uplo, trans blas.Transpose, diag blas.Diag, n, nrhs int, a []float64, lda int, b []float64, ldb int) (ok bool)
 }
 
+// Float64Band defines the float64 LAPACK routines for band matrices. It is an
+// optional extension of Float64, so that implementations of Float64 need not
+// provide it. The lapack64 package uses the routines of the registered
+// implementation if it implements Float64Band, and the native implementation
+// otherwise.
+type Float64Band interface {
+	Dgbtrf(m, n, kl, ku int, ab []float64, ldab int, ipiv []int) (ok bool)
+	Dgbtrs(trans blas.Transpose, n, kl, ku, nrhs int, ab []float64, ldab int, ipiv []int, b []float64, ldb int)
+}
+
+// Float64Schur defines the float64 LAPACK routines for the computation and
+// reordering of the real Schur factorization. Like Float64Band, it is an
+// optional extension of Float64.
+type Float64Schur interface {
+	Dgehrd(n, ilo, ihi int, a []float64, lda int, tau, work []float64, lwork int)
+	Dhseqr(job SchurJob, compz SchurComp, n, ilo, ihi int, h []float64, ldh int, wr, wi []float64, z []float64, ldz int, work []float64, lwork int) (unconverged int)
+	Dorghr(n, ilo, ihi int, a []float64, lda int, tau, work []float64, lwork int)
+	Dtrexc(compq UpdateSchurComp, n int, t []float64, ldt int, q []float64, ldq int, ifst, ilst int, work []float64) (ifstOut, ilstOut int, ok bool)
+}
+
+// Float64PivotedQR defines the float64 LAPACK routine for the QR factorization
+// with column pivoting. Like Float64Band, it is an optional extension of
+// Float64.
+type Float64PivotedQR interface {
+	Dgeqp3(m, n int, a []float64, lda int, jpvt []int, tau, work []float64, lwork int)
+}
+
 // Direct specifies the direction of the multiplication for the Householder matrix.
 type Direct byte
 
@@ -14,11 +14,43 @@ import (
 var lapack64 lapack.Float64 = gonum.Implementation{}
 
 // Use sets the LAPACK float64 implementation to be used by subsequent BLAS calls.
-// The default implementation is native.Implementation.
+// The default implementation is native.Implementation. Routines of optional
+// extensions of lapack.Float64, such as lapack.Float64Band, that l does not
+// implement are computed by the native implementation.
 func Use(l lapack.Float64) {
 	lapack64 = l
 }
 
+// band returns the implementation of the band matrix routines, which is the
+// registered implementation if it provides them and the native implementation
+// otherwise.
+func band() lapack.Float64Band {
+	if l, ok := lapack64.(lapack.Float64Band); ok {
+		return l
+	}
+	return gonum.Implementation{}
+}
+
+// schur returns the implementation of the Schur factorization routines, which
+// is the registered implementation if it provides them and the native
+// implementation otherwise.
+func schur() lapack.Float64Schur {
+	if l, ok := lapack64.(lapack.Float64Schur); ok {
+		return l
+	}
+	return gonum.Implementation{}
+}
+
+// pivotedQR returns the implementation of the QR factorization with column
+// pivoting, which is the registered implementation if it provides it and the
+// native implementation otherwise.
+func pivotedQR() lapack.Float64PivotedQR {
+	if l, ok := lapack64.(lapack.Float64PivotedQR); ok {
+		return l
+	}
+	return gonum.Implementation{}
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
@@ -73,6 +105,38 @@ func Potrs(t blas64.Triangular, b blas64.General) {
 	lapack64.Dpotrs(t.Uplo, t.N, b.Cols, t.Data, max(1, t.Stride), b.Data, max(1, b.Stride))
 }
 
+// Gbtrf computes an LU factorization of the m×n band matrix A with KL
+// sub-diagonals and KU super-diagonals using partial pivoting with row
+// interchanges. The factorization has the form
+//  A = P * L * U
+// where P is a permutation matrix, L is lower triangular with unit diagonal
+// elements, and U is upper triangular with KL+KU super-diagonals.
+//
+// The stride of a must be at least 2*KL+KU+1 to hold the fill-in of U, and the
+// elements of the last KL columns of a need not be set on entry. On return, a
+// contains the factors L and U, and ipiv the row interchanges, as described in
+// the documentation of Dgbtrf in gonum.org/v1/gonum/lapack/gonum. ipiv must have
+// length min(m,n), and it is zero-indexed.
+//
+// Gbtrf returns whether the matrix A is nonsingular. The LU factorization is
+// computed regardless of the singularity of A, but the factor U is singular
+// and must not be used to solve a system of equations.
+func Gbtrf(a blas64.Band, ipiv []int) bool {
+	return band().Dgbtrf(a.Rows, a.Cols, a.KL, a.KU, a.Data, max(1, a.Stride), ipiv)
+}
+
+// Gbtrs solves a system of linear equations
+//  A * X = B   if trans == blas.NoTrans
+//  Aᵀ * X = B  if trans == blas.Trans or blas.ConjTrans
+// with an n×n band matrix A using the LU factorization and the row
+// interchanges computed by Gbtrf.
+//
+// On entry b contains the elements of the matrix B. On exit, b contains the
+// elements of X, the solution to the system of equations.
+func Gbtrs(trans blas.Transpose, a blas64.Band, b blas64.General, ipiv []int) {
+	band().Dgbtrs(trans, a.Cols, a.KL, a.KU, b.Cols, a.Data, max(1, a.Stride), ipiv, b.Data, max(1, b.Stride))
+}
+
 // Gecon estimates the reciprocal of the condition number of the n×n matrix A
 // given the LU decomposition of the matrix. The condition number computed may
 // be based on the 1-norm or the ∞-norm.
@@ -88,6 +152,25 @@ func Gecon(norm lapack.MatrixNorm, a blas64.General, anorm float64, work []float
 	return lapack64.Dgecon(norm, a.Cols, a.Data, max(1, a.Stride), anorm, work, iwork)
 }
 
+// Gehrd reduces a block of a real n×n general matrix A to upper Hessenberg
+// form H by an orthogonal similarity transformation Qᵀ * A * Q = H.
+//
+// The block is given by ilo and ihi, and A is assumed to be already upper
+// triangular in rows and columns [0:ilo] and [ihi+1:n]. They are typically set
+// by a previous call to Dgebal, otherwise they should be set to 0 and n-1,
+// respectively.
+//
+// On return, the upper triangle and the first subdiagonal of a contain H, and
+// the elements below the first subdiagonal, with tau, represent Q as a product
+// of elementary reflectors. tau must have length n-1.
+//
+// work must have length at least lwork and lwork must be at least max(1,n).
+// If lwork == -1, instead of performing Gehrd, only the optimal value of lwork
+// will be stored in work[0].
+func Gehrd(a blas64.General, ilo, ihi int, tau, work []float64, lwork int) {
+	schur().Dgehrd(a.Cols, ilo, ihi, a.Data, max(1, a.Stride), tau, work, lwork)
+}
+
 // Gels finds a minimum-norm solution based on the matrices A and B using the
 // QR or LQ factorization. Gels returns false if the matrix
 // A is singular, and true if this solution was successfully found.
@@ -121,6 +204,25 @@ func Gels(trans blas.Transpose, a blas64.General, b blas64.General, work []float
 	return lapack64.Dgels(trans, a.Rows, a.Cols, b.Cols, a.Data, max(1, a.Stride), b.Data, max(1, b.Stride), work, lwork)
 }
 
+// Geqp3 computes a QR factorization with column pivoting of the m×n matrix A
+//  A*P = Q*R
+// On return, the upper triangle of a contains the min(m,n)×n upper trapezoidal
+// matrix R, and the elements below the diagonal, with tau, represent the
+// orthogonal matrix Q as a product of min(m,n) elementary reflectors, in the
+// same form as returned by Geqrf.
+//
+// On entry, if jpvt[j] is at least zero, the jth column of A is permuted to
+// the front of A*P, and if jpvt[j] is -1, the jth column of A is a free column.
+// On return, the jth column of A*P was the jpvt[j] column of A. jpvt must have
+// length n and tau must have length min(m,n).
+//
+// work must have length at least max(1,lwork), and lwork must be at least
+// 3*n+1. If lwork == -1, instead of performing Geqp3, only the optimal value of
+// lwork will be stored in work[0].
+func Geqp3(a blas64.General, jpvt []int, tau, work []float64, lwork int) {
+	pivotedQR().Dgeqp3(a.Rows, a.Cols, a.Data, max(1, a.Stride), jpvt, tau, work, lwork)
+}
+
 // Geqrf computes the QR factorization of the m×n matrix A using a blocked
 // algorithm. A is modified to contain the information to construct Q and R.
 // The upper triangle of a contains the matrix R. The lower triangular elements
@@ -365,6 +467,33 @@ func Ggsvd3(jobU, jobV, jobQ lapack.GSVDJob, a, b blas64.General, alpha, beta []
 	return lapack64.Dggsvd3(jobU, jobV, jobQ, a.Rows, a.Cols, b.Rows, a.Data, max(1, a.Stride), b.Data, max(1, b.Stride), alpha, beta, u.Data, max(1, u.Stride), v.Data, max(1, v.Stride), q.Data, max(1, q.Stride), work, lwork, iwork)
 }
 
+// Hseqr computes the eigenvalues of an n×n Hessenberg matrix H and,
+// optionally, the matrices T and Z from the Schur decomposition
+//  H = Z T Zᵀ,
+// where T is an n×n upper quasi-triangular matrix (the Schur form), and Z is
+// the n×n orthogonal matrix of Schur vectors.
+//
+// If compz == lapack.SchurOrig, on entry z must contain the orthogonal matrix
+// Q that reduced a matrix A to the Hessenberg form H, and on return z is
+// updated to the product Q*Z, so that A = (QZ) T (QZ)ᵀ.
+// If compz == lapack.SchurHess, on return z contains the Schur vectors of H.
+// If compz == lapack.SchurNone, z is not referenced.
+//
+// ilo and ihi determine the block of H on which Hseqr operates, as described
+// in the documentation of Dhseqr in gonum.org/v1/gonum/lapack/gonum. They
+// should be set to 0 and n-1 if H was not balanced.
+//
+// wr and wi must have length n and on return contain the real and imaginary
+// parts of the eigenvalues. work must have length at least lwork and lwork
+// must be at least max(1,n). If lwork == -1, instead of performing Hseqr, only
+// the optimal value of lwork will be stored in work[0].
+//
+// Hseqr returns the number of unconverged eigenvalues. If it is zero, all
+// eigenvalues have been computed.
+func Hseqr(job lapack.SchurJob, compz lapack.SchurComp, h blas64.General, ilo, ihi int, wr, wi []float64, z blas64.General, work []float64, lwork int) (unconverged int) {
+	return schur().Dhseqr(job, compz, h.Cols, ilo, ihi, h.Data, max(1, h.Stride), wr, wi, z.Data, max(1, z.Stride), work, lwork)
+}
+
 // Lange computes the matrix norm of the general m×n matrix A. The input norm
 // specifies the norm computed.
 //  lapack.MaxAbs: the maximum absolute value of an element.
@@ -408,6 +537,20 @@ func Lapmt(forward bool, x blas64.General, k []int) {
 	lapack64.Dlapmt(forward, x.Rows, x.Cols, x.Data, max(1, x.Stride), k)
 }
 
+// Orghr generates an n×n orthogonal matrix Q which is defined as the product
+// of ihi-ilo elementary reflectors as returned by Gehrd. On entry, a must
+// contain the reflectors and tau their scalar factors, and on return a is
+// overwritten by Q.
+//
+// ilo and ihi must have the same values as in the previous call of Gehrd.
+//
+// work must have length at least max(1,lwork) and lwork must be at least
+// ihi-ilo. If lwork == -1, instead of performing Orghr, only the optimal value
+// of lwork will be stored into work[0].
+func Orghr(a blas64.General, ilo, ihi int, tau, work []float64, lwork int) {
+	schur().Dorghr(a.Cols, ilo, ihi, a.Data, max(1, a.Stride), tau, work, lwork)
+}
+
 // Ormlq multiplies the matrix C by the othogonal matrix Q defined by
 // A and tau. A and tau are as returned from Gelqf.
 //  C = Q * C   if side == blas.Left and trans == blas.NoTrans
@@ -503,6 +646,27 @@ func Trcon(norm lapack.MatrixNorm, a blas64.Triangular, work []float64, iwork []
 	return lapack64.Dtrcon(norm, a.Uplo, a.Diag, a.N, a.Data, max(1, a.Stride), work, iwork)
 }
 
+// Trexc reorders the real Schur factorization of a n×n real matrix
+//  A = Q*T*Qᵀ
+// so that the diagonal block of T with row index ifst is moved to row ilst.
+//
+// T must be in Schur canonical form, that is, block upper triangular with 1×1
+// and 2×2 diagonal blocks; each 2×2 diagonal block has its diagonal elements
+// equal and its off-diagonal elements of opposite sign.
+//
+// If compq is lapack.UpdateSchur, on return the matrix Q of Schur vectors will
+// be updated. If compq is lapack.UpdateSchurNone, q is not referenced.
+//
+// ifstOut and ilstOut are the first rows of the moved block in its initial and
+// final position, as described in the documentation of Dtrexc in
+// gonum.org/v1/gonum/lapack/gonum. If ok is false, two adjacent blocks were too
+// close to swap and T may have been partially reordered.
+//
+// work must have length at least n.
+func Trexc(compq lapack.UpdateSchurComp, t, q blas64.General, ifst, ilst int, work []float64) (ifstOut, ilstOut int, ok bool) {
+	return schur().Dtrexc(compq, t.Cols, t.Data, max(1, t.Stride), q.Data, max(1, q.Stride), ifst, ilst, work)
+}
+
 // Trtri computes the inverse of a triangular matrix, storing the result in place
 // into a.
 //
@@ -0,0 +1,85 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lapack64
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/lapack"
+	"gonum.org/v1/gonum/lapack/gonum"
+)
+
+// float64Only implements only the routines of lapack.Float64.
+type float64Only struct {
+	lapack.Float64
+}
+
+// TestUseFloat64Only checks that the routines of the optional extensions of
+// lapack.Float64 fall back to the native implementation. It is not run in
+// parallel since it changes the registered implementation.
+func TestUseFloat64Only(t *testing.T) {
+	if _, ok := lapack.Float64(float64Only{}).(lapack.Float64Band); ok {
+		t.Fatal("test implementation provides band routines")
+	}
+	if _, ok := lapack.Float64(float64Only{}).(lapack.Float64Schur); ok {
+		t.Fatal("test implementation provides Schur routines")
+	}
+	if _, ok := lapack.Float64(float64Only{}).(lapack.Float64PivotedQR); ok {
+		t.Fatal("test implementation provides pivoted QR routines")
+	}
+	Use(float64Only{gonum.Implementation{}})
+	defer Use(gonum.Implementation{})
+
+	// Solve the tridiagonal system
+	//  [ 2 1 0 ] [x0]   [3]
+	//  [ 1 2 1 ] [x1] = [4]
+	//  [ 0 1 2 ] [x2]   [3]
+	// whose solution is all ones. The last column of the band storage holds
+	// the fill-in of the factorization.
+	a := blas64.Band{
+		Rows: 3, Cols: 3, KL: 1, KU: 1, Stride: 4,
+		Data: []float64{
+			0, 2, 1, 0,
+			1, 2, 1, 0,
+			1, 2, 0, 0,
+		},
+	}
+	ipiv := make([]int, 3)
+	if !Gbtrf(a, ipiv) {
+		t.Fatal("unexpected singular matrix")
+	}
+	b := blas64.General{Rows: 3, Cols: 1, Stride: 1, Data: []float64{3, 4, 3}}
+	Gbtrs(blas.NoTrans, a, b, ipiv)
+	if !floats.EqualApprox(b.Data, []float64{1, 1, 1}, 1e-14) {
+		t.Errorf("unexpected solution: got %v", b.Data)
+	}
+
+	// Swap the diagonal blocks of an upper triangular matrix, which is in
+	// Schur form.
+	tm := blas64.General{Rows: 2, Cols: 2, Stride: 2, Data: []float64{1, 3, 0, 2}}
+	q := blas64.General{Rows: 2, Cols: 2, Stride: 2, Data: []float64{1, 0, 0, 1}}
+	_, _, ok := Trexc(lapack.UpdateSchur, tm, q, 0, 1, make([]float64, 2))
+	if !ok {
+		t.Fatal("unexpected failure to reorder Schur form")
+	}
+	if !floats.EqualApprox([]float64{tm.Data[0], tm.Data[2], tm.Data[3]}, []float64{2, 0, 1}, 1e-14) {
+		t.Errorf("unexpected reordered Schur form: got %v", tm.Data)
+	}
+
+	// The column of largest norm is moved first by the pivoted QR
+	// factorization.
+	g := blas64.General{Rows: 2, Cols: 2, Stride: 2, Data: []float64{1, 0, 0, 2}}
+	jpvt := []int{-1, -1}
+	work := make([]float64, 1)
+	Geqp3(g, jpvt, make([]float64, 2), work, -1)
+	work = make([]float64, int(work[0]))
+	Geqp3(g, jpvt, make([]float64, 2), work, len(work))
+	if jpvt[0] != 1 || jpvt[1] != 0 {
+		t.Errorf("unexpected column pivots: got %v", jpvt)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package testlapack
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+type Dgbtrfer interface {
+	Dgbtrf(m, n, kl, ku int, ab []float64, ldab int, ipiv []int) (ok bool)
+}
+
+// DgbtrfTest tests Dgbtrf by checking that the product of the computed factors
+// P, L and U is equal to the original band matrix A.
+func DgbtrfTest(t *testing.T, impl Dgbtrfer) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, m := range []int{0, 1, 2, 3, 4, 5, 10, 33, 65} {
+		for _, n := range []int{0, 1, 2, 3, 4, 5, 10, 33, 65} {
+			for _, kl := range []int{0, 1, 2, 5} {
+				for _, ku := range []int{0, 1, 2, 5} {
+					for _, extra := range []int{0, 3} {
+						dgbtrfTest(t, impl, rnd, m, n, kl, ku, 2*kl+ku+1+extra)
+					}
+				}
+			}
+		}
+	}
+}
+
+func dgbtrfTest(t *testing.T, impl Dgbtrfer, rnd *rand.Rand, m, n, kl, ku, ldab int) {
+	const tol = 1e-13
+
+	name := fmt.Sprintf("m=%v,n=%v,kl=%v,ku=%v,ldab=%v", m, n, kl, ku, ldab)
+
+	a, ab := randGeneralBand(m, n, kl, ku, ldab, rnd)
+	mn := min(m, n)
+	ipiv := make([]int, mn)
+	ok := impl.Dgbtrf(m, n, kl, ku, ab, ldab, ipiv)
+	if !ok {
+		t.Fatalf("%v: unexpected singular matrix", name)
+	}
+	if mn == 0 {
+		return
+	}
+
+	// Reconstruct A = P_0*L_0*P_1*L_1*...*U from the factors.
+	lda := max(1, n)
+	got := make([]float64, m*lda)
+	for i := 0; i < mn; i++ {
+		for j := i; j < min(n, i+kl+ku+1); j++ {
+			got[i*lda+j] = ab[i*ldab+kl+j-i]
+		}
+	}
+	for j := mn - 1; j >= 0; j-- {
+		for i := j + 1; i < min(m, j+kl+1); i++ {
+			l := ab[i*ldab+kl+j-i]
+			for k := 0; k < n; k++ {
+				got[i*lda+k] += l * got[j*lda+k]
+			}
+		}
+		if p := ipiv[j]; p != j {
+			if p < j || p > j+kl {
+				t.Errorf("%v: pivot %v out of range at column %v", name, p, j)
+				return
+			}
+			for k := 0; k < n; k++ {
+				got[j*lda+k], got[p*lda+k] = got[p*lda+k], got[j*lda+k]
+			}
+		}
+	}
+	var diff float64
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			diff = math.Max(diff, math.Abs(got[i*lda+j]-a[i*lda+j]))
+		}
+	}
+	if diff > tol {
+		t.Errorf("%v: unexpected result, diff=%v", name, diff)
+	}
+}
+
+// randGeneralBand returns a random m×n band matrix with kl sub-diagonals and
+// ku super-diagonals as a dense matrix with stride max(1,n), and in the band
+// storage of Dgbtrf with the leading dimension ldab. The elements of the band
+// storage outside of the band are NaN.
+func randGeneralBand(m, n, kl, ku, ldab int, rnd *rand.Rand) (a, ab []float64) {
+	lda := max(1, n)
+	a = make([]float64, m*lda)
+	ab = nanSlice(m * ldab)
+	for i := 0; i < m; i++ {
+		for j := max(0, i-kl); j < min(n, i+ku+1); j++ {
+			v := rnd.NormFloat64()
+			a[i*lda+j] = v
+			ab[i*ldab+kl+j-i] = v
+		}
+	}
+	return a, ab
+}
@@ -0,0 +1,105 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package testlapack
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/floats"
+)
+
+type Dgbtrser interface {
+	Dgbtrs(trans blas.Transpose, n, kl, ku, nrhs int, ab []float64, ldab int, ipiv []int, b []float64, ldb int)
+
+	Dgbtrfer
+}
+
+// DgbtrsTest tests Dgbtrs by checking the residual of the computed
+// solution of a linear system with a random band matrix.
+func DgbtrsTest(t *testing.T, impl Dgbtrser) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 2, 3, 4, 5, 10, 65} {
+		for _, kl := range []int{0, 1, 2, 5} {
+			for _, ku := range []int{0, 1, 2, 5} {
+				for _, nrhs := range []int{0, 1, 2, 5} {
+					for _, trans := range []blas.Transpose{blas.NoTrans, blas.Trans} {
+						for _, ldb := range []int{max(1, nrhs), nrhs + 3} {
+							dgbtrsTest(t, impl, rnd, trans, n, kl, ku, nrhs, 2*kl+ku+1, ldb)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func dgbtrsTest(t *testing.T, impl Dgbtrser, rnd *rand.Rand, trans blas.Transpose, n, kl, ku, nrhs, ldab, ldb int) {
+	const tol = 1e-14
+
+	name := fmt.Sprintf("trans=%v,n=%v,kl=%v,ku=%v,nrhs=%v,ldb=%v", string(trans), n, kl, ku, nrhs, ldb)
+
+	// Generate a random band matrix, which needs row interchanges in
+	// general. The diagonal is increased to avoid the exponentially large
+	// condition numbers of random triangular matrices.
+	a, ab := randGeneralBand(n, n, kl, ku, ldab, rnd)
+	lda := max(1, n)
+	for i := 0; i < n; i++ {
+		a[i*lda+i] += math.Copysign(2, a[i*lda+i])
+		ab[i*ldab+kl] = a[i*lda+i]
+	}
+
+	abFac := make([]float64, len(ab))
+	copy(abFac, ab)
+	ipiv := make([]int, n)
+	ok := impl.Dgbtrf(n, n, kl, ku, abFac, ldab, ipiv)
+	if !ok {
+		t.Fatalf("%v: bad test matrix, Dgbtrf failed", name)
+	}
+	abFacCopy := make([]float64, len(abFac))
+	copy(abFacCopy, abFac)
+
+	// Generate a random right-hand side.
+	bCopy := make([]float64, n*ldb)
+	for i := range bCopy {
+		bCopy[i] = rnd.NormFloat64()
+	}
+	b := make([]float64, len(bCopy))
+	copy(b, bCopy)
+
+	impl.Dgbtrs(trans, n, kl, ku, nrhs, abFac, ldab, ipiv, b, ldb)
+	x := b
+
+	if !floats.Same(abFac, abFacCopy) {
+		t.Errorf("%v: unexpected modification of ab", name)
+	}
+	if n == 0 || nrhs == 0 {
+		return
+	}
+
+	// Compute the residual op(A)*X - B and check its norm relative to the
+	// norms of A and X.
+	resid := make([]float64, len(bCopy))
+	copy(resid, bCopy)
+	blas64.Implementation().Dgemm(trans, blas.NoTrans, n, nrhs, n, 1, a, lda, x, ldb, -1, resid, ldb)
+	var rnorm, anorm, xnorm float64
+	for i := 0; i < n; i++ {
+		for j := 0; j < nrhs; j++ {
+			rnorm = math.Max(rnorm, math.Abs(resid[i*ldb+j]))
+			xnorm = math.Max(xnorm, math.Abs(x[i*ldb+j]))
+		}
+		for j := 0; j < n; j++ {
+			anorm = math.Max(anorm, math.Abs(a[i*lda+j]))
+		}
+	}
+	if rnorm > tol*float64(n)*anorm*xnorm {
+		t.Errorf("%v: unexpected result, residual=%v", name, rnorm)
+	}
+}
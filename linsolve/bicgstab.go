@@ -0,0 +1,86 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// BiCGSTAB implements the biconjugate gradient stabilized method for
+// systems with a general non-singular matrix A. BiCGSTAB needs two products
+// of A with a vector and two applications of the preconditioner per
+// iteration, and its memory does not grow with the number of iterations.
+// Its convergence is often faster than that of GMRES with a small restart,
+// but it is less smooth and BiCGSTAB may stop with ErrBreakdown.
+//
+// See
+//  Saad, Y.: Iterative Methods for Sparse Linear Systems. 2nd ed., SIAM
+//  (2003), Algorithm 7.7
+// for more information.
+type BiCGSTAB struct{}
+
+func (BiCGSTAB) solve(a MulVecToer, b, x, r *mat.VecDense, ctl *control) error {
+	n := b.Len()
+	rhat := mat.VecDenseCopyOf(r)
+	p := mat.NewVecDense(n, nil)
+	v := mat.NewVecDense(n, nil)
+	phat := mat.NewVecDense(n, nil)
+	s := mat.NewVecDense(n, nil)
+	shat := mat.NewVecDense(n, nil)
+	t := mat.NewVecDense(n, nil)
+
+	var rho, alpha, omega float64
+	for first := true; ; first = false {
+		rhoNew := mat.Dot(rhat, r)
+		if rhoNew == 0 {
+			return ErrBreakdown
+		}
+		if first {
+			p.CopyVec(r)
+		} else {
+			beta := (rhoNew / rho) * (alpha / omega)
+			p.AddScaledVec(p, -omega, v)
+			p.AddScaledVec(r, beta, p)
+		}
+		rho = rhoNew
+
+		if err := ctl.preconSolve(phat, false, p); err != nil {
+			return err
+		}
+		a.MulVecTo(v, false, phat)
+		rv := mat.Dot(rhat, v)
+		if rv == 0 {
+			return ErrBreakdown
+		}
+		alpha = rho / rv
+		s.AddScaledVec(r, -alpha, v)
+		if snorm := mat.Norm(s, 2); snorm <= ctl.tol {
+			// The half step has converged.
+			x.AddScaledVec(x, alpha, phat)
+			r.CopyVec(s)
+			ctl.done(snorm)
+			return nil
+		}
+
+		if err := ctl.preconSolve(shat, false, s); err != nil {
+			return err
+		}
+		a.MulVecTo(t, false, shat)
+		tt := mat.Dot(t, t)
+		if tt == 0 {
+			return ErrBreakdown
+		}
+		omega = mat.Dot(t, s) / tt
+		x.AddScaledVec(x, alpha, phat)
+		x.AddScaledVec(x, omega, shat)
+		r.AddScaledVec(s, -omega, t)
+		if ctl.done(mat.Norm(r, 2)) {
+			return nil
+		}
+		if omega == 0 {
+			return ErrBreakdown
+		}
+	}
+}
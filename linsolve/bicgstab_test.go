@@ -0,0 +1,49 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestBiCGSTAB(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, k := range []int{1, 3, 10, 20} {
+		for _, c := range []float64{0, 0.5, 2} {
+			a := convectionDiffusion2D(k, c)
+			n := k * k
+			b := randVec(n, rnd)
+			var want mat.VecDense
+			if err := want.SolveVec(a, b); err != nil {
+				t.Fatalf("unexpected error from dense solve: %v", err)
+			}
+			name := fmt.Sprintf("k=%d,c=%v", k, c)
+			res, err := Iterative(Operator(a), b, BiCGSTAB{}, &Settings{Tolerance: 1e-10})
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", name, err)
+			}
+			checkResult(t, name, a, b, res, 1e-10)
+			if !mat.EqualApprox(res.X, &want, 1e-7) {
+				t.Errorf("%s: unexpected solution", name)
+			}
+
+			jacobi := func(dst *mat.VecDense, _ bool, rhs mat.Vector) error {
+				dst.ScaleVec(0.25, rhs)
+				return nil
+			}
+			res, err = Iterative(Operator(a), b, BiCGSTAB{}, &Settings{Tolerance: 1e-10, Preconditioner: PreconFunc(jacobi)})
+			if err != nil {
+				t.Fatalf("%s: unexpected error with preconditioner: %v", name, err)
+			}
+			checkResult(t, name+",precon", a, b, res, 1e-10)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// CG implements the preconditioned conjugate gradient method for systems
+// with a symmetric positive definite matrix A. CG minimizes the A-norm of
+// the error over a Krylov subspace of increasing dimension at each
+// iteration, and it needs one product of A with a vector and one
+// application of the preconditioner per iteration. In exact arithmetic, CG
+// finds the solution in at most n iterations.
+//
+// If A is not positive definite, CG may stop with ErrBreakdown. See
+//  Saad, Y.: Iterative Methods for Sparse Linear Systems. 2nd ed., SIAM
+//  (2003), Algorithm 9.1
+// for more information.
+type CG struct{}
+
+func (CG) solve(a MulVecToer, b, x, r *mat.VecDense, ctl *control) error {
+	n := b.Len()
+	z := mat.NewVecDense(n, nil)
+	p := mat.NewVecDense(n, nil)
+	ap := mat.NewVecDense(n, nil)
+
+	if err := ctl.preconSolve(z, false, r); err != nil {
+		return err
+	}
+	p.CopyVec(z)
+	rz := mat.Dot(r, z)
+	for {
+		a.MulVecTo(ap, false, p)
+		pap := mat.Dot(p, ap)
+		if pap <= 0 || math.IsNaN(pap) {
+			return ErrBreakdown
+		}
+		alpha := rz / pap
+		x.AddScaledVec(x, alpha, p)
+		r.AddScaledVec(r, -alpha, ap)
+		if ctl.done(mat.Norm(r, 2)) {
+			return nil
+		}
+
+		if err := ctl.preconSolve(z, false, r); err != nil {
+			return err
+		}
+		rzNew := mat.Dot(r, z)
+		if rzNew == 0 {
+			return ErrBreakdown
+		}
+		beta := rzNew / rz
+		rz = rzNew
+		p.AddScaledVec(z, beta, p)
+	}
+}
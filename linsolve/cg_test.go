@@ -0,0 +1,71 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestCG(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, k := range []int{1, 2, 5, 20} {
+		a := laplacian2D(k)
+		n := k * k
+		b := randVec(n, rnd)
+		for _, tol := range []float64{1e-6, 1e-10} {
+			name := fmt.Sprintf("k=%d,tol=%v", k, tol)
+			res, err := Iterative(Operator(a), b, CG{}, &Settings{Tolerance: tol})
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", name, err)
+			}
+			checkResult(t, name, a, b, res, tol)
+
+			// The dense operator must give the same iterates.
+			dense := mat.DenseCopyOf(a)
+			resDense, err := Iterative(Operator(dense), b, CG{}, &Settings{Tolerance: tol})
+			if err != nil {
+				t.Fatalf("%s: unexpected error with dense matrix: %v", name, err)
+			}
+			if resDense.Iterations != res.Iterations || !mat.EqualApprox(resDense.X, res.X, 1e-12) {
+				t.Errorf("%s: dense and sparse operators disagree", name)
+			}
+
+			// The Jacobi preconditioner of the Laplacian is a scaling,
+			// which must not change the convergence much.
+			diag := func(dst *mat.VecDense, _ bool, rhs mat.Vector) error {
+				dst.ScaleVec(0.25, rhs)
+				return nil
+			}
+			resPre, err := Iterative(Operator(a), b, CG{}, &Settings{Tolerance: tol, Preconditioner: PreconFunc(diag)})
+			if err != nil {
+				t.Fatalf("%s: unexpected error with preconditioner: %v", name, err)
+			}
+			checkResult(t, name+",precon", a, b, resPre, tol)
+		}
+	}
+
+	// The preconditioner error is returned.
+	errPrecon := errors.New("precon failed")
+	_, err := Iterative(Operator(laplacian2D(3)), randVec(9, rnd), CG{}, &Settings{
+		Preconditioner: PreconFunc(func(*mat.VecDense, bool, mat.Vector) error { return errPrecon }),
+	})
+	if err != errPrecon {
+		t.Errorf("unexpected error: got %v, want %v", err, errPrecon)
+	}
+
+	// An indefinite matrix may cause a breakdown.
+	a := mat.NewDense(2, 2, []float64{1, 0, 0, -1})
+	_, err = Iterative(Operator(a), mat.NewVecDense(2, []float64{1, 1}), CG{}, nil)
+	if err != ErrBreakdown {
+		t.Errorf("unexpected error for indefinite matrix: got %v, want %v", err, ErrBreakdown)
+	}
+}
@@ -0,0 +1,12 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package linsolve implements iterative methods for solving systems of linear
+// equations,
+//  A x = b,
+// where A is a square matrix that is only accessed through its products with
+// vectors. Iterative methods are suited to large sparse systems, for which a
+// direct factorization is too expensive, and to operators that are not
+// stored as matrices at all.
+package linsolve // import "gonum.org/v1/gonum/linsolve"
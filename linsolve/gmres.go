@@ -0,0 +1,138 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// defaultRestart is the default maximum dimension of the Krylov subspace of
+// GMRES.
+const defaultRestart = 30
+
+// GMRES implements the restarted generalized minimal residual method,
+// GMRES(m), for systems with a general non-singular matrix A. GMRES
+// minimizes the residual norm over a Krylov subspace whose dimension
+// increases by one at each iteration. The subspace is restarted after
+// Restart iterations to bound the memory, which is proportional to
+// Restart times the dimension of the system, and the work per iteration.
+// GMRES needs one product of A with a vector and one application of the
+// preconditioner per iteration.
+//
+// See
+//  Saad, Y.: Iterative Methods for Sparse Linear Systems. 2nd ed., SIAM
+//  (2003), Algorithm 9.5
+// for more information.
+type GMRES struct {
+	// Restart is the number of iterations after which the Krylov
+	// subspace is restarted. If Restart is zero, the smaller of 30 and the
+	// dimension of the system is used. Restart must not be negative.
+	Restart int
+}
+
+func (g GMRES) solve(a MulVecToer, b, x, r *mat.VecDense, ctl *control) error {
+	n := b.Len()
+	m := g.Restart
+	if m < 0 {
+		panic("linsolve: negative GMRES restart")
+	}
+	if m == 0 {
+		m = defaultRestart
+	}
+	if m > n {
+		m = n
+	}
+
+	// v holds the orthonormal basis of the Krylov subspace.
+	v := make([]*mat.VecDense, m+1)
+	for i := range v {
+		v[i] = mat.NewVecDense(n, nil)
+	}
+	// h holds the upper Hessenberg matrix of the Arnoldi process, which
+	// is reduced to upper triangular form by the Givens rotations in c
+	// and s. The right-hand side of the least-squares problem is in gam.
+	h := mat.NewDense(m+1, m, nil)
+	c := make([]float64, m)
+	s := make([]float64, m)
+	gam := make([]float64, m+1)
+	y := make([]float64, m)
+	w := mat.NewVecDense(n, nil)
+	z := mat.NewVecDense(n, nil)
+
+	for {
+		beta := mat.Norm(r, 2)
+		v[0].ScaleVec(1/beta, r)
+		for i := range gam {
+			gam[i] = 0
+		}
+		gam[0] = beta
+
+		var k int
+		var stop bool
+		for k < m && !stop {
+			if err := ctl.preconSolve(z, false, v[k]); err != nil {
+				return err
+			}
+			a.MulVecTo(w, false, z)
+			// Orthogonalize with the modified Gram-Schmidt process.
+			for i := 0; i <= k; i++ {
+				hik := mat.Dot(w, v[i])
+				h.Set(i, k, hik)
+				w.AddScaledVec(w, -hik, v[i])
+			}
+			hk := mat.Norm(w, 2)
+			if hk != 0 {
+				v[k+1].ScaleVec(1/hk, w)
+			}
+
+			// Apply the previous rotations to the new column, and
+			// eliminate its subdiagonal element.
+			for i := 0; i < k; i++ {
+				hi, hi1 := h.At(i, k), h.At(i+1, k)
+				h.Set(i, k, c[i]*hi+s[i]*hi1)
+				h.Set(i+1, k, -s[i]*hi+c[i]*hi1)
+			}
+			hkk := h.At(k, k)
+			rho := math.Hypot(hkk, hk)
+			if rho == 0 {
+				return ErrBreakdown
+			}
+			c[k], s[k] = hkk/rho, hk/rho
+			h.Set(k, k, rho)
+			h.Set(k+1, k, 0)
+			gam[k+1] = -s[k] * gam[k]
+			gam[k] *= c[k]
+
+			// The residual norm of the least-squares problem is the
+			// residual norm of the system.
+			stop = ctl.done(math.Abs(gam[k+1]))
+			k++
+		}
+
+		// Solve the triangular system for the coefficients of the
+		// update in the Krylov basis.
+		for i := k - 1; i >= 0; i-- {
+			sum := gam[i]
+			for j := i + 1; j < k; j++ {
+				sum -= h.At(i, j) * y[j]
+			}
+			y[i] = sum / h.At(i, i)
+		}
+		w.Zero()
+		for i := 0; i < k; i++ {
+			w.AddScaledVec(w, y[i], v[i])
+		}
+		if err := ctl.preconSolve(z, false, w); err != nil {
+			return err
+		}
+		x.AddVec(x, z)
+		if stop {
+			return nil
+		}
+		residual(r, a, b, x)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestGMRES(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, k := range []int{1, 3, 10} {
+		for _, c := range []float64{0, 0.5, 2} {
+			a := convectionDiffusion2D(k, c)
+			n := k * k
+			b := randVec(n, rnd)
+			var want mat.VecDense
+			if err := want.SolveVec(a, b); err != nil {
+				t.Fatalf("unexpected error from dense solve: %v", err)
+			}
+			for _, restart := range []int{0, 1, 5, n} {
+				name := fmt.Sprintf("k=%d,c=%v,restart=%d", k, c, restart)
+				res, err := Iterative(Operator(a), b, GMRES{Restart: restart}, &Settings{
+					Tolerance:     1e-10,
+					MaxIterations: 100 * n,
+				})
+				if err != nil {
+					t.Fatalf("%s: unexpected error: %v", name, err)
+				}
+				checkResult(t, name, a, b, res, 1e-10)
+				if !mat.EqualApprox(res.X, &want, 1e-7) {
+					t.Errorf("%s: unexpected solution", name)
+				}
+				if restart == 0 || restart == n {
+					// Without restarts, GMRES minimizes the
+					// residual, so its norm does not increase.
+					for i := 1; i < len(res.ResidualHistory); i++ {
+						if res.ResidualHistory[i] > res.ResidualHistory[i-1]*(1+1e-12) {
+							t.Errorf("%s: residual norm increased at iteration %d", name, i)
+							break
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// The right preconditioner must not change the solution.
+	a := convectionDiffusion2D(8, 1)
+	b := randVec(64, rnd)
+	jacobi := func(dst *mat.VecDense, _ bool, rhs mat.Vector) error {
+		dst.ScaleVec(0.25, rhs)
+		return nil
+	}
+	res, err := Iterative(Operator(a), b, GMRES{Restart: 10}, &Settings{Tolerance: 1e-10, Preconditioner: PreconFunc(jacobi)})
+	if err != nil {
+		t.Fatalf("unexpected error with preconditioner: %v", err)
+	}
+	checkResult(t, "precon", a, b, res, 1e-10)
+}
@@ -0,0 +1,211 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"errors"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	// ErrIterationLimit signifies that the iterations have been stopped by
+	// the iteration limit before the residual norm reached the tolerance.
+	ErrIterationLimit = errors.New("linsolve: iteration limit reached")
+
+	// ErrBreakdown signifies that a Method cannot continue because of a
+	// division by zero, for example if the matrix of CG is not positive
+	// definite.
+	ErrBreakdown = errors.New("linsolve: method breakdown")
+
+	// ErrZeroPivot signifies that a preconditioner cannot be constructed
+	// because of a zero diagonal element or pivot.
+	ErrZeroPivot = errors.New("linsolve: zero pivot")
+)
+
+// defaultTolerance is the default relative tolerance of the residual norm.
+const defaultTolerance = 1e-8
+
+// MulVecToer is a linear operator A that can compute its product with a
+// vector.
+type MulVecToer interface {
+	// MulVecTo computes A*x if trans is false, or Aᵀ*x if trans is true,
+	// and stores the result in dst.
+	MulVecTo(dst *mat.VecDense, trans bool, x mat.Vector)
+}
+
+// Operator returns the MulVecToer of the matrix a, which computes the
+// products with VecDense.MulVec. The products with the sparse matrix types
+// of the mat package take time proportional to their number of non-zero
+// elements.
+func Operator(a mat.Matrix) MulVecToer {
+	return matrixOperator{a}
+}
+
+type matrixOperator struct {
+	a mat.Matrix
+}
+
+func (op matrixOperator) MulVecTo(dst *mat.VecDense, trans bool, x mat.Vector) {
+	if trans {
+		dst.MulVec(op.a.T(), x)
+		return
+	}
+	dst.MulVec(op.a, x)
+}
+
+// Settings holds the settings of Iterative.
+type Settings struct {
+	// InitX is the initial guess of the solution. If InitX is nil, the
+	// initial guess is the zero vector. InitX is not modified.
+	InitX mat.Vector
+
+	// Tolerance is the relative tolerance of the residual. The iterations
+	// are stopped successfully when the residual satisfies
+	//  |b - A x| <= Tolerance * |b|
+	// in the Euclidean norm. If Tolerance is 0, a default value of 1e-8 is
+	// used.
+	Tolerance float64
+
+	// MaxIterations is the maximum number of iterations. If MaxIterations
+	// is 0, it is set to twice the dimension of the system.
+	MaxIterations int
+
+	// Preconditioner, if not nil, is the preconditioner M, an
+	// approximation of A that is easier to solve. A good preconditioner
+	// reduces the number of iterations. For CG, M must be symmetric
+	// positive definite. GMRES and BiCGSTAB apply M from the right, so
+	// that the residual norm is the one of the original system. If the
+	// Preconditioner returns an error, the iterations are stopped and the
+	// error is returned by Iterative.
+	Preconditioner Preconditioner
+}
+
+// Result holds the result of Iterative.
+type Result struct {
+	// X is the approximate solution.
+	X *mat.VecDense
+
+	// Converged is whether the residual norm reached the tolerance.
+	Converged bool
+
+	// ResidualNorm is the Euclidean norm of the residual b - A*X as
+	// computed by the Method, which may differ from the true residual
+	// norm by rounding errors.
+	ResidualNorm float64
+
+	// Iterations is the number of iterations performed.
+	Iterations int
+
+	// ResidualHistory holds the residual norm of the initial guess followed
+	// by the residual norms after each iteration, for monitoring the
+	// convergence.
+	ResidualHistory []float64
+}
+
+// Method is an iterative method for linear systems. Method is implemented by
+// CG, GMRES and BiCGSTAB.
+type Method interface {
+	// solve improves the solution x of A x = b in place until ctl reports
+	// that the iterations are done. r holds the residual b - A x on entry.
+	solve(a MulVecToer, b, x, r *mat.VecDense, ctl *control) error
+}
+
+// Iterative solves the system of linear equations A x = b with the
+// iterative Method m. The dimension of the system is the length of b. If
+// settings is nil, the default settings are used.
+//
+// If the residual does not reach the tolerance within the iteration limit,
+// the Result with the final approximation is returned together with
+// ErrIterationLimit.
+func Iterative(a MulVecToer, b mat.Vector, m Method, settings *Settings) (*Result, error) {
+	if settings == nil {
+		settings = &Settings{}
+	}
+	n := b.Len()
+	if settings.Tolerance < 0 {
+		panic("linsolve: negative tolerance")
+	}
+	if settings.MaxIterations < 0 {
+		panic("linsolve: negative iteration limit")
+	}
+
+	bv := mat.VecDenseCopyOf(b)
+	x := mat.NewVecDense(n, nil)
+	if settings.InitX != nil {
+		if settings.InitX.Len() != n {
+			panic(mat.ErrShape)
+		}
+		x.CopyVec(settings.InitX)
+	}
+	r := mat.NewVecDense(n, nil)
+	residual(r, a, bv, x)
+
+	ctl := &control{
+		tol:     settings.Tolerance,
+		maxIter: settings.MaxIterations,
+		precon:  settings.Preconditioner,
+	}
+	if ctl.tol == 0 {
+		ctl.tol = defaultTolerance
+	}
+	if ctl.maxIter == 0 {
+		ctl.maxIter = 2 * n
+	}
+	ctl.tol *= mat.Norm(bv, 2)
+
+	var err error
+	if !ctl.done(mat.Norm(r, 2)) {
+		err = m.solve(a, bv, x, r, ctl)
+		if err == nil && !ctl.converged {
+			err = ErrIterationLimit
+		}
+	}
+	return &Result{
+		X:               x,
+		Converged:       ctl.converged,
+		ResidualNorm:    ctl.history[len(ctl.history)-1],
+		Iterations:      len(ctl.history) - 1,
+		ResidualHistory: ctl.history,
+	}, err
+}
+
+// residual stores b - A x in dst.
+func residual(dst *mat.VecDense, a MulVecToer, b, x *mat.VecDense) {
+	a.MulVecTo(dst, false, x)
+	dst.SubVec(b, dst)
+}
+
+// control records the residual norms of the iterations and decides when a
+// Method stops.
+type control struct {
+	tol     float64 // Absolute tolerance of the residual norm.
+	maxIter int
+	precon  Preconditioner
+
+	history   []float64
+	converged bool
+}
+
+// done records the residual norm after an iteration, or of the initial
+// guess at the first call, and returns whether the iterations should stop.
+func (c *control) done(rnorm float64) bool {
+	c.history = append(c.history, rnorm)
+	if rnorm <= c.tol {
+		c.converged = true
+		return true
+	}
+	return len(c.history)-1 >= c.maxIter
+}
+
+// preconSolve stores M⁻¹ rhs, or M⁻ᵀ rhs if trans is true, in dst. Without
+// a preconditioner, rhs is copied to dst.
+func (c *control) preconSolve(dst *mat.VecDense, trans bool, rhs *mat.VecDense) error {
+	if c.precon == nil {
+		dst.CopyVec(rhs)
+		return nil
+	}
+	return c.precon.PreconSolve(dst, trans, rhs)
+}
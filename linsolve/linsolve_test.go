@@ -0,0 +1,159 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// laplacian2D returns the matrix of the 5-point finite difference Laplacian
+// on a k×k grid, which is symmetric positive definite.
+func laplacian2D(k int) *mat.CSR {
+	n := k * k
+	m := mat.NewCOO(n, n)
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			v := i*k + j
+			m.Append(v, v, 4)
+			if i > 0 {
+				m.Append(v, v-k, -1)
+			}
+			if i < k-1 {
+				m.Append(v, v+k, -1)
+			}
+			if j > 0 {
+				m.Append(v, v-1, -1)
+			}
+			if j < k-1 {
+				m.Append(v, v+1, -1)
+			}
+		}
+	}
+	return m.ToCSR()
+}
+
+// convectionDiffusion2D returns the matrix of the 5-point finite difference
+// discretization of a convection-diffusion operator on a k×k grid with the
+// convection coefficient c, which is not symmetric if c is not zero.
+func convectionDiffusion2D(k int, c float64) *mat.CSR {
+	n := k * k
+	m := mat.NewCOO(n, n)
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			v := i*k + j
+			m.Append(v, v, 4)
+			if i > 0 {
+				m.Append(v, v-k, -1)
+			}
+			if i < k-1 {
+				m.Append(v, v+k, -1)
+			}
+			if j > 0 {
+				m.Append(v, v-1, -1-c)
+			}
+			if j < k-1 {
+				m.Append(v, v+1, -1+c)
+			}
+		}
+	}
+	return m.ToCSR()
+}
+
+// randVec returns a vector of length n with normally distributed elements.
+func randVec(n int, rnd *rand.Rand) *mat.VecDense {
+	v := make([]float64, n)
+	for i := range v {
+		v[i] = rnd.NormFloat64()
+	}
+	return mat.NewVecDense(n, v)
+}
+
+// checkResult checks that res satisfies the tolerance tol on the relative
+// residual of the system A x = b and that the history is consistent.
+func checkResult(t *testing.T, name string, a mat.Matrix, b mat.Vector, res *Result, tol float64) {
+	t.Helper()
+	if !res.Converged {
+		t.Errorf("%s: not converged", name)
+	}
+	var r mat.VecDense
+	r.MulVec(a, res.X)
+	r.SubVec(b, &r)
+	// The recursively updated residual may differ slightly from the true
+	// residual.
+	if rnorm := mat.Norm(&r, 2); rnorm > 10*tol*mat.Norm(b, 2) {
+		t.Errorf("%s: residual norm too large: got %v, want <= %v", name, rnorm, tol*mat.Norm(b, 2))
+	}
+	if len(res.ResidualHistory) != res.Iterations+1 {
+		t.Errorf("%s: unexpected history length: got %d, want %d", name, len(res.ResidualHistory), res.Iterations+1)
+	}
+	if res.ResidualNorm != res.ResidualHistory[len(res.ResidualHistory)-1] {
+		t.Errorf("%s: residual norm does not match history", name)
+	}
+}
+
+func TestIterativeSettings(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a := laplacian2D(10)
+	n, _ := a.Dims()
+	want := randVec(n, rnd)
+	var b mat.VecDense
+	b.MulVec(a, want)
+
+	// The exact solution as the initial guess needs no iterations.
+	res, err := Iterative(Operator(a), &b, CG{}, &Settings{InitX: want})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Iterations != 0 || !mat.Equal(res.X, want) {
+		t.Errorf("unexpected iterations from exact initial guess: %d", res.Iterations)
+	}
+
+	// A zero right-hand side has the zero solution.
+	res, err = Iterative(Operator(a), mat.NewVecDense(n, nil), CG{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Iterations != 0 || mat.Norm(res.X, 2) != 0 {
+		t.Errorf("unexpected solution for zero right-hand side")
+	}
+
+	// The iteration limit is reported.
+	res, err = Iterative(Operator(a), &b, CG{}, &Settings{MaxIterations: 3})
+	if err != ErrIterationLimit {
+		t.Errorf("unexpected error: got %v, want %v", err, ErrIterationLimit)
+	}
+	if res.Converged {
+		t.Errorf("unexpected convergence at iteration limit")
+	}
+	if res.Iterations != 3 || len(res.ResidualHistory) != 4 {
+		t.Errorf("unexpected number of iterations: got %d, want 3", res.Iterations)
+	}
+
+	// A looser tolerance needs fewer iterations.
+	loose, err := Iterative(Operator(a), &b, CG{}, &Settings{Tolerance: 1e-2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tight, err := Iterative(Operator(a), &b, CG{}, &Settings{Tolerance: 1e-12})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loose.Iterations >= tight.Iterations {
+		t.Errorf("looser tolerance did not reduce iterations: %d >= %d", loose.Iterations, tight.Iterations)
+	}
+	if !loose.Converged || !tight.Converged {
+		t.Errorf("unexpected convergence status")
+	}
+	checkResult(t, "tight", a, &b, tight, 1e-12)
+	if !floats.EqualApprox(tight.X.RawVector().Data, want.RawVector().Data, 1e-8) {
+		t.Errorf("unexpected solution with tight tolerance")
+	}
+}
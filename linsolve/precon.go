@@ -0,0 +1,299 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// Preconditioner is a preconditioner M of the iterative methods, an
+// approximation of the matrix A of the system whose inverse is cheap to
+// apply.
+type Preconditioner interface {
+	// PreconSolve solves M dst = rhs, or Mᵀ dst = rhs if trans is true.
+	// dst must be empty or have the length of rhs.
+	PreconSolve(dst *mat.VecDense, trans bool, rhs mat.Vector) error
+}
+
+// PreconFunc is an adapter to allow the use of ordinary functions as
+// Preconditioners.
+type PreconFunc func(dst *mat.VecDense, trans bool, rhs mat.Vector) error
+
+// PreconSolve calls f(dst, trans, rhs).
+func (f PreconFunc) PreconSolve(dst *mat.VecDense, trans bool, rhs mat.Vector) error {
+	return f(dst, trans, rhs)
+}
+
+// Jacobi is the Jacobi or diagonal preconditioner, M = diag(A). It is
+// effective for matrices whose diagonal elements vary widely in magnitude.
+type Jacobi struct {
+	inv *mat.VecDense
+}
+
+// NewJacobi returns the Jacobi preconditioner of the square matrix a. If a
+// diagonal element of a is zero, NewJacobi returns ErrZeroPivot.
+func NewJacobi(a mat.Matrix) (*Jacobi, error) {
+	r, c := a.Dims()
+	if r != c {
+		panic(mat.ErrSquare)
+	}
+	inv := mat.NewVecDense(r, nil)
+	for i := 0; i < r; i++ {
+		d := a.At(i, i)
+		if d == 0 {
+			return nil, ErrZeroPivot
+		}
+		inv.SetVec(i, 1/d)
+	}
+	return &Jacobi{inv: inv}, nil
+}
+
+// PreconSolve solves M dst = rhs. M is diagonal, so trans is ignored.
+func (p *Jacobi) PreconSolve(dst *mat.VecDense, trans bool, rhs mat.Vector) error {
+	if rhs.Len() != p.inv.Len() {
+		panic(mat.ErrShape)
+	}
+	dst.MulElemVec(p.inv, rhs)
+	return nil
+}
+
+// ILU0 is the incomplete LU factorization preconditioner without fill-in,
+// M = L*U, where the unit lower triangular L and the upper triangular U have
+// the sparsity pattern of the lower and upper triangles of A. The product
+// L*U equals A on the sparsity pattern of A.
+type ILU0 struct {
+	lu   splitCSR
+	diag []float64 // Diagonal of U.
+}
+
+// NewILU0 returns the ILU(0) preconditioner of the square matrix a. If a
+// diagonal element of a is not stored or a pivot is zero, NewILU0 returns
+// ErrZeroPivot.
+func NewILU0(a *mat.CSR) (*ILU0, error) {
+	lu := newSplitCSR(a)
+	n := lu.n
+	// pos holds the positions of the elements of the current row.
+	pos := make([]int, n)
+	for j := range pos {
+		pos[j] = -1
+	}
+	for i := 0; i < n; i++ {
+		if lu.diag[i] == -1 {
+			return nil, ErrZeroPivot
+		}
+		for p := lu.ptr[i]; p < lu.ptr[i+1]; p++ {
+			pos[lu.ind[p]] = p
+		}
+		for p := lu.ptr[i]; p < lu.diag[i]; p++ {
+			k := lu.ind[p]
+			lik := lu.data[p] / lu.data[lu.diag[k]]
+			lu.data[p] = lik
+			for q := lu.diag[k] + 1; q < lu.ptr[k+1]; q++ {
+				if w := pos[lu.ind[q]]; w != -1 {
+					lu.data[w] -= lik * lu.data[q]
+				}
+			}
+		}
+		if lu.data[lu.diag[i]] == 0 {
+			return nil, ErrZeroPivot
+		}
+		for p := lu.ptr[i]; p < lu.ptr[i+1]; p++ {
+			pos[lu.ind[p]] = -1
+		}
+	}
+	diag := make([]float64, n)
+	for i := range diag {
+		diag[i] = lu.data[lu.diag[i]]
+	}
+	return &ILU0{lu: lu, diag: diag}, nil
+}
+
+// PreconSolve solves M dst = rhs, or Mᵀ dst = rhs if trans is true.
+func (p *ILU0) PreconSolve(dst *mat.VecDense, trans bool, rhs mat.Vector) error {
+	p.lu.solve(dst, rhs, func(x []float64) {
+		if trans {
+			p.lu.solveUpperTrans(x, p.diag)
+			p.lu.solveLowerTrans(x, nil)
+			return
+		}
+		p.lu.solveLower(x, nil)
+		p.lu.solveUpper(x, p.diag)
+	})
+	return nil
+}
+
+// SSOR is the symmetric successive over-relaxation preconditioner,
+//
+//	M = ω/(2-ω) (D/ω + L) (D/ω)⁻¹ (D/ω + U),
+//
+// where D, L and U are the diagonal, and the strictly lower and upper
+// triangles of A, and 0 < ω < 2 is the relaxation parameter. If A is
+// symmetric positive definite, so is M.
+type SSOR struct {
+	a     splitCSR
+	diag  []float64 // Diagonal of A divided by ω.
+	omega float64
+}
+
+// NewSSOR returns the SSOR preconditioner of the square matrix a with the
+// relaxation parameter omega, which must be in the interval (0, 2). With
+// omega equal to 1, M is the symmetric Gauss-Seidel preconditioner. If a
+// diagonal element of a is zero, NewSSOR returns ErrZeroPivot.
+func NewSSOR(a *mat.CSR, omega float64) (*SSOR, error) {
+	if !(0 < omega && omega < 2) {
+		panic("linsolve: SSOR relaxation parameter out of range")
+	}
+	s := newSplitCSR(a)
+	diag := make([]float64, s.n)
+	for i := range diag {
+		if s.diag[i] == -1 || s.data[s.diag[i]] == 0 {
+			return nil, ErrZeroPivot
+		}
+		diag[i] = s.data[s.diag[i]] / omega
+	}
+	return &SSOR{a: s, diag: diag, omega: omega}, nil
+}
+
+// PreconSolve solves M dst = rhs, or Mᵀ dst = rhs if trans is true.
+func (p *SSOR) PreconSolve(dst *mat.VecDense, trans bool, rhs mat.Vector) error {
+	p.a.solve(dst, rhs, func(x []float64) {
+		if trans {
+			p.a.solveUpperTrans(x, p.diag)
+			scaleDiag(x, p.diag, (2-p.omega)/p.omega)
+			p.a.solveLowerTrans(x, p.diag)
+			return
+		}
+		p.a.solveLower(x, p.diag)
+		scaleDiag(x, p.diag, (2-p.omega)/p.omega)
+		p.a.solveUpper(x, p.diag)
+	})
+	return nil
+}
+
+// scaleDiag multiplies the elements of x by the elements of d and by alpha.
+func scaleDiag(x, d []float64, alpha float64) {
+	for i, v := range d {
+		x[i] *= alpha * v
+	}
+}
+
+// splitCSR is a copy of a square matrix in compressed sparse row format,
+// which is treated as split into its strictly lower triangle, its diagonal
+// and its strictly upper triangle.
+type splitCSR struct {
+	n        int
+	ptr, ind []int
+	data     []float64
+	diag     []int // Positions of the diagonal elements, or -1 if not stored.
+	work     []float64
+}
+
+func newSplitCSR(a *mat.CSR) splitCSR {
+	r, c := a.Dims()
+	if r != c {
+		panic(mat.ErrSquare)
+	}
+	ptr, ind, data := a.RawCSR()
+	s := splitCSR{
+		n:    r,
+		ptr:  ptr,
+		ind:  ind,
+		data: append([]float64(nil), data...),
+		diag: make([]int, r),
+		work: make([]float64, r),
+	}
+	for i := 0; i < r; i++ {
+		s.diag[i] = -1
+		for p := ptr[i]; p < ptr[i+1]; p++ {
+			if ind[p] == i {
+				s.diag[i] = p
+				break
+			}
+		}
+	}
+	return s
+}
+
+// solve copies rhs to dst and calls fn with the elements of dst, which fn
+// overwrites with the solution.
+func (s *splitCSR) solve(dst *mat.VecDense, rhs mat.Vector, fn func(x []float64)) {
+	if rhs.Len() != s.n {
+		panic(mat.ErrShape)
+	}
+	if dst.IsEmpty() {
+		dst.ReuseAsVec(s.n)
+	}
+	if dst.Len() != s.n {
+		panic(mat.ErrShape)
+	}
+	if dst != rhs {
+		dst.CopyVec(rhs)
+	}
+	raw := dst.RawVector()
+	if raw.Inc == 1 {
+		fn(raw.Data[:s.n])
+		return
+	}
+	w := mat.NewVecDense(s.n, s.work)
+	w.CopyVec(dst)
+	fn(s.work)
+	dst.CopyVec(w)
+}
+
+// solveLower solves (L + D) x = b in place, where D = diag(d), or D = I if d
+// is nil.
+func (s *splitCSR) solveLower(x, d []float64) {
+	for i := 0; i < s.n; i++ {
+		sum := x[i]
+		for p := s.ptr[i]; p < s.ptr[i+1] && s.ind[p] < i; p++ {
+			sum -= s.data[p] * x[s.ind[p]]
+		}
+		if d != nil {
+			sum /= d[i]
+		}
+		x[i] = sum
+	}
+}
+
+// solveUpper solves (D + U) x = b in place, where D = diag(d), or D = I if d
+// is nil.
+func (s *splitCSR) solveUpper(x, d []float64) {
+	for i := s.n - 1; i >= 0; i-- {
+		sum := x[i]
+		for p := s.ptr[i+1] - 1; p >= s.ptr[i] && s.ind[p] > i; p-- {
+			sum -= s.data[p] * x[s.ind[p]]
+		}
+		if d != nil {
+			sum /= d[i]
+		}
+		x[i] = sum
+	}
+}
+
+// solveLowerTrans solves (L + D)ᵀ x = b in place, where D = diag(d), or
+// D = I if d is nil.
+func (s *splitCSR) solveLowerTrans(x, d []float64) {
+	for i := s.n - 1; i >= 0; i-- {
+		if d != nil {
+			x[i] /= d[i]
+		}
+		for p := s.ptr[i]; p < s.ptr[i+1] && s.ind[p] < i; p++ {
+			x[s.ind[p]] -= s.data[p] * x[i]
+		}
+	}
+}
+
+// solveUpperTrans solves (D + U)ᵀ x = b in place, where D = diag(d), or
+// D = I if d is nil.
+func (s *splitCSR) solveUpperTrans(x, d []float64) {
+	for i := 0; i < s.n; i++ {
+		if d != nil {
+			x[i] /= d[i]
+		}
+		for p := s.ptr[i+1] - 1; p >= s.ptr[i] && s.ind[p] > i; p-- {
+			x[s.ind[p]] -= s.data[p] * x[i]
+		}
+	}
+}
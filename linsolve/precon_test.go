@@ -0,0 +1,237 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package linsolve
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// preconInverse returns the dense matrix of the action of p, M⁻¹ or M⁻ᵀ.
+func preconInverse(t *testing.T, p Preconditioner, n int, trans bool) *mat.Dense {
+	t.Helper()
+	inv := mat.NewDense(n, n, nil)
+	for j := 0; j < n; j++ {
+		e := mat.NewVecDense(n, nil)
+		e.SetVec(j, 1)
+		var x mat.VecDense
+		if err := p.PreconSolve(&x, trans, e); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		inv.ColView(j).(*mat.VecDense).CopyVec(&x)
+	}
+	return inv
+}
+
+// checkPreconInverse checks that p applies the inverse of m and its
+// transpose.
+func checkPreconInverse(t *testing.T, name string, p Preconditioner, m mat.Matrix) {
+	t.Helper()
+	n, _ := m.Dims()
+	for _, trans := range []bool{false, true} {
+		inv := preconInverse(t, p, n, trans)
+		var got mat.Dense
+		if trans {
+			got.Mul(m.T(), inv)
+		} else {
+			got.Mul(m, inv)
+		}
+		eye := mat.NewDiagDense(n, nil)
+		for i := 0; i < n; i++ {
+			eye.SetDiag(i, 1)
+		}
+		if !mat.EqualApprox(&got, eye, 1e-10) {
+			t.Errorf("%s, trans=%t: preconditioner does not apply the inverse", name, trans)
+		}
+	}
+}
+
+func TestJacobi(t *testing.T) {
+	t.Parallel()
+	a := convectionDiffusion2D(4, 0.5)
+	p, err := NewJacobi(a)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	n, _ := a.Dims()
+	d := mat.NewDiagDense(n, nil)
+	for i := 0; i < n; i++ {
+		d.SetDiag(i, a.At(i, i))
+	}
+	checkPreconInverse(t, "Jacobi", p, d)
+
+	_, err = NewJacobi(mat.NewDense(2, 2, []float64{1, 1, 1, 0}))
+	if err != ErrZeroPivot {
+		t.Errorf("unexpected error for zero diagonal: got %v, want %v", err, ErrZeroPivot)
+	}
+
+	// A badly scaled matrix needs many more iterations without the
+	// preconditioner.
+	rnd := rand.New(rand.NewSource(1))
+	k := 10
+	n = k * k
+	lap := laplacian2D(k)
+	s := mat.NewDiagDense(n, nil)
+	for i := 0; i < n; i++ {
+		s.SetDiag(i, float64(1+rnd.Intn(1000)))
+	}
+	m := mat.NewCOO(n, n)
+	lap.DoNonZero(func(i, j int, v float64) {
+		m.Append(i, j, s.At(i, i)*v*s.At(j, j))
+	})
+	scaled := m.ToCSR()
+	p, err = NewJacobi(scaled)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b := randVec(n, rnd)
+	plain, _ := Iterative(Operator(scaled), b, CG{}, &Settings{MaxIterations: 10 * n})
+	res, err := Iterative(Operator(scaled), b, CG{}, &Settings{Preconditioner: p})
+	if err != nil {
+		t.Fatalf("unexpected error with preconditioner: %v", err)
+	}
+	checkResult(t, "scaled", scaled, b, res, 1e-8)
+	if res.Iterations >= plain.Iterations {
+		t.Errorf("Jacobi preconditioner did not reduce iterations: %d >= %d", res.Iterations, plain.Iterations)
+	}
+}
+
+func TestILU0(t *testing.T) {
+	t.Parallel()
+	// For a tridiagonal matrix ILU(0) is the exact LU factorization.
+	n := 10
+	m := mat.NewCOO(n, n)
+	for i := 0; i < n; i++ {
+		m.Append(i, i, 3)
+		if i > 0 {
+			m.Append(i, i-1, -1)
+		}
+		if i < n-1 {
+			m.Append(i, i+1, -1.5)
+		}
+	}
+	tri := m.ToCSR()
+	p, err := NewILU0(tri)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkPreconInverse(t, "tridiagonal", p, tri)
+
+	// In general, L*U equals A on the sparsity pattern of A.
+	a := convectionDiffusion2D(5, 0.5)
+	n, _ = a.Dims()
+	p, err = NewILU0(a)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	inv := preconInverse(t, p, n, false)
+	var lu mat.Dense
+	if err := lu.Inverse(inv); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	a.DoNonZero(func(i, j int, v float64) {
+		if got := lu.At(i, j); !floats.EqualWithinAbsOrRel(got, v, 1e-10, 1e-10) {
+			t.Errorf("unexpected L*U element at (%d,%d): got %v, want %v", i, j, got, v)
+		}
+	})
+	checkPreconInverse(t, "convection-diffusion", p, &lu)
+
+	rnd := rand.New(rand.NewSource(1))
+	b := randVec(n, rnd)
+	for _, method := range []Method{GMRES{}, BiCGSTAB{}} {
+		plain, err := Iterative(Operator(a), b, method, nil)
+		if err != nil {
+			t.Fatalf("%T: unexpected error: %v", method, err)
+		}
+		res, err := Iterative(Operator(a), b, method, &Settings{Preconditioner: p})
+		if err != nil {
+			t.Fatalf("%T: unexpected error with preconditioner: %v", method, err)
+		}
+		checkResult(t, fmt.Sprintf("%T", method), a, b, res, 1e-8)
+		if res.Iterations >= plain.Iterations {
+			t.Errorf("%T: ILU(0) did not reduce iterations: %d >= %d", method, res.Iterations, plain.Iterations)
+		}
+	}
+
+	zero := mat.NewCOO(2, 2)
+	zero.Append(0, 1, 1)
+	zero.Append(1, 0, 1)
+	zero.Append(1, 1, 1)
+	if _, err := NewILU0(zero.ToCSR()); err != ErrZeroPivot {
+		t.Errorf("unexpected error for missing diagonal: got %v, want %v", err, ErrZeroPivot)
+	}
+}
+
+func TestSSOR(t *testing.T) {
+	t.Parallel()
+	a := convectionDiffusion2D(4, 0.5)
+	n, _ := a.Dims()
+	for _, omega := range []float64{0.5, 1, 1.5} {
+		p, err := NewSSOR(a, omega)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// Construct M = ω/(2-ω) (D/ω + L) (D/ω)⁻¹ (D/ω + U).
+		lower := mat.NewDense(n, n, nil)
+		upper := mat.NewDense(n, n, nil)
+		dinv := mat.NewDiagDense(n, nil)
+		a.DoNonZero(func(i, j int, v float64) {
+			switch {
+			case i > j:
+				lower.Set(i, j, v)
+			case i < j:
+				upper.Set(i, j, v)
+			default:
+				lower.Set(i, i, v/omega)
+				upper.Set(i, i, v/omega)
+				dinv.SetDiag(i, omega/v)
+			}
+		})
+		var m mat.Dense
+		m.Product(lower, dinv, upper)
+		m.Scale(omega/(2-omega), &m)
+		checkPreconInverse(t, fmt.Sprintf("omega=%v", omega), p, &m)
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	lap := laplacian2D(15)
+	n, _ = lap.Dims()
+	b := randVec(n, rnd)
+	p, err := NewSSOR(lap, 1.5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plain, err := Iterative(Operator(lap), b, CG{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res, err := Iterative(Operator(lap), b, CG{}, &Settings{Preconditioner: p})
+	if err != nil {
+		t.Fatalf("unexpected error with preconditioner: %v", err)
+	}
+	checkResult(t, "laplacian", lap, b, res, 1e-8)
+	if res.Iterations >= plain.Iterations {
+		t.Errorf("SSOR did not reduce iterations: %d >= %d", res.Iterations, plain.Iterations)
+	}
+
+	for _, omega := range []float64{0, 2, -1} {
+		if !panics(func() { NewSSOR(lap, omega) }) {
+			t.Errorf("expected panic for omega=%v", omega)
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return false
+}
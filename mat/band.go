@@ -7,6 +7,7 @@ package mat
 import (
 	"gonum.org/v1/gonum/blas"
 	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/internal/asm/f64"
 )
 
 var (
@@ -333,3 +334,29 @@ func (b *BandDense) MulVecTo(dst *VecDense, trans bool, x Vector) {
 		putWorkspaceVec(xCopy)
 	}
 }
+
+// mulBand computes A*B, or Aᵀ*B if aTrans is true, for the band matrix A and
+// stores the result in the receiver, which must have the correct size and
+// must not overlap A or B.
+func (m *Dense) mulBand(a *BandDense, aTrans bool, b Matrix) {
+	br, bc := b.Dims()
+	bd, ok := b.(*Dense)
+	if !ok {
+		bd = getWorkspace(br, bc, false)
+		bd.Copy(b)
+		defer putWorkspace(bd)
+	}
+	m.Zero()
+	ar, ac := a.Dims()
+	kl, ku := a.Bandwidth()
+	for i := 0; i < ar; i++ {
+		for j := max(0, i-kl); j < min(ac, i+ku+1); j++ {
+			v := a.mat.Data[i*a.mat.Stride+kl+j-i]
+			k, l := i, j
+			if aTrans {
+				k, l = j, i
+			}
+			f64.AxpyUnitary(v, bd.mat.Data[l*bd.mat.Stride:l*bd.mat.Stride+bc], m.mat.Data[k*m.mat.Stride:k*m.mat.Stride+bc])
+		}
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/lapack/lapack64"
+)
+
+const badBandLU = "mat: invalid band LU factorization"
+
+// BandLU is a type for creating and using the LU factorization of a square
+// band matrix. The factorization with partial pivoting of an n×n matrix with
+// kl sub-diagonals and ku super-diagonals takes O(n·kl·(kl+ku)) time and
+// O(n·(2·kl+ku)) storage, so it is much cheaper than the dense LU for narrow
+// bands.
+type BandLU struct {
+	// lu holds the factors in the storage format of lapack64.Gbtrf,
+	// which has kl additional columns for the fill-in of U.
+	lu    blas64.Band
+	pivot []int
+	cond  float64
+}
+
+// Factorize computes the LU factorization of the square band matrix a and
+// stores the result. The LU decomposition will complete regardless of the
+// singularity of a.
+func (lu *BandLU) Factorize(a Banded) {
+	n, c := a.Dims()
+	if n != c {
+		panic(ErrSquare)
+	}
+	kl, ku := a.Bandwidth()
+	stride := 2*kl + ku + 1
+	lu.lu = blas64.Band{
+		Rows:   n,
+		Cols:   n,
+		KL:     kl,
+		KU:     ku,
+		Stride: stride,
+		Data:   useZeroed(lu.lu.Data, n*stride),
+	}
+	data := lu.lu.Data
+	if b, ok := a.(*BandDense); ok {
+		for i := 0; i < n; i++ {
+			lo := max(0, kl-i)
+			hi := min(kl+ku+1, kl+n-i)
+			copy(data[i*stride+lo:i*stride+hi], b.mat.Data[i*b.mat.Stride+lo:i*b.mat.Stride+hi])
+		}
+	} else {
+		for i := 0; i < n; i++ {
+			for j := max(0, i-kl); j < min(n, i+ku+1); j++ {
+				data[i*stride+kl+j-i] = a.At(i, j)
+			}
+		}
+	}
+
+	// Compute the norm of A for the condition number.
+	var anorm float64
+	for i := 0; i < n; i++ {
+		var sum float64
+		for j := max(0, i-kl); j < min(n, i+ku+1); j++ {
+			sum += math.Abs(data[i*stride+kl+j-i])
+		}
+		anorm = math.Max(anorm, sum)
+	}
+
+	lu.pivot = useInt(lu.pivot, n)
+	if !lapack64.Gbtrf(lu.lu, lu.pivot) {
+		lu.cond = math.Inf(1)
+		return
+	}
+	// CondNorm is the infinity norm, and the infinity norm of A⁻¹ is the
+	// 1-norm of A⁻ᵀ.
+	lu.cond = anorm * normEst1(n, func(x []float64, trans bool) {
+		lu.solve(x, !trans)
+	})
+}
+
+// solve overwrites x with the solution of A*x = b or Aᵀ*x = b if trans is
+// true.
+func (lu *BandLU) solve(x []float64, trans bool) {
+	t := blas.NoTrans
+	if trans {
+		t = blas.Trans
+	}
+	lapack64.Gbtrs(t, lu.lu, blas64.General{Rows: len(x), Cols: 1, Stride: 1, Data: x}, lu.pivot)
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (lu *BandLU) isValid() bool {
+	return lu.lu.Rows != 0
+}
+
+// Cond returns the condition number for the factorized matrix.
+// Cond will panic if the receiver does not contain a factorization.
+func (lu *BandLU) Cond() float64 {
+	if !lu.isValid() {
+		panic(badBandLU)
+	}
+	return lu.cond
+}
+
+// Reset resets the factorization so that it can be reused as the receiver of a
+// dimensionally restricted operation.
+func (lu *BandLU) Reset() {
+	lu.lu.Rows = 0
+	lu.lu.Cols = 0
+	lu.lu.Data = lu.lu.Data[:0]
+	lu.pivot = lu.pivot[:0]
+}
+
+// Det returns the determinant of the matrix that has been factorized. In many
+// expressions, using LogDet will be more numerically stable.
+// Det will panic if the receiver does not contain a factorization.
+func (lu *BandLU) Det() float64 {
+	det, sign := lu.LogDet()
+	return math.Exp(det) * sign
+}
+
+// LogDet returns the log of the determinant and the sign of the determinant
+// for the matrix that has been factorized. Numerical stability in product and
+// division expressions is generally improved by working in log space.
+// LogDet will panic if the receiver does not contain a factorization.
+func (lu *BandLU) LogDet() (det float64, sign float64) {
+	if !lu.isValid() {
+		panic(badBandLU)
+	}
+	sign = 1
+	for i, p := range lu.pivot {
+		if p != i {
+			sign = -sign
+		}
+		v := lu.lu.Data[i*lu.lu.Stride+lu.lu.KL]
+		if v < 0 {
+			sign = -sign
+		}
+		det += math.Log(math.Abs(v))
+	}
+	return det, sign
+}
+
+// SolveTo solves a system of linear equations using the LU decomposition of a
+// band matrix. It computes
+//  A * X = B if trans == false
+//  Aᵀ * X = B if trans == true
+// In both cases, A is represented in LU factorized form, and the matrix X is
+// stored into dst.
+//
+// If A is singular or near-singular a Condition error is returned. See
+// the documentation for Condition for more information.
+// SolveTo will panic if the receiver does not contain a factorization.
+func (lu *BandLU) SolveTo(dst *Dense, trans bool, b Matrix) error {
+	if !lu.isValid() {
+		panic(badBandLU)
+	}
+	n := lu.lu.Rows
+	br, bc := b.Dims()
+	if br != n {
+		panic(ErrShape)
+	}
+	if math.IsInf(lu.cond, 1) {
+		return Condition(math.Inf(1))
+	}
+
+	dst.reuseAsNonZeroed(n, bc)
+	bU, _ := untranspose(b)
+	var restore func()
+	if dst == bU {
+		dst, restore = dst.isolatedWorkspace(bU)
+		defer restore()
+	} else if rm, ok := bU.(RawMatrixer); ok {
+		dst.checkOverlap(rm.RawMatrix())
+	}
+
+	dst.Copy(b)
+	t := blas.NoTrans
+	if trans {
+		t = blas.Trans
+	}
+	lapack64.Gbtrs(t, lu.lu, dst.mat, lu.pivot)
+	if lu.cond > ConditionTolerance {
+		return Condition(lu.cond)
+	}
+	return nil
+}
+
+// SolveVecTo solves a system of linear equations using the LU decomposition of
+// a band matrix. It computes
+//  A * x = b if trans == false
+//  Aᵀ * x = b if trans == true
+// In both cases, A is represented in LU factorized form, and the vector x is
+// stored into dst.
+//
+// If A is singular or near-singular a Condition error is returned. See
+// the documentation for Condition for more information.
+// SolveVecTo will panic if the receiver does not contain a factorization.
+func (lu *BandLU) SolveVecTo(dst *VecDense, trans bool, b Vector) error {
+	if !lu.isValid() {
+		panic(badBandLU)
+	}
+	n := lu.lu.Rows
+	if br, bc := b.Dims(); br != n || bc != 1 {
+		panic(ErrShape)
+	}
+	switch rv := b.(type) {
+	default:
+		dst.reuseAsNonZeroed(n)
+		return lu.SolveTo(dst.asDense(), trans, b)
+	case RawVectorer:
+		if dst != b {
+			dst.checkOverlap(rv.RawVector())
+		}
+		if math.IsInf(lu.cond, 1) {
+			return Condition(math.Inf(1))
+		}
+
+		dst.reuseAsNonZeroed(n)
+		var restore func()
+		if dst == b {
+			dst, restore = dst.isolatedWorkspace(b)
+			defer restore()
+		}
+		dst.CopyVec(b)
+		t := blas.NoTrans
+		if trans {
+			t = blas.Trans
+		}
+		lapack64.Gbtrs(t, lu.lu, generalFromVector(dst.mat, n, 1), lu.pivot)
+		if lu.cond > ConditionTolerance {
+			return Condition(lu.cond)
+		}
+		return nil
+	}
+}
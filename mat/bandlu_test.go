@@ -0,0 +1,168 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// randBand returns a random n×n band matrix with kl sub-diagonals and ku
+// super-diagonals.
+func randBand(n, kl, ku int, rnd *rand.Rand) *BandDense {
+	b := NewBandDense(n, n, kl, ku, nil)
+	for i := 0; i < n; i++ {
+		for j := max(0, i-kl); j < min(n, i+ku+1); j++ {
+			b.SetBand(i, j, rnd.NormFloat64())
+		}
+		// Avoid the exponentially large condition numbers of random
+		// triangular matrices.
+		b.SetBand(i, i, b.At(i, i)+math.Copysign(2, b.At(i, i)))
+	}
+	return b
+}
+
+func TestBandLU(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 5, 10, 50} {
+		for _, kl := range []int{0, 1, 2, 4} {
+			for _, ku := range []int{0, 1, 3} {
+				if kl >= n || ku >= n {
+					continue
+				}
+				a := randBand(n, kl, ku, rnd)
+				for _, m := range []Banded{a, a.TBand()} {
+					var lu BandLU
+					lu.Factorize(m)
+					var dlu LU
+					dlu.Factorize(m)
+
+					det, sign := lu.LogDet()
+					wantDet, wantSign := dlu.LogDet()
+					if sign != wantSign || math.Abs(det-wantDet) > 1e-10*math.Max(1, math.Abs(wantDet)) {
+						t.Errorf("n=%d,kl=%d,ku=%d: unexpected log determinant: got %v,%v, want %v,%v", n, kl, ku, det, sign, wantDet, wantSign)
+					}
+					if cond, want := lu.Cond(), dlu.Cond(); cond > 10*want || cond < want/10 {
+						t.Errorf("n=%d,kl=%d,ku=%d: poor condition number estimate: got %v, want %v", n, kl, ku, cond, want)
+					}
+
+					for _, trans := range []bool{false, true} {
+						b := NewDense(n, 3, nil)
+						for i := 0; i < n; i++ {
+							for j := 0; j < 3; j++ {
+								b.Set(i, j, rnd.NormFloat64())
+							}
+						}
+						var x, want Dense
+						if err := lu.SolveTo(&x, trans, b); err != nil {
+							t.Fatalf("n=%d: unexpected error: %v", n, err)
+						}
+						dlu.SolveTo(&want, trans, b)
+						if !EqualApprox(&x, &want, 1e-10) {
+							t.Errorf("n=%d,kl=%d,ku=%d,trans=%t: unexpected solution", n, kl, ku, trans)
+						}
+
+						bv := b.ColView(2)
+						var xv VecDense
+						if err := lu.SolveVecTo(&xv, trans, bv); err != nil {
+							t.Fatalf("n=%d: unexpected error: %v", n, err)
+						}
+						if !EqualApprox(&xv, want.ColView(2), 1e-10) {
+							t.Errorf("n=%d,kl=%d,ku=%d,trans=%t: unexpected vector solution", n, kl, ku, trans)
+						}
+						// The solution may overwrite the right-hand side.
+						xv.CloneFromVec(bv)
+						if err := lu.SolveVecTo(&xv, trans, &xv); err != nil {
+							t.Fatalf("n=%d: unexpected error: %v", n, err)
+						}
+						if !EqualApprox(&xv, want.ColView(2), 1e-10) {
+							t.Errorf("n=%d,kl=%d,ku=%d,trans=%t: unexpected in-place solution", n, kl, ku, trans)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// A singular matrix.
+	a := NewBandDense(3, 3, 1, 1, []float64{
+		0, 1, 2,
+		2, 4, 0,
+		0, 1, 1,
+	})
+	var lu BandLU
+	lu.Factorize(a)
+	if lu.Det() != 0 {
+		t.Errorf("unexpected determinant of singular matrix: %v", lu.Det())
+	}
+	var x Dense
+	err := lu.SolveTo(&x, false, NewDense(3, 1, []float64{1, 2, 3}))
+	if _, ok := err.(Condition); !ok {
+		t.Errorf("expected Condition error for singular matrix, got %v", err)
+	}
+
+	lu.Reset()
+	if ok, _ := panics(func() { lu.Cond() }); !ok {
+		t.Errorf("expected panic for reset factorization")
+	}
+}
+
+func TestBandMul(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		r, c, kl, ku int
+	}{
+		{1, 1, 0, 0},
+		{5, 5, 1, 2},
+		{7, 4, 3, 1},
+		{4, 7, 2, 3},
+		{10, 10, 0, 4},
+	} {
+		a := NewBandDense(test.r, test.c, test.kl, test.ku, nil)
+		for i := 0; i < test.r; i++ {
+			for j := max(0, i-test.kl); j < min(test.c, i+test.ku+1); j++ {
+				a.SetBand(i, j, rnd.NormFloat64())
+			}
+		}
+		ad := DenseCopyOf(a)
+		for _, trans := range []bool{false, true} {
+			var m, md Matrix = a, ad
+			k := test.c
+			if trans {
+				m, md = a.T(), ad.T()
+				k = test.r
+			}
+			b := NewDense(k, 3, nil)
+			for i := 0; i < k; i++ {
+				for j := 0; j < 3; j++ {
+					b.Set(i, j, rnd.NormFloat64())
+				}
+			}
+			var got, want Dense
+			got.Mul(m, b)
+			want.Mul(md, b)
+			if !EqualApprox(&got, &want, 1e-14) {
+				t.Errorf("r=%d,c=%d,trans=%t: unexpected product", test.r, test.c, trans)
+			}
+			// A non-Dense right operand.
+			got.Reset()
+			got.Mul(m, asBasicMatrix(b))
+			if !EqualApprox(&got, &want, 1e-14) {
+				t.Errorf("r=%d,c=%d,trans=%t: unexpected product with non-Dense operand", test.r, test.c, trans)
+			}
+
+			var gotVec, wantVec VecDense
+			gotVec.MulVec(m, b.ColView(1))
+			wantVec.MulVec(md, b.ColView(1))
+			if !EqualApprox(&gotVec, &wantVec, 1e-14) {
+				t.Errorf("r=%d,c=%d,trans=%t: unexpected vector product", test.r, test.c, trans)
+			}
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+)
+
+// trsmMinCols is the minimum number of right-hand side columns handled by
+// each goroutine of a parallel triangular solve.
+const trsmMinCols = 64
+
+// maxWorkers is the maximum number of goroutines used by the concurrent
+// solves. If it is not positive, GOMAXPROCS is used.
+var maxWorkers int32
+
+// SetMaxWorkers sets the maximum number of goroutines that SolveBatch,
+// SolveTriBatch and the triangular solves of Dense.Solve run concurrently to
+// n, and returns the previous value. If n is zero or less, the limit is the
+// value of runtime.GOMAXPROCS, which is the default. Setting n to one solves
+// all systems serially. The limit does not apply to the BLAS implementation,
+// see gonum.SetMaxWorkers in the blas/gonum package for the limit of the
+// default implementation. SetMaxWorkers is safe for concurrent use, and the
+// limit applies to subsequent calls.
+func SetMaxWorkers(n int) (prev int) {
+	if n < 0 {
+		n = 0
+	}
+	return int(atomic.SwapInt32(&maxWorkers, int32(n)))
+}
+
+// solveWorkers returns the maximum number of goroutines for concurrent solves.
+func solveWorkers() int {
+	if n := atomic.LoadInt32(&maxWorkers); n > 0 {
+		return int(n)
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// SolveTriBatch solves the batch of triangular systems of linear equations
+//  A[i] * X[i] = B[i] if trans == false
+//  A[i]ᵀ * X[i] = B[i] if trans == true
+// storing each solution X[i] into dst[i], which is resized if it is empty.
+// The systems are solved concurrently on up to the number of goroutines set by
+// SetMaxWorkers, which is much faster than solving them in turn when the
+// matrices are small, for example the lower triangular Cholesky factors of the
+// covariance matrices of many particles. Each B[i] may have many columns, and
+// the dst[i] must not share backing data.
+//
+// All the systems are solved. If A[i] is singular or near-singular for some i,
+// the Condition error of the first such system in the batch is returned. See
+// the documentation for Condition for more information.
+//
+// SolveTriBatch will panic with ErrShape if the lengths of dst, a and b differ
+// or if the dimensions of a system do not match.
+func SolveTriBatch(dst []*Dense, trans bool, a []Triangular, b []Matrix) error {
+	if len(dst) != len(a) || len(a) != len(b) {
+		panic(ErrShape)
+	}
+	return batchDo(len(a), func(i int) error {
+		var ai Matrix = a[i]
+		if trans {
+			ai = a[i].T()
+		}
+		return dst[i].Solve(ai, b[i])
+	})
+}
+
+// SolveBatch solves the batch of square systems of linear equations
+//  A[i] * X[i] = B[i]
+// storing each solution X[i] into dst[i], which is resized if it is empty.
+// The systems are solved concurrently using their LU factorizations, on up to
+// the number of goroutines set by SetMaxWorkers. The dst[i] must not share
+// backing data.
+//
+// All the systems are solved. If A[i] is singular or near-singular for some i,
+// the Condition error of the first such system in the batch is returned. See
+// the documentation for Condition for more information.
+//
+// SolveBatch will panic with ErrShape if the lengths of dst, a and b differ or
+// if the dimensions of a system do not match, and with ErrSquare if some A[i]
+// is not square.
+func SolveBatch(dst []*Dense, a, b []Matrix) error {
+	if len(dst) != len(a) || len(a) != len(b) {
+		panic(ErrShape)
+	}
+	for _, ai := range a {
+		if r, c := ai.Dims(); r != c {
+			panic(ErrSquare)
+		}
+	}
+	return batchDo(len(a), func(i int) error {
+		var lu LU
+		lu.Factorize(a[i])
+		return lu.SolveTo(dst[i], false, b[i])
+	})
+}
+
+// batchDo calls fn for each i in [0, n) on up to the number of goroutines set
+// by SetMaxWorkers and returns the error of the smallest i for which fn
+// returned an error. A panic in fn is propagated to the caller.
+func batchDo(n int, fn func(i int) error) error {
+	errs := make([]error, n)
+	workers := min(n, solveWorkers())
+	if workers <= 1 {
+		for i := range errs {
+			errs[i] = fn(i)
+		}
+	} else {
+		var (
+			wg   sync.WaitGroup
+			mu   sync.Mutex
+			next int
+			perr interface{}
+		)
+		wg.Add(workers)
+		for w := 0; w < workers; w++ {
+			go func() {
+				defer wg.Done()
+				defer func() {
+					if r := recover(); r != nil {
+						mu.Lock()
+						if perr == nil {
+							perr = r
+						}
+						next = n
+						mu.Unlock()
+					}
+				}()
+				for {
+					mu.Lock()
+					i := next
+					next++
+					mu.Unlock()
+					if i >= n {
+						return
+					}
+					errs[i] = fn(i)
+				}
+			}()
+		}
+		wg.Wait()
+		if perr != nil {
+			panic(perr)
+		}
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trsm solves op(A) * X = B for the n×n triangular A, overwriting B with X.
+// Right-hand sides with many columns are split into blocks of columns that
+// are solved concurrently on up to the number of goroutines set by
+// SetMaxWorkers.
+func trsm(tA blas.Transpose, a blas64.Triangular, b blas64.General) {
+	workers := min(b.Cols/trsmMinCols, solveWorkers())
+	if workers <= 1 {
+		blas64.Trsm(blas.Left, tA, 1, a, b)
+		return
+	}
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		j0 := w * b.Cols / workers
+		j1 := (w + 1) * b.Cols / workers
+		blk := blas64.General{
+			Rows:   b.Rows,
+			Cols:   j1 - j0,
+			Stride: b.Stride,
+			Data:   b.Data[j0:],
+		}
+		go func() {
+			defer wg.Done()
+			blas64.Trsm(blas.Left, tA, 1, a, blk)
+		}()
+	}
+	wg.Wait()
+}
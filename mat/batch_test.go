@@ -0,0 +1,213 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestSolveTriBatch(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const batch = 50
+	for _, trans := range []bool{false, true} {
+		a := make([]Triangular, batch)
+		b := make([]Matrix, batch)
+		dst := make([]*Dense, batch)
+		for i := range a {
+			n := 1 + rnd.Intn(6)
+			kind := Upper
+			if i%2 == 0 {
+				kind = Lower
+			}
+			tri := NewTriDense(n, kind, nil)
+			for r := 0; r < n; r++ {
+				for c := 0; c < n; c++ {
+					if (kind == Upper && c >= r) || (kind == Lower && c <= r) {
+						tri.SetTri(r, c, rnd.NormFloat64())
+					}
+				}
+				tri.SetTri(r, r, 5+rnd.Float64())
+			}
+			a[i] = tri
+			b[i] = randNormal(n, 1+rnd.Intn(3), rnd)
+			dst[i] = &Dense{}
+		}
+		err := SolveTriBatch(dst, trans, a, b)
+		if err != nil {
+			t.Fatalf("trans=%t: unexpected error: %v", trans, err)
+		}
+		for i := range a {
+			var ai Matrix = a[i]
+			if trans {
+				ai = a[i].T()
+			}
+			var got Dense
+			got.Mul(ai, dst[i])
+			if !EqualApprox(&got, b[i], 1e-12) {
+				t.Errorf("trans=%t: system %d not solved", trans, i)
+			}
+		}
+	}
+
+	a := []Triangular{
+		NewTriDense(2, Upper, []float64{1, 2, 0, 3}),
+		NewTriDense(2, Upper, []float64{1, 2, 0, 0}),
+		NewTriDense(2, Upper, []float64{0, 2, 0, 1}),
+	}
+	b := []Matrix{NewDense(2, 1, []float64{1, 1}), NewDense(2, 1, []float64{1, 1}), NewDense(2, 1, []float64{1, 1})}
+	dst := []*Dense{{}, {}, {}}
+	err := SolveTriBatch(dst, false, a, b)
+	if _, ok := err.(Condition); !ok {
+		t.Errorf("expected Condition error for singular system, got %v", err)
+	}
+
+	panicked, _ := panics(func() { SolveTriBatch(dst[:1], false, a, b) })
+	if !panicked {
+		t.Error("expected panic for mismatched batch lengths")
+	}
+	b[2] = NewDense(3, 1, nil)
+	panicked, message := panics(func() { SolveTriBatch(dst, false, a, b) })
+	if !panicked || message != ErrShape.Error() {
+		t.Errorf("expected panic %q for mismatched system, got %q", ErrShape, message)
+	}
+}
+
+func TestSolveBatch(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const batch = 40
+	a := make([]Matrix, batch)
+	b := make([]Matrix, batch)
+	dst := make([]*Dense, batch)
+	for i := range a {
+		n := 1 + rnd.Intn(8)
+		a[i] = randNormal(n, n, rnd)
+		b[i] = randNormal(n, 2, rnd)
+		dst[i] = &Dense{}
+	}
+	err := SolveBatch(dst, a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range a {
+		var want Dense
+		err := want.Solve(a[i], b[i])
+		if err != nil {
+			t.Fatalf("unexpected error for system %d: %v", i, err)
+		}
+		if !EqualApprox(dst[i], &want, 1e-10) {
+			t.Errorf("system %d: unexpected solution", i)
+		}
+	}
+
+	panicked, message := panics(func() {
+		SolveBatch([]*Dense{{}}, []Matrix{NewDense(2, 3, nil)}, []Matrix{NewDense(2, 1, nil)})
+	})
+	if !panicked || message != ErrSquare.Error() {
+		t.Errorf("expected panic %q for non-square system, got %q", ErrSquare, message)
+	}
+}
+
+func TestSolveTriManyRHS(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		n, rhs int
+	}{
+		{n: 10, rhs: 1},
+		{n: 20, rhs: trsmMinCols},
+		{n: 30, rhs: 4*trsmMinCols + 7},
+	} {
+		for _, kind := range []TriKind{Upper, Lower} {
+			for _, trans := range []bool{false, true} {
+				name := fmt.Sprintf("n=%d rhs=%d kind=%v trans=%t", test.n, test.rhs, kind, trans)
+				tri := NewTriDense(test.n, kind, nil)
+				tri.Copy(randNormal(test.n, test.n, rnd))
+				for i := 0; i < test.n; i++ {
+					tri.SetTri(i, i, float64(test.n))
+				}
+				var a Matrix = tri
+				if trans {
+					a = tri.T()
+				}
+				b := randNormal(test.n, test.rhs, rnd)
+				var x, got Dense
+				err := x.Solve(a, b)
+				if err != nil {
+					t.Fatalf("%s: unexpected error: %v", name, err)
+				}
+				got.Mul(a, &x)
+				if !EqualApprox(&got, b, 1e-10) {
+					t.Errorf("%s: system not solved", name)
+				}
+			}
+		}
+	}
+}
+
+func TestBatchDoError(t *testing.T) {
+	t.Parallel()
+	// The error of the first failing system is returned.
+	err := batchDo(10, func(i int) error {
+		if i >= 3 {
+			return Condition(i)
+		}
+		return nil
+	})
+	if err != Condition(3) {
+		t.Errorf("unexpected error: got %v want %v", err, Condition(3))
+	}
+}
+
+// TestBatchMaxWorkers is not run in parallel since it changes GOMAXPROCS and
+// the limit on the number of workers.
+func TestBatchMaxWorkers(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(8))
+	defer SetMaxWorkers(SetMaxWorkers(2))
+	var running, most int32
+	err := batchDo(100, func(i int) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&most)
+			if n <= m || atomic.CompareAndSwapInt32(&most, m, n) {
+				break
+			}
+		}
+		for j := 0; j < 10; j++ {
+			runtime.Gosched()
+		}
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if most > 2 {
+		t.Errorf("too many concurrent workers: got %d want at most 2", most)
+	}
+}
+
+func TestSetMaxWorkers(t *testing.T) {
+	defer SetMaxWorkers(SetMaxWorkers(0))
+	for _, n := range []int{1, 3, 0, -1} {
+		SetMaxWorkers(n)
+		want := n
+		if n <= 0 {
+			want = runtime.GOMAXPROCS(0)
+		}
+		if got := solveWorkers(); got != want {
+			t.Errorf("unexpected number of workers for SetMaxWorkers(%d): got %d want %d", n, got, want)
+		}
+	}
+	if prev := SetMaxWorkers(4); prev != 0 {
+		t.Errorf("unexpected previous limit: got %d want 0", prev)
+	}
+}
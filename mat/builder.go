@@ -0,0 +1,102 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "gonum.org/v1/gonum/blas/blas64"
+
+// DenseBuilder assembles a dense matrix by appending rows or columns, such as
+// when streaming data of unknown length. Unlike Stack and Augment, which copy
+// both operands, appending a row or column copies only its elements in
+// amortized constant time per element, by reserving spare capacity as it
+// grows. The assembled matrix is obtained with the Dense method.
+//
+// The zero value of a DenseBuilder is an empty builder ready to use.
+type DenseBuilder struct {
+	// mat holds the assembled matrix. Its stride is the column capacity
+	// and its data holds complete strides for each row.
+	mat blas64.General
+}
+
+// Dims returns the number of rows and columns appended so far.
+func (b *DenseBuilder) Dims() (r, c int) {
+	return b.mat.Rows, b.mat.Cols
+}
+
+// AppendRow appends a row with the elements of row to the matrix. The first
+// row or column appended sets the dimension of the matrix that is fixed for
+// appending rows, so AppendRow will panic with ErrShape if the length of row
+// is not the number of columns, or with ErrZeroLength if row is empty.
+func (b *DenseBuilder) AppendRow(row []float64) {
+	if len(row) == 0 {
+		panic(ErrZeroLength)
+	}
+	if b.mat.Rows == 0 && b.mat.Cols == 0 {
+		b.mat.Cols = len(row)
+		b.mat.Stride = len(row)
+	}
+	if len(row) != b.mat.Cols {
+		panic(ErrShape)
+	}
+	b.mat.Data = append(b.mat.Data, row...)
+	for k := len(row); k < b.mat.Stride; k++ {
+		b.mat.Data = append(b.mat.Data, 0)
+	}
+	b.mat.Rows++
+}
+
+// AppendCol appends a column with the elements of col to the matrix. AppendCol
+// will panic with ErrShape if the length of col is not the number of rows, or
+// with ErrZeroLength if col is empty.
+func (b *DenseBuilder) AppendCol(col []float64) {
+	if len(col) == 0 {
+		panic(ErrZeroLength)
+	}
+	if b.mat.Rows == 0 && b.mat.Cols == 0 {
+		b.mat.Rows = len(col)
+		b.mat.Stride = 1
+		b.mat.Data = use(b.mat.Data, len(col))
+	} else {
+		if len(col) != b.mat.Rows {
+			panic(ErrShape)
+		}
+		if b.mat.Cols == b.mat.Stride {
+			// Double the column capacity, moving the rows to the
+			// new stride.
+			stride := 2 * b.mat.Stride
+			data := make([]float64, b.mat.Rows*stride)
+			for i := 0; i < b.mat.Rows; i++ {
+				copy(data[i*stride:i*stride+b.mat.Cols], b.mat.Data[i*b.mat.Stride:])
+			}
+			b.mat.Stride = stride
+			b.mat.Data = data
+		}
+	}
+	j := b.mat.Cols
+	for i, v := range col {
+		b.mat.Data[i*b.mat.Stride+j] = v
+	}
+	b.mat.Cols++
+}
+
+// Dense returns the assembled matrix and resets the receiver to an empty
+// builder. The returned matrix uses the memory of the builder without copying,
+// so its column capacity may exceed its number of columns. Dense returns an
+// empty matrix if nothing has been appended.
+func (b *DenseBuilder) Dense() *Dense {
+	m := &Dense{
+		mat:     b.mat,
+		capRows: b.mat.Rows,
+		capCols: b.mat.Stride,
+	}
+	b.mat = blas64.General{}
+	return m
+}
+
+// Reset discards the appended rows and columns, retaining the memory of the
+// builder for reuse.
+func (b *DenseBuilder) Reset() {
+	b.mat.Rows, b.mat.Cols, b.mat.Stride = 0, 0, 0
+	b.mat.Data = b.mat.Data[:0]
+}
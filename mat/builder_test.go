@@ -0,0 +1,94 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestDenseBuilder(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, rowsFirst := range []bool{true, false} {
+		for _, n := range []int{1, 2, 7, 50} {
+			var b DenseBuilder
+			want := NewDense(n, n, nil)
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					want.Set(i, j, rnd.NormFloat64())
+				}
+			}
+
+			// Append the first half of the rows or columns, then the
+			// remaining columns or rows of the leading block, then the
+			// remaining rows or columns.
+			h := (n + 1) / 2
+			if rowsFirst {
+				for i := 0; i < h; i++ {
+					b.AppendRow(Row(nil, i, want.Slice(0, n, 0, h)))
+				}
+				for j := h; j < n; j++ {
+					b.AppendCol(Col(nil, j, want.Slice(0, h, 0, n)))
+				}
+				for i := h; i < n; i++ {
+					b.AppendRow(Row(nil, i, want))
+				}
+			} else {
+				for j := 0; j < h; j++ {
+					b.AppendCol(Col(nil, j, want.Slice(0, h, 0, n)))
+				}
+				for i := h; i < n; i++ {
+					b.AppendRow(Row(nil, i, want.Slice(0, n, 0, h)))
+				}
+				for j := h; j < n; j++ {
+					b.AppendCol(Col(nil, j, want))
+				}
+			}
+
+			if r, c := b.Dims(); r != n || c != n {
+				t.Errorf("unexpected builder dimensions: got %d×%d want %d×%d", r, c, n, n)
+			}
+			got := b.Dense()
+			if !Equal(got, want) {
+				t.Errorf("unexpected matrix for n=%d rowsFirst=%t:\ngot:\n%v\nwant:\n%v", n, rowsFirst, Formatted(got), Formatted(want))
+			}
+			if r, c := b.Dims(); r != 0 || c != 0 {
+				t.Errorf("builder not reset by Dense")
+			}
+
+			// The returned matrix can be used as a receiver.
+			got.Add(got, want)
+			want.Scale(2, want)
+			if !Equal(got, want) {
+				t.Errorf("unexpected sum for n=%d", n)
+			}
+		}
+	}
+
+	var b DenseBuilder
+	if m := b.Dense(); !m.IsEmpty() {
+		t.Errorf("expected empty matrix from empty builder")
+	}
+	b.AppendRow([]float64{1, 2})
+	b.Reset()
+	b.AppendCol([]float64{3, 4, 5})
+	if !Equal(b.Dense(), NewDense(3, 1, []float64{3, 4, 5})) {
+		t.Errorf("unexpected matrix after Reset")
+	}
+
+	b.AppendRow([]float64{1, 2})
+	for _, fn := range []func(){
+		func() { b.AppendRow([]float64{1}) },
+		func() { b.AppendCol([]float64{1, 2}) },
+		func() { b.AppendRow(nil) },
+		func() { b.AppendCol(nil) },
+	} {
+		if ok, _ := panics(fn); !ok {
+			t.Errorf("expected panic")
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/cblas128"
+)
+
+// isolated returns a copy of a if a is the receiver or the conjugate
+// transpose of the receiver, and a otherwise. It is used by element-wise
+// operations, which can be done in place unless the receiver is also read
+// transposed.
+func (m *CDense) isolated(a CMatrix, transposed bool) CMatrix {
+	aU, conj := unconjugate(a)
+	if aU != m || !(conj || transposed) {
+		return a
+	}
+	r, c := a.Dims()
+	tmp := NewCDense(r, c, nil)
+	tmp.Copy(a)
+	return tmp
+}
+
+// Add adds a and b element-wise, placing the result in the receiver. Add
+// will panic if the two matrices do not have the same shape.
+func (m *CDense) Add(a, b CMatrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != br || ac != bc {
+		panic(ErrShape)
+	}
+	a = m.isolated(a, false)
+	b = m.isolated(b, false)
+	m.reuseAsNonZeroed(ar, ac)
+	for i := 0; i < ar; i++ {
+		row := m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+ac]
+		for j := range row {
+			row[j] = a.At(i, j) + b.At(i, j)
+		}
+	}
+}
+
+// Sub subtracts the matrix b from a, placing the result in the receiver. Sub
+// will panic if the two matrices do not have the same shape.
+func (m *CDense) Sub(a, b CMatrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != br || ac != bc {
+		panic(ErrShape)
+	}
+	a = m.isolated(a, false)
+	b = m.isolated(b, false)
+	m.reuseAsNonZeroed(ar, ac)
+	for i := 0; i < ar; i++ {
+		row := m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+ac]
+		for j := range row {
+			row[j] = a.At(i, j) - b.At(i, j)
+		}
+	}
+}
+
+// Scale multiplies the elements of a by f, placing the result in the receiver.
+//
+// See the Scaler interface for more information.
+func (m *CDense) Scale(f complex128, a CMatrix) {
+	r, c := a.Dims()
+	a = m.isolated(a, false)
+	m.reuseAsNonZeroed(r, c)
+	for i := 0; i < r; i++ {
+		row := m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+c]
+		for j := range row {
+			row[j] = f * a.At(i, j)
+		}
+	}
+}
+
+// Conj calculates the element-wise conjugate of a and stores the result in the
+// receiver. The conjugate transpose of a is returned by its H method.
+func (m *CDense) Conj(a CMatrix) {
+	r, c := a.Dims()
+	a = m.isolated(a, false)
+	m.reuseAsNonZeroed(r, c)
+	for i := 0; i < r; i++ {
+		row := m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+c]
+		for j := range row {
+			row[j] = cmplx.Conj(a.At(i, j))
+		}
+	}
+}
+
+// Mul takes the matrix product of a and b, placing the result in the receiver.
+// If the number of columns in a does not equal the number of rows in b, Mul
+// will panic.
+func (m *CDense) Mul(a, b CMatrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ac != br {
+		panic(ErrShape)
+	}
+	a = m.isolated(a, true)
+	b = m.isolated(b, true)
+	aU, aConj := unconjugate(a)
+	bU, bConj := unconjugate(b)
+	m.reuseAsNonZeroed(ar, bc)
+
+	if aRaw, ok := aU.(RawCMatrixer); ok {
+		if bRaw, ok := bU.(RawCMatrixer); ok {
+			aT := blas.NoTrans
+			if aConj {
+				aT = blas.ConjTrans
+			}
+			bT := blas.NoTrans
+			if bConj {
+				bT = blas.ConjTrans
+			}
+			cblas128.Gemm(aT, bT, 1, aRaw.RawCMatrix(), bRaw.RawCMatrix(), 0, m.mat)
+			return
+		}
+	}
+
+	row := make([]complex128, ac)
+	for r := 0; r < ar; r++ {
+		for i := range row {
+			row[i] = a.At(r, i)
+		}
+		for c := 0; c < bc; c++ {
+			var v complex128
+			for i, e := range row {
+				v += e * b.At(i, c)
+			}
+			m.mat.Data[r*m.mat.Stride+c] = v
+		}
+	}
+}
+
+// Solve solves the linear least squares problem
+//  minimize over x |b - A*x|_2
+// where A is an m×n matrix A, b is a given m element vector and x is n element
+// solution vector. Solve assumes that A has full rank, that is
+//  rank(A) = min(m,n)
+//
+// If m >= n, Solve finds the unique least squares solution of an overdetermined
+// system.
+//
+// If m < n, there is an infinite number of solutions that satisfy b-A*x=0. In
+// this case Solve finds the unique solution of an underdetermined system that
+// minimizes |x|_2.
+//
+// Several right-hand side vectors b and solution vectors x can be handled in a
+// single call. Vectors b are stored in the columns of the m×k matrix B. Vectors
+// x will be stored in-place into the n×k receiver.
+//
+// If the underlying matrix of a is a solved singular system, or if the matrix
+// is near singular, Solve will return a Condition error. See the documentation
+// for Condition for more information.
+func (m *CDense) Solve(a, b CMatrix) error {
+	ar, ac := a.Dims()
+	br, _ := b.Dims()
+	if ar != br {
+		panic(ErrShape)
+	}
+	switch {
+	case ar == ac:
+		var lu CLU
+		lu.Factorize(a)
+		return lu.SolveTo(m, false, b)
+	case ar > ac:
+		var qr CQR
+		qr.Factorize(a)
+		return qr.SolveTo(m, false, b)
+	default:
+		var qr CQR
+		qr.Factorize(a.H())
+		return qr.SolveTo(m, true, b)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// randCDense returns an r×c matrix with random complex elements.
+func randCDense(r, c int, rnd *rand.Rand) *CDense {
+	m := NewCDense(r, c, nil)
+	for i := range m.mat.Data {
+		m.mat.Data[i] = complex(rnd.NormFloat64(), rnd.NormFloat64())
+	}
+	return m
+}
+
+// naiveCMul returns the product of a and b computed element by element.
+func naiveCMul(a, b CMatrix) *CDense {
+	ar, ac := a.Dims()
+	_, bc := b.Dims()
+	m := NewCDense(ar, bc, nil)
+	for i := 0; i < ar; i++ {
+		for j := 0; j < bc; j++ {
+			var v complex128
+			for k := 0; k < ac; k++ {
+				v += a.At(i, k) * b.At(k, j)
+			}
+			m.Set(i, j, v)
+		}
+	}
+	return m
+}
+
+// basicCMatrix is a CMatrix that is not a RawCMatrixer.
+type basicCMatrix CDense
+
+func (m *basicCMatrix) Dims() (r, c int)       { return (*CDense)(m).Dims() }
+func (m *basicCMatrix) At(i, j int) complex128 { return (*CDense)(m).At(i, j) }
+func (m *basicCMatrix) H() CMatrix             { return Conjugate{m} }
+
+func TestCDenseMul(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		ar, ac, bc int
+	}{
+		{1, 1, 1},
+		{3, 4, 2},
+		{5, 5, 5},
+		{2, 7, 3},
+	} {
+		a := randCDense(test.ar, test.ac, rnd)
+		b := randCDense(test.ac, test.bc, rnd)
+		ah := randCDense(test.ac, test.ar, rnd)
+		bh := randCDense(test.bc, test.ac, rnd)
+		for _, pair := range []struct {
+			a, b CMatrix
+		}{
+			{a, b},
+			{ah.H(), b},
+			{a, bh.H()},
+			{ah.H(), bh.H()},
+			{(*basicCMatrix)(a), b},
+			{a, (*basicCMatrix)(bh).H()},
+		} {
+			var got CDense
+			got.Mul(pair.a, pair.b)
+			want := naiveCMul(pair.a, pair.b)
+			if !CEqualApprox(&got, want, 1e-14) {
+				t.Errorf("unexpected product for %T and %T", pair.a, pair.b)
+			}
+		}
+	}
+
+	// The receiver may be an operand.
+	a := randCDense(4, 4, rnd)
+	want := naiveCMul(a, a.H())
+	a.Mul(a, a.H())
+	if !CEqualApprox(a, want, 1e-14) {
+		t.Errorf("unexpected product with aliased receiver")
+	}
+
+	if ok, _ := panics(func() {
+		var m CDense
+		m.Mul(NewCDense(2, 3, nil), NewCDense(2, 3, nil))
+	}); !ok {
+		t.Errorf("expected panic for shape mismatch")
+	}
+}
+
+func TestCDenseElementwise(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	a := randCDense(3, 3, rnd)
+	b := randCDense(3, 3, rnd)
+	f := complex(2, -1)
+
+	var add, sub, scale, conj CDense
+	add.Add(a, b.H())
+	sub.Sub(a, b)
+	scale.Scale(f, a)
+	conj.Conj(a)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if add.At(i, j) != a.At(i, j)+cmplx.Conj(b.At(j, i)) {
+				t.Errorf("unexpected sum at (%d,%d)", i, j)
+			}
+			if sub.At(i, j) != a.At(i, j)-b.At(i, j) {
+				t.Errorf("unexpected difference at (%d,%d)", i, j)
+			}
+			if scale.At(i, j) != f*a.At(i, j) {
+				t.Errorf("unexpected scaled element at (%d,%d)", i, j)
+			}
+			if conj.At(i, j) != cmplx.Conj(a.At(i, j)) {
+				t.Errorf("unexpected conjugate at (%d,%d)", i, j)
+			}
+		}
+	}
+
+	// Adding the conjugate transpose of the receiver in place gives a
+	// Hermitian matrix.
+	herm := NewCDense(3, 3, nil)
+	herm.Copy(a)
+	herm.Add(herm, herm.H())
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if want := a.At(i, j) + cmplx.Conj(a.At(j, i)); herm.At(i, j) != want {
+				t.Errorf("unexpected Hermitian sum at (%d,%d): got %v, want %v", i, j, herm.At(i, j), want)
+			}
+		}
+	}
+}
+
+func TestCDenseSolve(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		r, c int
+	}{
+		{3, 3},
+		{6, 3},
+		{3, 6},
+	} {
+		a := randCDense(test.r, test.c, rnd)
+		b := randCDense(test.r, 2, rnd)
+		var x CDense
+		if err := x.Solve(a, b); err != nil {
+			t.Fatalf("r=%d,c=%d: unexpected error: %v", test.r, test.c, err)
+		}
+		var res CDense
+		res.Mul(a, &x)
+		res.Sub(&res, b)
+		if test.r > test.c {
+			// The residual of the least squares solution is
+			// orthogonal to the columns of A.
+			var ortho CDense
+			ortho.Mul(a.H(), &res)
+			res = ortho
+		}
+		if !CEqualApprox(&res, NewCDense(res.mat.Rows, res.mat.Cols, nil), 1e-12) {
+			t.Errorf("r=%d,c=%d: unexpected solution", test.r, test.c)
+		}
+	}
+}
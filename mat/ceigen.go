@@ -0,0 +1,620 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+const (
+	// dlamchP is the relative machine precision times the base of the
+	// floating point representation, as computed by LAPACK's dlamch.
+	dlamchP = 0x1p-52
+	// dlamchS is the smallest normal floating point number.
+	dlamchS = 0x1p-1022
+)
+
+// CEigenHerm is a type for creating and manipulating the Eigen decomposition of
+// Hermitian matrices.
+type CEigenHerm struct {
+	vectorsComputed bool
+
+	values  []float64
+	vectors *CDense
+}
+
+// Factorize computes the eigenvalue decomposition of the Hermitian matrix a.
+// Only the upper triangle of a is referenced, and the imaginary parts of its
+// diagonal elements are assumed to be zero. Factorize panics if a is not
+// square.
+//
+// The Eigen decomposition is defined as
+//  A = P * D * Pᴴ
+// where D is a real diagonal matrix containing the eigenvalues of the matrix,
+// and P is a unitary matrix of the eigenvectors of A. Factorize computes the
+// eigenvalues in ascending order. If the vectors input argument is false, the
+// eigenvectors are not computed.
+//
+// Factorize returns whether the decomposition succeeded. If the decomposition
+// failed, methods that require a successful factorization will panic.
+func (e *CEigenHerm) Factorize(a CMatrix, vectors bool) (ok bool) {
+	// kill previous decomposition
+	e.vectorsComputed = false
+	e.values = nil
+	e.vectors = nil
+
+	n, c := a.Dims()
+	if n != c {
+		panic(ErrSquare)
+	}
+	h := NewCDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		h.set(i, i, complex(real(a.At(i, i)), 0))
+		for j := i + 1; j < n; j++ {
+			v := a.At(i, j)
+			h.set(i, j, v)
+			h.set(j, i, cmplx.Conj(v))
+		}
+	}
+
+	// Reduce A to tridiagonal form Qᴴ*A*Q, and then scale the off-diagonal
+	// elements with the unitary diagonal matrix D so that T = Dᴴ*Qᴴ*A*Q*D
+	// is real.
+	var q *CDense
+	if vectors {
+		q = NewCDense(n, n, nil)
+	}
+	cHessenberg(h, q)
+	t := NewSymDense(n, nil)
+	d := make([]complex128, n)
+	if n > 0 {
+		d[0] = 1
+	}
+	for k := 0; k < n; k++ {
+		t.SetSym(k, k, real(h.at(k, k)))
+		if k == n-1 {
+			break
+		}
+		sub := h.at(k+1, k)
+		abs := cmplx.Abs(sub)
+		d[k+1] = d[k]
+		if abs != 0 {
+			d[k+1] *= sub / complex(abs, 0)
+		}
+		t.SetSym(k+1, k, abs)
+	}
+
+	var es EigenSym
+	if !es.Factorize(t, vectors) {
+		return false
+	}
+	e.values = es.values
+	if vectors {
+		// P = Q*D*Z where Z holds the real eigenvectors of T.
+		var z Dense
+		es.VectorsTo(&z)
+		qd := q
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				qd.set(i, j, qd.at(i, j)*d[j])
+			}
+		}
+		e.vectors = NewCDense(n, n, nil)
+		e.vectors.Mul(qd, realToC(&z))
+		e.vectorsComputed = true
+	}
+	return true
+}
+
+// realToC returns a copy of the real matrix a as a complex matrix.
+func realToC(a Matrix) *CDense {
+	r, c := a.Dims()
+	m := NewCDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			m.set(i, j, complex(a.At(i, j), 0))
+		}
+	}
+	return m
+}
+
+// succFact returns whether the receiver contains a successful factorization.
+func (e *CEigenHerm) succFact() bool {
+	return len(e.values) != 0
+}
+
+// Values extracts the eigenvalues of the factorized matrix. If dst is
+// non-nil, the values are stored in-place into dst. In this case
+// dst must have length n, otherwise Values will panic. If dst is
+// nil, then a new slice will be allocated of the proper length and filled
+// with the eigenvalues.
+//
+// Values panics if the Eigen decomposition was not successful.
+func (e *CEigenHerm) Values(dst []float64) []float64 {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if dst == nil {
+		dst = make([]float64, len(e.values))
+	}
+	if len(dst) != len(e.values) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(dst, e.values)
+	return dst
+}
+
+// VectorsTo stores the orthonormal eigenvectors of the decomposition into the
+// columns of dst.
+//
+// If dst is empty, VectorsTo will resize dst to be n×n. When dst is
+// non-empty, VectorsTo will panic if dst is not n×n. VectorsTo will also
+// panic if the eigenvectors were not computed during the factorization,
+// or if the receiver does not contain a successful factorization.
+func (e *CEigenHerm) VectorsTo(dst *CDense) {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if !e.vectorsComputed {
+		panic(noVectors)
+	}
+	r, c := e.vectors.Dims()
+	if dst.IsEmpty() {
+		dst.ReuseAs(r, c)
+	} else {
+		r2, c2 := dst.Dims()
+		if r != r2 || c != c2 {
+			panic(ErrShape)
+		}
+	}
+	dst.Copy(e.vectors)
+}
+
+// CEigen is a type for creating and using the eigenvalue decomposition of a
+// complex dense matrix.
+type CEigen struct {
+	n int // The size of the factorized matrix.
+
+	kind EigenKind
+
+	values   []complex128
+	rVectors *CDense
+	lVectors *CDense
+}
+
+// succFact returns whether the receiver contains a successful factorization.
+func (e *CEigen) succFact() bool {
+	return e.n != 0
+}
+
+// Factorize computes the eigenvalues of the square complex matrix a, and
+// optionally the eigenvectors.
+//
+// A right eigenvalue/eigenvector combination is defined by
+//  A * x_r = λ * x_r
+// where x_r is the column vector called an eigenvector, and λ is the corresponding
+// eigenvalue.
+//
+// Similarly, a left eigenvalue/eigenvector combination is defined by
+//  x_lᴴ * A = λ * x_lᴴ
+// The eigenvalues, but not the eigenvectors, are the same for both decompositions.
+//
+// The eigenvalues are computed from the Schur factorization of a, obtained by
+// reduction to upper Hessenberg form followed by the shifted QR algorithm.
+// kind specifies which of the eigenvectors, if any, to compute. See the
+// EigenKind documentation for more information.
+// Factorize panics if the input matrix is not square.
+//
+// Factorize returns whether the decomposition succeeded. If the decomposition
+// failed, methods that require a successful factorization will panic.
+func (e *CEigen) Factorize(a CMatrix, kind EigenKind) (ok bool) {
+	// kill previous factorization.
+	e.n = 0
+	e.kind = 0
+	e.values = nil
+	e.rVectors = nil
+	e.lVectors = nil
+
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrShape)
+	}
+	n := r
+	t := NewCDense(n, n, nil)
+	t.Copy(a)
+
+	left := kind&EigenLeft != 0
+	right := kind&EigenRight != 0
+	var z *CDense
+	if left || right {
+		z = NewCDense(n, n, nil)
+	}
+	cHessenberg(t, z)
+	if !cSchur(t, z) {
+		return false
+	}
+	e.n = n
+	e.kind = kind
+
+	values := make([]complex128, n)
+	for i := range values {
+		values[i] = t.at(i, i)
+	}
+	e.values = values
+
+	if right {
+		e.rVectors = cSchurVectors(t, z, false)
+	}
+	if left {
+		e.lVectors = cSchurVectors(t, z, true)
+	}
+	return true
+}
+
+// Kind returns the EigenKind of the decomposition. If no decomposition has been
+// computed, Kind returns -1.
+func (e *CEigen) Kind() EigenKind {
+	if !e.succFact() {
+		return -1
+	}
+	return e.kind
+}
+
+// Values extracts the eigenvalues of the factorized matrix. If dst is
+// non-nil, the values are stored in-place into dst. In this case
+// dst must have length n, otherwise Values will panic. If dst is
+// nil, then a new slice will be allocated of the proper length and
+// filled with the eigenvalues.
+//
+// Values panics if the Eigen decomposition was not successful.
+func (e *CEigen) Values(dst []complex128) []complex128 {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if dst == nil {
+		dst = make([]complex128, e.n)
+	}
+	if len(dst) != e.n {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(dst, e.values)
+	return dst
+}
+
+// VectorsTo stores the right eigenvectors of the decomposition into the columns
+// of dst. The computed eigenvectors are normalized to have Euclidean norm equal
+// to 1 and largest component real.
+//
+// If dst is empty, VectorsTo will resize dst to be n×n. When dst is
+// non-empty, VectorsTo will panic if dst is not n×n. VectorsTo will also
+// panic if the eigenvectors were not computed during the factorization,
+// or if the receiver does not contain a successful factorization.
+func (e *CEigen) VectorsTo(dst *CDense) {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if e.kind&EigenRight == 0 {
+		panic(noVectors)
+	}
+	if dst.IsEmpty() {
+		dst.ReuseAs(e.n, e.n)
+	} else {
+		r, c := dst.Dims()
+		if r != e.n || c != e.n {
+			panic(ErrShape)
+		}
+	}
+	dst.Copy(e.rVectors)
+}
+
+// LeftVectorsTo stores the left eigenvectors of the decomposition into the
+// columns of dst. The computed eigenvectors are normalized to have Euclidean
+// norm equal to 1 and largest component real.
+//
+// If dst is empty, LeftVectorsTo will resize dst to be n×n. When dst is
+// non-empty, LeftVectorsTo will panic if dst is not n×n. LeftVectorsTo will also
+// panic if the left eigenvectors were not computed during the factorization,
+// or if the receiver does not contain a successful factorization.
+func (e *CEigen) LeftVectorsTo(dst *CDense) {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if e.kind&EigenLeft == 0 {
+		panic(noVectors)
+	}
+	if dst.IsEmpty() {
+		dst.ReuseAs(e.n, e.n)
+	} else {
+		r, c := dst.Dims()
+		if r != e.n || c != e.n {
+			panic(ErrShape)
+		}
+	}
+	dst.Copy(e.lVectors)
+}
+
+// cHessenberg reduces the n×n matrix a in place to the upper Hessenberg form
+//  H = Qᴴ * A * Q
+// using Householder reflections. If q is not nil, it must be n×n and it is
+// overwritten by the unitary matrix Q. If a is Hermitian, H is tridiagonal.
+func cHessenberg(a, q *CDense) {
+	n := a.mat.Rows
+	if q != nil {
+		q.Zero()
+		for i := 0; i < n; i++ {
+			q.set(i, i, 1)
+		}
+	}
+	data, stride := a.mat.Data, a.mat.Stride
+	v := make([]complex128, n)
+	for k := 0; k < n-2; k++ {
+		beta, tau := cHouseholder(data[(k+1)*stride+k], data[(k+2)*stride+k:], n-k-2, stride)
+		if tau == 0 {
+			continue
+		}
+		// v holds the Householder vector in its elements k+1 to n-1.
+		v[k+1] = 1
+		for i := k + 2; i < n; i++ {
+			v[i] = data[i*stride+k]
+			data[i*stride+k] = 0
+		}
+		data[(k+1)*stride+k] = beta
+		ctau := complex(tau, 0)
+
+		// Apply H = I - tau*v*vᴴ from the left to the trailing columns.
+		for j := k + 1; j < n; j++ {
+			var w complex128
+			for i := k + 1; i < n; i++ {
+				w += cmplx.Conj(v[i]) * data[i*stride+j]
+			}
+			w *= ctau
+			for i := k + 1; i < n; i++ {
+				data[i*stride+j] -= v[i] * w
+			}
+		}
+		// Apply H from the right to all rows of A and of Q.
+		applyRight := func(d []complex128, stride, rows int) {
+			for i := 0; i < rows; i++ {
+				row := d[i*stride : i*stride+n]
+				var w complex128
+				for j := k + 1; j < n; j++ {
+					w += row[j] * v[j]
+				}
+				w *= ctau
+				for j := k + 1; j < n; j++ {
+					row[j] -= w * cmplx.Conj(v[j])
+				}
+			}
+		}
+		applyRight(data, stride, n)
+		if q != nil {
+			applyRight(q.mat.Data, q.mat.Stride, n)
+		}
+	}
+}
+
+// cabs1 returns |real(v)| + |imag(v)|.
+func cabs1(v complex128) float64 {
+	return math.Abs(real(v)) + math.Abs(imag(v))
+}
+
+// cGivens returns the complex plane rotation
+//  G = [ c         s ]
+//      [ -conj(s)  c ]
+// with real c such that G * [f; g] = [r; 0].
+func cGivens(f, g complex128) (c float64, s, r complex128) {
+	if g == 0 {
+		return 1, 0, f
+	}
+	if f == 0 {
+		absG := cmplx.Abs(g)
+		return 0, cmplx.Conj(g) / complex(absG, 0), complex(absG, 0)
+	}
+	absF := cmplx.Abs(f)
+	norm := math.Hypot(absF, cmplx.Abs(g))
+	phase := f / complex(absF, 0)
+	c = absF / norm
+	s = phase * cmplx.Conj(g) / complex(norm, 0)
+	r = phase * complex(norm, 0)
+	return c, s, r
+}
+
+// cSchur computes the Schur factorization
+//  T = Zᴴ * H * Z
+// of the n×n upper Hessenberg matrix h in place using the single-shift QR
+// algorithm, so that on return h holds the upper triangular matrix T. If z is
+// not nil, it is post-multiplied by Z. cSchur returns whether the iteration
+// converged.
+func cSchur(h, z *CDense) bool {
+	const (
+		ulp   = dlamchP
+		small = dlamchS
+		// Number of iterations after which an exceptional shift is used.
+		exceptional = 10
+	)
+	n := h.mat.Rows
+	data, stride := h.mat.Data, h.mat.Stride
+	at := func(i, j int) complex128 { return data[i*stride+j] }
+
+	// rotate applies the rotation G to rows k and k+1 and Gᴴ to
+	// columns k and k+1 of H and of Z.
+	rotate := func(k, colStart, rowEnd int, c float64, s complex128) {
+		cc := complex(c, 0)
+		for j := colStart; j < n; j++ {
+			x, y := data[k*stride+j], data[(k+1)*stride+j]
+			data[k*stride+j] = cc*x + s*y
+			data[(k+1)*stride+j] = -cmplx.Conj(s)*x + cc*y
+		}
+		for i := 0; i <= rowEnd; i++ {
+			x, y := data[i*stride+k], data[i*stride+k+1]
+			data[i*stride+k] = cc*x + cmplx.Conj(s)*y
+			data[i*stride+k+1] = -s*x + cc*y
+		}
+		if z != nil {
+			zd, zs := z.mat.Data, z.mat.Stride
+			for i := 0; i < n; i++ {
+				x, y := zd[i*zs+k], zd[i*zs+k+1]
+				zd[i*zs+k] = cc*x + cmplx.Conj(s)*y
+				zd[i*zs+k+1] = -s*x + cc*y
+			}
+		}
+	}
+
+	maxIter := 30 * max(10, n)
+	its := 0
+	for hi := n - 1; hi > 0; {
+		// Look for a negligible subdiagonal element.
+		lo := 0
+		for k := hi; k > 0; k-- {
+			sub := cabs1(at(k, k-1))
+			if sub <= small {
+				data[k*stride+k-1] = 0
+				lo = k
+				break
+			}
+			tst := cabs1(at(k-1, k-1)) + cabs1(at(k, k))
+			if tst == 0 {
+				for i := max(0, k-2); i <= min(n-1, k+1); i++ {
+					tst += cabs1(at(i, k))
+				}
+			}
+			if sub <= ulp*tst {
+				data[k*stride+k-1] = 0
+				lo = k
+				break
+			}
+		}
+		if lo == hi {
+			// A 1×1 block has converged.
+			hi--
+			its = 0
+			continue
+		}
+		if maxIter == 0 {
+			return false
+		}
+		maxIter--
+		its++
+
+		// Choose the shift.
+		var mu complex128
+		if its%exceptional == 0 {
+			mu = at(hi, hi) + complex(0.75*cabs1(at(hi, hi-1)), 0)
+		} else {
+			// The Wilkinson shift is the eigenvalue of the trailing
+			// 2×2 block closer to its last diagonal element.
+			a, b := at(hi-1, hi-1), at(hi-1, hi)
+			c, d := at(hi, hi-1), at(hi, hi)
+			t := (a - d) / 2
+			disc := cmplx.Sqrt(t*t + b*c)
+			if cabs1(t-disc) < cabs1(t+disc) {
+				disc = -disc
+			}
+			mu = d + t - disc
+		}
+
+		// Chase the bulge down the active block.
+		x, y := at(lo, lo)-mu, at(lo+1, lo)
+		for k := lo; k < hi; k++ {
+			if k > lo {
+				x, y = at(k, k-1), at(k+1, k-1)
+			}
+			c, s, _ := cGivens(x, y)
+			rotate(k, max(lo, k-1), min(k+2, hi), c, s)
+			if k > lo {
+				data[(k+1)*stride+k-1] = 0
+			}
+		}
+	}
+	return true
+}
+
+// cSchurVectors returns the eigenvectors of the matrix Z*T*Zᴴ where T is the
+// n×n upper triangular matrix t and Z is unitary. If left is false the right
+// eigenvectors are returned, otherwise the left eigenvectors are returned. The
+// eigenvectors are normalized to have Euclidean norm equal to 1 and largest
+// component real.
+func cSchurVectors(t, z *CDense, left bool) *CDense {
+	n := t.mat.Rows
+	var tnorm float64
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			tnorm = math.Max(tnorm, cabs1(t.at(i, j)))
+		}
+	}
+	smin := math.Max(dlamchP*tnorm, dlamchS)
+
+	x := NewCDense(n, n, nil)
+	col := make([]complex128, n)
+	for k := 0; k < n; k++ {
+		lambda := t.at(k, k)
+		for i := range col {
+			col[i] = 0
+		}
+		col[k] = 1
+		if !left {
+			// Solve (T - λI)*x = 0 with x[k] = 1 and x[i] = 0 for i > k.
+			for i := k - 1; i >= 0; i-- {
+				sum := t.at(i, k)
+				for j := i + 1; j < k; j++ {
+					sum += t.at(i, j) * col[j]
+				}
+				d := t.at(i, i) - lambda
+				if cabs1(d) < smin {
+					d = complex(smin, 0)
+				}
+				col[i] = -sum / d
+			}
+		} else {
+			// Solve (Tᴴ - conj(λ)I)*y = 0 with y[k] = 1 and y[i] = 0
+			// for i < k.
+			for j := k + 1; j < n; j++ {
+				var sum complex128
+				for i := k; i < j; i++ {
+					sum += cmplx.Conj(t.at(i, j)) * col[i]
+				}
+				d := cmplx.Conj(t.at(j, j) - lambda)
+				if cabs1(d) < smin {
+					d = complex(smin, 0)
+				}
+				col[j] = -sum / d
+			}
+		}
+		for i := 0; i < n; i++ {
+			x.set(i, k, col[i])
+		}
+	}
+	var v CDense
+	v.Mul(z, x)
+
+	cNormalizeColumns(&v)
+	return &v
+}
+
+// cNormalizeColumns scales the columns of v to have Euclidean norm equal to 1
+// and largest component real. Zero columns are left unchanged.
+func cNormalizeColumns(v *CDense) {
+	r, c := v.Dims()
+	for j := 0; j < c; j++ {
+		var norm, big float64
+		imax := 0
+		for i := 0; i < r; i++ {
+			a := sqAbs(v.at(i, j))
+			norm += a
+			if a > big {
+				big = a
+				imax = i
+			}
+		}
+		if norm == 0 {
+			continue
+		}
+		scale := cmplx.Conj(v.at(imax, j)) / complex(math.Sqrt(big)*math.Sqrt(norm), 0)
+		for i := 0; i < r; i++ {
+			v.set(i, j, v.at(i, j)*scale)
+		}
+	}
+}
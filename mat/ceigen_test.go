@@ -0,0 +1,166 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestCEigenHerm(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 5, 10, 30} {
+		// Only the upper triangle of a is used.
+		a := randCDense(n, n, rnd)
+		herm := NewCDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			herm.set(i, i, complex(real(a.at(i, i)), 0))
+			for j := i + 1; j < n; j++ {
+				herm.set(i, j, a.at(i, j))
+				herm.set(j, i, cmplx.Conj(a.at(i, j)))
+			}
+		}
+
+		var es CEigenHerm
+		if !es.Factorize(a, true) {
+			t.Fatalf("n=%d: factorization failed", n)
+		}
+		values := es.Values(nil)
+		if !sort.Float64sAreSorted(values) {
+			t.Errorf("n=%d: eigenvalues not in ascending order: %v", n, values)
+		}
+		var p CDense
+		es.VectorsTo(&p)
+
+		var php CDense
+		php.Mul(p.H(), &p)
+		eye := NewCDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			eye.set(i, i, 1)
+		}
+		if !CEqualApprox(&php, eye, 1e-13) {
+			t.Errorf("n=%d: eigenvectors not orthonormal", n)
+		}
+		var ap, pd CDense
+		ap.Mul(herm, &p)
+		pd.Scale(1, &p)
+		for j := 0; j < n; j++ {
+			for i := 0; i < n; i++ {
+				pd.set(i, j, pd.at(i, j)*complex(values[j], 0))
+			}
+		}
+		if !CEqualApprox(&ap, &pd, 1e-12) {
+			t.Errorf("n=%d: A*P != P*D", n)
+		}
+
+		var noVec CEigenHerm
+		noVec.Factorize(a, false)
+		got := noVec.Values(nil)
+		for i := range got {
+			if math.Abs(got[i]-values[i]) > 1e-12*math.Max(1, math.Abs(values[i])) {
+				t.Errorf("n=%d: eigenvalues differ without vectors: got %v, want %v", n, got[i], values[i])
+			}
+		}
+		if ok, _ := panics(func() { noVec.VectorsTo(&CDense{}) }); !ok {
+			t.Errorf("n=%d: expected panic for eigenvectors not computed", n)
+		}
+	}
+}
+
+func TestCEigen(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 5, 10, 30} {
+		a := randCDense(n, n, rnd)
+
+		var e CEigen
+		if !e.Factorize(a, EigenBoth) {
+			t.Fatalf("n=%d: factorization failed", n)
+		}
+		if e.Kind() != EigenBoth {
+			t.Errorf("n=%d: unexpected kind: %v", n, e.Kind())
+		}
+		values := e.Values(nil)
+		var vr, vl CDense
+		e.VectorsTo(&vr)
+		e.LeftVectorsTo(&vl)
+
+		// Check A*x_r = λ*x_r and x_lᴴ*A = λ*x_lᴴ.
+		var ar, la CDense
+		ar.Mul(a, &vr)
+		la.Mul(vl.H(), a)
+		for j := 0; j < n; j++ {
+			var norm, normL float64
+			for i := 0; i < n; i++ {
+				norm += sqAbs(vr.at(i, j))
+				normL += sqAbs(vl.at(i, j))
+				if d := cmplx.Abs(ar.at(i, j) - values[j]*vr.at(i, j)); d > 1e-10 {
+					t.Errorf("n=%d: right eigenpair %d: residual %v", n, j, d)
+					break
+				}
+				if d := cmplx.Abs(la.at(j, i) - values[j]*cmplx.Conj(vl.at(i, j))); d > 1e-10 {
+					t.Errorf("n=%d: left eigenpair %d: residual %v", n, j, d)
+					break
+				}
+			}
+			if math.Abs(norm-1) > 1e-14 || math.Abs(normL-1) > 1e-14 {
+				t.Errorf("n=%d: eigenvectors %d not normalized", n, j)
+			}
+		}
+
+		// The eigenvalues are invariant under similarity, and their sum
+		// is the trace.
+		var trace, sum complex128
+		for i := 0; i < n; i++ {
+			trace += a.at(i, i)
+			sum += values[i]
+		}
+		if cmplx.Abs(trace-sum) > 1e-10*math.Max(1, cmplx.Abs(trace)) {
+			t.Errorf("n=%d: sum of eigenvalues %v does not match trace %v", n, sum, trace)
+		}
+	}
+
+	// A real matrix has the same eigenvalues as computed by Eigen.
+	d := NewDense(4, 4, []float64{
+		0, 1, 0, 0,
+		-1, 0, 0, 0,
+		0, 0, 2, 1,
+		0, 0, 0, 3,
+	})
+	var re Eigen
+	re.Factorize(d, EigenNone)
+	want := re.Values(nil)
+	var e CEigen
+	e.Factorize(realToC(d), EigenNone)
+	got := e.Values(nil)
+	less := func(v []complex128) func(i, j int) bool {
+		return func(i, j int) bool {
+			if real(v[i]) != real(v[j]) {
+				return real(v[i]) < real(v[j])
+			}
+			return imag(v[i]) < imag(v[j])
+		}
+	}
+	for i := range got {
+		got[i] = complex(math.Round(real(got[i])*1e10)/1e10, math.Round(imag(got[i])*1e10)/1e10)
+		want[i] = complex(math.Round(real(want[i])*1e10)/1e10, math.Round(imag(want[i])*1e10)/1e10)
+	}
+	sort.Slice(got, less(got))
+	sort.Slice(want, less(want))
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("unexpected eigenvalues: got %v, want %v", got, want)
+			break
+		}
+	}
+	if ok, _ := panics(func() { e.VectorsTo(&CDense{}) }); !ok {
+		t.Errorf("expected panic for eigenvectors not computed")
+	}
+}
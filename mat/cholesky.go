@@ -5,6 +5,7 @@
 package mat
 
 import (
+	"errors"
 	"math"
 
 	"gonum.org/v1/gonum/blas"
@@ -120,6 +121,30 @@ func (c *Cholesky) Cond() float64 {
 	return c.cond
 }
 
+// CondEst returns an estimate of the condition number of the factorized
+// matrix in the 1-norm if norm is 1, or in the ∞-norm if norm is math.Inf(1),
+// which are equal for a symmetric matrix. See LU.CondEst for details of the
+// estimation.
+//
+// CondEst will panic if norm is not 1 or math.Inf(1), or if the receiver does
+// not contain a successful factorization.
+func (c *Cholesky) CondEst(norm float64) float64 {
+	if !c.valid() {
+		panic(badCholesky)
+	}
+	n := c.chol.mat.N
+	// A = Uᵀ*U is symmetric, so trans is ignored.
+	mul := func(x []float64, _ bool) {
+		v := blas64.Vector{N: n, Inc: 1, Data: x}
+		blas64.Trmv(blas.NoTrans, c.chol.mat, v)
+		blas64.Trmv(blas.Trans, c.chol.mat, v)
+	}
+	solve := func(x []float64, _ bool) {
+		lapack64.Potrs(c.chol.mat, blas64.General{Rows: n, Cols: 1, Stride: 1, Data: x})
+	}
+	return condEst(n, norm, -1, mul, solve)
+}
+
 // Factorize calculates the Cholesky decomposition of the matrix A and returns
 // whether the matrix is positive definite. If Factorize returns false, the
 // factorization must not be used.
@@ -128,7 +153,8 @@ func (c *Cholesky) Factorize(a Symmetric) (ok bool) {
 	if c.chol == nil {
 		c.chol = NewTriDense(n, Upper, nil)
 	} else {
-		c.chol = NewTriDense(n, Upper, use(c.chol.mat.Data, n*n))
+		c.chol.Reset()
+		c.chol.reuseAsNonZeroed(n, Upper)
 	}
 	copySymIntoTriangle(c.chol, a)
 
@@ -584,15 +610,13 @@ func (c *Cholesky) SymRankOne(orig *Cholesky, alpha float64, x Vector) (ok bool)
 
 	work := getFloats(n, false)
 	defer putFloats(work)
-	var xmat blas64.Vector
 	if rv, ok := x.(RawVectorer); ok {
-		xmat = rv.RawVector()
+		blas64.Copy(rv.RawVector(), blas64.Vector{N: n, Data: work, Inc: 1})
 	} else {
-		var tmp *VecDense
-		tmp.CopyVec(x)
-		xmat = tmp.RawVector()
+		for i := range work {
+			work[i] = x.AtVec(i)
+		}
 	}
-	blas64.Copy(xmat, blas64.Vector{N: n, Data: work, Inc: 1})
 
 	if alpha > 0 {
 		// Compute rank-1 update.
@@ -695,6 +719,68 @@ func (c *Cholesky) SymRankOne(orig *Cholesky, alpha float64, x Vector) (ok bool)
 	return ok
 }
 
+// Update performs a rank-1 update of the factorized matrix A in place, so that
+// the receiver holds the Cholesky factorization of
+//  A + x * xᵀ.
+// The update is always positive definite and is computed in O(n²) time.
+// Update will panic if the receiver does not contain a factorization or if the
+// length of x does not match the size of A.
+func (c *Cholesky) Update(x Vector) {
+	c.SymRankOne(c, 1, x)
+}
+
+// Downdate performs a rank-1 downdate of the factorized matrix A in place, so
+// that the receiver holds the Cholesky factorization of
+//  A - x * xᵀ.
+// Downdate returns whether the downdated matrix is positive definite. If it is
+// not, the receiver is left unchanged. The downdate is computed in O(n²) time.
+// Downdate will panic if the receiver does not contain a factorization or if
+// the length of x does not match the size of A.
+func (c *Cholesky) Downdate(x Vector) (ok bool) {
+	if !c.valid() {
+		panic(badCholesky)
+	}
+	var tmp Cholesky
+	if !tmp.SymRankOne(c, -1, x) {
+		return false
+	}
+	c.chol.Copy(tmp.chol)
+	c.cond = tmp.cond
+	return true
+}
+
+// MarshalBinary encodes the Cholesky factor U of the receiver into a binary
+// form and returns the result. See TriDense.MarshalBinary for the on-disk
+// layout. MarshalBinary returns an error if the receiver does not contain a
+// factorization.
+func (c *Cholesky) MarshalBinary() ([]byte, error) {
+	if !c.valid() {
+		return nil, errors.New(badCholesky)
+	}
+	return c.chol.MarshalBinary()
+}
+
+// UnmarshalBinary decodes the binary form of a Cholesky factorization into the
+// receiver. It panics if the receiver contains a factorization. As with
+// SetFromU, the condition number is estimated from the decoded factor and may
+// differ from that of the original factorization. See TriDense.UnmarshalBinary
+// for the checks performed on the input.
+func (c *Cholesky) UnmarshalBinary(data []byte) error {
+	if !c.IsEmpty() {
+		panic("mat: unmarshal into non-empty factorization")
+	}
+	var u TriDense
+	err := u.UnmarshalBinary(data)
+	if err != nil {
+		return err
+	}
+	if !u.isUpper() {
+		return errWrongType
+	}
+	c.SetFromU(&u)
+	return nil
+}
+
 func (c *Cholesky) valid() bool {
 	return c.chol != nil && !c.chol.IsEmpty()
 }
@@ -502,6 +502,60 @@ func TestCholeskySymRankOne(t *testing.T) {
 	}
 }
 
+func TestCholeskyUpdateDowndate(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 5, 10, 30} {
+		a := NewSymDense(n, nil)
+		for i := 0; i < n; i++ {
+			a.SetSym(i, i, 1)
+		}
+		var chol Cholesky
+		if !chol.Factorize(a) {
+			t.Fatalf("n=%d: bad test, Cholesky factorization failed", n)
+		}
+
+		// Accumulate a sequence of updates and downdates, checking the
+		// factorization against the explicitly modified matrix.
+		xs := make([]*basicVector, 2*n)
+		for k := range xs {
+			x := make([]float64, n)
+			for i := range x {
+				x[i] = rnd.NormFloat64()
+			}
+			xs[k] = &basicVector{m: x}
+			chol.Update(xs[k])
+			a.SymRankOne(a, 1, xs[k])
+		}
+		for _, x := range xs[:n] {
+			if !chol.Downdate(x) {
+				t.Fatalf("n=%d: unexpected failure from Downdate", n)
+			}
+			a.SymRankOne(a, -1, x)
+		}
+		var got SymDense
+		chol.ToSym(&got)
+		if !EqualApprox(&got, a, 1e-10) {
+			t.Errorf("n=%d: mismatch between modified matrix and from Cholesky", n)
+		}
+
+		// A downdate that loses positive definiteness must leave the
+		// factorization unchanged.
+		var before TriDense
+		chol.UTo(&before)
+		x := make([]float64, n)
+		x[n-1] = 1e3
+		if chol.Downdate(NewVecDense(n, x)) {
+			t.Errorf("n=%d: expected failure from Downdate", n)
+		}
+		var after TriDense
+		chol.UTo(&after)
+		if !Equal(&before, &after) {
+			t.Errorf("n=%d: factorization modified by failed Downdate", n)
+		}
+	}
+}
+
 func TestCholeskyExtendVecSym(t *testing.T) {
 	t.Parallel()
 	for cas, test := range []struct {
@@ -0,0 +1,182 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+var (
+	circulant *Circulant
+	_         Matrix = circulant
+)
+
+// Circulant represents an n×n circulant matrix, a Toeplitz matrix in which
+// each column is the cyclic shift of the previous column. A circulant matrix
+// is diagonalized by the discrete Fourier transform, so products with a
+// vector and solutions of linear systems are computed with the FFT in
+// O(n log n) time.
+type Circulant struct {
+	c []float64
+}
+
+// NewCirculant creates a new n×n circulant matrix with the first column c, so
+// that the element [i,j] is c[(i-j) mod n]. If c is nil, a new slice is
+// allocated. Otherwise c must have length n and is used as the backing data
+// of the matrix, so that changes to the elements of c are reflected in the
+// returned Circulant. NewCirculant will panic if n is zero or the length of c
+// is not n.
+func NewCirculant(n int, c []float64) *Circulant {
+	if n <= 0 {
+		if n == 0 {
+			panic(ErrZeroLength)
+		}
+		panic(ErrNegativeDimension)
+	}
+	if c == nil {
+		c = make([]float64, n)
+	}
+	if len(c) != n {
+		panic(ErrShape)
+	}
+	return &Circulant{c: c}
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (c *Circulant) Dims() (r, cols int) {
+	return len(c.c), len(c.c)
+}
+
+// At returns the element at row i, column j.
+func (c *Circulant) At(i, j int) float64 {
+	n := len(c.c)
+	if uint(i) >= uint(n) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(n) {
+		panic(ErrColAccess)
+	}
+	k := i - j
+	if k < 0 {
+		k += n
+	}
+	return c.c[k]
+}
+
+// T performs an implicit transpose by returning the receiver inside a
+// Transpose.
+func (c *Circulant) T() Matrix {
+	return Transpose{c}
+}
+
+// Eigenvalues returns the eigenvalues of the circulant matrix, which are the
+// discrete Fourier coefficients of its first column. The kth eigenvalue
+// corresponds to the eigenvector with elements exp(2πi*j*k/n)/√n. If dst is
+// nil, a new slice is allocated, otherwise dst must have length n.
+func (c *Circulant) Eigenvalues(dst []complex128) []complex128 {
+	n := len(c.c)
+	if dst == nil {
+		dst = make([]complex128, n)
+	}
+	if len(dst) != n {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(dst, fftReal(c.c))
+	return dst
+}
+
+// MulVecTo computes A⋅x or Aᵀ⋅x storing the result into dst.
+func (c *Circulant) MulVecTo(dst *VecDense, trans bool, x Vector) {
+	n := len(c.c)
+	if x.Len() != n {
+		panic(ErrShape)
+	}
+	xv := getWorkspaceVec(n, false)
+	xv.CopyVec(x)
+	defer putWorkspaceVec(xv)
+
+	y := getFloats(n, false)
+	defer putFloats(y)
+	circulantMul(y, c.c, xv.mat.Data, trans)
+	dst.reuseAsNonZeroed(n)
+	for i, v := range y {
+		dst.setVec(i, v)
+	}
+}
+
+// SolveVecTo solves the circulant system of linear equations
+//  A * x = b if trans == false
+//  Aᵀ * x = b if trans == true
+// and stores the result in dst. The system is solved in O(n log n) time by
+// dividing the Fourier coefficients of b by the eigenvalues of A.
+//
+// The 2-norm condition number of a circulant matrix is the ratio of the
+// largest and smallest absolute values of its eigenvalues. If A is singular
+// or near-singular a Condition error is returned. See the documentation for
+// Condition for more information.
+func (c *Circulant) SolveVecTo(dst *VecDense, trans bool, b Vector) error {
+	n := len(c.c)
+	if br, bc := b.Dims(); br != n || bc != 1 {
+		panic(ErrShape)
+	}
+	lambda := fftReal(c.c)
+	lmax, lmin := 0.0, math.Inf(1)
+	for _, v := range lambda {
+		a := cmplx.Abs(v)
+		lmax = math.Max(lmax, a)
+		lmin = math.Min(lmin, a)
+	}
+	// The eigenvalues are computed with an absolute error of about n times
+	// the machine epsilon relative to the largest, so smaller eigenvalues
+	// cannot be distinguished from zero.
+	if lmin <= float64(n)*dlamchE*lmax {
+		return Condition(math.Inf(1))
+	}
+
+	bv := getWorkspaceVec(n, false)
+	bv.CopyVec(b)
+	defer putWorkspaceVec(bv)
+	coeff := fftReal(bv.mat.Data)
+	for k, v := range lambda {
+		if trans {
+			v = cmplx.Conj(v)
+		}
+		coeff[k] /= v * complex(float64(n), 0)
+	}
+	fft(coeff, true)
+	dst.reuseAsNonZeroed(n)
+	for i, v := range coeff {
+		dst.setVec(i, real(v))
+	}
+	cond := lmax / lmin
+	if cond > ConditionTolerance {
+		return Condition(cond)
+	}
+	return nil
+}
+
+// circulantMul computes y = C*x, or y = Cᵀ*x if trans is true, where C is the
+// circulant matrix with first column c, using the FFT. y, c and x must have the
+// same length and y must not overlap c or x.
+func circulantMul(y, c, x []float64, trans bool) {
+	n := len(c)
+	lambda := fftReal(c)
+	coeff := fftReal(x)
+	for k, v := range lambda {
+		// The eigenvalues of the transpose are the conjugates of the
+		// eigenvalues of the real matrix C.
+		if trans {
+			v = cmplx.Conj(v)
+		}
+		coeff[k] *= v
+	}
+	fft(coeff, true)
+	// The transforms are unnormalized.
+	f := 1 / float64(n)
+	for i, v := range coeff {
+		y[i] = real(v) * f
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestCirculant(t *testing.T) {
+	t.Parallel()
+	a := NewCirculant(4, []float64{1, 2, 3, 4})
+	want := NewDense(4, 4, []float64{
+		1, 4, 3, 2,
+		2, 1, 4, 3,
+		3, 2, 1, 4,
+		4, 3, 2, 1,
+	})
+	if !Equal(a, want) {
+		t.Errorf("unexpected circulant matrix:\ngot:\n%v\nwant:\n%v", Formatted(a), Formatted(want))
+	}
+
+	for _, fn := range []func(){
+		func() { NewCirculant(0, nil) },
+		func() { NewCirculant(3, []float64{1, 2}) },
+	} {
+		if ok, _ := panics(fn); !ok {
+			t.Errorf("expected panic")
+		}
+	}
+}
+
+func TestCirculantMulVecSolve(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 5, 8, 63} {
+		c := make([]float64, n)
+		for i := range c {
+			c[i] = rnd.NormFloat64()
+		}
+		c[0] += float64(2 * n)
+		a := NewCirculant(n, c)
+		ad := DenseCopyOf(a)
+
+		// The eigenvalues are those of the dense matrix, with
+		// eigenvectors given by the Fourier modes.
+		lambda := a.Eigenvalues(nil)
+		for k, l := range lambda {
+			v := NewCDense(n, 1, nil)
+			for j := 0; j < n; j++ {
+				v.Set(j, 0, cmplx.Exp(complex(0, 2*math.Pi*float64(j*k)/float64(n))))
+			}
+			var av, lv CDense
+			av.Mul(realToC(ad), v)
+			lv.Scale(l, v)
+			if !CEqualApprox(&av, &lv, 1e-10) {
+				t.Errorf("n=%d: unexpected eigenvalue %d: %v", n, k, l)
+			}
+		}
+
+		for _, trans := range []bool{false, true} {
+			var am Matrix = ad
+			if trans {
+				am = ad.T()
+			}
+			x := randNormal(n, 1, rnd).ColView(0)
+			var got, want VecDense
+			a.MulVecTo(&got, trans, x)
+			want.MulVec(am, x)
+			if !EqualApprox(&got, &want, 1e-12) {
+				t.Errorf("n=%d,trans=%t: unexpected product", n, trans)
+			}
+
+			b := randNormal(n, 1, rnd).ColView(0)
+			err := a.SolveVecTo(&got, trans, b)
+			if err != nil {
+				t.Fatalf("n=%d,trans=%t: unexpected error: %v", n, trans, err)
+			}
+			err = want.SolveVec(am, b)
+			if err != nil {
+				t.Fatalf("n=%d,trans=%t: unexpected error from dense solve: %v", n, trans, err)
+			}
+			if !EqualApprox(&got, &want, 1e-12) {
+				t.Errorf("n=%d,trans=%t: unexpected solution", n, trans)
+			}
+		}
+	}
+
+	// A matrix of ones is singular.
+	a := NewCirculant(3, []float64{1, 1, 1})
+	var x VecDense
+	err := a.SolveVecTo(&x, false, NewVecDense(3, []float64{1, 2, 3}))
+	if _, ok := err.(Condition); !ok {
+		t.Errorf("expected Condition error for singular matrix, got %v", err)
+	}
+}
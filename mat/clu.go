@@ -0,0 +1,312 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+const badCLU = "mat: invalid complex LU factorization"
+
+// CLU is a type for creating and using the LU factorization of a complex
+// matrix.
+type CLU struct {
+	lu    *CDense
+	pivot []int
+	cond  float64
+}
+
+// Factorize computes the LU factorization of the square matrix a and stores
+// the result. The LU decomposition will complete regardless of the singularity
+// of a.
+//
+// The LU factorization is computed with partial pivoting, so that
+//  A = P * L * U
+// where P is a permutation matrix, L is lower triangular with unit diagonal
+// elements and U is upper triangular.
+func (lu *CLU) Factorize(a CMatrix) {
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	if lu.lu == nil {
+		lu.lu = &CDense{}
+	}
+	lu.lu.Reset()
+	lu.lu.reuseAsNonZeroed(r, r)
+	lu.lu.Copy(a)
+	lu.pivot = useInt(lu.pivot, r)
+
+	n := r
+	data, stride := lu.lu.mat.Data, lu.lu.mat.Stride
+	anorm := cMaxRowSum(lu.lu)
+	singular := false
+	for k := 0; k < n; k++ {
+		// Find the pivot.
+		p := k
+		for i := k + 1; i < n; i++ {
+			if cmplx.Abs(data[i*stride+k]) > cmplx.Abs(data[p*stride+k]) {
+				p = i
+			}
+		}
+		lu.pivot[k] = p
+		if p != k {
+			rowK := data[k*stride : k*stride+n]
+			rowP := data[p*stride : p*stride+n]
+			for j := range rowK {
+				rowK[j], rowP[j] = rowP[j], rowK[j]
+			}
+		}
+		pivot := data[k*stride+k]
+		if pivot == 0 {
+			singular = true
+			continue
+		}
+		rowK := data[k*stride+k+1 : k*stride+n]
+		for i := k + 1; i < n; i++ {
+			l := data[i*stride+k] / pivot
+			data[i*stride+k] = l
+			if l == 0 {
+				continue
+			}
+			rowI := data[i*stride+k+1 : i*stride+n]
+			for j, v := range rowK {
+				rowI[j] -= l * v
+			}
+		}
+	}
+	if singular {
+		lu.cond = math.Inf(1)
+		return
+	}
+	// CondNorm is the infinity norm, and the infinity norm of A⁻¹ is the
+	// 1-norm of A⁻ᴴ.
+	x := make([]complex128, n)
+	lu.cond = anorm * cNormEst1(n, func(v []complex128, conjTrans bool) {
+		copy(x, v)
+		lu.solveVec(x, 1, !conjTrans)
+		copy(v, x)
+	})
+}
+
+// solveVec overwrites the vector x with stride inc with the solution of
+// A*x = b, or Aᴴ*x = b if conjTrans is true.
+func (lu *CLU) solveVec(x []complex128, inc int, conjTrans bool) {
+	n := len(lu.pivot)
+	data, stride := lu.lu.mat.Data, lu.lu.mat.Stride
+	if !conjTrans {
+		for k, p := range lu.pivot {
+			if p != k {
+				x[k*inc], x[p*inc] = x[p*inc], x[k*inc]
+			}
+		}
+		// Solve L*y = P*b.
+		for i := 1; i < n; i++ {
+			var sum complex128
+			for j, l := range data[i*stride : i*stride+i] {
+				sum += l * x[j*inc]
+			}
+			x[i*inc] -= sum
+		}
+		// Solve U*x = y.
+		for i := n - 1; i >= 0; i-- {
+			var sum complex128
+			for j := i + 1; j < n; j++ {
+				sum += data[i*stride+j] * x[j*inc]
+			}
+			x[i*inc] = (x[i*inc] - sum) / data[i*stride+i]
+		}
+		return
+	}
+	// Solve Uᴴ*y = b.
+	for i := 0; i < n; i++ {
+		x[i*inc] /= cmplx.Conj(data[i*stride+i])
+		v := x[i*inc]
+		for j := i + 1; j < n; j++ {
+			x[j*inc] -= cmplx.Conj(data[i*stride+j]) * v
+		}
+	}
+	// Solve Lᴴ*z = y.
+	for i := n - 1; i > 0; i-- {
+		v := x[i*inc]
+		for j, l := range data[i*stride : i*stride+i] {
+			x[j*inc] -= cmplx.Conj(l) * v
+		}
+	}
+	// x = P*z.
+	for k := n - 1; k >= 0; k-- {
+		if p := lu.pivot[k]; p != k {
+			x[k*inc], x[p*inc] = x[p*inc], x[k*inc]
+		}
+	}
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (lu *CLU) isValid() bool {
+	return lu.lu != nil && !lu.lu.IsEmpty()
+}
+
+// Cond returns the condition number for the factorized matrix.
+// Cond will panic if the receiver does not contain a factorization.
+func (lu *CLU) Cond() float64 {
+	if !lu.isValid() {
+		panic(badCLU)
+	}
+	return lu.cond
+}
+
+// Reset resets the factorization so that it can be reused as the receiver of a
+// dimensionally restricted operation.
+func (lu *CLU) Reset() {
+	if lu.lu != nil {
+		lu.lu.Reset()
+	}
+	lu.pivot = lu.pivot[:0]
+}
+
+// Det returns the determinant of the matrix that has been factorized. In many
+// expressions, using LogDet will be more numerically stable.
+// Det will panic if the receiver does not contain a factorization.
+func (lu *CLU) Det() complex128 {
+	det, phase := lu.LogDet()
+	return phase * complex(math.Exp(det), 0)
+}
+
+// LogDet returns the log of the absolute value of the determinant and the
+// phase of the determinant, a complex number with unit modulus, for the
+// matrix that has been factorized, so that
+//  det(A) = phase * exp(det).
+// If the determinant is zero, phase is zero. Numerical stability in product
+// and division expressions is generally improved by working in log space.
+// LogDet will panic if the receiver does not contain a factorization.
+func (lu *CLU) LogDet() (det float64, phase complex128) {
+	if !lu.isValid() {
+		panic(badCLU)
+	}
+	phase = 1
+	for i, p := range lu.pivot {
+		if p != i {
+			phase = -phase
+		}
+		v := lu.lu.at(i, i)
+		abs := cmplx.Abs(v)
+		if abs == 0 {
+			return math.Inf(-1), 0
+		}
+		phase *= v / complex(abs, 0)
+		det += math.Log(abs)
+	}
+	return det, phase
+}
+
+// SolveTo solves a system of linear equations using the LU decomposition of a
+// complex matrix. It computes
+//  A * X = B if trans == false
+//  Aᴴ * X = B if trans == true
+// In both cases, A is represented in LU factorized form, and the matrix X is
+// stored into dst.
+//
+// If A is singular or near-singular a Condition error is returned. See
+// the documentation for Condition for more information.
+// SolveTo will panic if the receiver does not contain a factorization.
+func (lu *CLU) SolveTo(dst *CDense, trans bool, b CMatrix) error {
+	if !lu.isValid() {
+		panic(badCLU)
+	}
+	n := len(lu.pivot)
+	br, bc := b.Dims()
+	if br != n {
+		panic(ErrShape)
+	}
+	if math.IsInf(lu.cond, 1) {
+		return Condition(math.Inf(1))
+	}
+	b = dst.isolated(b, true)
+	dst.reuseAsNonZeroed(n, bc)
+	dst.Copy(b)
+	for j := 0; j < bc; j++ {
+		lu.solveVec(dst.mat.Data[j:], dst.mat.Stride, trans)
+	}
+	if lu.cond > ConditionTolerance {
+		return Condition(lu.cond)
+	}
+	return nil
+}
+
+// cMaxRowSum returns the infinity norm of a.
+func cMaxRowSum(a *CDense) float64 {
+	var norm float64
+	for i := 0; i < a.mat.Rows; i++ {
+		var sum float64
+		for _, v := range a.mat.Data[i*a.mat.Stride : i*a.mat.Stride+a.mat.Cols] {
+			sum += cmplx.Abs(v)
+		}
+		norm = math.Max(norm, sum)
+	}
+	return norm
+}
+
+// cNormEst1 returns an estimate of the 1-norm of the n×n complex matrix B,
+// where mulTo(x, false) overwrites x with B*x and mulTo(x, true) overwrites x
+// with Bᴴ*x. It is the complex analogue of normEst1.
+func cNormEst1(n int, mulTo func(x []complex128, conjTrans bool)) float64 {
+	x := make([]complex128, n)
+	for i := range x {
+		x[i] = complex(1/float64(n), 0)
+	}
+	y := make([]complex128, n)
+	var est float64
+	jPrev := -1
+	for iter := 0; iter < 5; iter++ {
+		copy(y, x)
+		mulTo(y, false)
+		var ynorm float64
+		for _, v := range y {
+			ynorm += cmplx.Abs(v)
+		}
+		if iter > 0 && ynorm <= est {
+			break
+		}
+		est = ynorm
+		for i, v := range y {
+			if abs := cmplx.Abs(v); abs != 0 {
+				x[i] = v / complex(abs, 0)
+			} else {
+				x[i] = 1
+			}
+		}
+		mulTo(x, true)
+		j := 0
+		for i, v := range x {
+			if cmplx.Abs(v) > cmplx.Abs(x[j]) {
+				j = i
+			}
+		}
+		if j == jPrev {
+			break
+		}
+		jPrev = j
+		for i := range x {
+			x[i] = 0
+		}
+		x[j] = 1
+	}
+	// Alternative estimate that guards against cancellation.
+	for i := range y {
+		s := 1 + float64(i)/float64(max(n-1, 1))
+		if i%2 == 1 {
+			s = -s
+		}
+		y[i] = complex(s, 0)
+	}
+	mulTo(y, false)
+	var alt float64
+	for _, v := range y {
+		alt += cmplx.Abs(v)
+	}
+	alt *= 2 / (3 * float64(n))
+	return math.Max(est, alt)
+}
@@ -0,0 +1,86 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestCLU(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 5, 10, 30} {
+		a := randCDense(n, n, rnd)
+		var lu CLU
+		lu.Factorize(a)
+
+		for _, trans := range []bool{false, true} {
+			b := randCDense(n, 3, rnd)
+			var x CDense
+			if err := lu.SolveTo(&x, trans, b); err != nil {
+				t.Fatalf("n=%d: unexpected error: %v", n, err)
+			}
+			var got CDense
+			if trans {
+				got.Mul(a.H(), &x)
+			} else {
+				got.Mul(a, &x)
+			}
+			if !CEqualApprox(&got, b, 1e-10) {
+				t.Errorf("n=%d,trans=%t: unexpected solution", n, trans)
+			}
+		}
+
+		// The determinant of a product is the product of the
+		// determinants.
+		b := randCDense(n, n, rnd)
+		var ab CDense
+		ab.Mul(a, b)
+		var luB, luAB CLU
+		luB.Factorize(b)
+		luAB.Factorize(&ab)
+		want := lu.Det() * luB.Det()
+		if got := luAB.Det(); cmplx.Abs(got-want) > 1e-10*cmplx.Abs(want) {
+			t.Errorf("n=%d: unexpected determinant: got %v, want %v", n, got, want)
+		}
+		det, phase := lu.LogDet()
+		if math.Abs(cmplx.Abs(phase)-1) > 1e-14 {
+			t.Errorf("n=%d: phase not of unit modulus: %v", n, phase)
+		}
+		if got := phase * complex(math.Exp(det), 0); cmplx.Abs(got-lu.Det()) > 1e-12*cmplx.Abs(got) {
+			t.Errorf("n=%d: LogDet inconsistent with Det", n)
+		}
+
+		// Compare the condition number estimate with the condition
+		// number computed from the inverse.
+		var inv CDense
+		eye := NewCDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			eye.Set(i, i, 1)
+		}
+		lu.SolveTo(&inv, false, eye)
+		want2 := cMaxRowSum(a) * cMaxRowSum(&inv)
+		if cond := lu.Cond(); cond > want2*(1+1e-10) || cond < want2/10 {
+			t.Errorf("n=%d: poor condition number estimate: got %v, want %v", n, cond, want2)
+		}
+	}
+
+	// A singular matrix.
+	a := NewCDense(2, 2, []complex128{1, 1i, 1i, -1})
+	var lu CLU
+	lu.Factorize(a)
+	if lu.Det() != 0 {
+		t.Errorf("unexpected determinant of singular matrix: %v", lu.Det())
+	}
+	var x CDense
+	err := lu.SolveTo(&x, false, NewCDense(2, 1, []complex128{1, 2}))
+	if _, ok := err.(Condition); !ok {
+		t.Errorf("expected Condition error for singular matrix, got %v", err)
+	}
+}
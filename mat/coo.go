@@ -0,0 +1,160 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+var (
+	coo *COO
+	_   Matrix      = coo
+	_   NonZeroDoer = coo
+)
+
+// COO is a sparse matrix in coordinate format, which holds a list of (i, j, v)
+// triplets. COO is intended for the assembly of sparse matrices element by
+// element, for example from the element contributions of a finite element
+// discretization, and for conversion to the CSR and CSC formats for
+// arithmetic.
+//
+// The value of an element is the sum of the values of all triplets with its
+// indices, so duplicate entries accumulate.
+type COO struct {
+	r, c int
+	rows []int
+	cols []int
+	data []float64
+}
+
+// NewCOO returns a new empty r×c sparse matrix in coordinate format. The
+// triplets are added with Append. NewCOO will panic if either r or c is not
+// positive.
+func NewCOO(r, c int) *COO {
+	if r <= 0 || c <= 0 {
+		if r == 0 || c == 0 {
+			panic(ErrZeroLength)
+		}
+		panic(ErrNegativeDimension)
+	}
+	return &COO{r: r, c: c}
+}
+
+// Append adds the triplet (i, j, v) to the matrix, so that v is added to the
+// value of the element at row i, column j.
+func (m *COO) Append(i, j int, v float64) {
+	if uint(i) >= uint(m.r) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.c) {
+		panic(ErrColAccess)
+	}
+	m.rows = append(m.rows, i)
+	m.cols = append(m.cols, j)
+	m.data = append(m.data, v)
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *COO) Dims() (r, c int) {
+	return m.r, m.c
+}
+
+// At returns the element at row i, column j, which is the sum of the values
+// of the triplets with the indices. At takes time proportional to the number
+// of triplets.
+func (m *COO) At(i, j int) float64 {
+	if uint(i) >= uint(m.r) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.c) {
+		panic(ErrColAccess)
+	}
+	var v float64
+	for k, r := range m.rows {
+		if r == i && m.cols[k] == j {
+			v += m.data[k]
+		}
+	}
+	return v
+}
+
+// T performs an implicit transpose by returning the receiver inside a Transpose.
+func (m *COO) T() Matrix {
+	return Transpose{m}
+}
+
+// Len returns the number of triplets in the matrix, including duplicates.
+func (m *COO) Len() int {
+	return len(m.data)
+}
+
+// DoNonZero calls the function fn for each of the triplets of m in the order
+// that they were appended. Elements with duplicate triplets are visited once
+// for each triplet.
+func (m *COO) DoNonZero(fn func(i, j int, v float64)) {
+	for k, v := range m.data {
+		fn(m.rows[k], m.cols[k], v)
+	}
+}
+
+// ToCSR returns the matrix in compressed sparse row format. The values of
+// duplicate triplets are summed. The returned matrix does not share backing
+// data with the receiver.
+func (m *COO) ToCSR() *CSR {
+	return &CSR{mat: m.compress(m.rows, m.cols, m.r, m.c)}
+}
+
+// ToCSC returns the matrix in compressed sparse column format. The values of
+// duplicate triplets are summed. The returned matrix does not share backing
+// data with the receiver.
+func (m *COO) ToCSC() *CSC {
+	return &CSC{mat: m.compress(m.cols, m.rows, m.c, m.r)}
+}
+
+// compress returns the compressed storage of the triplets with the major
+// indices in major and the minor indices in minor, summing duplicates.
+func (m *COO) compress(major, minor []int, nMajor, nMinor int) compressed {
+	// Bucket the triplets by the minor index, and transpose to order the
+	// minor indices of each major index. Duplicates are then adjacent.
+	t := compressed{
+		major: nMinor,
+		minor: nMajor,
+		ptr:   make([]int, nMinor+1),
+		ind:   make([]int, len(m.data)),
+		data:  make([]float64, len(m.data)),
+	}
+	for _, l := range minor {
+		t.ptr[l+1]++
+	}
+	for l := 0; l < nMinor; l++ {
+		t.ptr[l+1] += t.ptr[l]
+	}
+	next := getInts(nMinor, false)
+	copy(next, t.ptr[:nMinor])
+	for p, l := range minor {
+		q := next[l]
+		t.ind[q] = major[p]
+		t.data[q] = m.data[p]
+		next[l]++
+	}
+	putInts(next)
+	c := t.transpose()
+
+	// Sum the duplicates in place.
+	n := 0
+	for k := 0; k < c.major; k++ {
+		start := n
+		for p := c.ptr[k]; p < c.ptr[k+1]; p++ {
+			if n > start && c.ind[n-1] == c.ind[p] {
+				c.data[n-1] += c.data[p]
+				continue
+			}
+			c.ind[n] = c.ind[p]
+			c.data[n] = c.data[p]
+			n++
+		}
+		c.ptr[k] = start
+	}
+	c.ptr[c.major] = n
+	c.ind = c.ind[:n]
+	c.data = c.data[:n]
+	return *c
+}
@@ -0,0 +1,96 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestCOO(t *testing.T) {
+	t.Parallel()
+	m := NewCOO(3, 4)
+	m.Append(2, 1, 1)
+	m.Append(0, 3, 2)
+	m.Append(2, 1, 3)
+	m.Append(0, 0, 4)
+	m.Append(1, 2, -1)
+	m.Append(1, 2, 1)
+	m.Append(2, 0, 5)
+	want := NewDense(3, 4, []float64{
+		4, 0, 0, 2,
+		0, 0, 0, 0,
+		5, 4, 0, 0,
+	})
+	if !Equal(m, want) {
+		t.Errorf("unexpected COO matrix:\ngot:\n%v\nwant:\n%v", Formatted(m), Formatted(want))
+	}
+	if m.Len() != 7 {
+		t.Errorf("unexpected number of triplets: got %d, want 7", m.Len())
+	}
+
+	csr := m.ToCSR()
+	if !Equal(csr, want) {
+		t.Errorf("unexpected CSR matrix:\ngot:\n%v\nwant:\n%v", Formatted(csr), Formatted(want))
+	}
+	indptr, ind, _ := csr.RawCSR()
+	// The cancelling duplicates of element (1,2) are kept as an explicit zero.
+	if wantPtr := []int{0, 2, 3, 5}; !equalInts(indptr, wantPtr) {
+		t.Errorf("unexpected CSR indptr: got %v, want %v", indptr, wantPtr)
+	}
+	if wantInd := []int{0, 3, 2, 0, 1}; !equalInts(ind, wantInd) {
+		t.Errorf("unexpected CSR indices: got %v, want %v", ind, wantInd)
+	}
+	csc := m.ToCSC()
+	if !Equal(csc, want) {
+		t.Errorf("unexpected CSC matrix:\ngot:\n%v\nwant:\n%v", Formatted(csc), Formatted(want))
+	}
+
+	// The conversions must produce valid matrices.
+	rnd := rand.New(rand.NewSource(1))
+	m = NewCOO(10, 7)
+	d := NewDense(10, 7, nil)
+	for k := 0; k < 100; k++ {
+		i, j := rnd.Intn(10), rnd.Intn(7)
+		v := rnd.NormFloat64()
+		m.Append(i, j, v)
+		d.Set(i, j, d.At(i, j)+v)
+	}
+	csr = m.ToCSR()
+	indptr, ind, data := csr.RawCSR()
+	csr = NewCSR(10, 7, indptr, ind, data)
+	if !EqualApprox(csr, d, 1e-14) {
+		t.Errorf("unexpected CSR matrix from random triplets")
+	}
+	csc = m.ToCSC()
+	indptr, ind, data = csc.RawCSC()
+	csc = NewCSC(10, 7, indptr, ind, data)
+	if !EqualApprox(csc, d, 1e-14) {
+		t.Errorf("unexpected CSC matrix from random triplets")
+	}
+
+	for _, fn := range []func(){
+		func() { NewCOO(0, 1) },
+		func() { m.Append(10, 0, 1) },
+		func() { m.Append(0, -1, 1) },
+	} {
+		if ok, _ := panics(fn); !ok {
+			t.Errorf("expected panic")
+		}
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, v := range a {
+		if v != b[i] {
+			return false
+		}
+	}
+	return true
+}
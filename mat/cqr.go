@@ -0,0 +1,284 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+const badCQR = "mat: invalid complex QR factorization"
+
+// CQR is a type for creating and using the QR factorization of a complex
+// matrix.
+type CQR struct {
+	// qr holds R in its upper triangle and the Householder vectors
+	// without their unit first element below the diagonal.
+	qr   *CDense
+	tau  []float64
+	cond float64
+}
+
+// Factorize computes the QR factorization of an m×n complex matrix a where
+// m >= n. The QR factorization always exists even if A is singular.
+//
+// The QR decomposition is a factorization of the matrix A such that A = Q * R.
+// The matrix Q is a unitary m×m matrix, and R is an m×n upper triangular
+// matrix. Q and R can be extracted using the QTo and RTo methods.
+func (qr *CQR) Factorize(a CMatrix) {
+	m, n := a.Dims()
+	if m < n {
+		panic(ErrShape)
+	}
+	if qr.qr == nil {
+		qr.qr = &CDense{}
+	}
+	qr.qr.Reset()
+	qr.qr.reuseAsNonZeroed(m, n)
+	qr.qr.Copy(a)
+	qr.tau = make([]float64, n)
+
+	data, stride := qr.qr.mat.Data, qr.qr.mat.Stride
+	for k := 0; k < n && k < m-1; k++ {
+		beta, tau := cHouseholder(data[k*stride+k], data[(k+1)*stride+k:], m-k-1, stride)
+		if tau == 0 {
+			continue
+		}
+		qr.tau[k] = tau
+		data[k*stride+k] = beta
+
+		// Apply H to the trailing columns.
+		for j := k + 1; j < n; j++ {
+			w := data[k*stride+j]
+			for i := k + 1; i < m; i++ {
+				w += cmplx.Conj(data[i*stride+k]) * data[i*stride+j]
+			}
+			w *= complex(tau, 0)
+			data[k*stride+j] -= w
+			for i := k + 1; i < m; i++ {
+				data[i*stride+j] -= data[i*stride+k] * w
+			}
+		}
+	}
+	qr.updateCond()
+}
+
+// cHouseholder computes the Householder reflection H = I - tau*v*vᴴ with
+// v[0] = 1 such that
+//  H * [alpha; x] = [beta; 0]
+// where the n elements of x are stored with stride inc. x is overwritten by
+// v[1:]. If x is zero, tau is zero and H is the identity.
+func cHouseholder(alpha complex128, x []complex128, n, inc int) (beta complex128, tau float64) {
+	var tailSq float64
+	for i := 0; i < n; i++ {
+		tailSq += sqAbs(x[i*inc])
+	}
+	if tailSq == 0 {
+		return alpha, 0
+	}
+	xnorm := math.Sqrt(sqAbs(alpha) + tailSq)
+	phase := complex(1, 0)
+	if alpha != 0 {
+		phase = alpha / complex(cmplx.Abs(alpha), 0)
+	}
+	beta = -phase * complex(xnorm, 0)
+	u0 := alpha - beta
+	vnormSq := 1.0
+	for i := 0; i < n; i++ {
+		x[i*inc] /= u0
+		vnormSq += sqAbs(x[i*inc])
+	}
+	return beta, 2 / vnormSq
+}
+
+func sqAbs(v complex128) float64 {
+	return real(v)*real(v) + imag(v)*imag(v)
+}
+
+func (qr *CQR) updateCond() {
+	// As for QR, the condition number of A is approximated by the
+	// condition number of R.
+	n := qr.qr.mat.Cols
+	r := NewCDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			v := qr.qr.at(i, j)
+			if i == j && v == 0 {
+				qr.cond = math.Inf(1)
+				return
+			}
+			r.set(i, j, v)
+		}
+	}
+	qr.cond = cMaxRowSum(r) * cNormEst1(n, func(x []complex128, conjTrans bool) {
+		qr.solveR(x, 1, !conjTrans)
+	})
+}
+
+// solveR overwrites the vector x with stride inc with the solution of R*x = b,
+// or Rᴴ*x = b if conjTrans is true, where R is the leading n×n block of the
+// triangular factor.
+func (qr *CQR) solveR(x []complex128, inc int, conjTrans bool) {
+	n := qr.qr.mat.Cols
+	data, stride := qr.qr.mat.Data, qr.qr.mat.Stride
+	if !conjTrans {
+		for i := n - 1; i >= 0; i-- {
+			var sum complex128
+			for j := i + 1; j < n; j++ {
+				sum += data[i*stride+j] * x[j*inc]
+			}
+			x[i*inc] = (x[i*inc] - sum) / data[i*stride+i]
+		}
+		return
+	}
+	for i := 0; i < n; i++ {
+		x[i*inc] /= cmplx.Conj(data[i*stride+i])
+		v := x[i*inc]
+		for j := i + 1; j < n; j++ {
+			x[j*inc] -= cmplx.Conj(data[i*stride+j]) * v
+		}
+	}
+}
+
+// applyReflector applies the k-th Householder reflection to the columns of
+// the m×c matrix b. The reflections are Hermitian.
+func (qr *CQR) applyReflector(k int, b *CDense) {
+	tau := qr.tau[k]
+	if tau == 0 {
+		return
+	}
+	m := qr.qr.mat.Rows
+	data, stride := qr.qr.mat.Data, qr.qr.mat.Stride
+	bd, bs := b.mat.Data, b.mat.Stride
+	for j := 0; j < b.mat.Cols; j++ {
+		w := bd[k*bs+j]
+		for i := k + 1; i < m; i++ {
+			w += cmplx.Conj(data[i*stride+k]) * bd[i*bs+j]
+		}
+		w *= complex(tau, 0)
+		bd[k*bs+j] -= w
+		for i := k + 1; i < m; i++ {
+			bd[i*bs+j] -= data[i*stride+k] * w
+		}
+	}
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (qr *CQR) isValid() bool {
+	return qr.qr != nil && !qr.qr.IsEmpty()
+}
+
+// Cond returns the condition number for the factorized matrix.
+// Cond will panic if the receiver does not contain a factorization.
+func (qr *CQR) Cond() float64 {
+	if !qr.isValid() {
+		panic(badCQR)
+	}
+	return qr.cond
+}
+
+// RTo extracts the m×n upper trapezoidal matrix from a QR decomposition.
+//
+// If dst is empty, RTo will resize dst to be r×c. When dst is non-empty,
+// RTo will panic if dst is not r×c. RTo will also panic if the receiver
+// does not contain a successful factorization.
+func (qr *CQR) RTo(dst *CDense) {
+	if !qr.isValid() {
+		panic(badCQR)
+	}
+	r, c := qr.qr.Dims()
+	dst.reuseAsZeroed(r, c)
+	for i := 0; i < c; i++ {
+		for j := i; j < c; j++ {
+			dst.set(i, j, qr.qr.at(i, j))
+		}
+	}
+}
+
+// QTo extracts the m×m unitary matrix Q from a QR decomposition.
+//
+// If dst is empty, QTo will resize dst to be m×m. When dst is non-empty,
+// QTo will panic if dst is not m×m. QTo will also panic if the receiver
+// does not contain a successful factorization.
+func (qr *CQR) QTo(dst *CDense) {
+	if !qr.isValid() {
+		panic(badCQR)
+	}
+	m, n := qr.qr.Dims()
+	dst.reuseAsZeroed(m, m)
+	for i := 0; i < m; i++ {
+		dst.set(i, i, 1)
+	}
+	// Q = H_0 * H_1 * ... * H_{n-1}.
+	for k := n - 1; k >= 0; k-- {
+		qr.applyReflector(k, dst)
+	}
+}
+
+// SolveTo finds a minimum-norm solution to a system of linear equations defined
+// by the matrices A and b, where A is an m×n matrix represented in its QR factorized
+// form. If A is singular or near-singular a Condition error is returned.
+// See the documentation for Condition for more information.
+//
+// The minimization problem solved depends on the input parameters.
+//  If trans == false, find X such that ||A*X - B||_2 is minimized.
+//  If trans == true, find the minimum norm solution of Aᴴ * X = B.
+// The solution matrix, X, is stored in place into dst.
+// SolveTo will panic if the receiver does not contain a factorization.
+func (qr *CQR) SolveTo(dst *CDense, trans bool, b CMatrix) error {
+	if !qr.isValid() {
+		panic(badCQR)
+	}
+	r, c := qr.qr.Dims()
+	br, bc := b.Dims()
+
+	// The QR solve algorithm stores the result in-place into the right hand side.
+	// The storage for the answer must be large enough to hold both b and x.
+	// However, this method's receiver must be the size of x. Copy b, and then
+	// copy the result into x at the end.
+	if trans {
+		if c != br {
+			panic(ErrShape)
+		}
+	} else {
+		if r != br {
+			panic(ErrShape)
+		}
+	}
+	if math.IsInf(qr.cond, 1) {
+		return Condition(math.Inf(1))
+	}
+	x := NewCDense(r, bc, nil)
+	for i := 0; i < br; i++ {
+		for j := 0; j < bc; j++ {
+			x.set(i, j, b.At(i, j))
+		}
+	}
+	if trans {
+		// Aᴴ = Rᴴ*Qᴴ, so X = Q*[R⁻ᴴ*B; 0].
+		for j := 0; j < bc; j++ {
+			qr.solveR(x.mat.Data[j:], x.mat.Stride, true)
+		}
+		for k := c - 1; k >= 0; k-- {
+			qr.applyReflector(k, x)
+		}
+		dst.reuseAsNonZeroed(r, bc)
+		dst.Copy(x)
+	} else {
+		// Qᴴ = H_{n-1} * ... * H_0.
+		for k := 0; k < c; k++ {
+			qr.applyReflector(k, x)
+		}
+		for j := 0; j < bc; j++ {
+			qr.solveR(x.mat.Data[j:], x.mat.Stride, false)
+		}
+		dst.reuseAsNonZeroed(c, bc)
+		dst.Copy(x)
+	}
+	if qr.cond > ConditionTolerance {
+		return Condition(qr.cond)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestCQR(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n int
+	}{
+		{1, 1},
+		{3, 3},
+		{5, 3},
+		{10, 4},
+		{20, 20},
+	} {
+		m, n := test.m, test.n
+		a := randCDense(m, n, rnd)
+		var qr CQR
+		qr.Factorize(a)
+
+		var q, r CDense
+		qr.QTo(&q)
+		qr.RTo(&r)
+		for i := 0; i < m; i++ {
+			for j := 0; j < i && j < n; j++ {
+				if r.At(i, j) != 0 {
+					t.Errorf("m=%d,n=%d: R is not upper triangular", m, n)
+				}
+			}
+		}
+		var qhq CDense
+		qhq.Mul(q.H(), &q)
+		eye := NewCDense(m, m, nil)
+		for i := 0; i < m; i++ {
+			eye.Set(i, i, 1)
+		}
+		if !CEqualApprox(&qhq, eye, 1e-13) {
+			t.Errorf("m=%d,n=%d: Q is not unitary", m, n)
+		}
+		var qrProd CDense
+		qrProd.Mul(&q, &r)
+		if !CEqualApprox(&qrProd, a, 1e-13) {
+			t.Errorf("m=%d,n=%d: Q*R != A", m, n)
+		}
+
+		// The least squares residual is orthogonal to the columns of A.
+		b := randCDense(m, 2, rnd)
+		var x CDense
+		if err := qr.SolveTo(&x, false, b); err != nil {
+			t.Fatalf("m=%d,n=%d: unexpected error: %v", m, n, err)
+		}
+		var res CDense
+		res.Mul(a, &x)
+		res.Sub(&res, b)
+		var ortho CDense
+		ortho.Mul(a.H(), &res)
+		if !CEqualApprox(&ortho, NewCDense(n, 2, nil), 1e-12) {
+			t.Errorf("m=%d,n=%d: unexpected least squares solution", m, n)
+		}
+
+		// The minimum norm solution of Aᴴ*X = B is in the range of A.
+		b = randCDense(n, 2, rnd)
+		var xh CDense
+		if err := qr.SolveTo(&xh, true, b); err != nil {
+			t.Fatalf("m=%d,n=%d: unexpected error: %v", m, n, err)
+		}
+		var got CDense
+		got.Mul(a.H(), &xh)
+		if !CEqualApprox(&got, b, 1e-12) {
+			t.Errorf("m=%d,n=%d: unexpected minimum norm solution", m, n)
+		}
+		var y, ay CDense
+		if err := qr.SolveTo(&y, false, &xh); err != nil {
+			t.Fatalf("m=%d,n=%d: unexpected error: %v", m, n, err)
+		}
+		ay.Mul(a, &y)
+		if !CEqualApprox(&ay, &xh, 1e-12) {
+			t.Errorf("m=%d,n=%d: minimum norm solution not in range of A", m, n)
+		}
+	}
+
+	if ok, _ := panics(func() {
+		var qr CQR
+		qr.Factorize(NewCDense(2, 3, nil))
+	}); !ok {
+		t.Errorf("expected panic for wide matrix")
+	}
+}
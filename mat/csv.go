@@ -0,0 +1,183 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CSVOptions specifies the layout of the delimited text read by ReadCSV and
+// written by WriteCSV. The zero value describes comma-separated values without
+// a header line.
+type CSVOptions struct {
+	// Comma is the field delimiter. If Comma is zero, ',' is used.
+	// Tab-separated values are handled with '\t'.
+	Comma rune
+
+	// Comment, if not zero, is the comment character. Lines beginning
+	// with the comment character are ignored when reading.
+	Comment rune
+
+	// Header specifies whether the first record holds the names of the
+	// columns.
+	Header bool
+
+	// Columns holds the indices of the columns to read, in the order in
+	// which they are placed in the returned matrix. If Columns is nil,
+	// all columns are read.
+	Columns []int
+
+	// Missing holds the cell values, other than the empty cell, that
+	// denote a missing value, such as "NA" or "?". Missing cells are read
+	// as NaN.
+	Missing []string
+}
+
+func (o *CSVOptions) comma() rune {
+	if o.Comma == 0 {
+		return ','
+	}
+	return o.Comma
+}
+
+// ReadCSV reads a matrix of delimited text from r, with each record forming a
+// row of the returned matrix. If opts is nil, the zero value of CSVOptions is
+// used. If opts.Header is true, the names of the read columns are returned in
+// header.
+//
+// Fields are parsed with strconv.ParseFloat after leading and trailing space is
+// removed. Empty fields and fields listed in opts.Missing are set to NaN.
+// All records must have the same number of fields. ReadCSV returns
+// ErrZeroLength if no records or no columns are read.
+func ReadCSV(r io.Reader, opts *CSVOptions) (m *Dense, header []string, err error) {
+	if opts == nil {
+		opts = &CSVOptions{}
+	}
+	cr := csv.NewReader(r)
+	cr.Comma = opts.comma()
+	cr.Comment = opts.Comment
+	cr.ReuseRecord = true
+
+	missing := make(map[string]bool, len(opts.Missing)+1)
+	missing[""] = true
+	for _, s := range opts.Missing {
+		missing[s] = true
+	}
+
+	var (
+		columns []int
+		data    []float64
+		rows    int
+	)
+	for line := 1; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if line == 1 {
+			columns = opts.Columns
+			if columns == nil {
+				columns = make([]int, len(record))
+				for j := range columns {
+					columns[j] = j
+				}
+			}
+			for _, j := range columns {
+				if uint(j) >= uint(len(record)) {
+					return nil, nil, fmt.Errorf("mat: CSV column %d out of range", j)
+				}
+			}
+			if opts.Header {
+				header = make([]string, len(columns))
+				for k, j := range columns {
+					header[k] = strings.TrimSpace(record[j])
+				}
+				continue
+			}
+		}
+		for _, j := range columns {
+			f := strings.TrimSpace(record[j])
+			if missing[f] {
+				data = append(data, math.NaN())
+				continue
+			}
+			v, err := strconv.ParseFloat(f, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("mat: invalid CSV value in record %d, column %d: %v", line, j, err)
+			}
+			data = append(data, v)
+		}
+		rows++
+	}
+	if rows == 0 || len(columns) == 0 {
+		return nil, header, ErrZeroLength
+	}
+	return NewDense(rows, len(columns), data), header, nil
+}
+
+// WriteCSV writes the matrix a to w as delimited text, with each row of a
+// written as a record. If opts is nil, the zero value of CSVOptions is used.
+// If opts.Header is true, header is written as the first record and must have
+// one name for each written column. If opts.Columns is not nil, only the
+// listed columns of a are written, in the given order.
+//
+// Values are written with the shortest representation that reads back
+// exactly, except that NaN values are written as empty fields so that they
+// are read as missing by ReadCSV and other CSV readers.
+func WriteCSV(w io.Writer, a Matrix, header []string, opts *CSVOptions) error {
+	if opts == nil {
+		opts = &CSVOptions{}
+	}
+	r, c := a.Dims()
+	columns := opts.Columns
+	if columns == nil {
+		columns = make([]int, c)
+		for j := range columns {
+			columns[j] = j
+		}
+	}
+	for _, j := range columns {
+		if uint(j) >= uint(c) {
+			panic(ErrColAccess)
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Comma = opts.comma()
+	if opts.Header {
+		if len(header) != len(columns) {
+			panic(ErrSliceLengthMismatch)
+		}
+		err := cw.Write(header)
+		if err != nil {
+			return err
+		}
+	}
+	record := make([]string, len(columns))
+	for i := 0; i < r; i++ {
+		for k, j := range columns {
+			v := a.At(i, j)
+			if math.IsNaN(v) {
+				record[k] = ""
+			} else {
+				record[k] = strconv.FormatFloat(v, 'g', -1, 64)
+			}
+		}
+		err := cw.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,126 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadCSV(t *testing.T) {
+	t.Parallel()
+	nan := math.NaN()
+	for _, test := range []struct {
+		name       string
+		csv        string
+		opts       *CSVOptions
+		want       *Dense
+		wantHeader []string
+	}{
+		{
+			name: "default",
+			csv:  "1,2,3\n4, 5 ,6e1\n",
+			want: NewDense(2, 3, []float64{
+				1, 2, 3,
+				4, 5, 60,
+			}),
+		},
+		{
+			name: "header missing",
+			csv:  "x,y,z\n1,,3\nNA,5,?\n",
+			opts: &CSVOptions{Header: true, Missing: []string{"NA", "?"}},
+			want: NewDense(2, 3, []float64{
+				1, nan, 3,
+				nan, 5, nan,
+			}),
+			wantHeader: []string{"x", "y", "z"},
+		},
+		{
+			name: "tsv columns comment",
+			csv:  "# A comment.\na\tb\tc\n1\t2\t3\n# Another.\n4\t5\t6\n",
+			opts: &CSVOptions{Comma: '\t', Comment: '#', Header: true, Columns: []int{2, 0}},
+			want: NewDense(2, 2, []float64{
+				3, 1,
+				6, 4,
+			}),
+			wantHeader: []string{"c", "a"},
+		},
+	} {
+		got, header, err := ReadCSV(strings.NewReader(test.csv), test.opts)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !EqualApprox(got, test.want, 0, NaNEqual()) {
+			t.Errorf("%s: unexpected matrix:\ngot:\n%v\nwant:\n%v", test.name, Formatted(got), Formatted(test.want))
+		}
+		if !reflect.DeepEqual(header, test.wantHeader) {
+			t.Errorf("%s: unexpected header: got:%q want:%q", test.name, header, test.wantHeader)
+		}
+	}
+
+	for _, test := range []struct {
+		csv  string
+		opts *CSVOptions
+	}{
+		{csv: ""},
+		{csv: "a,b\n", opts: &CSVOptions{Header: true}},
+		{csv: "1,2\n3\n"},
+		{csv: "1,x\n"},
+		{csv: "1,2\n", opts: &CSVOptions{Columns: []int{2}}},
+	} {
+		_, _, err := ReadCSV(strings.NewReader(test.csv), test.opts)
+		if err == nil {
+			t.Errorf("expected error for input %q", test.csv)
+		}
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	t.Parallel()
+	a := NewDense(3, 2, []float64{
+		1, 1.0 / 3,
+		math.NaN(), -1e-300,
+		math.Inf(1), 5,
+	})
+	for _, opts := range []*CSVOptions{
+		nil,
+		{Comma: '\t'},
+		{Comma: ';', Header: true},
+	} {
+		var header []string
+		if opts != nil && opts.Header {
+			header = []string{"first", "second"}
+		}
+		var buf bytes.Buffer
+		err := WriteCSV(&buf, a, header, opts)
+		if err != nil {
+			t.Fatalf("unexpected error writing: %v", err)
+		}
+		got, gotHeader, err := ReadCSV(&buf, opts)
+		if err != nil {
+			t.Fatalf("unexpected error reading: %v", err)
+		}
+		if !EqualApprox(got, a, 0, NaNEqual()) {
+			t.Errorf("round trip failed:\ngot:\n%v\nwant:\n%v", Formatted(got), Formatted(a))
+		}
+		if !reflect.DeepEqual(gotHeader, header) {
+			t.Errorf("unexpected header: got:%q want:%q", gotHeader, header)
+		}
+	}
+
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, a, []string{"second"}, &CSVOptions{Header: true, Columns: []int{1}})
+	if err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	want := "second\n0.3333333333333333\n-1e-300\n5\n"
+	if buf.String() != want {
+		t.Errorf("unexpected column output: got:%q want:%q", buf.String(), want)
+	}
+}
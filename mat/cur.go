@@ -0,0 +1,284 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/lapack/lapack64"
+)
+
+const (
+	badColumnID = "mat: invalid interpolative decomposition"
+	badCUR      = "mat: invalid CUR decomposition"
+)
+
+// ColumnID is a type for computing the column interpolative decomposition of a
+// matrix, a low-rank approximation expressed in terms of a subset of the
+// columns of the matrix.
+//
+// The column interpolative decomposition of rank k of an m×n matrix A is
+//  A ≈ C * Z
+// where C is the m×k matrix formed by k columns of A, and Z is a k×n matrix
+// that contains the k×k identity matrix among its columns. Since C holds
+// actual columns of A, the approximation retains the interpretation of the
+// data, such as sparsity or nonnegativity of the columns.
+type ColumnID struct {
+	cols []int
+	z    *Dense
+}
+
+// Factorize computes the column interpolative decomposition of rank k of the
+// m×n matrix a. k must be in [1, min(m,n)], otherwise Factorize will panic.
+//
+// The columns are selected by the QR factorization with column pivoting of a,
+// so that the first k pivot columns are used. The error of the approximation
+// is within a modest factor of the error of the best rank k approximation
+// when the singular values of A beyond the k-th are small. The numerical rank
+// of a, as returned by PivotedQR.Rank, is a suitable choice of k.
+//
+// Factorize returns whether the decomposition succeeded. The decomposition
+// fails if the selected columns are exactly linearly dependent, which happens
+// when k exceeds the rank of a. If the decomposition failed, methods that
+// require a successful factorization will panic.
+func (id *ColumnID) Factorize(a Matrix, k int) (ok bool) {
+	m, n := a.Dims()
+	if k < 1 || min(m, n) < k {
+		panic(badTruncRank)
+	}
+	id.Reset()
+
+	var qr PivotedQR
+	qr.Factorize(a)
+
+	// With A * P = Q * [R11 R12], where R11 is k×k, the first k pivot
+	// columns are C = Q * R11 and
+	//  A * P ≈ C * [I R11⁻¹*R12].
+	z := NewDense(k, n, nil)
+	if k < n {
+		t := DenseCopyOf(qr.qr.slice(0, k, k, n))
+		r11 := qr.qr.slice(0, k, 0, k).asTriDense(k, blas.NonUnit, blas.Upper)
+		if !lapack64.Trtrs(blas.NoTrans, r11.mat, t.mat) {
+			return false
+		}
+		for j := k; j < n; j++ {
+			for i := 0; i < k; i++ {
+				z.set(i, qr.piv[j], t.at(i, j-k))
+			}
+		}
+	}
+	for j := 0; j < k; j++ {
+		if qr.qr.at(j, j) == 0 {
+			return false
+		}
+		z.set(j, qr.piv[j], 1)
+	}
+	id.cols = qr.piv[:k:k]
+	id.z = z
+	return true
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (id *ColumnID) isValid() bool {
+	return id.z != nil
+}
+
+// Reset resets the factorization so that it can be reused as the receiver of a
+// dimensionally restricted operation.
+func (id *ColumnID) Reset() {
+	id.cols = nil
+	id.z = nil
+}
+
+// Rank returns the rank k of the decomposition. Rank will panic if the
+// receiver does not contain a successful factorization.
+func (id *ColumnID) Rank() int {
+	if !id.isValid() {
+		panic(badColumnID)
+	}
+	return len(id.cols)
+}
+
+// Columns returns the indices of the k columns of A that form C, in the order
+// in which they were selected. If dst is nil, a new slice is allocated,
+// otherwise dst must have length k. Columns will panic if the receiver does not
+// contain a successful factorization.
+func (id *ColumnID) Columns(dst []int) []int {
+	if !id.isValid() {
+		panic(badColumnID)
+	}
+	if dst == nil {
+		dst = make([]int, len(id.cols))
+	}
+	if len(dst) != len(id.cols) {
+		panic(badSliceLength)
+	}
+	copy(dst, id.cols)
+	return dst
+}
+
+// InterpTo extracts the k×n interpolation matrix Z, whose column Columns()[j]
+// is the jth column of the identity matrix.
+//
+// If dst is empty, InterpTo will resize dst to be k×n. When dst is non-empty,
+// InterpTo will panic if dst is not k×n. InterpTo will also panic if the
+// receiver does not contain a successful factorization.
+func (id *ColumnID) InterpTo(dst *Dense) {
+	if !id.isValid() {
+		panic(badColumnID)
+	}
+	truncSVDVectorsTo(dst, id.z)
+}
+
+// CUR is a type for computing the CUR decomposition of a matrix, a low-rank
+// approximation expressed in terms of a subset of the columns and a subset of
+// the rows of the matrix.
+//
+// The CUR decomposition of rank k of an m×n matrix A is
+//  A ≈ C * U * R
+// where C is the m×k matrix formed by k columns of A, R is the k×n matrix
+// formed by k rows of A, and U is a k×k matrix.
+type CUR struct {
+	cols, rows []int
+	c, u, r    *Dense
+}
+
+// Factorize computes the CUR decomposition of rank k of the m×n matrix a. k
+// must be in [1, min(m,n)], otherwise Factorize will panic.
+//
+// The columns and rows are selected with the column interpolative
+// decompositions of a and of aᵀ, and U is then chosen as C⁺ * A * R⁺, which
+// minimizes the Frobenius norm of the error for the selected C and R.
+//
+// Factorize returns whether the decomposition succeeded. The decomposition
+// fails if the selected columns or rows are exactly linearly dependent, which
+// happens when k exceeds the rank of a. If the decomposition failed, methods
+// that require a successful factorization will panic.
+func (cur *CUR) Factorize(a Matrix, k int) (ok bool) {
+	m, n := a.Dims()
+	if k < 1 || min(m, n) < k {
+		panic(badTruncRank)
+	}
+	cur.Reset()
+
+	var colID, rowID ColumnID
+	if !colID.Factorize(a, k) || !rowID.Factorize(a.T(), k) {
+		return false
+	}
+	cols := colID.Columns(nil)
+	rows := rowID.Columns(nil)
+	c := DenseCopyOf(NewIndexed(a, nil, cols))
+	r := DenseCopyOf(NewIndexed(a, rows, nil))
+
+	// X = C⁺ * A and U = X * R⁺, computed as Uᵀ = (Rᵀ)⁺ * Xᵀ.
+	var qr PivotedQR
+	var x, ut Dense
+	qr.Factorize(c)
+	if qr.SolveTo(&x, k, a) != nil {
+		return false
+	}
+	qr.Reset()
+	qr.Factorize(r.T())
+	if qr.SolveTo(&ut, k, x.T()) != nil {
+		return false
+	}
+
+	cur.cols = cols
+	cur.rows = rows
+	cur.c = c
+	cur.u = DenseCopyOf(ut.T())
+	cur.r = r
+	return true
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (cur *CUR) isValid() bool {
+	return cur.u != nil
+}
+
+// Reset resets the factorization so that it can be reused as the receiver of a
+// dimensionally restricted operation.
+func (cur *CUR) Reset() {
+	cur.cols = nil
+	cur.rows = nil
+	cur.c = nil
+	cur.u = nil
+	cur.r = nil
+}
+
+// Rank returns the rank k of the decomposition. Rank will panic if the
+// receiver does not contain a successful factorization.
+func (cur *CUR) Rank() int {
+	if !cur.isValid() {
+		panic(badCUR)
+	}
+	return len(cur.cols)
+}
+
+// Columns returns the indices of the k columns of A that form C. If dst is
+// nil, a new slice is allocated, otherwise dst must have length k. Columns will
+// panic if the receiver does not contain a successful factorization.
+func (cur *CUR) Columns(dst []int) []int {
+	if !cur.isValid() {
+		panic(badCUR)
+	}
+	return curIndices(dst, cur.cols)
+}
+
+// Rows returns the indices of the k rows of A that form R. If dst is nil, a
+// new slice is allocated, otherwise dst must have length k. Rows will panic if
+// the receiver does not contain a successful factorization.
+func (cur *CUR) Rows(dst []int) []int {
+	if !cur.isValid() {
+		panic(badCUR)
+	}
+	return curIndices(dst, cur.rows)
+}
+
+func curIndices(dst, idx []int) []int {
+	if dst == nil {
+		dst = make([]int, len(idx))
+	}
+	if len(dst) != len(idx) {
+		panic(badSliceLength)
+	}
+	copy(dst, idx)
+	return dst
+}
+
+// CTo extracts the m×k matrix C of the selected columns of A.
+//
+// If dst is empty, CTo will resize dst to be m×k. When dst is non-empty, CTo
+// will panic if dst is not m×k. CTo will also panic if the receiver does not
+// contain a successful factorization.
+func (cur *CUR) CTo(dst *Dense) {
+	if !cur.isValid() {
+		panic(badCUR)
+	}
+	truncSVDVectorsTo(dst, cur.c)
+}
+
+// UTo extracts the k×k linking matrix U.
+//
+// If dst is empty, UTo will resize dst to be k×k. When dst is non-empty, UTo
+// will panic if dst is not k×k. UTo will also panic if the receiver does not
+// contain a successful factorization.
+func (cur *CUR) UTo(dst *Dense) {
+	if !cur.isValid() {
+		panic(badCUR)
+	}
+	truncSVDVectorsTo(dst, cur.u)
+}
+
+// RTo extracts the k×n matrix R of the selected rows of A.
+//
+// If dst is empty, RTo will resize dst to be k×n. When dst is non-empty, RTo
+// will panic if dst is not k×n. RTo will also panic if the receiver does not
+// contain a successful factorization.
+func (cur *CUR) RTo(dst *Dense) {
+	if !cur.isValid() {
+		panic(badCUR)
+	}
+	truncSVDVectorsTo(dst, cur.r)
+}
@@ -0,0 +1,133 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestColumnID(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, rank int
+	}{
+		{m: 10, n: 8, rank: 3},
+		{m: 6, n: 12, rank: 2},
+		{m: 9, n: 9, rank: 9},
+		{m: 20, n: 5, rank: 1},
+	} {
+		var a Dense
+		a.Mul(randNormal(test.m, test.rank, rnd), randNormal(test.rank, test.n, rnd))
+
+		var id ColumnID
+		if !id.Factorize(&a, test.rank) {
+			t.Errorf("m=%d n=%d rank=%d: unexpected factorization failure", test.m, test.n, test.rank)
+			continue
+		}
+		cols := id.Columns(nil)
+		if len(cols) != test.rank || id.Rank() != test.rank {
+			t.Errorf("m=%d n=%d rank=%d: unexpected number of columns %d", test.m, test.n, test.rank, len(cols))
+		}
+		var z Dense
+		id.InterpTo(&z)
+		for j, c := range cols {
+			for i := 0; i < test.rank; i++ {
+				want := 0.0
+				if i == j {
+					want = 1
+				}
+				if z.At(i, c) != want {
+					t.Errorf("m=%d n=%d rank=%d: Z column %d is not an identity column", test.m, test.n, test.rank, c)
+				}
+			}
+		}
+		var got Dense
+		got.Mul(NewIndexed(&a, nil, cols), &z)
+		if !EqualApprox(&got, &a, 1e-10) {
+			t.Errorf("m=%d n=%d rank=%d: C*Z does not reconstruct A", test.m, test.n, test.rank)
+		}
+	}
+
+	var id ColumnID
+	a := NewDense(3, 3, []float64{
+		1, 0, 0,
+		0, 0, 0,
+		0, 0, 0,
+	})
+	if id.Factorize(a, 2) {
+		t.Error("unexpected success for rank exceeding that of the matrix")
+	}
+	panicked, message := panics(func() { id.Columns(nil) })
+	if !panicked || message != badColumnID {
+		t.Errorf("expected panic %q after failed factorization, got %q", badColumnID, message)
+	}
+	panicked, message = panics(func() { id.Factorize(a, 4) })
+	if !panicked || message != badTruncRank {
+		t.Errorf("expected panic %q for rank out of range, got %q", badTruncRank, message)
+	}
+}
+
+func TestCUR(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, rank int
+	}{
+		{m: 10, n: 8, rank: 3},
+		{m: 6, n: 12, rank: 2},
+		{m: 7, n: 7, rank: 7},
+	} {
+		var a Dense
+		a.Mul(randNormal(test.m, test.rank, rnd), randNormal(test.rank, test.n, rnd))
+
+		var cur CUR
+		if !cur.Factorize(&a, test.rank) {
+			t.Errorf("m=%d n=%d rank=%d: unexpected factorization failure", test.m, test.n, test.rank)
+			continue
+		}
+		var c, u, r Dense
+		cur.CTo(&c)
+		cur.UTo(&u)
+		cur.RTo(&r)
+		if !Equal(&c, NewIndexed(&a, nil, cur.Columns(nil))) {
+			t.Errorf("m=%d n=%d rank=%d: C is not formed by columns of A", test.m, test.n, test.rank)
+		}
+		if !Equal(&r, NewIndexed(&a, cur.Rows(nil), nil)) {
+			t.Errorf("m=%d n=%d rank=%d: R is not formed by rows of A", test.m, test.n, test.rank)
+		}
+		var got Dense
+		got.Product(&c, &u, &r)
+		if !EqualApprox(&got, &a, 1e-9) {
+			t.Errorf("m=%d n=%d rank=%d: C*U*R does not reconstruct A", test.m, test.n, test.rank)
+		}
+	}
+
+	// For a matrix of full rank, the error decreases with increasing rank.
+	a := randNormal(12, 10, rnd)
+	prev := Norm(a, 2)
+	for k := 1; k <= 10; k++ {
+		var cur CUR
+		if !cur.Factorize(a, k) {
+			t.Fatalf("k=%d: unexpected factorization failure", k)
+		}
+		var c, u, r, d Dense
+		cur.CTo(&c)
+		cur.UTo(&u)
+		cur.RTo(&r)
+		d.Product(&c, &u, &r)
+		d.Sub(a, &d)
+		e := Norm(&d, 2)
+		if e > prev*(1+1e-10) {
+			t.Errorf("k=%d: error increased from %v to %v", k, prev, e)
+		}
+		prev = e
+	}
+	if prev > 1e-10 {
+		t.Errorf("unexpected error for full rank: %v", prev)
+	}
+}
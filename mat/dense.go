@@ -452,6 +452,98 @@ func (m *Dense) CloneFrom(a Matrix) {
 	m.mat = mat
 }
 
+// TCopy sets the receiver to the transpose of a, stored explicitly rather than
+// as the lazy view returned by a.T(), so that the rows of a are contiguous in
+// memory as the columns of the receiver. Dense sources are transposed with a
+// cache-oblivious blocked algorithm. If a is the receiver and is square, it is
+// transposed in place without allocation. If a is the receiver and is not
+// square, TCopy will panic with ErrShape.
+func (m *Dense) TCopy(a Matrix) {
+	r, c := a.Dims()
+	if a == m && r == c {
+		transposeInPlace(m.mat.Data, m.mat.Stride, r)
+		return
+	}
+	m.reuseAsNonZeroed(c, r)
+	aU, trans := untransposeExtract(a)
+	if trans {
+		// The transpose of a is its untransposed matrix.
+		m.Copy(aU)
+		return
+	}
+	if aU, ok := aU.(*Dense); ok {
+		m.checkOverlap(aU.mat)
+		transposeTo(m.mat.Data, m.mat.Stride, aU.mat.Data, aU.mat.Stride, r, c)
+		return
+	}
+	m.Copy(Transpose{a})
+}
+
+// transposeBlock is the size of the blocks below which transposeTo and
+// transposeInPlace operate element-wise.
+const transposeBlock = 32
+
+// transposeTo stores the transpose of the r×c matrix in src with stride ss
+// into the c×r matrix in dst with stride ds. The matrix is recursively split
+// along its larger dimension so that the blocks that are transposed
+// element-wise fit in cache regardless of its size.
+func transposeTo(dst []float64, ds int, src []float64, ss int, r, c int) {
+	for r > transposeBlock || c > transposeBlock {
+		if r >= c {
+			h := r / 2
+			transposeTo(dst, ds, src, ss, h, c)
+			dst, src, r = dst[h:], src[h*ss:], r-h
+		} else {
+			h := c / 2
+			transposeTo(dst, ds, src, ss, r, h)
+			dst, src, c = dst[h*ds:], src[h:], c-h
+		}
+	}
+	for i := 0; i < r; i++ {
+		for j, v := range src[i*ss : i*ss+c] {
+			dst[j*ds+i] = v
+		}
+	}
+}
+
+// transposeInPlace transposes the n×n matrix in data with the given stride.
+func transposeInPlace(data []float64, stride, n int) {
+	if n <= transposeBlock {
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				data[i*stride+j], data[j*stride+i] = data[j*stride+i], data[i*stride+j]
+			}
+		}
+		return
+	}
+	h := n / 2
+	transposeInPlace(data, stride, h)
+	transposeInPlace(data[h*stride+h:], stride, n-h)
+	swapTransposed(data[h:], data[h*stride:], stride, h, n-h)
+}
+
+// swapTransposed exchanges the r×c matrix in a with the transpose of the c×r
+// matrix in b, both with the given stride, using the same recursion as
+// transposeTo.
+func swapTransposed(a, b []float64, stride, r, c int) {
+	for r > transposeBlock || c > transposeBlock {
+		if r >= c {
+			h := r / 2
+			swapTransposed(a, b, stride, h, c)
+			a, b, r = a[h*stride:], b[h:], r-h
+		} else {
+			h := c / 2
+			swapTransposed(a, b, stride, r, h)
+			a, b, c = a[h:], b[h*stride:], c-h
+		}
+	}
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			a[i*stride+j], b[j*stride+i] = b[j*stride+i], a[i*stride+j]
+		}
+	}
+}
+
 // Copy makes a copy of elements of a into the receiver. It is similar to the
 // built-in copy; it copies as much as the overlap between the two matrices and
 // returns the number of rows and columns it copied. If a aliases the receiver
@@ -478,10 +570,14 @@ func (m *Dense) Copy(a Matrix) (r, c int) {
 			if amat.Stride != 1 {
 				m.checkOverlap(amat)
 			}
-			for i := 0; i < r; i++ {
-				blas64.Copy(blas64.Vector{N: c, Inc: amat.Stride, Data: amat.Data[i : i+(c-1)*amat.Stride+1]},
-					blas64.Vector{N: c, Inc: 1, Data: m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+c]})
+			if amat.Stride == 1 {
+				for i := 0; i < r; i++ {
+					blas64.Copy(blas64.Vector{N: c, Inc: amat.Stride, Data: amat.Data[i : i+(c-1)*amat.Stride+1]},
+						blas64.Vector{N: c, Inc: 1, Data: m.mat.Data[i*m.mat.Stride : i*m.mat.Stride+c]})
+				}
+				break
 			}
+			transposeTo(m.mat.Data, m.mat.Stride, amat.Data, amat.Stride, c, r)
 		} else {
 			switch o := offset(m.mat.Data, amat.Data); {
 			case o < 0:
@@ -266,8 +266,59 @@ func (m *Dense) Inverse(a Matrix) error {
 	return nil
 }
 
+// Pinv computes the Moore-Penrose pseudoinverse of the m×n matrix a, storing
+// the n×m result into the receiver. The pseudoinverse is computed from the
+// singular value decomposition of a, treating singular values less than or
+// equal to rcond times the largest singular value as zero. If rcond is not
+// positive, a default of max(m,n) times the machine epsilon is used.
+//
+// The pseudoinverse gives the minimum norm solution of rank-deficient
+// least-squares problems, although solving with a PivotedQR or SVD
+// factorization directly is generally preferable. Pinv returns
+// ErrNotConverged if the singular value decomposition fails.
+func (m *Dense) Pinv(a Matrix, rcond float64) error {
+	r, c := a.Dims()
+	var svd SVD
+	if !svd.Factorize(a, SVDThin) {
+		return ErrNotConverged
+	}
+	if rcond <= 0 {
+		rcond = float64(max(r, c)) * dlamchP
+	}
+	values := svd.Values(nil)
+	var u, v Dense
+	svd.UTo(&u)
+	svd.VTo(&v)
+
+	// A⁺ = V * Σ⁺ * Uᵀ, formed from the columns of V scaled by the inverse
+	// of the retained singular values.
+	var k int
+	for k < len(values) && values[k] > rcond*values[0] {
+		k++
+	}
+	m.reuseAsZeroed(c, r)
+	if k == 0 {
+		return nil
+	}
+	vs := v.Slice(0, c, 0, k).(*Dense)
+	for j := 0; j < k; j++ {
+		for i := 0; i < c; i++ {
+			vs.set(i, j, vs.at(i, j)/values[j])
+		}
+	}
+	m.Mul(vs, u.Slice(0, r, 0, k).T())
+	return nil
+}
+
 // Mul takes the matrix product of a and b, placing the result in the receiver.
 // If the number of columns in a does not equal the number of rows in b, Mul will panic.
+//
+// Products of dense matrices are computed with the Gemm routine of the BLAS
+// implementation registered with blas64. The default Go implementation computes
+// large products in cache-sized blocks on concurrent goroutines, the number of
+// which can be limited with gonum.SetMaxWorkers in the blas/gonum package.
+// Very large products of two *Dense matrices may use Strassen's algorithm if
+// it has been enabled, see SetStrassenThreshold.
 func (m *Dense) Mul(a, b Matrix) {
 	ar, ac := a.Dims()
 	br, bc := b.Dims()
@@ -309,6 +360,10 @@ func (m *Dense) Mul(a, b Matrix) {
 			if restore == nil {
 				m.checkOverlap(bU.mat)
 			}
+			if t := useStrassen(ar, bc, ac); t > 0 {
+				strassen(aT, bT, aU.mat, bU.mat, m.mat, t)
+				return
+			}
 			blas64.Gemm(aT, bT, 1, aU.mat, bU.mat, 0, m.mat)
 			return
 
@@ -432,6 +487,16 @@ func (m *Dense) Mul(a, b Matrix) {
 		}
 	}
 
+	if aU, ok := aU.(sparser); ok {
+		m.mulSparse(aU, aTrans, b)
+		return
+	}
+	if aU, ok := aU.(*BandDense); ok {
+		m.checkOverlapMatrix(bU)
+		m.mulBand(aU, aTrans, b)
+		return
+	}
+
 	m.checkOverlapMatrix(aU)
 	m.checkOverlapMatrix(bU)
 	row := getFloats(ac, false)
@@ -673,6 +738,169 @@ func (m *Dense) Pow(a Matrix, n int) {
 	putWorkspace(x)
 }
 
+// Sqrt calculates the principal square root of the matrix a, the unique
+// matrix X with eigenvalues in the open right half-plane such that X*X = A,
+// placing the result in the receiver. Sqrt will panic with ErrSquare if a is
+// not square.
+//
+// The principal square root exists if a has no eigenvalues on the closed
+// negative real axis. Sqrt returns a Condition error if a singular iterate
+// is encountered, and ErrNotConverged if the iteration does not converge,
+// which happens when a does not have a principal square root.
+func (m *Dense) Sqrt(a Matrix) error {
+	// The implementation used here is the scaled product form of the
+	// Denman–Beavers iteration from Functions of Matrices: Theory and
+	// Computation, Chapter 6, Equation 6.29. https://doi.org/10.1137/1.9780898717778.ch6
+	const maxIter = 100
+
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	n := r
+	mk := NewDense(n, n, nil)
+	mk.Copy(a)
+	y := NewDense(n, n, nil)
+	y.Copy(a)
+	eye := NewDiagDense(n, nil)
+	for i := 0; i < n; i++ {
+		eye.SetDiag(i, 1)
+	}
+
+	var (
+		lu      LU
+		inv     Dense
+		tmp     Dense
+		yPrev   Dense
+		scaling = true
+	)
+	tol := math.Sqrt(float64(n)) * dlamchP / 2
+	for k := 0; k < maxIter; k++ {
+		lu.Factorize(mk)
+		err := lu.SolveTo(&inv, false, eye)
+		if err != nil {
+			if c, ok := err.(Condition); ok && math.IsInf(float64(c), 1) {
+				return err
+			}
+		}
+		mu := 1.0
+		if scaling {
+			logDet, _ := lu.LogDet()
+			mu = math.Exp(-logDet / float64(2*n))
+		}
+
+		// Y_{k+1} = μ/2 * Y_k * (I + μ⁻² M_k⁻¹).
+		yPrev.CloneFrom(y)
+		tmp.Scale(1/(mu*mu), &inv)
+		tmp.Add(&tmp, eye)
+		y.Mul(&yPrev, &tmp)
+		y.Scale(mu/2, y)
+
+		// M_{k+1} = 1/2 * (I + (μ² M_k + μ⁻² M_k⁻¹)/2).
+		tmp.Scale(1/(mu*mu), &inv)
+		mk.Scale(mu*mu, mk)
+		mk.Add(mk, &tmp)
+		mk.Scale(0.5, mk)
+		mk.Add(mk, eye)
+		mk.Scale(0.5, mk)
+
+		tmp.Sub(mk, eye)
+		dist := Norm(&tmp, 1)
+		if dist < 1e-2 {
+			// Scaling is no longer beneficial close to convergence.
+			scaling = false
+		}
+		tmp.Sub(y, &yPrev)
+		if dist <= tol || Norm(&tmp, 1) <= tol*Norm(y, 1) {
+			m.reuseAsNonZeroed(n, n)
+			m.Copy(y)
+			return nil
+		}
+	}
+	return ErrNotConverged
+}
+
+// Log calculates the principal logarithm of the matrix a, the unique matrix X
+// with eigenvalues whose imaginary parts lie in (-π, π) such that e^X = A,
+// placing the result in the receiver. Log will panic with ErrSquare if a is
+// not square.
+//
+// The principal logarithm exists if a has no eigenvalues on the closed
+// negative real axis. Log returns an error if a does not have a principal
+// logarithm or it could not be computed. See the documentation of Sqrt for
+// the errors that may be returned.
+func (m *Dense) Log(a Matrix) error {
+	// The implementation used here is the inverse scaling and squaring
+	// method from Functions of Matrices: Theory and Computation, Chapter 11,
+	// Section 11.5, using the [8/8] Padé approximant of log(1+x) evaluated
+	// with Gauss–Legendre quadrature. https://doi.org/10.1137/1.9780898717778.ch11
+	const (
+		// theta is the bound on ‖A^(1/2^k) - I‖ that ensures full
+		// accuracy of the [8/8] Padé approximant.
+		theta = 0.25
+		// maxSqrt is the maximal number of square roots taken.
+		maxSqrt = 64
+	)
+	// Nodes and weights of the 8-point Gauss–Legendre rule on [0, 1].
+	nodes := [...]float64{
+		0.01985507175123188, 0.10166676129318664, 0.2372337950418355, 0.4082826787521751,
+		0.5917173212478249, 0.7627662049581645, 0.8983332387068134, 0.9801449282487681,
+	}
+	weights := [...]float64{
+		0.05061426814518813, 0.11119051722668724, 0.15685332293894363, 0.18134189168918100,
+		0.18134189168918100, 0.15685332293894363, 0.11119051722668724, 0.05061426814518813,
+	}
+
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	n := r
+	x := NewDense(n, n, nil)
+	x.Copy(a)
+	eye := NewDiagDense(n, nil)
+	for i := 0; i < n; i++ {
+		eye.SetDiag(i, 1)
+	}
+
+	var k int
+	var tmp Dense
+	for {
+		tmp.Sub(x, eye)
+		if Norm(&tmp, 1) <= theta {
+			break
+		}
+		if k == maxSqrt {
+			return ErrNotConverged
+		}
+		err := x.Sqrt(x)
+		if err != nil {
+			return err
+		}
+		k++
+	}
+
+	// log(I+X) ≈ Σ w_j X (I + t_j X)⁻¹ where X = A^(1/2^k) - I.
+	x.Sub(x, eye)
+	sum := NewDense(n, n, nil)
+	var lhs, term Dense
+	for j, t := range nodes {
+		lhs.Scale(t, x)
+		lhs.Add(&lhs, eye)
+		err := term.Solve(&lhs, x)
+		if err != nil {
+			if c, ok := err.(Condition); ok && math.IsInf(float64(c), 1) {
+				return err
+			}
+		}
+		term.Scale(weights[j], &term)
+		sum.Add(sum, &term)
+	}
+	m.reuseAsNonZeroed(n, n)
+	m.Scale(math.Pow(2, float64(k)), sum)
+	return nil
+}
+
 // Kronecker calculates the Kronecker product of a and b, placing the result in
 // the receiver.
 func (m *Dense) Kronecker(a, b Matrix) {
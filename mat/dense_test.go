@@ -1014,6 +1014,94 @@ func TestDenseExp(t *testing.T) {
 	}
 }
 
+func TestDenseSqrtLog(t *testing.T) {
+	t.Parallel()
+	for i, test := range []struct {
+		a        [][]float64
+		wantSqrt [][]float64
+		wantLog  [][]float64
+	}{
+		{
+			a:        [][]float64{{4, 0}, {0, 9}},
+			wantSqrt: [][]float64{{2, 0}, {0, 3}},
+			wantLog:  [][]float64{{math.Log(4), 0}, {0, math.Log(9)}},
+		},
+		{
+			// A rotation by 2 radians.
+			a:        [][]float64{{math.Cos(2), -math.Sin(2)}, {math.Sin(2), math.Cos(2)}},
+			wantSqrt: [][]float64{{math.Cos(1), -math.Sin(1)}, {math.Sin(1), math.Cos(1)}},
+			wantLog:  [][]float64{{0, -2}, {2, 0}},
+		},
+		{
+			// A Jordan block.
+			a:        [][]float64{{1, 1}, {0, 1}},
+			wantSqrt: [][]float64{{1, 0.5}, {0, 1}},
+			wantLog:  [][]float64{{0, 1}, {0, 0}},
+		},
+	} {
+		a := NewDense(flatten(test.a))
+		var got Dense
+		if err := got.Sqrt(a); err != nil {
+			t.Fatalf("unexpected error for Sqrt test %d: %v", i, err)
+		}
+		if want := NewDense(flatten(test.wantSqrt)); !EqualApprox(&got, want, 1e-13) {
+			t.Errorf("unexpected result for Sqrt test %d\ngot:\n%v\nwant:\n%v",
+				i, Formatted(&got), Formatted(want))
+		}
+		got.Reset()
+		if err := got.Log(a); err != nil {
+			t.Fatalf("unexpected error for Log test %d: %v", i, err)
+		}
+		if want := NewDense(flatten(test.wantLog)); !EqualApprox(&got, want, 1e-13) {
+			t.Errorf("unexpected result for Log test %d\ngot:\n%v\nwant:\n%v",
+				i, Formatted(&got), Formatted(want))
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 5, 10, 30} {
+		// A random matrix shifted to move its eigenvalues into the right
+		// half-plane.
+		a := NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				a.Set(i, j, rnd.NormFloat64())
+			}
+			a.Set(i, i, a.At(i, i)+2*math.Sqrt(float64(n)))
+		}
+		var x, xx Dense
+		if err := x.Sqrt(a); err != nil {
+			t.Fatalf("n=%d: unexpected Sqrt error: %v", n, err)
+		}
+		xx.Mul(&x, &x)
+		if !EqualApprox(&xx, a, 1e-12) {
+			t.Errorf("n=%d: Sqrt(A)² != A", n)
+		}
+		var l, el Dense
+		if err := l.Log(a); err != nil {
+			t.Fatalf("n=%d: unexpected Log error: %v", n, err)
+		}
+		el.Exp(&l)
+		if !EqualApprox(&el, a, 1e-12) {
+			t.Errorf("n=%d: Exp(Log(A)) != A", n)
+		}
+	}
+
+	// Matrices without a principal square root or logarithm.
+	for _, a := range []*Dense{
+		NewDense(2, 2, []float64{-1, 0, 0, 1}),
+		NewDense(2, 2, []float64{0, 0, 0, 1}),
+	} {
+		var m Dense
+		if err := m.Sqrt(a); err == nil {
+			t.Errorf("expected Sqrt error for %v", a.RawMatrix().Data)
+		}
+		if err := m.Log(a); err == nil {
+			t.Errorf("expected Log error for %v", a.RawMatrix().Data)
+		}
+	}
+}
+
 func TestDensePow(t *testing.T) {
 	t.Parallel()
 	for i, test := range []struct {
@@ -1359,6 +1447,73 @@ func TestDenseCopyT(t *testing.T) {
 	}
 }
 
+func TestDenseTCopy(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, r := range []int{1, 2, 31, 33, 70, 129} {
+		for _, c := range []int{1, 3, 32, 65, 100} {
+			// Use a view with a padded stride as the source.
+			a := randNormal(r+2, c+5, rnd).Slice(1, r+1, 2, c+2).(*Dense)
+			want := NewDense(c, r, nil)
+			for i := 0; i < c; i++ {
+				for j := 0; j < r; j++ {
+					want.Set(i, j, a.At(j, i))
+				}
+			}
+
+			var got Dense
+			got.TCopy(a)
+			if !Equal(&got, want) {
+				t.Errorf("unexpected TCopy result for %d×%d", r, c)
+			}
+			var gotT Dense
+			gotT.TCopy(a.T())
+			if !Equal(&gotT, a) {
+				t.Errorf("unexpected TCopy result for transposed %d×%d", r, c)
+			}
+			var gotM Dense
+			gotM.TCopy((*basicMatrix)(a))
+			if !Equal(&gotM, want) {
+				t.Errorf("unexpected TCopy result for non-Dense %d×%d", r, c)
+			}
+			var gotCopy Dense
+			gotCopy.CloneFrom(want)
+			gotCopy.Copy(a.T())
+			if !Equal(&gotCopy, want) {
+				t.Errorf("unexpected Copy result for transposed %d×%d", r, c)
+			}
+
+			if r == c {
+				m := DenseCopyOf(a)
+				m.TCopy(m)
+				if !Equal(m, want) {
+					t.Errorf("unexpected in-place TCopy result for %d×%d", r, c)
+				}
+				v := randNormal(r+3, r+3, rnd)
+				view := v.Slice(2, r+2, 1, r+1).(*Dense)
+				orig := DenseCopyOf(v)
+				viewT := DenseCopyOf(view.T())
+				view.TCopy(view)
+				if !Equal(view, viewT) {
+					t.Errorf("unexpected in-place TCopy result for %d×%d view", r, c)
+				}
+				for i := 0; i < r+3; i++ {
+					for j := 0; j < r+3; j++ {
+						if (i < 2 || i >= r+2 || j < 1 || j >= r+1) && v.At(i, j) != orig.At(i, j) {
+							t.Errorf("element outside view modified by in-place TCopy for %d×%d", r, c)
+						}
+					}
+				}
+			} else {
+				m := DenseCopyOf(a)
+				if ok, _ := panics(func() { m.TCopy(m) }); !ok {
+					t.Errorf("expected panic for in-place TCopy of %d×%d", r, c)
+				}
+			}
+		}
+	}
+}
+
 func TestDenseCopyDenseAlias(t *testing.T) {
 	t.Parallel()
 	for _, trans := range []bool{false, true} {
@@ -2089,6 +2244,72 @@ func TestDenseInverse(t *testing.T) {
 	}
 }
 
+func TestDensePinv(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, rank int
+	}{
+		{m: 1, n: 1, rank: 1},
+		{m: 4, n: 4, rank: 4},
+		{m: 4, n: 4, rank: 2},
+		{m: 7, n: 3, rank: 3},
+		{m: 7, n: 3, rank: 1},
+		{m: 3, n: 7, rank: 2},
+		{m: 30, n: 20, rank: 11},
+	} {
+		m, n := test.m, test.n
+		var a Dense
+		a.Mul(randNormal(m, test.rank, rnd), randNormal(test.rank, n, rnd))
+
+		var p Dense
+		err := p.Pinv(&a, 0)
+		if err != nil {
+			t.Fatalf("m=%d,n=%d: unexpected error: %v", m, n, err)
+		}
+		if r, c := p.Dims(); r != n || c != m {
+			t.Fatalf("m=%d,n=%d: unexpected dimensions %d×%d", m, n, r, c)
+		}
+
+		// Check the Penrose conditions.
+		var ap, pa, apa, pap Dense
+		ap.Mul(&a, &p)
+		pa.Mul(&p, &a)
+		apa.Mul(&ap, &a)
+		pap.Mul(&pa, &p)
+		const tol = 1e-10
+		if !EqualApprox(&apa, &a, tol) {
+			t.Errorf("m=%d,n=%d: A*A⁺*A != A", m, n)
+		}
+		if !EqualApprox(&pap, &p, tol) {
+			t.Errorf("m=%d,n=%d: A⁺*A*A⁺ != A⁺", m, n)
+		}
+		if !EqualApprox(&ap, ap.T(), tol) {
+			t.Errorf("m=%d,n=%d: A*A⁺ is not symmetric", m, n)
+		}
+		if !EqualApprox(&pa, pa.T(), tol) {
+			t.Errorf("m=%d,n=%d: A⁺*A is not symmetric", m, n)
+		}
+
+		if test.rank == min(m, n) && m == n {
+			var inv Dense
+			inv.Inverse(&a)
+			if !EqualApprox(&p, &inv, tol) {
+				t.Errorf("m=%d,n=%d: pseudoinverse of non-singular matrix differs from inverse", m, n)
+			}
+		}
+	}
+
+	var p Dense
+	err := p.Pinv(NewDense(2, 3, nil), 0)
+	if err != nil {
+		t.Errorf("unexpected error for zero matrix: %v", err)
+	}
+	if !Equal(&p, NewDense(3, 2, nil)) {
+		t.Errorf("unexpected pseudoinverse of zero matrix: %v", Formatted(&p))
+	}
+}
+
 var (
 	wd *Dense
 )
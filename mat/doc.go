@@ -62,6 +62,11 @@
 // An empty matrix can not be sliced even if it does have an adequately sized
 // backing data slice, but can be expanded using its Grow method if it exists.
 //
+// Factorization types such as Cholesky, LU and QR likewise reuse the memory
+// held by the receiver when Factorize is called again, and temporary workspace
+// is taken from internal pools. Repeatedly factorizing and solving small
+// systems with the same receivers in a loop therefore does not allocate.
+//
 // The Matrix Interfaces
 //
 // The Matrix interface is the common link between the concrete types of real
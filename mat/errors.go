@@ -136,6 +136,7 @@ var (
 	ErrSliceLengthMismatch = Error{"mat: input slice length mismatch"}
 	ErrNotPSD              = Error{"mat: input not positive symmetric definite"}
 	ErrFailedEigen         = Error{"mat: eigendecomposition not successful"}
+	ErrNotConverged        = Error{"mat: iteration did not converge"}
 )
 
 // ErrorStack represents matrix handling errors that have been recovered by Maybe wrappers.
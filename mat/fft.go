@@ -0,0 +1,118 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// The discrete Fourier transforms used by the structured matrix types are
+// computed here rather than with the dsp/fourier package, so that mat does
+// not depend on the signal processing packages.
+
+// fft computes in place the unnormalized discrete Fourier transform of x,
+//  X[k] = \sum_j x[j] exp(-2πi j k/n),
+// or the transform with the positive exponent if inverse is true. The
+// transform takes O(n log n) time for all lengths of x, using Bluestein's
+// algorithm when the length is not a power of two.
+func fft(x []complex128, inverse bool) {
+	n := len(x)
+	if n <= 1 {
+		return
+	}
+	if n&(n-1) == 0 {
+		fftRadix2(x, inverse)
+		return
+	}
+	fftBluestein(x, inverse)
+}
+
+// fftRadix2 computes the transform of fft when the length of x is a power
+// of two.
+func fftRadix2(x []complex128, inverse bool) {
+	n := len(x)
+	// Reorder x into bit-reversed index order.
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+	for size := 2; size <= n; size <<= 1 {
+		half := size >> 1
+		theta := sign * 2 * math.Pi / float64(size)
+		for k := 0; k < half; k++ {
+			w := cmplx.Rect(1, theta*float64(k))
+			for i := k; i < n; i += size {
+				u := x[i]
+				v := x[i+half] * w
+				x[i] = u + v
+				x[i+half] = u - v
+			}
+		}
+	}
+}
+
+// fftBluestein computes the transform of fft for any length of x by writing
+// it as a convolution, which is computed with power of two transforms.
+func fftBluestein(x []complex128, inverse bool) {
+	n := len(x)
+	m := 1
+	for m < 2*n-1 {
+		m <<= 1
+	}
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+	// Since 2jk = j² + k² - (k-j)², the transform is
+	//  X[k] = w[k] \sum_j x[j] w[j] conj(w[k-j])
+	// with the chirp w[j] = exp(±πi j²/n). The exponent is reduced modulo
+	// 2n before scaling to keep the angle accurate for large j.
+	w := make([]complex128, n)
+	for j := range w {
+		w[j] = cmplx.Rect(1, sign*math.Pi*float64((j*j)%(2*n))/float64(n))
+	}
+	a := make([]complex128, m)
+	b := make([]complex128, m)
+	for j, v := range x {
+		a[j] = v * w[j]
+	}
+	b[0] = cmplx.Conj(w[0])
+	for j := 1; j < n; j++ {
+		b[j] = cmplx.Conj(w[j])
+		b[m-j] = b[j]
+	}
+	fftRadix2(a, false)
+	fftRadix2(b, false)
+	for i := range a {
+		a[i] *= b[i]
+	}
+	fftRadix2(a, true)
+	f := complex(1/float64(m), 0)
+	for k := range x {
+		x[k] = w[k] * a[k] * f
+	}
+}
+
+// fftReal returns the unnormalized discrete Fourier transform of the real
+// sequence x, as computed by fft.
+func fftReal(x []float64) []complex128 {
+	c := make([]complex128, len(x))
+	for i, v := range x {
+		c[i] = complex(v, 0)
+	}
+	fft(c, false)
+	return c
+}
@@ -0,0 +1,43 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestFFT(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 4, 5, 7, 8, 12, 16, 17, 31, 64, 100} {
+		x := make([]complex128, n)
+		for i := range x {
+			x[i] = complex(rnd.NormFloat64(), rnd.NormFloat64())
+		}
+		for _, inverse := range []bool{false, true} {
+			sign := -1.0
+			if inverse {
+				sign = 1
+			}
+			want := make([]complex128, n)
+			for k := range want {
+				for j, v := range x {
+					want[k] += v * cmplx.Rect(1, sign*2*math.Pi*float64(j*k)/float64(n))
+				}
+			}
+			got := append([]complex128(nil), x...)
+			fft(got, inverse)
+			for k := range got {
+				if cmplx.Abs(got[k]-want[k]) > 1e-10*float64(n) {
+					t.Errorf("n=%d inverse=%t: unexpected coefficient %d: got %v want %v", n, inverse, k, got[k], want[k])
+				}
+			}
+		}
+	}
+}
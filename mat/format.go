@@ -6,10 +6,16 @@ package mat
 
 import (
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
 )
 
 // Formatted returns a fmt.Formatter for the matrix m using the given options.
+// The representation of the elements is controlled by the formatting verb and
+// its flags, so that, for example, %.3f prints elements with three decimal
+// places and %.4e prints elements in scientific notation with four decimal
+// places.
 func Formatted(m Matrix, options ...FormatOption) fmt.Formatter {
 	f := formatter{
 		matrix: m,
@@ -27,8 +33,18 @@ type formatter struct {
 	margin  int
 	dot     byte
 	squeeze bool
+	syntax  literalSyntax
 }
 
+// literalSyntax is the language syntax of a formatted matrix literal.
+type literalSyntax int
+
+const (
+	noSyntax literalSyntax = iota
+	matlabSyntax
+	pythonSyntax
+)
+
 // FormatOption is a functional option for matrix formatting.
 type FormatOption func(*formatter)
 
@@ -56,8 +72,30 @@ func Squeeze() FormatOption {
 	return func(f *formatter) { f.squeeze = true }
 }
 
+// FormatMATLAB sets the printing behaviour to output a MATLAB matrix literal,
+// such as [1 2 3; 4 5 6]. If the '#' verb flag is used, each row of the matrix
+// is printed on a separate line with the columns aligned. The Excerpt and
+// DotByte options and the ' ' verb flag are ignored for MATLAB output.
+func FormatMATLAB() FormatOption {
+	return func(f *formatter) { f.syntax = matlabSyntax }
+}
+
+// FormatPython sets the printing behaviour to output a Python nested list
+// literal suitable for passing to numpy.array, such as [[1, 2, 3], [4, 5, 6]].
+// Infinite and NaN elements are printed as np.inf and np.nan. If the '#' verb
+// flag is used, each row of the matrix is printed on a separate line with the
+// columns aligned. The Excerpt and DotByte options and the ' ' verb flag are
+// ignored for Python output.
+func FormatPython() FormatOption {
+	return func(f *formatter) { f.syntax = pythonSyntax }
+}
+
 // Format satisfies the fmt.Formatter interface.
 func (f formatter) Format(fs fmt.State, c rune) {
+	if f.syntax != noSyntax {
+		formatLiteral(f.matrix, f.prefix, f.syntax, fs, c)
+		return
+	}
 	if c == 'v' && fs.Flag('#') {
 		fmt.Fprintf(fs, "%#v", f.matrix)
 		return
@@ -236,3 +274,124 @@ type columnWidth []int
 
 func (c columnWidth) width(i int) int   { return c[i] }
 func (c columnWidth) setWidth(i, w int) { c[i] = w }
+
+// formatLiteral prints m to fs as a matrix literal in the given syntax. The
+// format character c and the precision specify the numerical representation
+// of elements as for format. If the '#' flag is set, the rows are printed on
+// separate lines each preceded by the prefix, and the columns are aligned.
+func formatLiteral(m Matrix, prefix string, syntax literalSyntax, fs fmt.State, c rune) {
+	rows, cols := m.Dims()
+	switch c {
+	case 'v', 'e', 'E', 'f', 'F', 'g', 'G':
+		if c == 'v' {
+			c = 'g'
+		}
+	default:
+		fmt.Fprintf(fs, "%%!%c(%T=Dims(%d, %d))", c, m, rows, cols)
+		return
+	}
+	prec, ok := fs.Precision()
+	if !ok {
+		prec = -1
+	}
+
+	// Columns are aligned to the widest element in the multi-line layout.
+	layout := fs.Flag('#')
+	width, _ := fs.Width()
+	cells := make([]string, rows*cols)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			s := literalFloat(m.At(i, j), syntax, byte(c), prec)
+			cells[i*cols+j] = s
+			if layout {
+				width = max(width, len(s))
+			}
+		}
+	}
+	pad := func(s string) string {
+		if len(s) >= width {
+			return s
+		}
+		p := strings.Repeat(" ", width-len(s))
+		if fs.Flag('-') {
+			return s + p
+		}
+		return p + s
+	}
+
+	var b strings.Builder
+	switch syntax {
+	case matlabSyntax:
+		b.WriteByte('[')
+		if layout {
+			b.WriteString("\n")
+		}
+		for i := 0; i < rows; i++ {
+			if layout {
+				b.WriteString(prefix)
+				b.WriteByte(' ')
+			} else if i != 0 {
+				b.WriteString("; ")
+			}
+			for j := 0; j < cols; j++ {
+				if j != 0 {
+					b.WriteByte(' ')
+				}
+				b.WriteString(pad(cells[i*cols+j]))
+			}
+			if layout {
+				b.WriteString("\n")
+			}
+		}
+		if layout {
+			b.WriteString(prefix)
+		}
+		b.WriteByte(']')
+	case pythonSyntax:
+		b.WriteByte('[')
+		for i := 0; i < rows; i++ {
+			if i != 0 {
+				b.WriteByte(',')
+				if layout {
+					b.WriteString("\n")
+					b.WriteString(prefix)
+				}
+				b.WriteByte(' ')
+			}
+			b.WriteByte('[')
+			for j := 0; j < cols; j++ {
+				if j != 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString(pad(cells[i*cols+j]))
+			}
+			b.WriteByte(']')
+		}
+		b.WriteByte(']')
+	default:
+		panic("mat: unknown literal syntax")
+	}
+	fmt.Fprint(fs, b.String())
+}
+
+// literalFloat returns the representation of v in the given literal syntax.
+func literalFloat(v float64, syntax literalSyntax, c byte, prec int) string {
+	switch {
+	case math.IsNaN(v):
+		if syntax == pythonSyntax {
+			return "np.nan"
+		}
+		return "NaN"
+	case math.IsInf(v, 1):
+		if syntax == pythonSyntax {
+			return "np.inf"
+		}
+		return "Inf"
+	case math.IsInf(v, -1):
+		if syntax == pythonSyntax {
+			return "-np.inf"
+		}
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, c, prec, 64)
+}
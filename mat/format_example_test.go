@@ -110,3 +110,39 @@ func ExampleExcerpt() {
 	//  [ 0   1   2  ...  ...  97  98  99]
 
 }
+
+func ExampleFormatMATLAB() {
+	a := mat.NewDense(2, 3, []float64{1, 2, 3, 0, 4.5, 6})
+
+	// Create a matrix formatting value that prints MATLAB syntax
+	// to generate test fixtures for MATLAB code.
+	fa := mat.Formatted(a, mat.FormatMATLAB())
+	fmt.Printf("a = %v;\n", fa)
+
+	// The '#' flag prints rows on separate lines.
+	fmt.Printf("a = %#.2f;\n", fa)
+
+	// Output:
+	// a = [1 2 3; 0 4.5 6];
+	// a = [
+	//  1.00 2.00 3.00
+	//  0.00 4.50 6.00
+	// ];
+}
+
+func ExampleFormatPython() {
+	a := mat.NewDense(2, 3, []float64{1, 2, 3, 0, 4.5, 6})
+
+	// Create a matrix formatting value that prints Python syntax
+	// suitable for use with numpy.array.
+	fa := mat.Formatted(a, mat.FormatPython(), mat.Prefix("             "))
+	fmt.Printf("a = np.array(%v)\n", fa)
+
+	// The '#' flag prints rows on separate lines with the prefix.
+	fmt.Printf("a = np.array(%#v)\n", fa)
+
+	// Output:
+	// a = np.array([[1, 2, 3], [0, 4.5, 6]])
+	// a = np.array([[  1,   2,   3],
+	//               [  0, 4.5,   6]])
+}
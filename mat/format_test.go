@@ -128,6 +128,37 @@ func TestFormat(t *testing.T) {
 				{"%v", "Dims(10, 1)\n⎡ 1⎤\n⎢ 2⎥\n⎢ 3⎥\n .\n .\n .\n⎢ 8⎥\n⎢ 9⎥\n⎣10⎦"},
 			},
 		},
+		{
+			Formatted(NewDense(2, 3, []float64{1, -2.5, 0, math.Inf(1), math.NaN(), 1e-10}), FormatMATLAB(), Prefix("  ")),
+			[]rp{
+				{"%v", "[1 -2.5 0; Inf NaN 1e-10]"},
+				{"%.2f", "[1.00 -2.50 0.00; Inf NaN 0.00]"},
+				{"%.1e", "[1.0e+00 -2.5e+00 0.0e+00; Inf NaN 1.0e-10]"},
+				{"%#v", "[\n       1  -2.5     0\n     Inf   NaN 1e-10\n  ]"},
+				{"%s", "%!s(*mat.Dense=Dims(2, 3))"},
+			},
+		},
+		{
+			Formatted(NewDense(2, 3, []float64{1, -2.5, 0, math.Inf(1), math.NaN(), math.Inf(-1)}), FormatPython(), Prefix("  ")),
+			[]rp{
+				{"%v", "[[1, -2.5, 0], [np.inf, np.nan, -np.inf]]"},
+				{"%3v", "[[  1, -2.5,   0], [np.inf, np.nan, -np.inf]]"},
+				{"%#v", "[[      1,    -2.5,       0],\n   [ np.inf,  np.nan, -np.inf]]"},
+				{"%#-v", "[[1      , -2.5   , 0      ],\n   [np.inf , np.nan , -np.inf]]"},
+			},
+		},
+		{
+			Formatted(NewVecDense(3, []float64{1, 2, 3}), FormatMATLAB(), Excerpt(1)),
+			[]rp{
+				{"%v", "[1; 2; 3]"},
+			},
+		},
+		{
+			Formatted(NewVecDense(3, []float64{1, 2, 3}).T(), FormatPython()),
+			[]rp{
+				{"%v", "[[1, 2, 3]]"},
+			},
+		},
 		{
 			func() fmt.Formatter {
 				m := NewDense(10, 10, nil)
@@ -0,0 +1,605 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+)
+
+// GenEigenSym is a type for creating and manipulating the eigenvalue
+// decomposition of the symmetric-definite generalized eigenvalue problem
+//  A * x = λ * B * x
+// where A is symmetric and B is symmetric positive definite.
+type GenEigenSym struct {
+	vectorsComputed bool
+
+	values  []float64
+	vectors *Dense
+}
+
+// Factorize computes the eigenvalues, and optionally the eigenvectors, of the
+// symmetric-definite generalized eigenvalue problem A * x = λ * B * x.
+// Factorize panics if a and b do not have the same size.
+//
+// The problem is reduced to a standard symmetric eigenvalue problem using the
+// Cholesky factorization B = Uᵀ * U. The eigenvalues are real and are computed
+// in ascending order. The eigenvectors X are normalized so that
+//  Xᵀ * B * X = I.
+// If the vectors input argument is false, the eigenvectors are not computed.
+//
+// Factorize returns whether the decomposition succeeded. The decomposition
+// fails if B is not positive definite. If the decomposition failed, methods
+// that require a successful factorization will panic.
+func (e *GenEigenSym) Factorize(a, b Symmetric, vectors bool) (ok bool) {
+	// kill previous decomposition
+	e.vectorsComputed = false
+	e.values = nil
+	e.vectors = nil
+
+	n := a.Symmetric()
+	if b.Symmetric() != n {
+		panic(ErrShape)
+	}
+	var chol Cholesky
+	if !chol.Factorize(b) {
+		return false
+	}
+	u := chol.chol.mat
+
+	// C = U⁻ᵀ * A * U⁻¹ has the same eigenvalues as the pencil (A, B).
+	c := NewDense(n, n, nil)
+	c.Copy(a)
+	blas64.Trsm(blas.Left, blas.Trans, 1, u, c.mat)
+	blas64.Trsm(blas.Right, blas.NoTrans, 1, u, c.mat)
+	sc := NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			sc.SetSym(i, j, (c.at(i, j)+c.at(j, i))/2)
+		}
+	}
+
+	var es EigenSym
+	if !es.Factorize(sc, vectors) {
+		return false
+	}
+	e.values = es.values
+	if vectors {
+		// The eigenvectors of the pencil are X = U⁻¹ * Y where Y holds
+		// the eigenvectors of C.
+		x := NewDense(n, n, nil)
+		es.VectorsTo(x)
+		blas64.Trsm(blas.Left, blas.NoTrans, 1, u, x.mat)
+		e.vectors = x
+		e.vectorsComputed = true
+	}
+	return true
+}
+
+// succFact returns whether the receiver contains a successful factorization.
+func (e *GenEigenSym) succFact() bool {
+	return len(e.values) != 0
+}
+
+// Values extracts the eigenvalues of the factorized problem. If dst is
+// non-nil, the values are stored in-place into dst. In this case
+// dst must have length n, otherwise Values will panic. If dst is
+// nil, then a new slice will be allocated of the proper length and filled
+// with the eigenvalues.
+//
+// Values panics if the decomposition was not successful.
+func (e *GenEigenSym) Values(dst []float64) []float64 {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if dst == nil {
+		dst = make([]float64, len(e.values))
+	}
+	if len(dst) != len(e.values) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(dst, e.values)
+	return dst
+}
+
+// VectorsTo stores the eigenvectors of the decomposition into the columns of
+// dst. The eigenvectors are B-orthonormal.
+//
+// If dst is empty, VectorsTo will resize dst to be n×n. When dst is
+// non-empty, VectorsTo will panic if dst is not n×n. VectorsTo will also
+// panic if the eigenvectors were not computed during the factorization,
+// or if the receiver does not contain a successful factorization.
+func (e *GenEigenSym) VectorsTo(dst *Dense) {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if !e.vectorsComputed {
+		panic(noVectors)
+	}
+	r, c := e.vectors.Dims()
+	if dst.IsEmpty() {
+		dst.ReuseAs(r, c)
+	} else {
+		r2, c2 := dst.Dims()
+		if r != r2 || c != c2 {
+			panic(ErrShape)
+		}
+	}
+	dst.Copy(e.vectors)
+}
+
+// GenEigen is a type for creating and using the eigenvalue decomposition of
+// the general generalized eigenvalue problem
+//  A * x = λ * B * x
+// where A and B are square matrices.
+type GenEigen struct {
+	n int // The size of the factorized problem.
+
+	kind EigenKind
+
+	alpha    []complex128
+	beta     []float64
+	rVectors *CDense
+	lVectors *CDense
+}
+
+// succFact returns whether the receiver contains a successful factorization.
+func (e *GenEigen) succFact() bool {
+	return e.n != 0
+}
+
+// Factorize computes the generalized eigenvalues of the pair of n×n matrices
+// (a, b), and optionally the generalized eigenvectors. Factorize panics if a
+// and b are not square or do not have the same size.
+//
+// A right eigenvalue/eigenvector combination is defined by
+//  A * x_r = λ * B * x_r
+// and a left eigenvalue/eigenvector combination is defined by
+//  x_lᴴ * A = λ * x_lᴴ * B
+// The eigenvalues are represented as ratios λ = α/β. When B is singular, some
+// eigenvalues may be infinite, with β equal to zero. If A and B have a common
+// null space, the problem is singular and the eigenvalues are meaningless.
+//
+// The eigenvalues are computed with the QZ algorithm applied to the generalized
+// Schur form of the pair. kind specifies which of the eigenvectors, if any, to
+// compute. See the EigenKind documentation for more information.
+//
+// Factorize returns whether the decomposition succeeded. If the decomposition
+// failed, methods that require a successful factorization will panic.
+func (e *GenEigen) Factorize(a, b Matrix, kind EigenKind) (ok bool) {
+	// kill previous factorization.
+	e.n = 0
+	e.kind = 0
+	e.alpha = nil
+	e.beta = nil
+	e.rVectors = nil
+	e.lVectors = nil
+
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	br, bc := b.Dims()
+	if br != r || bc != c {
+		panic(ErrShape)
+	}
+	n := r
+	s := realToC(a)
+	p := realToC(b)
+	q := NewCDense(n, n, nil)
+	z := NewCDense(n, n, nil)
+	cHessenbergTriangular(s, p, q, z)
+	if !cQZ(s, p, q, z) {
+		return false
+	}
+	e.n = n
+	e.kind = kind
+
+	// Scale each eigenvalue pair so that β is real and non-negative.
+	e.alpha = make([]complex128, n)
+	e.beta = make([]float64, n)
+	for i := 0; i < n; i++ {
+		alpha, beta := s.at(i, i), p.at(i, i)
+		absBeta := cmplx.Abs(beta)
+		if absBeta != 0 {
+			alpha *= cmplx.Conj(beta) / complex(absBeta, 0)
+		}
+		e.alpha[i] = alpha
+		e.beta[i] = absBeta
+	}
+
+	if kind&EigenRight != 0 {
+		e.rVectors = cGenSchurVectors(s, p, z, false)
+	}
+	if kind&EigenLeft != 0 {
+		e.lVectors = cGenSchurVectors(s, p, q, true)
+	}
+	return true
+}
+
+// Kind returns the EigenKind of the decomposition. If no decomposition has been
+// computed, Kind returns -1.
+func (e *GenEigen) Kind() EigenKind {
+	if !e.succFact() {
+		return -1
+	}
+	return e.kind
+}
+
+// Values extracts the generalized eigenvalues α/β of the factorized problem.
+// Infinite eigenvalues are returned as cmplx.Inf(). If dst is non-nil, the
+// values are stored in-place into dst. In this case dst must have length n,
+// otherwise Values will panic. If dst is nil, then a new slice will be
+// allocated of the proper length and filled with the eigenvalues.
+//
+// Values panics if the decomposition was not successful.
+func (e *GenEigen) Values(dst []complex128) []complex128 {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if dst == nil {
+		dst = make([]complex128, e.n)
+	}
+	if len(dst) != e.n {
+		panic(ErrSliceLengthMismatch)
+	}
+	for i, beta := range e.beta {
+		if beta == 0 {
+			dst[i] = cmplx.Inf()
+			continue
+		}
+		dst[i] = e.alpha[i] / complex(beta, 0)
+	}
+	return dst
+}
+
+// AlphaBeta extracts the generalized eigenvalues of the factorized problem as
+// the pairs (α, β) such that λ = α/β. The values of β are real and
+// non-negative. If alpha or beta is non-nil, it must have length n, otherwise
+// AlphaBeta will panic. Nil arguments are replaced by newly allocated slices.
+//
+// AlphaBeta panics if the decomposition was not successful.
+func (e *GenEigen) AlphaBeta(alpha []complex128, beta []float64) ([]complex128, []float64) {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if alpha == nil {
+		alpha = make([]complex128, e.n)
+	}
+	if beta == nil {
+		beta = make([]float64, e.n)
+	}
+	if len(alpha) != e.n || len(beta) != e.n {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(alpha, e.alpha)
+	copy(beta, e.beta)
+	return alpha, beta
+}
+
+// VectorsTo stores the right eigenvectors of the decomposition into the columns
+// of dst. The computed eigenvectors are normalized to have Euclidean norm equal
+// to 1 and largest component real.
+//
+// If dst is empty, VectorsTo will resize dst to be n×n. When dst is
+// non-empty, VectorsTo will panic if dst is not n×n. VectorsTo will also
+// panic if the eigenvectors were not computed during the factorization,
+// or if the receiver does not contain a successful factorization.
+func (e *GenEigen) VectorsTo(dst *CDense) {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if e.kind&EigenRight == 0 {
+		panic(noVectors)
+	}
+	if dst.IsEmpty() {
+		dst.ReuseAs(e.n, e.n)
+	} else {
+		r, c := dst.Dims()
+		if r != e.n || c != e.n {
+			panic(ErrShape)
+		}
+	}
+	dst.Copy(e.rVectors)
+}
+
+// LeftVectorsTo stores the left eigenvectors of the decomposition into the
+// columns of dst. The computed eigenvectors are normalized to have Euclidean
+// norm equal to 1 and largest component real.
+//
+// If dst is empty, LeftVectorsTo will resize dst to be n×n. When dst is
+// non-empty, LeftVectorsTo will panic if dst is not n×n. LeftVectorsTo will also
+// panic if the left eigenvectors were not computed during the factorization,
+// or if the receiver does not contain a successful factorization.
+func (e *GenEigen) LeftVectorsTo(dst *CDense) {
+	if !e.succFact() {
+		panic(badFact)
+	}
+	if e.kind&EigenLeft == 0 {
+		panic(noVectors)
+	}
+	if dst.IsEmpty() {
+		dst.ReuseAs(e.n, e.n)
+	} else {
+		r, c := dst.Dims()
+		if r != e.n || c != e.n {
+			panic(ErrShape)
+		}
+	}
+	dst.Copy(e.lVectors)
+}
+
+// cRotateRows applies the plane rotation
+//  G = [ c         s ]
+//      [ -conj(s)  c ]
+// from the left to rows k and k+1 of m in the columns from colStart onward.
+func cRotateRows(m *CDense, k, colStart int, c float64, s complex128) {
+	cc := complex(c, 0)
+	data, stride := m.mat.Data, m.mat.Stride
+	for j := colStart; j < m.mat.Cols; j++ {
+		x, y := data[k*stride+j], data[(k+1)*stride+j]
+		data[k*stride+j] = cc*x + s*y
+		data[(k+1)*stride+j] = -cmplx.Conj(s)*x + cc*y
+	}
+}
+
+// cRotateCols applies the conjugate transpose of the plane rotation G from the
+// right to columns k and k+1 of m in the rows up to and including rowEnd.
+func cRotateCols(m *CDense, k, rowEnd int, c float64, s complex128) {
+	cc := complex(c, 0)
+	data, stride := m.mat.Data, m.mat.Stride
+	for i := 0; i <= rowEnd; i++ {
+		x, y := data[i*stride+k], data[i*stride+k+1]
+		data[i*stride+k] = cc*x + cmplx.Conj(s)*y
+		data[i*stride+k+1] = -s*x + cc*y
+	}
+}
+
+// cHessenbergTriangular reduces the pair of n×n matrices (a, b) in place to
+// the pair (H, T) = (Qᴴ*A*Z, Qᴴ*B*Z) where H is upper Hessenberg and T is upper
+// triangular. q and z are overwritten by the unitary matrices Q and Z.
+func cHessenbergTriangular(a, b, q, z *CDense) {
+	n := a.mat.Rows
+
+	// Reduce B to upper triangular form.
+	var qr CQR
+	qr.Factorize(b)
+	qr.QTo(q)
+	qr.RTo(b)
+	var qa CDense
+	qa.Mul(q.H(), a)
+	a.Copy(&qa)
+	z.Zero()
+	for i := 0; i < n; i++ {
+		z.set(i, i, 1)
+	}
+
+	// Annihilate the elements of A below the first subdiagonal column by
+	// column, restoring the triangular form of B after each rotation.
+	for j := 0; j < n-2; j++ {
+		for i := n - 1; i > j+1; i-- {
+			c, s, _ := cGivens(a.at(i-1, j), a.at(i, j))
+			cRotateRows(a, i-1, j, c, s)
+			a.set(i, j, 0)
+			cRotateRows(b, i-1, i-1, c, s)
+			cRotateCols(q, i-1, n-1, c, s)
+
+			c, s, _ = cGivens(b.at(i, i), b.at(i, i-1))
+			cRotateCols(b, i-1, i, c, -s)
+			b.set(i, i-1, 0)
+			cRotateCols(a, i-1, n-1, c, -s)
+			cRotateCols(z, i-1, n-1, c, -s)
+		}
+	}
+}
+
+// cQZ computes the generalized Schur form
+//  (S, P) = (Qᴴ*H*Z, Qᴴ*T*Z)
+// of the upper Hessenberg-triangular pair (h, t) in place using the single-shift
+// QZ algorithm, so that on return h and t are upper triangular. The matrices q
+// and z are post-multiplied by the unitary transformations. cQZ returns whether
+// the iteration converged.
+func cQZ(h, t, q, z *CDense) bool {
+	const (
+		ulp = dlamchP
+		// Number of iterations after which an exceptional shift is used.
+		exceptional = 10
+	)
+	n := h.mat.Rows
+	var hnorm, tnorm float64
+	for i := 0; i < n; i++ {
+		for j := max(0, i-1); j < n; j++ {
+			hnorm = math.Max(hnorm, cabs1(h.at(i, j)))
+			tnorm = math.Max(tnorm, cabs1(t.at(i, j)))
+		}
+	}
+	htol := math.Max(dlamchS, ulp*hnorm)
+	ttol := math.Max(dlamchS, ulp*tnorm)
+
+	maxIter := 30 * max(10, n)
+	its := 0
+	for hi := n - 1; hi >= 0; {
+		// Look for a negligible subdiagonal element of H.
+		lo := 0
+		for k := hi; k > 0; k-- {
+			sub := cabs1(h.at(k, k-1))
+			if sub <= htol || sub <= ulp*(cabs1(h.at(k-1, k-1))+cabs1(h.at(k, k))) {
+				h.set(k, k-1, 0)
+				lo = k
+				break
+			}
+		}
+		if lo == hi {
+			// A 1×1 block has converged.
+			hi--
+			its = 0
+			continue
+		}
+
+		// Look for a negligible diagonal element of T, which
+		// corresponds to an infinite eigenvalue. The zero is chased
+		// to the bottom of the active block where it is deflated.
+		zero := -1
+		for k := lo; k <= hi; k++ {
+			if cabs1(t.at(k, k)) <= ttol {
+				t.set(k, k, 0)
+				zero = k
+				break
+			}
+		}
+		if zero >= 0 {
+			for k := zero; k < hi; k++ {
+				c, s, _ := cGivens(t.at(k, k+1), t.at(k+1, k+1))
+				cRotateRows(t, k, k+1, c, s)
+				t.set(k+1, k+1, 0)
+				cRotateRows(h, k, max(lo, k-1), c, s)
+				cRotateCols(q, k, n-1, c, s)
+				if k > lo {
+					c, s, _ = cGivens(h.at(k+1, k), h.at(k+1, k-1))
+					cRotateCols(h, k-1, k+1, c, -s)
+					h.set(k+1, k-1, 0)
+					cRotateCols(t, k-1, k, c, -s)
+					t.set(k, k-1, 0)
+					cRotateCols(z, k-1, n-1, c, -s)
+				}
+			}
+			c, s, _ := cGivens(h.at(hi, hi), h.at(hi, hi-1))
+			cRotateCols(h, hi-1, hi, c, -s)
+			h.set(hi, hi-1, 0)
+			cRotateCols(t, hi-1, hi, c, -s)
+			t.set(hi, hi-1, 0)
+			cRotateCols(z, hi-1, n-1, c, -s)
+			continue
+		}
+
+		if maxIter == 0 {
+			return false
+		}
+		maxIter--
+		its++
+
+		// Choose the shift.
+		var mu complex128
+		if its%exceptional == 0 {
+			mu = h.at(hi, hi)/t.at(hi, hi) + complex(0.75*cabs1(h.at(hi, hi-1)/t.at(hi-1, hi-1)), 0)
+		} else {
+			// The shift is the eigenvalue of the trailing 2×2 block of
+			// H*T⁻¹ closer to its last diagonal element.
+			t11, t12, t22 := t.at(hi-1, hi-1), t.at(hi-1, hi), t.at(hi, hi)
+			h11, h12 := h.at(hi-1, hi-1), h.at(hi-1, hi)
+			h21, h22 := h.at(hi, hi-1), h.at(hi, hi)
+			a := h11 / t11
+			b := (h12 - a*t12) / t22
+			c := h21 / t11
+			d := (h22 - c*t12) / t22
+			tr := (a - d) / 2
+			disc := cmplx.Sqrt(tr*tr + b*c)
+			if cabs1(tr-disc) < cabs1(tr+disc) {
+				disc = -disc
+			}
+			mu = d + tr - disc
+		}
+
+		// Chase the bulge down the active block.
+		x, y := h.at(lo, lo)-mu*t.at(lo, lo), h.at(lo+1, lo)
+		for k := lo; k < hi; k++ {
+			if k > lo {
+				x, y = h.at(k, k-1), h.at(k+1, k-1)
+			}
+			c, s, _ := cGivens(x, y)
+			cRotateRows(h, k, max(lo, k-1), c, s)
+			if k > lo {
+				h.set(k+1, k-1, 0)
+			}
+			cRotateRows(t, k, k, c, s)
+			cRotateCols(q, k, n-1, c, s)
+
+			c, s, _ = cGivens(t.at(k+1, k+1), t.at(k+1, k))
+			cRotateCols(t, k, k+1, c, -s)
+			t.set(k+1, k, 0)
+			cRotateCols(h, k, min(k+2, hi), c, -s)
+			cRotateCols(z, k, n-1, c, -s)
+		}
+	}
+	return true
+}
+
+// cGenSchurVectors returns the generalized eigenvectors of the pair
+// (U*S*Vᴴ, U*P*Vᴴ) where S and P are the n×n upper triangular matrices s and p.
+// If left is false, w must be V and the right eigenvectors are returned,
+// otherwise w must be U and the left eigenvectors are returned. The
+// eigenvectors are normalized to have Euclidean norm equal to 1 and largest
+// component real.
+func cGenSchurVectors(s, p, w *CDense, left bool) *CDense {
+	n := s.mat.Rows
+	var norm float64
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			norm = math.Max(norm, math.Max(cabs1(s.at(i, j)), cabs1(p.at(i, j))))
+		}
+	}
+	smin := math.Max(dlamchP*norm, dlamchS)
+
+	x := NewCDense(n, n, nil)
+	col := make([]complex128, n)
+	for k := 0; k < n; k++ {
+		// The eigenvalue is α/β, scaled so that max(|α|, |β|) = 1.
+		alpha, beta := s.at(k, k), p.at(k, k)
+		scale := math.Max(cabs1(alpha), cabs1(beta))
+		if scale == 0 {
+			// A singular pencil; any vector is an eigenvector.
+			x.set(k, k, 1)
+			continue
+		}
+		alpha /= complex(scale, 0)
+		beta /= complex(scale, 0)
+		// m returns the element of β*S - α*P.
+		m := func(i, j int) complex128 { return beta*s.at(i, j) - alpha*p.at(i, j) }
+
+		for i := range col {
+			col[i] = 0
+		}
+		col[k] = 1
+		if !left {
+			// Solve (β*S - α*P)*x = 0 with x[k] = 1 and x[i] = 0
+			// for i > k.
+			for i := k - 1; i >= 0; i-- {
+				var sum complex128
+				for j := i + 1; j <= k; j++ {
+					sum += m(i, j) * col[j]
+				}
+				d := m(i, i)
+				if cabs1(d) < smin {
+					d = complex(smin, 0)
+				}
+				col[i] = -sum / d
+			}
+		} else {
+			// Solve (β*S - α*P)ᴴ*y = 0 with y[k] = 1 and y[j] = 0
+			// for j < k.
+			for j := k + 1; j < n; j++ {
+				var sum complex128
+				for i := k; i < j; i++ {
+					sum += cmplx.Conj(m(i, j)) * col[i]
+				}
+				d := cmplx.Conj(m(j, j))
+				if cabs1(d) < smin {
+					d = complex(smin, 0)
+				}
+				col[j] = -sum / d
+			}
+		}
+		for i := 0; i < n; i++ {
+			x.set(i, k, col[i])
+		}
+	}
+	var v CDense
+	v.Mul(w, x)
+	cNormalizeColumns(&v)
+	return &v
+}
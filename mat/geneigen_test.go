@@ -0,0 +1,189 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestGenEigenSym(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 5, 10, 30} {
+		a := NewSymDense(n, nil)
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				a.SetSym(i, j, rnd.NormFloat64())
+			}
+		}
+		// B = Gᵀ*G + I is positive definite.
+		g := NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				g.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		b := NewSymDense(n, nil)
+		b.SymOuterK(1, g.T())
+		for i := 0; i < n; i++ {
+			b.SetSym(i, i, b.At(i, i)+1)
+		}
+
+		var ge GenEigenSym
+		if !ge.Factorize(a, b, true) {
+			t.Fatalf("n=%d: factorization failed", n)
+		}
+		values := ge.Values(nil)
+		if !sort.Float64sAreSorted(values) {
+			t.Errorf("n=%d: eigenvalues not in ascending order: %v", n, values)
+		}
+		var x Dense
+		ge.VectorsTo(&x)
+
+		var xbx Dense
+		xbx.Product(x.T(), b, &x)
+		eye := NewDiagDense(n, nil)
+		for i := 0; i < n; i++ {
+			eye.SetDiag(i, 1)
+		}
+		if !EqualApprox(&xbx, eye, 1e-10) {
+			t.Errorf("n=%d: eigenvectors not B-orthonormal", n)
+		}
+		var ax, bx Dense
+		ax.Mul(a, &x)
+		bx.Mul(b, &x)
+		for j := 0; j < n; j++ {
+			for i := 0; i < n; i++ {
+				bx.Set(i, j, bx.At(i, j)*values[j])
+			}
+		}
+		if !EqualApprox(&ax, &bx, 1e-10) {
+			t.Errorf("n=%d: A*X != B*X*Λ", n)
+		}
+	}
+
+	// B is not positive definite.
+	a := NewSymDense(2, []float64{1, 0, 0, 1})
+	b := NewSymDense(2, []float64{1, 0, 0, -1})
+	var ge GenEigenSym
+	if ge.Factorize(a, b, true) {
+		t.Errorf("expected failure for indefinite B")
+	}
+}
+
+func TestGenEigen(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 5, 10, 30} {
+		a := NewDense(n, n, nil)
+		b := NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				a.Set(i, j, rnd.NormFloat64())
+				b.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		testGenEigen(t, n, a, b)
+	}
+
+	// A singular B gives infinite eigenvalues.
+	a := NewDense(3, 3, []float64{
+		1, 2, 3,
+		4, 5, 6,
+		7, 8, 10,
+	})
+	b := NewDense(3, 3, []float64{
+		1, 0, 0,
+		0, 1, 0,
+		0, 0, 0,
+	})
+	values := testGenEigen(t, 3, a, b)
+	var inf int
+	for _, v := range values {
+		if cmplx.IsInf(v) {
+			inf++
+		}
+	}
+	if inf != 1 {
+		t.Errorf("unexpected number of infinite eigenvalues: got %d, want 1: %v", inf, values)
+	}
+
+	// With B = I the problem is a standard eigenvalue problem.
+	a = NewDense(4, 4, []float64{
+		0, 1, 0, 0,
+		-1, 0, 0, 0,
+		0, 0, 2, 1,
+		0, 0, 0, 3,
+	})
+	b = NewDense(4, 4, nil)
+	for i := 0; i < 4; i++ {
+		b.Set(i, i, 1)
+	}
+	var ge GenEigen
+	ge.Factorize(a, b, EigenNone)
+	got := ge.Values(nil)
+	var e Eigen
+	e.Factorize(a, EigenNone)
+	want := e.Values(nil)
+	for _, w := range want {
+		var found bool
+		for _, g := range got {
+			if cmplx.Abs(g-w) < 1e-12 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("eigenvalue %v not found in %v", w, got)
+		}
+	}
+	if ok, _ := panics(func() { ge.VectorsTo(&CDense{}) }); !ok {
+		t.Errorf("expected panic for eigenvectors not computed")
+	}
+}
+
+func testGenEigen(t *testing.T, n int, a, b *Dense) []complex128 {
+	var ge GenEigen
+	if !ge.Factorize(a, b, EigenBoth) {
+		t.Fatalf("n=%d: factorization failed", n)
+	}
+	values := ge.Values(nil)
+	alpha, beta := ge.AlphaBeta(nil, nil)
+	var vr, vl CDense
+	ge.VectorsTo(&vr)
+	ge.LeftVectorsTo(&vl)
+
+	ca, cb := realToC(a), realToC(b)
+	var avr, bvr, vla, vlb CDense
+	avr.Mul(ca, &vr)
+	bvr.Mul(cb, &vr)
+	vla.Mul(vl.H(), ca)
+	vlb.Mul(vl.H(), cb)
+	norm := math.Max(cMaxRowSum(ca), cMaxRowSum(cb))
+	for j := 0; j < n; j++ {
+		if beta[j] < 0 {
+			t.Errorf("n=%d: negative beta %v", n, beta[j])
+		}
+		scale := math.Max(cmplx.Abs(alpha[j]), beta[j])
+		al, be := alpha[j]/complex(scale, 0), complex(beta[j]/scale, 0)
+		// Check β*A*x_r = α*B*x_r and β*x_lᴴ*A = α*x_lᴴ*B.
+		for i := 0; i < n; i++ {
+			if d := cmplx.Abs(be*avr.at(i, j) - al*bvr.at(i, j)); d > 1e-12*norm*float64(n) {
+				t.Errorf("n=%d: right eigenpair %d: residual %v", n, j, d)
+				break
+			}
+			if d := cmplx.Abs(be*vla.at(j, i) - al*vlb.at(j, i)); d > 1e-12*norm*float64(n) {
+				t.Errorf("n=%d: left eigenpair %d: residual %v", n, j, d)
+				break
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,141 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+var (
+	indexed *Indexed
+	_       Matrix  = indexed
+	_       Mutable = indexed
+)
+
+// Indexed is a view of the elements of a matrix in selected rows and columns,
+// such as the training rows of a cross-validation fold or a subset of feature
+// columns. The elements are not copied, so changes to the underlying matrix
+// are reflected in the view, and Set alters the underlying matrix.
+type Indexed struct {
+	m Matrix
+
+	// rows and cols are the selected row and column indices of m.
+	// A nil slice selects all rows or columns in order.
+	rows, cols []int
+
+	// dense is m if it is a *Dense, allowing direct access to its data.
+	dense *Dense
+}
+
+// NewIndexed returns a view of the matrix a that has element a[rows[i],cols[j]]
+// at row i, column j. If rows or cols is nil, all the rows or columns of a are
+// selected in order. Indices may be repeated and in any order. The rows and
+// cols slices are used as the backing data of the view, so changes to their
+// elements are reflected in the returned Indexed. NewIndexed will panic with
+// ErrRowAccess or ErrColAccess if an index is out of range for a, and with
+// ErrZeroLength if rows or cols is empty but not nil.
+func NewIndexed(a Matrix, rows, cols []int) *Indexed {
+	r, c := a.Dims()
+	if (rows != nil && len(rows) == 0) || (cols != nil && len(cols) == 0) {
+		panic(ErrZeroLength)
+	}
+	for _, i := range rows {
+		if uint(i) >= uint(r) {
+			panic(ErrRowAccess)
+		}
+	}
+	for _, j := range cols {
+		if uint(j) >= uint(c) {
+			panic(ErrColAccess)
+		}
+	}
+	v := &Indexed{m: a, rows: rows, cols: cols}
+	v.dense, _ = a.(*Dense)
+	return v
+}
+
+// IndexRange returns the indices start, start+step, start+2*step, ... that
+// are less than end if step is positive or greater than end if step is
+// negative, for selecting a strided range of rows or columns with
+// NewIndexed. IndexRange will panic if step is zero.
+func IndexRange(start, end, step int) []int {
+	if step == 0 {
+		panic("mat: zero step")
+	}
+	var idx []int
+	for i := start; (step > 0 && i < end) || (step < 0 && i > end); i += step {
+		idx = append(idx, i)
+	}
+	return idx
+}
+
+// Dims returns the number of selected rows and columns.
+func (v *Indexed) Dims() (r, c int) {
+	r, c = v.m.Dims()
+	if v.rows != nil {
+		r = len(v.rows)
+	}
+	if v.cols != nil {
+		c = len(v.cols)
+	}
+	return r, c
+}
+
+// At returns the element at row i, column j of the view.
+func (v *Indexed) At(i, j int) float64 {
+	i, j = v.index(i, j)
+	if v.dense != nil {
+		return v.dense.at(i, j)
+	}
+	return v.m.At(i, j)
+}
+
+// Set sets the element at row i, column j of the view to x, altering the
+// underlying matrix. Set will panic if the underlying matrix is not Mutable or
+// the implicit transpose of a Mutable.
+func (v *Indexed) Set(i, j int, x float64) {
+	i, j = v.index(i, j)
+	if v.dense != nil {
+		v.dense.set(i, j, x)
+		return
+	}
+	switch m := v.m.(type) {
+	case Mutable:
+		m.Set(i, j, x)
+		return
+	case Untransposer:
+		if m, ok := m.Untranspose().(Mutable); ok {
+			m.Set(j, i, x)
+			return
+		}
+	}
+	panic("mat: underlying matrix is not mutable")
+}
+
+// index returns the indices into the underlying matrix of the element at
+// row i, column j of the view.
+func (v *Indexed) index(i, j int) (int, int) {
+	r, c := v.Dims()
+	if uint(i) >= uint(r) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(c) {
+		panic(ErrColAccess)
+	}
+	if v.rows != nil {
+		i = v.rows[i]
+	}
+	if v.cols != nil {
+		j = v.cols[j]
+	}
+	return i, j
+}
+
+// T performs an implicit transpose by returning the receiver inside a
+// Transpose.
+func (v *Indexed) T() Matrix {
+	return Transpose{v}
+}
+
+// Underlying returns the matrix viewed by the receiver.
+func (v *Indexed) Underlying() Matrix {
+	return v.m
+}
@@ -0,0 +1,119 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIndexed(t *testing.T) {
+	t.Parallel()
+	a := NewDense(4, 5, []float64{
+		0, 1, 2, 3, 4,
+		10, 11, 12, 13, 14,
+		20, 21, 22, 23, 24,
+		30, 31, 32, 33, 34,
+	})
+	for _, test := range []struct {
+		name       string
+		a          Matrix
+		rows, cols []int
+		want       *Dense
+	}{
+		{
+			name: "all",
+			a:    a,
+			want: a,
+		},
+		{
+			name: "rows",
+			a:    a,
+			rows: []int{3, 1, 1},
+			want: NewDense(3, 5, []float64{
+				30, 31, 32, 33, 34,
+				10, 11, 12, 13, 14,
+				10, 11, 12, 13, 14,
+			}),
+		},
+		{
+			name: "strided cols",
+			a:    a,
+			cols: IndexRange(0, 5, 2),
+			want: NewDense(4, 3, []float64{
+				0, 2, 4,
+				10, 12, 14,
+				20, 22, 24,
+				30, 32, 34,
+			}),
+		},
+		{
+			name: "transpose",
+			a:    a.T(),
+			rows: []int{4},
+			cols: []int{0, 3},
+			want: NewDense(1, 2, []float64{4, 34}),
+		},
+	} {
+		v := NewIndexed(test.a, test.rows, test.cols)
+		if !Equal(v, test.want) {
+			t.Errorf("%s: unexpected view:\ngot:\n%v\nwant:\n%v", test.name, Formatted(v), Formatted(test.want))
+		}
+		var prod, want Dense
+		prod.Mul(v, v.T())
+		want.Mul(test.want, test.want.T())
+		if !Equal(&prod, &want) {
+			t.Errorf("%s: unexpected product of view", test.name)
+		}
+	}
+
+	b := DenseCopyOf(a)
+	v := NewIndexed(b, []int{2, 0}, []int{4, 1})
+	v.Set(0, 0, -1)
+	v.Set(1, 1, -2)
+	if b.At(2, 4) != -1 || b.At(0, 1) != -2 {
+		t.Errorf("Set did not alter the underlying matrix")
+	}
+	tv := NewIndexed(b.T(), []int{4}, []int{2})
+	tv.Set(0, 0, -3)
+	if b.At(2, 4) != -3 {
+		t.Errorf("Set did not alter the underlying transposed matrix")
+	}
+	if v.Underlying() != Matrix(b) {
+		t.Errorf("unexpected underlying matrix")
+	}
+
+	for _, fn := range []func(){
+		func() { NewIndexed(a, []int{4}, nil) },
+		func() { NewIndexed(a, nil, []int{-1}) },
+		func() { NewIndexed(a, []int{}, nil) },
+		func() { v.At(2, 0) },
+		func() { v.At(0, 2) },
+		func() { NewIndexed(NewDiagDense(2, nil), nil, nil).Set(0, 1, 1) },
+		func() { IndexRange(0, 1, 0) },
+	} {
+		if ok, _ := panics(fn); !ok {
+			t.Errorf("expected panic")
+		}
+	}
+}
+
+func TestIndexRange(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		start, end, step int
+		want             []int
+	}{
+		{start: 0, end: 5, step: 1, want: []int{0, 1, 2, 3, 4}},
+		{start: 1, end: 8, step: 3, want: []int{1, 4, 7}},
+		{start: 5, end: 0, step: -2, want: []int{5, 3, 1}},
+		{start: 3, end: 3, step: 1, want: nil},
+	} {
+		got := IndexRange(test.start, test.end, test.step)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("unexpected IndexRange(%d, %d, %d): got %v want %v", test.start, test.end, test.step, got, test.want)
+		}
+	}
+}
@@ -29,6 +29,7 @@ var (
 	errTooSmall  = errors.New("mat: input slice too small")
 	errBadBuffer = errors.New("mat: data buffer size mismatch")
 	errBadSize   = errors.New("mat: invalid dimension")
+	errBadPivot  = errors.New("mat: invalid pivot index")
 )
 
 // Type encoding scheme:
@@ -424,6 +425,205 @@ func (v *VecDense) UnmarshalBinaryFrom(r io.Reader) (int, error) {
 	return n, nil
 }
 
+// MarshalBinary encodes the receiver into a binary form and returns the result.
+//
+// SymDense is little-endian encoded as follows, storing only the elements of
+// the upper triangle:
+//   0 -  3  Version = 1          (uint32)
+//   4       'S'                  (byte)
+//   5       'P'                  (byte)
+//   6       'U'                  (byte)
+//   7       0                    (byte)
+//   8 - 15  n                    (int64)
+//  16 - 23  n                    (int64)
+//  24 - 31  0                    (int64)
+//  32 - 39  0                    (int64)
+//  40 - ..  matrix data elements (float64)
+//           [0,0] [0,1] ... [0,n-1]
+//           [1,1] [1,2] ... [1,n-1]
+//           ...
+//           [n-1,n-1]
+func (s SymDense) MarshalBinary() ([]byte, error) {
+	header := storage{
+		Form: 'S', Packing: 'P', Uplo: 'U',
+		Rows: int64(s.mat.N), Cols: int64(s.mat.N),
+		Version: version,
+	}
+	return marshalPacked(header, true, s.at)
+}
+
+// UnmarshalBinary decodes the binary form into the receiver.
+// It panics if the receiver is a non-empty SymDense matrix.
+//
+// See MarshalBinary for the on-disk layout. The checks on the validity of the
+// binary input are those of Dense.UnmarshalBinary. UnmarshalBinary does not
+// limit the size of the unmarshaled matrix, and so it should not be used on
+// untrusted data.
+func (s *SymDense) UnmarshalBinary(data []byte) error {
+	if !s.IsEmpty() {
+		panic("mat: unmarshal into non-empty matrix")
+	}
+	n, err := unmarshalPackedHeader(data, storage{Form: 'S', Packing: 'P', Uplo: 'U'})
+	if err != nil {
+		return err
+	}
+	s.ReuseAsSym(n)
+	p := headerSize
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			s.mat.Data[i*s.mat.Stride+j] = math.Float64frombits(binary.LittleEndian.Uint64(data[p : p+sizeFloat64]))
+			p += sizeFloat64
+		}
+	}
+	return nil
+}
+
+// MarshalBinary encodes the receiver into a binary form and returns the result.
+//
+// TriDense is little-endian encoded as follows, storing only the elements of
+// the triangle:
+//   0 -  3  Version = 1          (uint32)
+//   4       'T'                  (byte)
+//   5       'P'                  (byte)
+//   6       'U' or 'L'           (byte)
+//   7       0                    (byte)
+//   8 - 15  n                    (int64)
+//  16 - 23  n                    (int64)
+//  24 - 31  0                    (int64)
+//  32 - 39  0                    (int64)
+//  40 - ..  matrix data elements (float64)
+//           for an upper triangular matrix
+//           [0,0] [0,1] ... [0,n-1]
+//           [1,1] [1,2] ... [1,n-1]
+//           ...
+//           [n-1,n-1]
+//           and for a lower triangular matrix
+//           [0,0]
+//           [1,0] [1,1]
+//           ...
+//           [n-1,0] ... [n-1,n-1]
+func (t TriDense) MarshalBinary() ([]byte, error) {
+	upper := t.isUpper()
+	uplo := byte('L')
+	if upper {
+		uplo = 'U'
+	}
+	header := storage{
+		Form: 'T', Packing: 'P', Uplo: uplo,
+		Rows: int64(t.mat.N), Cols: int64(t.mat.N),
+		Version: version,
+	}
+	return marshalPacked(header, upper, t.at)
+}
+
+// UnmarshalBinary decodes the binary form into the receiver.
+// It panics if the receiver is a non-empty TriDense matrix.
+//
+// See MarshalBinary for the on-disk layout. The checks on the validity of the
+// binary input are those of Dense.UnmarshalBinary. UnmarshalBinary does not
+// limit the size of the unmarshaled matrix, and so it should not be used on
+// untrusted data.
+func (t *TriDense) UnmarshalBinary(data []byte) error {
+	if !t.IsEmpty() {
+		panic("mat: unmarshal into non-empty matrix")
+	}
+	if len(data) < headerSize {
+		return errTooSmall
+	}
+	kind := Upper
+	want := storage{Form: 'T', Packing: 'P', Uplo: 'U'}
+	if data[6] == 'L' {
+		kind = Lower
+		want.Uplo = 'L'
+	}
+	n, err := unmarshalPackedHeader(data, want)
+	if err != nil {
+		return err
+	}
+	t.ReuseAsTri(n, kind)
+	p := headerSize
+	for i := 0; i < n; i++ {
+		lo, hi := i, n
+		if kind == Lower {
+			lo, hi = 0, i+1
+		}
+		for j := lo; j < hi; j++ {
+			t.mat.Data[i*t.mat.Stride+j] = math.Float64frombits(binary.LittleEndian.Uint64(data[p : p+sizeFloat64]))
+			p += sizeFloat64
+		}
+	}
+	return nil
+}
+
+// marshalPacked returns the binary form of the n×n matrix described by header
+// with the upper or lower triangle of elements returned by at stored row-wise.
+func marshalPacked(header storage, upper bool, at func(i, j int) float64) ([]byte, error) {
+	n := header.Rows
+	bufLen := int64(headerSize) + n*(n+1)/2*int64(sizeFloat64)
+	if bufLen <= 0 {
+		// bufLen is too big and has wrapped around.
+		return nil, errTooBig
+	}
+
+	buf := make([]byte, bufLen)
+	nn, err := header.marshalBinaryTo(bytes.NewBuffer(buf[:0]))
+	if err != nil {
+		return buf[:nn], err
+	}
+
+	p := headerSize
+	for i := 0; i < int(n); i++ {
+		lo, hi := i, int(n)
+		if !upper {
+			lo, hi = 0, i+1
+		}
+		for j := lo; j < hi; j++ {
+			binary.LittleEndian.PutUint64(buf[p:p+sizeFloat64], math.Float64bits(at(i, j)))
+			p += sizeFloat64
+		}
+	}
+
+	return buf, nil
+}
+
+// unmarshalPackedHeader decodes and checks the header of the binary form of a
+// packed n×n matrix with the type described by want, and checks the length of
+// data. It returns n.
+func unmarshalPackedHeader(data []byte, want storage) (int, error) {
+	if len(data) < headerSize {
+		return 0, errTooSmall
+	}
+
+	var header storage
+	err := header.unmarshalBinary(data[:headerSize])
+	if err != nil {
+		return 0, err
+	}
+	rows := header.Rows
+	cols := header.Cols
+	header.Version = 0
+	header.Rows = 0
+	header.Cols = 0
+	if header != want {
+		return 0, errWrongType
+	}
+	if rows < 0 || rows != cols {
+		return 0, errBadSize
+	}
+	if rows == 0 {
+		return 0, ErrZeroLength
+	}
+	// The matrix is stored in full.
+	size := rows * rows
+	if size/rows != rows || int(size) < 0 || size > maxLen {
+		return 0, errTooBig
+	}
+	if len(data) != headerSize+int(rows*(rows+1)/2)*sizeFloat64 {
+		return 0, errBadBuffer
+	}
+	return int(rows), nil
+}
+
 // storage is the internal representation of the storage format of a
 // serialised matrix.
 type storage struct {
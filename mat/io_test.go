@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"encoding"
 	"encoding/binary"
+	"encoding/gob"
 	"io"
 	"io/ioutil"
 	"math"
@@ -21,6 +22,16 @@ var (
 	_ encoding.BinaryUnmarshaler = (*Dense)(nil)
 	_ encoding.BinaryMarshaler   = (*VecDense)(nil)
 	_ encoding.BinaryUnmarshaler = (*VecDense)(nil)
+	_ encoding.BinaryMarshaler   = (*SymDense)(nil)
+	_ encoding.BinaryUnmarshaler = (*SymDense)(nil)
+	_ encoding.BinaryMarshaler   = (*TriDense)(nil)
+	_ encoding.BinaryUnmarshaler = (*TriDense)(nil)
+	_ encoding.BinaryMarshaler   = (*Cholesky)(nil)
+	_ encoding.BinaryUnmarshaler = (*Cholesky)(nil)
+	_ encoding.BinaryMarshaler   = (*LU)(nil)
+	_ encoding.BinaryUnmarshaler = (*LU)(nil)
+	_ encoding.BinaryMarshaler   = (*QR)(nil)
+	_ encoding.BinaryUnmarshaler = (*QR)(nil)
 )
 
 var sizeInt64 = binary.Size(int64(0))
@@ -582,6 +593,259 @@ func TestVecDenseIORoundTrip(t *testing.T) {
 	}
 }
 
+func TestPackedIORoundTrip(t *testing.T) {
+	t.Parallel()
+	for _, n := range []int{1, 2, 5} {
+		sym := NewSymDense(n, nil)
+		upper := NewTriDense(n, Upper, nil)
+		lower := NewTriDense(n, Lower, nil)
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				sym.SetSym(i, j, float64(i*n+j)+0.5)
+				upper.SetTri(i, j, float64(i*n+j)-0.25)
+				lower.SetTri(j, i, float64(j*n+i)+0.125)
+			}
+		}
+
+		buf, err := sym.MarshalBinary()
+		if err != nil {
+			t.Fatalf("n=%d: unexpected error encoding SymDense: %v", n, err)
+		}
+		if len(buf) != headerSize+n*(n+1)/2*sizeFloat64 {
+			t.Errorf("n=%d: unexpected SymDense encoding length %d", n, len(buf))
+		}
+		var gotSym SymDense
+		err = gotSym.UnmarshalBinary(buf)
+		if err != nil {
+			t.Fatalf("n=%d: unexpected error decoding SymDense: %v", n, err)
+		}
+		if !Equal(&gotSym, sym) {
+			t.Errorf("n=%d: SymDense round trip failed:\ngot:\n%v\nwant:\n%v", n, Formatted(&gotSym), Formatted(sym))
+		}
+		var d Dense
+		if err := d.UnmarshalBinary(buf); err != errWrongType {
+			t.Errorf("n=%d: unexpected error decoding SymDense into Dense: %v", n, err)
+		}
+
+		for _, tri := range []*TriDense{upper, lower} {
+			buf, err := tri.MarshalBinary()
+			if err != nil {
+				t.Fatalf("n=%d: unexpected error encoding TriDense: %v", n, err)
+			}
+			var got TriDense
+			err = got.UnmarshalBinary(buf)
+			if err != nil {
+				t.Fatalf("n=%d: unexpected error decoding TriDense: %v", n, err)
+			}
+			_, kind := tri.Triangle()
+			if _, gotKind := got.Triangle(); gotKind != kind || !Equal(&got, tri) {
+				t.Errorf("n=%d: TriDense round trip failed:\ngot:\n%v\nwant:\n%v", n, Formatted(&got), Formatted(tri))
+			}
+			var s SymDense
+			if err := s.UnmarshalBinary(buf); err != errWrongType {
+				t.Errorf("n=%d: unexpected error decoding TriDense into SymDense: %v", n, err)
+			}
+			var short TriDense
+			if err := short.UnmarshalBinary(buf[:len(buf)-1]); err != errBadBuffer {
+				t.Errorf("n=%d: unexpected error for short buffer: %v", n, err)
+			}
+		}
+	}
+}
+
+func TestCholeskyIORoundTrip(t *testing.T) {
+	t.Parallel()
+	a := NewSymDense(3, []float64{
+		4, 1, 1,
+		0, 2, 3,
+		0, 0, 6,
+	})
+	var chol Cholesky
+	if !chol.Factorize(a) {
+		t.Fatal("unexpected Cholesky factorization failure")
+	}
+	buf, err := chol.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error encoding Cholesky: %v", err)
+	}
+	var got Cholesky
+	err = got.UnmarshalBinary(buf)
+	if err != nil {
+		t.Fatalf("unexpected error decoding Cholesky: %v", err)
+	}
+	var gotA SymDense
+	got.ToSym(&gotA)
+	if !EqualApprox(&gotA, a, 1e-14) {
+		t.Errorf("Cholesky round trip failed:\ngot:\n%v\nwant:\n%v", Formatted(&gotA), Formatted(a))
+	}
+
+	var empty Cholesky
+	if _, err := empty.MarshalBinary(); err == nil {
+		t.Errorf("expected error encoding empty Cholesky")
+	}
+	lower, _ := NewTriDense(3, Lower, []float64{1, 0, 0, 1, 1, 0, 1, 1, 1}).MarshalBinary()
+	if err := empty.UnmarshalBinary(lower); err != errWrongType {
+		t.Errorf("unexpected error decoding lower triangle into Cholesky: %v", err)
+	}
+}
+
+func TestLUIORoundTrip(t *testing.T) {
+	t.Parallel()
+	a := NewDense(3, 3, []float64{
+		1, 2, 3,
+		4, 5, 6,
+		7, 8, 10,
+	})
+	var lu LU
+	lu.Factorize(a)
+	buf, err := lu.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error encoding LU: %v", err)
+	}
+	var got LU
+	err = got.UnmarshalBinary(buf)
+	if err != nil {
+		t.Fatalf("unexpected error decoding LU: %v", err)
+	}
+	if got.Cond() != lu.Cond() {
+		t.Errorf("unexpected condition number: got %v want %v", got.Cond(), lu.Cond())
+	}
+	if got.Det() != lu.Det() {
+		t.Errorf("unexpected determinant: got %v want %v", got.Det(), lu.Det())
+	}
+	var l TriDense
+	var u TriDense
+	var p, gotA Dense
+	got.LTo(&l)
+	got.UTo(&u)
+	p.Permutation(3, got.Pivot(nil))
+	gotA.Product(&p, &l, &u)
+	if !EqualApprox(&gotA, a, 1e-14) {
+		t.Errorf("LU round trip failed:\ngot:\n%v\nwant:\n%v", Formatted(&gotA), Formatted(a))
+	}
+
+	var empty LU
+	if _, err := empty.MarshalBinary(); err == nil {
+		t.Errorf("expected error encoding empty LU")
+	}
+	bad := append([]byte(nil), buf...)
+	binary.LittleEndian.PutUint64(bad[len(bad)-2*sizeInt64:], 0)
+	if err := empty.UnmarshalBinary(bad); err != errBadPivot {
+		t.Errorf("unexpected error decoding invalid pivot: %v", err)
+	}
+	if err := empty.UnmarshalBinary(buf[:len(buf)-1]); err != errBadBuffer {
+		t.Errorf("unexpected error decoding short buffer: %v", err)
+	}
+	rect, _ := NewDense(3, 2, nil).MarshalBinary()
+	rect = append(rect, make([]byte, 4*sizeInt64)...)
+	if err := empty.UnmarshalBinary(rect); err != errBadSize {
+		t.Errorf("unexpected error decoding non-square factors: %v", err)
+	}
+}
+
+func TestQRIORoundTrip(t *testing.T) {
+	t.Parallel()
+	a := NewDense(4, 3, []float64{
+		1, 2, 3,
+		4, 5, 6,
+		7, 8, 10,
+		1, 0, 1,
+	})
+	for _, updated := range []bool{false, true} {
+		var qr QR
+		qr.Factorize(a)
+		want := a
+		if updated {
+			// Updating the factorization forms Q explicitly.
+			qr.InsertRow(1, NewVecDense(3, []float64{2, -1, 1}))
+			want = NewDense(5, 3, []float64{
+				1, 2, 3,
+				2, -1, 1,
+				4, 5, 6,
+				7, 8, 10,
+				1, 0, 1,
+			})
+		}
+		buf, err := qr.MarshalBinary()
+		if err != nil {
+			t.Fatalf("updated=%t: unexpected error encoding QR: %v", updated, err)
+		}
+		var got QR
+		err = got.UnmarshalBinary(buf)
+		if err != nil {
+			t.Fatalf("updated=%t: unexpected error decoding QR: %v", updated, err)
+		}
+		if got.Cond() != qr.Cond() {
+			t.Errorf("updated=%t: unexpected condition number: got %v want %v", updated, got.Cond(), qr.Cond())
+		}
+		var q, r, gotA Dense
+		got.QTo(&q)
+		got.RTo(&r)
+		gotA.Mul(&q, &r)
+		if !EqualApprox(&gotA, want, 1e-13) {
+			t.Errorf("updated=%t: QR round trip failed:\ngot:\n%v\nwant:\n%v", updated, Formatted(&gotA), Formatted(want))
+		}
+		if err := new(QR).UnmarshalBinary(buf[:len(buf)-1]); err == nil {
+			t.Errorf("updated=%t: expected error decoding short buffer", updated)
+		}
+	}
+
+	var empty QR
+	if _, err := empty.MarshalBinary(); err == nil {
+		t.Errorf("expected error encoding empty QR")
+	}
+	wide, _ := NewDense(2, 3, nil).MarshalBinary()
+	if err := empty.UnmarshalBinary(wide); err != errBadSize {
+		t.Errorf("unexpected error decoding wide factor: %v", err)
+	}
+	tall, _ := NewDense(3, 2, nil).MarshalBinary()
+	tall = append(tall, make([]byte, sizeInt64)...)
+	tall = append(tall, 'X')
+	if err := empty.UnmarshalBinary(tall); err != errWrongType {
+		t.Errorf("unexpected error decoding unknown form of Q: %v", err)
+	}
+}
+
+func TestGobRoundTrip(t *testing.T) {
+	t.Parallel()
+	type record struct {
+		Name string
+		A    *Dense
+		S    *SymDense
+		V    *VecDense
+		C    *Cholesky
+	}
+	s := NewSymDense(2, []float64{2, 1, 1, 2})
+	var chol Cholesky
+	chol.Factorize(s)
+	want := record{
+		Name: "gob",
+		A:    NewDense(2, 3, []float64{1, 2, 3, 4, 5, 6}),
+		S:    s,
+		V:    NewVecDense(3, []float64{7, 8, 9}),
+		C:    &chol,
+	}
+
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(want)
+	if err != nil {
+		t.Fatalf("unexpected error encoding: %v", err)
+	}
+	var got record
+	err = gob.NewDecoder(&buf).Decode(&got)
+	if err != nil {
+		t.Fatalf("unexpected error decoding: %v", err)
+	}
+	if got.Name != want.Name || !Equal(got.A, want.A) || !Equal(got.S, want.S) || !Equal(got.V, want.V) {
+		t.Errorf("gob round trip failed:\ngot: %+v\nwant:%+v", got, want)
+	}
+	var gotS SymDense
+	got.C.ToSym(&gotS)
+	if !EqualApprox(&gotS, s, 1e-14) {
+		t.Errorf("gob round trip of Cholesky failed")
+	}
+}
+
 func BenchmarkMarshalDense10(b *testing.B)    { marshalBinaryBenchDense(b, 10) }
 func BenchmarkMarshalDense100(b *testing.B)   { marshalBinaryBenchDense(b, 100) }
 func BenchmarkMarshalDense1000(b *testing.B)  { marshalBinaryBenchDense(b, 1000) }
@@ -0,0 +1,241 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+)
+
+const (
+	badLanczos     = "mat: invalid Lanczos factorization"
+	badLanczosRank = "mat: number of eigenpairs out of range"
+
+	// lanczosTol is the tolerance on the residual norm of a Ritz pair,
+	// relative to the largest Ritz value, for the pair to be accepted.
+	lanczosTol = 1e-10
+
+	// dlamchE is the machine epsilon.
+	dlamchE = 1.0 / (1 << 53)
+)
+
+// SymOperator is a symmetric linear operator that is only accessed through
+// its products with vectors.
+type SymOperator interface {
+	// Symmetric returns the number of rows and columns of the operator.
+	Symmetric() int
+
+	// MulVecTo computes A⋅x storing the result into dst. Since A is
+	// symmetric, trans has no effect on the result.
+	MulVecTo(dst *VecDense, trans bool, x Vector)
+}
+
+var (
+	_ SymOperator = (*SymDense)(nil)
+	_ SymOperator = (*SymBandDense)(nil)
+)
+
+// LanczosSym is a type for computing a few of the largest or smallest
+// eigenvalues and the corresponding eigenvectors of a symmetric operator.
+//
+// Only products of the operator with vectors are formed, so LanczosSym is
+// suited to large sparse or implicitly defined operators for which only a
+// small number of eigenpairs are required, for example in spectral clustering
+// or principal component analysis.
+type LanczosSym struct {
+	values  []float64
+	vectors *Dense
+	iter    int
+}
+
+// Factorize computes the k largest eigenvalues of the n×n symmetric operator a
+// if largest is true, and the k smallest eigenvalues otherwise, together with
+// the corresponding eigenvectors if vectors is true. k must be in [1, n],
+// otherwise Factorize will panic.
+//
+// The eigenpairs are approximated by the Ritz pairs of a Krylov subspace
+// built by the Lanczos process with full reorthogonalization, starting from a
+// Gaussian random vector drawn from src. The subspace is expanded until the
+// residuals of the wanted Ritz pairs are small relative to the largest Ritz
+// value, or until it spans the whole space. If src is nil, the global random
+// source is used. As with all single vector Krylov methods, eigenvalues of
+// high multiplicity may not be found with their full multiplicity.
+//
+// The memory required by Factorize is proportional to n times the dimension of
+// the subspace, which is typically a small multiple of k when the wanted
+// eigenvalues are well separated from the rest of the spectrum.
+//
+// Factorize returns whether the decomposition succeeded. If the decomposition
+// failed, methods that require a successful factorization will panic.
+func (l *LanczosSym) Factorize(a SymOperator, k int, largest, vectors bool, src rand.Source) (ok bool) {
+	n := a.Symmetric()
+	if k < 1 || n < k {
+		panic(badLanczosRank)
+	}
+	l.Reset()
+
+	normFloat64 := rand.NormFloat64
+	if src != nil {
+		normFloat64 = rand.New(src).NormFloat64
+	}
+
+	// The Lanczos vectors are stored in the rows of q.
+	q := blas64.General{Rows: 0, Cols: n, Stride: n}
+	var alpha, beta []float64
+	h := make([]float64, n)
+	w := NewVecDense(n, nil)
+	var ritz EigenSym
+	for m := 1; m <= n; m++ {
+		if m == 1 || beta[m-2] == 0 {
+			// Start a new Krylov subspace with a random vector
+			// orthogonal to the current basis.
+			for {
+				for i := range w.mat.Data {
+					w.mat.Data[i] = normFloat64()
+				}
+				nrm := blas64.Nrm2(w.mat)
+				if lanczosOrthogonalize(q, w.mat, h) > 0.5*nrm {
+					break
+				}
+			}
+		}
+		v := w.mat.Data
+		blas64.Scal(1/blas64.Nrm2(w.mat), w.mat)
+		q.Data = append(q.Data, v...)
+		q.Rows = m
+		qj := blas64.Vector{N: n, Data: q.Data[(m-1)*n:], Inc: 1}
+
+		a.MulVecTo(w, false, NewVecDense(n, qj.Data[:n]))
+		alpha = append(alpha, blas64.Dot(qj, w.mat))
+		b := lanczosOrthogonalize(q, w.mat, h)
+
+		t := NewSymDense(m, nil)
+		for i, v := range alpha {
+			t.set(i, i, v)
+		}
+		for i, v := range beta {
+			t.set(i, i+1, v)
+		}
+		if !ritz.Factorize(t, true) {
+			return false
+		}
+		anorm := math.Max(math.Abs(ritz.values[0]), math.Abs(ritz.values[m-1]))
+		if b <= dlamchE*anorm {
+			b = 0
+		}
+		beta = append(beta, b)
+		if m < k {
+			continue
+		}
+
+		off := 0
+		if largest {
+			off = m - k
+		}
+		converged := true
+		for i := off; i < off+k; i++ {
+			if b*math.Abs(ritz.vectors.At(m-1, i)) > lanczosTol*anorm {
+				converged = false
+				break
+			}
+		}
+		if !converged && m < n {
+			continue
+		}
+
+		l.iter = m
+		l.values = make([]float64, k)
+		copy(l.values, ritz.values[off:off+k])
+		if vectors {
+			l.vectors = NewDense(n, k, nil)
+			s := ritz.vectors.Slice(0, m, off, off+k).(*Dense)
+			blas64.Gemm(blas.Trans, blas.NoTrans, 1, q, s.mat, 0, l.vectors.mat)
+		}
+		return true
+	}
+	panic("unreachable")
+}
+
+// lanczosOrthogonalize orthogonalizes v against the rows of q using classical
+// Gram-Schmidt applied twice, and returns the norm of the result. h is used as
+// workspace and must have length at least q.Rows.
+func lanczosOrthogonalize(q blas64.General, v blas64.Vector, h []float64) float64 {
+	if q.Rows == 0 {
+		return blas64.Nrm2(v)
+	}
+	hv := blas64.Vector{N: q.Rows, Data: h[:q.Rows], Inc: 1}
+	for i := 0; i < 2; i++ {
+		blas64.Gemv(blas.NoTrans, 1, q, v, 0, hv)
+		blas64.Gemv(blas.Trans, -1, q, hv, 1, v)
+	}
+	return blas64.Nrm2(v)
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (l *LanczosSym) isValid() bool {
+	return len(l.values) != 0
+}
+
+// Reset resets the factorization so that it can be reused as the receiver of a
+// dimensionally restricted operation.
+func (l *LanczosSym) Reset() {
+	l.values = nil
+	l.vectors = nil
+	l.iter = 0
+}
+
+// Iterations returns the dimension of the Krylov subspace from which the
+// eigenpairs were extracted, which is the number of products of the operator
+// with a vector that were formed. Iterations will panic if the receiver does
+// not contain a successful factorization.
+func (l *LanczosSym) Iterations() int {
+	if !l.isValid() {
+		panic(badLanczos)
+	}
+	return l.iter
+}
+
+// Values returns the k computed eigenvalues in ascending order.
+//
+// If the input slice is non-nil, the values will be stored in-place into
+// the slice. In this case, the slice must have length k, and Values will
+// panic with ErrSliceLengthMismatch otherwise. If the input slice is nil, a new
+// slice of the appropriate length will be allocated and returned.
+//
+// Values will panic if the receiver does not contain a successful factorization.
+func (l *LanczosSym) Values(dst []float64) []float64 {
+	if !l.isValid() {
+		panic(badLanczos)
+	}
+	if dst == nil {
+		dst = make([]float64, len(l.values))
+	}
+	if len(dst) != len(l.values) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(dst, l.values)
+	return dst
+}
+
+// VectorsTo stores the n×k matrix of orthonormal eigenvectors corresponding to
+// the eigenvalues returned from Values into the columns of dst.
+//
+// If dst is empty, VectorsTo will resize dst to be n×k. When dst is
+// non-empty, VectorsTo will panic if dst is not n×k. VectorsTo will also
+// panic if the eigenvectors were not computed during the factorization,
+// or if the receiver does not contain a successful factorization.
+func (l *LanczosSym) VectorsTo(dst *Dense) {
+	if !l.isValid() {
+		panic(badLanczos)
+	}
+	if l.vectors == nil {
+		panic(noVectors)
+	}
+	truncSVDVectorsTo(dst, l.vectors)
+}
@@ -0,0 +1,144 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestLanczosSym(t *testing.T) {
+	t.Parallel()
+	src := rand.NewSource(1)
+	ones := make([]float64, 30)
+	for i := range ones {
+		ones[i] = 1
+	}
+	for _, test := range []struct {
+		name string
+		a    SymOperator
+	}{
+		{name: "random", a: randSymDense(40, src)},
+		{name: "identity", a: NewSymBandDense(30, 0, ones)},
+		{name: "band", a: NewSymBandDense(50, 1, lanczosLaplacian(50))},
+		{name: "small", a: randSymDense(3, src)},
+	} {
+		n := test.a.Symmetric()
+		dense := NewSymDense(n, nil)
+		for j := 0; j < n; j++ {
+			e := NewVecDense(n, nil)
+			e.SetVec(j, 1)
+			var col VecDense
+			test.a.MulVecTo(&col, false, e)
+			for i := 0; i <= j; i++ {
+				dense.SetSym(i, j, col.AtVec(i))
+			}
+		}
+		var es EigenSym
+		if !es.Factorize(dense, false) {
+			t.Fatalf("%s: EigenSym factorization failed", test.name)
+		}
+		all := es.Values(nil)
+		anorm := math.Max(math.Abs(all[0]), math.Abs(all[n-1]))
+
+		for _, k := range []int{1, 3, n} {
+			if k > n {
+				continue
+			}
+			for _, largest := range []bool{false, true} {
+				var l LanczosSym
+				ok := l.Factorize(test.a, k, largest, true, rand.NewSource(2))
+				if !ok {
+					t.Errorf("%s: k=%d largest=%t: unexpected factorization failure", test.name, k, largest)
+					continue
+				}
+				got := l.Values(nil)
+				want := all[:k]
+				if largest {
+					want = all[n-k:]
+				}
+				if !floats.EqualApprox(got, want, 1e-8*anorm) {
+					t.Errorf("%s: k=%d largest=%t: unexpected values:\ngot  %v\nwant %v", test.name, k, largest, got, want)
+				}
+
+				var vecs Dense
+				l.VectorsTo(&vecs)
+				var qtq Dense
+				qtq.Mul(vecs.T(), &vecs)
+				if !EqualApprox(&qtq, eye(k), 1e-10) {
+					t.Errorf("%s: k=%d largest=%t: eigenvectors not orthonormal", test.name, k, largest)
+				}
+				var av, vd Dense
+				av.Mul(dense, &vecs)
+				vd.Mul(&vecs, NewDiagDense(k, got))
+				if !EqualApprox(&av, &vd, 1e-6*anorm) {
+					t.Errorf("%s: k=%d largest=%t: A*V != V*D", test.name, k, largest)
+				}
+				if l.Iterations() > n {
+					t.Errorf("%s: k=%d largest=%t: too many iterations: %d", test.name, k, largest, l.Iterations())
+				}
+			}
+		}
+	}
+
+	var l LanczosSym
+	if !l.Factorize(NewSymDense(4, []float64{
+		4, 1, 0, 0,
+		1, 3, 0, 0,
+		0, 0, 2, 0,
+		0, 0, 0, 1,
+	}), 2, true, false, nil) {
+		t.Fatal("unexpected factorization failure")
+	}
+	panicked, message := panics(func() { l.VectorsTo(&Dense{}) })
+	if !panicked || message != noVectors {
+		t.Errorf("expected panic %q when vectors not computed, got %q", noVectors, message)
+	}
+	panicked, message = panics(func() { l.Factorize(NewSymDense(4, nil), 5, true, false, nil) })
+	if !panicked || message != badLanczosRank {
+		t.Errorf("expected panic %q for k > n, got %q", badLanczosRank, message)
+	}
+}
+
+func TestLanczosSymIterations(t *testing.T) {
+	t.Parallel()
+	// The extreme eigenvalues of a large matrix with a few well separated
+	// eigenvalues are found in many fewer iterations than its order.
+	const n = 500
+	rnd := rand.New(rand.NewSource(1))
+	d := make([]float64, n)
+	for i := range d {
+		d[i] = rnd.Float64()
+	}
+	d[0], d[1], d[2] = 10, 20, 30
+	a := NewSymBandDense(n, 0, d)
+	var l LanczosSym
+	if !l.Factorize(a, 3, true, true, rand.NewSource(1)) {
+		t.Fatal("unexpected factorization failure")
+	}
+	got := l.Values(nil)
+	if !floats.EqualApprox(got, []float64{10, 20, 30}, 1e-8) {
+		t.Errorf("unexpected values: got %v", got)
+	}
+	if l.Iterations() > n/4 {
+		t.Errorf("unexpectedly many iterations: %d", l.Iterations())
+	}
+}
+
+// lanczosLaplacian returns the upper band storage of the n×n one-dimensional
+// discrete Laplacian.
+func lanczosLaplacian(n int) []float64 {
+	data := make([]float64, 2*n)
+	for i := 0; i < n; i++ {
+		data[2*i] = 2
+		data[2*i+1] = -1
+	}
+	data[2*n-1] = 0
+	return data
+}
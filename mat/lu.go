@@ -5,6 +5,9 @@
 package mat
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
 	"math"
 
 	"gonum.org/v1/gonum/blas"
@@ -100,6 +103,58 @@ func (lu *LU) Cond() float64 {
 	return lu.cond
 }
 
+// CondEst returns an estimate of the condition number of the factorized
+// matrix in the 1-norm if norm is 1, or in the ∞-norm if norm is math.Inf(1).
+// The norms of A and A⁻¹ are estimated with the algorithm of Hager and Higham
+// using products and solves with the factors, so A⁻¹ is not formed and the
+// cost is O(n²). The estimate is a lower bound of the condition number that is
+// often exact and is usually within a small factor of it.
+//
+// CondEst will panic if norm is not 1 or math.Inf(1), or if the receiver does
+// not contain a factorization.
+func (lu *LU) CondEst(norm float64) float64 {
+	if !lu.isValid() {
+		panic(badLU)
+	}
+	n := len(lu.pivot)
+	for i := 0; i < n; i++ {
+		if lu.lu.at(i, i) == 0 {
+			checkCondNorm(norm)
+			return math.Inf(1)
+		}
+	}
+	u := lu.lu.asTriDense(n, blas.NonUnit, blas.Upper).mat
+	l := lu.lu.asTriDense(n, blas.Unit, blas.Lower).mat
+	mul := func(x []float64, trans bool) {
+		// A = P*L*U, where P is the product of the row interchanges
+		// in reverse order.
+		v := blas64.Vector{N: n, Inc: 1, Data: x}
+		if !trans {
+			blas64.Trmv(blas.NoTrans, u, v)
+			blas64.Trmv(blas.NoTrans, l, v)
+			for i := n - 1; i >= 0; i-- {
+				j := lu.pivot[i]
+				x[i], x[j] = x[j], x[i]
+			}
+			return
+		}
+		for i, j := range lu.pivot {
+			x[i], x[j] = x[j], x[i]
+		}
+		blas64.Trmv(blas.Trans, l, v)
+		blas64.Trmv(blas.Trans, u, v)
+	}
+	solve := func(x []float64, trans bool) {
+		t := blas.NoTrans
+		if trans {
+			t = blas.Trans
+		}
+		b := blas64.General{Rows: n, Cols: 1, Stride: 1, Data: x}
+		lapack64.Getrs(t, lu.lu.mat, b, lu.pivot)
+	}
+	return condEst(n, norm, -1, mul, solve)
+}
+
 // Reset resets the factorization so that it can be reused as the receiver of a
 // dimensionally restricted operation.
 func (lu *LU) Reset() {
@@ -109,6 +164,71 @@ func (lu *LU) Reset() {
 	lu.pivot = lu.pivot[:0]
 }
 
+// MarshalBinary encodes the receiver into a binary form and returns the result.
+// MarshalBinary returns an error if the receiver does not contain a
+// factorization.
+//
+// LU is little-endian encoded as follows:
+//  Dense binary form of the n×n L and U factors, see Dense.MarshalBinary
+//  n pivot indices   (int64)
+//  condition number  (float64)
+func (lu *LU) MarshalBinary() ([]byte, error) {
+	if !lu.isValid() {
+		return nil, errors.New(badLU)
+	}
+	buf, err := lu.lu.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	tail := make([]byte, (len(lu.pivot)+1)*sizeFloat64)
+	p := 0
+	for _, v := range lu.pivot {
+		binary.LittleEndian.PutUint64(tail[p:p+sizeFloat64], uint64(v))
+		p += sizeFloat64
+	}
+	binary.LittleEndian.PutUint64(tail[p:], math.Float64bits(lu.cond))
+	return append(buf, tail...), nil
+}
+
+// UnmarshalBinary decodes the binary form of an LU factorization into the
+// receiver. It panics if the receiver contains a factorization.
+//
+// See MarshalBinary for the on-disk layout. In addition to the checks of
+// Dense.UnmarshalBinary, UnmarshalBinary checks that the factors are square
+// and that the pivot indices are valid. UnmarshalBinary does not limit the
+// size of the unmarshaled factorization, and so it should not be used on
+// untrusted data.
+func (lu *LU) UnmarshalBinary(data []byte) error {
+	if lu.isValid() {
+		panic("mat: unmarshal into non-empty factorization")
+	}
+	var f Dense
+	p, err := f.UnmarshalBinaryFrom(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	n, c := f.Dims()
+	if n != c {
+		return errBadSize
+	}
+	if len(data)-p != (n+1)*sizeFloat64 {
+		return errBadBuffer
+	}
+	pivot := make([]int, n)
+	for i := range pivot {
+		v := int64(binary.LittleEndian.Uint64(data[p : p+sizeFloat64]))
+		if v < int64(i) || v >= int64(n) {
+			return errBadPivot
+		}
+		pivot[i] = int(v)
+		p += sizeFloat64
+	}
+	lu.lu = &f
+	lu.pivot = pivot
+	lu.cond = math.Float64frombits(binary.LittleEndian.Uint64(data[p:]))
+	return nil
+}
+
 func (lu *LU) isZero() bool {
 	return len(lu.pivot) == 0
 }
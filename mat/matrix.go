@@ -415,6 +415,33 @@ func Cond(a Matrix, norm float64) float64 {
 	return lq.Cond()
 }
 
+// checkCondNorm panics if norm is not a norm supported by condEst.
+func checkCondNorm(norm float64) {
+	if norm != 1 && !math.IsInf(norm, 1) {
+		panic("mat: bad norm value")
+	}
+}
+
+// condEst returns an estimate of the condition number ‖A‖ ‖A⁻¹‖ of the n×n
+// non-singular matrix A in the 1-norm if norm is 1, or in the ∞-norm if norm is
+// math.Inf(1), without forming A⁻¹. mul overwrites x with A*x, or Aᵀ*x if trans
+// is true, and solve overwrites x with A⁻¹*x, or A⁻ᵀ*x if trans is true. If
+// anorm is negative, ‖A‖ is estimated using mul, otherwise anorm is used as
+// ‖A‖.
+func condEst(n int, norm, anorm float64, mul, solve func(x []float64, trans bool)) float64 {
+	checkCondNorm(norm)
+	if math.IsInf(norm, 1) {
+		// The ∞-norm of a matrix is the 1-norm of its transpose.
+		m, s := mul, solve
+		mul = func(x []float64, trans bool) { m(x, !trans) }
+		solve = func(x []float64, trans bool) { s(x, !trans) }
+	}
+	if anorm < 0 {
+		anorm = normEst1(n, mul)
+	}
+	return anorm * normEst1(n, solve)
+}
+
 // Det returns the determinant of the matrix a. In many expressions using LogDet
 // will be more numerically stable.
 func Det(a Matrix) float64 {
@@ -516,7 +543,16 @@ func Equal(a, b Matrix) bool {
 // EqualApprox returns whether the matrices a and b have the same size and contain all equal
 // elements with tolerance for element-wise equality specified by epsilon. Matrices
 // with non-equal shapes are not equal.
-func EqualApprox(a, b Matrix, epsilon float64) bool {
+//
+// Without options, elements are equal if they are within epsilon of each other in
+// either absolute or relative terms, as determined by floats.EqualWithinAbsOrRel,
+// and NaN elements are not equal to any element. The AbsTol, RelTol and NaNEqual
+// options change the element-wise comparison.
+func EqualApprox(a, b Matrix, epsilon float64, options ...ApproxOption) bool {
+	e := approxEqual{absTol: epsilon, relTol: epsilon}
+	for _, o := range options {
+		o(&e)
+	}
 	ar, ac := a.Dims()
 	br, bc := b.Dims()
 	if ar != br || ac != bc {
@@ -531,7 +567,7 @@ func EqualApprox(a, b Matrix, epsilon float64) bool {
 			if aTrans == bTrans {
 				for i := 0; i < ra.Rows; i++ {
 					for j := 0; j < ra.Cols; j++ {
-						if !floats.EqualWithinAbsOrRel(ra.Data[i*ra.Stride+j], rb.Data[i*rb.Stride+j], epsilon, epsilon) {
+						if !e.equal(ra.Data[i*ra.Stride+j], rb.Data[i*rb.Stride+j]) {
 							return false
 						}
 					}
@@ -540,7 +576,7 @@ func EqualApprox(a, b Matrix, epsilon float64) bool {
 			}
 			for i := 0; i < ra.Rows; i++ {
 				for j := 0; j < ra.Cols; j++ {
-					if !floats.EqualWithinAbsOrRel(ra.Data[i*ra.Stride+j], rb.Data[j*rb.Stride+i], epsilon, epsilon) {
+					if !e.equal(ra.Data[i*ra.Stride+j], rb.Data[j*rb.Stride+i]) {
 						return false
 					}
 				}
@@ -555,7 +591,7 @@ func EqualApprox(a, b Matrix, epsilon float64) bool {
 			// Symmetric matrices are always upper and equal to their transpose.
 			for i := 0; i < ra.N; i++ {
 				for j := i; j < ra.N; j++ {
-					if !floats.EqualWithinAbsOrRel(ra.Data[i*ra.Stride+j], rb.Data[i*rb.Stride+j], epsilon, epsilon) {
+					if !e.equal(ra.Data[i*ra.Stride+j], rb.Data[i*rb.Stride+j]) {
 						return false
 					}
 				}
@@ -568,7 +604,7 @@ func EqualApprox(a, b Matrix, epsilon float64) bool {
 			// If the raw vectors are the same length they must either both be
 			// transposed or both not transposed (or have length 1).
 			for i := 0; i < ra.mat.N; i++ {
-				if !floats.EqualWithinAbsOrRel(ra.mat.Data[i*ra.mat.Inc], rb.mat.Data[i*rb.mat.Inc], epsilon, epsilon) {
+				if !e.equal(ra.mat.Data[i*ra.mat.Inc], rb.mat.Data[i*rb.mat.Inc]) {
 					return false
 				}
 			}
@@ -577,7 +613,7 @@ func EqualApprox(a, b Matrix, epsilon float64) bool {
 	}
 	for i := 0; i < ar; i++ {
 		for j := 0; j < ac; j++ {
-			if !floats.EqualWithinAbsOrRel(a.At(i, j), b.At(i, j), epsilon, epsilon) {
+			if !e.equal(a.At(i, j), b.At(i, j)) {
 				return false
 			}
 		}
@@ -585,6 +621,46 @@ func EqualApprox(a, b Matrix, epsilon float64) bool {
 	return true
 }
 
+// approxEqual holds the element-wise comparison parameters of EqualApprox.
+type approxEqual struct {
+	absTol   float64
+	relTol   float64
+	nanEqual bool
+}
+
+func (e approxEqual) equal(x, y float64) bool {
+	if e.nanEqual && math.IsNaN(x) && math.IsNaN(y) {
+		return true
+	}
+	return floats.EqualWithinAbsOrRel(x, y, e.absTol, e.relTol)
+}
+
+// ApproxOption is a functional option for approximate matrix comparison.
+type ApproxOption func(*approxEqual)
+
+// AbsTol sets the absolute tolerance of an approximate comparison to tol in place
+// of epsilon. Elements are then equal if their absolute difference is at most tol or
+// they are equal within the relative tolerance. AbsTol(0) gives a purely relative
+// comparison.
+func AbsTol(tol float64) ApproxOption {
+	return func(e *approxEqual) { e.absTol = tol }
+}
+
+// RelTol sets the relative tolerance of an approximate comparison to tol in place
+// of epsilon. Elements are then equal if their absolute difference is at most tol
+// times the larger of their magnitudes or they are equal within the absolute
+// tolerance. RelTol(0) gives a purely absolute comparison.
+func RelTol(tol float64) ApproxOption {
+	return func(e *approxEqual) { e.relTol = tol }
+}
+
+// NaNEqual sets an approximate comparison to treat NaN elements as equal to each
+// other, so that matrices with NaN elements in the same positions may compare as
+// equal.
+func NaNEqual() ApproxOption {
+	return func(e *approxEqual) { e.nanEqual = true }
+}
+
 // LogDet returns the log of the determinant and the sign of the determinant
 // for the matrix that has been factorized. Numerical stability in product and
 // division expressions is generally improved by working in log space.
@@ -745,15 +821,25 @@ func Min(a Matrix) float64 {
 	}
 }
 
+// MaxAbsNorm is the norm order that specifies to Norm the max norm, the
+// largest absolute value of the elements of a matrix. The max norm is not an
+// induced norm.
+const MaxAbsNorm = -1
+
 // Norm returns the specified (induced) norm of the matrix a. See
 // https://en.wikipedia.org/wiki/Matrix_norm for the definition of an induced norm.
 //
 // Valid norms are:
-//    1 - The maximum absolute column sum
-//    2 - Frobenius norm, the square root of the sum of the squares of the elements.
-//  Inf - The maximum absolute row sum.
+//            1 - The maximum absolute column sum
+//            2 - Frobenius norm, the square root of the sum of the squares of the elements.
+//          Inf - The maximum absolute row sum.
+//   MaxAbsNorm - The maximum absolute value of the elements.
 // Norm will panic with ErrNormOrder if an illegal norm order is specified and
 // with matrix.ErrShape if the matrix has zero size.
+//
+// All norms are computed for every matrix type. Banded and sparse matrices,
+// that is matrices implementing Banded or NonZeroDoer, only have their
+// structurally non-zero elements visited.
 func Norm(a Matrix, norm float64) float64 {
 	r, c := a.Dims()
 	if r == 0 || c == 0 {
@@ -805,43 +891,84 @@ func Norm(a Matrix, norm float64) float64 {
 			}
 			imax := blas64.Iamax(rv)
 			return math.Abs(rma.At(imax, 0))
+		case MaxAbsNorm:
+			imax := blas64.Iamax(rv)
+			return math.Abs(rma.At(imax, 0))
 		}
+	case *COO:
+		// Duplicate triplets must be summed before taking
+		// absolute values.
+		aU = rma.ToCSR()
 	}
+	if b, ok := a.(Banded); ok {
+		kl, ku := b.Bandwidth()
+		return normVisit(norm, r, c, false, func(fn func(i, j int, v float64)) {
+			for i := 0; i < r; i++ {
+				for j := max(0, i-kl); j < min(c, i+ku+1); j++ {
+					fn(i, j, b.At(i, j))
+				}
+			}
+		})
+	}
+	if nz, ok := aU.(NonZeroDoer); ok {
+		return normVisit(norm, r, c, aTrans, nz.DoNonZero)
+	}
+	return normVisit(norm, r, c, false, func(fn func(i, j int, v float64)) {
+		for i := 0; i < r; i++ {
+			for j := 0; j < c; j++ {
+				fn(i, j, a.At(i, j))
+			}
+		}
+	})
+}
+
+// normVisit returns the specified norm of the r×c matrix whose elements, or
+// whose non-zero elements, are passed to fn by visit. If trans is true, the
+// elements passed by visit are those of the transpose of the matrix.
+func normVisit(norm float64, r, c int, trans bool, visit func(fn func(i, j int, v float64))) float64 {
 	switch norm {
 	default:
 		panic(ErrNormOrder)
-	case 1:
-		var max float64
-		for j := 0; j < c; j++ {
-			var sum float64
-			for i := 0; i < r; i++ {
-				sum += math.Abs(a.At(i, j))
+	case 1, math.Inf(1):
+		// The absolute column sums for the 1-norm and row sums
+		// for the ∞-norm are accumulated in sums.
+		rowSum := norm != 1
+		l := c
+		if rowSum {
+			l = r
+		}
+		sums := getFloats(l, true)
+		defer putFloats(sums)
+		visit(func(i, j int, v float64) {
+			if trans {
+				i, j = j, i
 			}
-			if sum > max {
-				max = sum
+			if rowSum {
+				sums[i] += math.Abs(v)
+			} else {
+				sums[j] += math.Abs(v)
+			}
+		})
+		var max float64
+		for _, v := range sums {
+			if v > max {
+				max = v
 			}
 		}
 		return max
 	case 2:
 		var sum float64
-		for i := 0; i < r; i++ {
-			for j := 0; j < c; j++ {
-				v := a.At(i, j)
-				sum += v * v
-			}
-		}
+		visit(func(_, _ int, v float64) {
+			sum += v * v
+		})
 		return math.Sqrt(sum)
-	case math.Inf(1):
+	case MaxAbsNorm:
 		var max float64
-		for i := 0; i < r; i++ {
-			var sum float64
-			for j := 0; j < c; j++ {
-				sum += math.Abs(a.At(i, j))
-			}
-			if sum > max {
-				max = sum
+		visit(func(_, _ int, v float64) {
+			if math.Abs(v) > max {
+				max = math.Abs(v)
 			}
-		}
+		})
 		return max
 	}
 }
@@ -863,6 +990,8 @@ func normLapack(norm float64, aTrans bool) lapack.MatrixNorm {
 			n = lapack.MaxColumnSum
 		}
 		return n
+	case MaxAbsNorm:
+		return lapack.MaxAbs
 	default:
 		panic(ErrNormOrder)
 	}
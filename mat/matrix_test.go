@@ -292,6 +292,109 @@ func TestCond(t *testing.T) {
 	}
 }
 
+func TestEqualApproxOptions(t *testing.T) {
+	t.Parallel()
+	nan := math.NaN()
+	for _, typ := range []struct {
+		name string
+		conv func(*Dense) Matrix
+	}{
+		{name: "Dense", conv: func(m *Dense) Matrix { return m }},
+		{name: "Transpose", conv: func(m *Dense) Matrix { return m.T().T() }},
+		{name: "Matrix", conv: func(m *Dense) Matrix { return (*basicMatrix)(m) }},
+	} {
+		for _, test := range []struct {
+			a, b    []float64
+			epsilon float64
+			options []ApproxOption
+			want    bool
+		}{
+			{a: []float64{1, 2}, b: []float64{1, 2.1}, epsilon: 0.1, want: true},
+			{a: []float64{1, 1000}, b: []float64{1, 1001}, epsilon: 0.01, want: true},
+			{a: []float64{1, 1000}, b: []float64{1, 1001}, epsilon: 0.01, options: []ApproxOption{RelTol(0)}, want: false},
+			{a: []float64{1, 1000}, b: []float64{1, 1001}, epsilon: 0, options: []ApproxOption{AbsTol(2)}, want: true},
+			{a: []float64{1e-12, 0}, b: []float64{2e-12, 0}, epsilon: 1e-10, options: []ApproxOption{AbsTol(0)}, want: false},
+			{a: []float64{1e-12, 0}, b: []float64{1.1e-12, 0}, epsilon: 0, options: []ApproxOption{AbsTol(0), RelTol(0.1)}, want: true},
+			{a: []float64{nan, 1}, b: []float64{nan, 1}, epsilon: 1, want: false},
+			{a: []float64{nan, 1}, b: []float64{nan, 1}, epsilon: 0, options: []ApproxOption{NaNEqual()}, want: true},
+			{a: []float64{nan, 1}, b: []float64{1, 1}, epsilon: 1, options: []ApproxOption{NaNEqual()}, want: false},
+			{a: []float64{math.Inf(1), 1}, b: []float64{math.Inf(1), 1}, epsilon: 0, want: true},
+		} {
+			a := typ.conv(NewDense(1, 2, test.a))
+			b := typ.conv(NewDense(1, 2, test.b))
+			got := EqualApprox(a, b, test.epsilon, test.options...)
+			if got != test.want {
+				t.Errorf("%s: unexpected result for %v and %v with epsilon %v: got %t want %t",
+					typ.name, test.a, test.b, test.epsilon, got, test.want)
+			}
+		}
+	}
+}
+
+func TestCondEst(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 5, 10, 30} {
+		for k := 0; k < 5; k++ {
+			a := NewDense(n, n, nil)
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					a.Set(i, j, rnd.NormFloat64())
+				}
+			}
+			var spd SymDense
+			spd.SymOuterK(1, a)
+			for i := 0; i < n; i++ {
+				spd.SetSym(i, i, spd.At(i, i)+1)
+			}
+
+			var lu LU
+			lu.Factorize(a)
+			var chol Cholesky
+			if !chol.Factorize(&spd) {
+				t.Fatalf("n=%d: bad test matrix", n)
+			}
+			var qr QR
+			qr.Factorize(a)
+			var r Dense
+			qr.RTo(&r)
+
+			for _, norm := range []float64{1, math.Inf(1)} {
+				for _, test := range []struct {
+					name string
+					a    Matrix
+					est  float64
+				}{
+					{name: "LU", a: a, est: lu.CondEst(norm)},
+					{name: "Cholesky", a: &spd, est: chol.CondEst(norm)},
+					{name: "QR", a: &r, est: qr.CondEst(norm)},
+				} {
+					var inv Dense
+					err := inv.Inverse(test.a)
+					if err != nil {
+						if _, ok := err.(Condition); !ok {
+							t.Fatalf("n=%d: unexpected error: %v", n, err)
+						}
+					}
+					want := Norm(test.a, norm) * Norm(&inv, norm)
+					if test.est > want*(1+1e-10) || test.est < want/10 {
+						t.Errorf("%s n=%d norm=%v: unexpected condition estimate: got %v, want %v", test.name, n, norm, test.est, want)
+					}
+				}
+			}
+		}
+	}
+
+	var lu LU
+	lu.Factorize(NewDense(2, 2, []float64{1, 2, 2, 4}))
+	if c := lu.CondEst(1); !math.IsInf(c, 1) {
+		t.Errorf("unexpected condition estimate for singular matrix: got %v, want +Inf", c)
+	}
+	if ok, _ := panics(func() { lu.CondEst(2) }); !ok {
+		t.Errorf("expected panic for 2-norm")
+	}
+}
+
 func TestDet(t *testing.T) {
 	t.Parallel()
 	for c, test := range []struct {
@@ -517,6 +620,7 @@ func TestNorm(t *testing.T) {
 		{"NormOne", 1},
 		{"NormTwo", 2},
 		{"NormInf", math.Inf(1)},
+		{"NormMaxAbs", MaxAbsNorm},
 	} {
 		f := func(a Matrix) interface{} {
 			return Norm(a, test.norm)
@@ -528,6 +632,56 @@ func TestNorm(t *testing.T) {
 	}
 }
 
+func TestNormTypes(t *testing.T) {
+	t.Parallel()
+	coo := NewCOO(3, 4)
+	coo.Append(0, 1, 2)
+	coo.Append(2, 3, -5)
+	coo.Append(0, 1, -3)
+	coo.Append(1, 0, 4)
+	for _, a := range []Matrix{
+		NewBandDense(4, 5, 1, 2, []float64{
+			0, 1, -2, 3,
+			4, -5, 6, 7,
+			-8, 9, 1, -2,
+			3, 4, -5, 0,
+		}),
+		NewSymBandDense(4, 1, []float64{
+			1, -2,
+			3, 4,
+			-5, 6,
+			7, 0,
+		}),
+		NewTriBandDense(4, 1, Lower, []float64{
+			0, 1,
+			-2, 3,
+			4, -5,
+			6, 7,
+		}),
+		NewDiagDense(3, []float64{1, -4, 2}),
+		NewTridiag(4, []float64{1, -2, 3}, []float64{4, 5, -6, 7}, []float64{-8, 9, 1}),
+		NewCSR(3, 4, []int{0, 2, 3, 5}, []int{0, 3, 1, 0, 2}, []float64{1, -2, 3, -4, 5}),
+		NewCSC(3, 4, []int{0, 2, 3, 4, 5}, []int{0, 2, 1, 0, 2}, []float64{1, -2, 3, -4, 5}),
+		coo,
+		NewPermutation(3, []int{2, 0, 1}),
+	} {
+		for _, m := range []Matrix{a, a.T()} {
+			d := DenseCopyOf(m)
+			for _, norm := range []float64{1, 2, math.Inf(1), MaxAbsNorm} {
+				got := Norm(m, norm)
+				want := Norm(d, norm)
+				if math.Abs(got-want) > 1e-14*want {
+					t.Errorf("unexpected %v norm of %T: got %v, want %v", norm, m, got, want)
+				}
+			}
+		}
+	}
+	panicked, message := panics(func() { Norm(NewCSR(1, 1, []int{0, 0}, nil, nil), 3) })
+	if !panicked || message != ErrNormOrder.Error() {
+		t.Errorf("expected panic %q for invalid norm order, got %q", ErrNormOrder, message)
+	}
+}
+
 func TestNormZero(t *testing.T) {
 	t.Parallel()
 	for _, a := range []Matrix{
@@ -0,0 +1,270 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// mtxBanner is the first token of a Matrix Market file.
+const mtxBanner = "%%MatrixMarket"
+
+// ReadMatrixMarket reads a real matrix in the Matrix Market exchange format
+// from r. Files in coordinate format are returned as a *COO, which may be
+// converted to a compressed format with its ToCSR and ToCSC methods. Files
+// in array format are returned as a *SymDense if they are symmetric and as a
+// *Dense otherwise.
+//
+// The real, integer and pattern fields are supported, the values of pattern
+// files all being one, with the general, symmetric and skew-symmetric
+// symmetry qualifiers. Complex and Hermitian files are not supported. The
+// format is described at https://math.nist.gov/MatrixMarket/formats.html.
+func ReadMatrixMarket(r io.Reader) (Matrix, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	var line int
+	next := func() ([]string, error) {
+		for sc.Scan() {
+			line++
+			text := strings.TrimSpace(sc.Text())
+			if text == "" || text[0] == '%' {
+				continue
+			}
+			return strings.Fields(text), nil
+		}
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	if !sc.Scan() {
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.ErrUnexpectedEOF
+	}
+	line++
+	header := strings.Fields(strings.ToLower(sc.Text()))
+	if len(header) != 5 || header[0] != strings.ToLower(mtxBanner) || header[1] != "matrix" {
+		return nil, fmt.Errorf("mat: invalid Matrix Market header: %q", sc.Text())
+	}
+	format, field, symmetry := header[2], header[3], header[4]
+	switch format {
+	case "coordinate", "array":
+	default:
+		return nil, fmt.Errorf("mat: unsupported Matrix Market format: %q", format)
+	}
+	switch field {
+	case "real", "integer":
+	case "pattern":
+		if format == "array" {
+			return nil, fmt.Errorf("mat: invalid Matrix Market pattern array")
+		}
+	default:
+		return nil, fmt.Errorf("mat: unsupported Matrix Market field: %q", field)
+	}
+	switch symmetry {
+	case "general", "symmetric", "skew-symmetric":
+	default:
+		return nil, fmt.Errorf("mat: unsupported Matrix Market symmetry: %q", symmetry)
+	}
+
+	size, err := next()
+	if err != nil {
+		return nil, err
+	}
+	want := 2
+	if format == "coordinate" {
+		want = 3
+	}
+	dims, err := mtxInts(size, want, line)
+	if err != nil {
+		return nil, err
+	}
+	rows, cols := dims[0], dims[1]
+	if rows < 0 || cols < 0 {
+		return nil, errBadSize
+	}
+	if rows == 0 || cols == 0 {
+		return nil, ErrZeroLength
+	}
+	if symmetry != "general" && rows != cols {
+		return nil, fmt.Errorf("mat: non-square %s Matrix Market matrix", symmetry)
+	}
+
+	if format == "coordinate" {
+		nnz := dims[2]
+		if nnz < 0 {
+			return nil, errBadSize
+		}
+		m := NewCOO(rows, cols)
+		for k := 0; k < nnz; k++ {
+			fields, err := next()
+			if err != nil {
+				return nil, err
+			}
+			var v float64
+			if field == "pattern" {
+				if len(fields) != 2 {
+					return nil, fmt.Errorf("mat: invalid Matrix Market entry on line %d", line)
+				}
+				v = 1
+			} else {
+				if len(fields) != 3 {
+					return nil, fmt.Errorf("mat: invalid Matrix Market entry on line %d", line)
+				}
+				v, err = strconv.ParseFloat(fields[2], 64)
+				if err != nil {
+					return nil, fmt.Errorf("mat: invalid Matrix Market value on line %d: %v", line, err)
+				}
+			}
+			ij, err := mtxInts(fields[:2], 2, line)
+			if err != nil {
+				return nil, err
+			}
+			i, j := ij[0]-1, ij[1]-1
+			if uint(i) >= uint(rows) || uint(j) >= uint(cols) {
+				return nil, fmt.Errorf("mat: Matrix Market index out of range on line %d", line)
+			}
+			if symmetry != "general" && i < j {
+				return nil, fmt.Errorf("mat: Matrix Market entry above the diagonal on line %d", line)
+			}
+			m.Append(i, j, v)
+			if i != j {
+				switch symmetry {
+				case "symmetric":
+					m.Append(j, i, v)
+				case "skew-symmetric":
+					m.Append(j, i, -v)
+				}
+			}
+		}
+		return m, nil
+	}
+
+	// Array entries are listed in column-major order, for the lower
+	// triangle of symmetric matrices and the strictly lower triangle of
+	// skew-symmetric matrices.
+	var (
+		d   *Dense
+		s   *SymDense
+		set func(i, j int, v float64)
+	)
+	switch symmetry {
+	case "general":
+		d = NewDense(rows, cols, nil)
+		set = d.set
+	case "symmetric":
+		s = NewSymDense(rows, nil)
+		set = func(i, j int, v float64) { s.SetSym(i, j, v) }
+	case "skew-symmetric":
+		d = NewDense(rows, cols, nil)
+		set = func(i, j int, v float64) {
+			d.set(i, j, v)
+			d.set(j, i, -v)
+		}
+	}
+	for j := 0; j < cols; j++ {
+		var i0 int
+		switch symmetry {
+		case "symmetric":
+			i0 = j
+		case "skew-symmetric":
+			i0 = j + 1
+		}
+		for i := i0; i < rows; i++ {
+			fields, err := next()
+			if err != nil {
+				return nil, err
+			}
+			if len(fields) != 1 {
+				return nil, fmt.Errorf("mat: invalid Matrix Market entry on line %d", line)
+			}
+			v, err := strconv.ParseFloat(fields[0], 64)
+			if err != nil {
+				return nil, fmt.Errorf("mat: invalid Matrix Market value on line %d: %v", line, err)
+			}
+			set(i, j, v)
+		}
+	}
+	if s != nil {
+		return s, nil
+	}
+	return d, nil
+}
+
+// mtxInts parses the n integers in fields read from the given line.
+func mtxInts(fields []string, n, line int) ([]int, error) {
+	if len(fields) != n {
+		return nil, fmt.Errorf("mat: invalid Matrix Market line %d", line)
+	}
+	v := make([]int, n)
+	for i, f := range fields {
+		var err error
+		v[i], err = strconv.Atoi(f)
+		if err != nil {
+			return nil, fmt.Errorf("mat: invalid Matrix Market integer on line %d: %v", line, err)
+		}
+	}
+	return v, nil
+}
+
+// WriteMatrixMarket writes the matrix a to w in the Matrix Market exchange
+// format with the real field. Matrices that implement NonZeroDoer, such as the
+// sparse and banded matrix types, are written in coordinate format, and all
+// other matrices are written in array format. Matrices that implement
+// Symmetric are written with the symmetric qualifier, storing only their
+// lower triangle. Values are written with the shortest representation that
+// reads back exactly.
+//
+// The values of duplicate triplets of a *COO are summed before writing.
+func WriteMatrixMarket(w io.Writer, a Matrix) error {
+	if coo, ok := a.(*COO); ok {
+		a = coo.ToCSR()
+	}
+	_, isSym := a.(Symmetric)
+	symmetry := "general"
+	if isSym {
+		symmetry = "symmetric"
+	}
+	r, c := a.Dims()
+	bw := bufio.NewWriter(w)
+	format := func(v float64) string {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+
+	if nz, ok := a.(NonZeroDoer); ok {
+		var nnz int
+		nz.DoNonZero(func(i, j int, _ float64) {
+			if !isSym || i >= j {
+				nnz++
+			}
+		})
+		fmt.Fprintf(bw, "%s matrix coordinate real %s\n%d %d %d\n", mtxBanner, symmetry, r, c, nnz)
+		nz.DoNonZero(func(i, j int, v float64) {
+			if !isSym || i >= j {
+				fmt.Fprintf(bw, "%d %d %s\n", i+1, j+1, format(v))
+			}
+		})
+		return bw.Flush()
+	}
+
+	fmt.Fprintf(bw, "%s matrix array real %s\n%d %d\n", mtxBanner, symmetry, r, c)
+	for j := 0; j < c; j++ {
+		var i0 int
+		if isSym {
+			i0 = j
+		}
+		for i := i0; i < r; i++ {
+			fmt.Fprintln(bw, format(a.At(i, j)))
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,215 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadMatrixMarket(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		mtx  string
+		want Matrix
+	}{
+		{
+			name: "coordinate general",
+			mtx: `%%MatrixMarket matrix coordinate real general
+% A comment.
+
+3 4 4
+1 1 1.5
+2 3 -2
+3 4 3e2
+1 1 0.5
+`,
+			want: NewDense(3, 4, []float64{
+				2, 0, 0, 0,
+				0, 0, -2, 0,
+				0, 0, 0, 300,
+			}),
+		},
+		{
+			name: "coordinate symmetric integer",
+			mtx: `%%MatrixMarket matrix coordinate integer symmetric
+3 3 3
+1 1 4
+3 1 2
+3 2 -1
+`,
+			want: NewDense(3, 3, []float64{
+				4, 0, 2,
+				0, 0, -1,
+				2, -1, 0,
+			}),
+		},
+		{
+			name: "coordinate skew-symmetric",
+			mtx: `%%MatrixMarket matrix coordinate real skew-symmetric
+2 2 1
+2 1 5
+`,
+			want: NewDense(2, 2, []float64{
+				0, -5,
+				5, 0,
+			}),
+		},
+		{
+			name: "coordinate pattern",
+			mtx: `%%MatrixMarket matrix coordinate pattern general
+2 3 2
+1 3
+2 1
+`,
+			want: NewDense(2, 3, []float64{
+				0, 0, 1,
+				1, 0, 0,
+			}),
+		},
+		{
+			name: "array general",
+			mtx: `%%MatrixMarket matrix array real general
+2 3
+1
+4
+2
+5
+3
+6
+`,
+			want: NewDense(2, 3, []float64{
+				1, 2, 3,
+				4, 5, 6,
+			}),
+		},
+		{
+			name: "array symmetric",
+			mtx: `%%MATRIXMARKET MATRIX ARRAY REAL SYMMETRIC
+3 3
+1
+2
+3
+4
+5
+6
+`,
+			want: NewSymDense(3, []float64{
+				1, 2, 3,
+				2, 4, 5,
+				3, 5, 6,
+			}),
+		},
+		{
+			name: "array skew-symmetric",
+			mtx: `%%MatrixMarket matrix array real skew-symmetric
+3 3
+1
+2
+3
+`,
+			want: NewDense(3, 3, []float64{
+				0, -1, -2,
+				1, 0, -3,
+				2, 3, 0,
+			}),
+		},
+	} {
+		got, err := ReadMatrixMarket(strings.NewReader(test.mtx))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !Equal(got, test.want) {
+			t.Errorf("%s: unexpected matrix:\ngot:\n%v\nwant:\n%v", test.name, Formatted(got), Formatted(test.want))
+		}
+		if strings.Contains(test.name, "coordinate") {
+			if _, ok := got.(*COO); !ok {
+				t.Errorf("%s: unexpected type %T", test.name, got)
+			}
+		}
+		if test.name == "array symmetric" {
+			if _, ok := got.(*SymDense); !ok {
+				t.Errorf("%s: unexpected type %T", test.name, got)
+			}
+		}
+	}
+
+	for _, mtx := range []string{
+		"",
+		"%%MatrixMarket matrix coordinate complex general\n1 1 1\n1 1 1 0\n",
+		"%%MatrixMarket matrix array pattern general\n1 1\n",
+		"%%MatrixMarket vector coordinate real general\n1 1 1\n1 1 1\n",
+		"%%MatrixMarket matrix coordinate real general\n2 2 2\n1 1 1\n",
+		"%%MatrixMarket matrix coordinate real general\n2 2 1\n3 1 1\n",
+		"%%MatrixMarket matrix coordinate real general\n2 2 1\n1 1 x\n",
+		"%%MatrixMarket matrix coordinate real symmetric\n2 2 1\n1 2 1\n",
+		"%%MatrixMarket matrix coordinate real symmetric\n2 3 1\n1 1 1\n",
+		"%%MatrixMarket matrix array real general\n0 2\n",
+		"%%MatrixMarket matrix array real general\n2 1\n1\n",
+	} {
+		_, err := ReadMatrixMarket(strings.NewReader(mtx))
+		if err == nil {
+			t.Errorf("expected error for input %q", mtx)
+		}
+	}
+}
+
+func TestMatrixMarketRoundTrip(t *testing.T) {
+	t.Parallel()
+	coo := NewCOO(3, 4)
+	coo.Append(0, 1, 0.1)
+	coo.Append(2, 3, -1e-300)
+	coo.Append(0, 1, 0.2)
+	for _, test := range []struct {
+		name   string
+		a      Matrix
+		header string
+	}{
+		{
+			name:   "Dense",
+			a:      NewDense(2, 3, []float64{1, 2, 3, 4, 5, 1.0 / 3}),
+			header: "%%MatrixMarket matrix array real general",
+		},
+		{
+			name:   "SymDense",
+			a:      NewSymDense(2, []float64{1, 2, 2, 3}),
+			header: "%%MatrixMarket matrix array real symmetric",
+		},
+		{
+			name:   "CSR",
+			a:      NewCSR(2, 3, []int{0, 2, 3}, []int{0, 2, 1}, []float64{1, 2, 3}),
+			header: "%%MatrixMarket matrix coordinate real general",
+		},
+		{
+			name:   "COO",
+			a:      coo,
+			header: "%%MatrixMarket matrix coordinate real general",
+		},
+		{
+			name:   "SymBandDense",
+			a:      NewSymBandDense(3, 1, []float64{1, 2, 3, 4, 5, 0}),
+			header: "%%MatrixMarket matrix coordinate real symmetric",
+		},
+	} {
+		var buf bytes.Buffer
+		err := WriteMatrixMarket(&buf, test.a)
+		if err != nil {
+			t.Fatalf("%s: unexpected error writing: %v", test.name, err)
+		}
+		if !strings.HasPrefix(buf.String(), test.header+"\n") {
+			t.Errorf("%s: unexpected header in:\n%s", test.name, buf.String())
+		}
+		got, err := ReadMatrixMarket(&buf)
+		if err != nil {
+			t.Fatalf("%s: unexpected error reading: %v", test.name, err)
+		}
+		if !Equal(got, test.a) {
+			t.Errorf("%s: round trip failed:\ngot:\n%v\nwant:\n%v", test.name, Formatted(got), Formatted(test.a))
+		}
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"errors"
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+const (
+	badNMF     = "mat: invalid nonnegative matrix factorization"
+	badNMFRank = "mat: nonnegative factorization rank out of range"
+
+	// nmfMaxIter and nmfTolerance are the default iteration limit and
+	// convergence tolerance of NMF.Factorize.
+	nmfMaxIter   = 200
+	nmfTolerance = 1e-4
+
+	// nmfEps guards the denominators of the multiplicative updates.
+	nmfEps = 1e-16
+)
+
+var errNMFNegative = errors.New("mat: negative element in nonnegative factorization input")
+
+// NMFMethod specifies the algorithm used to compute a nonnegative matrix
+// factorization.
+type NMFMethod int
+
+const (
+	// NMFHALS specifies hierarchical alternating least squares, which
+	// updates each row of H and each column of W in turn with the solution
+	// of a nonnegative least squares problem in a single variable.
+	NMFHALS NMFMethod = iota
+	// NMFMultiplicative specifies the multiplicative update rules of Lee
+	// and Seung.
+	NMFMultiplicative
+)
+
+// NMFSettings specifies the computation of a nonnegative matrix factorization
+// by NMF.Factorize. The zero value specifies the HALS method with the default
+// iteration limit and tolerance, initialized from the global random source.
+type NMFSettings struct {
+	// Method is the algorithm used to update the factors.
+	Method NMFMethod
+
+	// MaxIter is the maximum number of updates of both factors. If
+	// MaxIter is zero, a default of 200 is used.
+	MaxIter int
+
+	// Tolerance is the convergence tolerance. The iteration stops when
+	// the Frobenius norm of the residual decreases by less than Tolerance
+	// times its previous value. If Tolerance is zero, a default of 1e-4
+	// is used.
+	Tolerance float64
+
+	// Src is the random source used to initialize the factors. If Src is
+	// nil, the global random source is used.
+	Src rand.Source
+}
+
+// NMF is a type for computing a nonnegative matrix factorization.
+//
+// The nonnegative matrix factorization of rank k of an m×n matrix A with
+// nonnegative elements is
+//  A ≈ W * H
+// where W is m×k and H is k×n, and both have nonnegative elements. The factors
+// are chosen to locally minimize the Frobenius norm of A - W*H.
+type NMF struct {
+	w, h *Dense
+
+	iter  int
+	resid float64
+}
+
+// Factorize computes a nonnegative matrix factorization of rank k of the m×n
+// matrix a, which must have nonnegative elements. k must be positive, otherwise
+// Factorize will panic. If settings is nil, the zero value of NMFSettings is
+// used.
+//
+// The factors are initialized with uniform random elements scaled to match the
+// mean of a, and are then alternately updated with the method specified in
+// settings until the relative decrease of the residual norm falls below the
+// tolerance. The result depends on the initialization, since the problem is
+// not convex.
+//
+// Factorize returns an error if a has a negative element, in which case the
+// receiver is left without a factorization. If the iteration limit is reached
+// before convergence, Factorize returns ErrNotConverged and the receiver holds
+// the factors of the final iterate.
+//
+// The HALS method is described in
+//  A. Cichocki and A.-H. Phan. Fast Local Algorithms for Large Scale
+//  Nonnegative Matrix and Tensor Factorizations. IEICE Transactions on
+//  Fundamentals of Electronics, Communications and Computer Sciences 92(3)
+//  (2009), pp. 708–721.
+// and the multiplicative updates in
+//  D. D. Lee and H. S. Seung. Algorithms for Non-negative Matrix
+//  Factorization. Advances in Neural Information Processing Systems 13
+//  (2001), pp. 556–562.
+func (f *NMF) Factorize(a Matrix, k int, settings *NMFSettings) error {
+	if k < 1 {
+		panic(badNMFRank)
+	}
+	f.Reset()
+	if settings == nil {
+		settings = &NMFSettings{}
+	}
+	maxIter := settings.MaxIter
+	if maxIter == 0 {
+		maxIter = nmfMaxIter
+	}
+	tol := settings.Tolerance
+	if tol == 0 {
+		tol = nmfTolerance
+	}
+	uniform := rand.Float64
+	if settings.Src != nil {
+		uniform = rand.New(settings.Src).Float64
+	}
+
+	m, n := a.Dims()
+	ad := DenseCopyOf(a)
+	var sum float64
+	for i := 0; i < m; i++ {
+		for _, v := range ad.RawRowView(i) {
+			if v < 0 {
+				return errNMFNegative
+			}
+			sum += v
+		}
+	}
+
+	// Initialize so that the elements of W*H have the mean of A on
+	// average.
+	scale := math.Sqrt(2 * sum / float64(m*n*k))
+	w := NewDense(m, k, nil)
+	for i := range w.mat.Data {
+		w.mat.Data[i] = scale * uniform()
+	}
+	h := NewDense(k, n, nil)
+	for i := range h.mat.Data {
+		h.mat.Data[i] = scale * uniform()
+	}
+
+	var (
+		wta, wtw, aht, hht, tmp Dense
+
+		err  error = ErrNotConverged
+		iter int
+	)
+	residual := func() float64 {
+		tmp.Reset()
+		tmp.Mul(w, h)
+		tmp.Sub(ad, &tmp)
+		r := Norm(&tmp, 2)
+		tmp.Reset()
+		return r
+	}
+	prev := residual()
+	for iter < maxIter {
+		iter++
+		wta.Mul(w.T(), ad)
+		wtw.Mul(w.T(), w)
+		switch settings.Method {
+		case NMFHALS:
+			nmfHALSUpdate(h, &wta, &wtw)
+		case NMFMultiplicative:
+			tmp.Mul(&wtw, h)
+			nmfMultUpdate(h, &wta, &tmp)
+		default:
+			panic("mat: unknown NMF method")
+		}
+
+		aht.Mul(ad, h.T())
+		hht.Mul(h, h.T())
+		switch settings.Method {
+		case NMFHALS:
+			// The update of the columns of W is the update of
+			// the rows of Wᵀ with Aᵀ and H exchanging roles.
+			wt := DenseCopyOf(w.T())
+			ahtT := DenseCopyOf(aht.T())
+			nmfHALSUpdate(wt, ahtT, &hht)
+			w.Copy(wt.T())
+		case NMFMultiplicative:
+			tmp.Reset()
+			tmp.Mul(w, &hht)
+			nmfMultUpdate(w, &aht, &tmp)
+		}
+
+		resid := residual()
+		if prev-resid <= tol*prev {
+			prev = resid
+			err = nil
+			break
+		}
+		prev = resid
+	}
+	f.w = w
+	f.h = h
+	f.iter = iter
+	f.resid = prev
+	return err
+}
+
+// nmfHALSUpdate updates the rows of the k×n factor h in turn, given the
+// products c = Wᵀ*A and g = Wᵀ*W of the other factor W with A and itself.
+func nmfHALSUpdate(h, c, g *Dense) {
+	k, n := h.Dims()
+	for j := 0; j < k; j++ {
+		gjj := g.at(j, j)
+		if gjj == 0 {
+			continue
+		}
+		hj := h.RawRowView(j)
+		cj := c.RawRowView(j)
+		gj := g.RawRowView(j)
+		for col := 0; col < n; col++ {
+			// The gradient of the row j at col is (G*H)[j,col] - C[j,col].
+			var gh float64
+			for l := 0; l < k; l++ {
+				gh += gj[l] * h.at(l, col)
+			}
+			hj[col] = math.Max(0, hj[col]+(cj[col]-gh)/gjj)
+		}
+	}
+}
+
+// nmfMultUpdate scales each element of the factor x by the ratio of the
+// corresponding elements of num and den.
+func nmfMultUpdate(x, num, den *Dense) {
+	r, _ := x.Dims()
+	for i := 0; i < r; i++ {
+		xi := x.RawRowView(i)
+		ni := num.RawRowView(i)
+		di := den.RawRowView(i)
+		for j := range xi {
+			xi[j] *= ni[j] / (di[j] + nmfEps)
+		}
+	}
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (f *NMF) isValid() bool {
+	return f.w != nil
+}
+
+// Reset resets the factorization so that it can be reused as the receiver of a
+// dimensionally restricted operation.
+func (f *NMF) Reset() {
+	f.w = nil
+	f.h = nil
+	f.iter = 0
+	f.resid = 0
+}
+
+// Rank returns the rank k of the factorization. Rank will panic if the
+// receiver does not contain a factorization.
+func (f *NMF) Rank() int {
+	if !f.isValid() {
+		panic(badNMF)
+	}
+	_, k := f.w.Dims()
+	return k
+}
+
+// Iterations returns the number of updates of both factors that were made.
+// Iterations will panic if the receiver does not contain a factorization.
+func (f *NMF) Iterations() int {
+	if !f.isValid() {
+		panic(badNMF)
+	}
+	return f.iter
+}
+
+// Residual returns the Frobenius norm of A - W*H. Residual will panic if the
+// receiver does not contain a factorization.
+func (f *NMF) Residual() float64 {
+	if !f.isValid() {
+		panic(badNMF)
+	}
+	return f.resid
+}
+
+// WTo extracts the m×k nonnegative factor W.
+//
+// If dst is empty, WTo will resize dst to be m×k. When dst is non-empty, WTo
+// will panic if dst is not m×k. WTo will also panic if the receiver does not
+// contain a factorization.
+func (f *NMF) WTo(dst *Dense) {
+	if !f.isValid() {
+		panic(badNMF)
+	}
+	truncSVDVectorsTo(dst, f.w)
+}
+
+// HTo extracts the k×n nonnegative factor H.
+//
+// If dst is empty, HTo will resize dst to be k×n. When dst is non-empty, HTo
+// will panic if dst is not k×n. HTo will also panic if the receiver does not
+// contain a factorization.
+func (f *NMF) HTo(dst *Dense) {
+	if !f.isValid() {
+		panic(badNMF)
+	}
+	truncSVDVectorsTo(dst, f.h)
+}
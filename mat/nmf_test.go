@@ -0,0 +1,109 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestNMF(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, k int
+	}{
+		{m: 20, n: 15, k: 1},
+		{m: 30, n: 20, k: 3},
+		{m: 10, n: 40, k: 4},
+	} {
+		w0 := NewDense(test.m, test.k, nil)
+		for i := range w0.mat.Data {
+			w0.mat.Data[i] = rnd.Float64()
+		}
+		h0 := NewDense(test.k, test.n, nil)
+		for i := range h0.mat.Data {
+			h0.mat.Data[i] = rnd.Float64()
+		}
+		var a Dense
+		a.Mul(w0, h0)
+		anorm := Norm(&a, 2)
+
+		for _, method := range []NMFMethod{NMFHALS, NMFMultiplicative} {
+			settings := &NMFSettings{
+				Method:    method,
+				MaxIter:   5000,
+				Tolerance: 1e-10,
+				Src:       rand.NewSource(2),
+			}
+			var f NMF
+			err := f.Factorize(&a, test.k, settings)
+			if err != nil && err != ErrNotConverged {
+				t.Fatalf("m=%d n=%d k=%d method=%d: unexpected error: %v", test.m, test.n, test.k, method, err)
+			}
+			if f.Rank() != test.k {
+				t.Errorf("m=%d n=%d k=%d method=%d: unexpected rank %d", test.m, test.n, test.k, method, f.Rank())
+			}
+			var w, h Dense
+			f.WTo(&w)
+			f.HTo(&h)
+			for _, x := range []*Dense{&w, &h} {
+				for _, v := range x.mat.Data {
+					if v < 0 {
+						t.Errorf("m=%d n=%d k=%d method=%d: negative factor element %v", test.m, test.n, test.k, method, v)
+					}
+				}
+			}
+			var r Dense
+			r.Mul(&w, &h)
+			r.Sub(&a, &r)
+			resid := Norm(&r, 2)
+			if math.Abs(resid-f.Residual()) > 1e-12*anorm {
+				t.Errorf("m=%d n=%d k=%d method=%d: residual mismatch: got %v, want %v", test.m, test.n, test.k, method, f.Residual(), resid)
+			}
+			if resid > 1e-2*anorm {
+				t.Errorf("m=%d n=%d k=%d method=%d: residual too large: %v, |A|=%v", test.m, test.n, test.k, method, resid, anorm)
+			}
+		}
+	}
+}
+
+func TestNMFErrors(t *testing.T) {
+	t.Parallel()
+	var f NMF
+	err := f.Factorize(NewDense(2, 2, []float64{1, 2, -1, 3}), 1, nil)
+	if err != errNMFNegative {
+		t.Errorf("unexpected error for negative input: %v", err)
+	}
+	panicked, message := panics(func() { f.Rank() })
+	if !panicked || message != badNMF {
+		t.Errorf("expected panic %q after failed factorization, got %q", badNMF, message)
+	}
+
+	a := NewDense(3, 3, []float64{
+		1, 2, 3,
+		4, 5, 6,
+		7, 8, 0,
+	})
+	err = f.Factorize(a, 2, &NMFSettings{MaxIter: 1, Tolerance: 1e-15, Src: rand.NewSource(1)})
+	if err != ErrNotConverged {
+		t.Errorf("unexpected error with iteration limit: %v", err)
+	}
+	if f.Iterations() != 1 {
+		t.Errorf("unexpected number of iterations: got %d, want 1", f.Iterations())
+	}
+
+	err = f.Factorize(NewDense(2, 3, nil), 2, nil)
+	if err != nil || f.Residual() != 0 {
+		t.Errorf("unexpected result for zero matrix: err=%v residual=%v", err, f.Residual())
+	}
+
+	panicked, message = panics(func() { f.Factorize(a, 0, nil) })
+	if !panicked || message != badNMFRank {
+		t.Errorf("expected panic %q for zero rank, got %q", badNMFRank, message)
+	}
+}
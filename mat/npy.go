@@ -0,0 +1,347 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// npyMagic is the magic string at the start of a NumPy .npy file.
+const npyMagic = "\x93NUMPY"
+
+var errNPYHeader = errors.New("mat: invalid npy header")
+
+// ReadNPY reads an array in the NumPy .npy format from r into a new Dense
+// matrix. Two-dimensional r×c arrays are returned as r×c matrices and
+// one-dimensional arrays of length n are returned as n×1 column matrices.
+// Arrays in both C and Fortran order are read.
+//
+// Arrays of little- or big-endian float64, float32, int64 and int32 elements
+// are read, with the elements converted to float64. Other element types and
+// arrays of other dimensions result in an error.
+func ReadNPY(r io.Reader) (*Dense, error) {
+	data, shape, err := ReadNPYFloats(r)
+	if err != nil {
+		return nil, err
+	}
+	switch len(shape) {
+	case 1:
+		shape = []int{shape[0], 1}
+	case 2:
+	default:
+		return nil, fmt.Errorf("mat: cannot read %d-dimensional npy array into Dense", len(shape))
+	}
+	if shape[0] == 0 || shape[1] == 0 {
+		return nil, ErrZeroLength
+	}
+	return NewDense(shape[0], shape[1], data), nil
+}
+
+// ReadNPYFloats reads an array of any dimension in the NumPy .npy format from
+// r, returning its elements in C order, so that the last index varies
+// fastest, and its shape. See ReadNPY for the supported element types.
+func ReadNPYFloats(r io.Reader) (data []float64, shape []int, err error) {
+	var pre [8]byte
+	_, err = io.ReadFull(r, pre[:])
+	if err != nil {
+		return nil, nil, err
+	}
+	if string(pre[:6]) != npyMagic {
+		return nil, nil, errors.New("mat: not an npy file")
+	}
+	var hlen int
+	switch pre[6] {
+	case 1:
+		var b [2]byte
+		_, err = io.ReadFull(r, b[:])
+		hlen = int(binary.LittleEndian.Uint16(b[:]))
+	case 2, 3:
+		var b [4]byte
+		_, err = io.ReadFull(r, b[:])
+		hlen = int(binary.LittleEndian.Uint32(b[:]))
+	default:
+		return nil, nil, fmt.Errorf("mat: unsupported npy version: %d.%d", pre[6], pre[7])
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	header := make([]byte, hlen)
+	_, err = io.ReadFull(r, header)
+	if err != nil {
+		return nil, nil, err
+	}
+	descr, fortran, shape, err := parseNPYHeader(string(header))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var order binary.ByteOrder
+	switch descr[0] {
+	case '<', '|', '=':
+		order = binary.LittleEndian
+	case '>':
+		order = binary.BigEndian
+	default:
+		return nil, nil, fmt.Errorf("mat: unsupported npy dtype: %q", descr)
+	}
+	var decode func([]byte) float64
+	var size int
+	switch descr[1:] {
+	case "f8":
+		size = 8
+		decode = func(b []byte) float64 { return math.Float64frombits(order.Uint64(b)) }
+	case "f4":
+		size = 4
+		decode = func(b []byte) float64 { return float64(math.Float32frombits(order.Uint32(b))) }
+	case "i8":
+		size = 8
+		decode = func(b []byte) float64 { return float64(int64(order.Uint64(b))) }
+	case "i4":
+		size = 4
+		decode = func(b []byte) float64 { return float64(int32(order.Uint32(b))) }
+	default:
+		return nil, nil, fmt.Errorf("mat: unsupported npy dtype: %q", descr)
+	}
+
+	n := 1
+	for _, d := range shape {
+		n *= d
+	}
+	buf := make([]byte, n*size)
+	_, err = io.ReadFull(r, buf)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, nil, err
+	}
+	data = make([]float64, n)
+	for i := range data {
+		data[i] = decode(buf[i*size : (i+1)*size])
+	}
+	if fortran && len(shape) > 1 {
+		data = fortranToC(data, shape)
+	}
+	return data, shape, nil
+}
+
+// parseNPYHeader parses the Python dictionary literal of an npy header.
+func parseNPYHeader(h string) (descr string, fortran bool, shape []int, err error) {
+	h = strings.TrimSpace(strings.Replace(h, `"`, "'", -1))
+	if !strings.HasPrefix(h, "{") || !strings.HasSuffix(h, "}") {
+		return "", false, nil, errNPYHeader
+	}
+	value := func(key string) (string, bool) {
+		k := strings.Index(h, "'"+key+"'")
+		if k < 0 {
+			return "", false
+		}
+		v := strings.TrimSpace(h[k+len(key)+2:])
+		if !strings.HasPrefix(v, ":") {
+			return "", false
+		}
+		return strings.TrimSpace(v[1:]), true
+	}
+
+	v, ok := value("descr")
+	if !ok || !strings.HasPrefix(v, "'") {
+		return "", false, nil, errNPYHeader
+	}
+	end := strings.Index(v[1:], "'")
+	if end < 0 {
+		return "", false, nil, errNPYHeader
+	}
+	descr = v[1 : end+1]
+	if len(descr) < 2 {
+		return "", false, nil, errNPYHeader
+	}
+
+	v, ok = value("fortran_order")
+	switch {
+	case ok && strings.HasPrefix(v, "True"):
+		fortran = true
+	case ok && strings.HasPrefix(v, "False"):
+	default:
+		return "", false, nil, errNPYHeader
+	}
+
+	v, ok = value("shape")
+	if !ok || !strings.HasPrefix(v, "(") {
+		return "", false, nil, errNPYHeader
+	}
+	end = strings.Index(v, ")")
+	if end < 0 {
+		return "", false, nil, errNPYHeader
+	}
+	shape = []int{}
+	for _, f := range strings.Split(v[1:end], ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		d, err := strconv.Atoi(strings.TrimSuffix(f, "L"))
+		if err != nil || d < 0 {
+			return "", false, nil, errNPYHeader
+		}
+		shape = append(shape, d)
+	}
+	return descr, fortran, shape, nil
+}
+
+// fortranToC returns the elements of the array with the given shape, stored
+// in Fortran order in data, in C order.
+func fortranToC(data []float64, shape []int) []float64 {
+	dst := make([]float64, len(data))
+	idx := make([]int, len(shape))
+	for _, v := range data {
+		// idx is the index of v, whose first element varies fastest.
+		var c int
+		for d, i := range idx {
+			c = c*shape[d] + i
+		}
+		dst[c] = v
+		for d := range idx {
+			idx[d]++
+			if idx[d] < shape[d] {
+				break
+			}
+			idx[d] = 0
+		}
+	}
+	return dst
+}
+
+// WriteNPY writes the r×c matrix a to w in the NumPy .npy format as a
+// two-dimensional array of little-endian float64 elements in C order.
+func WriteNPY(w io.Writer, a Matrix) error {
+	r, c := a.Dims()
+	err := writeNPYHeader(w, []int{r, c})
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, 8*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			binary.LittleEndian.PutUint64(buf[8*j:], math.Float64bits(a.At(i, j)))
+		}
+		_, err = w.Write(buf)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteNPYFloats writes data to w in the NumPy .npy format as a
+// one-dimensional array of little-endian float64 elements.
+func WriteNPYFloats(w io.Writer, data []float64) error {
+	err := writeNPYHeader(w, []int{len(data)})
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, 8*len(data))
+	for i, v := range data {
+		binary.LittleEndian.PutUint64(buf[8*i:], math.Float64bits(v))
+	}
+	_, err = w.Write(buf)
+	return err
+}
+
+// writeNPYHeader writes the preamble and header of an npy file holding a
+// little-endian float64 array in C order with the given shape.
+func writeNPYHeader(w io.Writer, shape []int) error {
+	dims := make([]string, len(shape))
+	for i, d := range shape {
+		dims[i] = strconv.Itoa(d)
+	}
+	s := strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		s += ","
+	}
+	header := fmt.Sprintf("{'descr': '<f8', 'fortran_order': False, 'shape': (%s), }", s)
+
+	// The total length of the preamble and the newline terminated header
+	// is padded with spaces to a multiple of 64 bytes.
+	var buf bytes.Buffer
+	buf.WriteString(npyMagic)
+	pre := 10
+	version := []byte{1, 0}
+	if len(header)+pre+1 > math.MaxUint16 {
+		pre = 12
+		version = []byte{2, 0}
+	}
+	pad := (64 - (pre+len(header)+1)%64) % 64
+	hlen := len(header) + pad + 1
+	buf.Write(version)
+	if pre == 10 {
+		binary.Write(&buf, binary.LittleEndian, uint16(hlen))
+	} else {
+		binary.Write(&buf, binary.LittleEndian, uint32(hlen))
+	}
+	buf.WriteString(header)
+	buf.WriteString(strings.Repeat(" ", pad))
+	buf.WriteByte('\n')
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ReadNPZ reads all the arrays of the NumPy .npz archive in r, which has the
+// given size in bytes. The arrays are returned keyed by their names without
+// the .npy extension. See ReadNPY for the arrays that can be read. Both
+// compressed and uncompressed archives are read.
+func ReadNPZ(r io.ReaderAt, size int64) (map[string]*Dense, error) {
+	z, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	arrays := make(map[string]*Dense, len(z.File))
+	for _, f := range z.File {
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		m, err := ReadNPY(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("mat: reading %s: %v", f.Name, err)
+		}
+		arrays[strings.TrimSuffix(f.Name, ".npy")] = m
+	}
+	return arrays, nil
+}
+
+// WriteNPZ writes the named matrices to w as an uncompressed NumPy .npz
+// archive that can be read by numpy.load. Each matrix is written with WriteNPY
+// to an archive member with the name and the .npy extension, in sorted order
+// of the names.
+func WriteNPZ(w io.Writer, arrays map[string]Matrix) error {
+	names := make([]string, 0, len(arrays))
+	for name := range arrays {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	z := zip.NewWriter(w)
+	for _, name := range names {
+		f, err := z.CreateHeader(&zip.FileHeader{Name: name + ".npy", Method: zip.Store})
+		if err != nil {
+			return err
+		}
+		err = WriteNPY(f, arrays[name])
+		if err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
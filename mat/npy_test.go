@@ -0,0 +1,212 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// npyFile returns an npy version 1.0 file with the given header dictionary
+// and data.
+func npyFile(header string, data []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(npyMagic)
+	buf.Write([]byte{1, 0})
+	binary.Write(&buf, binary.LittleEndian, uint16(len(header)+1))
+	buf.WriteString(header)
+	buf.WriteByte('\n')
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+func TestReadNPY(t *testing.T) {
+	t.Parallel()
+	be := make([]byte, 6*8)
+	for i, v := range []float64{1, 4, 2, 5, 3, 6} {
+		binary.BigEndian.PutUint64(be[8*i:], math.Float64bits(v))
+	}
+	i4 := make([]byte, 3*4)
+	for i, v := range []int32{-1, 0, 7} {
+		binary.LittleEndian.PutUint32(i4[4*i:], uint32(v))
+	}
+	f4 := make([]byte, 4*4)
+	for i, v := range []float32{0.5, 1.5, 2.5, 3.5} {
+		binary.LittleEndian.PutUint32(f4[4*i:], math.Float32bits(v))
+	}
+
+	for _, test := range []struct {
+		name string
+		file []byte
+		want *Dense
+	}{
+		{
+			name: "fortran big-endian f8",
+			file: npyFile(`{'descr': '>f8', 'fortran_order': True, 'shape': (2, 3), }`, be),
+			want: NewDense(2, 3, []float64{
+				1, 2, 3,
+				4, 5, 6,
+			}),
+		},
+		{
+			name: "1-D i4",
+			file: npyFile(`{'descr': '<i4', 'fortran_order': False, 'shape': (3,), }`, i4),
+			want: NewDense(3, 1, []float64{-1, 0, 7}),
+		},
+		{
+			name: "f4 double quotes",
+			file: npyFile(`{"descr": "<f4", "shape": (2, 2), "fortran_order": False}`, f4),
+			want: NewDense(2, 2, []float64{
+				0.5, 1.5,
+				2.5, 3.5,
+			}),
+		},
+	} {
+		got, err := ReadNPY(bytes.NewReader(test.file))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !Equal(got, test.want) {
+			t.Errorf("%s: unexpected matrix:\ngot:\n%v\nwant:\n%v", test.name, Formatted(got), Formatted(test.want))
+		}
+	}
+
+	for _, file := range [][]byte{
+		nil,
+		[]byte("not an npy file"),
+		npyFile(`{'descr': '<c16', 'fortran_order': False, 'shape': (1, 1), }`, make([]byte, 16)),
+		npyFile(`{'descr': '<f8', 'fortran_order': False, 'shape': (1, 1, 1), }`, make([]byte, 8)),
+		npyFile(`{'descr': '<f8', 'fortran_order': False, 'shape': (2, 2), }`, make([]byte, 24)),
+		npyFile(`{'descr': '<f8', 'shape': (2, 2), }`, make([]byte, 32)),
+		npyFile(`{'descr': '<f8', 'fortran_order': False, 'shape': (0, 2), }`, nil),
+	} {
+		_, err := ReadNPY(bytes.NewReader(file))
+		if err == nil {
+			t.Errorf("expected error for input %q", file)
+		}
+	}
+}
+
+func TestReadNPYFloatsFortran(t *testing.T) {
+	t.Parallel()
+	// A 2×3×2 array with elements equal to their C order offset, stored
+	// in Fortran order.
+	shape := []int{2, 3, 2}
+	var data []byte
+	for k := 0; k < 2; k++ {
+		for j := 0; j < 3; j++ {
+			for i := 0; i < 2; i++ {
+				var b [8]byte
+				binary.LittleEndian.PutUint64(b[:], math.Float64bits(float64(i*6+j*2+k)))
+				data = append(data, b[:]...)
+			}
+		}
+	}
+	file := npyFile(`{'descr': '<f8', 'fortran_order': True, 'shape': (2, 3, 2), }`, data)
+	got, gotShape, err := ReadNPYFloats(bytes.NewReader(file))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(gotShape, shape) {
+		t.Errorf("unexpected shape: got:%v want:%v", gotShape, shape)
+	}
+	for i, v := range got {
+		if v != float64(i) {
+			t.Errorf("unexpected data: got:%v", got)
+			break
+		}
+	}
+}
+
+func TestNPYRoundTrip(t *testing.T) {
+	t.Parallel()
+	for _, a := range []Matrix{
+		NewDense(1, 1, []float64{math.Pi}),
+		NewDense(2, 3, []float64{1, 2, 3, 4, math.Inf(-1), 1.0 / 3}),
+		NewSymDense(2, []float64{1, 2, 2, 3}),
+		NewDense(3, 2, []float64{1, 2, 3, 4, 5, 6}).T(),
+	} {
+		var buf bytes.Buffer
+		err := WriteNPY(&buf, a)
+		if err != nil {
+			t.Fatalf("unexpected error writing: %v", err)
+		}
+		r, c := a.Dims()
+		if hlen := int(binary.LittleEndian.Uint16(buf.Bytes()[8:])); (10+hlen)%64 != 0 {
+			t.Errorf("header not aligned: length %d", 10+hlen)
+		}
+		if buf.Len()%64 != (8*r*c)%64 {
+			t.Errorf("unexpected length: %d", buf.Len())
+		}
+		got, err := ReadNPY(&buf)
+		if err != nil {
+			t.Fatalf("unexpected error reading: %v", err)
+		}
+		if !Equal(got, a) {
+			t.Errorf("round trip failed:\ngot:\n%v\nwant:\n%v", Formatted(got), Formatted(a))
+		}
+	}
+
+	want := []float64{1, -2, 3.5}
+	var buf bytes.Buffer
+	err := WriteNPYFloats(&buf, want)
+	if err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	if !strings.Contains(buf.String(), "'shape': (3,)") {
+		t.Errorf("unexpected 1-D header: %q", buf.String())
+	}
+	got, shape, err := ReadNPYFloats(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error reading: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) || !reflect.DeepEqual(shape, []int{3}) {
+		t.Errorf("unexpected 1-D round trip: got:%v %v want:%v", got, shape, want)
+	}
+}
+
+func TestNPZRoundTrip(t *testing.T) {
+	t.Parallel()
+	arrays := map[string]Matrix{
+		"a": NewDense(2, 2, []float64{1, 2, 3, 4}),
+		"b": NewVecDense(3, []float64{5, 6, 7}),
+	}
+	var buf bytes.Buffer
+	err := WriteNPZ(&buf, arrays)
+	if err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("unexpected error opening archive: %v", err)
+	}
+	var names []string
+	for _, f := range z.File {
+		names = append(names, f.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"a.npy", "b.npy"}) {
+		t.Errorf("unexpected archive members: %v", names)
+	}
+
+	got, err := ReadNPZ(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("unexpected error reading: %v", err)
+	}
+	if len(got) != len(arrays) {
+		t.Errorf("unexpected number of arrays: got:%d want:%d", len(got), len(arrays))
+	}
+	for name, a := range arrays {
+		if !Equal(got[name], a) {
+			t.Errorf("%s: round trip failed:\ngot:\n%v\nwant:\n%v", name, Formatted(got[name]), Formatted(a))
+		}
+	}
+}
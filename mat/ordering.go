@@ -0,0 +1,156 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "sort"
+
+// minimumDegree returns a fill-reducing elimination order of the nodes of an
+// undirected graph. adj holds the sorted adjacency lists of the nodes without
+// self loops, and it is modified by minimumDegree. order[k] is the node that is
+// eliminated at step k.
+//
+// At each step, the node of minimum degree in the elimination graph is
+// eliminated and its neighbors are joined into a clique. Ties are broken in
+// favor of the node with the smallest index.
+func minimumDegree(adj [][]int) []int {
+	n := len(adj)
+	// The nodes of each degree are kept in a doubly linked list.
+	head := make([]int, n)
+	next := make([]int, n)
+	prev := make([]int, n)
+	deg := make([]int, n)
+	for d := range head {
+		head[d] = -1
+	}
+	insert := func(v int) {
+		d := deg[v]
+		prev[v] = -1
+		next[v] = head[d]
+		if head[d] != -1 {
+			prev[head[d]] = v
+		}
+		head[d] = v
+	}
+	remove := func(v int) {
+		if prev[v] != -1 {
+			next[prev[v]] = next[v]
+		} else {
+			head[deg[v]] = next[v]
+		}
+		if next[v] != -1 {
+			prev[next[v]] = prev[v]
+		}
+	}
+	for v := n - 1; v >= 0; v-- {
+		deg[v] = len(adj[v])
+		insert(v)
+	}
+
+	mark := make([]int, n)
+	for i := range mark {
+		mark[i] = -1
+	}
+	order := make([]int, 0, n)
+	minDeg := 0
+	for len(order) < n {
+		for head[minDeg] == -1 {
+			minDeg++
+		}
+		v := head[minDeg]
+		remove(v)
+		order = append(order, v)
+
+		nbrs := adj[v]
+		adj[v] = nil
+		for _, u := range nbrs {
+			mark[u] = v
+		}
+		mark[v] = v
+		for _, u := range nbrs {
+			// Join u to the other neighbors of v, and remove v.
+			merged := make([]int, 0, len(adj[u])+len(nbrs))
+			for _, w := range adj[u] {
+				if w != v && mark[w] != v {
+					merged = append(merged, w)
+				}
+			}
+			for _, w := range nbrs {
+				if w != u {
+					merged = append(merged, w)
+				}
+			}
+			sort.Ints(merged)
+			adj[u] = merged
+			remove(u)
+			deg[u] = len(merged)
+			insert(u)
+			if deg[u] < minDeg {
+				minDeg = deg[u]
+			}
+		}
+	}
+	return order
+}
+
+// symmetricPattern returns the adjacency lists of the graph of the symmetric
+// n×n sparsity pattern of c + cᵀ, excluding the diagonal.
+func symmetricPattern(c *compressed) [][]int {
+	t := c.transpose()
+	adj := make([][]int, c.major)
+	for k := range adj {
+		a := c.ind[c.ptr[k]:c.ptr[k+1]]
+		b := t.ind[t.ptr[k]:t.ptr[k+1]]
+		adj[k] = mergeIndices(make([]int, 0, len(a)+len(b)), a, b, k)
+	}
+	return adj
+}
+
+// columnIntersectionPattern returns the adjacency lists of the graph of the
+// sparsity pattern of cᵀc, excluding the diagonal, where the major index of c
+// is its row index. Two columns are adjacent if they have a non-zero element
+// in the same row.
+func columnIntersectionPattern(c *compressed) [][]int {
+	t := c.transpose()
+	adj := make([][]int, c.minor)
+	mark := make([]int, c.minor)
+	for j := range mark {
+		mark[j] = -1
+	}
+	for j := 0; j < t.major; j++ {
+		mark[j] = j
+		for p := t.ptr[j]; p < t.ptr[j+1]; p++ {
+			i := t.ind[p]
+			for q := c.ptr[i]; q < c.ptr[i+1]; q++ {
+				l := c.ind[q]
+				if mark[l] != j {
+					mark[l] = j
+					adj[j] = append(adj[j], l)
+				}
+			}
+		}
+		sort.Ints(adj[j])
+	}
+	return adj
+}
+
+// mergeIndices appends the union of the sorted indices a and b excluding skip
+// to dst.
+func mergeIndices(dst, a, b []int, skip int) []int {
+	for len(a) > 0 || len(b) > 0 {
+		var v int
+		switch {
+		case len(b) == 0 || (len(a) > 0 && a[0] < b[0]):
+			v, a = a[0], a[1:]
+		case len(a) == 0 || b[0] < a[0]:
+			v, b = b[0], b[1:]
+		default:
+			v, a, b = a[0], a[1:], b[1:]
+		}
+		if v != skip {
+			dst = append(dst, v)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,186 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+const badPermutation = "mat: invalid permutation"
+
+var (
+	permutation *Permutation
+	_           Matrix = permutation
+)
+
+// Permutation represents an n×n permutation matrix P, which has a single one
+// in each row and column and zeros elsewhere. It is stored as a slice of n
+// indices, perm, so that the one in row i of P is in column perm[i]. Hence row
+// i of P*A is row perm[i] of A, and column j of A*Pᵀ is column perm[j] of A.
+//
+// Applying a Permutation with Dense.PermuteRows or Dense.PermuteCols takes time
+// proportional to the number of elements moved, rather than the time of the
+// multiplication by an explicit permutation matrix.
+type Permutation struct {
+	perm []int
+}
+
+// NewPermutation creates a new n×n permutation matrix with the one in row i
+// in column perm[i]. If perm is nil, the identity permutation is returned.
+// Otherwise perm must hold each of the integers 0 to n-1 exactly once, and it
+// is used as the backing data of the matrix. The pivots of an LU factorization
+// returned by LU.Pivot have this form. NewPermutation will panic if n is zero,
+// the length of perm is not n, or perm is not a permutation.
+func NewPermutation(n int, perm []int) *Permutation {
+	if n <= 0 {
+		if n == 0 {
+			panic(ErrZeroLength)
+		}
+		panic(ErrNegativeDimension)
+	}
+	if perm == nil {
+		perm = make([]int, n)
+		for i := range perm {
+			perm[i] = i
+		}
+		return &Permutation{perm: perm}
+	}
+	if len(perm) != n {
+		panic(ErrShape)
+	}
+	seen := make([]bool, n)
+	for _, v := range perm {
+		if uint(v) >= uint(n) || seen[v] {
+			panic(badPermutation)
+		}
+		seen[v] = true
+	}
+	return &Permutation{perm: perm}
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (p *Permutation) Dims() (r, c int) {
+	return len(p.perm), len(p.perm)
+}
+
+// At returns the element at row i, column j.
+func (p *Permutation) At(i, j int) float64 {
+	n := len(p.perm)
+	if uint(i) >= uint(n) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(n) {
+		panic(ErrColAccess)
+	}
+	if p.perm[i] == j {
+		return 1
+	}
+	return 0
+}
+
+// T performs an implicit transpose by returning the receiver inside a
+// Transpose. The explicit transpose is returned by Inverse.
+func (p *Permutation) T() Matrix {
+	return Transpose{p}
+}
+
+// Indices copies the indices of the permutation into dst and returns it. If
+// dst is nil, a new slice is allocated, otherwise its length must be the size
+// of the permutation.
+func (p *Permutation) Indices(dst []int) []int {
+	if dst == nil {
+		dst = make([]int, len(p.perm))
+	}
+	if len(dst) != len(p.perm) {
+		panic(badSliceLength)
+	}
+	copy(dst, p.perm)
+	return dst
+}
+
+// Inverse returns the inverse of the permutation, which is its transpose.
+func (p *Permutation) Inverse() *Permutation {
+	inv := make([]int, len(p.perm))
+	for i, v := range p.perm {
+		inv[v] = i
+	}
+	return &Permutation{perm: inv}
+}
+
+// Mul sets the receiver to the composition of the permutations a and b, the
+// permutation matrix A*B, so that A*B*C is the result of permuting the rows of
+// C by B and then by A. Mul will panic if a and b have different sizes.
+func (p *Permutation) Mul(a, b *Permutation) {
+	n := len(a.perm)
+	if len(b.perm) != n {
+		panic(ErrShape)
+	}
+	perm := p.perm
+	if perm == nil || len(perm) != n || p == a || p == b {
+		perm = make([]int, n)
+	}
+	// Row i of A*B has its one in the column of the one in row a.perm[i]
+	// of B.
+	for i, v := range a.perm {
+		perm[i] = b.perm[v]
+	}
+	p.perm = perm
+}
+
+// PermuteRows sets the receiver to P*M, or Pᵀ*M if trans is true, in place,
+// where M is the receiver, so that row i of the receiver is replaced by row
+// p.perm[i] if trans is false. PermuteRows will panic if the number of rows
+// of the receiver is not the size of p.
+func (m *Dense) PermuteRows(p *Permutation, trans bool) {
+	r, c := m.Dims()
+	if r != len(p.perm) {
+		panic(ErrShape)
+	}
+	src := p.perm
+	if trans {
+		src = p.Inverse().perm
+	}
+	// Follow each cycle of the permutation, moving each row once.
+	done := make([]bool, r)
+	tmp := getFloats(c, false)
+	defer putFloats(tmp)
+	for s := range src {
+		if done[s] || src[s] == s {
+			continue
+		}
+		copy(tmp, m.rawRowView(s))
+		i := s
+		for {
+			done[i] = true
+			j := src[i]
+			if j == s {
+				copy(m.rawRowView(i), tmp)
+				break
+			}
+			copy(m.rawRowView(i), m.rawRowView(j))
+			i = j
+		}
+	}
+}
+
+// PermuteCols sets the receiver to M*Pᵀ, or M*P if trans is true, in place,
+// where M is the receiver, so that column j of the receiver is replaced by
+// column p.perm[j] if trans is false. PermuteCols will panic if the number of
+// columns of the receiver is not the size of p.
+func (m *Dense) PermuteCols(p *Permutation, trans bool) {
+	r, c := m.Dims()
+	if c != len(p.perm) {
+		panic(ErrShape)
+	}
+	src := p.perm
+	if trans {
+		src = p.Inverse().perm
+	}
+	tmp := getFloats(c, false)
+	defer putFloats(tmp)
+	for i := 0; i < r; i++ {
+		row := m.rawRowView(i)
+		copy(tmp, row)
+		for j, k := range src {
+			row[j] = tmp[k]
+		}
+	}
+}
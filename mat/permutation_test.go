@@ -0,0 +1,98 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestPermutation(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 5, 20} {
+		for k := 0; k < 5; k++ {
+			p := NewPermutation(n, rnd.Perm(n))
+			var want Dense
+			want.Permutation(n, p.Indices(nil))
+			if !Equal(p, &want) {
+				t.Errorf("n=%d: unexpected permutation matrix:\ngot:\n%v\nwant:\n%v", n, Formatted(p), Formatted(&want))
+			}
+			if !Equal(p.Inverse(), p.T()) {
+				t.Errorf("n=%d: inverse not equal to transpose", n)
+			}
+
+			q := NewPermutation(n, rnd.Perm(n))
+			var pq Permutation
+			pq.Mul(p, q)
+			var wantPQ Dense
+			wantPQ.Mul(p, q)
+			if !Equal(&pq, &wantPQ) {
+				t.Errorf("n=%d: unexpected composition", n)
+			}
+			pq.Mul(&pq, pq.Inverse())
+			if !Equal(&pq, NewPermutation(n, nil)) {
+				t.Errorf("n=%d: composition with inverse is not the identity", n)
+			}
+
+			a := randNormal(n, n+3, rnd)
+			for _, trans := range []bool{false, true} {
+				var pm Matrix = p
+				if trans {
+					pm = p.T()
+				}
+				var wantRows Dense
+				wantRows.Mul(pm, a)
+				rows := DenseCopyOf(a)
+				rows.PermuteRows(p, trans)
+				if !Equal(rows, &wantRows) {
+					t.Errorf("n=%d trans=%t: unexpected row permutation", n, trans)
+				}
+
+				var wantCols Dense
+				wantCols.Mul(a.T(), pm.T())
+				cols := DenseCopyOf(a.T())
+				cols.PermuteCols(p, trans)
+				if !Equal(cols, &wantCols) {
+					t.Errorf("n=%d trans=%t: unexpected column permutation", n, trans)
+				}
+			}
+		}
+	}
+
+	// The LU pivots form a permutation with A = P*L*U.
+	a := randNormal(6, 6, rnd)
+	var lu LU
+	lu.Factorize(a)
+	p := NewPermutation(6, lu.Pivot(nil))
+	var l, u TriDense
+	lu.LTo(&l)
+	lu.UTo(&u)
+	var plu Dense
+	plu.Mul(&l, &u)
+	plu.PermuteRows(p, false)
+	if !EqualApprox(&plu, a, 1e-12) {
+		t.Errorf("unexpected product of LU factors")
+	}
+
+	if got := NewPermutation(3, nil).Indices(nil); !reflect.DeepEqual(got, []int{0, 1, 2}) {
+		t.Errorf("unexpected identity indices: %v", got)
+	}
+	for _, fn := range []func(){
+		func() { NewPermutation(0, nil) },
+		func() { NewPermutation(2, []int{0}) },
+		func() { NewPermutation(2, []int{1, 1}) },
+		func() { NewPermutation(2, []int{0, 2}) },
+		func() { NewDense(3, 2, nil).PermuteRows(NewPermutation(2, nil), false) },
+		func() { NewDense(3, 2, nil).PermuteCols(NewPermutation(3, nil), false) },
+		func() { new(Permutation).Mul(NewPermutation(2, nil), NewPermutation(3, nil)) },
+	} {
+		if ok, _ := panics(fn); !ok {
+			t.Errorf("expected panic")
+		}
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/lapack/lapack64"
+)
+
+const badPivotedQR = "mat: invalid pivoted QR factorization"
+
+// PivotedQR is a type for creating and using the QR factorization with column
+// pivoting of a matrix. The factorization reveals the numerical rank of the
+// matrix and can be used to solve rank-deficient least-squares problems.
+type PivotedQR struct {
+	qr  *Dense
+	tau []float64
+	piv []int
+}
+
+// Factorize computes the QR factorization with column pivoting of the m×n
+// matrix a. The factorization always exists even if A is singular.
+//
+// The factorization is
+//  A * P = Q * R
+// where P is a permutation matrix, Q is an orthonormal m×m matrix and R is an
+// m×n upper trapezoidal matrix whose diagonal elements are non-increasing in
+// absolute value. At each step the remaining column of largest norm is chosen
+// as the pivot.
+func (qr *PivotedQR) Factorize(a Matrix) {
+	m, n := a.Dims()
+	if qr.qr == nil {
+		qr.qr = &Dense{}
+	}
+	qr.qr.Reset()
+	qr.qr.reuseAsNonZeroed(m, n)
+	qr.qr.Copy(a)
+	qr.tau = use(qr.tau, min(m, n))
+	qr.piv = useInt(qr.piv, n)
+	for j := range qr.piv {
+		qr.piv[j] = -1
+	}
+	work := []float64{0}
+	lapack64.Geqp3(qr.qr.mat, qr.piv, qr.tau, work, -1)
+	work = getFloats(int(work[0]), false)
+	lapack64.Geqp3(qr.qr.mat, qr.piv, qr.tau, work, len(work))
+	putFloats(work)
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (qr *PivotedQR) isValid() bool {
+	return qr.qr != nil && !qr.qr.IsEmpty()
+}
+
+// Reset resets the factorization so that it can be reused as the receiver of a
+// dimensionally restricted operation.
+func (qr *PivotedQR) Reset() {
+	if qr.qr != nil {
+		qr.qr.Reset()
+	}
+	qr.tau = qr.tau[:0]
+	qr.piv = qr.piv[:0]
+}
+
+// Rank returns the numerical rank of the factorized matrix, the number of
+// diagonal elements of R whose absolute value is larger than tol times the
+// absolute value of the first. If tol is not positive, a default tolerance of
+// max(m,n) times the machine epsilon is used.
+// Rank will panic if the receiver does not contain a factorization.
+func (qr *PivotedQR) Rank(tol float64) int {
+	if !qr.isValid() {
+		panic(badPivotedQR)
+	}
+	m, n := qr.qr.Dims()
+	if tol <= 0 {
+		tol = float64(max(m, n)) * dlamchP
+	}
+	r0 := math.Abs(qr.qr.at(0, 0))
+	var rank int
+	for i := 0; i < min(m, n); i++ {
+		if math.Abs(qr.qr.at(i, i)) <= tol*r0 {
+			break
+		}
+		rank++
+	}
+	return rank
+}
+
+// Pivot returns the column pivots of the factorization, such that the jth
+// column of A*P is the column piv[j] of A. If piv is nil, then new memory will
+// be allocated, otherwise the length of the input must be equal to the number
+// of columns of the factorized matrix.
+// Pivot will panic if the receiver does not contain a factorization.
+func (qr *PivotedQR) Pivot(piv []int) []int {
+	if !qr.isValid() {
+		panic(badPivotedQR)
+	}
+	if piv == nil {
+		piv = make([]int, len(qr.piv))
+	}
+	if len(piv) != len(qr.piv) {
+		panic(badSliceLength)
+	}
+	copy(piv, qr.piv)
+	return piv
+}
+
+// RTo extracts the m×n upper trapezoidal matrix R from the factorization.
+//
+// If dst is empty, RTo will resize dst to be m×n. When dst is non-empty,
+// RTo will panic if dst is not m×n. RTo will also panic if the receiver
+// does not contain a factorization.
+func (qr *PivotedQR) RTo(dst *Dense) {
+	if !qr.isValid() {
+		panic(badPivotedQR)
+	}
+	m, n := qr.qr.Dims()
+	if dst.IsEmpty() {
+		dst.ReuseAs(m, n)
+	} else {
+		r, c := dst.Dims()
+		if r != m || c != n {
+			panic(ErrShape)
+		}
+	}
+	for i := 0; i < m; i++ {
+		row := dst.mat.Data[i*dst.mat.Stride : i*dst.mat.Stride+n]
+		zero(row[:min(i, n)])
+		if i < n {
+			copy(row[i:], qr.qr.mat.Data[i*qr.qr.mat.Stride+i:i*qr.qr.mat.Stride+n])
+		}
+	}
+}
+
+// QTo extracts the m×m orthonormal matrix Q from the factorization.
+//
+// If dst is empty, QTo will resize dst to be m×m. When dst is non-empty,
+// QTo will panic if dst is not m×m. QTo will also panic if the receiver
+// does not contain a factorization.
+func (qr *PivotedQR) QTo(dst *Dense) {
+	if !qr.isValid() {
+		panic(badPivotedQR)
+	}
+	m, _ := qr.qr.Dims()
+	if dst.IsEmpty() {
+		dst.ReuseAs(m, m)
+	} else {
+		r, c := dst.Dims()
+		if r != m || c != m {
+			panic(ErrShape)
+		}
+		dst.Zero()
+	}
+	for i := 0; i < m; i++ {
+		dst.set(i, i, 1)
+	}
+	qr.applyQ(blas.NoTrans, dst)
+}
+
+// applyQ overwrites b with Q*b or Qᵀ*b.
+func (qr *PivotedQR) applyQ(trans blas.Transpose, b *Dense) {
+	// Only the first min(m,n) columns of the factor hold reflectors.
+	m, n := qr.qr.Dims()
+	v := qr.qr.slice(0, m, 0, min(m, n))
+	work := []float64{0}
+	lapack64.Ormqr(blas.Left, trans, v.mat, qr.tau, b.mat, work, -1)
+	work = getFloats(int(work[0]), false)
+	lapack64.Ormqr(blas.Left, trans, v.mat, qr.tau, b.mat, work, len(work))
+	putFloats(work)
+}
+
+// SolveTo finds the basic solution of the least-squares problem
+//  minimize ||A*X - B||_2
+// where A is an m×n matrix represented in its pivoted QR factorized form and
+// rank is its numerical rank, typically obtained from the Rank method. The
+// basic solution has at most rank non-zero rows, which correspond to the
+// first rank pivot columns of A. It coincides with the unique least-squares
+// solution if A has full column rank, but is not the minimum norm solution
+// in general. The solution matrix, X, is stored in place into dst.
+//
+// If the leading rank×rank block of R is singular, a Condition error is
+// returned. SolveTo will panic if rank is not in [0, min(m,n)], or if the
+// receiver does not contain a factorization.
+func (qr *PivotedQR) SolveTo(dst *Dense, rank int, b Matrix) error {
+	if !qr.isValid() {
+		panic(badPivotedQR)
+	}
+	m, n := qr.qr.Dims()
+	if rank < 0 || min(m, n) < rank {
+		panic(badTruncRank)
+	}
+	br, bc := b.Dims()
+	if br != m {
+		panic(ErrShape)
+	}
+
+	w := getWorkspace(m, bc, false)
+	defer putWorkspace(w)
+	w.Copy(b)
+	qr.applyQ(blas.Trans, w)
+
+	dst.reuseAsZeroed(n, bc)
+	if rank == 0 {
+		return nil
+	}
+	r11 := qr.qr.slice(0, rank, 0, rank).asTriDense(rank, blas.NonUnit, blas.Upper)
+	c := w.slice(0, rank, 0, bc)
+	ok := lapack64.Trtrs(blas.NoTrans, r11.mat, c.mat)
+	if !ok {
+		return Condition(math.Inf(1))
+	}
+	for i := 0; i < rank; i++ {
+		copy(dst.mat.Data[qr.piv[i]*dst.mat.Stride:qr.piv[i]*dst.mat.Stride+bc], c.mat.Data[i*c.mat.Stride:i*c.mat.Stride+bc])
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// randRankDeficient returns a random m×n matrix of rank r.
+func randRankDeficient(m, n, r int, rnd *rand.Rand) *Dense {
+	var a Dense
+	a.Mul(randNormal(m, r, rnd), randNormal(r, n, rnd))
+	return &a
+}
+
+func TestPivotedQR(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, rank int
+	}{
+		{m: 1, n: 1, rank: 1},
+		{m: 5, n: 5, rank: 5},
+		{m: 5, n: 5, rank: 3},
+		{m: 10, n: 6, rank: 6},
+		{m: 10, n: 6, rank: 2},
+		{m: 6, n: 10, rank: 6},
+		{m: 6, n: 10, rank: 4},
+		{m: 50, n: 30, rank: 17},
+	} {
+		m, n := test.m, test.n
+		a := randRankDeficient(m, n, test.rank, rnd)
+
+		var qr PivotedQR
+		qr.Factorize(a)
+		if rank := qr.Rank(0); rank != test.rank {
+			t.Errorf("m=%d,n=%d: unexpected rank: got %d, want %d", m, n, rank, test.rank)
+		}
+
+		var q, r Dense
+		qr.QTo(&q)
+		qr.RTo(&r)
+		if !isOrthonormal(&q, 1e-12) {
+			t.Errorf("m=%d,n=%d: Q is not orthonormal", m, n)
+		}
+		for i := 0; i < m; i++ {
+			for j := 0; j < min(i, n); j++ {
+				if r.At(i, j) != 0 {
+					t.Errorf("m=%d,n=%d: R is not upper trapezoidal", m, n)
+				}
+			}
+		}
+
+		// A*P = Q*R.
+		piv := qr.Pivot(nil)
+		seen := make([]bool, n)
+		ap := NewDense(m, n, nil)
+		for j, p := range piv {
+			if p < 0 || n <= p || seen[p] {
+				t.Fatalf("m=%d,n=%d: invalid pivots %v", m, n, piv)
+			}
+			seen[p] = true
+			for i := 0; i < m; i++ {
+				ap.set(i, j, a.At(i, p))
+			}
+		}
+		var qrm Dense
+		qrm.Mul(&q, &r)
+		if !EqualApprox(ap, &qrm, 1e-12) {
+			t.Errorf("m=%d,n=%d: A*P != Q*R", m, n)
+		}
+	}
+}
+
+func TestPivotedQRSolveTo(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, rank, bc int
+	}{
+		{m: 5, n: 5, rank: 5, bc: 1},
+		{m: 10, n: 6, rank: 6, bc: 3},
+		{m: 10, n: 6, rank: 3, bc: 2},
+		{m: 6, n: 10, rank: 4, bc: 1},
+		{m: 40, n: 25, rank: 12, bc: 4},
+	} {
+		m, n, bc := test.m, test.n, test.bc
+		a := randRankDeficient(m, n, test.rank, rnd)
+		b := randNormal(m, bc, rnd)
+
+		var qr PivotedQR
+		qr.Factorize(a)
+		rank := qr.Rank(0)
+		var x Dense
+		err := qr.SolveTo(&x, rank, b)
+		if err != nil {
+			t.Fatalf("m=%d,n=%d: unexpected error: %v", m, n, err)
+		}
+		if r, c := x.Dims(); r != n || c != bc {
+			t.Fatalf("m=%d,n=%d: unexpected solution shape %d×%d", m, n, r, c)
+		}
+
+		// The basic solution has at most rank non-zero rows.
+		piv := qr.Pivot(nil)
+		for _, p := range piv[rank:] {
+			for j := 0; j < bc; j++ {
+				if x.At(p, j) != 0 {
+					t.Errorf("m=%d,n=%d: unexpected non-zero in row %d", m, n, p)
+				}
+			}
+		}
+
+		// The residual of a least-squares solution is orthogonal to the
+		// range of A, so it must agree with the minimum norm solution.
+		var pinv, xmin Dense
+		if err := pinv.Pinv(a, 0); err != nil {
+			t.Fatalf("m=%d,n=%d: unexpected Pinv error: %v", m, n, err)
+		}
+		xmin.Mul(&pinv, b)
+		var ax, axmin Dense
+		ax.Mul(a, &x)
+		axmin.Mul(a, &xmin)
+		if !EqualApprox(&ax, &axmin, 1e-10) {
+			t.Errorf("m=%d,n=%d: solution is not a least-squares solution", m, n)
+		}
+	}
+
+	var qr PivotedQR
+	qr.Factorize(NewDense(3, 2, nil))
+	if rank := qr.Rank(0); rank != 0 {
+		t.Errorf("unexpected rank of zero matrix: %d", rank)
+	}
+	var x Dense
+	if err := qr.SolveTo(&x, 0, NewDense(3, 1, []float64{1, 2, 3})); err != nil {
+		t.Errorf("unexpected error for zero rank: %v", err)
+	}
+	if !Equal(&x, NewDense(2, 1, nil)) {
+		t.Errorf("unexpected solution for zero rank: %v", Formatted(&x))
+	}
+	if panicked, _ := panics(func() { qr.SolveTo(&x, 3, NewDense(3, 1, nil)) }); !panicked {
+		t.Errorf("expected panic for rank out of range")
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+const badPolar = "mat: invalid polar decomposition"
+
+// Polar is a type for creating and using the polar decomposition of a matrix.
+//
+// The polar decomposition of an m×n matrix A with m >= n is
+//  A = U * P
+// where U is an m×n matrix with orthonormal columns and P is an n×n symmetric
+// positive semidefinite matrix. P is always unique and is equal to (Aᵀ*A)^½.
+// If A has full column rank, U is unique and P is positive definite.
+type Polar struct {
+	u *Dense
+	p *SymDense
+}
+
+// Factorize computes the polar decomposition of the m×n matrix a with m >= n
+// from its singular value decomposition
+//  A = W * Σ * Vᵀ
+// as U = W * Vᵀ and P = V * Σ * Vᵀ. Factorize panics with ErrShape if m < n;
+// the decomposition A = P * U of a wide matrix can be obtained from the
+// factorization of Aᵀ.
+//
+// Factorize returns whether the decomposition succeeded. If the decomposition
+// failed, methods that require a successful factorization will panic.
+func (p *Polar) Factorize(a Matrix) (ok bool) {
+	m, n := a.Dims()
+	if m < n {
+		panic(ErrShape)
+	}
+	p.Reset()
+
+	var svd SVD
+	if !svd.Factorize(a, SVDThin) {
+		return false
+	}
+	values := svd.Values(nil)
+	var w, v Dense
+	svd.UTo(&w)
+	svd.VTo(&v)
+
+	u := NewDense(m, n, nil)
+	u.Mul(&w, v.T())
+
+	// P = V * Σ * Vᵀ is formed from the scaled columns of V.
+	var vs Dense
+	vs.CloneFrom(&v)
+	for j, s := range values {
+		for i := 0; i < n; i++ {
+			vs.set(i, j, vs.at(i, j)*s)
+		}
+	}
+	var vsvt Dense
+	vsvt.Mul(&vs, v.T())
+	sym := NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			sym.SetSym(i, j, (vsvt.at(i, j)+vsvt.at(j, i))/2)
+		}
+	}
+
+	p.u = u
+	p.p = sym
+	return true
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (p *Polar) isValid() bool {
+	return p.u != nil && !p.u.IsEmpty()
+}
+
+// Reset resets the factorization so that it can be reused as the receiver of a
+// dimensionally restricted operation.
+func (p *Polar) Reset() {
+	p.u = nil
+	p.p = nil
+}
+
+// UTo extracts the m×n matrix U with orthonormal columns from the polar
+// decomposition.
+//
+// If dst is empty, UTo will resize dst to be m×n. When dst is non-empty, UTo
+// will panic if dst is not m×n. UTo will also panic if the receiver does not
+// contain a successful factorization.
+func (p *Polar) UTo(dst *Dense) {
+	if !p.isValid() {
+		panic(badPolar)
+	}
+	m, n := p.u.Dims()
+	if dst.IsEmpty() {
+		dst.ReuseAs(m, n)
+	} else {
+		r, c := dst.Dims()
+		if r != m || c != n {
+			panic(ErrShape)
+		}
+	}
+	dst.Copy(p.u)
+}
+
+// PTo extracts the n×n symmetric positive semidefinite matrix P from the polar
+// decomposition.
+//
+// If dst is empty, PTo will resize dst to be n×n. When dst is non-empty, PTo
+// will panic if dst is not n×n. PTo will also panic if the receiver does not
+// contain a successful factorization.
+func (p *Polar) PTo(dst *SymDense) {
+	if !p.isValid() {
+		panic(badPolar)
+	}
+	n := p.p.Symmetric()
+	if dst.IsEmpty() {
+		dst.ReuseAsSym(n)
+	} else if dst.Symmetric() != n {
+		panic(ErrShape)
+	}
+	dst.CopySym(p.p)
+}
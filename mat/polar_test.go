@@ -0,0 +1,74 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestPolar(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n int
+	}{
+		{1, 1},
+		{3, 3},
+		{5, 3},
+		{10, 10},
+		{20, 7},
+	} {
+		m, n := test.m, test.n
+		a := NewDense(m, n, nil)
+		for i := 0; i < m; i++ {
+			for j := 0; j < n; j++ {
+				a.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		var polar Polar
+		if !polar.Factorize(a) {
+			t.Fatalf("m=%d,n=%d: factorization failed", m, n)
+		}
+		var u Dense
+		var p SymDense
+		polar.UTo(&u)
+		polar.PTo(&p)
+
+		var utu Dense
+		utu.Mul(u.T(), &u)
+		eye := NewDiagDense(n, nil)
+		for i := 0; i < n; i++ {
+			eye.SetDiag(i, 1)
+		}
+		if !EqualApprox(&utu, eye, 1e-12) {
+			t.Errorf("m=%d,n=%d: U does not have orthonormal columns", m, n)
+		}
+		var chol Cholesky
+		if !chol.Factorize(&p) {
+			t.Errorf("m=%d,n=%d: P is not positive definite", m, n)
+		}
+		var up Dense
+		up.Mul(&u, &p)
+		if !EqualApprox(&up, a, 1e-12) {
+			t.Errorf("m=%d,n=%d: U*P != A", m, n)
+		}
+		// P² = Aᵀ*A.
+		var pp, ata Dense
+		pp.Mul(&p, &p)
+		ata.Mul(a.T(), a)
+		if !EqualApprox(&pp, &ata, 1e-11) {
+			t.Errorf("m=%d,n=%d: P*P != Aᵀ*A", m, n)
+		}
+	}
+
+	if ok, _ := panics(func() {
+		var polar Polar
+		polar.Factorize(NewDense(2, 3, nil))
+	}); !ok {
+		t.Errorf("expected panic for wide matrix")
+	}
+}
@@ -59,6 +59,12 @@ var (
 
 	// poolInts is the []int equivalent of pool.
 	poolInts [63]sync.Pool
+
+	// floatsHeaders and intsHeaders hold the unused slice headers
+	// that are the elements of poolFloats and poolInts, so that
+	// putFloats and putInts do not allocate.
+	floatsHeaders = sync.Pool{New: func() interface{} { return new([]float64) }}
+	intsHeaders   = sync.Pool{New: func() interface{} { return new([]int) }}
 )
 
 func init() {
@@ -202,8 +208,10 @@ func putWorkspaceVec(v *VecDense) {
 // getFloats returns a []float64 of length l and a cap that is
 // less than 2*l. If clear is true, the slice visible is zeroed.
 func getFloats(l int, clear bool) []float64 {
-	w := *poolFloats[bits(uint64(l))].Get().(*[]float64)
-	w = w[:l]
+	p := poolFloats[bits(uint64(l))].Get().(*[]float64)
+	w := (*p)[:l]
+	*p = nil
+	floatsHeaders.Put(p)
 	if clear {
 		zero(w)
 	}
@@ -214,14 +222,18 @@ func getFloats(l int, clear bool) []float64 {
 // workspace pool. putFloats must not be called with a slice
 // where references to the underlying data have been kept.
 func putFloats(w []float64) {
-	poolFloats[bits(uint64(cap(w)))].Put(&w)
+	p := floatsHeaders.Get().(*[]float64)
+	*p = w
+	poolFloats[bits(uint64(cap(w)))].Put(p)
 }
 
 // getInts returns a []ints of length l and a cap that is
 // less than 2*l. If clear is true, the slice visible is zeroed.
 func getInts(l int, clear bool) []int {
-	w := *poolInts[bits(uint64(l))].Get().(*[]int)
-	w = w[:l]
+	p := poolInts[bits(uint64(l))].Get().(*[]int)
+	w := (*p)[:l]
+	*p = nil
+	intsHeaders.Put(p)
 	if clear {
 		for i := range w {
 			w[i] = 0
@@ -234,5 +246,7 @@ func getInts(l int, clear bool) []int {
 // workspace pool. putInts must not be called with a slice
 // where references to the underlying data have been kept.
 func putInts(w []int) {
-	poolInts[bits(uint64(cap(w)))].Put(&w)
+	p := intsHeaders.Get().(*[]int)
+	*p = w
+	poolInts[bits(uint64(cap(w)))].Put(p)
 }
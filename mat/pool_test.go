@@ -82,3 +82,93 @@ func denseMulWorkspaceBench(b *testing.B, size int, rho float64) {
 		a.Mul(a, d)
 	}
 }
+
+func TestFactorizeReuse(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	var (
+		chol Cholesky
+		lu   LU
+		qr   QR
+		pqr  PivotedQR
+	)
+	// Factorizations reuse the memory held by the receiver, so the
+	// results must not depend on what was factorized before.
+	for _, n := range []int{5, 3, 8, 8, 1, 6} {
+		a := randNormal(n, n, rnd)
+		var s SymDense
+		s.SymOuterK(1, a)
+		for i := 0; i < n; i++ {
+			s.SetSym(i, i, s.At(i, i)+1)
+		}
+
+		if !chol.Factorize(&s) {
+			t.Fatalf("n=%d: unexpected Cholesky failure", n)
+		}
+		var u TriDense
+		chol.UTo(&u)
+		var got Dense
+		got.Mul(u.T(), &u)
+		if !EqualApprox(&got, &s, 1e-10) {
+			t.Errorf("n=%d: Cholesky does not reconstruct A after reuse", n)
+		}
+
+		lu.Factorize(a)
+		var want Dense
+		want.Solve(a, eye(n))
+		var inv Dense
+		err := lu.SolveTo(&inv, false, eye(n))
+		if err != nil || !EqualApprox(&inv, &want, 1e-8) {
+			t.Errorf("n=%d: LU solve incorrect after reuse", n)
+		}
+
+		qr.Factorize(a)
+		var q, r Dense
+		qr.QTo(&q)
+		qr.RTo(&r)
+		got.Reset()
+		got.Mul(&q, &r)
+		if !EqualApprox(&got, a, 1e-10) {
+			t.Errorf("n=%d: QR does not reconstruct A after reuse", n)
+		}
+
+		pqr.Factorize(a)
+		if pqr.Rank(0) != n {
+			t.Errorf("n=%d: unexpected pivoted QR rank after reuse: %d", n, pqr.Rank(0))
+		}
+		q.Reset()
+		r.Reset()
+		pqr.QTo(&q)
+		pqr.RTo(&r)
+		got.Reset()
+		got.Mul(&q, &r)
+		p := NewPermutation(n, pqr.Pivot(nil))
+		want.Reset()
+		want.Mul(a, p.T())
+		if !EqualApprox(&got, &want, 1e-10) {
+			t.Errorf("n=%d: pivoted QR does not reconstruct A*P after reuse", n)
+		}
+	}
+}
+
+func benchmarkFactorizeSolveReuse(b *testing.B, n int) {
+	rnd := rand.New(rand.NewSource(1))
+	a := randNormal(n, n, rnd)
+	var s SymDense
+	s.SymOuterK(1, a)
+	for i := 0; i < n; i++ {
+		s.SetSym(i, i, s.At(i, i)+1)
+	}
+	x := NewVecDense(n, nil)
+	y := NewVecDense(n, nil)
+	var chol Cholesky
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		chol.Factorize(&s)
+		_ = chol.SolveVecTo(y, x)
+	}
+}
+
+func BenchmarkCholeskyFactorizeSolveReuse4(b *testing.B)  { benchmarkFactorizeSolveReuse(b, 4) }
+func BenchmarkCholeskyFactorizeSolveReuse16(b *testing.B) { benchmarkFactorizeSolveReuse(b, 16) }
@@ -5,6 +5,9 @@
 package mat
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
 	"math"
 
 	"gonum.org/v1/gonum/blas"
@@ -20,6 +23,11 @@ type QR struct {
 	qr   *Dense
 	tau  []float64
 	cond float64
+
+	// q holds the explicit orthonormal factor once the factorization
+	// has been updated. R is then held in the upper triangle of qr and
+	// tau is not used.
+	q *Dense
 }
 
 func (qr *QR) updateCond(norm lapack.MatrixNorm) {
@@ -59,9 +67,12 @@ func (qr *QR) factorize(a Matrix, norm lapack.MatrixNorm) {
 	if qr.qr == nil {
 		qr.qr = &Dense{}
 	}
-	qr.qr.CloneFrom(a)
+	qr.qr.Reset()
+	qr.qr.reuseAsNonZeroed(m, n)
+	qr.qr.Copy(a)
+	qr.q = nil
 	work := []float64{0}
-	qr.tau = make([]float64, k)
+	qr.tau = use(qr.tau, k)
 	lapack64.Geqrf(qr.qr.mat, qr.tau, work, -1)
 	work = getFloats(int(work[0]), false)
 	lapack64.Geqrf(qr.qr.mat, qr.tau, work, len(work))
@@ -83,6 +94,46 @@ func (qr *QR) Cond() float64 {
 	return qr.cond
 }
 
+// CondEst returns an estimate of the condition number of the factorized
+// matrix in the 1-norm if norm is 1, or in the ∞-norm if norm is math.Inf(1).
+// As for Cond, the condition number of the n×n triangular factor R is
+// returned, which is equal to the condition number of A in the 2-norm and
+// approximates it in other norms. The norm of R is computed exactly and the
+// norm of R⁻¹ is estimated as described for LU.CondEst.
+//
+// CondEst will panic if norm is not 1 or math.Inf(1), or if the receiver does
+// not contain a factorization.
+func (qr *QR) CondEst(norm float64) float64 {
+	if !qr.isValid() {
+		panic(badQR)
+	}
+	n := qr.qr.mat.Cols
+	for i := 0; i < n; i++ {
+		if qr.qr.at(i, i) == 0 {
+			checkCondNorm(norm)
+			return math.Inf(1)
+		}
+	}
+	r := qr.qr.asTriDense(n, blas.NonUnit, blas.Upper).mat
+	op := func(f func(blas.Transpose, blas64.Triangular, blas64.Vector)) func([]float64, bool) {
+		return func(x []float64, trans bool) {
+			t := blas.NoTrans
+			if trans {
+				t = blas.Trans
+			}
+			f(t, r, blas64.Vector{N: n, Inc: 1, Data: x})
+		}
+	}
+	lnorm := lapack.MaxColumnSum
+	if norm != 1 {
+		lnorm = lapack.MaxRowSum
+	}
+	work := getFloats(n, false)
+	rnorm := lapack64.Lantr(lnorm, r, work)
+	putFloats(work)
+	return condEst(n, norm, rnorm, op(blas64.Trmv), op(blas64.Trsv))
+}
+
 // TODO(btracey): Add in the "Reduced" forms for extracting the n×n orthogonal
 // and upper triangular matrices.
 
@@ -146,16 +197,37 @@ func (qr *QR) QTo(dst *Dense) {
 		dst.Zero()
 	}
 
+	if qr.q != nil {
+		dst.Copy(qr.q)
+		return
+	}
+
 	// Set Q = I.
 	for i := 0; i < r*r; i += r + 1 {
 		dst.mat.Data[i] = 1
 	}
 
 	// Construct Q from the elementary reflectors.
+	qr.applyQ(blas.NoTrans, dst)
+}
+
+// applyQ overwrites b with Q*b or Qᵀ*b.
+func (qr *QR) applyQ(trans blas.Transpose, b *Dense) {
+	if qr.q != nil {
+		var q Matrix = qr.q
+		if trans == blas.Trans {
+			q = qr.q.T()
+		}
+		tmp := getWorkspace(b.mat.Rows, b.mat.Cols, false)
+		tmp.Mul(q, b)
+		b.Copy(tmp)
+		putWorkspace(tmp)
+		return
+	}
 	work := []float64{0}
-	lapack64.Ormqr(blas.Left, blas.NoTrans, qr.qr.mat, qr.tau, dst.mat, work, -1)
+	lapack64.Ormqr(blas.Left, trans, qr.qr.mat, qr.tau, b.mat, work, -1)
 	work = getFloats(int(work[0]), false)
-	lapack64.Ormqr(blas.Left, blas.NoTrans, qr.qr.mat, qr.tau, dst.mat, work, len(work))
+	lapack64.Ormqr(blas.Left, trans, qr.qr.mat, qr.tau, b.mat, work, len(work))
 	putFloats(work)
 }
 
@@ -205,17 +277,9 @@ func (qr *QR) SolveTo(dst *Dense, trans bool, b Matrix) error {
 		for i := c; i < r; i++ {
 			zero(w.mat.Data[i*w.mat.Stride : i*w.mat.Stride+bc])
 		}
-		work := []float64{0}
-		lapack64.Ormqr(blas.Left, blas.NoTrans, qr.qr.mat, qr.tau, w.mat, work, -1)
-		work = getFloats(int(work[0]), false)
-		lapack64.Ormqr(blas.Left, blas.NoTrans, qr.qr.mat, qr.tau, w.mat, work, len(work))
-		putFloats(work)
+		qr.applyQ(blas.NoTrans, w)
 	} else {
-		work := []float64{0}
-		lapack64.Ormqr(blas.Left, blas.Trans, qr.qr.mat, qr.tau, w.mat, work, -1)
-		work = getFloats(int(work[0]), false)
-		lapack64.Ormqr(blas.Left, blas.Trans, qr.qr.mat, qr.tau, w.mat, work, len(work))
-		putFloats(work)
+		qr.applyQ(blas.Trans, w)
 
 		ok := lapack64.Trtrs(blas.NoTrans, t, w.mat)
 		if !ok {
@@ -264,3 +328,320 @@ func (qr *QR) SolveVecTo(dst *VecDense, trans bool, b Vector) error {
 	return qr.SolveTo(dst.asDense(), trans, bm)
 
 }
+
+// InsertRow updates the factorization of the m×n matrix A to the factorization
+// of the (m+1)×n matrix obtained by inserting the row x into A before row i, so
+// that x becomes row i of the updated matrix. i must be in [0, m] and the
+// length of x must be n, otherwise InsertRow will panic.
+//
+// InsertRow and the other updating methods use Givens rotations and take
+// O(m² + m*n) time instead of the O(m*n²) time of a new factorization. The
+// updated factorization holds Q explicitly, so subsequent uses of the
+// receiver cost O(m²) memory.
+//
+// InsertRow will panic if the receiver does not contain a factorization.
+func (qr *QR) InsertRow(i int, x Vector) {
+	if !qr.isValid() {
+		panic(badQR)
+	}
+	m, n := qr.qr.Dims()
+	if i < 0 || m < i {
+		panic(ErrRowAccess)
+	}
+	if x.Len() != n {
+		panic(ErrShape)
+	}
+	qr.formQ()
+
+	// Append x as the last row, so that
+	//  [A; xᵀ] = [Q 0; 0 1] * [R; xᵀ],
+	// and zero it against the diagonal of R.
+	r := NewDense(m+1, n, nil)
+	r.slice(0, m, 0, n).Copy(qr.qr)
+	for j := 0; j < n; j++ {
+		r.set(m, j, x.AtVec(j))
+	}
+	q := NewDense(m+1, m+1, nil)
+	q.slice(0, m, 0, m).Copy(qr.q)
+	q.set(m, m, 1)
+	for j := 0; j < n; j++ {
+		c, s := rotateRows(r, j, m, j)
+		rotateCols(q, j, m, c, s)
+	}
+
+	// Move the last row of Q into position i.
+	last := make([]float64, m+1)
+	copy(last, q.RawRowView(m))
+	for k := m; k > i; k-- {
+		copy(q.RawRowView(k), q.RawRowView(k-1))
+	}
+	copy(q.RawRowView(i), last)
+
+	qr.qr = r
+	qr.q = q
+	qr.updateCond(CondNorm)
+}
+
+// DeleteRow updates the factorization of the m×n matrix A to the factorization
+// of the (m-1)×n matrix obtained by removing row i from A. DeleteRow will panic
+// if i is not in [0, m), if m-1 < n, or if the receiver does not contain a
+// factorization.
+func (qr *QR) DeleteRow(i int) {
+	if !qr.isValid() {
+		panic(badQR)
+	}
+	m, n := qr.qr.Dims()
+	if i < 0 || m <= i {
+		panic(ErrRowAccess)
+	}
+	if m-1 < n {
+		panic(ErrShape)
+	}
+	qr.formQ()
+
+	// Rotate the columns of Q so that its row i becomes ±e_0ᵀ. The first
+	// column of Q is then ±e_i, and the rotations applied to R leave it
+	// upper Hessenberg, so that removing row i of Q and A amounts to
+	// removing the first column of Q and the first row of R.
+	q := qr.q
+	r := NewDense(m, n, nil)
+	r.Copy(qr.qr)
+	for k := m - 1; k > 0; k-- {
+		c, s, _, _ := blas64.Rotg(q.at(i, k-1), q.at(i, k))
+		rotateCols(q, k-1, k, c, s)
+		if k-1 < n {
+			blas64.Rot(
+				blas64.Vector{N: n - k + 1, Data: r.mat.Data[(k-1)*r.mat.Stride+k-1:], Inc: 1},
+				blas64.Vector{N: n - k + 1, Data: r.mat.Data[k*r.mat.Stride+k-1:], Inc: 1},
+				c, s)
+		}
+	}
+
+	newQ := NewDense(m-1, m-1, nil)
+	if i > 0 {
+		newQ.slice(0, i, 0, m-1).Copy(q.slice(0, i, 1, m))
+	}
+	if i < m-1 {
+		newQ.slice(i, m-1, 0, m-1).Copy(q.slice(i+1, m, 1, m))
+	}
+	newR := NewDense(m-1, n, nil)
+	newR.Copy(r.slice(1, m, 0, n))
+
+	qr.qr = newR
+	qr.q = newQ
+	qr.updateCond(CondNorm)
+}
+
+// InsertCol updates the factorization of the m×n matrix A to the factorization
+// of the m×(n+1) matrix obtained by inserting the column x into A before column
+// j, so that x becomes column j of the updated matrix. InsertCol will panic if
+// j is not in [0, n], if the length of x is not m, if m < n+1, or if the
+// receiver does not contain a factorization.
+func (qr *QR) InsertCol(j int, x Vector) {
+	if !qr.isValid() {
+		panic(badQR)
+	}
+	m, n := qr.qr.Dims()
+	if j < 0 || n < j {
+		panic(ErrColAccess)
+	}
+	if x.Len() != m {
+		panic(ErrShape)
+	}
+	if m < n+1 {
+		panic(ErrShape)
+	}
+	qr.formQ()
+
+	// The new column of R is Qᵀ*x. Zero it below the diagonal from the
+	// bottom up.
+	r := NewDense(m, n+1, nil)
+	if j > 0 {
+		r.slice(0, m, 0, j).Copy(qr.qr.slice(0, m, 0, j))
+	}
+	if j < n {
+		r.slice(0, m, j+1, n+1).Copy(qr.qr.slice(0, m, j, n))
+	}
+	var w VecDense
+	w.MulVec(qr.q.T(), x)
+	r.ColView(j).(*VecDense).CopyVec(&w)
+	for k := m - 1; k > j; k-- {
+		c, s := rotateRows(r, k-1, k, j)
+		rotateCols(qr.q, k-1, k, c, s)
+	}
+
+	qr.qr = r
+	qr.updateCond(CondNorm)
+}
+
+// DeleteCol updates the factorization of the m×n matrix A to the factorization
+// of the m×(n-1) matrix obtained by removing column j from A. DeleteCol will
+// panic if j is not in [0, n), if n is 1, or if the receiver does not contain a
+// factorization.
+func (qr *QR) DeleteCol(j int) {
+	if !qr.isValid() {
+		panic(badQR)
+	}
+	m, n := qr.qr.Dims()
+	if j < 0 || n <= j {
+		panic(ErrColAccess)
+	}
+	if n == 1 {
+		panic(ErrShape)
+	}
+	qr.formQ()
+
+	// Removing column j leaves R upper Hessenberg from column j onwards.
+	r := NewDense(m, n-1, nil)
+	if j > 0 {
+		r.slice(0, m, 0, j).Copy(qr.qr.slice(0, m, 0, j))
+	}
+	if j < n-1 {
+		r.slice(0, m, j, n-1).Copy(qr.qr.slice(0, m, j+1, n))
+	}
+	for k := j; k < n-1; k++ {
+		c, s := rotateRows(r, k, k+1, k)
+		rotateCols(qr.q, k, k+1, c, s)
+	}
+
+	qr.qr = r
+	qr.updateCond(CondNorm)
+}
+
+// formQ forms the explicit orthonormal factor of the factorization, if it has
+// not already been formed, and zeros the reflectors below the diagonal of R.
+func (qr *QR) formQ() {
+	if qr.q != nil {
+		return
+	}
+	m, n := qr.qr.Dims()
+	q := NewDense(m, m, nil)
+	qr.QTo(q)
+	for i := 1; i < m; i++ {
+		zero(qr.qr.mat.Data[i*qr.qr.mat.Stride : i*qr.qr.mat.Stride+min(i, n)])
+	}
+	qr.q = q
+	qr.tau = nil
+}
+
+// MarshalBinary encodes the receiver into a binary form and returns the result.
+// MarshalBinary returns an error if the receiver does not contain a
+// factorization.
+//
+// QR is little-endian encoded as follows, where the factorization of an m×n
+// matrix is held either as Householder reflectors, or as the explicit m×m
+// orthonormal factor Q once it has been updated:
+//  Dense binary form of the m×n factor R and reflectors, see Dense.MarshalBinary
+//  condition number                (float64)
+//  'H' or 'Q'                      (byte)
+//  for 'H', n Householder scalars  (float64)
+//  for 'Q', Dense binary form of Q
+func (qr *QR) MarshalBinary() ([]byte, error) {
+	if !qr.isValid() {
+		return nil, errors.New(badQR)
+	}
+	buf, err := qr.qr.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	var tail [9]byte
+	binary.LittleEndian.PutUint64(tail[:sizeFloat64], math.Float64bits(qr.cond))
+	if qr.q != nil {
+		tail[sizeFloat64] = 'Q'
+		q, err := qr.q.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		return append(append(buf, tail[:]...), q...), nil
+	}
+	tail[sizeFloat64] = 'H'
+	buf = append(buf, tail[:]...)
+	for _, v := range qr.tau {
+		binary.LittleEndian.PutUint64(tail[:sizeFloat64], math.Float64bits(v))
+		buf = append(buf, tail[:sizeFloat64]...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes the binary form of a QR factorization into the
+// receiver. It panics if the receiver contains a factorization.
+//
+// See MarshalBinary for the on-disk layout. In addition to the checks of
+// Dense.UnmarshalBinary, UnmarshalBinary checks that the dimensions of the
+// factors are consistent. UnmarshalBinary does not limit the size of the
+// unmarshaled factorization, and so it should not be used on untrusted data.
+func (qr *QR) UnmarshalBinary(data []byte) error {
+	if qr.isValid() {
+		panic("mat: unmarshal into non-empty factorization")
+	}
+	var f Dense
+	p, err := f.UnmarshalBinaryFrom(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	m, n := f.Dims()
+	if m < n {
+		return errBadSize
+	}
+	if len(data)-p < sizeFloat64+1 {
+		return errBadBuffer
+	}
+	cond := math.Float64frombits(binary.LittleEndian.Uint64(data[p : p+sizeFloat64]))
+	p += sizeFloat64
+	kind := data[p]
+	p++
+	switch kind {
+	case 'H':
+		if len(data)-p != n*sizeFloat64 {
+			return errBadBuffer
+		}
+		tau := make([]float64, n)
+		for i := range tau {
+			tau[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[p : p+sizeFloat64]))
+			p += sizeFloat64
+		}
+		qr.q = nil
+		qr.tau = tau
+	case 'Q':
+		var q Dense
+		err := q.UnmarshalBinary(data[p:])
+		if err != nil {
+			return err
+		}
+		if r, c := q.Dims(); r != m || c != m {
+			return errBadSize
+		}
+		qr.q = &q
+		qr.tau = nil
+	default:
+		return errWrongType
+	}
+	qr.qr = &f
+	qr.cond = cond
+	return nil
+}
+
+// rotateRows applies a Givens rotation to rows i and k of a, from column j
+// onwards, that zeros the element a[k,j] against a[i,j]. The parameters of
+// the rotation are returned.
+func rotateRows(a *Dense, i, k, j int) (c, s float64) {
+	n := a.mat.Cols
+	c, s, _, _ = blas64.Rotg(a.at(i, j), a.at(k, j))
+	blas64.Rot(
+		blas64.Vector{N: n - j, Data: a.mat.Data[i*a.mat.Stride+j:], Inc: 1},
+		blas64.Vector{N: n - j, Data: a.mat.Data[k*a.mat.Stride+j:], Inc: 1},
+		c, s)
+	a.set(k, j, 0)
+	return c, s
+}
+
+// rotateCols applies the Givens rotation with parameters c and s to columns i
+// and k of a from the right, such that if rows i and k of R are rotated by
+// rotateRows, the product a*R is unchanged.
+func rotateCols(a *Dense, i, k int, c, s float64) {
+	m := a.mat.Rows
+	blas64.Rot(
+		blas64.Vector{N: m, Data: a.mat.Data[i:], Inc: a.mat.Stride},
+		blas64.Vector{N: m, Data: a.mat.Data[k:], Inc: a.mat.Stride},
+		c, s)
+}
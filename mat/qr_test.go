@@ -216,3 +216,140 @@ func TestQRSolveCondTo(t *testing.T) {
 		}
 	}
 }
+
+func TestQRUpdate(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n int
+	}{
+		{1, 1},
+		{3, 2},
+		{6, 6},
+		{10, 4},
+		{20, 12},
+	} {
+		m, n := test.m, test.n
+		a := randNormal(m, n, rnd)
+		var qr QR
+		qr.Factorize(a)
+
+		for step := 0; step < 40; step++ {
+			m, n := a.Dims()
+			var op string
+			switch rnd.Intn(4) {
+			case 0:
+				op = "InsertRow"
+				i := rnd.Intn(m + 1)
+				x := randNormal(n, 1, rnd)
+				qr.InsertRow(i, x.ColView(0))
+				b := NewDense(m+1, n, nil)
+				for k := 0; k < m+1; k++ {
+					for j := 0; j < n; j++ {
+						switch {
+						case k < i:
+							b.set(k, j, a.At(k, j))
+						case k == i:
+							b.set(k, j, x.At(j, 0))
+						default:
+							b.set(k, j, a.At(k-1, j))
+						}
+					}
+				}
+				a = b
+			case 1:
+				if m == n {
+					continue
+				}
+				op = "DeleteRow"
+				i := rnd.Intn(m)
+				qr.DeleteRow(i)
+				b := NewDense(m-1, n, nil)
+				for k := 0; k < m-1; k++ {
+					src := k
+					if k >= i {
+						src++
+					}
+					for j := 0; j < n; j++ {
+						b.set(k, j, a.At(src, j))
+					}
+				}
+				a = b
+			case 2:
+				if m == n {
+					continue
+				}
+				op = "InsertCol"
+				j := rnd.Intn(n + 1)
+				x := randNormal(m, 1, rnd)
+				qr.InsertCol(j, x.ColView(0))
+				b := NewDense(m, n+1, nil)
+				for k := 0; k < m; k++ {
+					for l := 0; l < n+1; l++ {
+						switch {
+						case l < j:
+							b.set(k, l, a.At(k, l))
+						case l == j:
+							b.set(k, l, x.At(k, 0))
+						default:
+							b.set(k, l, a.At(k, l-1))
+						}
+					}
+				}
+				a = b
+			case 3:
+				if n == 1 {
+					continue
+				}
+				op = "DeleteCol"
+				j := rnd.Intn(n)
+				qr.DeleteCol(j)
+				b := NewDense(m, n-1, nil)
+				for k := 0; k < m; k++ {
+					for l := 0; l < n-1; l++ {
+						src := l
+						if l >= j {
+							src++
+						}
+						b.set(k, l, a.At(k, src))
+					}
+				}
+				a = b
+			}
+
+			m, n = a.Dims()
+			var q, r Dense
+			qr.QTo(&q)
+			qr.RTo(&r)
+			if !isOrthonormal(&q, 1e-12) {
+				t.Fatalf("%d×%d after %s: Q is not orthonormal", m, n, op)
+			}
+			for i := 1; i < m; i++ {
+				for j := 0; j < min(i, n); j++ {
+					if r.At(i, j) != 0 {
+						t.Fatalf("%d×%d after %s: R is not upper triangular", m, n, op)
+					}
+				}
+			}
+			var got Dense
+			got.Mul(&q, &r)
+			if !EqualApprox(&got, a, 1e-12) {
+				t.Fatalf("%d×%d after %s: Q*R does not equal updated matrix", m, n, op)
+			}
+
+			// Least-squares solutions agree with a new factorization.
+			b := randNormal(m, 2, rnd)
+			var want QR
+			want.Factorize(a)
+			var x, xWant Dense
+			err := qr.SolveTo(&x, false, b)
+			errWant := want.SolveTo(&xWant, false, b)
+			if (err == nil) != (errWant == nil) {
+				t.Fatalf("%d×%d after %s: mismatched solve errors %v and %v", m, n, op, err, errWant)
+			}
+			if err == nil && !EqualApprox(&x, &xWant, 1e-8) {
+				t.Fatalf("%d×%d after %s: solution does not match new factorization", m, n, op)
+			}
+		}
+	}
+}
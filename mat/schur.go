@@ -0,0 +1,237 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/lapack"
+	"gonum.org/v1/gonum/lapack/lapack64"
+)
+
+const badSchur = "mat: invalid Schur factorization"
+
+// Schur is a type for creating and using the real Schur factorization of a
+// square matrix.
+//
+// The real Schur factorization of an n×n matrix A is
+//  A = Z * T * Zᵀ
+// where Z is an orthogonal matrix of Schur vectors and T is an upper
+// quasi-triangular matrix in Schur canonical form. T is block upper triangular
+// with 1×1 and 2×2 diagonal blocks. The 1×1 blocks hold the real eigenvalues
+// of A, and each 2×2 block has equal diagonal elements and off-diagonal
+// elements of opposite sign, and holds a complex conjugate pair of eigenvalues.
+type Schur struct {
+	t *Dense
+	z *Dense
+
+	vectorsComputed bool
+}
+
+// Factorize computes the real Schur factorization of the square matrix a, and
+// optionally the Schur vectors. Factorize panics if a is not square.
+//
+// Factorize returns whether the decomposition succeeded. If the decomposition
+// failed, methods that require a successful factorization will panic.
+func (s *Schur) Factorize(a Matrix, vectors bool) (ok bool) {
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	s.Reset()
+	n := r
+	t := NewDense(n, n, nil)
+	t.Copy(a)
+
+	// Reduce A to upper Hessenberg form.
+	tau := make([]float64, max(0, n-1))
+	work := []float64{0}
+	lapack64.Gehrd(t.mat, 0, n-1, tau, work, -1)
+	work = getFloats(int(work[0]), false)
+	lapack64.Gehrd(t.mat, 0, n-1, tau, work, len(work))
+	putFloats(work)
+
+	compz := lapack.SchurNone
+	var z Dense
+	if vectors {
+		compz = lapack.SchurOrig
+		z.CloneFrom(t)
+		work = []float64{0}
+		lapack64.Orghr(z.mat, 0, n-1, tau, work, -1)
+		work = getFloats(int(work[0]), false)
+		lapack64.Orghr(z.mat, 0, n-1, tau, work, len(work))
+		putFloats(work)
+	}
+	for i := 2; i < n; i++ {
+		for j := 0; j < i-1; j++ {
+			t.set(i, j, 0)
+		}
+	}
+
+	wr := getFloats(n, false)
+	defer putFloats(wr)
+	wi := getFloats(n, false)
+	defer putFloats(wi)
+	work = []float64{0}
+	lapack64.Hseqr(lapack.EigenvaluesAndSchur, compz, t.mat, 0, n-1, wr, wi, z.mat, work, -1)
+	work = getFloats(int(work[0]), false)
+	unconverged := lapack64.Hseqr(lapack.EigenvaluesAndSchur, compz, t.mat, 0, n-1, wr, wi, z.mat, work, len(work))
+	putFloats(work)
+	if unconverged != 0 {
+		return false
+	}
+	s.t = t
+	if vectors {
+		s.z = &z
+		s.vectorsComputed = true
+	}
+	return true
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (s *Schur) isValid() bool {
+	return s.t != nil && !s.t.IsEmpty()
+}
+
+// Reset resets the factorization so that it can be reused as the receiver of a
+// dimensionally restricted operation.
+func (s *Schur) Reset() {
+	s.t = nil
+	s.z = nil
+	s.vectorsComputed = false
+}
+
+// Values extracts the eigenvalues of the factorized matrix in the order in
+// which they appear on the diagonal of T. Complex conjugate pairs of
+// eigenvalues appear consecutively with the eigenvalue having the positive
+// imaginary part first. If dst is non-nil, the values are stored in-place into
+// dst. In this case dst must have length n, otherwise Values will panic. If dst
+// is nil, then a new slice will be allocated of the proper length and filled
+// with the eigenvalues.
+//
+// Values panics if the receiver does not contain a successful factorization.
+func (s *Schur) Values(dst []complex128) []complex128 {
+	if !s.isValid() {
+		panic(badSchur)
+	}
+	n := s.t.mat.Rows
+	if dst == nil {
+		dst = make([]complex128, n)
+	}
+	if len(dst) != n {
+		panic(ErrSliceLengthMismatch)
+	}
+	for k := 0; k < n; {
+		if k == n-1 || s.t.at(k+1, k) == 0 {
+			dst[k] = complex(s.t.at(k, k), 0)
+			k++
+			continue
+		}
+		re := s.t.at(k, k)
+		im := math.Sqrt(math.Abs(s.t.at(k, k+1))) * math.Sqrt(math.Abs(s.t.at(k+1, k)))
+		dst[k] = complex(re, im)
+		dst[k+1] = complex(re, -im)
+		k += 2
+	}
+	return dst
+}
+
+// TTo extracts the quasi-triangular Schur form T of the factorization.
+//
+// If dst is empty, TTo will resize dst to be n×n. When dst is non-empty, TTo
+// will panic if dst is not n×n. TTo will also panic if the receiver does not
+// contain a successful factorization.
+func (s *Schur) TTo(dst *Dense) {
+	if !s.isValid() {
+		panic(badSchur)
+	}
+	n := s.t.mat.Rows
+	if dst.IsEmpty() {
+		dst.ReuseAs(n, n)
+	} else {
+		r, c := dst.Dims()
+		if r != n || c != n {
+			panic(ErrShape)
+		}
+	}
+	dst.Copy(s.t)
+}
+
+// ZTo extracts the orthogonal matrix of Schur vectors Z of the factorization.
+//
+// If dst is empty, ZTo will resize dst to be n×n. When dst is non-empty, ZTo
+// will panic if dst is not n×n. ZTo will also panic if the Schur vectors were
+// not computed during the factorization, or if the receiver does not contain a
+// successful factorization.
+func (s *Schur) ZTo(dst *Dense) {
+	if !s.isValid() {
+		panic(badSchur)
+	}
+	if !s.vectorsComputed {
+		panic(noVectors)
+	}
+	n := s.z.mat.Rows
+	if dst.IsEmpty() {
+		dst.ReuseAs(n, n)
+	} else {
+		r, c := dst.Dims()
+		if r != n || c != n {
+			panic(ErrShape)
+		}
+	}
+	dst.Copy(s.z)
+}
+
+// Reorder reorders the factorization so that the eigenvalues for which sel
+// returns true appear in the leading diagonal blocks of T, with the Schur
+// vectors updated accordingly. The relative order of the selected and of the
+// unselected eigenvalues is preserved. The columns of Z corresponding to the
+// selected eigenvalues then form an orthonormal basis of their invariant
+// subspace. sel is called for each eigenvalue of a complex conjugate pair, and
+// the pair is selected if sel returns true for either of them.
+//
+// Reorder returns the number of selected eigenvalues, and whether the
+// reordering succeeded. The reordering fails if two adjacent blocks are too
+// close to be swapped, in which case T may have been partially reordered but
+// remains a valid Schur factorization.
+//
+// Reorder panics if the receiver does not contain a successful factorization.
+func (s *Schur) Reorder(sel func(complex128) bool) (m int, ok bool) {
+	if !s.isValid() {
+		panic(badSchur)
+	}
+	n := s.t.mat.Rows
+	compq := lapack.UpdateSchurNone
+	var z Dense
+	if s.vectorsComputed {
+		compq = lapack.UpdateSchur
+		z = *s.z
+	}
+	work := getFloats(n, false)
+	defer putFloats(work)
+	values := make([]complex128, n)
+	for k := 0; k < n; {
+		s.Values(values)
+		size := 1
+		if k < n-1 && s.t.at(k+1, k) != 0 {
+			size = 2
+		}
+		selected := sel(values[k])
+		if size == 2 {
+			selected = selected || sel(values[k+1])
+		}
+		if selected {
+			if k != m {
+				_, _, ok = lapack64.Trexc(compq, s.t.mat, z.mat, k, m, work)
+				if !ok {
+					return m, false
+				}
+			}
+			m += size
+		}
+		k += size
+	}
+	return m, true
+}
@@ -0,0 +1,110 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestSchur(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 5, 10, 30, 100} {
+		a := NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				a.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		var s Schur
+		if !s.Factorize(a, true) {
+			t.Fatalf("n=%d: factorization failed", n)
+		}
+		checkSchur(t, n, a, &s)
+
+		// The eigenvalues agree with those computed by Eigen.
+		var e Eigen
+		e.Factorize(a, EigenNone)
+		want := e.Values(nil)
+		got := s.Values(nil)
+		for _, w := range want {
+			var found bool
+			for _, g := range got {
+				if cmplx.Abs(g-w) < 1e-10 {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Errorf("n=%d: eigenvalue %v not found", n, w)
+			}
+		}
+
+		// Move the eigenvalues in the left half-plane to the top.
+		left := func(v complex128) bool { return real(v) < 0 }
+		var wantM int
+		for _, v := range got {
+			if left(v) {
+				wantM++
+			}
+		}
+		m, ok := s.Reorder(left)
+		if !ok {
+			t.Fatalf("n=%d: reordering failed", n)
+		}
+		if m != wantM {
+			t.Errorf("n=%d: unexpected number of selected eigenvalues: got %d, want %d", n, m, wantM)
+		}
+		checkSchur(t, n, a, &s)
+		for i, v := range s.Values(nil) {
+			if left(v) != (i < m) {
+				t.Errorf("n=%d: eigenvalue %v at position %d not reordered", n, v, i)
+			}
+		}
+
+		var noVec Schur
+		noVec.Factorize(a, false)
+		if ok, _ := panics(func() { noVec.ZTo(&Dense{}) }); !ok {
+			t.Errorf("n=%d: expected panic for Schur vectors not computed", n)
+		}
+		if _, ok := noVec.Reorder(left); !ok {
+			t.Errorf("n=%d: reordering without Schur vectors failed", n)
+		}
+	}
+}
+
+func checkSchur(t *testing.T, n int, a *Dense, s *Schur) {
+	var tm, z Dense
+	s.TTo(&tm)
+	s.ZTo(&z)
+	for i := 0; i < n; i++ {
+		for j := 0; j < i-1; j++ {
+			if tm.At(i, j) != 0 {
+				t.Errorf("n=%d: T is not quasi-triangular", n)
+				return
+			}
+		}
+		if i > 0 && i < n-1 && tm.At(i, i-1) != 0 && tm.At(i+1, i) != 0 {
+			t.Errorf("n=%d: T has overlapping 2×2 blocks", n)
+		}
+	}
+	var ztz Dense
+	ztz.Mul(z.T(), &z)
+	eye := NewDiagDense(n, nil)
+	for i := 0; i < n; i++ {
+		eye.SetDiag(i, 1)
+	}
+	if !EqualApprox(&ztz, eye, 1e-12) {
+		t.Errorf("n=%d: Z is not orthogonal", n)
+	}
+	var got Dense
+	got.Product(&z, &tm, z.T())
+	if !EqualApprox(&got, a, 1e-11) {
+		t.Errorf("n=%d: Z*T*Zᵀ != A", n)
+	}
+}
@@ -203,6 +203,21 @@ func generalFromVector(a blas64.Vector, r, c int) blas64.General {
 	}
 }
 
+func (b *BandDense) checkOverlap(a blas64.General) bool {
+	return checkOverlap(generalFromBand(b.RawBand()), a)
+}
+
+// generalFromBand returns a blas64.General with the backing
+// data and dimensions of a.
+func generalFromBand(a blas64.Band) blas64.General {
+	return blas64.General{
+		Rows:   min(a.Rows, a.Cols+a.KL),
+		Cols:   a.KL + a.KU + 1,
+		Data:   a.Data,
+		Stride: a.Stride,
+	}
+}
+
 func (s *SymBandDense) checkOverlap(a blas64.General) bool {
 	return checkOverlap(generalFromSymmetricBand(s.RawSymBand()), a)
 }
@@ -5,8 +5,9 @@
 package mat
 
 import (
+	"math"
+
 	"gonum.org/v1/gonum/blas"
-	"gonum.org/v1/gonum/blas/blas64"
 	"gonum.org/v1/gonum/lapack/lapack64"
 )
 
@@ -18,6 +19,11 @@ import (
 //  - if m >= n, find X such that ||A*X - B||_2 is minimized,
 //  - if m < n, find the minimum norm solution of A * X = B.
 // The solution matrix, X, is stored in-place into the receiver.
+//
+// Each column of B is a right-hand side, and all the right-hand sides are
+// solved with a single factorization of A. If A is triangular, right-hand
+// sides with many columns are solved concurrently, see SetMaxWorkers. See
+// SolveBatch and SolveTriBatch for solving many independent systems.
 func (m *Dense) Solve(a, b Matrix) error {
 	ar, ac := a.Dims()
 	br, bc := b.Dims()
@@ -31,7 +37,6 @@ func (m *Dense) Solve(a, b Matrix) error {
 	bU, bTrans := untranspose(b)
 	switch rma := aU.(type) {
 	case RawTriangular:
-		side := blas.Left
 		tA := blas.NoTrans
 		if aTrans {
 			tA = blas.Trans
@@ -62,12 +67,17 @@ func (m *Dense) Solve(a, b Matrix) error {
 		}
 
 		rm := rma.RawTriangular()
-		blas64.Trsm(side, tA, 1, rm, m.mat)
+		trsm(tA, rm, m.mat)
 		work := getFloats(3*rm.N, false)
 		iwork := getInts(rm.N, false)
-		cond := lapack64.Trcon(CondNorm, rm, work, iwork)
+		// Trcon returns the reciprocal of the condition number.
+		rcond := lapack64.Trcon(CondNorm, rm, work, iwork)
 		putFloats(work)
 		putInts(iwork)
+		if rcond == 0 {
+			return Condition(math.Inf(1))
+		}
+		cond := 1 / rcond
 		if cond > ConditionTolerance {
 			return Condition(cond)
 		}
@@ -138,3 +148,62 @@ func (v *VecDense) SolveVec(a Matrix, b Vector) error {
 	m := v.asDense()
 	return m.Solve(a, b)
 }
+
+// SolveKronVec solves the system of linear equations
+//  (A ⊗ B) * x = c
+// where A is a p×p matrix, B is a q×q matrix and c is a vector of length p*q,
+// without forming the Kronecker product, storing the result into the receiver.
+// SolveKronVec will panic if a or b is not square, or if the length of c is not
+// p*q.
+//
+// The solution uses the identity
+//  (A ⊗ B) * vec(X) = vec(A * X * Bᵀ)
+// where vec stacks the rows of the p×q matrix X, and computes X = A⁻¹ * C * B⁻ᵀ
+// from the LU factorizations of A and B in O(p³ + q³ + p*q*(p+q)) time instead
+// of the O(p³*q³) time of solving with the full product.
+//
+// The condition number of A ⊗ B is the product of the condition numbers of A
+// and B. If it exceeds ConditionTolerance, a Condition error is returned. See
+// the documentation for Condition for more information.
+func (v *VecDense) SolveKronVec(a, b Matrix, c Vector) error {
+	p, pc := a.Dims()
+	q, qc := b.Dims()
+	if p != pc || q != qc {
+		panic(ErrSquare)
+	}
+	if c.Len() != p*q {
+		panic(ErrShape)
+	}
+
+	var luA, luB LU
+	luA.Factorize(a)
+	luB.Factorize(b)
+	if luA.Det() == 0 || luB.Det() == 0 {
+		return Condition(math.Inf(1))
+	}
+
+	// Solve A * Z = C and then Y * Bᵀ = Z as B * Yᵀ = Zᵀ. Condition errors
+	// are reported below for the product.
+	cm := getWorkspace(p, q, false)
+	defer putWorkspace(cm)
+	for i := 0; i < p; i++ {
+		for j := 0; j < q; j++ {
+			cm.set(i, j, c.AtVec(i*q+j))
+		}
+	}
+	var z, yt Dense
+	_ = luA.SolveTo(&z, false, cm)
+	_ = luB.SolveTo(&yt, false, z.T())
+
+	v.reuseAsNonZeroed(p * q)
+	for i := 0; i < p; i++ {
+		for j := 0; j < q; j++ {
+			v.setVec(i*q+j, yt.at(j, i))
+		}
+	}
+	cond := luA.cond * luB.cond
+	if cond > ConditionTolerance {
+		return Condition(cond)
+	}
+	return nil
+}
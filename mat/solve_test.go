@@ -5,6 +5,7 @@
 package mat
 
 import (
+	"math"
 	"testing"
 
 	"golang.org/x/exp/rand"
@@ -246,6 +247,46 @@ func TestSolve(t *testing.T) {
 	testTwoInput(t, "Solve", &Dense{}, method, denseComparison, legalTypesAll, legalSizeSolve, 1e-7)
 }
 
+func TestSolveTriCondition(t *testing.T) {
+	t.Parallel()
+	// The condition number of a diagonal matrix in any induced norm is the
+	// ratio of the largest and smallest magnitudes of its diagonal, which
+	// the estimate of Trcon computes exactly.
+	for _, test := range []struct {
+		diag []float64
+		want float64
+	}{
+		{diag: []float64{1, 2, 4}, want: 4},
+		{diag: []float64{2, 1, 1e-17}, want: 2e17},
+		{diag: []float64{1, 0, 1}, want: math.Inf(1)},
+	} {
+		for _, kind := range []TriKind{Upper, Lower} {
+			n := len(test.diag)
+			a := NewTriDense(n, kind, nil)
+			for i, v := range test.diag {
+				a.SetTri(i, i, v)
+			}
+			b := NewDense(n, 1, []float64{1, 2, 3})
+			var x Dense
+			err := x.Solve(a, b)
+			if test.want <= ConditionTolerance {
+				if err != nil {
+					t.Errorf("diag=%v upper=%t: unexpected error: %v", test.diag, kind == Upper, err)
+				}
+				continue
+			}
+			cond, ok := err.(Condition)
+			if !ok {
+				t.Errorf("diag=%v upper=%t: expected Condition error, got %v", test.diag, kind == Upper, err)
+				continue
+			}
+			if got := float64(cond); got != test.want && math.Abs(got-test.want) > 1e-12*test.want {
+				t.Errorf("diag=%v upper=%t: unexpected condition number: got %v want %v", test.diag, kind == Upper, got, test.want)
+			}
+		}
+	}
+}
+
 func TestSolveVec(t *testing.T) {
 	t.Parallel()
 	rnd := rand.New(rand.NewSource(1))
@@ -305,3 +346,55 @@ func TestSolveVec(t *testing.T) {
 	}
 	testTwoInput(t, "SolveVec", &VecDense{}, method, denseComparison, legalTypesMatrixVector, legalSizeSolve, 1e-12)
 }
+
+func TestSolveKronVec(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		p, q int
+	}{
+		{1, 1},
+		{1, 4},
+		{3, 1},
+		{3, 5},
+		{8, 6},
+	} {
+		p, q := test.p, test.q
+		a := randNormal(p, p, rnd)
+		b := randNormal(q, q, rnd)
+		c := randNormal(p*q, 1, rnd).ColView(0)
+
+		var x VecDense
+		err := x.SolveKronVec(a, b, c)
+		if err != nil {
+			t.Fatalf("p=%d,q=%d: unexpected error: %v", p, q, err)
+		}
+
+		var kron Dense
+		kron.Kronecker(a, b)
+		var want VecDense
+		err = want.SolveVec(&kron, c)
+		if err != nil {
+			t.Fatalf("p=%d,q=%d: unexpected error solving with Kronecker product: %v", p, q, err)
+		}
+		if !EqualApprox(&x, &want, 1e-10) {
+			t.Errorf("p=%d,q=%d: unexpected solution:\ngot: %v\nwant:%v", p, q, Formatted(x.T()), Formatted(want.T()))
+		}
+
+		// The receiver may alias the right-hand side.
+		y := VecDenseCopyOf(c)
+		err = y.SolveKronVec(a, b, y)
+		if err != nil {
+			t.Fatalf("p=%d,q=%d: unexpected error with aliased receiver: %v", p, q, err)
+		}
+		if !EqualApprox(y, &want, 1e-10) {
+			t.Errorf("p=%d,q=%d: unexpected solution with aliased receiver", p, q)
+		}
+	}
+
+	var x VecDense
+	err := x.SolveKronVec(NewDense(2, 2, []float64{1, 2, 2, 4}), eye(3), NewVecDense(6, nil))
+	if _, ok := err.(Condition); !ok {
+		t.Errorf("expected Condition error for singular factor, got %v", err)
+	}
+}
@@ -0,0 +1,632 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"sort"
+
+	"gonum.org/v1/gonum/internal/asm/f64"
+)
+
+var (
+	csr *CSR
+	_   Matrix     = csr
+	_   allMatrix  = csr
+	_   ClonerFrom = csr
+
+	_ NonZeroDoer    = csr
+	_ RowNonZeroDoer = csr
+	_ ColNonZeroDoer = csr
+
+	csc *CSC
+	_   Matrix     = csc
+	_   allMatrix  = csc
+	_   ClonerFrom = csc
+
+	_ NonZeroDoer    = csc
+	_ RowNonZeroDoer = csc
+	_ ColNonZeroDoer = csc
+)
+
+// CSR is a sparse matrix in compressed sparse row format. The column indices
+// of the non-zero elements of row i are ind[indptr[i]:indptr[i+1]] and their
+// values are data[indptr[i]:indptr[i+1]].
+//
+// CSR is efficient for row access and for the product of the matrix with a
+// vector. Only the non-zero elements are stored, so the memory used is
+// proportional to the number of non-zero elements instead of the number of
+// elements of the matrix.
+type CSR struct {
+	mat compressed
+}
+
+// CSC is a sparse matrix in compressed sparse column format. The row indices
+// of the non-zero elements of column j are ind[indptr[j]:indptr[j+1]] and
+// their values are data[indptr[j]:indptr[j+1]].
+//
+// CSC is efficient for column access and for the product of the transpose of
+// the matrix with a vector. The CSC representation of a matrix is the CSR
+// representation of its transpose, so the transpose of a CSC is obtained
+// without copying.
+type CSC struct {
+	mat compressed
+}
+
+// compressed is the storage of a compressed sparse matrix. The major index
+// is the row index for CSR and the column index for CSC. The minor indices of
+// the non-zero elements at major index k are ind[ptr[k]:ptr[k+1]] in strictly
+// increasing order, and their values are data[ptr[k]:ptr[k+1]].
+type compressed struct {
+	major, minor int
+	ptr          []int
+	ind          []int
+	data         []float64
+}
+
+// sparser is a compressed sparse matrix type.
+type sparser interface {
+	Matrix
+	// compressed returns the storage of the receiver and whether the major
+	// index is the row index.
+	compressed() (c *compressed, byRow bool)
+}
+
+// NewCSR creates a new r×c sparse matrix in compressed sparse row format.
+// The length of indptr must be r+1 with indptr[0] == 0, indptr must be
+// non-decreasing, and the lengths of ind and data must be indptr[r]. The
+// column indices of each row must be strictly increasing and less than c.
+// NewCSR will panic if these conditions are not met. The slices are used as
+// the backing data of the returned matrix, and changes to the elements of the
+// matrix will be reflected in data.
+// NewCSR will panic if either r or c is zero.
+func NewCSR(r, c int, indptr, ind []int, data []float64) *CSR {
+	return &CSR{mat: newCompressed(r, c, indptr, ind, data)}
+}
+
+// NewCSC creates a new r×c sparse matrix in compressed sparse column format.
+// The length of indptr must be c+1 with indptr[0] == 0, indptr must be
+// non-decreasing, and the lengths of ind and data must be indptr[c]. The row
+// indices of each column must be strictly increasing and less than r.
+// NewCSC will panic if these conditions are not met. The slices are used as
+// the backing data of the returned matrix, and changes to the elements of the
+// matrix will be reflected in data.
+// NewCSC will panic if either r or c is zero.
+func NewCSC(r, c int, indptr, ind []int, data []float64) *CSC {
+	return &CSC{mat: newCompressed(c, r, indptr, ind, data)}
+}
+
+func newCompressed(major, minor int, ptr, ind []int, data []float64) compressed {
+	if major <= 0 || minor <= 0 {
+		if major == 0 || minor == 0 {
+			panic(ErrZeroLength)
+		}
+		panic(ErrNegativeDimension)
+	}
+	if len(ptr) != major+1 || ptr[0] != 0 || len(ind) != ptr[major] || len(data) != len(ind) {
+		panic(ErrShape)
+	}
+	for k := 0; k < major; k++ {
+		if ptr[k+1] < ptr[k] {
+			panic(ErrShape)
+		}
+		for p := ptr[k]; p < ptr[k+1]; p++ {
+			if ind[p] < 0 || minor <= ind[p] {
+				panic(ErrIndexOutOfRange)
+			}
+			if p > ptr[k] && ind[p] <= ind[p-1] {
+				panic(errUnsortedIndices)
+			}
+		}
+	}
+	return compressed{major: major, minor: minor, ptr: ptr, ind: ind, data: data}
+}
+
+// errUnsortedIndices is the panic value for sparse matrix indices that are not
+// strictly increasing.
+var errUnsortedIndices = Error{"mat: sparse indices not strictly increasing"}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *CSR) Dims() (r, c int) {
+	return m.mat.major, m.mat.minor
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *CSC) Dims() (r, c int) {
+	return m.mat.minor, m.mat.major
+}
+
+// At returns the element at row i, column j.
+func (m *CSR) At(i, j int) float64 {
+	if uint(i) >= uint(m.mat.major) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.mat.minor) {
+		panic(ErrColAccess)
+	}
+	return m.mat.at(i, j)
+}
+
+// At returns the element at row i, column j.
+func (m *CSC) At(i, j int) float64 {
+	if uint(i) >= uint(m.mat.minor) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(m.mat.major) {
+		panic(ErrColAccess)
+	}
+	return m.mat.at(j, i)
+}
+
+// T returns the transpose of the receiver as a CSC that shares the backing
+// data of the receiver.
+func (m *CSR) T() Matrix {
+	return &CSC{mat: m.mat}
+}
+
+// T returns the transpose of the receiver as a CSR that shares the backing
+// data of the receiver.
+func (m *CSC) T() Matrix {
+	return &CSR{mat: m.mat}
+}
+
+// NNZ returns the number of stored elements of the matrix.
+func (m *CSR) NNZ() int {
+	return len(m.mat.ind)
+}
+
+// NNZ returns the number of stored elements of the matrix.
+func (m *CSC) NNZ() int {
+	return len(m.mat.ind)
+}
+
+// RawCSR returns the backing slices of the receiver as described for NewCSR.
+// Changes to the elements of data will be reflected in the receiver.
+func (m *CSR) RawCSR() (indptr, ind []int, data []float64) {
+	return m.mat.ptr, m.mat.ind, m.mat.data
+}
+
+// RawCSC returns the backing slices of the receiver as described for NewCSC.
+// Changes to the elements of data will be reflected in the receiver.
+func (m *CSC) RawCSC() (indptr, ind []int, data []float64) {
+	return m.mat.ptr, m.mat.ind, m.mat.data
+}
+
+func (m *CSR) compressed() (*compressed, bool) {
+	return &m.mat, true
+}
+
+func (m *CSC) compressed() (*compressed, bool) {
+	return &m.mat, false
+}
+
+// IsEmpty returns whether the receiver is empty. Empty matrices can be the
+// receiver for size-restricted operations. The receiver can be emptied using
+// Reset.
+func (m *CSR) IsEmpty() bool {
+	return m.mat.major == 0
+}
+
+// IsEmpty returns whether the receiver is empty. Empty matrices can be the
+// receiver for size-restricted operations. The receiver can be emptied using
+// Reset.
+func (m *CSC) IsEmpty() bool {
+	return m.mat.major == 0
+}
+
+// Reset empties the matrix so that it can be reused as the
+// receiver of a dimensionally restricted operation.
+//
+// See the Reseter interface for more information.
+func (m *CSR) Reset() {
+	m.mat.reset()
+}
+
+// Reset empties the matrix so that it can be reused as the
+// receiver of a dimensionally restricted operation.
+//
+// See the Reseter interface for more information.
+func (m *CSC) Reset() {
+	m.mat.reset()
+}
+
+// Zero sets all of the stored elements to zero. The sparsity pattern of the
+// receiver is retained.
+func (m *CSR) Zero() {
+	zero(m.mat.data)
+}
+
+// Zero sets all of the stored elements to zero. The sparsity pattern of the
+// receiver is retained.
+func (m *CSC) Zero() {
+	zero(m.mat.data)
+}
+
+// CloneFrom makes a copy of a into the receiver, overwriting the previous
+// value of the receiver. Only the non-zero elements of a are stored.
+func (m *CSR) CloneFrom(a Matrix) {
+	m.mat = rowCompressed(a).clone()
+}
+
+// CloneFrom makes a copy of a into the receiver, overwriting the previous
+// value of the receiver. Only the non-zero elements of a are stored.
+func (m *CSC) CloneFrom(a Matrix) {
+	m.mat = rowCompressed(a.T()).clone()
+}
+
+// DoNonZero calls the function fn for each of the stored elements of m. The
+// function fn takes a row/column index and the element value of m at (i, j).
+func (m *CSR) DoNonZero(fn func(i, j int, v float64)) {
+	for i := 0; i < m.mat.major; i++ {
+		m.mat.doMajor(i, false, fn)
+	}
+}
+
+// DoNonZero calls the function fn for each of the stored elements of m. The
+// function fn takes a row/column index and the element value of m at (i, j).
+func (m *CSC) DoNonZero(fn func(i, j int, v float64)) {
+	for j := 0; j < m.mat.major; j++ {
+		m.mat.doMajor(j, true, fn)
+	}
+}
+
+// DoRowNonZero calls the function fn for each of the stored elements of row i
+// of m. The function fn takes a row/column index and the element value of m
+// at (i, j).
+func (m *CSR) DoRowNonZero(i int, fn func(i, j int, v float64)) {
+	if uint(i) >= uint(m.mat.major) {
+		panic(ErrRowAccess)
+	}
+	m.mat.doMajor(i, false, fn)
+}
+
+// DoRowNonZero calls the function fn for each of the stored elements of row i
+// of m. The function fn takes a row/column index and the element value of m
+// at (i, j).
+func (m *CSC) DoRowNonZero(i int, fn func(i, j int, v float64)) {
+	if uint(i) >= uint(m.mat.minor) {
+		panic(ErrRowAccess)
+	}
+	m.mat.doMinor(i, true, fn)
+}
+
+// DoColNonZero calls the function fn for each of the stored elements of
+// column j of m. The function fn takes a row/column index and the element
+// value of m at (i, j).
+func (m *CSR) DoColNonZero(j int, fn func(i, j int, v float64)) {
+	if uint(j) >= uint(m.mat.minor) {
+		panic(ErrColAccess)
+	}
+	m.mat.doMinor(j, false, fn)
+}
+
+// DoColNonZero calls the function fn for each of the stored elements of
+// column j of m. The function fn takes a row/column index and the element
+// value of m at (i, j).
+func (m *CSC) DoColNonZero(j int, fn func(i, j int, v float64)) {
+	if uint(j) >= uint(m.mat.major) {
+		panic(ErrColAccess)
+	}
+	m.mat.doMajor(j, true, fn)
+}
+
+// Mul stores the matrix product of a and b in the receiver. Only the elements
+// that are structurally non-zero in the product are stored. If the receiver
+// is not empty, it must have the dimensions of the product, otherwise Mul
+// will panic.
+//
+// The product is computed without forming dense intermediates if a and b are
+// CSR or CSC matrices, or their transposes.
+func (m *CSR) Mul(a, b Matrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ac != br {
+		panic(ErrShape)
+	}
+	if !m.IsEmpty() && (m.mat.major != ar || m.mat.minor != bc) {
+		panic(ErrShape)
+	}
+	m.mat = mulCompressed(rowCompressed(a), rowCompressed(b))
+}
+
+// Mul stores the matrix product of a and b in the receiver. Only the elements
+// that are structurally non-zero in the product are stored. If the receiver
+// is not empty, it must have the dimensions of the product, otherwise Mul
+// will panic.
+//
+// The product is computed without forming dense intermediates if a and b are
+// CSR or CSC matrices, or their transposes.
+func (m *CSC) Mul(a, b Matrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ac != br {
+		panic(ErrShape)
+	}
+	if !m.IsEmpty() && (m.mat.major != bc || m.mat.minor != ar) {
+		panic(ErrShape)
+	}
+	// The columns of a b are the rows of bᵀ aᵀ.
+	m.mat = mulCompressed(rowCompressed(b.T()), rowCompressed(a.T()))
+}
+
+// Add stores the sum of a and b in the receiver. The stored elements of the
+// sum are the union of the non-zero elements of a and b. If the receiver is
+// not empty, it must have the dimensions of a and b, otherwise Add will
+// panic.
+func (m *CSR) Add(a, b Matrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != br || ac != bc {
+		panic(ErrShape)
+	}
+	if !m.IsEmpty() && (m.mat.major != ar || m.mat.minor != ac) {
+		panic(ErrShape)
+	}
+	m.mat = addCompressed(rowCompressed(a), rowCompressed(b))
+}
+
+// Add stores the sum of a and b in the receiver. The stored elements of the
+// sum are the union of the non-zero elements of a and b. If the receiver is
+// not empty, it must have the dimensions of a and b, otherwise Add will
+// panic.
+func (m *CSC) Add(a, b Matrix) {
+	ar, ac := a.Dims()
+	br, bc := b.Dims()
+	if ar != br || ac != bc {
+		panic(ErrShape)
+	}
+	if !m.IsEmpty() && (m.mat.major != ac || m.mat.minor != ar) {
+		panic(ErrShape)
+	}
+	m.mat = addCompressed(rowCompressed(a.T()), rowCompressed(b.T()))
+}
+
+// at returns the element at the major index k and minor index l.
+func (c *compressed) at(k, l int) float64 {
+	lo, hi := c.ptr[k], c.ptr[k+1]
+	p := lo + sort.SearchInts(c.ind[lo:hi], l)
+	if p < hi && c.ind[p] == l {
+		return c.data[p]
+	}
+	return 0
+}
+
+func (c *compressed) reset() {
+	c.major = 0
+	c.minor = 0
+	c.ptr = c.ptr[:0]
+	c.ind = c.ind[:0]
+	c.data = c.data[:0]
+}
+
+// doMajor calls fn for the stored elements at the major index k. If trans is
+// true, the major index is the column index of fn.
+func (c *compressed) doMajor(k int, trans bool, fn func(i, j int, v float64)) {
+	for p := c.ptr[k]; p < c.ptr[k+1]; p++ {
+		if trans {
+			fn(c.ind[p], k, c.data[p])
+		} else {
+			fn(k, c.ind[p], c.data[p])
+		}
+	}
+}
+
+// doMinor calls fn for the stored elements at the minor index l. If trans is
+// true, the major index is the column index of fn.
+func (c *compressed) doMinor(l int, trans bool, fn func(i, j int, v float64)) {
+	for k := 0; k < c.major; k++ {
+		lo, hi := c.ptr[k], c.ptr[k+1]
+		p := lo + sort.SearchInts(c.ind[lo:hi], l)
+		if p < hi && c.ind[p] == l {
+			if trans {
+				fn(l, k, c.data[p])
+			} else {
+				fn(k, l, c.data[p])
+			}
+		}
+	}
+}
+
+// clone returns a copy of c that does not share the backing data.
+func (c *compressed) clone() compressed {
+	return compressed{
+		major: c.major,
+		minor: c.minor,
+		ptr:   append([]int(nil), c.ptr...),
+		ind:   append([]int(nil), c.ind...),
+		data:  append([]float64(nil), c.data...),
+	}
+}
+
+// transpose returns the compressed storage of the transpose of c, in which
+// the major and minor indices are exchanged.
+func (c *compressed) transpose() *compressed {
+	t := &compressed{
+		major: c.minor,
+		minor: c.major,
+		ptr:   make([]int, c.minor+1),
+		ind:   make([]int, len(c.ind)),
+		data:  make([]float64, len(c.data)),
+	}
+	for _, l := range c.ind {
+		t.ptr[l+1]++
+	}
+	for l := 0; l < t.major; l++ {
+		t.ptr[l+1] += t.ptr[l]
+	}
+	next := getInts(t.major, false)
+	copy(next, t.ptr[:t.major])
+	for k := 0; k < c.major; k++ {
+		for p := c.ptr[k]; p < c.ptr[k+1]; p++ {
+			l := c.ind[p]
+			q := next[l]
+			t.ind[q] = k
+			t.data[q] = c.data[p]
+			next[l]++
+		}
+	}
+	putInts(next)
+	return t
+}
+
+// mulVec computes dst = c x if trans is false and dst = cᵀ x otherwise, where
+// the major index of c is its row index. The elements of x and dst are
+// separated by incX and incDst.
+func (c *compressed) mulVec(dst []float64, incDst int, trans bool, x []float64, incX int) {
+	if !trans {
+		for k := 0; k < c.major; k++ {
+			var v float64
+			for p := c.ptr[k]; p < c.ptr[k+1]; p++ {
+				v += c.data[p] * x[c.ind[p]*incX]
+			}
+			dst[k*incDst] = v
+		}
+		return
+	}
+	for l := 0; l < c.minor; l++ {
+		dst[l*incDst] = 0
+	}
+	for k := 0; k < c.major; k++ {
+		xk := x[k*incX]
+		if xk == 0 {
+			continue
+		}
+		for p := c.ptr[k]; p < c.ptr[k+1]; p++ {
+			dst[c.ind[p]*incDst] += c.data[p] * xk
+		}
+	}
+}
+
+// rowCompressed returns the compressed storage of a with the row index as the
+// major index. The returned value may share the backing data of a and must
+// not be modified.
+func rowCompressed(a Matrix) *compressed {
+	u, trans := untranspose(a)
+	if s, ok := u.(sparser); ok {
+		c, byRow := s.compressed()
+		if byRow != trans {
+			return c
+		}
+		return c.transpose()
+	}
+	r, cols := a.Dims()
+	c := &compressed{
+		major: r,
+		minor: cols,
+		ptr:   make([]int, r+1),
+	}
+	for i := 0; i < r; i++ {
+		for j := 0; j < cols; j++ {
+			v := a.At(i, j)
+			if v != 0 {
+				c.ind = append(c.ind, j)
+				c.data = append(c.data, v)
+			}
+		}
+		c.ptr[i+1] = len(c.ind)
+	}
+	return c
+}
+
+// mulCompressed returns the product of a and b, which have the row index as
+// the major index, using Gustavson's algorithm.
+func mulCompressed(a, b *compressed) compressed {
+	c := compressed{
+		major: a.major,
+		minor: b.minor,
+		ptr:   make([]int, a.major+1),
+	}
+	mark := getInts(b.minor, false)
+	for j := range mark {
+		mark[j] = -1
+	}
+	acc := getFloats(b.minor, false)
+	for i := 0; i < a.major; i++ {
+		start := len(c.ind)
+		for p := a.ptr[i]; p < a.ptr[i+1]; p++ {
+			k := a.ind[p]
+			v := a.data[p]
+			for q := b.ptr[k]; q < b.ptr[k+1]; q++ {
+				j := b.ind[q]
+				if mark[j] != i {
+					mark[j] = i
+					c.ind = append(c.ind, j)
+					acc[j] = v * b.data[q]
+				} else {
+					acc[j] += v * b.data[q]
+				}
+			}
+		}
+		row := c.ind[start:]
+		sort.Ints(row)
+		for _, j := range row {
+			c.data = append(c.data, acc[j])
+		}
+		c.ptr[i+1] = len(c.ind)
+	}
+	putInts(mark)
+	putFloats(acc)
+	return c
+}
+
+// addCompressed returns the sum of a and b, which have the same dimensions.
+func addCompressed(a, b *compressed) compressed {
+	c := compressed{
+		major: a.major,
+		minor: a.minor,
+		ptr:   make([]int, a.major+1),
+		ind:   make([]int, 0, len(a.ind)+len(b.ind)),
+		data:  make([]float64, 0, len(a.ind)+len(b.ind)),
+	}
+	for k := 0; k < a.major; k++ {
+		p, pEnd := a.ptr[k], a.ptr[k+1]
+		q, qEnd := b.ptr[k], b.ptr[k+1]
+		for p < pEnd || q < qEnd {
+			switch {
+			case q == qEnd || (p < pEnd && a.ind[p] < b.ind[q]):
+				c.ind = append(c.ind, a.ind[p])
+				c.data = append(c.data, a.data[p])
+				p++
+			case p == pEnd || b.ind[q] < a.ind[p]:
+				c.ind = append(c.ind, b.ind[q])
+				c.data = append(c.data, b.data[q])
+				q++
+			default:
+				c.ind = append(c.ind, a.ind[p])
+				c.data = append(c.data, a.data[p]+b.data[q])
+				p++
+				q++
+			}
+		}
+		c.ptr[k+1] = len(c.ind)
+	}
+	return c
+}
+
+// mulSparse stores in m the product of the sparse matrix a, transposed if
+// aTrans is true, and b. The receiver must have the dimensions of the product
+// and must not share backing data with b.
+func (m *Dense) mulSparse(a sparser, aTrans bool, b Matrix) {
+	br, bc := b.Dims()
+	bd, ok := b.(*Dense)
+	if !ok {
+		bd = getWorkspace(br, bc, false)
+		bd.Copy(b)
+		defer putWorkspace(bd)
+	}
+	m.Zero()
+	c, byRow := a.compressed()
+	// The major index of c is the row index of the product if the storage
+	// order and the transpose cancel.
+	rowMajor := byRow != aTrans
+	for k := 0; k < c.major; k++ {
+		for p := c.ptr[k]; p < c.ptr[k+1]; p++ {
+			i, l := k, c.ind[p]
+			if !rowMajor {
+				i, l = l, k
+			}
+			f64.AxpyUnitary(c.data[p], bd.mat.Data[l*bd.mat.Stride:l*bd.mat.Stride+bc], m.mat.Data[i*m.mat.Stride:i*m.mat.Stride+bc])
+		}
+	}
+}
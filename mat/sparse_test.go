@@ -0,0 +1,225 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// randSparse returns a random r×c Dense matrix in which each element is
+// non-zero with probability density.
+func randSparse(r, c int, density float64, rnd *rand.Rand) *Dense {
+	d := NewDense(r, c, nil)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			if rnd.Float64() < density {
+				d.Set(i, j, rnd.NormFloat64())
+			}
+		}
+	}
+	return d
+}
+
+func TestNewCSR(t *testing.T) {
+	t.Parallel()
+	// 1 0 2
+	// 0 0 3
+	// 4 5 0
+	// 0 0 0
+	a := NewCSR(4, 3, []int{0, 2, 3, 5, 5}, []int{0, 2, 2, 0, 1}, []float64{1, 2, 3, 4, 5})
+	want := NewDense(4, 3, []float64{
+		1, 0, 2,
+		0, 0, 3,
+		4, 5, 0,
+		0, 0, 0,
+	})
+	if !Equal(a, want) {
+		t.Errorf("unexpected CSR matrix:\ngot:\n%v\nwant:\n%v", Formatted(a), Formatted(want))
+	}
+	if a.NNZ() != 5 {
+		t.Errorf("unexpected number of non-zeros: got %d, want 5", a.NNZ())
+	}
+	at := a.T()
+	if _, ok := at.(*CSC); !ok {
+		t.Errorf("transpose of CSR is not a CSC: %T", at)
+	}
+	if !Equal(at, want.T()) {
+		t.Errorf("unexpected transpose")
+	}
+
+	c := NewCSC(3, 4, []int{0, 2, 3, 5, 5}, []int{0, 2, 2, 0, 1}, []float64{1, 2, 3, 4, 5})
+	if !Equal(c, want.T()) {
+		t.Errorf("unexpected CSC matrix:\ngot:\n%v\nwant:\n%v", Formatted(c), Formatted(want.T()))
+	}
+
+	var nnz int
+	a.DoNonZero(func(i, j int, v float64) {
+		nnz++
+		if v != want.At(i, j) {
+			t.Errorf("unexpected value at (%d,%d): got %v, want %v", i, j, v, want.At(i, j))
+		}
+	})
+	if nnz != 5 {
+		t.Errorf("unexpected number of elements visited: got %d, want 5", nnz)
+	}
+	for _, m := range []interface {
+		Matrix
+		RowNonZeroDoer
+		ColNonZeroDoer
+	}{a, at.(*CSC)} {
+		r, cols := m.Dims()
+		for i := 0; i < r; i++ {
+			m.DoRowNonZero(i, func(ii, j int, v float64) {
+				if ii != i || v != m.At(i, j) {
+					t.Errorf("unexpected row element (%d,%d)=%v in row %d", ii, j, v, i)
+				}
+			})
+		}
+		for j := 0; j < cols; j++ {
+			m.DoColNonZero(j, func(i, jj int, v float64) {
+				if jj != j || v != m.At(i, j) {
+					t.Errorf("unexpected column element (%d,%d)=%v in column %d", i, jj, v, j)
+				}
+			})
+		}
+	}
+
+	for _, test := range []struct {
+		name   string
+		indptr []int
+		ind    []int
+		data   []float64
+	}{
+		{"short indptr", []int{0, 1}, []int{0}, []float64{1}},
+		{"bad start", []int{1, 1, 1, 1, 1}, []int{0}, []float64{1}},
+		{"decreasing indptr", []int{0, 2, 1, 2, 2}, []int{0, 1}, []float64{1, 2}},
+		{"data length", []int{0, 1, 1, 1, 1}, []int{0}, []float64{1, 2}},
+		{"index range", []int{0, 1, 1, 1, 1}, []int{3}, []float64{1}},
+		{"unsorted", []int{0, 2, 2, 2, 2}, []int{1, 0}, []float64{1, 2}},
+		{"duplicate", []int{0, 2, 2, 2, 2}, []int{1, 1}, []float64{1, 2}},
+	} {
+		if ok, _ := panics(func() { NewCSR(4, 3, test.indptr, test.ind, test.data) }); !ok {
+			t.Errorf("%s: expected panic", test.name)
+		}
+	}
+}
+
+func TestSparseArithmetic(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		r, k, c int
+	}{
+		{1, 1, 1},
+		{3, 4, 5},
+		{10, 7, 3},
+		{20, 20, 20},
+	} {
+		da := randSparse(test.r, test.k, 0.3, rnd)
+		db := randSparse(test.k, test.c, 0.3, rnd)
+		dd := randSparse(test.r, test.k, 0.3, rnd)
+
+		var a, d CSR
+		a.CloneFrom(da)
+		d.CloneFrom(dd)
+		var bc CSC
+		bc.CloneFrom(db)
+		if !Equal(&a, da) || !Equal(&bc, db) {
+			t.Fatalf("unexpected CloneFrom")
+		}
+
+		var want Dense
+		want.Mul(da, db)
+		for _, b := range []Matrix{&bc, bc.T().T(), db} {
+			var got CSR
+			got.Mul(&a, b)
+			if !EqualApprox(&got, &want, 1e-14) {
+				t.Errorf("%d×%d×%d: unexpected CSR product with %T", test.r, test.k, test.c, b)
+			}
+			var gotC CSC
+			gotC.Mul(&a, b)
+			if !EqualApprox(&gotC, &want, 1e-14) {
+				t.Errorf("%d×%d×%d: unexpected CSC product with %T", test.r, test.k, test.c, b)
+			}
+			var gotD Dense
+			gotD.Mul(&a, b)
+			if !EqualApprox(&gotD, &want, 1e-14) {
+				t.Errorf("%d×%d×%d: unexpected dense product with %T", test.r, test.k, test.c, b)
+			}
+		}
+		// Products with transposed operands.
+		var gotT CSR
+		gotT.Mul(a.T().T(), bc.T().T())
+		if !EqualApprox(&gotT, &want, 1e-14) {
+			t.Errorf("%d×%d×%d: unexpected product of double transposes", test.r, test.k, test.c)
+		}
+		var wantT, gotDT Dense
+		wantT.Mul(db.T(), da.T())
+		gotDT.Mul(bc.T(), a.T())
+		if !EqualApprox(&gotDT, &wantT, 1e-14) {
+			t.Errorf("%d×%d×%d: unexpected dense product of transposes", test.r, test.k, test.c)
+		}
+
+		var wantSum Dense
+		wantSum.Add(da, dd)
+		var sum CSR
+		sum.Add(&a, &d)
+		if !EqualApprox(&sum, &wantSum, 1e-14) {
+			t.Errorf("%d×%d: unexpected CSR sum", test.r, test.k)
+		}
+		var sumC CSC
+		sumC.Add(&a, dd)
+		if !EqualApprox(&sumC, &wantSum, 1e-14) {
+			t.Errorf("%d×%d: unexpected CSC sum", test.r, test.k)
+		}
+		// The receiver may be an operand.
+		sum.Add(&sum, &a)
+		wantSum.Add(&wantSum, da)
+		if !EqualApprox(&sum, &wantSum, 1e-14) {
+			t.Errorf("%d×%d: unexpected sum into operand", test.r, test.k)
+		}
+
+		x := NewVecDense(test.k, nil)
+		for i := 0; i < test.k; i++ {
+			x.SetVec(i, rnd.NormFloat64())
+		}
+		var wantV, gotV VecDense
+		wantV.MulVec(da, x)
+		gotV.MulVec(&a, x)
+		if !EqualApprox(&gotV, &wantV, 1e-14) {
+			t.Errorf("%d×%d: unexpected CSR MulVec", test.r, test.k)
+		}
+		var ac CSC
+		ac.CloneFrom(da)
+		gotV.Reset()
+		gotV.MulVec(&ac, x)
+		if !EqualApprox(&gotV, &wantV, 1e-14) {
+			t.Errorf("%d×%d: unexpected CSC MulVec", test.r, test.k)
+		}
+		y := NewVecDense(test.r, nil)
+		for i := 0; i < test.r; i++ {
+			y.SetVec(i, rnd.NormFloat64())
+		}
+		wantV.Reset()
+		wantV.MulVec(da.T(), y)
+		for _, m := range []Matrix{a.T(), ac.T(), Transpose{&a}} {
+			gotV.Reset()
+			gotV.MulVec(m, y)
+			if !EqualApprox(&gotV, &wantV, 1e-14) {
+				t.Errorf("%d×%d: unexpected transposed MulVec with %T", test.r, test.k, m)
+			}
+		}
+	}
+
+	a := NewCSR(2, 2, []int{0, 1, 2}, []int{0, 1}, []float64{1, 2})
+	if ok, _ := panics(func() { a.Mul(a, NewDense(2, 3, nil)) }); !ok {
+		t.Errorf("expected panic for non-empty receiver of wrong shape")
+	}
+	if ok, _ := panics(func() { new(CSR).Add(a, NewDense(3, 2, nil)) }); !ok {
+		t.Errorf("expected panic for mismatched sum")
+	}
+}
@@ -0,0 +1,565 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+)
+
+const (
+	badSparseCholesky = "mat: invalid sparse Cholesky factorization"
+	badSymbolic       = "mat: sparsity pattern does not match symbolic analysis"
+)
+
+// SymbolicCholesky is the symbolic analysis of the sparse Cholesky
+// factorization of a symmetric matrix. It holds the fill-reducing ordering,
+// the elimination tree and the sparsity pattern of the factor, which depend
+// only on the sparsity pattern of the matrix. A SymbolicCholesky can be used
+// for the factorization of any number of matrices whose non-zero elements
+// are a subset of the analyzed pattern, and it is not modified by the
+// factorizations, so it may be shared between concurrent factorizations.
+type SymbolicCholesky struct {
+	n      int
+	perm   []int // Row and column perm[k] of A is row and column k of P*A*Pᵀ.
+	pinv   []int // Inverse of perm.
+	parent []int // Elimination tree of P*A*Pᵀ.
+	ptr    []int // Column pointers of L.
+}
+
+// Analyze computes the symbolic analysis of the Cholesky factorization of
+// the symmetric matrix a, of which only the upper triangular part is used.
+// The rows and columns are ordered with a minimum degree ordering of the
+// sparsity pattern of a to reduce the fill-in of the factor.
+func (s *SymbolicCholesky) Analyze(a Matrix) {
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	n := r
+	upper := upperCompressed(a)
+	s.n = n
+	s.perm = minimumDegree(symmetricPattern(upper))
+	s.pinv = make([]int, n)
+	for k, i := range s.perm {
+		s.pinv[i] = k
+	}
+	pa := symmetricPermute(upper, s.pinv)
+	s.parent = eliminationTree(pa)
+
+	// Count the non-zero elements of the columns of L from the patterns of
+	// its rows.
+	counts := make([]int, n)
+	mark := make([]int, n)
+	for i := range mark {
+		mark[i] = -1
+	}
+	stack := make([]int, n)
+	for k := 0; k < n; k++ {
+		top := s.rowPattern(pa, k, mark, stack)
+		for _, i := range stack[top:] {
+			counts[i]++
+		}
+	}
+	s.ptr = make([]int, n+1)
+	for k, v := range counts {
+		// The diagonal is stored first in each column.
+		s.ptr[k+1] = s.ptr[k] + v + 1
+	}
+}
+
+// Symmetric returns the dimension of the analyzed matrix.
+func (s *SymbolicCholesky) Symmetric() int {
+	return s.n
+}
+
+// NNZ returns the number of non-zero elements of the Cholesky factor L,
+// including the diagonal.
+func (s *SymbolicCholesky) NNZ() int {
+	if s.n == 0 {
+		return 0
+	}
+	return s.ptr[s.n]
+}
+
+// rowPattern stores the columns of the off-diagonal non-zero elements of row k
+// of L in stack[top:] and returns top. The columns are found by following the
+// paths in the elimination tree from the rows of the non-zero elements of
+// column k of the upper triangle pa of P*A*Pᵀ. Visited nodes are marked in mark
+// with k.
+func (s *SymbolicCholesky) rowPattern(pa *compressed, k int, mark, stack []int) int {
+	top := s.n
+	mark[k] = k
+	for p := pa.ptr[k]; p < pa.ptr[k+1]; p++ {
+		i := pa.ind[p]
+		if i > k {
+			continue
+		}
+		// Follow the path to the root of the elimination tree until a
+		// visited node is found, and push the path onto the stack.
+		n := 0
+		for ; mark[i] != k; i = s.parent[i] {
+			if i == -1 {
+				// k is not an ancestor in the elimination tree.
+				panic(badSymbolic)
+			}
+			stack[n] = i
+			n++
+			mark[i] = k
+		}
+		for n > 0 {
+			n--
+			top--
+			stack[top] = stack[n]
+		}
+	}
+	return top
+}
+
+// SparseCholesky is a type for creating and using the Cholesky factorization
+//  P * A * Pᵀ = L * Lᵀ
+// of a sparse symmetric positive definite matrix A, where P is a permutation
+// matrix that reduces the fill-in of the lower triangular factor L. The
+// factor is stored in compressed sparse column format.
+//
+// The factorization is split into a symbolic analysis, which depends only on
+// the sparsity pattern of A, and a numeric factorization. Matrices with the
+// same sparsity pattern, for example in time stepping or in Newton's method,
+// can be factorized with FactorizeSymbolic reusing the analysis.
+type SparseCholesky struct {
+	sym  *SymbolicCholesky
+	l    compressed // L by columns, with the diagonal first in each column.
+	cond float64
+}
+
+// Factorize computes the symbolic analysis and the Cholesky factorization of
+// the symmetric positive definite matrix a, of which only the upper
+// triangular part is used. If a is not positive definite, false is returned.
+//
+// Factorize is most efficient if a is a CSC or CSR matrix. Other matrix
+// types are converted by examining all of their elements.
+func (c *SparseCholesky) Factorize(a Matrix) (ok bool) {
+	var sym SymbolicCholesky
+	sym.Analyze(a)
+	return c.FactorizeSymbolic(a, &sym)
+}
+
+// FactorizeSymbolic computes the Cholesky factorization of the symmetric
+// positive definite matrix a using the symbolic analysis sym, which is
+// retained by the receiver. The non-zero elements of the upper triangular
+// part of a must be a subset of the sparsity pattern analyzed by sym,
+// otherwise FactorizeSymbolic will panic. If a is not positive definite,
+// false is returned.
+func (c *SparseCholesky) FactorizeSymbolic(a Matrix, sym *SymbolicCholesky) (ok bool) {
+	r, cols := a.Dims()
+	if r != cols {
+		panic(ErrSquare)
+	}
+	if r != sym.n {
+		panic(ErrShape)
+	}
+	n := sym.n
+	upper := upperCompressed(a)
+	pa := symmetricPermute(upper, sym.pinv)
+
+	nnz := sym.ptr[n]
+	l := compressed{
+		major: n,
+		minor: n,
+		ptr:   sym.ptr,
+		ind:   make([]int, nnz),
+		data:  make([]float64, nnz),
+	}
+	next := make([]int, n) // Next free position in each column of L.
+	copy(next, sym.ptr[:n])
+	x := make([]float64, n)
+	mark := make([]int, n)
+	for i := range mark {
+		mark[i] = -1
+	}
+	stack := make([]int, n)
+	c.Reset()
+	for k := 0; k < n; k++ {
+		// Compute row k of L by solving
+		//  L[:k,:k] * L[k,:k]ᵀ = A[:k,k]
+		// with the up-looking algorithm.
+		top := sym.rowPattern(pa, k, mark, stack)
+		x[k] = 0
+		for p := pa.ptr[k]; p < pa.ptr[k+1]; p++ {
+			if i := pa.ind[p]; i <= k {
+				x[i] += pa.data[p]
+			}
+		}
+		d := x[k]
+		x[k] = 0
+		for _, i := range stack[top:] {
+			lki := x[i] / l.data[sym.ptr[i]]
+			x[i] = 0
+			for p := sym.ptr[i] + 1; p < next[i]; p++ {
+				x[l.ind[p]] -= l.data[p] * lki
+			}
+			d -= lki * lki
+			p := next[i]
+			if p >= sym.ptr[i+1] {
+				panic(badSymbolic)
+			}
+			l.ind[p] = k
+			l.data[p] = lki
+			next[i]++
+		}
+		if d <= 0 || math.IsNaN(d) {
+			return false
+		}
+		p := next[k]
+		if p >= sym.ptr[k+1] {
+			panic(badSymbolic)
+		}
+		l.ind[p] = k
+		l.data[p] = math.Sqrt(d)
+		next[k]++
+	}
+	for k := 0; k < n; k++ {
+		if next[k] != sym.ptr[k+1] {
+			// The pattern of a is a strict subset of the analyzed pattern,
+			// and the unused positions are marked with the index -1.
+			for p := next[k]; p < sym.ptr[k+1]; p++ {
+				l.ind[p] = -1
+			}
+		}
+	}
+	c.sym = sym
+	c.l = l
+	c.updateCond(upper)
+	return true
+}
+
+// upperCompressed returns the upper triangle of the square matrix a by
+// columns.
+func upperCompressed(a Matrix) *compressed {
+	c := rowCompressed(a.T())
+	u := &compressed{
+		major: c.major,
+		minor: c.minor,
+		ptr:   make([]int, c.major+1),
+	}
+	for j := 0; j < c.major; j++ {
+		for p := c.ptr[j]; p < c.ptr[j+1]; p++ {
+			if i := c.ind[p]; i <= j {
+				u.ind = append(u.ind, i)
+				u.data = append(u.data, c.data[p])
+			}
+		}
+		u.ptr[j+1] = len(u.ind)
+	}
+	return u
+}
+
+// symmetricPermute returns the upper triangle by columns of P*A*Pᵀ, where
+// a is the upper triangle of A by columns and pinv is the inverse of the
+// permutation of P. The row indices of the columns are not sorted.
+func symmetricPermute(a *compressed, pinv []int) *compressed {
+	n := a.major
+	c := &compressed{
+		major: n,
+		minor: n,
+		ptr:   make([]int, n+1),
+		ind:   make([]int, len(a.ind)),
+		data:  make([]float64, len(a.data)),
+	}
+	for j := 0; j < n; j++ {
+		for p := a.ptr[j]; p < a.ptr[j+1]; p++ {
+			c.ptr[max(pinv[a.ind[p]], pinv[j])+1]++
+		}
+	}
+	for j := 0; j < n; j++ {
+		c.ptr[j+1] += c.ptr[j]
+	}
+	next := getInts(n, false)
+	copy(next, c.ptr[:n])
+	for j := 0; j < n; j++ {
+		for p := a.ptr[j]; p < a.ptr[j+1]; p++ {
+			i2, j2 := pinv[a.ind[p]], pinv[j]
+			if i2 > j2 {
+				i2, j2 = j2, i2
+			}
+			q := next[j2]
+			c.ind[q] = i2
+			c.data[q] = a.data[p]
+			next[j2]++
+		}
+	}
+	putInts(next)
+	return c
+}
+
+// eliminationTree returns the parents in the elimination tree of the
+// symmetric matrix with the upper triangle a by columns. The roots have the
+// parent -1.
+func eliminationTree(a *compressed) []int {
+	n := a.major
+	parent := make([]int, n)
+	ancestor := make([]int, n)
+	for k := 0; k < n; k++ {
+		parent[k] = -1
+		ancestor[k] = -1
+		for p := a.ptr[k]; p < a.ptr[k+1]; p++ {
+			i := a.ind[p]
+			for i != -1 && i < k {
+				// Follow the path to the root with path compression.
+				next := ancestor[i]
+				ancestor[i] = k
+				if next == -1 {
+					parent[i] = k
+				}
+				i = next
+			}
+		}
+	}
+	return parent
+}
+
+// updateCond estimates the condition number of the factorized matrix in the
+// 1-norm. upper is the upper triangle of the factorized matrix by columns.
+func (c *SparseCholesky) updateCond(upper *compressed) {
+	n := c.sym.n
+	sums := make([]float64, n)
+	for j := 0; j < n; j++ {
+		for p := upper.ptr[j]; p < upper.ptr[j+1]; p++ {
+			i := upper.ind[p]
+			v := math.Abs(upper.data[p])
+			sums[j] += v
+			if i != j {
+				sums[i] += v
+			}
+		}
+	}
+	var anorm float64
+	for _, v := range sums {
+		anorm = math.Max(anorm, v)
+	}
+	c.cond = anorm * normEst1(n, func(x []float64, _ bool) {
+		c.solve(x)
+	})
+}
+
+// solve overwrites x with the solution of A*x = b, where b is the value of x
+// on entry.
+func (c *SparseCholesky) solve(x []float64) {
+	n := c.sym.n
+	l := &c.l
+	work := getFloats(n, false)
+	defer putFloats(work)
+	for k, i := range c.sym.perm {
+		work[k] = x[i]
+	}
+	// L*z = P*b.
+	for k := 0; k < n; k++ {
+		p := l.ptr[k]
+		work[k] /= l.data[p]
+		v := work[k]
+		for p++; p < l.ptr[k+1]; p++ {
+			if i := l.ind[p]; i >= 0 {
+				work[i] -= l.data[p] * v
+			}
+		}
+	}
+	// Lᵀ*y = z.
+	for k := n - 1; k >= 0; k-- {
+		p := l.ptr[k]
+		v := work[k]
+		for q := p + 1; q < l.ptr[k+1]; q++ {
+			if i := l.ind[q]; i >= 0 {
+				v -= l.data[q] * work[i]
+			}
+		}
+		work[k] = v / l.data[p]
+	}
+	for k, i := range c.sym.perm {
+		x[i] = work[k]
+	}
+}
+
+// valid returns whether the receiver contains a factorization.
+func (c *SparseCholesky) valid() bool {
+	return c.sym != nil
+}
+
+// Reset resets the factorization so that it can be reused as the receiver of
+// a dimensionally restricted operation.
+func (c *SparseCholesky) Reset() {
+	c.sym = nil
+	c.l = compressed{}
+}
+
+// Symbolic returns the symbolic analysis used by the factorization, which can
+// be passed to FactorizeSymbolic to factorize matrices with the same sparsity
+// pattern.
+// Symbolic will panic if the receiver does not contain a factorization.
+func (c *SparseCholesky) Symbolic() *SymbolicCholesky {
+	if !c.valid() {
+		panic(badSparseCholesky)
+	}
+	return c.sym
+}
+
+// Symmetric returns the dimension of the factorized matrix.
+// Symmetric will panic if the receiver does not contain a factorization.
+func (c *SparseCholesky) Symmetric() int {
+	if !c.valid() {
+		panic(badSparseCholesky)
+	}
+	return c.sym.n
+}
+
+// Cond returns an estimate of the condition number of the factorized matrix.
+// Cond will panic if the receiver does not contain a factorization.
+func (c *SparseCholesky) Cond() float64 {
+	if !c.valid() {
+		panic(badSparseCholesky)
+	}
+	return c.cond
+}
+
+// Det returns the determinant of the factorized matrix.
+// Det will panic if the receiver does not contain a factorization.
+func (c *SparseCholesky) Det() float64 {
+	return math.Exp(c.LogDet())
+}
+
+// LogDet returns the log of the determinant of the factorized matrix.
+// LogDet will panic if the receiver does not contain a factorization.
+func (c *SparseCholesky) LogDet() float64 {
+	if !c.valid() {
+		panic(badSparseCholesky)
+	}
+	var det float64
+	for k := 0; k < c.sym.n; k++ {
+		det += 2 * math.Log(c.l.data[c.l.ptr[k]])
+	}
+	return det
+}
+
+// Permutation returns the permutation of the factorization
+// P * A * Pᵀ = L * Lᵀ. Row and column k of P * A * Pᵀ are row and column
+// perm[k] of A. If perm is nil, new memory will be allocated, otherwise its
+// length must be equal to the size of the factorized matrix.
+// Permutation will panic if the receiver does not contain a factorization.
+func (c *SparseCholesky) Permutation(perm []int) []int {
+	if !c.valid() {
+		panic(badSparseCholesky)
+	}
+	if perm == nil {
+		perm = make([]int, c.sym.n)
+	}
+	if len(perm) != c.sym.n {
+		panic(badSliceLength)
+	}
+	copy(perm, c.sym.perm)
+	return perm
+}
+
+// LTo stores the lower triangular factor L of the factorization in dst,
+// overwriting the previous value of dst.
+// LTo will panic if the receiver does not contain a factorization.
+func (c *SparseCholesky) LTo(dst *CSC) {
+	if !c.valid() {
+		panic(badSparseCholesky)
+	}
+	n := c.sym.n
+	l := compressed{
+		major: n,
+		minor: n,
+		ptr:   make([]int, n+1),
+		ind:   make([]int, 0, len(c.l.ind)),
+		data:  make([]float64, 0, len(c.l.data)),
+	}
+	for k := 0; k < n; k++ {
+		for p := c.l.ptr[k]; p < c.l.ptr[k+1]; p++ {
+			if i := c.l.ind[p]; i >= 0 {
+				l.ind = append(l.ind, i)
+				l.data = append(l.data, c.l.data[p])
+			}
+		}
+		l.ptr[k+1] = len(l.ind)
+	}
+	dst.mat = l
+}
+
+// SolveTo finds the matrix X that solves A * X = B where A is represented
+// by the sparse Cholesky decomposition. The result is stored in-place into
+// dst.
+// If the condition number of A is large, a Condition error is returned. See
+// the documentation for Condition for more information.
+// SolveTo will panic if the receiver does not contain a factorization.
+func (c *SparseCholesky) SolveTo(dst *Dense, b Matrix) error {
+	if !c.valid() {
+		panic(badSparseCholesky)
+	}
+	n := c.sym.n
+	br, bc := b.Dims()
+	if br != n {
+		panic(ErrShape)
+	}
+
+	dst.reuseAsNonZeroed(n, bc)
+	bU, _ := untranspose(b)
+	var restore func()
+	if dst == bU {
+		dst, restore = dst.isolatedWorkspace(bU)
+		defer restore()
+	} else if rm, ok := bU.(RawMatrixer); ok {
+		dst.checkOverlap(rm.RawMatrix())
+	}
+
+	dst.Copy(b)
+	x := getFloats(n, false)
+	defer putFloats(x)
+	for j := 0; j < bc; j++ {
+		for i := range x {
+			x[i] = dst.mat.Data[i*dst.mat.Stride+j]
+		}
+		c.solve(x)
+		for i, v := range x {
+			dst.mat.Data[i*dst.mat.Stride+j] = v
+		}
+	}
+	if c.cond > ConditionTolerance {
+		return Condition(c.cond)
+	}
+	return nil
+}
+
+// SolveVecTo finds the vector x that solves A * x = b where A is represented
+// by the sparse Cholesky decomposition. The result is stored in-place into
+// dst.
+// If the condition number of A is large, a Condition error is returned. See
+// the documentation for Condition for more information.
+// SolveVecTo will panic if the receiver does not contain a factorization.
+func (c *SparseCholesky) SolveVecTo(dst *VecDense, b Vector) error {
+	if !c.valid() {
+		panic(badSparseCholesky)
+	}
+	n := c.sym.n
+	if br, bc := b.Dims(); br != n || bc != 1 {
+		panic(ErrShape)
+	}
+	if rv, ok := b.(RawVectorer); ok && dst != b {
+		dst.checkOverlap(rv.RawVector())
+	}
+
+	x := getFloats(n, false)
+	defer putFloats(x)
+	for i := range x {
+		x[i] = b.AtVec(i)
+	}
+	c.solve(x)
+	dst.reuseAsNonZeroed(n)
+	for i, v := range x {
+		dst.setVec(i, v)
+	}
+	if c.cond > ConditionTolerance {
+		return Condition(c.cond)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+// laplacian2D returns the n²×n² matrix of the 5-point finite difference
+// Laplacian on an n×n grid plus shift times the identity.
+func laplacian2D(n int, shift float64) *CSR {
+	m := NewCOO(n*n, n*n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			k := i*n + j
+			m.Append(k, k, 4+shift)
+			if i > 0 {
+				m.Append(k, k-n, -1)
+			}
+			if i < n-1 {
+				m.Append(k, k+n, -1)
+			}
+			if j > 0 {
+				m.Append(k, k-1, -1)
+			}
+			if j < n-1 {
+				m.Append(k, k+1, -1)
+			}
+		}
+	}
+	return m.ToCSR()
+}
+
+func TestSparseCholesky(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		name string
+		a    Matrix
+	}{
+		{"1×1", NewCSR(1, 1, []int{0, 1}, []int{0}, []float64{4})},
+		{"laplacian 3", laplacian2D(3, 0)},
+		{"laplacian 10", laplacian2D(10, 0.5)},
+		{"random", randSPDSparse(40, 0.1, rnd)},
+		{"dense", randSPDSparse(15, 1, rnd)},
+	} {
+		n, _ := test.a.Dims()
+		var chol SparseCholesky
+		if !chol.Factorize(test.a) {
+			t.Errorf("%s: unexpected factorization failure", test.name)
+			continue
+		}
+
+		// Check that P*A*Pᵀ = L*Lᵀ.
+		perm := chol.Permutation(nil)
+		pap := NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				pap.Set(i, j, test.a.At(perm[i], perm[j]))
+			}
+		}
+		var l CSC
+		chol.LTo(&l)
+		var got Dense
+		got.Mul(&l, l.T())
+		if !EqualApprox(&got, pap, 1e-12) {
+			t.Errorf("%s: P*A*Pᵀ != L*Lᵀ", test.name)
+		}
+		if l.NNZ() > chol.Symbolic().NNZ() {
+			t.Errorf("%s: more non-zeros than analyzed", test.name)
+		}
+
+		sym := NewSymDense(n, nil)
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				sym.SetSym(i, j, test.a.At(i, j))
+			}
+		}
+		var dchol Cholesky
+		if !dchol.Factorize(sym) {
+			t.Fatalf("%s: dense factorization failed", test.name)
+		}
+		if got, want := chol.LogDet(), dchol.LogDet(); math.Abs(got-want) > 1e-10*math.Max(1, math.Abs(want)) {
+			t.Errorf("%s: unexpected log determinant: got %v, want %v", test.name, got, want)
+		}
+		if cond, want := chol.Cond(), dchol.Cond(); cond > 10*want || cond < want/10 {
+			t.Errorf("%s: poor condition number estimate: got %v, want %v", test.name, cond, want)
+		}
+
+		b := NewDense(n, 2, nil)
+		for i := 0; i < n; i++ {
+			b.Set(i, 0, rnd.NormFloat64())
+			b.Set(i, 1, rnd.NormFloat64())
+		}
+		var x, want Dense
+		if err := chol.SolveTo(&x, b); err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		dchol.SolveTo(&want, b)
+		if !EqualApprox(&x, &want, 1e-10) {
+			t.Errorf("%s: unexpected solution", test.name)
+		}
+		var xv VecDense
+		if err := chol.SolveVecTo(&xv, b.ColView(0)); err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if !EqualApprox(&xv, want.ColView(0), 1e-10) {
+			t.Errorf("%s: unexpected vector solution", test.name)
+		}
+	}
+}
+
+func TestSparseCholeskySymbolic(t *testing.T) {
+	t.Parallel()
+	var sym SymbolicCholesky
+	sym.Analyze(laplacian2D(6, 0))
+
+	// Matrices with the same pattern reuse the analysis.
+	for _, shift := range []float64{0, 1, 10} {
+		a := laplacian2D(6, shift)
+		var chol, want SparseCholesky
+		if !chol.FactorizeSymbolic(a, &sym) {
+			t.Fatalf("shift=%v: unexpected factorization failure", shift)
+		}
+		if chol.Symbolic() != &sym {
+			t.Errorf("shift=%v: symbolic analysis not retained", shift)
+		}
+		want.Factorize(a)
+		if got, want := chol.LogDet(), want.LogDet(); math.Abs(got-want) > 1e-12*math.Abs(want) {
+			t.Errorf("shift=%v: unexpected log determinant: got %v, want %v", shift, got, want)
+		}
+	}
+
+	// A diagonal matrix has a pattern that is a subset of the analyzed one.
+	diag := NewCOO(36, 36)
+	for i := 0; i < 36; i++ {
+		diag.Append(i, i, float64(i+1))
+	}
+	var chol SparseCholesky
+	if !chol.FactorizeSymbolic(diag.ToCSC(), &sym) {
+		t.Fatalf("unexpected factorization failure for subset pattern")
+	}
+	b := NewVecDense(36, nil)
+	for i := 0; i < 36; i++ {
+		b.SetVec(i, float64(i+1))
+	}
+	var x VecDense
+	chol.SolveVecTo(&x, b)
+	for i := 0; i < 36; i++ {
+		if math.Abs(x.AtVec(i)-1) > 1e-14 {
+			t.Errorf("unexpected solution for subset pattern: %v", x.AtVec(i))
+			break
+		}
+	}
+
+	// A pattern that is not a subset panics.
+	full := NewDense(36, 36, nil)
+	for i := 0; i < 36; i++ {
+		for j := 0; j < 36; j++ {
+			full.Set(i, j, 1)
+		}
+		full.Set(i, i, 100)
+	}
+	if ok, _ := panics(func() { chol.FactorizeSymbolic(full, &sym) }); !ok {
+		t.Errorf("expected panic for pattern outside analysis")
+	}
+
+	// Matrices that are not positive definite fail.
+	if chol.Factorize(laplacian2D(4, -5)) {
+		t.Errorf("unexpected success for indefinite matrix")
+	}
+}
+
+// randSPDSparse returns a random sparse symmetric positive definite n×n
+// matrix.
+func randSPDSparse(n int, density float64, rnd *rand.Rand) *CSC {
+	m := NewCOO(n, n)
+	for i := 0; i < n; i++ {
+		m.Append(i, i, float64(n))
+		for j := i + 1; j < n; j++ {
+			if rnd.Float64() < density {
+				v := rnd.NormFloat64()
+				m.Append(i, j, v)
+				m.Append(j, i, v)
+			}
+		}
+	}
+	return m.ToCSC()
+}
@@ -0,0 +1,578 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+)
+
+const badSparseLU = "mat: invalid sparse LU factorization"
+
+// SparseLU is a type for creating and using the LU factorization of a sparse
+// matrix. The factors are stored in compressed sparse column format, so the
+// memory used is proportional to the number of non-zero elements of the
+// factors instead of the number of elements of the matrix.
+type SparseLU struct {
+	n    int
+	l    compressed // Strictly lower triangular part of L by columns.
+	u    compressed // Strictly upper triangular part of U by columns.
+	diag []float64  // Diagonal of U.
+	p    []int      // Row p[k] of A is row k of P*A*Q.
+	q    []int      // Column q[k] of A is column k of P*A*Q.
+	cond float64
+}
+
+// Factorize computes the LU factorization of the square matrix a and stores
+// the result. The LU decomposition will complete regardless of the
+// singularity of a.
+//
+// The factorization is
+//  P * A * Q = L * U
+// where P and Q are permutation matrices, L is unit lower triangular and U
+// is upper triangular. The columns are ordered by Q to reduce the fill-in of
+// the factors, using a minimum degree ordering of the sparsity pattern of
+// Aᵀ*A, and the rows are ordered by P with partial pivoting. The factors are
+// computed column by column with the left-looking algorithm of Gilbert and
+// Peierls, which takes time proportional to the number of floating point
+// operations.
+//
+// Factorize is most efficient if a is a CSC or CSR matrix. Other matrix
+// types are converted by examining all of their elements.
+func (lu *SparseLU) Factorize(a Matrix) {
+	r, c := a.Dims()
+	if r != c {
+		panic(ErrSquare)
+	}
+	n := r
+	rows := rowCompressed(a)
+	cols := rowCompressed(a.T())
+	q := minimumDegree(columnIntersectionPattern(rows))
+
+	l := compressed{major: n, minor: n, ptr: make([]int, n+1)}
+	u := compressed{major: n, minor: n, ptr: make([]int, n+1)}
+	diag := make([]float64, n)
+	perm := make([]int, n)
+	pinv := make([]int, n)
+	for i := range pinv {
+		pinv[i] = -1
+	}
+	x := make([]float64, n)
+	mark := make([]int, n)
+	for i := range mark {
+		mark[i] = -1
+	}
+	xi := make([]int, n)
+	stack := make([]int, n)
+	pstack := make([]int, n)
+	free := 0 // All rows before free are pivotal.
+	for k, j := range q {
+		// Find the rows of the non-zero elements of column k of L and U
+		// in topological order, and compute the column by solving
+		//  L x = A[:, j]
+		// with the known columns of L.
+		top := sparseReach(&l, cols, j, pinv, mark, k, xi, stack, pstack)
+		for _, i := range xi[top:] {
+			x[i] = 0
+		}
+		for p := cols.ptr[j]; p < cols.ptr[j+1]; p++ {
+			x[cols.ind[p]] = cols.data[p]
+		}
+		for _, i := range xi[top:] {
+			s := pinv[i]
+			if s < 0 {
+				continue
+			}
+			xs := x[i]
+			for p := l.ptr[s]; p < l.ptr[s+1]; p++ {
+				x[l.ind[p]] -= l.data[p] * xs
+			}
+		}
+
+		// Choose the pivot of largest magnitude among the rows that have
+		// not been pivotal.
+		ipiv := -1
+		amax := -1.0
+		for _, i := range xi[top:] {
+			if pinv[i] < 0 {
+				if v := math.Abs(x[i]); v > amax {
+					amax = v
+					ipiv = i
+				}
+			} else {
+				u.ind = append(u.ind, pinv[i])
+				u.data = append(u.data, x[i])
+			}
+		}
+		var pivot float64
+		if ipiv == -1 {
+			// The column is structurally singular, so any row that has not
+			// been pivotal can be used.
+			for pinv[free] >= 0 {
+				free++
+			}
+			ipiv = free
+		} else {
+			pivot = x[ipiv]
+		}
+		pinv[ipiv] = k
+		perm[k] = ipiv
+		diag[k] = pivot
+		if pivot != 0 {
+			for _, i := range xi[top:] {
+				if pinv[i] < 0 {
+					l.ind = append(l.ind, i)
+					l.data = append(l.data, x[i]/pivot)
+				}
+			}
+		}
+		l.ptr[k+1] = len(l.ind)
+		u.ptr[k+1] = len(u.ind)
+	}
+	// Number the rows of L in the pivot order, and sort the row indices of
+	// the columns.
+	for i, v := range l.ind {
+		l.ind[i] = pinv[v]
+	}
+
+	lu.n = n
+	lu.l = *l.transpose().transpose()
+	lu.u = *u.transpose().transpose()
+	lu.diag = diag
+	lu.p = perm
+	lu.q = q
+	lu.updateCond(rows)
+}
+
+// sparseReach computes the rows that are reachable from the rows of the
+// non-zero elements of column j of b in the graph of the partial factor l,
+// in which the pivotal row i has edges to the rows of column pinv[i] of l.
+// The reachable rows are stored in xi[top:] in topological order, and top is
+// returned. Visited rows are marked in mark with stamp. stack and pstack are
+// work space of length n.
+func sparseReach(l, b *compressed, j int, pinv, mark []int, stamp int, xi, stack, pstack []int) int {
+	top := len(xi)
+	for p := b.ptr[j]; p < b.ptr[j+1]; p++ {
+		start := b.ind[p]
+		if mark[start] == stamp {
+			continue
+		}
+		// Depth-first search from start.
+		head := 0
+		stack[0] = start
+		for head >= 0 {
+			i := stack[head]
+			s := pinv[i]
+			if mark[i] != stamp {
+				mark[i] = stamp
+				if s >= 0 {
+					pstack[head] = l.ptr[s]
+				}
+			}
+			done := true
+			if s >= 0 {
+				for q := pstack[head]; q < l.ptr[s+1]; q++ {
+					c := l.ind[q]
+					if mark[c] == stamp {
+						continue
+					}
+					pstack[head] = q + 1
+					head++
+					stack[head] = c
+					done = false
+					break
+				}
+			}
+			if done {
+				head--
+				top--
+				xi[top] = i
+			}
+		}
+	}
+	return top
+}
+
+// updateCond estimates the condition number of the factorized matrix in the
+// CondNorm norm, using the algorithm of Hager and Higham for the norm of the
+// inverse. rows is the factorized matrix with the row index as the major
+// index.
+func (lu *SparseLU) updateCond(rows *compressed) {
+	for _, v := range lu.diag {
+		if v == 0 {
+			lu.cond = math.Inf(1)
+			return
+		}
+	}
+	var anorm float64
+	for i := 0; i < rows.major; i++ {
+		var sum float64
+		for _, v := range rows.data[rows.ptr[i]:rows.ptr[i+1]] {
+			sum += math.Abs(v)
+		}
+		anorm = math.Max(anorm, sum)
+	}
+	// The infinity norm of A⁻¹ is the 1-norm of A⁻ᵀ.
+	lu.cond = anorm * normEst1(lu.n, func(x []float64, trans bool) {
+		lu.solve(x, !trans)
+	})
+}
+
+// normEst1 returns an estimate of the 1-norm of the n×n matrix B, where
+// mulTo(x, false) overwrites x with B*x and mulTo(x, true) overwrites x with
+// Bᵀ*x. See
+//  Higham, N.J.: FORTRAN codes for estimating the one-norm of a real or
+//  complex matrix, with applications to condition estimation. ACM Trans.
+//  Math. Softw. 14(4) (1988), 381-396
+func normEst1(n int, mulTo func(x []float64, trans bool)) float64 {
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = 1 / float64(n)
+	}
+	y := make([]float64, n)
+	var est float64
+	jPrev := -1
+	for iter := 0; iter < 5; iter++ {
+		copy(y, x)
+		mulTo(y, false)
+		var ynorm float64
+		for _, v := range y {
+			ynorm += math.Abs(v)
+		}
+		if iter > 0 && ynorm <= est {
+			break
+		}
+		est = ynorm
+		for i, v := range y {
+			if v >= 0 {
+				x[i] = 1
+			} else {
+				x[i] = -1
+			}
+		}
+		mulTo(x, true)
+		j := 0
+		for i, v := range x {
+			if math.Abs(v) > math.Abs(x[j]) {
+				j = i
+			}
+		}
+		if j == jPrev {
+			break
+		}
+		jPrev = j
+		for i := range x {
+			x[i] = 0
+		}
+		x[j] = 1
+	}
+	// Alternative estimate that guards against cancellation.
+	for i := range y {
+		s := 1 + float64(i)/float64(max(n-1, 1))
+		if i%2 == 1 {
+			s = -s
+		}
+		y[i] = s
+	}
+	mulTo(y, false)
+	var alt float64
+	for _, v := range y {
+		alt += math.Abs(v)
+	}
+	alt *= 2 / (3 * float64(n))
+	return math.Max(est, alt)
+}
+
+// solve overwrites x with the solution of A*x = b if trans is false, or of
+// Aᵀ*x = b if trans is true, where b is the value of x on entry.
+func (lu *SparseLU) solve(x []float64, trans bool) {
+	n := lu.n
+	work := getFloats(n, false)
+	defer putFloats(work)
+	l, u := &lu.l, &lu.u
+	if !trans {
+		// L*U*y = P*b and x = Q*y.
+		for k, i := range lu.p {
+			work[k] = x[i]
+		}
+		for k := 0; k < n; k++ {
+			v := work[k]
+			if v == 0 {
+				continue
+			}
+			for p := l.ptr[k]; p < l.ptr[k+1]; p++ {
+				work[l.ind[p]] -= l.data[p] * v
+			}
+		}
+		for k := n - 1; k >= 0; k-- {
+			work[k] /= lu.diag[k]
+			v := work[k]
+			if v == 0 {
+				continue
+			}
+			for p := u.ptr[k]; p < u.ptr[k+1]; p++ {
+				work[u.ind[p]] -= u.data[p] * v
+			}
+		}
+		for k, j := range lu.q {
+			x[j] = work[k]
+		}
+		return
+	}
+	// Uᵀ*Lᵀ*w = Qᵀ*b and x = Pᵀ*w.
+	for k, j := range lu.q {
+		work[k] = x[j]
+	}
+	for k := 0; k < n; k++ {
+		v := work[k]
+		for p := u.ptr[k]; p < u.ptr[k+1]; p++ {
+			v -= u.data[p] * work[u.ind[p]]
+		}
+		work[k] = v / lu.diag[k]
+	}
+	for k := n - 1; k >= 0; k-- {
+		v := work[k]
+		for p := l.ptr[k]; p < l.ptr[k+1]; p++ {
+			v -= l.data[p] * work[l.ind[p]]
+		}
+		work[k] = v
+	}
+	for k, i := range lu.p {
+		x[i] = work[k]
+	}
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (lu *SparseLU) isValid() bool {
+	return lu.n > 0
+}
+
+// Cond returns an estimate of the condition number of the factorized matrix.
+// Cond will panic if the receiver does not contain a factorization.
+func (lu *SparseLU) Cond() float64 {
+	if !lu.isValid() {
+		panic(badSparseLU)
+	}
+	return lu.cond
+}
+
+// Reset resets the factorization so that it can be reused as the receiver of a
+// dimensionally restricted operation.
+func (lu *SparseLU) Reset() {
+	lu.n = 0
+	lu.l.reset()
+	lu.u.reset()
+	lu.diag = lu.diag[:0]
+	lu.p = lu.p[:0]
+	lu.q = lu.q[:0]
+}
+
+// Det returns the determinant of the matrix that has been factorized. In many
+// expressions, using LogDet will be more numerically stable.
+// Det will panic if the receiver does not contain a factorization.
+func (lu *SparseLU) Det() float64 {
+	det, sign := lu.LogDet()
+	return math.Exp(det) * sign
+}
+
+// LogDet returns the log of the determinant and the sign of the determinant
+// for the matrix that has been factorized. Numerical stability in product and
+// division expressions is generally improved by working in log space.
+// LogDet will panic if the receiver does not contain a factorization.
+func (lu *SparseLU) LogDet() (det float64, sign float64) {
+	if !lu.isValid() {
+		panic(badSparseLU)
+	}
+	sign = permutationSign(lu.p) * permutationSign(lu.q)
+	for _, v := range lu.diag {
+		if v < 0 {
+			sign *= -1
+		}
+		det += math.Log(math.Abs(v))
+	}
+	return det, sign
+}
+
+// permutationSign returns the sign of the permutation perm.
+func permutationSign(perm []int) float64 {
+	visited := make([]bool, len(perm))
+	sign := 1.0
+	for i := range perm {
+		if visited[i] {
+			continue
+		}
+		// A cycle of length m is the product of m-1 transpositions.
+		for j := perm[i]; j != i; j = perm[j] {
+			visited[j] = true
+			sign = -sign
+		}
+		visited[i] = true
+	}
+	return sign
+}
+
+// Permutation returns the row and column permutations of the factorization
+// P * A * Q = L * U. Row k of P * A * Q is row rows[k] of A, and column k is
+// column cols[k] of A. If rows or cols is nil, new memory will be allocated,
+// otherwise its length must be equal to the size of the factorized matrix.
+// Permutation will panic if the receiver does not contain a factorization.
+func (lu *SparseLU) Permutation(rows, cols []int) ([]int, []int) {
+	if !lu.isValid() {
+		panic(badSparseLU)
+	}
+	if rows == nil {
+		rows = make([]int, lu.n)
+	}
+	if cols == nil {
+		cols = make([]int, lu.n)
+	}
+	if len(rows) != lu.n || len(cols) != lu.n {
+		panic(badSliceLength)
+	}
+	copy(rows, lu.p)
+	copy(cols, lu.q)
+	return rows, cols
+}
+
+// LTo stores the unit lower triangular factor L of the factorization in dst,
+// overwriting the previous value of dst.
+// LTo will panic if the receiver does not contain a factorization.
+func (lu *SparseLU) LTo(dst *CSC) {
+	if !lu.isValid() {
+		panic(badSparseLU)
+	}
+	n := lu.n
+	c := compressed{
+		major: n,
+		minor: n,
+		ptr:   make([]int, n+1),
+		ind:   make([]int, 0, len(lu.l.ind)+n),
+		data:  make([]float64, 0, len(lu.l.ind)+n),
+	}
+	for k := 0; k < n; k++ {
+		c.ind = append(c.ind, k)
+		c.data = append(c.data, 1)
+		c.ind = append(c.ind, lu.l.ind[lu.l.ptr[k]:lu.l.ptr[k+1]]...)
+		c.data = append(c.data, lu.l.data[lu.l.ptr[k]:lu.l.ptr[k+1]]...)
+		c.ptr[k+1] = len(c.ind)
+	}
+	dst.mat = c
+}
+
+// UTo stores the upper triangular factor U of the factorization in dst,
+// overwriting the previous value of dst.
+// UTo will panic if the receiver does not contain a factorization.
+func (lu *SparseLU) UTo(dst *CSC) {
+	if !lu.isValid() {
+		panic(badSparseLU)
+	}
+	n := lu.n
+	c := compressed{
+		major: n,
+		minor: n,
+		ptr:   make([]int, n+1),
+		ind:   make([]int, 0, len(lu.u.ind)+n),
+		data:  make([]float64, 0, len(lu.u.ind)+n),
+	}
+	for k := 0; k < n; k++ {
+		c.ind = append(c.ind, lu.u.ind[lu.u.ptr[k]:lu.u.ptr[k+1]]...)
+		c.data = append(c.data, lu.u.data[lu.u.ptr[k]:lu.u.ptr[k+1]]...)
+		c.ind = append(c.ind, k)
+		c.data = append(c.data, lu.diag[k])
+		c.ptr[k+1] = len(c.ind)
+	}
+	dst.mat = c
+}
+
+// SolveTo solves a system of linear equations using the LU decomposition of a
+// sparse matrix. It computes
+//  A * X = B if trans == false
+//  Aᵀ * X = B if trans == true
+// In both cases, A is represented in LU factorized form, and the matrix X is
+// stored into dst.
+//
+// If A is singular or near-singular a Condition error is returned. See
+// the documentation for Condition for more information.
+// SolveTo will panic if the receiver does not contain a factorization.
+func (lu *SparseLU) SolveTo(dst *Dense, trans bool, b Matrix) error {
+	if !lu.isValid() {
+		panic(badSparseLU)
+	}
+	n := lu.n
+	br, bc := b.Dims()
+	if br != n {
+		panic(ErrShape)
+	}
+	if math.IsInf(lu.cond, 1) {
+		return Condition(math.Inf(1))
+	}
+
+	dst.reuseAsNonZeroed(n, bc)
+	bU, _ := untranspose(b)
+	var restore func()
+	if dst == bU {
+		dst, restore = dst.isolatedWorkspace(bU)
+		defer restore()
+	} else if rm, ok := bU.(RawMatrixer); ok {
+		dst.checkOverlap(rm.RawMatrix())
+	}
+
+	dst.Copy(b)
+	x := getFloats(n, false)
+	defer putFloats(x)
+	for j := 0; j < bc; j++ {
+		for i := range x {
+			x[i] = dst.mat.Data[i*dst.mat.Stride+j]
+		}
+		lu.solve(x, trans)
+		for i, v := range x {
+			dst.mat.Data[i*dst.mat.Stride+j] = v
+		}
+	}
+	if lu.cond > ConditionTolerance {
+		return Condition(lu.cond)
+	}
+	return nil
+}
+
+// SolveVecTo solves a system of linear equations using the LU decomposition of
+// a sparse matrix. It computes
+//  A * x = b if trans == false
+//  Aᵀ * x = b if trans == true
+// In both cases, A is represented in LU factorized form, and the vector x is
+// stored into dst.
+//
+// If A is singular or near-singular a Condition error is returned. See
+// the documentation for Condition for more information.
+// SolveVecTo will panic if the receiver does not contain a factorization.
+func (lu *SparseLU) SolveVecTo(dst *VecDense, trans bool, b Vector) error {
+	if !lu.isValid() {
+		panic(badSparseLU)
+	}
+	n := lu.n
+	if br, bc := b.Dims(); br != n || bc != 1 {
+		panic(ErrShape)
+	}
+	if rv, ok := b.(RawVectorer); ok && dst != b {
+		dst.checkOverlap(rv.RawVector())
+	}
+	if math.IsInf(lu.cond, 1) {
+		return Condition(math.Inf(1))
+	}
+
+	x := getFloats(n, false)
+	defer putFloats(x)
+	for i := range x {
+		x[i] = b.AtVec(i)
+	}
+	lu.solve(x, trans)
+	dst.reuseAsNonZeroed(n)
+	for i, v := range x {
+		dst.setVec(i, v)
+	}
+	if lu.cond > ConditionTolerance {
+		return Condition(lu.cond)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestSparseLU(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 5, 10, 30, 100} {
+		for _, density := range []float64{0.05, 0.2, 1} {
+			// A random sparse matrix with a random permutation of a
+			// non-zero diagonal, so that partial pivoting is needed.
+			d := randSparse(n, n, density, rnd)
+			perm := rnd.Perm(n)
+			for i, j := range perm {
+				d.Set(i, j, 1+rnd.Float64())
+			}
+			var a CSC
+			a.CloneFrom(d)
+
+			var lu SparseLU
+			lu.Factorize(&a)
+
+			// Check that P*A*Q = L*U.
+			p, q := lu.Permutation(nil, nil)
+			paq := NewDense(n, n, nil)
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					paq.Set(i, j, d.At(p[i], q[j]))
+				}
+			}
+			var l, u CSC
+			lu.LTo(&l)
+			lu.UTo(&u)
+			for i := 0; i < n; i++ {
+				if l.At(i, i) != 1 {
+					t.Errorf("n=%d: L is not unit diagonal", n)
+					break
+				}
+				for j := i + 1; j < n; j++ {
+					if l.At(i, j) != 0 || u.At(j, i) != 0 {
+						t.Errorf("n=%d: factors are not triangular", n)
+					}
+				}
+			}
+			var got Dense
+			got.Mul(&l, &u)
+			if !EqualApprox(&got, paq, 1e-12) {
+				t.Errorf("n=%d, density=%v: P*A*Q != L*U", n, density)
+			}
+
+			var dlu LU
+			dlu.Factorize(d)
+			det, sign := lu.LogDet()
+			wantDet, wantSign := dlu.LogDet()
+			if sign != wantSign || math.Abs(det-wantDet) > 1e-10*math.Max(1, math.Abs(wantDet)) {
+				t.Errorf("n=%d, density=%v: unexpected log determinant: got %v,%v, want %v,%v", n, density, det, sign, wantDet, wantSign)
+			}
+			if cond, want := lu.Cond(), dlu.Cond(); cond > 10*want || cond < want/10 {
+				t.Errorf("n=%d, density=%v: poor condition number estimate: got %v, want %v", n, density, cond, want)
+			}
+
+			for _, trans := range []bool{false, true} {
+				b := NewDense(n, 3, nil)
+				for i := 0; i < n; i++ {
+					for j := 0; j < 3; j++ {
+						b.Set(i, j, rnd.NormFloat64())
+					}
+				}
+				var x, want Dense
+				if err := lu.SolveTo(&x, trans, b); err != nil {
+					t.Fatalf("n=%d: unexpected error: %v", n, err)
+				}
+				dlu.SolveTo(&want, trans, b)
+				if !EqualApprox(&x, &want, 1e-10) {
+					t.Errorf("n=%d, density=%v, trans=%t: unexpected solution", n, density, trans)
+				}
+
+				bv := b.ColView(1)
+				var xv VecDense
+				if err := lu.SolveVecTo(&xv, trans, bv); err != nil {
+					t.Fatalf("n=%d: unexpected error: %v", n, err)
+				}
+				if !EqualApprox(&xv, want.ColView(1), 1e-10) {
+					t.Errorf("n=%d, density=%v, trans=%t: unexpected vector solution", n, density, trans)
+				}
+				// The solution may overwrite the right-hand side.
+				xv.CloneFromVec(bv)
+				if err := lu.SolveVecTo(&xv, trans, &xv); err != nil {
+					t.Fatalf("n=%d: unexpected error: %v", n, err)
+				}
+				if !EqualApprox(&xv, want.ColView(1), 1e-10) {
+					t.Errorf("n=%d, density=%v, trans=%t: unexpected in-place solution", n, density, trans)
+				}
+			}
+		}
+	}
+
+	// Singular matrices.
+	for _, d := range []*Dense{
+		NewDense(3, 3, []float64{
+			1, 0, 2,
+			0, 0, 0,
+			3, 0, 4,
+		}),
+		NewDense(3, 3, []float64{
+			1, 2, 3,
+			2, 4, 6,
+			0, 1, 1,
+		}),
+	} {
+		var lu SparseLU
+		lu.Factorize(d)
+		if lu.Det() != 0 {
+			t.Errorf("unexpected determinant of singular matrix: %v", lu.Det())
+		}
+		var x Dense
+		err := lu.SolveTo(&x, false, NewDense(3, 1, []float64{1, 2, 3}))
+		if _, ok := err.(Condition); !ok {
+			t.Errorf("expected Condition error for singular matrix, got %v", err)
+		}
+	}
+}
+
+func TestMinimumDegree(t *testing.T) {
+	t.Parallel()
+	// An arrow matrix with a dense first row and column has no fill-in if the
+	// dense node is eliminated last.
+	n := 10
+	m := NewCOO(n, n)
+	for i := 0; i < n; i++ {
+		m.Append(i, i, 1)
+		if i > 0 {
+			m.Append(0, i, 1)
+			m.Append(i, 0, 1)
+		}
+	}
+	a := m.ToCSR()
+	order := minimumDegree(symmetricPattern(&a.mat))
+	if order[n-1] != 0 && order[n-2] != 0 {
+		t.Errorf("dense node eliminated early: order %v", order)
+	}
+	seen := make([]bool, n)
+	for _, v := range order {
+		if seen[v] {
+			t.Fatalf("order is not a permutation: %v", order)
+		}
+		seen[v] = true
+	}
+}
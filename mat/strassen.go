@@ -0,0 +1,207 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"sync/atomic"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+)
+
+// defaultStrassenThreshold is the default crossover dimension for Strassen
+// multiplication in Dense.Mul. Strassen multiplication is disabled by default
+// so that the accuracy of Mul does not change unless it is requested.
+const defaultStrassenThreshold = 0
+
+// strassenThreshold is the crossover dimension for Strassen multiplication in
+// Dense.Mul. It is accessed atomically.
+var strassenThreshold int64 = defaultStrassenThreshold
+
+// SetStrassenThreshold sets the crossover dimension for the use of Strassen's
+// algorithm by Dense.Mul to n and returns the previous value. When a product of
+// two *Dense matrices has all its dimensions at least n, Mul splits it into
+// seven products of half the size, instead of the eight used by conventional
+// multiplication, recursively until a dimension falls below n, when the BLAS
+// Gemm routine is used.
+//
+// Strassen's algorithm is disabled by default. Calling SetStrassenThreshold with
+// a positive n enables it; a threshold in the low thousands, such as 2048, is
+// a reasonable choice on most hardware.
+//
+// Strassen's algorithm needs O(n^log2(7)) ≈ O(n^2.81) operations, but its
+// rounding error is bounded only in norm rather than element-wise, and the bound
+// grows with the depth of the recursion. Small elements of a product of
+// matrices with widely varying element magnitudes may therefore be computed
+// less accurately. If n is zero or less, Strassen's algorithm is disabled.
+//
+// SetStrassenThreshold is safe for concurrent use, and the threshold applies to
+// subsequent calls to Mul.
+func SetStrassenThreshold(n int) (prev int) {
+	if n < 0 {
+		n = 0
+	}
+	return int(atomic.SwapInt64(&strassenThreshold, int64(n)))
+}
+
+// useStrassen returns the Strassen crossover dimension if the product of an m×k
+// and a k×n matrix should use Strassen's algorithm, and zero otherwise.
+func useStrassen(m, n, k int) int {
+	t := int(atomic.LoadInt64(&strassenThreshold))
+	if t <= 0 || m < t || n < t || k < t {
+		return 0
+	}
+	return t
+}
+
+// strassen computes C = op(A)*op(B) with Strassen's algorithm, recursing while
+// all the dimensions of the product are at least threshold. C must not overlap
+// A or B.
+func strassen(tA, tB blas.Transpose, a, b, c blas64.General, threshold int) {
+	m, n := c.Rows, c.Cols
+	k := a.Cols
+	if tA != blas.NoTrans {
+		k = a.Rows
+	}
+	if m < threshold || n < threshold || k < threshold || m < 2 || n < 2 || k < 2 {
+		blas64.Gemm(tA, tB, 1, a, b, 0, c)
+		return
+	}
+
+	// The recursion is applied to the leading block with even dimensions and
+	// the trailing odd rows and columns are added by dynamic peeling.
+	m2, n2, k2 := m&^1, n&^1, k&^1
+	hm, hn, hk := m2/2, n2/2, k2/2
+
+	a11 := opView(a, tA, 0, 0, hm, hk)
+	a12 := opView(a, tA, 0, hk, hm, hk)
+	a21 := opView(a, tA, hm, 0, hm, hk)
+	a22 := opView(a, tA, hm, hk, hm, hk)
+	b11 := opView(b, tB, 0, 0, hk, hn)
+	b12 := opView(b, tB, 0, hn, hk, hn)
+	b21 := opView(b, tB, hk, 0, hk, hn)
+	b22 := opView(b, tB, hk, hn, hk, hn)
+	c11 := generalView(c, 0, 0, hm, hn)
+	c12 := generalView(c, 0, hn, hm, hn)
+	c21 := generalView(c, hm, 0, hm, hn)
+	c22 := generalView(c, hm, hn, hm, hn)
+
+	ws := getWorkspace(hm, hk, false)
+	defer putWorkspace(ws)
+	wt := getWorkspace(hk, hn, false)
+	defer putWorkspace(wt)
+	wp := getWorkspace(hm, hn, false)
+	defer putWorkspace(wp)
+	s, t, p := ws.mat, wt.mat, wp.mat
+	const nt = blas.NoTrans
+
+	// M1 = (A11 + A22)*(B11 + B22) contributes to C11 and C22.
+	addOp(s, a11, tA, a22, tA, 1)
+	addOp(t, b11, tB, b22, tB, 1)
+	strassen(nt, nt, s, t, p, threshold)
+	axpyGeneral(c11, 0, 1, p)
+	axpyGeneral(c22, 0, 1, p)
+	// M2 = (A21 + A22)*B11 contributes to C21 and C22.
+	addOp(s, a21, tA, a22, tA, 1)
+	strassen(nt, tB, s, b11, p, threshold)
+	axpyGeneral(c21, 0, 1, p)
+	axpyGeneral(c22, 1, -1, p)
+	// M3 = A11*(B12 - B22) contributes to C12 and C22.
+	addOp(t, b12, tB, b22, tB, -1)
+	strassen(tA, nt, a11, t, p, threshold)
+	axpyGeneral(c12, 0, 1, p)
+	axpyGeneral(c22, 1, 1, p)
+	// M4 = A22*(B21 - B11) contributes to C11 and C21.
+	addOp(t, b21, tB, b11, tB, -1)
+	strassen(tA, nt, a22, t, p, threshold)
+	axpyGeneral(c11, 1, 1, p)
+	axpyGeneral(c21, 1, 1, p)
+	// M5 = (A11 + A12)*B22 contributes to C11 and C12.
+	addOp(s, a11, tA, a12, tA, 1)
+	strassen(nt, tB, s, b22, p, threshold)
+	axpyGeneral(c11, 1, -1, p)
+	axpyGeneral(c12, 1, 1, p)
+	// M6 = (A21 - A11)*(B11 + B12) contributes to C22.
+	addOp(s, a21, tA, a11, tA, -1)
+	addOp(t, b11, tB, b12, tB, 1)
+	strassen(nt, nt, s, t, p, threshold)
+	axpyGeneral(c22, 1, 1, p)
+	// M7 = (A12 - A22)*(B21 + B22) contributes to C11.
+	addOp(s, a12, tA, a22, tA, -1)
+	addOp(t, b21, tB, b22, tB, 1)
+	strassen(nt, nt, s, t, p, threshold)
+	axpyGeneral(c11, 1, 1, p)
+
+	if k2 < k {
+		// C[:m2,:n2] += op(A)[:m2,k2:] * op(B)[k2:,:n2]
+		blas64.Gemm(tA, tB, 1, opView(a, tA, 0, k2, m2, k-k2), opView(b, tB, k2, 0, k-k2, n2), 1, generalView(c, 0, 0, m2, n2))
+	}
+	if n2 < n {
+		// C[:,n2:] = op(A) * op(B)[:,n2:]
+		blas64.Gemm(tA, tB, 1, a, opView(b, tB, 0, n2, k, n-n2), 0, generalView(c, 0, n2, m, n-n2))
+	}
+	if m2 < m {
+		// C[m2:,:n2] = op(A)[m2:,:] * op(B)[:,:n2]
+		blas64.Gemm(tA, tB, 1, opView(a, tA, m2, 0, m-m2, k), opView(b, tB, 0, 0, k, n2), 0, generalView(c, m2, 0, m-m2, n2))
+	}
+}
+
+// generalView returns the r×c submatrix of g starting at row i and column j.
+// r and c must be positive.
+func generalView(g blas64.General, i, j, r, c int) blas64.General {
+	return blas64.General{
+		Rows:   r,
+		Cols:   c,
+		Stride: g.Stride,
+		Data:   g.Data[i*g.Stride+j : (i+r-1)*g.Stride+j+c],
+	}
+}
+
+// opView returns the matrix holding the r×c submatrix of op(g) starting at row
+// i and column j, which is to be used with the transpose flag t.
+func opView(g blas64.General, t blas.Transpose, i, j, r, c int) blas64.General {
+	if t == blas.NoTrans {
+		return generalView(g, i, j, r, c)
+	}
+	return generalView(g, j, i, c, r)
+}
+
+// addOp computes dst = op(x) + alpha*op(y), where op is determined by tx and ty.
+func addOp(dst, x blas64.General, tx blas.Transpose, y blas64.General, ty blas.Transpose, alpha float64) {
+	for i := 0; i < dst.Rows; i++ {
+		row := dst.Data[i*dst.Stride : i*dst.Stride+dst.Cols]
+		for j := range row {
+			var xv, yv float64
+			if tx == blas.NoTrans {
+				xv = x.Data[i*x.Stride+j]
+			} else {
+				xv = x.Data[j*x.Stride+i]
+			}
+			if ty == blas.NoTrans {
+				yv = y.Data[i*y.Stride+j]
+			} else {
+				yv = y.Data[j*y.Stride+i]
+			}
+			row[j] = xv + alpha*yv
+		}
+	}
+}
+
+// axpyGeneral computes dst = beta*dst + alpha*x, where beta is zero or one.
+func axpyGeneral(dst blas64.General, beta, alpha float64, x blas64.General) {
+	for i := 0; i < dst.Rows; i++ {
+		row := dst.Data[i*dst.Stride : i*dst.Stride+dst.Cols]
+		xrow := x.Data[i*x.Stride : i*x.Stride+x.Cols]
+		if beta == 0 {
+			for j, v := range xrow {
+				row[j] = alpha * v
+			}
+			continue
+		}
+		for j, v := range xrow {
+			row[j] += alpha * v
+		}
+	}
+}
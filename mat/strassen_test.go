@@ -0,0 +1,115 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/blas/blas64"
+)
+
+func TestStrassen(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, k   int
+		threshold int
+	}{
+		{m: 1, n: 1, k: 1, threshold: 1},
+		{m: 2, n: 2, k: 2, threshold: 1},
+		{m: 8, n: 8, k: 8, threshold: 2},
+		{m: 17, n: 17, k: 17, threshold: 4},
+		{m: 16, n: 23, k: 9, threshold: 4},
+		{m: 31, n: 12, k: 20, threshold: 3},
+		{m: 40, n: 40, k: 40, threshold: 50},
+	} {
+		for _, tA := range []blas.Transpose{blas.NoTrans, blas.Trans} {
+			for _, tB := range []blas.Transpose{blas.NoTrans, blas.Trans} {
+				name := fmt.Sprintf("m=%d n=%d k=%d threshold=%d tA=%c tB=%c", test.m, test.n, test.k, test.threshold, tA, tB)
+				ar, ac := test.m, test.k
+				if tA == blas.Trans {
+					ar, ac = ac, ar
+				}
+				br, bc := test.k, test.n
+				if tB == blas.Trans {
+					br, bc = bc, br
+				}
+				// Use padded strides to check the handling of submatrices.
+				a := randNormal(ar, ac+3, rnd).Slice(0, ar, 0, ac).(*Dense)
+				b := randNormal(br, bc+1, rnd).Slice(0, br, 0, bc).(*Dense)
+				aCopy := DenseCopyOf(a)
+				bCopy := DenseCopyOf(b)
+
+				want := NewDense(test.m, test.n, nil)
+				blas64.Gemm(tA, tB, 1, a.mat, b.mat, 0, want.mat)
+				got := NewDense(test.m, test.n+2, nil)
+				for i := range got.mat.Data {
+					got.mat.Data[i] = 1000
+				}
+				gotView := got.Slice(0, test.m, 0, test.n).(*Dense)
+				strassen(tA, tB, a.mat, b.mat, gotView.mat, test.threshold)
+
+				if !EqualApprox(gotView, want, 1e-12) {
+					t.Errorf("%s: unexpected result:\ngot:\n%v\nwant:\n%v", name, Formatted(gotView), Formatted(want))
+				}
+				for i := 0; i < test.m; i++ {
+					for j := test.n; j < test.n+2; j++ {
+						if got.At(i, j) != 1000 {
+							t.Errorf("%s: element outside result modified", name)
+						}
+					}
+				}
+				if !Equal(a, aCopy) || !Equal(b, bCopy) {
+					t.Errorf("%s: unexpected modification of inputs", name)
+				}
+			}
+		}
+	}
+}
+
+// TestStrassenMul is not run in parallel since it changes the package-level
+// Strassen threshold.
+func TestStrassenMul(t *testing.T) {
+	if useStrassen(4096, 4096, 4096) != 0 {
+		t.Errorf("Strassen multiplication enabled by default")
+	}
+	prev := SetStrassenThreshold(8)
+	defer SetStrassenThreshold(prev)
+	if prev != defaultStrassenThreshold {
+		t.Errorf("unexpected default threshold: got %d want %d", prev, defaultStrassenThreshold)
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	a := randNormal(37, 20, rnd)
+	b := randNormal(37, 45, rnd)
+	SetStrassenThreshold(0)
+	var want Dense
+	want.Mul(a.T(), b)
+	if useStrassen(100, 100, 100) != 0 {
+		t.Errorf("Strassen multiplication not disabled")
+	}
+
+	SetStrassenThreshold(8)
+	var got Dense
+	got.Mul(a.T(), b)
+	if !EqualApprox(&got, &want, 1e-12) {
+		t.Errorf("unexpected result from Mul:\ngot:\n%v\nwant:\n%v", Formatted(&got), Formatted(&want))
+	}
+
+	// Products aliasing the receiver are computed in a workspace.
+	c := randNormal(30, 30, rnd)
+	SetStrassenThreshold(0)
+	want.Reset()
+	want.Mul(c, c)
+	SetStrassenThreshold(8)
+	c.Mul(c, c)
+	if !EqualApprox(c, &want, 1e-12) {
+		t.Errorf("unexpected result from aliased Mul")
+	}
+}
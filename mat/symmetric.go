@@ -588,6 +588,11 @@ func (s *SymDense) Trace() float64 {
 	return v
 }
 
+// MulVecTo computes S⋅x storing the result into dst.
+func (s *SymDense) MulVecTo(dst *VecDense, _ bool, x Vector) {
+	dst.MulVec(s, x)
+}
+
 // GrowSym returns the receiver expanded by n rows and n columns. If the
 // dimensions of the expanded matrix are outside the capacity of the receiver
 // a new allocation is made, otherwise not. Note that the receiver itself is
@@ -637,6 +642,79 @@ func (s *SymDense) GrowSym(n int) Symmetric {
 // PowPSD returns an error if the matrix is not not positive symmetric definite
 // or the Eigen decomposition is not successful.
 func (s *SymDense) PowPSD(a Symmetric, pow float64) error {
+	return s.applyEigen(a, func(values []float64) error {
+		for i, v := range values {
+			if v <= 0 {
+				return ErrNotPSD
+			}
+			values[i] = math.Pow(v, pow)
+		}
+		return nil
+	})
+}
+
+// ExpSym computes the matrix exponential e^a of the symmetric matrix a,
+// placing the result in the receiver.
+//
+// ExpSym returns an error if the Eigen decomposition is not successful.
+func (s *SymDense) ExpSym(a Symmetric) error {
+	return s.applyEigen(a, func(values []float64) error {
+		for i, v := range values {
+			values[i] = math.Exp(v)
+		}
+		return nil
+	})
+}
+
+// LogPSD computes the principal matrix logarithm of the positive symmetric
+// definite matrix a, placing the result in the receiver.
+//
+// LogPSD returns an error if the matrix is not positive symmetric definite or
+// the Eigen decomposition is not successful.
+func (s *SymDense) LogPSD(a Symmetric) error {
+	return s.applyEigen(a, func(values []float64) error {
+		for i, v := range values {
+			if v <= 0 {
+				return ErrNotPSD
+			}
+			values[i] = math.Log(v)
+		}
+		return nil
+	})
+}
+
+// SqrtPSD computes the principal square root of the positive semidefinite
+// symmetric matrix a, the unique positive semidefinite matrix X such that
+// X*X = A, placing the result in the receiver.
+//
+// SqrtPSD returns an error if the matrix has a negative eigenvalue, or the
+// Eigen decomposition is not successful. Eigenvalues that are negative only
+// due to rounding are treated as zero.
+func (s *SymDense) SqrtPSD(a Symmetric) error {
+	return s.applyEigen(a, func(values []float64) error {
+		var big float64
+		for _, v := range values {
+			big = math.Max(big, math.Abs(v))
+		}
+		tol := float64(len(values)) * dlamchP * big
+		for i, v := range values {
+			if v < 0 {
+				if v < -tol {
+					return ErrNotPSD
+				}
+				v = 0
+			}
+			values[i] = math.Sqrt(v)
+		}
+		return nil
+	})
+}
+
+// applyEigen computes f(A) = U*f(Λ)*Uᵀ from the eigendecomposition
+// A = U*Λ*Uᵀ of the symmetric matrix a, placing the result in the receiver.
+// The function f is applied in place to the eigenvalues, and an error returned
+// by f is returned by applyEigen.
+func (s *SymDense) applyEigen(a Symmetric, f func(values []float64) error) error {
 	dim := a.Symmetric()
 	s.reuseAsNonZeroed(dim)
 
@@ -646,11 +724,9 @@ func (s *SymDense) PowPSD(a Symmetric, pow float64) error {
 		return ErrFailedEigen
 	}
 	values := eigen.Values(nil)
-	for i, v := range values {
-		if v <= 0 {
-			return ErrNotPSD
-		}
-		values[i] = math.Pow(v, pow)
+	err := f(values)
+	if err != nil {
+		return err
 	}
 	var u Dense
 	eigen.VectorsTo(&u)
@@ -6,6 +6,7 @@ package mat
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"reflect"
 	"testing"
@@ -762,6 +763,80 @@ func TestPowPSD(t *testing.T) {
 	}
 }
 
+func TestSymFunctions(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 5, 10} {
+		a := NewDense(n, n, nil)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				a.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		var sym SymDense
+		sym.SymOuterK(1, a)
+		psd := NewSymDense(n, nil)
+		psd.CopySym(&sym)
+		for i := 0; i < n; i++ {
+			sym.SetSym(i, i, sym.At(i, i)-float64(n))
+			psd.SetSym(i, i, psd.At(i, i)+1)
+		}
+
+		var exp SymDense
+		if err := exp.ExpSym(&sym); err != nil {
+			t.Fatalf("n=%d: unexpected ExpSym error: %v", n, err)
+		}
+		var want Dense
+		want.Exp(&sym)
+		if !EqualApprox(&exp, &want, 1e-10) {
+			t.Errorf("n=%d: ExpSym does not match Exp", n)
+		}
+
+		var log, explog SymDense
+		if err := log.LogPSD(psd); err != nil {
+			t.Fatalf("n=%d: unexpected LogPSD error: %v", n, err)
+		}
+		if err := explog.ExpSym(&log); err != nil {
+			t.Fatalf("n=%d: unexpected ExpSym error: %v", n, err)
+		}
+		if !EqualApprox(&explog, psd, 1e-10) {
+			t.Errorf("n=%d: ExpSym(LogPSD(A)) != A", n)
+		}
+
+		var sqrt, pow SymDense
+		if err := sqrt.SqrtPSD(psd); err != nil {
+			t.Fatalf("n=%d: unexpected SqrtPSD error: %v", n, err)
+		}
+		if err := pow.PowPSD(psd, 0.5); err != nil {
+			t.Fatalf("n=%d: unexpected PowPSD error: %v", n, err)
+		}
+		if !EqualApprox(&sqrt, &pow, 1e-12) {
+			t.Errorf("n=%d: SqrtPSD does not match PowPSD", n)
+		}
+	}
+
+	// A singular positive semidefinite matrix has a square root but no
+	// logarithm.
+	a := NewSymDense(2, []float64{1, 1, 1, 1})
+	var s SymDense
+	if err := s.SqrtPSD(a); err != nil {
+		t.Errorf("unexpected SqrtPSD error for singular matrix: %v", err)
+	}
+	want := NewDense(2, 2, []float64{0.5, 0.5, 0.5, 0.5})
+	for i := range want.mat.Data {
+		want.mat.Data[i] *= math.Sqrt2
+	}
+	if !EqualApprox(&s, want, 1e-14) {
+		t.Errorf("unexpected square root of singular matrix: %v", Formatted(&s))
+	}
+	if err := s.LogPSD(a); err != ErrNotPSD {
+		t.Errorf("unexpected LogPSD error: got %v, want %v", err, ErrNotPSD)
+	}
+	if err := s.SqrtPSD(NewSymDense(2, []float64{1, 0, 0, -1})); err != ErrNotPSD {
+		t.Errorf("unexpected SqrtPSD error: got %v, want %v", err, ErrNotPSD)
+	}
+}
+
 func BenchmarkSymSum1000(b *testing.B) { symSumBench(b, 1000) }
 
 var symSumForBench float64
@@ -0,0 +1,206 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "math"
+
+var (
+	toeplitz *Toeplitz
+	_        Matrix = toeplitz
+)
+
+// Toeplitz represents an n×n Toeplitz matrix, a matrix that is constant along
+// each of its diagonals. Products with a vector are computed with the FFT in
+// O(n log n) time and linear systems are solved with the Levinson recursion
+// in O(n²) time.
+type Toeplitz struct {
+	// col and row are the first column and the first row of the
+	// matrix. col[0] is the diagonal element and row[0] is not used.
+	col, row []float64
+}
+
+// NewToeplitz creates a new n×n Toeplitz matrix with the first column col and
+// the first row row, so that the element [i,j] is col[i-j] if i >= j and
+// row[j-i] otherwise. col[0] holds the diagonal and must be equal to row[0].
+// If both slices are nil, new slices are allocated. Otherwise col and row
+// must have length n and are used as the backing data of the matrix.
+// NewToeplitz will panic if n is zero, the slice lengths are wrong, or the
+// diagonal elements of col and row differ.
+func NewToeplitz(n int, col, row []float64) *Toeplitz {
+	if n <= 0 {
+		if n == 0 {
+			panic(ErrZeroLength)
+		}
+		panic(ErrNegativeDimension)
+	}
+	if col == nil && row == nil {
+		col = make([]float64, n)
+		row = make([]float64, n)
+	}
+	if len(col) != n || len(row) != n {
+		panic(ErrShape)
+	}
+	if col[0] != row[0] {
+		panic("mat: Toeplitz diagonal mismatch")
+	}
+	return &Toeplitz{col: col, row: row}
+}
+
+// NewSymToeplitz creates a new n×n symmetric Toeplitz matrix with the first
+// column and row c, such as the covariance matrix of a stationary time series.
+// See NewToeplitz for the use of c.
+func NewSymToeplitz(n int, c []float64) *Toeplitz {
+	if c == nil && n > 0 {
+		c = make([]float64, n)
+	}
+	return NewToeplitz(n, c, c)
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (t *Toeplitz) Dims() (r, c int) {
+	return len(t.col), len(t.col)
+}
+
+// At returns the element at row i, column j.
+func (t *Toeplitz) At(i, j int) float64 {
+	n := len(t.col)
+	if uint(i) >= uint(n) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(n) {
+		panic(ErrColAccess)
+	}
+	if i >= j {
+		return t.col[i-j]
+	}
+	return t.row[j-i]
+}
+
+// T performs an implicit transpose by returning the receiver inside a
+// Transpose.
+func (t *Toeplitz) T() Matrix {
+	return Transpose{t}
+}
+
+// MulVecTo computes A⋅x or Aᵀ⋅x storing the result into dst. The product is
+// computed with the FFT by embedding A into a circulant matrix of at least
+// twice its size.
+func (t *Toeplitz) MulVecTo(dst *VecDense, trans bool, x Vector) {
+	n := len(t.col)
+	if x.Len() != n {
+		panic(ErrShape)
+	}
+	col, row := t.col, t.row
+	if trans {
+		col, row = row, col
+	}
+
+	// Use a power of two length for the fastest transforms.
+	l := 1
+	for l < 2*n-1 {
+		l <<= 1
+	}
+	c := getFloats(l, true)
+	defer putFloats(c)
+	copy(c, col)
+	for k := 1; k < n; k++ {
+		c[l-k] = row[k]
+	}
+	xs := getFloats(l, true)
+	defer putFloats(xs)
+	for i := 0; i < n; i++ {
+		xs[i] = x.AtVec(i)
+	}
+	y := getFloats(l, false)
+	defer putFloats(y)
+	circulantMul(y, c, xs, false)
+
+	dst.reuseAsNonZeroed(n)
+	for i, v := range y[:n] {
+		dst.setVec(i, v)
+	}
+}
+
+// SolveVecTo solves the Toeplitz system of linear equations
+//  A * x = b if trans == false
+//  Aᵀ * x = b if trans == true
+// and stores the result in dst. The system is solved with the Levinson
+// recursion in O(n²) time, which requires all leading principal submatrices
+// of A to be non-singular. The recursion is stable for symmetric positive
+// definite matrices, for which it reduces to the Levinson-Durbin algorithm,
+// but may be inaccurate for indefinite or ill-conditioned matrices, which are
+// better solved with an LU factorization.
+//
+// If a singular leading principal submatrix is encountered, SolveVecTo returns
+// a Condition error with an infinite condition number and the contents of dst
+// are undefined. The condition number of A is not otherwise estimated.
+func (t *Toeplitz) SolveVecTo(dst *VecDense, trans bool, b Vector) error {
+	n := len(t.col)
+	if br, bc := b.Dims(); br != n || bc != 1 {
+		panic(ErrShape)
+	}
+	col, row := t.col, t.row
+	if trans {
+		col, row = row, col
+	}
+
+	bv := getWorkspaceVec(n, false)
+	bv.CopyVec(b)
+	defer putWorkspaceVec(bv)
+	y := bv.mat.Data
+
+	// For the leading m×m submatrix T_m of A, the forward and backward
+	// vectors f and bw satisfy
+	//  T_m * f = e_0 and T_m * bw = e_{m-1},
+	// and x solves T_m * x = y[:m]. Each is extended to the (m+1)×(m+1)
+	// submatrix from the residuals of the vectors padded with a zero.
+	work := getFloats(3*n, false)
+	defer putFloats(work)
+	f := work[:n]
+	bw := work[n : 2*n]
+	x := work[2*n:]
+	if col[0] == 0 {
+		return Condition(math.Inf(1))
+	}
+	f[0] = 1 / col[0]
+	bw[0] = f[0]
+	x[0] = y[0] / col[0]
+	for m := 1; m < n; m++ {
+		var ef, eb, ex float64
+		for j := 0; j < m; j++ {
+			ef += col[m-j] * f[j]
+			eb += row[j+1] * bw[j]
+			ex += col[m-j] * x[j]
+		}
+		d := 1 - ef*eb
+		if d == 0 {
+			return Condition(math.Inf(1))
+		}
+		// Update f and bw in place from the last element down, so that
+		// the old values are read before they are overwritten.
+		for j := m; j >= 0; j-- {
+			var fj, bj float64
+			if j < m {
+				fj = f[j]
+			}
+			if j > 0 {
+				bj = bw[j-1]
+			}
+			f[j] = (fj - ef*bj) / d
+			bw[j] = (bj - eb*fj) / d
+		}
+		x[m] = 0
+		r := y[m] - ex
+		for j := 0; j <= m; j++ {
+			x[j] += r * bw[j]
+		}
+	}
+
+	dst.reuseAsNonZeroed(n)
+	for i, v := range x {
+		dst.setVec(i, v)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestToeplitz(t *testing.T) {
+	t.Parallel()
+	a := NewToeplitz(4, []float64{1, 2, 3, 4}, []float64{1, 5, 6, 7})
+	want := NewDense(4, 4, []float64{
+		1, 5, 6, 7,
+		2, 1, 5, 6,
+		3, 2, 1, 5,
+		4, 3, 2, 1,
+	})
+	if !Equal(a, want) {
+		t.Errorf("unexpected Toeplitz matrix:\ngot:\n%v\nwant:\n%v", Formatted(a), Formatted(want))
+	}
+	s := NewSymToeplitz(3, []float64{3, 2, 1})
+	if !Equal(s, NewDense(3, 3, []float64{3, 2, 1, 2, 3, 2, 1, 2, 3})) {
+		t.Errorf("unexpected symmetric Toeplitz matrix:\n%v", Formatted(s))
+	}
+
+	for _, fn := range []func(){
+		func() { NewToeplitz(0, nil, nil) },
+		func() { NewToeplitz(3, []float64{1, 2, 3}, []float64{1, 2}) },
+		func() { NewToeplitz(2, []float64{1, 2}, []float64{3, 4}) },
+	} {
+		if ok, _ := panics(fn); !ok {
+			t.Errorf("expected panic")
+		}
+	}
+}
+
+func TestToeplitzMulVecSolve(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 7, 16, 100} {
+		for _, sym := range []bool{false, true} {
+			var a *Toeplitz
+			if sym {
+				// The covariance matrix of an AR(1) process.
+				c := make([]float64, n)
+				for k := range c {
+					c[k] = math.Pow(0.9, float64(k))
+				}
+				a = NewSymToeplitz(n, c)
+			} else {
+				// A random diagonally dominant matrix.
+				col := make([]float64, n)
+				row := make([]float64, n)
+				for k := 1; k < n; k++ {
+					col[k] = rnd.NormFloat64() / float64(k*k)
+					row[k] = rnd.NormFloat64() / float64(k*k)
+				}
+				col[0] = 4
+				row[0] = 4
+				a = NewToeplitz(n, col, row)
+			}
+			ad := DenseCopyOf(a)
+
+			for _, trans := range []bool{false, true} {
+				var am Matrix = ad
+				if trans {
+					am = ad.T()
+				}
+				x := randNormal(n, 1, rnd).ColView(0)
+				var got, want VecDense
+				a.MulVecTo(&got, trans, x)
+				want.MulVec(am, x)
+				if !EqualApprox(&got, &want, 1e-12) {
+					t.Errorf("n=%d,sym=%t,trans=%t: unexpected product", n, sym, trans)
+				}
+
+				b := randNormal(n, 1, rnd).ColView(0)
+				err := a.SolveVecTo(&got, trans, b)
+				if err != nil {
+					t.Fatalf("n=%d,sym=%t,trans=%t: unexpected error: %v", n, sym, trans, err)
+				}
+				err = want.SolveVec(am, b)
+				if err != nil {
+					t.Fatalf("n=%d,sym=%t,trans=%t: unexpected error from dense solve: %v", n, sym, trans, err)
+				}
+				if !EqualApprox(&got, &want, 1e-10) {
+					t.Errorf("n=%d,sym=%t,trans=%t: unexpected solution", n, sym, trans)
+				}
+			}
+		}
+	}
+
+	// The leading 1×1 submatrix is singular.
+	a := NewSymToeplitz(2, []float64{0, 1})
+	var x VecDense
+	err := a.SolveVecTo(&x, false, NewVecDense(2, []float64{1, 1}))
+	if _, ok := err.(Condition); !ok {
+		t.Errorf("expected Condition error for singular leading submatrix, got %v", err)
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import "math"
+
+var (
+	tridiag *Tridiag
+	_       Matrix = tridiag
+)
+
+// Tridiag represents an n×n tridiagonal matrix, optionally with non-zero
+// corner elements for the cyclic tridiagonal matrices of periodic problems.
+// Tridiagonal systems are solved in O(n) time.
+type Tridiag struct {
+	dl, d, du []float64
+
+	// cyclic is whether the matrix has the corner elements lower at
+	// [n-1,0] and upper at [0,n-1].
+	cyclic       bool
+	lower, upper float64
+}
+
+// NewTridiag creates a new n×n tridiagonal matrix with the sub-diagonal dl,
+// the diagonal d and the super-diagonal du, so that the element [i+1,i] is
+// dl[i], the element [i,i] is d[i] and the element [i,i+1] is du[i]. d must
+// have length n, and dl and du must have length n-1. If all the slices are
+// nil, new slices are allocated. Otherwise the slices are used as the backing
+// data of the matrix, and changes to the elements of the returned Tridiag
+// will be reflected in the slices and vice versa. NewTridiag will panic if
+// n is zero or the slice lengths are wrong.
+func NewTridiag(n int, dl, d, du []float64) *Tridiag {
+	if n <= 0 {
+		if n == 0 {
+			panic(ErrZeroLength)
+		}
+		panic(ErrNegativeDimension)
+	}
+	if dl == nil && d == nil && du == nil {
+		dl = make([]float64, n-1)
+		d = make([]float64, n)
+		du = make([]float64, n-1)
+	}
+	if len(dl) != n-1 || len(d) != n || len(du) != n-1 {
+		panic(ErrShape)
+	}
+	return &Tridiag{dl: dl, d: d, du: du}
+}
+
+// NewCyclicTridiag creates a new n×n cyclic tridiagonal matrix, which is the
+// tridiagonal matrix with dl, d and du as described in NewTridiag, and the
+// corner elements [n-1,0] equal to lower and [0,n-1] equal to upper. Cyclic
+// tridiagonal matrices arise from periodic boundary conditions.
+// NewCyclicTridiag will panic if n is less than 3.
+func NewCyclicTridiag(n int, dl, d, du []float64, lower, upper float64) *Tridiag {
+	if n < 3 {
+		panic("mat: cyclic tridiagonal matrix too small")
+	}
+	t := NewTridiag(n, dl, d, du)
+	t.cyclic = true
+	t.lower = lower
+	t.upper = upper
+	return t
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (t *Tridiag) Dims() (r, c int) {
+	return len(t.d), len(t.d)
+}
+
+// At returns the element at row i, column j.
+func (t *Tridiag) At(i, j int) float64 {
+	n := len(t.d)
+	if uint(i) >= uint(n) {
+		panic(ErrRowAccess)
+	}
+	if uint(j) >= uint(n) {
+		panic(ErrColAccess)
+	}
+	switch j - i {
+	case 0:
+		return t.d[i]
+	case -1:
+		return t.dl[j]
+	case 1:
+		return t.du[i]
+	}
+	if t.cyclic {
+		switch {
+		case i == n-1 && j == 0:
+			return t.lower
+		case i == 0 && j == n-1:
+			return t.upper
+		}
+	}
+	return 0
+}
+
+// T performs an implicit transpose by returning the receiver inside a
+// Transpose.
+func (t *Tridiag) T() Matrix {
+	return Transpose{t}
+}
+
+// IsCyclic returns whether the matrix is a cyclic tridiagonal matrix.
+func (t *Tridiag) IsCyclic() bool {
+	return t.cyclic
+}
+
+// MulVecTo computes A⋅x or Aᵀ⋅x storing the result into dst.
+func (t *Tridiag) MulVecTo(dst *VecDense, trans bool, x Vector) {
+	n := len(t.d)
+	if x.Len() != n {
+		panic(ErrShape)
+	}
+	dl, du, lower, upper := t.dl, t.du, t.lower, t.upper
+	if trans {
+		dl, du, lower, upper = du, dl, upper, lower
+	}
+	xv := getWorkspaceVec(n, false)
+	xv.CopyVec(x)
+	defer putWorkspaceVec(xv)
+	xs := xv.mat.Data
+
+	dst.reuseAsNonZeroed(n)
+	for i := 0; i < n; i++ {
+		v := t.d[i] * xs[i]
+		if i > 0 {
+			v += dl[i-1] * xs[i-1]
+		}
+		if i < n-1 {
+			v += du[i] * xs[i+1]
+		}
+		dst.setVec(i, v)
+	}
+	if t.cyclic {
+		dst.setVec(0, dst.at(0)+upper*xs[n-1])
+		dst.setVec(n-1, dst.at(n-1)+lower*xs[0])
+	}
+}
+
+// SolveTo solves the tridiagonal system of linear equations
+//  A * X = B if trans == false
+//  Aᵀ * X = B if trans == true
+// and stores the result in dst. The system is solved by Gaussian elimination
+// without pivoting, the Thomas algorithm, which is stable for diagonally
+// dominant and for symmetric positive definite matrices. Cyclic systems are
+// reduced to two tridiagonal systems with the Sherman-Morrison formula.
+//
+// If a zero pivot is encountered, SolveTo returns a Condition error with an
+// infinite condition number and the contents of dst are undefined.
+func (t *Tridiag) SolveTo(dst *Dense, trans bool, b Matrix) error {
+	n := len(t.d)
+	br, bc := b.Dims()
+	if br != n {
+		panic(ErrShape)
+	}
+	dst.reuseAsNonZeroed(n, bc)
+	bU, _ := untranspose(b)
+	var restore func()
+	if dst == bU {
+		dst, restore = dst.isolatedWorkspace(bU)
+		defer restore()
+	} else if rm, ok := bU.(RawMatrixer); ok {
+		dst.checkOverlap(rm.RawMatrix())
+	}
+	dst.Copy(b)
+
+	work := getFloats(3*n, false)
+	defer putFloats(work)
+	for j := 0; j < bc; j++ {
+		if !t.solve(dst.mat.Data[j:], dst.mat.Stride, trans, work) {
+			return Condition(math.Inf(1))
+		}
+	}
+	return nil
+}
+
+// SolveVecTo solves the tridiagonal system of linear equations
+//  A * x = b if trans == false
+//  Aᵀ * x = b if trans == true
+// and stores the result in dst. See SolveTo for more information.
+func (t *Tridiag) SolveVecTo(dst *VecDense, trans bool, b Vector) error {
+	n := len(t.d)
+	if br, bc := b.Dims(); br != n || bc != 1 {
+		panic(ErrShape)
+	}
+	if rv, ok := b.(RawVectorer); ok && dst != b {
+		dst.checkOverlap(rv.RawVector())
+	}
+	dst.reuseAsNonZeroed(n)
+	if dst != b {
+		dst.CopyVec(b)
+	}
+	work := getFloats(3*n, false)
+	defer putFloats(work)
+	if !t.solve(dst.mat.Data, dst.mat.Inc, trans, work) {
+		return Condition(math.Inf(1))
+	}
+	return nil
+}
+
+// solve overwrites the vector x with stride inc with the solution of the
+// system. work must have length at least 3*n. solve returns false if a zero
+// pivot is encountered.
+func (t *Tridiag) solve(x []float64, inc int, trans bool, work []float64) bool {
+	n := len(t.d)
+	dl, du, lower, upper := t.dl, t.du, t.lower, t.upper
+	if trans {
+		dl, du, lower, upper = du, dl, upper, lower
+	}
+	if !t.cyclic {
+		return thomas(x, inc, dl, t.d, du, work[:n])
+	}
+
+	// Write A = T + u*vᵀ with
+	//  u = [gamma, 0, ..., 0, lower]ᵀ,
+	//  v = [1, 0, ..., 0, upper/gamma]ᵀ,
+	// where T is tridiagonal with the modified corner elements of the
+	// diagonal d, and solve T*y = b and T*z = u. Then the solution is
+	//  x = y - (vᵀ*y)/(1 + vᵀ*z) * z.
+	gamma := -t.d[0]
+	if gamma == 0 {
+		gamma = 1
+	}
+	d := work[n : 2*n]
+	copy(d, t.d)
+	d[0] -= gamma
+	d[n-1] -= lower * upper / gamma
+	z := work[2*n : 3*n]
+	for i := range z {
+		z[i] = 0
+	}
+	z[0] = gamma
+	z[n-1] = lower
+	if !thomas(x, inc, dl, d, du, work[:n]) || !thomas(z, 1, dl, d, du, work[:n]) {
+		return false
+	}
+	den := 1 + z[0] + upper/gamma*z[n-1]
+	if den == 0 {
+		return false
+	}
+	f := (x[0] + upper/gamma*x[(n-1)*inc]) / den
+	for i, v := range z {
+		x[i*inc] -= f * v
+	}
+	return true
+}
+
+// thomas overwrites the vector x with stride inc with the solution of the
+// tridiagonal system with the sub-diagonal dl, the diagonal d and the
+// super-diagonal du using the Thomas algorithm. work must have length n.
+// thomas returns false if a zero pivot is encountered.
+func thomas(x []float64, inc int, dl, d, du, work []float64) bool {
+	n := len(d)
+	w := d[0]
+	if w == 0 {
+		return false
+	}
+	x[0] /= w
+	for i := 1; i < n; i++ {
+		work[i-1] = du[i-1] / w
+		w = d[i] - dl[i-1]*work[i-1]
+		if w == 0 {
+			return false
+		}
+		x[i*inc] = (x[i*inc] - dl[i-1]*x[(i-1)*inc]) / w
+	}
+	for i := n - 2; i >= 0; i-- {
+		x[i*inc] -= work[i] * x[(i+1)*inc]
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestTridiag(t *testing.T) {
+	t.Parallel()
+	a := NewTridiag(4, []float64{1, 2, 3}, []float64{4, 5, 6, 7}, []float64{8, 9, 10})
+	want := NewDense(4, 4, []float64{
+		4, 8, 0, 0,
+		1, 5, 9, 0,
+		0, 2, 6, 10,
+		0, 0, 3, 7,
+	})
+	if !Equal(a, want) {
+		t.Errorf("unexpected tridiagonal matrix:\ngot:\n%v\nwant:\n%v", Formatted(a), Formatted(want))
+	}
+	c := NewCyclicTridiag(4, []float64{1, 2, 3}, []float64{4, 5, 6, 7}, []float64{8, 9, 10}, 11, 12)
+	want = NewDense(4, 4, []float64{
+		4, 8, 0, 12,
+		1, 5, 9, 0,
+		0, 2, 6, 10,
+		11, 0, 3, 7,
+	})
+	if !Equal(c, want) {
+		t.Errorf("unexpected cyclic tridiagonal matrix:\ngot:\n%v\nwant:\n%v", Formatted(c), Formatted(want))
+	}
+
+	for _, fn := range []func(){
+		func() { NewTridiag(0, nil, nil, nil) },
+		func() { NewTridiag(3, []float64{1}, []float64{1, 2, 3}, []float64{1, 2}) },
+		func() { NewCyclicTridiag(2, nil, nil, nil, 1, 1) },
+	} {
+		if ok, _ := panics(fn); !ok {
+			t.Errorf("expected panic")
+		}
+	}
+}
+
+func TestTridiagSolve(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 4, 10, 100} {
+		for _, cyclic := range []bool{false, true} {
+			if cyclic && n < 3 {
+				continue
+			}
+			// A random diagonally dominant matrix.
+			dl := make([]float64, n-1)
+			d := make([]float64, n)
+			du := make([]float64, n-1)
+			for i := range dl {
+				dl[i] = rnd.NormFloat64()
+				du[i] = rnd.NormFloat64()
+			}
+			for i := range d {
+				d[i] = math.Copysign(5+rnd.Float64(), rnd.NormFloat64())
+			}
+			var a *Tridiag
+			if cyclic {
+				a = NewCyclicTridiag(n, dl, d, du, rnd.NormFloat64(), rnd.NormFloat64())
+			} else {
+				a = NewTridiag(n, dl, d, du)
+			}
+			if a.IsCyclic() != cyclic {
+				t.Errorf("unexpected cyclic status")
+			}
+			ad := DenseCopyOf(a)
+
+			for _, trans := range []bool{false, true} {
+				b := NewDense(n, 3, nil)
+				for i := 0; i < n; i++ {
+					for j := 0; j < 3; j++ {
+						b.Set(i, j, rnd.NormFloat64())
+					}
+				}
+				var x, want Dense
+				if err := a.SolveTo(&x, trans, b); err != nil {
+					t.Fatalf("n=%d: unexpected error: %v", n, err)
+				}
+				var lu LU
+				lu.Factorize(ad)
+				lu.SolveTo(&want, trans, b)
+				if !EqualApprox(&x, &want, 1e-12) {
+					t.Errorf("n=%d,cyclic=%t,trans=%t: unexpected solution", n, cyclic, trans)
+				}
+
+				var xv VecDense
+				if err := a.SolveVecTo(&xv, trans, b.ColView(1)); err != nil {
+					t.Fatalf("n=%d: unexpected error: %v", n, err)
+				}
+				if !EqualApprox(&xv, want.ColView(1), 1e-12) {
+					t.Errorf("n=%d,cyclic=%t,trans=%t: unexpected vector solution", n, cyclic, trans)
+				}
+				// The solution may overwrite the right-hand side.
+				xv.CloneFromVec(b.ColView(1))
+				if err := a.SolveVecTo(&xv, trans, &xv); err != nil {
+					t.Fatalf("n=%d: unexpected error: %v", n, err)
+				}
+				if !EqualApprox(&xv, want.ColView(1), 1e-12) {
+					t.Errorf("n=%d,cyclic=%t,trans=%t: unexpected in-place solution", n, cyclic, trans)
+				}
+
+				var got, wantVec VecDense
+				a.MulVecTo(&got, trans, b.ColView(0))
+				if trans {
+					wantVec.MulVec(ad.T(), b.ColView(0))
+				} else {
+					wantVec.MulVec(ad, b.ColView(0))
+				}
+				if !EqualApprox(&got, &wantVec, 1e-12) {
+					t.Errorf("n=%d,cyclic=%t,trans=%t: unexpected product", n, cyclic, trans)
+				}
+			}
+		}
+	}
+
+	// A zero pivot.
+	a := NewTridiag(3, []float64{1, 1}, []float64{1, 1, 1}, []float64{1, 1})
+	var x VecDense
+	err := a.SolveVecTo(&x, false, NewVecDense(3, []float64{1, 2, 3}))
+	if _, ok := err.(Condition); !ok {
+		t.Errorf("expected Condition error for zero pivot, got %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/blas"
+	"gonum.org/v1/gonum/lapack/lapack64"
+)
+
+const (
+	badTruncSVD  = "mat: invalid truncated SVD factorization"
+	badTruncRank = "mat: truncation rank out of range"
+
+	// truncSVDOversample is the number of random samples taken in addition
+	// to the requested rank.
+	truncSVDOversample = 10
+	// truncSVDPowerIter is the number of power iterations used to sharpen
+	// the decay of the singular values.
+	truncSVDPowerIter = 2
+)
+
+// TruncatedSVD is a type for computing an approximation of the leading
+// singular values and singular vectors of a matrix.
+//
+// The truncated SVD of rank k of an m×n matrix A is
+//  A ≈ U * Σ * Vᵀ
+// where U is m×k and V is n×k with orthonormal columns, and Σ is a k×k
+// diagonal matrix holding the k largest singular values of A. Only products of
+// A and Aᵀ with tall thin matrices are formed, so the cost of the
+// factorization grows linearly with the size of A for a fixed k.
+type TruncatedSVD struct {
+	s []float64
+	u *Dense
+	v *Dense
+}
+
+// Factorize computes an approximation of the leading k singular values and
+// singular vectors of the m×n matrix a using randomized range finding. k must
+// be in [1, min(m,n)], otherwise Factorize will panic.
+//
+// The range of A is sampled with a Gaussian random matrix drawn from src,
+// oversampled by a small number of columns and refined with power iterations,
+// and the SVD of A projected onto the sampled range is then computed. The
+// approximation is accurate when the singular values of A beyond the k-th
+// decay quickly. If src is nil, the global random source is used.
+//
+// Factorize returns whether the decomposition succeeded. If the decomposition
+// failed, methods that require a successful factorization will panic.
+//
+// The algorithm is described in
+//  N. Halko, P. G. Martinsson and J. A. Tropp. Finding Structure with
+//  Randomness: Probabilistic Algorithms for Constructing Approximate Matrix
+//  Decompositions. SIAM Review 53(2) (2011), pp. 217–288.
+func (t *TruncatedSVD) Factorize(a Matrix, k int, src rand.Source) (ok bool) {
+	m, n := a.Dims()
+	if k < 1 || min(m, n) < k {
+		panic(badTruncRank)
+	}
+	t.Reset()
+
+	normFloat64 := rand.NormFloat64
+	if src != nil {
+		normFloat64 = rand.New(src).NormFloat64
+	}
+	l := min(k+truncSVDOversample, min(m, n))
+
+	// Sample the range of A.
+	omega := NewDense(n, l, nil)
+	for i := range omega.mat.Data {
+		omega.mat.Data[i] = normFloat64()
+	}
+	q := NewDense(m, l, nil)
+	q.Mul(a, omega)
+	orthonormalizeColumns(q)
+
+	// Refine the sample with power iterations on A*Aᵀ, orthonormalizing
+	// after each product to avoid loss of accuracy.
+	z := omega
+	for i := 0; i < truncSVDPowerIter; i++ {
+		z.Mul(a.T(), q)
+		orthonormalizeColumns(z)
+		q.Mul(a, z)
+		orthonormalizeColumns(q)
+	}
+
+	// B = Qᵀ * A is a small l×n matrix whose SVD gives the approximate
+	// SVD of A.
+	var b Dense
+	b.Mul(q.T(), a)
+	var svd SVD
+	if !svd.Factorize(&b, SVDThin) {
+		return false
+	}
+	values := svd.Values(nil)
+	var ub, vb Dense
+	svd.UTo(&ub)
+	svd.VTo(&vb)
+
+	t.s = values[:k]
+	t.u = NewDense(m, k, nil)
+	t.u.Mul(q, ub.Slice(0, l, 0, k))
+	t.v = NewDense(n, k, nil)
+	t.v.Copy(vb.Slice(0, n, 0, k))
+	return true
+}
+
+// orthonormalizeColumns overwrites the m×n matrix a, m >= n, with an m×n
+// matrix with orthonormal columns that span the column space of a.
+func orthonormalizeColumns(a *Dense) {
+	m, n := a.Dims()
+	qr := NewDense(m, n, nil)
+	qr.Copy(a)
+	tau := make([]float64, n)
+	work := []float64{0}
+	lapack64.Geqrf(qr.mat, tau, work, -1)
+	work = getFloats(int(work[0]), false)
+	lapack64.Geqrf(qr.mat, tau, work, len(work))
+	putFloats(work)
+
+	// Q = H_0 * ... * H_{n-1} * [I; 0].
+	a.Zero()
+	for i := 0; i < n; i++ {
+		a.set(i, i, 1)
+	}
+	work = []float64{0}
+	lapack64.Ormqr(blas.Left, blas.NoTrans, qr.mat, tau, a.mat, work, -1)
+	work = getFloats(int(work[0]), false)
+	lapack64.Ormqr(blas.Left, blas.NoTrans, qr.mat, tau, a.mat, work, len(work))
+	putFloats(work)
+}
+
+// isValid returns whether the receiver contains a factorization.
+func (t *TruncatedSVD) isValid() bool {
+	return len(t.s) != 0
+}
+
+// Reset resets the factorization so that it can be reused as the receiver of a
+// dimensionally restricted operation.
+func (t *TruncatedSVD) Reset() {
+	t.s = nil
+	t.u = nil
+	t.v = nil
+}
+
+// Rank returns the number of singular triplets k of the factorization.
+// Rank will panic if the receiver does not contain a successful factorization.
+func (t *TruncatedSVD) Rank() int {
+	if !t.isValid() {
+		panic(badTruncSVD)
+	}
+	return len(t.s)
+}
+
+// Values returns the k approximate leading singular values of the factorized
+// matrix in descending order.
+//
+// If the input slice is non-nil, the values will be stored in-place into
+// the slice. In this case, the slice must have length k, and Values will
+// panic with ErrSliceLengthMismatch otherwise. If the input slice is nil, a new
+// slice of the appropriate length will be allocated and returned.
+//
+// Values will panic if the receiver does not contain a successful factorization.
+func (t *TruncatedSVD) Values(s []float64) []float64 {
+	if !t.isValid() {
+		panic(badTruncSVD)
+	}
+	if s == nil {
+		s = make([]float64, len(t.s))
+	}
+	if len(s) != len(t.s) {
+		panic(ErrSliceLengthMismatch)
+	}
+	copy(s, t.s)
+	return s
+}
+
+// UTo extracts the m×k matrix U of the approximate leading left singular
+// vectors, corresponding to the singular values returned from Values.
+//
+// If dst is empty, UTo will resize dst to be m×k. When dst is non-empty, UTo
+// will panic if dst is not m×k. UTo will also panic if the receiver does not
+// contain a successful factorization.
+func (t *TruncatedSVD) UTo(dst *Dense) {
+	if !t.isValid() {
+		panic(badTruncSVD)
+	}
+	truncSVDVectorsTo(dst, t.u)
+}
+
+// VTo extracts the n×k matrix V of the approximate leading right singular
+// vectors, corresponding to the singular values returned from Values.
+//
+// If dst is empty, VTo will resize dst to be n×k. When dst is non-empty, VTo
+// will panic if dst is not n×k. VTo will also panic if the receiver does not
+// contain a successful factorization.
+func (t *TruncatedSVD) VTo(dst *Dense) {
+	if !t.isValid() {
+		panic(badTruncSVD)
+	}
+	truncSVDVectorsTo(dst, t.v)
+}
+
+func truncSVDVectorsTo(dst, src *Dense) {
+	r, c := src.Dims()
+	if dst.IsEmpty() {
+		dst.ReuseAs(r, c)
+	} else {
+		r2, c2 := dst.Dims()
+		if r != r2 || c != c2 {
+			panic(ErrShape)
+		}
+	}
+	dst.Copy(src)
+}
@@ -0,0 +1,102 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestTruncatedSVD(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n, k int
+		decay   float64
+	}{
+		{m: 10, n: 10, k: 1, decay: 0.5},
+		{m: 50, n: 20, k: 5, decay: 0.5},
+		{m: 20, n: 50, k: 5, decay: 0.5},
+		{m: 200, n: 100, k: 10, decay: 0.7},
+		{m: 30, n: 30, k: 30, decay: 0.9},
+	} {
+		m, n, k := test.m, test.n, test.k
+		// A = X * Σ * Yᵀ with geometrically decaying singular values.
+		r := min(m, n)
+		x := randNormal(m, r, rnd)
+		orthonormalizeColumns(x)
+		y := randNormal(n, r, rnd)
+		orthonormalizeColumns(y)
+		for j := 0; j < r; j++ {
+			s := math.Pow(test.decay, float64(j))
+			for i := 0; i < m; i++ {
+				x.set(i, j, x.at(i, j)*s)
+			}
+		}
+		var a Dense
+		a.Mul(x, y.T())
+
+		var svd SVD
+		svd.Factorize(&a, SVDNone)
+		want := svd.Values(nil)[:k]
+
+		var tsvd TruncatedSVD
+		if !tsvd.Factorize(&a, k, rand.NewSource(1)) {
+			t.Fatalf("m=%d,n=%d,k=%d: factorization failed", m, n, k)
+		}
+		if tsvd.Rank() != k {
+			t.Errorf("m=%d,n=%d,k=%d: unexpected rank %d", m, n, k, tsvd.Rank())
+		}
+		got := tsvd.Values(nil)
+		for i := range got {
+			if math.Abs(got[i]-want[i]) > 1e-8*want[0] {
+				t.Errorf("m=%d,n=%d,k=%d: unexpected singular value %d: got %v, want %v", m, n, k, i, got[i], want[i])
+			}
+		}
+
+		var u, v Dense
+		tsvd.UTo(&u)
+		tsvd.VTo(&v)
+		eye := NewDiagDense(k, nil)
+		for i := 0; i < k; i++ {
+			eye.SetDiag(i, 1)
+		}
+		var utu, vtv Dense
+		utu.Mul(u.T(), &u)
+		vtv.Mul(v.T(), &v)
+		if !EqualApprox(&utu, eye, 1e-12) || !EqualApprox(&vtv, eye, 1e-12) {
+			t.Errorf("m=%d,n=%d,k=%d: singular vectors not orthonormal", m, n, k)
+		}
+
+		// A*V = U*Σ.
+		var av Dense
+		av.Mul(&a, &v)
+		for j := 0; j < k; j++ {
+			for i := 0; i < m; i++ {
+				u.set(i, j, u.at(i, j)*got[j])
+			}
+		}
+		if !EqualApprox(&av, &u, 1e-8) {
+			t.Errorf("m=%d,n=%d,k=%d: A*V != U*Σ", m, n, k)
+		}
+	}
+
+	if ok, _ := panics(func() {
+		var tsvd TruncatedSVD
+		tsvd.Factorize(NewDense(3, 4, nil), 4, nil)
+	}); !ok {
+		t.Errorf("expected panic for rank out of range")
+	}
+}
+
+func randNormal(r, c int, rnd *rand.Rand) *Dense {
+	m := NewDense(r, c, nil)
+	for i := range m.mat.Data {
+		m.mat.Data[i] = rnd.NormFloat64()
+	}
+	return m
+}
@@ -612,6 +612,16 @@ func (v *VecDense) MulVec(a Matrix, b Vector) {
 		}
 		v.setVec(0, sum)
 		return
+	case *BandDense:
+		if fast {
+			aU.checkOverlap(v.asGeneral())
+			t := blas.NoTrans
+			if trans {
+				t = blas.Trans
+			}
+			blas64.Gbmv(t, 1, aU.mat, bmat, 0, v.mat)
+			return
+		}
 	case *SymBandDense:
 		if fast {
 			aU.checkOverlap(v.asGeneral())
@@ -632,6 +642,12 @@ func (v *VecDense) MulVec(a Matrix, b Vector) {
 			ta = blas.Trans
 		}
 		blas64.Trmv(ta, aU.mat, v.mat)
+	case sparser:
+		if fast {
+			c, byRow := aU.compressed()
+			c.mulVec(v.mat.Data, v.mat.Inc, byRow == trans, bmat.Data, bmat.Inc)
+			return
+		}
 	case *Dense:
 		if fast {
 			aU.checkOverlap(v.asGeneral())
@@ -0,0 +1,581 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ Method            = (*ActiveSet)(nil)
+	_ localMethod       = (*ActiveSet)(nil)
+	_ Bounder           = (*ActiveSet)(nil)
+	_ LinearConstrainer = (*ActiveSet)(nil)
+	_ gradNormer        = (*ActiveSet)(nil)
+)
+
+// ActiveSet implements a quasi-Newton active-set method for gradient-based
+// minimization subject to linear equality and inequality constraints and
+// bound constraints on the variables.
+//
+// ActiveSet maintains a working set of inequality constraints that are
+// treated as equalities together with the equality constraints. At every
+// iteration the objective function is minimized in the null space of the
+// working set using a damped BFGS approximation of the Hessian and
+// a backtracking search. A constraint is added to the working set when it
+// blocks the step, and removed when its Lagrange multiplier estimate shows
+// that the objective function decreases by leaving the constraint. See
+//  Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer (2006),
+//  chapters 16.5 and 18.3
+// for the details of the method.
+//
+// The constraints are taken from the Linear and Bounds fields of the Problem,
+// and the initial location must satisfy them. ActiveSet is suitable for
+// problems of moderate dimension, as it uses dense factorizations of the
+// working set and of the Hessian approximation.
+type ActiveSet struct {
+	// DecreaseFactor is the constant in the sufficient decrease condition
+	// of the backtracking search. It must be between zero and one, and if
+	// DecreaseFactor is 0, it will be defaulted to 1e-4.
+	DecreaseFactor float64
+	// GradStopThreshold sets the threshold for stopping if the optimality
+	// measure gets too small. The optimality measure is the maximum of the
+	// infinity norm of the gradient projected onto the null space of the
+	// working set, and of the magnitude of negative Lagrange multiplier
+	// estimates of the inequality constraints in the working set. If
+	// GradStopThreshold is 0 it is defaulted to 1e-12, and if it is NaN the
+	// setting is not used.
+	GradStopThreshold float64
+
+	status Status
+	err    error
+
+	bounds []Bound
+	linear *LinearConstraints
+
+	dim       int
+	eq        [][]float64 // Rows of the equality constraints
+	ineq      [][]float64 // Rows of the inequality constraints including bounds
+	rhs       []float64   // Right-hand sides of the inequality constraints
+	working   []int       // Indices of the inequality constraints in the working set
+	inWorking []bool      // Indicator of the inequality constraints in the working set
+	started   bool        // Indicator that the working set has been initialized
+
+	// Factorization of the working set.
+	svd   mat.SVD
+	u, v  mat.Dense
+	sigma []float64
+	rank  int
+
+	x     []float64     // Location at the last major iteration
+	f     float64       // Function value at the last major iteration
+	grad  []float64     // Gradient at the last major iteration
+	hess  *mat.SymDense // Approximation of the Hessian
+	first bool          // Indicator that the Hessian approximation has not been updated
+
+	dir     []float64      // Search direction
+	step    float64        // Current step along dir
+	maxStep float64        // Largest feasible step along dir
+	block   int            // Constraint blocking the step at maxStep, or -1
+	slope   float64        // Directional derivative along the trial step
+	iter    int            // Number of backtracking steps in the current search
+	next    backtrackState // Next action taken in iterateLocal
+}
+
+func (a *ActiveSet) Status() (Status, error) {
+	return a.status, a.err
+}
+
+func (*ActiveSet) Uses(has Available) (uses Available, err error) {
+	return has.linearGradient()
+}
+
+// SetBounds sets the bounds on the variables. It implements the Bounder
+// interface.
+func (a *ActiveSet) SetBounds(bounds []Bound) {
+	a.bounds = bounds
+}
+
+// SetLinearConstraints sets the linear constraints on the variables. It
+// implements the LinearConstrainer interface.
+func (a *ActiveSet) SetLinearConstraints(c *LinearConstraints) {
+	a.linear = c
+}
+
+func (a *ActiveSet) Init(dim, tasks int) int {
+	a.status = NotTerminated
+	a.err = nil
+	a.started = false
+	return 1
+}
+
+func (a *ActiveSet) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	a.status, a.err = localOptimizer{}.run(a, a.GradStopThreshold, operation, result, tasks)
+	close(operation)
+}
+
+// start initializes the constraints and the working set at the initial
+// location x.
+func (a *ActiveSet) start(x []float64) {
+	a.started = true
+	a.dim = len(x)
+	a.eq, a.ineq, a.rhs = nil, nil, nil
+	if a.linear != nil {
+		a.eq, _ = a.linear.equality()
+		// The equality constraints are included in the working set as
+		// long as they are linearly independent.
+		a.eq = a.independent(a.eq)
+		rows, rhs := a.linear.inequality()
+		a.ineq = append(a.ineq, rows...)
+		a.rhs = append(a.rhs, rhs...)
+	}
+	if a.bounds != nil && len(a.bounds) != a.dim {
+		panic("activeset: bounds length mismatch")
+	}
+	for i, b := range a.bounds {
+		if !math.IsInf(b.Min, -1) {
+			row := make([]float64, a.dim)
+			row[i] = -1
+			a.ineq = append(a.ineq, row)
+			a.rhs = append(a.rhs, -b.Min)
+		}
+		if !math.IsInf(b.Max, 1) {
+			row := make([]float64, a.dim)
+			row[i] = 1
+			a.ineq = append(a.ineq, row)
+			a.rhs = append(a.rhs, b.Max)
+		}
+	}
+
+	a.working = a.working[:0]
+	a.inWorking = make([]bool, len(a.ineq))
+	a.factorize()
+	for i, row := range a.ineq {
+		if math.Abs(floats.Dot(row, x)-a.rhs[i]) > linearTol*(1+math.Abs(a.rhs[i])) {
+			continue
+		}
+		rank := a.rank
+		a.add(i)
+		if a.rank == rank {
+			// The constraint is linearly dependent on the working set.
+			a.remove(len(a.working) - 1)
+		}
+	}
+}
+
+// independent returns a linearly independent subset of rows.
+func (a *ActiveSet) independent(rows [][]float64) [][]float64 {
+	var ind [][]float64
+	rank := 0
+	for _, row := range rows {
+		try := append(ind, row)
+		a.factorizeRows(try)
+		if a.rank > rank {
+			ind = try
+			rank = a.rank
+		}
+	}
+	return ind
+}
+
+// add adds the inequality constraint i to the working set.
+func (a *ActiveSet) add(i int) {
+	a.working = append(a.working, i)
+	a.inWorking[i] = true
+	a.factorize()
+}
+
+// remove removes the j-th constraint of the working set.
+func (a *ActiveSet) remove(j int) {
+	a.inWorking[a.working[j]] = false
+	a.working = append(a.working[:j], a.working[j+1:]...)
+	a.factorize()
+}
+
+// factorize computes the singular value decomposition of the matrix of the
+// constraints in the working set.
+func (a *ActiveSet) factorize() {
+	rows := make([][]float64, 0, len(a.eq)+len(a.working))
+	rows = append(rows, a.eq...)
+	for _, i := range a.working {
+		rows = append(rows, a.ineq[i])
+	}
+	a.factorizeRows(rows)
+}
+
+func (a *ActiveSet) factorizeRows(rows [][]float64) {
+	a.rank = 0
+	if len(rows) == 0 {
+		return
+	}
+	m := mat.NewDense(len(rows), a.dim, nil)
+	for i, row := range rows {
+		m.SetRow(i, row)
+	}
+	ok := a.svd.Factorize(m, mat.SVDFull)
+	if !ok {
+		panic("activeset: singular value decomposition failed")
+	}
+	a.sigma = a.svd.Values(nil)
+	a.u.Reset()
+	a.svd.UTo(&a.u)
+	a.v.Reset()
+	a.svd.VTo(&a.v)
+	tol := float64(a.dim) * a.sigma[0] * dlamchE
+	if len(rows) > a.dim {
+		tol = float64(len(rows)) * a.sigma[0] * dlamchE
+	}
+	for _, s := range a.sigma {
+		if s > tol {
+			a.rank++
+		}
+	}
+}
+
+// project stores in dst the projection of g onto the null space of the working
+// set.
+func (a *ActiveSet) project(dst, g []float64) {
+	copy(dst, g)
+	for j := 0; j < a.rank; j++ {
+		vj := mat.Col(nil, j, &a.v)
+		floats.AddScaled(dst, -floats.Dot(vj, g), vj)
+	}
+}
+
+// multipliers returns the least-squares estimates of the Lagrange multipliers
+// of the inequality constraints in the working set at a location with gradient
+// g. The multipliers λ minimize the norm of g + Mᵀλ where the rows of M are
+// the constraints in the working set, and must be non-negative at a minimum.
+func (a *ActiveSet) multipliers(g []float64) []float64 {
+	m := len(a.eq) + len(a.working)
+	lambda := make([]float64, m)
+	for j := 0; j < a.rank; j++ {
+		c := -floats.Dot(mat.Col(nil, j, &a.v), g) / a.sigma[j]
+		for i := range lambda {
+			lambda[i] += c * a.u.At(i, j)
+		}
+	}
+	return lambda[len(a.eq):]
+}
+
+// gradNorm returns the optimality measure at loc.
+func (a *ActiveSet) gradNorm(loc *Location) float64 {
+	if !a.started {
+		a.start(loc.X)
+	}
+	pg := make([]float64, len(loc.Gradient))
+	a.project(pg, loc.Gradient)
+	norm := floats.Norm(pg, math.Inf(1))
+	for _, l := range a.multipliers(loc.Gradient) {
+		norm = math.Max(norm, -l)
+	}
+	return norm
+}
+
+func (a *ActiveSet) initLocal(loc *Location) (Operation, error) {
+	if a.DecreaseFactor == 0 {
+		a.DecreaseFactor = 1e-4
+	}
+	if a.DecreaseFactor <= 0 || a.DecreaseFactor >= 1 {
+		panic("activeset: DecreaseFactor must be between 0 and 1")
+	}
+	if !a.started {
+		a.start(loc.X)
+	}
+	dim := len(loc.X)
+	a.x = resize(a.x, dim)
+	a.grad = resize(a.grad, dim)
+	a.dir = resize(a.dir, dim)
+	a.hess = resizeSymDense(a.hess, dim)
+	a.resetHessian()
+
+	a.accept(loc)
+	return a.initSearch(loc)
+}
+
+// resetHessian sets the Hessian approximation to the identity.
+func (a *ActiveSet) resetHessian() {
+	for i := 0; i < a.dim; i++ {
+		for j := i; j < a.dim; j++ {
+			if i == j {
+				a.hess.SetSym(i, i, 1)
+			} else {
+				a.hess.SetSym(i, j, 0)
+			}
+		}
+	}
+	a.first = true
+}
+
+func (a *ActiveSet) iterateLocal(loc *Location) (Operation, error) {
+	switch a.next {
+	default:
+		panic("activeset: unexpected state")
+	case backtrackSearch:
+		if loc.F <= a.f+a.DecreaseFactor*a.slope {
+			// The trial location is accepted, evaluate the gradient.
+			a.next = backtrackGrad
+			return GradEvaluation, nil
+		}
+		a.iter++
+		if a.iter >= maxBacktracks {
+			return NoOperation, ErrLinesearcherFailure
+		}
+		a.step /= 2
+		if !a.trial(loc) {
+			return a.restart(loc)
+		}
+		return FuncEvaluation, nil
+	case backtrackGrad:
+		a.update(loc)
+		if a.step == a.maxStep && a.block >= 0 {
+			a.add(a.block)
+		}
+		a.next = backtrackMajor
+		return MajorIteration, nil
+	case backtrackMajor:
+		return a.initSearch(loc)
+	}
+}
+
+// accept stores loc as the location of the last major iteration.
+func (a *ActiveSet) accept(loc *Location) {
+	copy(a.x, loc.X)
+	copy(a.grad, loc.Gradient)
+	a.f = loc.F
+}
+
+// update updates the Hessian approximation with the step from the last major
+// iteration to loc using the damped BFGS update, and accepts loc.
+func (a *ActiveSet) update(loc *Location) {
+	dim := a.dim
+	s := mat.NewVecDense(dim, nil)
+	s.SubVec(mat.NewVecDense(dim, loc.X), mat.NewVecDense(dim, a.x))
+	y := mat.NewVecDense(dim, nil)
+	y.SubVec(mat.NewVecDense(dim, loc.Gradient), mat.NewVecDense(dim, a.grad))
+	a.accept(loc)
+	a.first = dampedBFGS(a.hess, s, y, a.first)
+}
+
+// dampedBFGS updates the positive definite Hessian approximation hess with
+// the step s and the corresponding change of the gradient y using the damped
+// BFGS update. If first is true, hess is the initial approximation, which is
+// rescaled before the update. dampedBFGS returns whether hess is still the
+// initial approximation.
+func dampedBFGS(hess *mat.SymDense, s, y *mat.VecDense, first bool) bool {
+	dim := s.Len()
+	sDotY := mat.Dot(s, y)
+	if first {
+		if sDotY <= dlamchE*mat.Norm(s, 2)*mat.Norm(y, 2) {
+			return true
+		}
+		// Rescale the initial Hessian.
+		scale := mat.Dot(y, y) / sDotY
+		for i := 0; i < dim; i++ {
+			hess.SetSym(i, i, scale)
+		}
+	}
+
+	// Damp the update so that the Hessian approximation remains positive
+	// definite as described in
+	// Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer (2006), procedure 18.2.
+	bs := mat.NewVecDense(dim, nil)
+	bs.MulVec(hess, s)
+	sBs := mat.Dot(s, bs)
+	if sBs <= 0 {
+		return false
+	}
+	theta := 1.0
+	if sDotY < 0.2*sBs {
+		theta = 0.8 * sBs / (sBs - sDotY)
+	}
+	r := mat.NewVecDense(dim, nil)
+	r.AddScaledVec(bs, theta, y)
+	r.AddScaledVec(r, -theta, bs)
+	sDotR := mat.Dot(s, r)
+	if sDotR <= 0 {
+		return false
+	}
+	hess.SymRankOne(hess, -1/sBs, bs)
+	hess.SymRankOne(hess, 1/sDotR, r)
+	return false
+}
+
+// initSearch computes the search direction at the last major iteration,
+// updating the working set, and sets loc.X to the first trial location of
+// the backtracking search.
+func (a *ActiveSet) initSearch(loc *Location) (Operation, error) {
+	pg := make([]float64, a.dim)
+	dropped := -1
+	for iter := 0; iter <= len(a.ineq); iter++ {
+		a.project(pg, a.grad)
+		norm := floats.Norm(pg, math.Inf(1))
+
+		// Remove the constraint with the most negative multiplier from
+		// the working set if the objective function decreases faster by
+		// leaving the constraint than in the null space of the working set.
+		if dropped < 0 {
+			minIdx := -1
+			minVal := -norm
+			for j, l := range a.multipliers(a.grad) {
+				if l < minVal {
+					minIdx = j
+					minVal = l
+				}
+			}
+			if minIdx >= 0 {
+				dropped = a.working[minIdx]
+				a.remove(minIdx)
+				continue
+			}
+		}
+
+		if !a.direction() {
+			return NoOperation, ErrNoProgress
+		}
+		if a.maxStep == 0 {
+			// The direction is blocked by a constraint at the current
+			// location. Add the constraint to the working set and compute
+			// a new direction.
+			a.add(a.block)
+			if a.block == dropped {
+				// The removal of the constraint did not enable
+				// progress.
+				dropped = len(a.ineq)
+			}
+			continue
+		}
+
+		a.iter = 0
+		a.step = 1
+		if a.first {
+			a.step = math.Min(1, 1/floats.Norm(a.dir, 2))
+		}
+		a.step = math.Min(a.step, a.maxStep)
+		if !a.trial(loc) {
+			return a.restart(loc)
+		}
+		a.next = backtrackSearch
+		return FuncEvaluation, nil
+	}
+	return NoOperation, ErrNoProgress
+}
+
+// restart resets the Hessian approximation and starts a new search. restart is
+// called when the search along the quasi-Newton direction does not make
+// progress.
+func (a *ActiveSet) restart(loc *Location) (Operation, error) {
+	if a.first {
+		return NoOperation, ErrNoProgress
+	}
+	a.resetHessian()
+	return a.initSearch(loc)
+}
+
+// trial sets loc.X to the location at the current step along the search
+// direction, and computes the directional derivative along the step. trial
+// returns false if the location does not differ from the location of the last
+// major iteration.
+func (a *ActiveSet) trial(loc *Location) bool {
+	floats.AddScaledTo(loc.X, a.x, a.step, a.dir)
+	// Remove the rounding errors that would place the location outside
+	// the bounds.
+	project(loc.X, a.bounds)
+	if floats.Equal(loc.X, a.x) {
+		return false
+	}
+	a.slope = 0
+	for i, g := range a.grad {
+		a.slope += g * (loc.X[i] - a.x[i])
+	}
+	return true
+}
+
+// direction computes the quasi-Newton search direction in the null space of
+// the working set and the largest feasible step along it. direction returns
+// false if the null space is empty.
+func (a *ActiveSet) direction() bool {
+	dim := a.dim
+	k := dim - a.rank
+	if k == 0 {
+		return false
+	}
+	// The columns of Z form a basis of the null space of the working set.
+	var z mat.Matrix
+	if a.rank == 0 {
+		z = eye(dim)
+	} else {
+		z = a.v.Slice(0, dim, a.rank, dim)
+	}
+
+	// Solve (Zᵀ B Z) w = -Zᵀ g and set dir = Z w.
+	var bz, zbz mat.Dense
+	bz.Mul(a.hess, z)
+	zbz.Mul(z.T(), &bz)
+	reduced := mat.NewSymDense(k, nil)
+	for i := 0; i < k; i++ {
+		for j := i; j < k; j++ {
+			reduced.SetSym(i, j, 0.5*(zbz.At(i, j)+zbz.At(j, i)))
+		}
+	}
+	var zg mat.VecDense
+	zg.MulVec(z.T(), mat.NewVecDense(dim, a.grad))
+	zg.ScaleVec(-1, &zg)
+	var w mat.VecDense
+	var chol mat.Cholesky
+	if ok := chol.Factorize(reduced); ok {
+		err := chol.SolveVecTo(&w, &zg)
+		if err != nil {
+			w.CloneFromVec(&zg)
+		}
+	} else {
+		w.CloneFromVec(&zg)
+	}
+	d := mat.NewVecDense(dim, a.dir)
+	d.MulVec(z, &w)
+
+	// Find the largest step that keeps the constraints outside the working
+	// set satisfied.
+	a.maxStep = math.Inf(1)
+	a.block = -1
+	dNorm := floats.Norm(a.dir, 2)
+	for i, row := range a.ineq {
+		if a.inWorking[i] {
+			continue
+		}
+		rd := floats.Dot(row, a.dir)
+		if rd <= dlamchE*floats.Norm(row, 2)*dNorm {
+			continue
+		}
+		step := math.Max(0, (a.rhs[i]-floats.Dot(row, a.x))/rd)
+		if step < a.maxStep {
+			a.maxStep = step
+			a.block = i
+		}
+	}
+	return true
+}
+
+// eye returns the n×n identity matrix.
+func eye(n int) *mat.Dense {
+	m := mat.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		m.Set(i, i, 1)
+	}
+	return m
+}
+
+func (*ActiveSet) needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return struct {
+		Gradient bool
+		Hessian  bool
+	}{true, false}
+}
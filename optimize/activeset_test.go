@@ -0,0 +1,187 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+// weightedQuadratic returns the Problem of minimizing
+//  sum_i w[i] * (x[i] - c[i])^2.
+func weightedQuadratic(w, c []float64) Problem {
+	return Problem{
+		Func: func(x []float64) float64 {
+			var f float64
+			for i, v := range x {
+				f += w[i] * (v - c[i]) * (v - c[i])
+			}
+			return f
+		},
+		Grad: func(grad, x []float64) {
+			for i, v := range x {
+				grad[i] = 2 * w[i] * (v - c[i])
+			}
+		},
+	}
+}
+
+func TestActiveSet(t *testing.T) {
+	t.Parallel()
+	inf := math.Inf(1)
+	quad := weightedQuadratic([]float64{1, 1, 1}, []float64{1, 2, 3})
+	rosen := Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+		Grad: functions.ExtendedRosenbrock{}.Grad,
+	}
+	for _, test := range []struct {
+		name   string
+		p      Problem
+		linear *LinearConstraints
+		bounds []Bound
+		x      []float64
+		want   []float64
+	}{
+		{
+			name: "equality",
+			p:    quad,
+			linear: &LinearConstraints{
+				A: mat.NewDense(1, 3, []float64{1, 1, 1}),
+				B: []float64{3},
+			},
+			x:    []float64{3, 0, 0},
+			want: []float64{0, 1, 2},
+		},
+		{
+			name: "redundant equality",
+			p:    quad,
+			linear: &LinearConstraints{
+				A: mat.NewDense(2, 3, []float64{1, 1, 1, 2, 2, 2}),
+				B: []float64{3, 6},
+			},
+			x:    []float64{3, 0, 0},
+			want: []float64{0, 1, 2},
+		},
+		{
+			name: "inequality",
+			p:    quad,
+			linear: &LinearConstraints{
+				G: mat.NewDense(2, 3, []float64{1, 1, 1, -1, 0, 0}),
+				H: []float64{3, -0.5},
+			},
+			x:    []float64{1, 0, 0},
+			want: []float64{0.5, 0.75, 1.75},
+		},
+		{
+			name: "inactive inequality",
+			p:    quad,
+			linear: &LinearConstraints{
+				G: mat.NewDense(1, 3, []float64{1, 1, 1}),
+				H: []float64{10},
+			},
+			x:    []float64{0, 0, 0},
+			want: []float64{1, 2, 3},
+		},
+		{
+			name: "equality and inequality",
+			p:    quad,
+			linear: &LinearConstraints{
+				A: mat.NewDense(1, 3, []float64{1, 1, 1}),
+				B: []float64{3},
+				G: mat.NewDense(1, 3, []float64{-1, 0, 0}),
+				H: []float64{-0.5},
+			},
+			x:    []float64{1, 1, 1},
+			want: []float64{0.5, 0.75, 1.75},
+		},
+		{
+			name:   "bounds dropped from working set",
+			p:      weightedQuadratic([]float64{1, 1}, []float64{1, 1}),
+			bounds: []Bound{{-1, 2}, {-inf, 0.5}},
+			x:      []float64{2, -1},
+			want:   []float64{1, 0.5},
+		},
+		{
+			name: "rosenbrock",
+			p:    rosen,
+			linear: &LinearConstraints{
+				G: mat.NewDense(1, 2, []float64{1, 0}),
+				H: []float64{0.5},
+			},
+			x:    []float64{-1.2, 1},
+			want: []float64{0.5, 0.25},
+		},
+		{
+			name: "rosenbrock linear and bounds",
+			p:    rosen,
+			linear: &LinearConstraints{
+				A: mat.NewDense(1, 3, []float64{1, 1, 1}),
+				B: []float64{2},
+			},
+			bounds: []Bound{{-inf, inf}, {0.5, inf}, {-inf, 0}},
+			x:      []float64{1, 1, 0},
+			want:   nil,
+		},
+	} {
+		p := test.p
+		p.Linear = test.linear
+		p.Bounds = test.bounds
+		x := make([]float64, len(test.x))
+		copy(x, test.x)
+		result, err := Minimize(p, x, nil, &ActiveSet{})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if result.Status != GradientThreshold {
+			t.Errorf("%s: unexpected status: got %v, want %v", test.name, result.Status, GradientThreshold)
+		}
+		if test.want != nil && !floats.EqualApprox(result.X, test.want, 1e-6) {
+			t.Errorf("%s: unexpected optimum: got %v, want %v", test.name, result.X, test.want)
+		}
+		if err := checkLinear(test.linear, result.X); err != nil {
+			t.Errorf("%s: optimum does not satisfy linear constraints: %v", test.name, result.X)
+		}
+		if err := checkBounds(test.bounds, result.X); err != nil {
+			t.Errorf("%s: optimum outside bounds: %v", test.name, result.X)
+		}
+	}
+}
+
+func TestMinimizeLinear(t *testing.T) {
+	t.Parallel()
+	p := weightedQuadratic([]float64{1, 1, 1}, []float64{1, 2, 3})
+	p.Linear = &LinearConstraints{
+		A: mat.NewDense(1, 3, []float64{1, 1, 1}),
+		B: []float64{3},
+	}
+
+	// The default method supports linear constraints.
+	result, err := Minimize(p, []float64{1, 1, 1}, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []float64{0, 1, 2}
+	if !floats.EqualApprox(result.X, want, 1e-6) {
+		t.Errorf("unexpected optimum with default method: got %v, want %v", result.X, want)
+	}
+
+	_, err = Minimize(p, []float64{0, 0, 0}, nil, &ActiveSet{})
+	if err != ErrInfeasible {
+		t.Errorf("unexpected error for infeasible initial location: got %v, want %v", err, ErrInfeasible)
+	}
+
+	if !panics(func() { Minimize(p, []float64{1, 1, 1}, nil, &LBFGSB{}) }) {
+		t.Errorf("no panic for linear constraints with bound-constrained method")
+	}
+	p.Linear.B = []float64{3, 3}
+	if !panics(func() { Minimize(p, []float64{1, 1, 1}, nil, &ActiveSet{}) }) {
+		t.Errorf("no panic for right-hand side length mismatch")
+	}
+}
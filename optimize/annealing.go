@@ -0,0 +1,306 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+var (
+	_ Method   = (*SimulatedAnnealing)(nil)
+	_ Statuser = (*SimulatedAnnealing)(nil)
+	_ Bounder  = (*SimulatedAnnealing)(nil)
+
+	_ CoolingSchedule = ExponentialCooling{}
+	_ CoolingSchedule = LogarithmicCooling{}
+	_ CoolingSchedule = FastCooling{}
+	_ Neighborer      = GaussianNeighbor{}
+)
+
+// SimulatedAnnealing is a global optimizer that performs a random walk whose
+// steps are accepted according to the Metropolis criterion. A candidate
+// location y generated from the current location x by the Neighbor is always
+// accepted if f(y) ≤ f(x), and otherwise with probability
+//  exp(-(f(y) - f(x)) / T)
+// where T is the temperature. The temperature is lowered according to the
+// Cooling schedule after a fixed number of candidate locations, so that the
+// walk progressively concentrates around low function values. See
+//  Kirkpatrick, S., Gelatt, C. D., Vecchi, M. P.: Optimization by simulated
+//  annealing. Science 220 (1983), 671-680
+// for more information.
+//
+// Each major iteration of SimulatedAnnealing corresponds to a temperature,
+// and the location of the major iteration is the best location found so far.
+// If the Problem has bounds, the candidate locations are projected onto the
+// bounds. SimulatedAnnealing evaluates the candidate locations sequentially.
+type SimulatedAnnealing struct {
+	// InitTemperature is the initial temperature. It should be comparable
+	// to the differences of the function values that the walk is allowed
+	// to climb. If InitTemperature is 0, a default value of 1 is used.
+	// InitTemperature cannot be negative, or SimulatedAnnealing will panic.
+	InitTemperature float64
+	// MinTemperature sets the threshold for stopping the optimization with
+	// MethodConverge status when the temperature falls below it. If
+	// MinTemperature is 0, a default value of 1e-8 times the initial
+	// temperature is used, and if it is NaN the stopping criterion is not
+	// used.
+	MinTemperature float64
+	// Proposals is the number of candidate locations evaluated at each
+	// temperature. If Proposals is 0, a default value of 10 times the
+	// dimension is used. Proposals cannot be negative, or
+	// SimulatedAnnealing will panic.
+	Proposals int
+	// Cooling is the cooling schedule. If Cooling is nil, a default value
+	// of ExponentialCooling{} is used.
+	Cooling CoolingSchedule
+	// Neighbor generates the candidate locations. If Neighbor is nil,
+	// a default value of GaussianNeighbor{} is used.
+	Neighbor Neighborer
+	// Src allows a random number generator to be supplied for generating
+	// the candidate locations and for the Metropolis criterion. If Src is
+	// nil the generator in golang.org/x/exp/rand is used.
+	Src rand.Source
+
+	bounds []Bound
+
+	status    Status
+	rnd       *rand.Rand
+	cooling   CoolingSchedule
+	neighbor  Neighborer
+	initTemp  float64
+	minTemp   float64
+	proposals int
+
+	temp    float64   // Current temperature
+	iter    int       // Index of the current temperature
+	count   int       // Number of candidate locations at the current temperature
+	started bool      // Indicator that the function value at x is known
+	x       []float64 // Current location of the walk
+	f       float64   // Function value at x
+	bestX   []float64
+	bestF   float64
+}
+
+func (sa *SimulatedAnnealing) Status() (Status, error) {
+	return sa.status, nil
+}
+
+func (*SimulatedAnnealing) Uses(has Available) (uses Available, err error) {
+	return has.boundedFunction()
+}
+
+// SetBounds sets the bounds on the variables. It implements the Bounder
+// interface.
+func (sa *SimulatedAnnealing) SetBounds(bounds []Bound) {
+	sa.bounds = bounds
+}
+
+func (sa *SimulatedAnnealing) Init(dim, tasks int) int {
+	if dim <= 0 {
+		panic(nonpositiveDimension)
+	}
+	if tasks < 0 {
+		panic(negativeTasks)
+	}
+	sa.initTemp = sa.InitTemperature
+	if sa.initTemp == 0 {
+		sa.initTemp = 1
+	} else if sa.initTemp < 0 {
+		panic("annealing: negative initial temperature")
+	}
+	sa.minTemp = sa.MinTemperature
+	if sa.minTemp == 0 {
+		sa.minTemp = 1e-8 * sa.initTemp
+	}
+	sa.proposals = sa.Proposals
+	if sa.proposals == 0 {
+		sa.proposals = 10 * dim
+	} else if sa.proposals < 0 {
+		panic("annealing: negative number of proposals")
+	}
+	sa.cooling = sa.Cooling
+	if sa.cooling == nil {
+		sa.cooling = ExponentialCooling{}
+	}
+	sa.neighbor = sa.Neighbor
+	if sa.neighbor == nil {
+		sa.neighbor = GaussianNeighbor{}
+	}
+	if sa.Src != nil {
+		sa.rnd = rand.New(sa.Src)
+	} else {
+		sa.rnd = rand.New(rand.NewSource(rand.Uint64()))
+	}
+
+	sa.status = NotTerminated
+	sa.temp = sa.initTemp
+	sa.iter = 0
+	sa.count = 0
+	sa.started = false
+	sa.x = resize(sa.x, dim)
+	sa.bestX = resize(sa.bestX, dim)
+	sa.bestF = math.Inf(1)
+	return 1
+}
+
+func (sa *SimulatedAnnealing) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	task := tasks[0]
+	copy(sa.x, task.X)
+	if task.Op&FuncEvaluation != 0 {
+		sa.start(task.F)
+		sa.propose(operation, task)
+	} else {
+		task.Op = FuncEvaluation
+		operation <- task
+	}
+
+Loop:
+	for {
+		task := <-result
+		switch task.Op {
+		default:
+			panic("unknown operation")
+		case PostIteration:
+			break Loop
+		case MajorIteration:
+			sa.propose(operation, task)
+		case FuncEvaluation:
+			sa.evaluated(operation, task)
+		}
+	}
+
+	// PostIteration was sent. Report the last candidate location if it is
+	// better than the best location reported so far.
+	for task := range result {
+		switch task.Op {
+		default:
+			panic("unknown operation")
+		case MajorIteration:
+		case FuncEvaluation:
+			if task.F < sa.bestF {
+				sa.bestF = task.F
+				task.Op = MajorIteration
+				operation <- task
+			}
+		}
+	}
+	close(operation)
+}
+
+// start sets the function value at the initial location of the walk.
+func (sa *SimulatedAnnealing) start(f float64) {
+	sa.started = true
+	sa.f = f
+	sa.bestF = f
+	copy(sa.bestX, sa.x)
+}
+
+// propose sends the evaluation of a new candidate location.
+func (sa *SimulatedAnnealing) propose(operation chan<- Task, task Task) {
+	sa.neighbor.Neighbor(task.X, sa.x, sa.temp, sa.rnd)
+	project(task.X, sa.bounds)
+	task.Op = FuncEvaluation
+	operation <- task
+}
+
+// evaluated applies the Metropolis criterion to the evaluated candidate
+// location in task, and sends the next candidate location or, if all
+// candidate locations at the current temperature have been evaluated,
+// a MajorIteration with the best location.
+func (sa *SimulatedAnnealing) evaluated(operation chan<- Task, task Task) {
+	if !sa.started {
+		sa.start(task.F)
+		sa.propose(operation, task)
+		return
+	}
+	df := task.F - sa.f
+	if df <= 0 || sa.rnd.Float64() < math.Exp(-df/sa.temp) {
+		copy(sa.x, task.X)
+		sa.f = task.F
+		if task.F < sa.bestF {
+			sa.bestF = task.F
+			copy(sa.bestX, task.X)
+		}
+	}
+	sa.count++
+	if sa.count < sa.proposals {
+		sa.propose(operation, task)
+		return
+	}
+
+	sa.count = 0
+	sa.iter++
+	sa.temp = sa.cooling.Temperature(sa.initTemp, sa.iter)
+	task.F = sa.bestF
+	copy(task.X, sa.bestX)
+	task.Op = MajorIteration
+	if sa.temp < sa.minTemp {
+		sa.status = MethodConverge
+		task.Op = MethodDone
+	}
+	operation <- task
+}
+
+// ExponentialCooling is a CoolingSchedule that decreases the temperature
+// geometrically,
+//  T_k = T_0 Rate^k.
+type ExponentialCooling struct {
+	// Rate is the factor by which the temperature is decreased at each
+	// major iteration. Rate must be between 0 and 1, and if Rate is 0,
+	// a default value of 0.9 is used.
+	Rate float64
+}
+
+func (e ExponentialCooling) Temperature(init float64, iter int) float64 {
+	rate := e.Rate
+	if rate == 0 {
+		rate = 0.9
+	}
+	if rate < 0 || rate >= 1 {
+		panic("annealing: cooling rate must be between 0 and 1")
+	}
+	return init * math.Pow(rate, float64(iter))
+}
+
+// LogarithmicCooling is a CoolingSchedule that decreases the temperature
+// logarithmically,
+//  T_k = T_0 log(2) / log(k + 2).
+// Logarithmic cooling is slow, but for sufficiently high initial
+// temperatures it guarantees the convergence of the walk to a global
+// minimum in probability.
+type LogarithmicCooling struct{}
+
+func (LogarithmicCooling) Temperature(init float64, iter int) float64 {
+	return init * math.Ln2 / math.Log(float64(iter)+2)
+}
+
+// FastCooling is a CoolingSchedule that decreases the temperature inversely
+// proportional to the number of major iterations,
+//  T_k = T_0 / (k + 1).
+type FastCooling struct{}
+
+func (FastCooling) Temperature(init float64, iter int) float64 {
+	return init / float64(iter+1)
+}
+
+// GaussianNeighbor is a Neighborer that perturbs each variable by an
+// independent normally distributed random number.
+type GaussianNeighbor struct {
+	// StdDev is the standard deviation of the perturbations. If StdDev is
+	// 0, a default value of 1 is used.
+	StdDev float64
+}
+
+func (g GaussianNeighbor) Neighbor(dst, x []float64, temp float64, rnd *rand.Rand) {
+	std := g.StdDev
+	if std == 0 {
+		std = 1
+	}
+	for i, v := range x {
+		dst[i] = v + std*rnd.NormFloat64()
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+// opRecorder is a Recorder that counts the recorded operations.
+type opRecorder struct {
+	ops map[Operation]int
+}
+
+func (r *opRecorder) Init() error {
+	r.ops = make(map[Operation]int)
+	return nil
+}
+
+func (r *opRecorder) Record(_ *Location, op Operation, _ *Stats) error {
+	r.ops[op]++
+	return nil
+}
+
+func TestSimulatedAnnealing(t *testing.T) {
+	t.Parallel()
+	p := Problem{
+		Func:   functions.Rastrigin{}.Func,
+		Bounds: []Bound{{-5.12, 5.12}, {-5.12, 5.12}},
+	}
+	for _, cooling := range []CoolingSchedule{
+		nil,
+		ExponentialCooling{Rate: 0.95},
+		FastCooling{},
+	} {
+		method := &SimulatedAnnealing{
+			InitTemperature: 10,
+			MinTemperature:  1e-2,
+			Cooling:         cooling,
+			Neighbor:        GaussianNeighbor{StdDev: 1},
+			Src:             rand.NewSource(1),
+		}
+		rec := &opRecorder{}
+		settings := &Settings{
+			Converger: NeverTerminate{},
+			Recorder:  rec,
+		}
+		result, err := Minimize(p, []float64{3.5, -4}, settings, method)
+		if err != nil {
+			t.Errorf("cooling %T: unexpected error: %v", cooling, err)
+			continue
+		}
+		if result.Status != MethodConverge {
+			t.Errorf("cooling %T: unexpected status: got %v, want %v", cooling, result.Status, MethodConverge)
+		}
+		// The basin of the global minimum at the origin is the square
+		// [-0.5, 0.5]².
+		if floats.Norm(result.X, math.Inf(1)) > 0.5 {
+			t.Errorf("cooling %T: global minimum not found: got f(%v) = %v", cooling, result.X, result.F)
+		}
+		if err := checkBounds(p.Bounds, result.X); err != nil {
+			t.Errorf("cooling %T: optimum outside bounds: %v", cooling, result.X)
+		}
+		if rec.ops[MajorIteration] != result.MajorIterations {
+			t.Errorf("cooling %T: recorded major iterations mismatch: got %d, want %d", cooling, rec.ops[MajorIteration], result.MajorIterations)
+		}
+		if result.FuncEvaluations != 20*(result.MajorIterations+1)+1 {
+			t.Errorf("cooling %T: unexpected number of evaluations: got %d, want %d", cooling, result.FuncEvaluations, 20*(result.MajorIterations+1)+1)
+		}
+
+		// The run is reproducible with the same source.
+		method.Src = rand.NewSource(1)
+		again, err := Minimize(p, []float64{3.5, -4}, &Settings{Converger: NeverTerminate{}}, method)
+		if err != nil {
+			t.Errorf("cooling %T: unexpected error: %v", cooling, err)
+			continue
+		}
+		if again.F != result.F || !floats.Equal(again.X, result.X) {
+			t.Errorf("cooling %T: run not reproducible: got f(%v) = %v, want f(%v) = %v", cooling, again.X, again.F, result.X, result.F)
+		}
+	}
+}
+
+func TestSimulatedAnnealingLimits(t *testing.T) {
+	t.Parallel()
+	p := Problem{Func: functions.ExtendedRosenbrock{}.Func}
+	method := &SimulatedAnnealing{Src: rand.NewSource(1)}
+	result, err := Minimize(p, []float64{-1.2, 1}, &Settings{FuncEvaluations: 55, Converger: NeverTerminate{}}, method)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != FunctionEvaluationLimit {
+		t.Errorf("unexpected status: got %v, want %v", result.Status, FunctionEvaluationLimit)
+	}
+	if result.FuncEvaluations != 55 {
+		t.Errorf("unexpected number of evaluations: got %d, want 55", result.FuncEvaluations)
+	}
+	if f := p.Func(result.X); f != result.F || f > p.Func([]float64{-1.2, 1}) {
+		t.Errorf("unexpected optimum: got f(%v) = %v", result.X, result.F)
+	}
+
+	if !panics(func() { Minimize(p, []float64{0, 0}, nil, &SimulatedAnnealing{InitTemperature: -1}) }) {
+		t.Errorf("no panic for negative initial temperature")
+	}
+}
+
+func TestCoolingSchedules(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		cooling CoolingSchedule
+		want    []float64
+	}{
+		{ExponentialCooling{}, []float64{2, 1.8, 1.62}},
+		{ExponentialCooling{Rate: 0.5}, []float64{2, 1, 0.5}},
+		{LogarithmicCooling{}, []float64{2, 2 * math.Ln2 / math.Log(3), 1}},
+		{FastCooling{}, []float64{2, 1, 2.0 / 3}},
+	} {
+		for iter, want := range test.want {
+			got := test.cooling.Temperature(2, iter)
+			if math.Abs(got-want) > 1e-14 {
+				t.Errorf("%#v: unexpected temperature at iteration %d: got %v, want %v", test.cooling, iter, got, want)
+			}
+		}
+	}
+}
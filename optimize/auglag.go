@@ -0,0 +1,473 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+var (
+	_ Method               = (*AugmentedLagrangian)(nil)
+	_ Statuser             = (*AugmentedLagrangian)(nil)
+	_ Bounder              = (*AugmentedLagrangian)(nil)
+	_ LinearConstrainer    = (*AugmentedLagrangian)(nil)
+	_ NonlinearConstrainer = (*AugmentedLagrangian)(nil)
+	_ MultiplierReporter   = (*AugmentedLagrangian)(nil)
+)
+
+// AugmentedLagrangian implements the augmented Lagrangian method for
+// minimization subject to nonlinear constraints, linear constraints and bound
+// constraints on the variables. The constrained problem is converted into
+// a sequence of subproblems of minimizing the augmented Lagrangian
+//  L_A(x) = f(x) + Σ_E (λ_i c_i(x) + μ/2 c_i(x)²)
+//                + Σ_I (max(0, λ_i + μ c_i(x))² - λ_i²) / (2μ)
+// with the multiplier estimates λ and the penalty parameter μ held fixed.
+// The subproblems are solved by Method. After every subproblem either the
+// multiplier estimates are updated if the constraint violation has decreased
+// sufficiently, or the penalty parameter is increased. See
+//  Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer (2006),
+//  algorithm 17.4
+// for the details of the method.
+//
+// The bounds of the Problem are passed to Method if it supports them, and
+// are treated as inequality constraints otherwise. The subproblems are
+// otherwise unconstrained. All evaluations of the objective function are
+// performed by Minimize, and every subproblem corresponds to one major
+// iteration of AugmentedLagrangian. The iterates are not necessarily feasible.
+//
+// AugmentedLagrangian concludes when the constraint violation is below
+// ConstraintTolerance and the subproblem has been solved to GradStopThreshold.
+// The final status is GradientThreshold if Method uses the gradient, and
+// MethodConverge otherwise.
+type AugmentedLagrangian struct {
+	// Method is the Method used to minimize the subproblems. Method must
+	// not use the Hessian. If Method is nil, it is defaulted to BFGS if the
+	// gradient is available and to NelderMead otherwise.
+	Method Method
+	// InitPenalty is the initial value of the penalty parameter. If
+	// InitPenalty is 0, it is defaulted to 10.
+	InitPenalty float64
+	// PenaltyGrowth is the factor by which the penalty parameter is
+	// increased when the constraint violation does not decrease
+	// sufficiently. It must be greater than one, and if PenaltyGrowth is 0,
+	// it is defaulted to 10.
+	PenaltyGrowth float64
+	// GradStopThreshold sets the threshold on the infinity norm of the
+	// gradient of the augmented Lagrangian at the solution of the final
+	// subproblem. If GradStopThreshold is 0, it is defaulted to 1e-8.
+	GradStopThreshold float64
+	// ConstraintTolerance sets the threshold on the constraint violation at
+	// the final location. If ConstraintTolerance is 0, it is defaulted to
+	// 1e-8.
+	ConstraintTolerance float64
+
+	status Status
+	err    error
+
+	cons        constraintSet
+	bounds      []Bound // Bounds of the Problem
+	method      Method  // Method solving the subproblems
+	uses        Available
+	innerBounds bool // Indicator that the bounds are passed to method
+	started     bool
+
+	dim     int
+	x       []float64 // Solution of the last subproblem
+	cE, cI  []float64 // Constraints at the last evaluated location
+	lamE    []float64 // Multiplier estimates of the equality constraints
+	lamI    []float64 // Multiplier estimates of the inequality constraints
+	gradL   []float64 // Gradient of the Lagrangian at x if the bounds are passed to method
+	penalty float64
+	omega   float64 // Gradient tolerance of the current subproblem
+	eta     float64 // Constraint violation tolerance of the current subproblem
+}
+
+func (a *AugmentedLagrangian) Status() (Status, error) {
+	return a.status, a.err
+}
+
+func (a *AugmentedLagrangian) Uses(has Available) (uses Available, err error) {
+	if has.Project {
+		return Available{}, ErrUnsupportedProjection
+	}
+	a.method = a.Method
+	if a.method == nil {
+		if has.Grad {
+			a.method = &BFGS{}
+		} else {
+			a.method = &NelderMead{}
+		}
+	}
+	a.innerBounds = false
+	innerUses, err := a.method.Uses(Available{Grad: has.Grad, Bounds: has.Bounds})
+	if err == nil && has.Bounds && innerUses.Bounds {
+		if _, ok := a.method.(Bounder); ok {
+			a.innerBounds = true
+		}
+	}
+	if !a.innerBounds {
+		innerUses, err = a.method.Uses(Available{Grad: has.Grad})
+	}
+	if err != nil {
+		return Available{}, err
+	}
+	if innerUses.Hess {
+		return Available{}, ErrMissingHess
+	}
+	a.uses = Available{
+		Grad:      innerUses.Grad,
+		Bounds:    has.Bounds,
+		Linear:    has.Linear,
+		Nonlinear: has.Nonlinear,
+	}
+	return a.uses, nil
+}
+
+// SetBounds sets the bounds on the variables. It implements the Bounder
+// interface.
+func (a *AugmentedLagrangian) SetBounds(bounds []Bound) {
+	a.bounds = bounds
+}
+
+// SetLinearConstraints sets the linear constraints on the variables. It
+// implements the LinearConstrainer interface.
+func (a *AugmentedLagrangian) SetLinearConstraints(c *LinearConstraints) {
+	a.cons.linear = c
+}
+
+// SetNonlinearConstraints sets the nonlinear constraints on the variables.
+// It implements the NonlinearConstrainer interface.
+func (a *AugmentedLagrangian) SetNonlinearConstraints(c *NonlinearConstraints) {
+	a.cons.nonlinear = c
+}
+
+// Multipliers returns the estimates of the Lagrange multipliers of the
+// constraints at the last major iteration. It implements the
+// MultiplierReporter interface.
+func (a *AugmentedLagrangian) Multipliers() *Multipliers {
+	if !a.started {
+		return nil
+	}
+	m := a.cons.multipliers(a.lamE, a.lamI)
+	if !a.innerBounds {
+		return m
+	}
+	// The multipliers of the bounds are estimated from the gradient of
+	// the Lagrangian at the last location.
+	m.Lower = make([]float64, a.dim)
+	m.Upper = make([]float64, a.dim)
+	if a.gradL == nil {
+		return m
+	}
+	for i, b := range a.bounds {
+		if a.x[i] <= b.Min {
+			m.Lower[i] = math.Max(0, a.gradL[i])
+		}
+		if a.x[i] >= b.Max {
+			m.Upper[i] = math.Max(0, -a.gradL[i])
+		}
+	}
+	return m
+}
+
+func (a *AugmentedLagrangian) Init(dim, tasks int) int {
+	if dim <= 0 {
+		panic(nonpositiveDimension)
+	}
+	if tasks < 0 {
+		panic(negativeTasks)
+	}
+	if a.method == nil {
+		panic("auglag: Uses must be called before Init")
+	}
+	if a.InitPenalty == 0 {
+		a.InitPenalty = 10
+	}
+	if a.PenaltyGrowth == 0 {
+		a.PenaltyGrowth = 10
+	}
+	if a.PenaltyGrowth <= 1 {
+		panic("auglag: PenaltyGrowth must be greater than 1")
+	}
+	if a.GradStopThreshold == 0 {
+		a.GradStopThreshold = 1e-8
+	}
+	if a.ConstraintTolerance == 0 {
+		a.ConstraintTolerance = 1e-8
+	}
+	a.status = NotTerminated
+	a.err = nil
+	a.dim = dim
+	a.cons.bounds = nil
+	if !a.innerBounds {
+		a.cons.bounds = a.bounds
+	}
+	a.cons.init(dim)
+	a.x = resize(a.x, dim)
+	a.cE = resize(a.cE, a.cons.mE)
+	a.cI = resize(a.cI, a.cons.mI)
+	a.lamE = resize(a.lamE, a.cons.mE)
+	a.lamI = resize(a.lamI, a.cons.mI)
+	for i := range a.lamE {
+		a.lamE[i] = 0
+	}
+	for i := range a.lamI {
+		a.lamI[i] = 0
+	}
+	a.gradL = nil
+	a.penalty = a.InitPenalty
+	a.omega = 1 / a.penalty
+	a.eta = 1 / math.Pow(a.penalty, 0.1)
+	a.started = true
+	return 1
+}
+
+func (a *AugmentedLagrangian) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	task := tasks[0]
+	copy(a.x, task.X)
+	var stalled bool
+	for {
+		gradNorm, done := a.solve(operation, result, task)
+		if done {
+			break
+		}
+		moved := !floats.Equal(task.X, a.x)
+		copy(a.x, task.X)
+
+		// Report the solution of the subproblem as a major iteration.
+		op := FuncEvaluation
+		if a.uses.Grad {
+			op |= GradEvaluation
+		}
+		task.Op = op
+		operation <- task
+		task = <-result
+		if task.Op == PostIteration {
+			break
+		}
+		a.cons.eval(a.cE, a.cI, task.X)
+		viol := a.violation()
+		a.lagrangianGrad(task.Location)
+		task.Op = MajorIteration
+		operation <- task
+		task = <-result
+		if task.Op == PostIteration {
+			break
+		}
+
+		if viol <= a.ConstraintTolerance && (!a.uses.Grad || gradNorm <= a.GradStopThreshold) {
+			a.status = GradientThreshold
+			if !a.uses.Grad {
+				a.status = MethodConverge
+			}
+			a.update()
+			task.Op = MethodDone
+			operation <- task
+			task = <-result
+			break
+		}
+		if !moved && stalled {
+			a.status = Failure
+			a.err = ErrNoProgress
+			task.Op = MethodDone
+			operation <- task
+			task = <-result
+			break
+		}
+		stalled = !moved
+
+		if viol <= a.eta {
+			// The constraint violation has decreased sufficiently, update
+			// the multipliers and tighten the tolerances.
+			a.update()
+			a.eta /= math.Pow(a.penalty, 0.9)
+			a.omega /= a.penalty
+		} else {
+			a.penalty *= a.PenaltyGrowth
+			a.eta = 1 / math.Pow(a.penalty, 0.1)
+			a.omega = 1 / a.penalty
+		}
+		a.eta = math.Max(a.eta, a.ConstraintTolerance)
+		a.omega = math.Max(a.omega, a.GradStopThreshold)
+	}
+	// Guarantee that result is closed before operation is closed.
+	for range result {
+	}
+	close(operation)
+}
+
+// violation returns the measure of the violation of the constraints and of
+// the complementarity at the last evaluated location.
+func (a *AugmentedLagrangian) violation() float64 {
+	var v float64
+	for _, c := range a.cE {
+		v = math.Max(v, math.Abs(c))
+	}
+	for i, c := range a.cI {
+		v = math.Max(v, math.Abs(math.Max(c, -a.lamI[i]/a.penalty)))
+	}
+	return v
+}
+
+// update updates the multiplier estimates using the constraints at the last
+// evaluated location.
+func (a *AugmentedLagrangian) update() {
+	for i, c := range a.cE {
+		a.lamE[i] += a.penalty * c
+	}
+	for i, c := range a.cI {
+		a.lamI[i] = math.Max(0, a.lamI[i]+a.penalty*c)
+	}
+}
+
+// augment replaces the values of the objective function and of its gradient
+// evaluated by op at loc with the values of the augmented Lagrangian.
+func (a *AugmentedLagrangian) augment(loc *Location, op Operation) {
+	a.cons.eval(a.cE, a.cI, loc.X)
+	if op&FuncEvaluation != 0 {
+		f := loc.F
+		for i, c := range a.cE {
+			f += a.lamE[i]*c + 0.5*a.penalty*c*c
+		}
+		for i, c := range a.cI {
+			l := a.lamI[i]
+			m := math.Max(0, l+a.penalty*c)
+			f += (m*m - l*l) / (2 * a.penalty)
+		}
+		loc.F = f
+	}
+	if op&GradEvaluation != 0 {
+		a.cons.jacobians(loc.X)
+		for i, c := range a.cE {
+			floats.AddScaled(loc.Gradient, a.lamE[i]+a.penalty*c, a.cons.jE[i])
+		}
+		for i, c := range a.cI {
+			if m := a.lamI[i] + a.penalty*c; m > 0 {
+				floats.AddScaled(loc.Gradient, m, a.cons.jI[i])
+			}
+		}
+	}
+}
+
+// lagrangianGrad stores the gradient of the Lagrangian at loc with the
+// multiplier estimates that follow from the constraints at loc. The gradient
+// is used for estimating the multipliers of the bounds passed to Method.
+func (a *AugmentedLagrangian) lagrangianGrad(loc *Location) {
+	if !a.uses.Grad || !a.innerBounds {
+		return
+	}
+	a.gradL = resize(a.gradL, a.dim)
+	copy(a.gradL, loc.Gradient)
+	a.cons.jacobians(loc.X)
+	for i, c := range a.cE {
+		floats.AddScaled(a.gradL, a.lamE[i]+a.penalty*c, a.cons.jE[i])
+	}
+	for i, c := range a.cI {
+		if m := a.lamI[i] + a.penalty*c; m > 0 {
+			floats.AddScaled(a.gradL, m, a.cons.jI[i])
+		}
+	}
+}
+
+// solve minimizes the augmented Lagrangian with the current multiplier
+// estimates and penalty parameter starting from a.x, forwarding the
+// evaluations commanded by the Method to operation. On return, task.X holds
+// the solution of the subproblem. solve returns the infinity norm of the
+// gradient of the augmented Lagrangian at the solution, and whether the
+// caller of AugmentedLagrangian has signaled the end of the optimization.
+// If the bounds are passed to the Method, the norm of the projected gradient
+// is returned instead.
+func (a *AugmentedLagrangian) solve(operation chan<- Task, result <-chan Task, task Task) (gradNorm float64, done bool) {
+	if b, ok := a.method.(Bounder); ok {
+		if a.innerBounds {
+			b.SetBounds(a.bounds)
+		} else {
+			b.SetBounds(nil)
+		}
+	}
+	if c, ok := a.method.(LinearConstrainer); ok {
+		c.SetLinearConstraints(nil)
+	}
+	if c, ok := a.method.(NonlinearConstrainer); ok {
+		c.SetNonlinearConstraints(nil)
+	}
+	a.method.Init(a.dim, 1)
+	inner := Task{Location: newLocation(a.dim)}
+	copy(inner.X, a.x)
+	copy(task.X, a.x)
+	innerOperation := make(chan Task, 1)
+	innerResult := make(chan Task, 1)
+	go a.method.Run(innerOperation, innerResult, []Task{inner})
+
+	var bounds []Bound
+	if a.innerBounds {
+		bounds = a.bounds
+	}
+	converger := defaultFunctionConverge()
+	converger.Init(a.dim)
+	gradNorm = math.Inf(1)
+	var stopping bool
+	stop := func() {
+		stopping = true
+		innerResult <- Task{Op: PostIteration}
+		close(innerResult)
+	}
+	for t := range innerOperation {
+		if stopping {
+			// Major iterations sent during the shutdown of the method
+			// still update the solution of the subproblem.
+			if t.Op == MajorIteration {
+				copy(task.X, t.X)
+			}
+			continue
+		}
+		switch t.Op {
+		case NoOperation:
+			innerResult <- t
+		case MethodDone:
+			stop()
+		case MajorIteration:
+			copy(task.X, t.X)
+			if t.Gradient != nil {
+				gradNorm = projGradNorm(t.X, t.Gradient, bounds)
+				if gradNorm <= a.omega {
+					stop()
+					continue
+				}
+			}
+			if converger.Converged(t.Location) != NotTerminated {
+				stop()
+				continue
+			}
+			innerResult <- t
+		default:
+			if !t.Op.isEvaluation() || t.Op&HessEvaluation != 0 {
+				panic("auglag: unexpected operation")
+			}
+			copy(task.X, t.X)
+			task.Op = t.Op
+			operation <- task
+			r := <-result
+			if r.Op == PostIteration {
+				done = true
+				stop()
+				continue
+			}
+			task = r
+			if t.Op&FuncEvaluation != 0 {
+				t.F = r.F
+			}
+			if t.Op&GradEvaluation != 0 {
+				t.Gradient = resize(t.Gradient, a.dim)
+				copy(t.Gradient, r.Gradient)
+			}
+			a.augment(t.Location, t.Op)
+			innerResult <- t
+		}
+	}
+	return gradNorm, done
+}
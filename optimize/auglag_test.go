@@ -0,0 +1,169 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestAugmentedLagrangian(t *testing.T) {
+	t.Parallel()
+	inf := math.Inf(1)
+	quad := weightedQuadratic([]float64{1, 1}, []float64{1, 2})
+	eq, eqJac := circle(1)
+	hs, hsCons := hs071()
+	for _, test := range []struct {
+		name       string
+		p          Problem
+		nonlinear  *NonlinearConstraints
+		linear     *LinearConstraints
+		bounds     []Bound
+		method     Method
+		x          []float64
+		want       []float64
+		wantStatus Status
+		tol        float64
+	}{
+		{
+			name:       "equality",
+			p:          quad,
+			nonlinear:  &NonlinearConstraints{NumEq: 1, Eq: eq, EqJac: eqJac},
+			x:          []float64{0, 0.5},
+			want:       []float64{1 / math.Sqrt(5), 2 / math.Sqrt(5)},
+			wantStatus: GradientThreshold,
+			tol:        1e-7,
+		},
+		{
+			name:       "active inequality CG",
+			p:          quad,
+			nonlinear:  &NonlinearConstraints{NumIneq: 1, Ineq: eq, IneqJac: eqJac},
+			method:     &CG{},
+			x:          []float64{3, -3},
+			want:       []float64{1 / math.Sqrt(5), 2 / math.Sqrt(5)},
+			wantStatus: GradientThreshold,
+			tol:        1e-7,
+		},
+		{
+			name:       "inactive inequality",
+			p:          weightedQuadratic([]float64{1, 1}, []float64{0.25, 0.5}),
+			nonlinear:  &NonlinearConstraints{NumIneq: 1, Ineq: eq, IneqJac: eqJac},
+			x:          []float64{0, 0},
+			want:       []float64{0.25, 0.5},
+			wantStatus: GradientThreshold,
+			tol:        1e-7,
+		},
+		{
+			name: "linear",
+			p:    weightedQuadratic([]float64{1, 1, 1}, []float64{1, 2, 3}),
+			linear: &LinearConstraints{
+				A: mat.NewDense(1, 3, []float64{1, 1, 1}),
+				B: []float64{3},
+			},
+			x:          []float64{3, 0, 0},
+			want:       []float64{0, 1, 2},
+			wantStatus: GradientThreshold,
+			tol:        1e-7,
+		},
+		{
+			name:       "bounds as constraints",
+			p:          quad,
+			nonlinear:  &NonlinearConstraints{NumIneq: 1, Ineq: eq, IneqJac: eqJac},
+			bounds:     []Bound{{-inf, inf}, {-inf, 0.5}},
+			x:          []float64{0, 0},
+			want:       []float64{math.Sqrt(0.75), 0.5},
+			wantStatus: GradientThreshold,
+			tol:        1e-7,
+		},
+		{
+			name:       "bounds passed to method",
+			p:          quad,
+			nonlinear:  &NonlinearConstraints{NumIneq: 1, Ineq: eq, IneqJac: eqJac},
+			bounds:     []Bound{{-inf, inf}, {-inf, 0.5}},
+			method:     &LBFGSB{},
+			x:          []float64{0, 0},
+			want:       []float64{math.Sqrt(0.75), 0.5},
+			wantStatus: GradientThreshold,
+			tol:        1e-7,
+		},
+		{
+			name:       "hs071",
+			p:          hs,
+			nonlinear:  hsCons,
+			method:     &LBFGSB{},
+			x:          []float64{1, 5, 5, 1},
+			want:       []float64{1, 4.742999, 3.821150, 1.379408},
+			wantStatus: GradientThreshold,
+			tol:        1e-5,
+		},
+		{
+			name: "gradient-free",
+			p: Problem{
+				Func: quad.Func,
+			},
+			nonlinear:  &NonlinearConstraints{NumEq: 1, Eq: eq, EqJac: eqJac},
+			x:          []float64{0, 0.5},
+			want:       []float64{1 / math.Sqrt(5), 2 / math.Sqrt(5)},
+			wantStatus: MethodConverge,
+			tol:        1e-4,
+		},
+	} {
+		p := test.p
+		p.Nonlinear = test.nonlinear
+		p.Linear = test.linear
+		if test.bounds != nil {
+			p.Bounds = test.bounds
+		}
+		x := make([]float64, len(test.x))
+		copy(x, test.x)
+		result, err := Minimize(p, x, nil, &AugmentedLagrangian{Method: test.method})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if result.Status != test.wantStatus {
+			t.Errorf("%s: unexpected status: got %v, want %v", test.name, result.Status, test.wantStatus)
+		}
+		if !floats.EqualApprox(result.X, test.want, test.tol) {
+			t.Errorf("%s: unexpected optimum: got %v, want %v", test.name, result.X, test.want)
+		}
+		if test.wantStatus == GradientThreshold && result.ConstraintViolation > 1e-8 {
+			t.Errorf("%s: unexpected constraint violation: %v", test.name, result.ConstraintViolation)
+		}
+		if err := checkBounds(p.Bounds, result.X); test.method != nil && err != nil {
+			t.Errorf("%s: optimum outside bounds passed to method: %v", test.name, result.X)
+		}
+		if result.Multipliers == nil {
+			t.Errorf("%s: no multipliers reported", test.name)
+		}
+	}
+}
+
+func TestAugmentedLagrangianMultipliers(t *testing.T) {
+	t.Parallel()
+	eq, eqJac := circle(1)
+	p := weightedQuadratic([]float64{1, 1}, []float64{1, 2})
+	p.Nonlinear = &NonlinearConstraints{NumEq: 1, Eq: eq, EqJac: eqJac}
+	result, err := Minimize(p, []float64{3, 3}, nil, &AugmentedLagrangian{Method: &LBFGS{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := math.Sqrt(5) - 1
+	if got := result.Multipliers.Eq[0]; math.Abs(got-want) > 1e-6 {
+		t.Errorf("unexpected multiplier: got %v, want %v", got, want)
+	}
+
+	// The major iterations correspond to the subproblems.
+	if result.MajorIterations > 20 {
+		t.Errorf("unexpected number of major iterations: %d", result.MajorIterations)
+	}
+
+	if !panics(func() { Minimize(p, []float64{3, 3}, nil, &AugmentedLagrangian{Method: &Newton{}}) }) {
+		t.Errorf("no panic for method using the Hessian")
+	}
+}
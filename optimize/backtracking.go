@@ -10,7 +10,10 @@ const (
 	minimumBacktrackingStepSize    = 1e-20
 )
 
-var _ Linesearcher = (*Backtracking)(nil)
+var (
+	_ Linesearcher  = (*Backtracking)(nil)
+	_ NoiseTolerant = (*Backtracking)(nil)
+)
 
 // Backtracking is a Linesearcher that uses backtracking to find a point that
 // satisfies the Armijo condition with the given decrease factor. If the Armijo
@@ -33,6 +36,8 @@ type Backtracking struct {
 	initF    float64
 	initG    float64
 
+	noise float64 // Estimate of the noise in the function values
+
 	lastOp Operation
 }
 
@@ -70,7 +75,7 @@ func (b *Backtracking) Iterate(f, _ float64) (Operation, float64, error) {
 		panic("backtracking: Init has not been called")
 	}
 
-	if ArmijoConditionMet(f, b.initF, b.initG, b.stepSize, b.DecreaseFactor) {
+	if ArmijoConditionMet(f, b.initF+2*b.noise, b.initG, b.stepSize, b.DecreaseFactor) {
 		b.lastOp = MajorIteration
 		return b.lastOp, b.stepSize, nil
 	}
@@ -82,3 +87,10 @@ func (b *Backtracking) Iterate(f, _ float64) (Operation, float64, error) {
 	b.lastOp = FuncEvaluation
 	return b.lastOp, b.stepSize, nil
 }
+
+// SetFunctionNoise sets the estimate of the noise in the function values. The
+// sufficient decrease condition is relaxed by twice the noise. It implements
+// the NoiseTolerant interface.
+func (b *Backtracking) SetFunctionNoise(noise float64) {
+	b.noise = noise
+}
@@ -0,0 +1,73 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bayesopt
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+var (
+	_ Acquisition = ExpectedImprovement{}
+	_ Acquisition = UpperConfidenceBound{}
+)
+
+// Acquisition scores a candidate location from the posterior of the surrogate
+// model at the location. Higher scores indicate more promising locations.
+type Acquisition interface {
+	// Score returns the score of a location where the posterior of the
+	// objective function has the given mean and standard deviation, and
+	// best is the lowest function value observed so far. Score also
+	// returns the partial derivatives of the score with respect to mean and
+	// std.
+	Score(mean, std, best float64) (score, dmean, dstd float64)
+}
+
+// ExpectedImprovement is the expected improvement acquisition function,
+//  EI(x) = E[max(best - Xi - f(x), 0)],
+// where the expectation is over the posterior of f(x).
+type ExpectedImprovement struct {
+	// Xi is a non-negative margin that is subtracted from the best
+	// function value to favor exploration.
+	Xi float64
+}
+
+func (e ExpectedImprovement) Score(mean, std, best float64) (score, dmean, dstd float64) {
+	d := best - e.Xi - mean
+	if std == 0 {
+		if d > 0 {
+			return d, -1, 0
+		}
+		return 0, 0, 0
+	}
+	z := d / std
+	cdf := distuv.UnitNormal.CDF(z)
+	pdf := distuv.UnitNormal.Prob(z)
+	return d*cdf + std*pdf, -cdf, pdf
+}
+
+// UpperConfidenceBound is the confidence bound acquisition function. For
+// minimization it scores a location by the negated lower confidence bound of
+// the function value,
+//  UCB(x) = -(μ(x) - Kappa σ(x)),
+// where μ and σ are the posterior mean and standard deviation of f(x).
+type UpperConfidenceBound struct {
+	// Kappa is the weight of the standard deviation that trades off
+	// exploration against exploitation. If Kappa is 0, a default value of
+	// 2 is used.
+	Kappa float64
+}
+
+func (u UpperConfidenceBound) Score(mean, std, best float64) (score, dmean, dstd float64) {
+	kappa := u.Kappa
+	if kappa == 0 {
+		kappa = 2
+	}
+	if kappa < 0 || math.IsNaN(kappa) {
+		panic("bayesopt: negative Kappa")
+	}
+	return kappa*std - mean, -1, kappa
+}
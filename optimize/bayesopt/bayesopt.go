@@ -0,0 +1,370 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bayesopt
+
+import (
+	"errors"
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+	"gonum.org/v1/gonum/stat/distmv"
+	"gonum.org/v1/gonum/stat/samplemv"
+)
+
+var (
+	_ optimize.Method   = (*Optimizer)(nil)
+	_ optimize.Statuser = (*Optimizer)(nil)
+	_ optimize.Bounder  = (*Optimizer)(nil)
+)
+
+// ErrMissingBounds signifies that the Problem does not specify the bounds
+// of the search domain needed by Optimizer.
+var ErrMissingBounds = errors.New("bayesopt: problem does not provide needed Bounds")
+
+// Optimizer is an optimize.Method that performs Bayesian optimization of
+// an expensive objective function within finite bounds. The function values
+// observed so far are modeled by a Gaussian process, and the next location
+// is the maximizer of the Acquisition function of the posterior, which is
+// found by local optimization of the cheap surrogate from several starting
+// locations. See
+//  Jones, D. R., Schonlau, M., Welch, W. J.: Efficient global optimization
+//  of expensive black-box functions. J Glob Optim 13 (1998), 455-492
+// for more information.
+//
+// The optimization starts with the evaluation of the initial location and
+// of a Latin hypercube design of InitSamples locations. Each subsequent major
+// iteration evaluates one location, and the location of the major iteration
+// is the best location found so far. Optimizer does not terminate on its own,
+// and the number of evaluations should be limited by Settings.FuncEvaluations.
+// Optimizer evaluates the function sequentially, and the cost of choosing
+// a location grows with the cube of the number of evaluations, so it is
+// intended for functions that can be evaluated at most a few hundred times.
+//
+// The Problem must have finite bounds on all variables. The surrogate model
+// is defined on the locations scaled to the unit hypercube and on the
+// standardized function values.
+type Optimizer struct {
+	// InitSamples is the number of locations of the initial Latin
+	// hypercube design. If InitSamples is 0, a default value of twice the
+	// dimension is used. InitSamples cannot be negative, or Optimizer will
+	// panic.
+	InitSamples int
+	// Kernel is the covariance function of the Gaussian process. If Kernel
+	// is nil, a Matern52 kernel with unit variance is used, and its length
+	// scale is chosen at every iteration to maximize the likelihood of the
+	// observations.
+	Kernel Kernel
+	// Noise is the variance of the observation noise relative to the
+	// variance of the observed function values. If Noise is 0, a default
+	// value of 1e-6 is used.
+	Noise float64
+	// Acquisition is the acquisition function. If Acquisition is nil,
+	// a default value of ExpectedImprovement{} is used.
+	Acquisition Acquisition
+	// Local is the method used to maximize the acquisition function. Local
+	// must support bound constraints. If Local is nil, a default value of
+	// &optimize.LBFGSB{} is used.
+	Local optimize.Method
+	// Starts is the number of starting locations for maximizing the
+	// acquisition function. The first starting location is the best
+	// location found so far, and the others are random. If Starts is 0,
+	// a default value of 10 is used.
+	Starts int
+	// Src allows a random number generator to be supplied for generating
+	// the initial design and the starting locations. If Src is nil the
+	// generator in golang.org/x/exp/rand is used.
+	Src rand.Source
+
+	bounds []optimize.Bound
+
+	status      optimize.Status
+	err         error
+	rnd         *rand.Rand
+	dim         int
+	noise       float64
+	acquisition Acquisition
+	local       optimize.Method
+	starts      int
+
+	design [][]float64 // Scaled locations of the initial design not yet sent
+	x      [][]float64 // Scaled locations of the observations
+	y      []float64   // Observed function values
+	bestF  float64
+	bestX  []float64
+	gp     gp
+}
+
+func (o *Optimizer) Status() (optimize.Status, error) {
+	return o.status, o.err
+}
+
+func (*Optimizer) Uses(has optimize.Available) (uses optimize.Available, err error) {
+	if has.Linear {
+		return optimize.Available{}, optimize.ErrUnsupportedLinear
+	}
+	if has.Nonlinear {
+		return optimize.Available{}, optimize.ErrUnsupportedNonlinear
+	}
+	if !has.Bounds {
+		return optimize.Available{}, ErrMissingBounds
+	}
+	return optimize.Available{Bounds: true}, nil
+}
+
+// SetBounds sets the bounds on the variables. It implements the
+// optimize.Bounder interface.
+func (o *Optimizer) SetBounds(bounds []optimize.Bound) {
+	o.bounds = bounds
+}
+
+func (o *Optimizer) Init(dim, tasks int) int {
+	if dim <= 0 {
+		panic("bayesopt: non-positive input dimension")
+	}
+	if tasks < 0 {
+		panic("bayesopt: negative input number of tasks")
+	}
+	if len(o.bounds) != dim {
+		panic("bayesopt: bounds not set")
+	}
+	for _, b := range o.bounds {
+		if math.IsInf(b.Min, 0) || math.IsInf(b.Max, 0) {
+			panic("bayesopt: infinite bounds")
+		}
+	}
+	nInit := o.InitSamples
+	if nInit == 0 {
+		nInit = 2 * dim
+	} else if nInit < 0 {
+		panic("bayesopt: negative number of initial samples")
+	}
+	o.noise = o.Noise
+	if o.noise == 0 {
+		o.noise = 1e-6
+	} else if o.noise < 0 {
+		panic("bayesopt: negative noise")
+	}
+	o.acquisition = o.Acquisition
+	if o.acquisition == nil {
+		o.acquisition = ExpectedImprovement{}
+	}
+	o.local = o.Local
+	if o.local == nil {
+		o.local = &optimize.LBFGSB{}
+	}
+	o.starts = o.Starts
+	if o.starts == 0 {
+		o.starts = 10
+	} else if o.starts < 0 {
+		panic("bayesopt: negative number of starts")
+	}
+	if o.Src != nil {
+		o.rnd = rand.New(o.Src)
+	} else {
+		o.rnd = rand.New(rand.NewSource(rand.Uint64()))
+	}
+
+	o.status = optimize.NotTerminated
+	o.err = nil
+	o.dim = dim
+	o.x = o.x[:0]
+	o.y = o.y[:0]
+	o.bestF = math.Inf(1)
+	o.bestX = make([]float64, dim)
+	o.design = o.design[:0]
+	if nInit > 0 {
+		batch := mat.NewDense(nInit, dim, nil)
+		samplemv.LatinHypercube{Q: distmv.NewUnitUniform(dim, nil), Src: o.rnd}.Sample(batch)
+		for i := 0; i < nInit; i++ {
+			o.design = append(o.design, batch.RawRowView(i))
+		}
+	}
+	return 1
+}
+
+func (o *Optimizer) Run(operation chan<- optimize.Task, result <-chan optimize.Task, tasks []optimize.Task) {
+	task := tasks[0]
+	if task.Op&optimize.FuncEvaluation != 0 {
+		o.observe(task.X, task.F)
+		o.next(operation, task)
+	} else {
+		task.Op = optimize.FuncEvaluation
+		operation <- task
+	}
+
+Loop:
+	for {
+		task := <-result
+		switch task.Op {
+		default:
+			panic("unknown operation")
+		case optimize.PostIteration:
+			break Loop
+		case optimize.MajorIteration:
+			o.next(operation, task)
+		case optimize.FuncEvaluation:
+			o.observe(task.X, task.F)
+			if task.F > o.bestF {
+				task.F = o.bestF
+				copy(task.X, o.bestX)
+			}
+			task.Op = optimize.MajorIteration
+			operation <- task
+		}
+	}
+
+	// PostIteration was sent. Report the last evaluated location if it is
+	// better than the best location reported so far.
+	for task := range result {
+		switch task.Op {
+		default:
+			panic("unknown operation")
+		case optimize.MajorIteration:
+		case optimize.FuncEvaluation:
+			if task.F < o.bestF {
+				o.bestF = task.F
+				task.Op = optimize.MajorIteration
+				operation <- task
+			}
+		}
+	}
+	close(operation)
+}
+
+// observe adds the function value f at x to the observations.
+func (o *Optimizer) observe(x []float64, f float64) {
+	if f < o.bestF {
+		o.bestF = f
+		copy(o.bestX, x)
+	}
+	u := make([]float64, o.dim)
+	for i, b := range o.bounds {
+		if b.Max > b.Min {
+			u[i] = (x[i] - b.Min) / (b.Max - b.Min)
+		}
+	}
+	o.x = append(o.x, u)
+	o.y = append(o.y, f)
+}
+
+// next sends the evaluation of the next location, or MethodDone if the next
+// location cannot be chosen. The task must hold the best location.
+func (o *Optimizer) next(operation chan<- optimize.Task, task optimize.Task) {
+	var u []float64
+	if len(o.design) > 0 {
+		u = o.design[0]
+		o.design = o.design[1:]
+	} else {
+		var err error
+		u, err = o.propose()
+		if err != nil {
+			o.status = optimize.Failure
+			o.err = err
+			task.Op = optimize.MethodDone
+			operation <- task
+			return
+		}
+	}
+	for i, b := range o.bounds {
+		task.X[i] = b.Min + u[i]*(b.Max-b.Min)
+	}
+	task.Op = optimize.FuncEvaluation
+	operation <- task
+}
+
+// propose fits the Gaussian process to the observations and returns the
+// scaled location that maximizes the acquisition function.
+func (o *Optimizer) propose() ([]float64, error) {
+	err := o.fit()
+	if err != nil {
+		return nil, err
+	}
+
+	dim := o.dim
+	dmean := make([]float64, dim)
+	dstd := make([]float64, dim)
+	p := optimize.Problem{
+		Func: func(u []float64) float64 {
+			mean, std := o.gp.predict(nil, nil, u)
+			score, _, _ := o.acquisition.Score(mean, std, o.bestF)
+			return -score
+		},
+		Grad: func(grad, u []float64) {
+			mean, std := o.gp.predict(dmean, dstd, u)
+			_, ds, dt := o.acquisition.Score(mean, std, o.bestF)
+			for i := range grad {
+				grad[i] = -ds*dmean[i] - dt*dstd[i]
+			}
+		},
+		Bounds: make([]optimize.Bound, dim),
+	}
+	for i := range p.Bounds {
+		p.Bounds[i] = optimize.Bound{Min: 0, Max: 1}
+	}
+
+	start := make([]float64, dim)
+	for i, b := range o.bounds {
+		if b.Max > b.Min {
+			start[i] = (o.bestX[i] - b.Min) / (b.Max - b.Min)
+		}
+	}
+	best := math.Inf(1)
+	u := make([]float64, dim)
+	for k := 0; k < o.starts; k++ {
+		if k > 0 {
+			for i := range start {
+				start[i] = o.rnd.Float64()
+			}
+		}
+		res, err := optimize.Minimize(p, start, nil, o.local)
+		if res == nil {
+			return nil, err
+		}
+		if res.F < best {
+			best = res.F
+			copy(u, res.X)
+		}
+	}
+
+	// Evaluating the function again at an observed location gives no new
+	// information, so explore a random location instead.
+	for _, x := range o.x {
+		if floats.Distance(x, u, math.Inf(1)) < 1e-8 {
+			for i := range u {
+				u[i] = o.rnd.Float64()
+			}
+			break
+		}
+	}
+	return u, nil
+}
+
+// fit conditions the Gaussian process on the observations. If Kernel is nil,
+// the length scale of the default kernel maximizes the likelihood on a grid.
+func (o *Optimizer) fit() error {
+	if o.Kernel != nil {
+		return o.gp.fit(o.Kernel, o.noise, o.x, o.y)
+	}
+	bestL := math.NaN()
+	bestLL := math.Inf(-1)
+	for _, l := range floats.LogSpan(make([]float64, 9), 0.02, 2) {
+		err := o.gp.fit(Matern52{Variance: 1, LengthScale: l}, o.noise, o.x, o.y)
+		if err != nil {
+			continue
+		}
+		if ll := o.gp.logLikelihood(); ll > bestLL {
+			bestLL = ll
+			bestL = l
+		}
+	}
+	if math.IsNaN(bestL) {
+		return errNotPositive
+	}
+	return o.gp.fit(Matern52{Variance: 1, LengthScale: bestL}, o.noise, o.x, o.y)
+}
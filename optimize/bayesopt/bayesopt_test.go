@@ -0,0 +1,76 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bayesopt
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestOptimizer(t *testing.T) {
+	t.Parallel()
+	p := optimize.Problem{
+		Func:   functions.BraninHoo{}.Func,
+		Bounds: []optimize.Bound{{Min: -5, Max: 10}, {Min: 0, Max: 15}},
+	}
+	const fmin = 0.397887
+	for _, test := range []struct {
+		name   string
+		method *Optimizer
+		tol    float64
+	}{
+		{"default", &Optimizer{}, 1e-2},
+		{"ucb", &Optimizer{Acquisition: UpperConfidenceBound{}}, 5e-2},
+		{"squared exponential", &Optimizer{Kernel: SquaredExponential{Variance: 1, LengthScale: 0.3}, Starts: 5}, 5e-2},
+	} {
+		var results []*optimize.Result
+		for i := 0; i < 2; i++ {
+			test.method.Src = rand.NewSource(1)
+			settings := &optimize.Settings{
+				FuncEvaluations: 40,
+				Converger:       optimize.NeverTerminate{},
+			}
+			result, err := optimize.Minimize(p, []float64{0, 0}, settings, test.method)
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.name, err)
+				continue
+			}
+			if result.Status != optimize.FunctionEvaluationLimit {
+				t.Errorf("%s: unexpected status: got %v, want %v", test.name, result.Status, optimize.FunctionEvaluationLimit)
+			}
+			if result.FuncEvaluations != 40 {
+				t.Errorf("%s: unexpected number of evaluations: got %d, want 40", test.name, result.FuncEvaluations)
+			}
+			if result.F-fmin > test.tol {
+				t.Errorf("%s: global minimum not found: got f(%v) = %v, want %v", test.name, result.X, result.F, fmin)
+			}
+			if f := p.Func(result.X); f != result.F {
+				t.Errorf("%s: mismatched optimum: f(%v) = %v, got %v", test.name, result.X, f, result.F)
+			}
+			results = append(results, result)
+		}
+		if len(results) == 2 && (results[0].F != results[1].F || !floats.Equal(results[0].X, results[1].X)) {
+			t.Errorf("%s: run not reproducible: got f(%v) = %v, want f(%v) = %v",
+				test.name, results[1].X, results[1].F, results[0].X, results[0].F)
+		}
+	}
+}
+
+func TestOptimizerUses(t *testing.T) {
+	t.Parallel()
+	_, err := (&Optimizer{}).Uses(optimize.Available{})
+	if err != ErrMissingBounds {
+		t.Errorf("unexpected error for unbounded problem: got %v, want %v", err, ErrMissingBounds)
+	}
+	_, err = (&Optimizer{}).Uses(optimize.Available{Bounds: true, Linear: true})
+	if err != optimize.ErrUnsupportedLinear {
+		t.Errorf("unexpected error for linear constraints: got %v, want %v", err, optimize.ErrUnsupportedLinear)
+	}
+}
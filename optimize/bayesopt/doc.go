@@ -0,0 +1,9 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package bayesopt implements Bayesian optimization of expensive black-box
+// functions. The objective function is modeled by a Gaussian process
+// surrogate, and new locations are chosen by maximizing an acquisition
+// function of the surrogate posterior.
+package bayesopt // import "gonum.org/v1/gonum/optimize/bayesopt"
@@ -0,0 +1,208 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bayesopt
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ Kernel = Matern52{}
+	_ Kernel = SquaredExponential{}
+)
+
+// errNotPositive is returned when the covariance matrix of the observations
+// cannot be factorized even after adding a diagonal jitter.
+var errNotPositive = errors.New("bayesopt: covariance matrix not positive definite")
+
+// Kernel is the covariance function of a Gaussian process. The kernel must be
+// stationary, that is, Cov(x, x) must not depend on x.
+type Kernel interface {
+	// Cov returns the covariance between the values of the process at
+	// x and y.
+	Cov(x, y []float64) float64
+
+	// CovGrad stores in dst the gradient of Cov(x, y) with respect to x,
+	// and returns Cov(x, y).
+	CovGrad(dst, x, y []float64) float64
+}
+
+// Matern52 is the Matérn kernel with smoothness parameter 5/2,
+//  k(x, y) = Variance (1 + √5 r + 5/3 r²) exp(-√5 r),
+// where r = |x - y| / LengthScale. The sample paths of the process are twice
+// differentiable.
+type Matern52 struct {
+	Variance    float64
+	LengthScale float64
+}
+
+func (m Matern52) Cov(x, y []float64) float64 {
+	r := floats.Distance(x, y, 2) / m.LengthScale
+	s := math.Sqrt(5) * r
+	return m.Variance * (1 + s + s*s/3) * math.Exp(-s)
+}
+
+func (m Matern52) CovGrad(dst, x, y []float64) float64 {
+	r := floats.Distance(x, y, 2) / m.LengthScale
+	s := math.Sqrt(5) * r
+	e := math.Exp(-s)
+	c := -5.0 / 3 * m.Variance * (1 + s) * e / (m.LengthScale * m.LengthScale)
+	for i := range dst {
+		dst[i] = c * (x[i] - y[i])
+	}
+	return m.Variance * (1 + s + s*s/3) * e
+}
+
+// SquaredExponential is the squared exponential kernel,
+//  k(x, y) = Variance exp(-|x - y|² / (2 LengthScale²)).
+// The sample paths of the process are infinitely differentiable.
+type SquaredExponential struct {
+	Variance    float64
+	LengthScale float64
+}
+
+func (se SquaredExponential) Cov(x, y []float64) float64 {
+	d := floats.Distance(x, y, 2) / se.LengthScale
+	return se.Variance * math.Exp(-d*d/2)
+}
+
+func (se SquaredExponential) CovGrad(dst, x, y []float64) float64 {
+	k := se.Cov(x, y)
+	c := -k / (se.LengthScale * se.LengthScale)
+	for i := range dst {
+		dst[i] = c * (x[i] - y[i])
+	}
+	return k
+}
+
+// gp is a Gaussian process regression model with a constant mean equal to the
+// mean of the observations. The observations are standardized internally.
+type gp struct {
+	kernel Kernel
+	noise  float64
+
+	x     [][]float64
+	mean  float64
+	scale float64
+	y     *mat.VecDense // Standardized observations
+	chol  mat.Cholesky
+	alpha *mat.VecDense // K⁻¹ y
+
+	k    *mat.VecDense
+	v    *mat.VecDense
+	grad [][]float64
+}
+
+// fit conditions the process on the observations y at the locations x. The
+// noise is the variance of the observation noise relative to the variance
+// of y. fit retains x.
+func (g *gp) fit(kernel Kernel, noise float64, x [][]float64, y []float64) error {
+	n := len(x)
+	g.kernel = kernel
+	g.noise = noise
+	g.x = x
+	g.mean = floats.Sum(y) / float64(n)
+	var ss float64
+	for _, v := range y {
+		ss += (v - g.mean) * (v - g.mean)
+	}
+	g.scale = math.Sqrt(ss / float64(n))
+	if g.scale == 0 {
+		g.scale = 1
+	}
+
+	k := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			k.SetSym(i, j, kernel.Cov(x[i], x[j]))
+		}
+	}
+	// Increase the diagonal jitter until the covariance matrix can be
+	// factorized.
+	jitter := noise
+	var ok bool
+	for try := 0; try < 6; try++ {
+		for i := 0; i < n; i++ {
+			k.SetSym(i, i, kernel.Cov(x[i], x[i])+jitter)
+		}
+		if ok = g.chol.Factorize(k); ok {
+			break
+		}
+		jitter = math.Max(10*jitter, 1e-10)
+	}
+	if !ok {
+		return errNotPositive
+	}
+
+	g.y = mat.NewVecDense(n, nil)
+	for i, v := range y {
+		g.y.SetVec(i, (v-g.mean)/g.scale)
+	}
+	// SolveVecTo only reports a Condition error, which is expected when
+	// observations are close together. The jitter keeps the solution
+	// usable, so the error is ignored here and in predict.
+	g.alpha = mat.NewVecDense(n, nil)
+	g.chol.SolveVecTo(g.alpha, g.y)
+
+	g.k = mat.NewVecDense(n, nil)
+	g.v = mat.NewVecDense(n, nil)
+	g.grad = make([][]float64, n)
+	for i := range g.grad {
+		g.grad[i] = make([]float64, len(x[0]))
+	}
+	return nil
+}
+
+// logLikelihood returns the log marginal likelihood of the standardized
+// observations, up to a constant.
+func (g *gp) logLikelihood() float64 {
+	return -0.5*mat.Dot(g.y, g.alpha) - 0.5*g.chol.LogDet()
+}
+
+// predict returns the posterior mean and standard deviation of the process
+// at x. If dmean and dstd are not nil, the gradients of the mean and the
+// standard deviation with respect to x are stored in them.
+func (g *gp) predict(dmean, dstd, x []float64) (mean, std float64) {
+	withGrad := dmean != nil
+	for i, xi := range g.x {
+		var c float64
+		if withGrad {
+			c = g.kernel.CovGrad(g.grad[i], x, xi)
+		} else {
+			c = g.kernel.Cov(x, xi)
+		}
+		g.k.SetVec(i, c)
+	}
+	mean = mat.Dot(g.k, g.alpha)
+	g.chol.SolveVecTo(g.v, g.k)
+	variance := math.Max(g.kernel.Cov(x, x)-mat.Dot(g.k, g.v), 0)
+	std = math.Sqrt(variance)
+
+	if withGrad {
+		for j := range dmean {
+			dmean[j] = 0
+			dstd[j] = 0
+		}
+		for i, gi := range g.grad {
+			floats.AddScaled(dmean, g.alpha.AtVec(i), gi)
+			floats.AddScaled(dstd, g.v.AtVec(i), gi)
+		}
+		// The variance is k(x, x) - kᵀ K⁻¹ k with a constant k(x, x), so
+		// the gradient of the standard deviation is -kᵀ K⁻¹ ∇k / std.
+		if std > 0 {
+			floats.Scale(-g.scale/std, dstd)
+		} else {
+			for j := range dstd {
+				dstd[j] = 0
+			}
+		}
+		floats.Scale(g.scale, dmean)
+	}
+	return g.mean + g.scale*mean, g.scale * std
+}
@@ -0,0 +1,112 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bayesopt
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestKernelGrad(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, kernel := range []Kernel{
+		Matern52{Variance: 2, LengthScale: 0.5},
+		SquaredExponential{Variance: 2, LengthScale: 0.5},
+	} {
+		for trial := 0; trial < 5; trial++ {
+			x := []float64{rnd.Float64(), rnd.Float64(), rnd.Float64()}
+			y := []float64{rnd.Float64(), rnd.Float64(), rnd.Float64()}
+			grad := make([]float64, 3)
+			k := kernel.CovGrad(grad, x, y)
+			if c := kernel.Cov(x, y); math.Abs(k-c) > 1e-14 {
+				t.Errorf("%T: mismatched covariance: CovGrad %v, Cov %v", kernel, k, c)
+			}
+			want := fd.Gradient(nil, func(x []float64) float64 { return kernel.Cov(x, y) }, x, nil)
+			if !floats.EqualApprox(grad, want, 1e-6) {
+				t.Errorf("%T: unexpected gradient: got %v, want %v", kernel, grad, want)
+			}
+		}
+		if c := kernel.Cov([]float64{0.3, 0.1}, []float64{0.3, 0.1}); c != 2 {
+			t.Errorf("%T: unexpected variance: got %v, want 2", kernel, c)
+		}
+	}
+}
+
+func TestGPPredict(t *testing.T) {
+	t.Parallel()
+	x := [][]float64{{0, 0}, {1, 0}, {0, 1}, {0.5, 0.5}, {1, 1}}
+	y := []float64{1, 3, -2, 0.5, 4}
+	var g gp
+	err := g.fit(Matern52{Variance: 1, LengthScale: 0.5}, 1e-10, x, y)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The posterior interpolates noiseless observations.
+	for i, xi := range x {
+		mean, std := g.predict(nil, nil, xi)
+		if math.Abs(mean-y[i]) > 1e-6 || std > 1e-3 {
+			t.Errorf("unexpected prediction at %v: got %v±%v, want %v", xi, mean, std, y[i])
+		}
+	}
+
+	dmean := make([]float64, 2)
+	dstd := make([]float64, 2)
+	for _, u := range [][]float64{{0.2, 0.7}, {0.9, 0.3}, {0.4, 0.45}} {
+		g.predict(dmean, dstd, u)
+		wantMean := fd.Gradient(nil, func(u []float64) float64 {
+			mean, _ := g.predict(nil, nil, u)
+			return mean
+		}, u, nil)
+		wantStd := fd.Gradient(nil, func(u []float64) float64 {
+			_, std := g.predict(nil, nil, u)
+			return std
+		}, u, nil)
+		if !floats.EqualApprox(dmean, wantMean, 1e-5) {
+			t.Errorf("unexpected mean gradient at %v: got %v, want %v", u, dmean, wantMean)
+		}
+		if !floats.EqualApprox(dstd, wantStd, 1e-5) {
+			t.Errorf("unexpected standard deviation gradient at %v: got %v, want %v", u, dstd, wantStd)
+		}
+	}
+}
+
+func TestAcquisition(t *testing.T) {
+	t.Parallel()
+	for _, acq := range []Acquisition{
+		ExpectedImprovement{},
+		ExpectedImprovement{Xi: 0.1},
+		UpperConfidenceBound{},
+		UpperConfidenceBound{Kappa: 0.5},
+	} {
+		for _, test := range []struct{ mean, std float64 }{
+			{0.3, 0.2},
+			{1.5, 0.7},
+			{-0.4, 1},
+		} {
+			_, dmean, dstd := acq.Score(test.mean, test.std, 0.5)
+			const h = 1e-6
+			sp, _, _ := acq.Score(test.mean+h, test.std, 0.5)
+			sm, _, _ := acq.Score(test.mean-h, test.std, 0.5)
+			if want := (sp - sm) / (2 * h); math.Abs(dmean-want) > 1e-6 {
+				t.Errorf("%#v: unexpected mean derivative: got %v, want %v", acq, dmean, want)
+			}
+			sp, _, _ = acq.Score(test.mean, test.std+h, 0.5)
+			sm, _, _ = acq.Score(test.mean, test.std-h, 0.5)
+			if want := (sp - sm) / (2 * h); math.Abs(dstd-want) > 1e-6 {
+				t.Errorf("%#v: unexpected std derivative: got %v, want %v", acq, dstd, want)
+			}
+		}
+	}
+	// The expected improvement of a certain value is the improvement.
+	if ei, _, _ := (ExpectedImprovement{}).Score(0.2, 0, 0.5); ei != 0.3 {
+		t.Errorf("unexpected expected improvement without uncertainty: got %v, want 0.3", ei)
+	}
+}
@@ -5,6 +5,8 @@
 package optimize
 
 import (
+	"bytes"
+	"encoding/gob"
 	"math"
 
 	"gonum.org/v1/gonum/mat"
@@ -14,6 +16,11 @@ var (
 	_ Method          = (*BFGS)(nil)
 	_ localMethod     = (*BFGS)(nil)
 	_ NextDirectioner = (*BFGS)(nil)
+	_ NoiseTolerant   = (*BFGS)(nil)
+	_ Checkpointer    = (*BFGS)(nil)
+	_ WarmStarter     = (*BFGS)(nil)
+
+	_ InverseHessianReporter = (*BFGS)(nil)
 )
 
 // BFGS implements the Broyden–Fletcher–Goldfarb–Shanno optimization method. It
@@ -31,7 +38,8 @@ type BFGS struct {
 	// if it is NaN the setting is not used.
 	GradStopThreshold float64
 
-	ls *LinesearchMethod
+	ls    *LinesearchMethod
+	noise float64 // Estimate of the noise in the function values
 
 	status Status
 	err    error
@@ -44,14 +52,25 @@ type BFGS struct {
 	tmp  mat.VecDense
 
 	invHess *mat.SymDense
+	warm    *mat.SymDense // Initial inverse Hessian set by SetWarmState.
 
 	first bool // Indicator of the first iteration.
+
+	started bool // Indicator that the initial direction has been computed.
+	resume  bool // Indicator that the state has been restored from a checkpoint.
 }
 
 func (b *BFGS) Status() (Status, error) {
 	return b.status, b.err
 }
 
+// SetFunctionNoise sets the estimate of the noise in the function values, which
+// is passed to the Linesearcher if it is NoiseTolerant. It implements the
+// NoiseTolerant interface.
+func (b *BFGS) SetFunctionNoise(noise float64) {
+	b.noise = noise
+}
+
 func (*BFGS) Uses(has Available) (uses Available, err error) {
 	return has.gradient()
 }
@@ -59,6 +78,7 @@ func (*BFGS) Uses(has Available) (uses Available, err error) {
 func (b *BFGS) Init(dim, tasks int) int {
 	b.status = NotTerminated
 	b.err = nil
+	b.started = false
 	return 1
 }
 
@@ -75,8 +95,18 @@ func (b *BFGS) initLocal(loc *Location) (Operation, error) {
 		b.ls = &LinesearchMethod{}
 	}
 	b.ls.Linesearcher = b.Linesearcher
+	setLinesearchNoise(b.Linesearcher, b.noise)
 	b.ls.NextDirectioner = b
 
+	if b.resume {
+		b.resume = false
+		if len(loc.X) != b.dim {
+			panic("bfgs: checkpoint size mismatch")
+		}
+		b.started = true
+		b.warm = nil
+		return b.ls.resume(loc)
+	}
 	return b.ls.Init(loc)
 }
 
@@ -88,6 +118,7 @@ func (b *BFGS) InitDirection(loc *Location, dir []float64) (stepSize float64) {
 	dim := len(loc.X)
 	b.dim = dim
 	b.first = true
+	b.started = true
 
 	x := mat.NewVecDense(dim, loc.X)
 	grad := mat.NewVecDense(dim, loc.Gradient)
@@ -103,12 +134,26 @@ func (b *BFGS) InitDirection(loc *Location, dir []float64) (stepSize float64) {
 	} else {
 		b.invHess = mat.NewSymDense(dim, b.invHess.RawSymmetric().Data[:dim*dim])
 	}
+
+	d := mat.NewVecDense(dim, dir)
+	if b.warm != nil {
+		if b.warm.Symmetric() != dim {
+			panic("bfgs: warm state size mismatch")
+		}
+		// Start with the inverse Hessian approximation of the previous run.
+		b.invHess.CopySym(b.warm)
+		b.warm = nil
+		b.first = false
+		d.MulVec(b.invHess, grad)
+		d.ScaleVec(-1, d)
+		return 1
+	}
+
 	// The values of the inverse Hessian are initialized in the first call to
 	// NextDirection.
 
 	// Initial direction is just negative of the gradient because the Hessian
 	// is an identity matrix.
-	d := mat.NewVecDense(dim, dir)
 	d.ScaleVec(-1, grad)
 	return 1 / mat.Norm(d, 2)
 }
@@ -154,19 +199,7 @@ func (b *BFGS) NextDirection(loc *Location, dir []float64) (stepSize float64) {
 	}
 
 	if math.Abs(sDotY) != 0 {
-		// Update the inverse Hessian according to the formula
-		//
-		//  B_{k+1}^-1 = B_k^-1
-		//             + (s_kᵀ y_k + y_kᵀ B_k^-1 y_k) / (s_kᵀ y_k)^2 * (s_k s_kᵀ)
-		//             - (B_k^-1 y_k s_kᵀ + s_k y_kᵀ B_k^-1) / (s_kᵀ y_k).
-		//
-		// Note that y_kᵀ B_k^-1 y_k is a scalar, and that the third term is a
-		// rank-two update where B_k^-1 y_k is one vector and s_k is the other.
-		yBy := mat.Inner(&b.y, b.invHess, &b.y)
-		b.tmp.MulVec(b.invHess, &b.y)
-		scale := (1 + yBy/sDotY) / sDotY
-		b.invHess.SymRankOne(b.invHess, scale, &b.s)
-		b.invHess.RankTwo(b.invHess, -1/sDotY, &b.tmp, &b.s)
+		updateInverseBFGS(b.invHess, &b.s, &b.y, &b.tmp)
 	}
 
 	// Update the stored BFGS data.
@@ -181,6 +214,26 @@ func (b *BFGS) NextDirection(loc *Location, dir []float64) (stepSize float64) {
 	return 1
 }
 
+// updateInverseBFGS applies the BFGS update with the step s and the change of
+// the gradient y to the inverse Hessian approximation invHess. The curvature
+// sᵀy must not be zero. tmp is used as workspace.
+func updateInverseBFGS(invHess *mat.SymDense, s, y, tmp *mat.VecDense) {
+	// Update the inverse Hessian according to the formula
+	//
+	//  B_{k+1}^-1 = B_k^-1
+	//             + (s_kᵀ y_k + y_kᵀ B_k^-1 y_k) / (s_kᵀ y_k)^2 * (s_k s_kᵀ)
+	//             - (B_k^-1 y_k s_kᵀ + s_k y_kᵀ B_k^-1) / (s_kᵀ y_k).
+	//
+	// Note that y_kᵀ B_k^-1 y_k is a scalar, and that the third term is a
+	// rank-two update where B_k^-1 y_k is one vector and s_k is the other.
+	sDotY := mat.Dot(s, y)
+	yBy := mat.Inner(y, invHess, y)
+	tmp.MulVec(invHess, y)
+	scale := (1 + yBy/sDotY) / sDotY
+	invHess.SymRankOne(invHess, scale, s)
+	invHess.RankTwo(invHess, -1/sDotY, tmp, s)
+}
+
 func (*BFGS) needs() struct {
 	Gradient bool
 	Hessian  bool
@@ -190,3 +243,130 @@ func (*BFGS) needs() struct {
 		Hessian  bool
 	}{true, false}
 }
+
+// bfgsState is the checkpointed state of BFGS.
+type bfgsState struct {
+	Started bool
+	First   bool
+	Dim     int
+	X       []float64
+	Grad    []float64
+	InvHess []float64
+}
+
+// MarshalState returns the state of the inverse Hessian approximation and of
+// the last major iteration. It implements the Checkpointer interface.
+func (b *BFGS) MarshalState() ([]byte, error) {
+	st := bfgsState{Started: b.started}
+	if b.started {
+		st.First = b.first
+		st.Dim = b.dim
+		st.X = b.x.RawVector().Data
+		st.Grad = b.grad.RawVector().Data
+		st.InvHess = b.invHess.RawSymmetric().Data
+	}
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(st)
+	return buf.Bytes(), err
+}
+
+// UnmarshalState restores the state returned by MarshalState. It implements
+// the Checkpointer interface.
+func (b *BFGS) UnmarshalState(data []byte) error {
+	var st bfgsState
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&st)
+	if err != nil {
+		return err
+	}
+	b.resume = st.Started
+	if !st.Started {
+		return nil
+	}
+	if len(st.X) != st.Dim || len(st.Grad) != st.Dim || len(st.InvHess) != st.Dim*st.Dim {
+		return ErrBadCheckpoint
+	}
+	b.first = st.First
+	b.dim = st.Dim
+	b.x.CloneFromVec(mat.NewVecDense(st.Dim, st.X))
+	b.grad.CloneFromVec(mat.NewVecDense(st.Dim, st.Grad))
+	b.invHess = mat.NewSymDense(st.Dim, st.InvHess)
+	b.y.Reset()
+	b.s.Reset()
+	b.tmp.Reset()
+	return nil
+}
+
+// bfgsWarmState is the warm-start state of BFGS.
+type bfgsWarmState struct {
+	Dim     int
+	InvHess []float64
+}
+
+// InverseHessian returns a copy of the inverse Hessian approximation updated
+// with the step to the final location loc if it has positive curvature, or nil
+// if there is no approximation. It implements the InverseHessianReporter
+// interface.
+func (b *BFGS) InverseHessian(loc *Location) *mat.SymDense {
+	if !b.started {
+		return nil
+	}
+	dim := b.dim
+	invHess := mat.NewSymDense(dim, nil)
+	if !b.first {
+		invHess.CopySym(b.invHess)
+	}
+	var s, y, tmp mat.VecDense
+	var sDotY float64
+	if len(loc.X) == dim && len(loc.Gradient) == dim {
+		s.SubVec(mat.NewVecDense(dim, loc.X), &b.x)
+		y.SubVec(mat.NewVecDense(dim, loc.Gradient), &b.grad)
+		sDotY = mat.Dot(&s, &y)
+	}
+	if !(sDotY > 0) {
+		if b.first {
+			return nil
+		}
+		return invHess
+	}
+	if b.first {
+		scale := sDotY / mat.Dot(&y, &y)
+		for i := 0; i < dim; i++ {
+			invHess.SetSym(i, i, scale)
+		}
+	}
+	updateInverseBFGS(invHess, &s, &y, &tmp)
+	return invHess
+}
+
+// WarmState returns the inverse Hessian approximation at the end of the last
+// run. It implements the WarmStarter interface.
+func (b *BFGS) WarmState() ([]byte, error) {
+	var st bfgsWarmState
+	if b.started && !b.first {
+		st.Dim = b.dim
+		st.InvHess = b.invHess.RawSymmetric().Data
+	}
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(st)
+	return buf.Bytes(), err
+}
+
+// SetWarmState sets the inverse Hessian approximation returned by WarmState as
+// the initial approximation of the next run, which must have the same
+// dimension. It implements the WarmStarter interface.
+func (b *BFGS) SetWarmState(data []byte) error {
+	var st bfgsWarmState
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&st)
+	if err != nil {
+		return err
+	}
+	if st.InvHess == nil {
+		b.warm = nil
+		return nil
+	}
+	if st.Dim <= 0 || len(st.InvHess) != st.Dim*st.Dim {
+		return ErrBadWarmState
+	}
+	b.warm = mat.NewSymDense(st.Dim, st.InvHess)
+	return nil
+}
@@ -8,7 +8,10 @@ import "math"
 
 const defaultBisectionCurvature = 0.9
 
-var _ Linesearcher = (*Bisection)(nil)
+var (
+	_ Linesearcher  = (*Bisection)(nil)
+	_ NoiseTolerant = (*Bisection)(nil)
+)
 
 // Bisection is a Linesearcher that uses a bisection to find a point that
 // satisfies the strong Wolfe conditions with the given curvature factor and
@@ -31,6 +34,8 @@ type Bisection struct {
 
 	initGrad float64
 
+	noise float64 // Estimate of the noise in the function values
+
 	lastOp Operation
 }
 
@@ -79,7 +84,7 @@ func (b *Bisection) Iterate(f, g float64) (Operation, float64, error) {
 		// See if the function value is good enough to make progress. If it is,
 		// evaluate the gradient. If not, set it to the upper bound if the bound
 		// has not yet been found, otherwise iterate toward the minimum location.
-		if f <= minF {
+		if f <= minF+2*b.noise {
 			b.lastF = f
 			b.lastOp = GradEvaluation
 			return b.lastOp, b.currStep, nil
@@ -101,7 +106,7 @@ func (b *Bisection) Iterate(f, g float64) (Operation, float64, error) {
 	f = b.lastF
 	// The function value was lower. Check if this location is sufficient to
 	// converge the linesearch, otherwise iterate.
-	if StrongWolfeConditionsMet(f, g, minF, b.initGrad, b.currStep, 0, b.CurvatureFactor) {
+	if StrongWolfeConditionsMet(f, g, minF+2*b.noise, b.initGrad, b.currStep, 0, b.CurvatureFactor) {
 		b.lastOp = MajorIteration
 		return b.lastOp, b.currStep, nil
 	}
@@ -130,6 +135,13 @@ func (b *Bisection) Iterate(f, g float64) (Operation, float64, error) {
 	return b.nextStep((b.minStep + b.maxStep) / 2)
 }
 
+// SetFunctionNoise sets the estimate of the noise in the function values. The
+// sufficient decrease condition is relaxed by twice the noise. It implements
+// the NoiseTolerant interface.
+func (b *Bisection) SetFunctionNoise(noise float64) {
+	b.noise = noise
+}
+
 // nextStep checks if the new step is equal to the old step.
 // This can happen if min and max are the same, or if the step size is infinity,
 // both of which indicate the minimization must stop. If the steps are different,
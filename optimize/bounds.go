@@ -0,0 +1,55 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// checkBounds checks that bounds are valid for a problem of dimension dim
+// and that x lies within them. checkBounds panics if the bounds are not valid
+// and returns ErrOutOfBounds if x is outside the bounds.
+func checkBounds(bounds []Bound, x []float64) error {
+	if bounds == nil {
+		return nil
+	}
+	if len(bounds) != len(x) {
+		panic("optimize: bounds length mismatch")
+	}
+	for i, b := range bounds {
+		if math.IsNaN(b.Min) || math.IsNaN(b.Max) || b.Min > b.Max {
+			panic("optimize: invalid bound")
+		}
+		if x[i] < b.Min || b.Max < x[i] {
+			return ErrOutOfBounds
+		}
+	}
+	return nil
+}
+
+// project projects x in place onto the bounds.
+func project(x []float64, bounds []Bound) {
+	for i, b := range bounds {
+		x[i] = math.Max(b.Min, math.Min(x[i], b.Max))
+	}
+}
+
+// projGradNorm returns the infinity norm of the projected gradient
+//  P(x - grad) - x
+// where P is the projection onto bounds. If bounds is nil, projGradNorm
+// returns the infinity norm of grad.
+func projGradNorm(x, grad []float64, bounds []Bound) float64 {
+	if bounds == nil {
+		return floats.Norm(grad, math.Inf(1))
+	}
+	var norm float64
+	for i, b := range bounds {
+		v := math.Max(b.Min, math.Min(x[i]-grad[i], b.Max)) - x[i]
+		norm = math.Max(norm, math.Abs(v))
+	}
+	return norm
+}
@@ -0,0 +1,25 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+var _ Recorder = CallbackRecorder(nil)
+
+// CallbackRecorder is a Recorder that calls the function at every
+// MajorIteration with the location and the statistics of the
+// optimization, for example to report the progress. If the function returns
+// ErrStop, the optimization is stopped with the Stopped status, and any other
+// non-nil error stops the optimization with the Failure status and the error.
+type CallbackRecorder func(loc *Location, stats *Stats) error
+
+func (CallbackRecorder) Init() error {
+	return nil
+}
+
+func (c CallbackRecorder) Record(loc *Location, op Operation, stats *Stats) error {
+	if op != MajorIteration {
+		return nil
+	}
+	return c(loc, stats)
+}
@@ -19,6 +19,7 @@ var (
 	_ Method          = (*CG)(nil)
 	_ localMethod     = (*CG)(nil)
 	_ NextDirectioner = (*CG)(nil)
+	_ NoiseTolerant   = (*CG)(nil)
 )
 
 // CGVariant calculates the scaling parameter, β, used for updating the
@@ -106,7 +107,8 @@ type CG struct {
 	// if it is NaN the setting is not used.
 	GradStopThreshold float64
 
-	ls *LinesearchMethod
+	ls    *LinesearchMethod
+	noise float64 // Estimate of the noise in the function values
 
 	status Status
 	err    error
@@ -123,6 +125,13 @@ func (cg *CG) Status() (Status, error) {
 	return cg.status, cg.err
 }
 
+// SetFunctionNoise sets the estimate of the noise in the function values, which
+// is passed to the Linesearcher if it is NoiseTolerant. It implements the
+// NoiseTolerant interface.
+func (cg *CG) SetFunctionNoise(noise float64) {
+	cg.noise = noise
+}
+
 func (*CG) Uses(has Available) (uses Available, err error) {
 	return has.gradient()
 }
@@ -167,6 +176,7 @@ func (cg *CG) initLocal(loc *Location) (Operation, error) {
 		cg.ls = &LinesearchMethod{}
 	}
 	cg.ls.Linesearcher = cg.Linesearcher
+	setLinesearchNoise(cg.Linesearcher, cg.noise)
 	cg.ls.NextDirectioner = cg
 
 	return cg.ls.Init(loc)
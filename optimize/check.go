@@ -0,0 +1,118 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrNothingToCheck signifies that CheckProblem was called with a Problem that
+// provides neither a Grad nor a Hess function.
+var ErrNothingToCheck = errors.New("optimize: problem does not provide Grad or Hess")
+
+// ProblemCheck holds the comparison of the derivatives of a Problem with
+// their finite-difference approximations at a location.
+//
+// The error of each component is the relative difference
+//  |a - d| / max(1, |a|, |d|)
+// between the analytic value a and the finite-difference value d.
+type ProblemCheck struct {
+	// Gradient, FDGradient and GradError are the gradient returned by
+	// Problem.Grad, its finite-difference approximation and the error of
+	// each component. They are nil if Problem.Grad is nil.
+	Gradient   []float64
+	FDGradient []float64
+	GradError  []float64
+
+	// Hessian, FDHessian and HessError are the Hessian returned by
+	// Problem.Hess, its finite-difference approximation and the error of
+	// each element. They are nil if Problem.Hess is nil.
+	Hessian   *mat.SymDense
+	FDHessian *mat.SymDense
+	HessError *mat.SymDense
+
+	// MaxGradError and MaxHessError are the largest errors of the gradient
+	// and the Hessian, or zero if they are not checked.
+	MaxGradError float64
+	MaxHessError float64
+}
+
+// CheckProblem compares the gradient and the Hessian of the Problem at x with
+// their finite-difference approximations, which helps to find errors in the
+// implementations of Problem.Grad and Problem.Hess. The gradient is
+// approximated by central differences of Problem.Func. The Hessian is
+// approximated by central differences of Problem.Grad if it is provided, and
+// by second-order central differences of Problem.Func otherwise. The
+// approximated Hessian is symmetrized.
+//
+// For well-scaled functions, the finite-difference approximations have
+// errors of about 1e-8 relative to the magnitude of the derivatives, and of
+// about 1e-4 for the Hessian approximated from Problem.Func. Errors much
+// larger than these indicate an incorrect derivative.
+//
+// CheckProblem panics if Problem.Func is nil, and it returns
+// ErrNothingToCheck if both Problem.Grad and Problem.Hess are nil.
+func CheckProblem(p Problem, x []float64) (*ProblemCheck, error) {
+	dim := len(x)
+	if dim == 0 {
+		return nil, ErrZeroDimensional
+	}
+	if p.Func == nil {
+		panic(badProblem)
+	}
+	if p.Grad == nil && p.Hess == nil {
+		return nil, ErrNothingToCheck
+	}
+	xCopy := make([]float64, dim)
+	copy(xCopy, x)
+
+	check := &ProblemCheck{}
+	if p.Grad != nil {
+		check.Gradient = make([]float64, dim)
+		p.Grad(check.Gradient, xCopy)
+		check.FDGradient = fd.Gradient(nil, p.Func, xCopy, &fd.Settings{Formula: fd.Central})
+		check.GradError = make([]float64, dim)
+		for i, g := range check.Gradient {
+			check.GradError[i] = relDiff(g, check.FDGradient[i])
+		}
+		check.MaxGradError = floats.Max(check.GradError)
+	}
+	if p.Hess != nil {
+		check.Hessian = mat.NewSymDense(dim, nil)
+		p.Hess(check.Hessian, xCopy)
+		check.FDHessian = mat.NewSymDense(dim, nil)
+		if p.Grad != nil {
+			jac := mat.NewDense(dim, dim, nil)
+			fd.Jacobian(jac, p.Grad, xCopy, &fd.JacobianSettings{Formula: fd.Central})
+			for i := 0; i < dim; i++ {
+				for j := i; j < dim; j++ {
+					check.FDHessian.SetSym(i, j, (jac.At(i, j)+jac.At(j, i))/2)
+				}
+			}
+		} else {
+			fd.Hessian(check.FDHessian, p.Func, xCopy, nil)
+		}
+		check.HessError = mat.NewSymDense(dim, nil)
+		for i := 0; i < dim; i++ {
+			for j := i; j < dim; j++ {
+				e := relDiff(check.Hessian.At(i, j), check.FDHessian.At(i, j))
+				check.HessError.SetSym(i, j, e)
+				check.MaxHessError = math.Max(check.MaxHessError, e)
+			}
+		}
+	}
+	return check, nil
+}
+
+// relDiff returns the difference between a and b relative to the larger of
+// their magnitudes and one.
+func relDiff(a, b float64) float64 {
+	return math.Abs(a-b) / math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
@@ -0,0 +1,77 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestCheckProblem(t *testing.T) {
+	t.Parallel()
+	f := functions.Wood{}
+	x := []float64{-3, -1, -3, -1}
+
+	for _, test := range []struct {
+		name    string
+		problem Problem
+	}{
+		{"grad", Problem{Func: f.Func, Grad: f.Grad}},
+		{"hess", Problem{Func: f.Func, Hess: f.Hess}},
+		{"grad and hess", Problem{Func: f.Func, Grad: f.Grad, Hess: f.Hess}},
+	} {
+		check, err := CheckProblem(test.problem, x)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if (check.Gradient != nil) != (test.problem.Grad != nil) {
+			t.Errorf("%s: unexpected gradient check", test.name)
+		}
+		if (check.Hessian != nil) != (test.problem.Hess != nil) {
+			t.Errorf("%s: unexpected Hessian check", test.name)
+		}
+		if check.MaxGradError > 1e-7 {
+			t.Errorf("%s: gradient error too large for correct gradient: %v", test.name, check.MaxGradError)
+		}
+		if check.MaxHessError > 1e-3 {
+			t.Errorf("%s: Hessian error too large for correct Hessian: %v", test.name, check.MaxHessError)
+		}
+	}
+
+	// Introduce errors in the second component of the gradient and in the
+	// first diagonal element of the Hessian.
+	p := Problem{
+		Func: f.Func,
+		Grad: func(grad, x []float64) {
+			f.Grad(grad, x)
+			grad[1] *= 1.01
+		},
+		Hess: func(hess *mat.SymDense, x []float64) {
+			f.Hess(hess, x)
+			hess.SetSym(0, 0, 1.01*hess.At(0, 0))
+		},
+	}
+	check, err := CheckProblem(p, x)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, e := range check.GradError {
+		if (e > 1e-4) != (i == 1) {
+			t.Errorf("unexpected error of gradient component %d: %v", i, e)
+		}
+	}
+	// The Hessian is approximated from the incorrect gradient, so only the
+	// error of the diagonal element is detected.
+	if check.HessError.At(0, 0) < 1e-3 {
+		t.Errorf("error of Hessian element not detected: %v", check.HessError.At(0, 0))
+	}
+
+	_, err = CheckProblem(Problem{Func: f.Func}, x)
+	if err != ErrNothingToCheck {
+		t.Errorf("unexpected error without derivatives: got %v, want %v", err, ErrNothingToCheck)
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"io"
+)
+
+var (
+	// ErrNoCheckpoint signifies that no complete checkpoint could be read.
+	ErrNoCheckpoint = errors.New("optimize: no checkpoint found")
+
+	// ErrBadCheckpoint signifies that the state restored from a checkpoint
+	// is inconsistent.
+	ErrBadCheckpoint = errors.New("optimize: inconsistent checkpoint state")
+
+	// ErrNotCheckpointer signifies that a Method used for a checkpointed
+	// optimization does not implement Checkpointer.
+	ErrNotCheckpointer = errors.New("optimize: method does not implement Checkpointer")
+
+	// ErrCheckpointTooLarge signifies that the length of a checkpoint
+	// record exceeds the limit for a valid record, which indicates
+	// corrupt input.
+	ErrCheckpointTooLarge = errors.New("optimize: checkpoint record too large")
+)
+
+// maxCheckpointSize is the largest checkpoint record accepted by
+// ReadCheckpoint.
+const maxCheckpointSize = 1 << 30
+
+// Checkpointer is a Method whose internal state can be saved and restored
+// so that an optimization run can be resumed.
+//
+// MarshalState is called at major iterations of the optimization and must
+// return the state needed to continue the run from the Location of that major
+// iteration. UnmarshalState restores the state so that the next call to Run
+// continues from the checkpointed major iteration instead of starting a new
+// optimization.
+type Checkpointer interface {
+	MarshalState() ([]byte, error)
+	UnmarshalState(data []byte) error
+}
+
+// Checkpoint is a snapshot of an optimization run at a major iteration.
+type Checkpoint struct {
+	// X, F and Gradient are the fields of the Location at the major
+	// iteration.
+	X        []float64
+	F        float64
+	Gradient []float64
+
+	// Stats are the statistics of the run up to and including the major
+	// iteration.
+	Stats Stats
+
+	// State is the internal state of the Method as returned by
+	// MarshalState.
+	State []byte
+}
+
+// WriteCheckpoint writes c to w as a single length-prefixed record.
+func WriteCheckpoint(w io.Writer, c *Checkpoint) error {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(c)
+	if err != nil {
+		return err
+	}
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(buf.Len()))
+	_, err = w.Write(size[:])
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// ReadCheckpoint reads the checkpoints written to r by WriteCheckpoint and
+// returns the last complete one. An incomplete trailing record, for example
+// as a result of an interrupted write, is ignored. If r does not contain
+// a complete checkpoint, ReadCheckpoint returns ErrNoCheckpoint. If the
+// length prefix of a record exceeds 1GiB, ReadCheckpoint returns
+// ErrCheckpointTooLarge.
+func ReadCheckpoint(r io.Reader) (*Checkpoint, error) {
+	var last *Checkpoint
+	var size [8]byte
+	for {
+		_, err := io.ReadFull(r, size[:])
+		if err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			return nil, err
+		}
+		n := binary.LittleEndian.Uint64(size[:])
+		if n > maxCheckpointSize {
+			return nil, ErrCheckpointTooLarge
+		}
+		// The buffer grows with the data actually read, so a length
+		// prefix of a truncated record does not cause a large
+		// allocation.
+		var buf bytes.Buffer
+		_, err = io.CopyN(&buf, r, int64(n))
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		var c Checkpoint
+		err = gob.NewDecoder(&buf).Decode(&c)
+		if err != nil {
+			return nil, err
+		}
+		last = &c
+	}
+	if last == nil {
+		return nil, ErrNoCheckpoint
+	}
+	return last, nil
+}
+
+var _ Recorder = (*CheckpointRecorder)(nil)
+
+// CheckpointRecorder is a Recorder that periodically writes a Checkpoint
+// of the optimization run to Writer. The checkpoints can be read with
+// ReadCheckpoint and the run resumed using MinimizeFromCheckpoint.
+//
+// The state of Method is captured at major iterations while Method waits for
+// the major iteration to be processed, which is the case for all local
+// methods. Method must be the Method passed to Minimize.
+type CheckpointRecorder struct {
+	Method Checkpointer
+	Writer io.Writer
+	// Interval is the number of major iterations between checkpoints.
+	// If Interval is 0, a checkpoint is written at every major iteration.
+	Interval int
+}
+
+func (c *CheckpointRecorder) Init() error {
+	if c.Method == nil {
+		return ErrNotCheckpointer
+	}
+	return nil
+}
+
+func (c *CheckpointRecorder) Record(loc *Location, op Operation, stats *Stats) error {
+	if op != MajorIteration {
+		return nil
+	}
+	if c.Interval > 0 && stats.MajorIterations%c.Interval != 0 {
+		return nil
+	}
+	state, err := c.Method.MarshalState()
+	if err != nil {
+		return err
+	}
+	return WriteCheckpoint(c.Writer, &Checkpoint{
+		X:        loc.X,
+		F:        loc.F,
+		Gradient: loc.Gradient,
+		Stats:    *stats,
+		State:    state,
+	})
+}
+
+// MinimizeFromCheckpoint resumes an optimization run from the last complete
+// checkpoint read from r. The method must implement Checkpointer and be
+// configured as in the checkpointed run. The statistics of the returned Result
+// include those of the run up to the checkpoint.
+//
+// The state of the Converger and of the Recorder in settings is not part of the
+// checkpoint, so they restart at the checkpointed location. settings.InitValues
+// must be nil, otherwise an ErrSetting is returned.
+//
+// See Minimize for the description of the other arguments.
+func MinimizeFromCheckpoint(r io.Reader, p Problem, settings *Settings, method Method) (*Result, error) {
+	checkpointer, ok := method.(Checkpointer)
+	if !ok {
+		return nil, ErrNotCheckpointer
+	}
+	if settings != nil && settings.InitValues != nil {
+		return nil, ErrSetting{Field: "InitValues", Reason: "must be nil when resuming from a checkpoint"}
+	}
+	c, err := ReadCheckpoint(r)
+	if err != nil {
+		return nil, err
+	}
+	err = checkpointer.UnmarshalState(c.State)
+	if err != nil {
+		return nil, err
+	}
+
+	var s Settings
+	if settings != nil {
+		s = *settings
+	}
+	s.InitValues = &Location{
+		F:        c.F,
+		Gradient: c.Gradient,
+	}
+	// The checkpointed location is sent again as the first major iteration,
+	// so it must not be counted twice.
+	stats := c.Stats
+	stats.MajorIterations--
+	return minimizeFrom(context.Background(), p, c.X, &s, method, &stats)
+}
@@ -0,0 +1,166 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+// pathRecorder records the locations of all major iterations.
+type pathRecorder struct {
+	x [][]float64
+	f []float64
+}
+
+func (p *pathRecorder) Init() error { return nil }
+
+func (p *pathRecorder) Record(loc *Location, op Operation, stats *Stats) error {
+	if op == MajorIteration {
+		p.x = append(p.x, append([]float64(nil), loc.X...))
+		p.f = append(p.f, loc.F)
+	}
+	return nil
+}
+
+func TestMinimizeFromCheckpoint(t *testing.T) {
+	t.Parallel()
+	const iter = 10
+	for _, test := range []struct {
+		name   string
+		method func() Method
+		// limit is the number of major iterations of the stochastic
+		// gradient methods, which do not converge on their own.
+		limit int
+	}{
+		{name: "BFGS", method: func() Method { return &BFGS{} }},
+		{name: "LBFGS", method: func() Method { return &LBFGS{Store: 3} }},
+		{name: "SGD", method: func() Method { return &SGD{LearningRate: 1e-4, Momentum: 0.9} }, limit: 50},
+		{name: "Adam", method: func() Method { return &Adam{LearningRate: 0.01} }, limit: 50},
+		{name: "AdaGrad", method: func() Method { return &AdaGrad{} }, limit: 50},
+		{name: "RMSProp", method: func() Method { return &RMSProp{Schedule: InverseTimeDecay{Decay: 0.1}} }, limit: 50},
+	} {
+		p := Problem{
+			Func: functions.ExtendedRosenbrock{}.Func,
+			Grad: functions.ExtendedRosenbrock{}.Grad,
+		}
+		x := []float64{-1.2, 1, -1.2, 1, -1.2, 1}
+
+		// Run an uninterrupted optimization.
+		want := &pathRecorder{}
+		wantResult, err := Minimize(p, x, &Settings{Recorder: want, Converger: NeverTerminate{}, MajorIterations: test.limit}, test.method())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if len(want.x) <= iter {
+			t.Fatalf("%s: too few major iterations for test: %d", test.name, len(want.x))
+		}
+
+		// Run an optimization that is interrupted after the checkpoint.
+		var buf bytes.Buffer
+		method := test.method()
+		_, err = Minimize(p, x, &Settings{
+			Recorder: &CheckpointRecorder{
+				Method:   method.(Checkpointer),
+				Writer:   &buf,
+				Interval: iter,
+			},
+			Converger:       NeverTerminate{},
+			MajorIterations: iter + 1,
+		}, method)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+
+		// Append a truncated record, which must be ignored.
+		buf.Write([]byte{1, 2, 3})
+
+		// Resume the optimization with a new method.
+		got := &pathRecorder{}
+		gotResult, err := MinimizeFromCheckpoint(&buf, p, &Settings{Recorder: got, Converger: NeverTerminate{}, MajorIterations: test.limit}, test.method())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if len(got.x) != len(want.x)-iter+1 {
+			t.Errorf("%s: unexpected number of major iterations after resume: got %d, want %d",
+				test.name, len(got.x), len(want.x)-iter+1)
+			continue
+		}
+		for i := range got.x {
+			j := i + iter - 1
+			if got.f[i] != want.f[j] || !floats.Equal(got.x[i], want.x[j]) {
+				t.Errorf("%s: resumed path differs at major iteration %d", test.name, j+1)
+				break
+			}
+		}
+		if gotResult.F != wantResult.F || !floats.Equal(gotResult.X, wantResult.X) {
+			t.Errorf("%s: different minimum after resume", test.name)
+		}
+		if gotResult.Status != wantResult.Status {
+			t.Errorf("%s: unexpected status: got %v, want %v", test.name, gotResult.Status, wantResult.Status)
+		}
+		if gotResult.MajorIterations != wantResult.MajorIterations ||
+			gotResult.FuncEvaluations != wantResult.FuncEvaluations ||
+			gotResult.GradEvaluations != wantResult.GradEvaluations {
+			t.Errorf("%s: unexpected stats after resume: got %+v, want %+v", test.name, gotResult.Stats, wantResult.Stats)
+		}
+	}
+}
+
+func TestReadCheckpointEmpty(t *testing.T) {
+	t.Parallel()
+	_, err := ReadCheckpoint(bytes.NewReader([]byte{1, 2}))
+	if err != ErrNoCheckpoint {
+		t.Errorf("unexpected error: got %v, want %v", err, ErrNoCheckpoint)
+	}
+}
+
+func TestReadCheckpointCorrupt(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	err := WriteCheckpoint(&buf, &Checkpoint{X: []float64{1, 2}, F: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	valid := buf.Bytes()
+
+	// A truncated record with a huge length prefix is ignored without
+	// allocating the claimed length.
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], maxCheckpointSize)
+	truncated := append(append(append([]byte(nil), valid...), size[:]...), 1, 2, 3)
+	c, err := ReadCheckpoint(bytes.NewReader(truncated))
+	if err != nil {
+		t.Fatalf("unexpected error for truncated record: %v", err)
+	}
+	if c.F != 3 {
+		t.Errorf("unexpected checkpoint: got %+v", c)
+	}
+
+	// A length prefix beyond the limit is an error.
+	binary.LittleEndian.PutUint64(size[:], 1<<62)
+	corrupt := append(append([]byte(nil), valid...), size[:]...)
+	_, err = ReadCheckpoint(bytes.NewReader(corrupt))
+	if err != ErrCheckpointTooLarge {
+		t.Errorf("unexpected error: got %v, want %v", err, ErrCheckpointTooLarge)
+	}
+}
+
+func TestMinimizeFromCheckpointInitValues(t *testing.T) {
+	t.Parallel()
+	p := Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+		Grad: functions.ExtendedRosenbrock{}.Grad,
+	}
+	settings := &Settings{InitValues: &Location{F: 1}}
+	_, err := MinimizeFromCheckpoint(bytes.NewReader(nil), p, settings, &BFGS{})
+	if _, ok := err.(ErrSetting); !ok {
+		t.Errorf("unexpected error: got %v, want ErrSetting", err)
+	}
+}
@@ -83,6 +83,20 @@ type CmaEsChol struct {
 	// Src allows a random number generator to be supplied for generating samples.
 	// If Src is nil the generator in golang.org/x/math/rand is used.
 	Src rand.Source
+	// Restarts is the maximum number of restarts. When the stopping criterion
+	// of StopLogDet is met and restarts remain, the algorithm is restarted
+	// from the initial location and the initial covariance with the population
+	// size increased by the factor IncPopulation, as described in
+	//  Auger, A., Hansen, N.: A restart CMA evolution strategy with increasing
+	//  population size. IEEE Congress on Evolutionary Computation (2005).
+	// Larger populations search the function more globally. The best location
+	// is tracked across restarts unless ForgetBest is true. Restarts cannot
+	// be negative, or CmaEsChol will panic.
+	Restarts int
+	// IncPopulation is the factor by which the population size is increased
+	// at each restart. If IncPopulation is 0, a default value of 2 is used.
+	// IncPopulation cannot be less than 1, or CmaEsChol will panic.
+	IncPopulation float64
 
 	// Fixed algorithm parameters.
 	dim                 int
@@ -106,6 +120,10 @@ type CmaEsChol struct {
 	bestX []float64
 	bestF float64
 
+	// Restarts.
+	restarts int
+	initMean []float64
+
 	// Synchronization.
 	sentIdx     int
 	receivedIdx int
@@ -152,16 +170,45 @@ func (cma *CmaEsChol) Init(dim, tasks int) int {
 		panic(negativeTasks)
 	}
 
-	// Set fixed algorithm parameters.
-	// Parameter values are from https://arxiv.org/pdf/1604.00772.pdf .
 	cma.dim = dim
 	cma.pop = cma.Population
-	n := float64(dim)
 	if cma.pop == 0 {
-		cma.pop = 4 + int(3*math.Log(n)) // Note the implicit floor.
+		cma.pop = 4 + int(3*math.Log(float64(dim))) // Note the implicit floor.
 	} else if cma.pop < 0 {
 		panic("cma-es-chol: negative population size")
 	}
+	if cma.InitStepSize < 0 {
+		panic("cma-es-chol: negative initial step size")
+	}
+	if cma.InitCholesky != nil && cma.InitCholesky.Symmetric() != dim {
+		panic("cma-es-chol: incorrect InitCholesky size")
+	}
+	if cma.Restarts < 0 {
+		panic("cma-es-chol: negative number of restarts")
+	}
+	if cma.IncPopulation != 0 && cma.IncPopulation < 1 {
+		panic("cma-es-chol: population increase factor less than 1")
+	}
+	cma.restarts = 0
+	cma.reset()
+
+	cma.bestX = resize(cma.bestX, dim)
+	cma.bestF = math.Inf(1)
+
+	cma.sentIdx = 0
+	cma.receivedIdx = 0
+	cma.operation = nil
+	cma.updateErr = nil
+	t := min(tasks, cma.pop)
+	return t
+}
+
+// reset sets the fixed algorithm parameters for the population size and
+// initializes the adaptive parameters except for the mean.
+func (cma *CmaEsChol) reset() {
+	// Parameter values are from https://arxiv.org/pdf/1604.00772.pdf .
+	dim := cma.dim
+	n := float64(dim)
 	mu := cma.pop / 2
 	cma.weights = resize(cma.weights, mu)
 	for i := range cma.weights {
@@ -187,13 +234,14 @@ func (cma *CmaEsChol) Init(dim, tasks int) int {
 	// Allocate memory for function data.
 	cma.xs = mat.NewDense(cma.pop, dim, nil)
 	cma.fs = resize(cma.fs, cma.pop)
+	for i := range cma.fs {
+		cma.fs[i] = math.NaN()
+	}
 
 	// Allocate and initialize adaptive parameters.
 	cma.invSigma = 1 / cma.InitStepSize
 	if cma.InitStepSize == 0 {
 		cma.invSigma = 10.0 / 3
-	} else if cma.InitStepSize < 0 {
-		panic("cma-es-chol: negative initial step size")
 	}
 	cma.pc = resize(cma.pc, dim)
 	for i := range cma.pc {
@@ -206,9 +254,6 @@ func (cma *CmaEsChol) Init(dim, tasks int) int {
 	cma.mean = resize(cma.mean, dim) // mean location initialized at the start of Run
 
 	if cma.InitCholesky != nil {
-		if cma.InitCholesky.Symmetric() != dim {
-			panic("cma-es-chol: incorrect InitCholesky size")
-		}
 		cma.chol.Clone(cma.InitCholesky)
 	} else {
 		// Set the initial Cholesky to I.
@@ -223,16 +268,26 @@ func (cma *CmaEsChol) Init(dim, tasks int) int {
 		}
 		cma.chol = chol
 	}
+}
 
-	cma.bestX = resize(cma.bestX, dim)
-	cma.bestF = math.Inf(1)
+// restart restarts the algorithm from the initial mean with an increased
+// population size.
+func (cma *CmaEsChol) restart() {
+	cma.restarts++
+	inc := cma.IncPopulation
+	if inc == 0 {
+		inc = 2
+	}
+	cma.pop = int(math.Ceil(inc * float64(cma.pop)))
+	cma.reset()
+	copy(cma.mean, cma.initMean)
+}
 
-	cma.sentIdx = 0
-	cma.receivedIdx = 0
-	cma.operation = nil
-	cma.updateErr = nil
-	t := min(tasks, cma.pop)
-	return t
+// StepSize returns the current value of the step size parameter σ of the
+// adaptation. StepSize may be called by a Recorder during a call to Record
+// to report the step size at each major iteration.
+func (cma *CmaEsChol) StepSize() float64 {
+	return 1 / cma.invSigma
 }
 
 func (cma *CmaEsChol) sendInitTasks(tasks []Task) {
@@ -297,6 +352,8 @@ func (cma *CmaEsChol) findBestAndUpdateTask(task Task) Task {
 
 func (cma *CmaEsChol) Run(operations chan<- Task, results <-chan Task, tasks []Task) {
 	copy(cma.mean, tasks[0].X)
+	cma.initMean = resize(cma.initMean, cma.dim)
+	copy(cma.initMean, tasks[0].X)
 	cma.operation = operations
 	// Send the initial tasks. We know there are at most as many tasks as elements
 	// of the population.
@@ -346,6 +403,10 @@ Loop:
 				case err != nil:
 					cma.updateErr = err
 					task.Op = MethodDone
+				case cma.methodConverged() != NotTerminated && cma.restarts < cma.Restarts:
+					cma.restart()
+					task.Op = MajorIteration
+					task.ID = -1
 				case cma.methodConverged() != NotTerminated:
 					task.Op = MethodDone
 				default:
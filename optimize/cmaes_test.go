@@ -230,3 +230,84 @@ func TestCmaEsChol(t *testing.T) {
 		test.settings.Concurrent = 0
 	}
 }
+
+// stepSizeRecorder records the step size of CmaEsChol at every major
+// iteration.
+type stepSizeRecorder struct {
+	cma   *CmaEsChol
+	sizes []float64
+}
+
+func (r *stepSizeRecorder) Init() error {
+	r.sizes = r.sizes[:0]
+	return nil
+}
+
+func (r *stepSizeRecorder) Record(_ *Location, op Operation, _ *Stats) error {
+	if op == MajorIteration {
+		r.sizes = append(r.sizes, r.cma.StepSize())
+	}
+	return nil
+}
+
+func TestCmaEsCholRestarts(t *testing.T) {
+	t.Parallel()
+	p := Problem{Func: functions.Rastrigin{}.Func}
+	initX := []float64{3.5, -4}
+	for _, restarts := range []int{0, 4} {
+		method := &CmaEsChol{
+			Restarts: restarts,
+			Src:      rand.New(rand.NewSource(1)),
+		}
+		rec := &stepSizeRecorder{cma: method}
+		settings := &Settings{
+			Converger: NeverTerminate{},
+			Recorder:  rec,
+		}
+		result, err := Minimize(p, initX, settings, method)
+		if err != nil {
+			t.Fatalf("restarts %d: unexpected error: %v", restarts, err)
+		}
+		if result.Status != MethodConverge {
+			t.Errorf("restarts %d: unexpected status: got %v, want %v", restarts, result.Status, MethodConverge)
+		}
+		if len(rec.sizes) != result.MajorIterations {
+			t.Errorf("restarts %d: unexpected number of recorded step sizes: got %d, want %d", restarts, len(rec.sizes), result.MajorIterations)
+		}
+		if restarts == 0 {
+			// A single run from the initial location does not find the global
+			// minimum.
+			if result.F < 0.5 {
+				t.Errorf("restarts %d: unexpected global minimum", restarts)
+			}
+			continue
+		}
+		if !floats.EqualApprox(result.X, []float64{0, 0}, 1e-6) {
+			t.Errorf("restarts %d: global minimum not found: got f(%v) = %v", restarts, result.X, result.F)
+		}
+		// The step size is reset at the restarts.
+		var resets int
+		for _, s := range rec.sizes {
+			if s == 0.3 {
+				resets++
+			}
+		}
+		if resets != method.restarts || resets == 0 {
+			t.Errorf("restarts %d: unexpected number of step size resets: got %d, want %d", restarts, resets, method.restarts)
+		}
+
+		// The optimization is deterministic for a given source, also with
+		// concurrent evaluations.
+		for _, concurrent := range []int{0, 3} {
+			method.Src = rand.New(rand.NewSource(1))
+			again, err := Minimize(p, initX, &Settings{Converger: NeverTerminate{}, Concurrent: concurrent}, method)
+			if err != nil {
+				t.Fatalf("restarts %d: unexpected error: %v", restarts, err)
+			}
+			if again.F != result.F || !floats.Equal(again.X, result.X) {
+				t.Errorf("restarts %d: run with %d concurrent evaluations not reproducible: got f(%v) = %v, want f(%v) = %v",
+					restarts, concurrent, again.X, again.F, result.X, result.F)
+			}
+		}
+	}
+}
@@ -0,0 +1,394 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"container/heap"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// BranchingRule selects the integer variable with a fractional value on which
+// a node of the branch-and-bound tree is branched.
+type BranchingRule int
+
+const (
+	// MostFractional branches on the variable whose fractional part is
+	// closest to one half.
+	MostFractional BranchingRule = iota
+	// FirstFractional branches on the fractional variable with the lowest
+	// index.
+	FirstFractional
+)
+
+// NodeSelection selects the next node of the branch-and-bound tree to be
+// solved.
+type NodeSelection int
+
+const (
+	// BestFirst selects the node with the lowest bound on the objective
+	// function, which minimizes the number of solved nodes.
+	BestFirst NodeSelection = iota
+	// DepthFirst selects the most recently created node, which finds
+	// feasible integer solutions early and keeps few nodes in memory.
+	DepthFirst
+)
+
+// MILPSettings controls the branch-and-bound search of MILP.
+type MILPSettings struct {
+	// Branching is the rule for selecting the branching variable.
+	Branching BranchingRule
+	// Selection is the rule for selecting the next node.
+	Selection NodeSelection
+
+	// AbsoluteGap and RelativeGap stop the search when the difference
+	// between the objective value of the best integer solution and the
+	// lower bound on the optimal value is at most AbsoluteGap or at most
+	// RelativeGap times the absolute objective value of the best integer
+	// solution. If AbsoluteGap is 0, a default value of 1e-9 is used. If
+	// RelativeGap is 0, the relative gap is not used.
+	AbsoluteGap float64
+	RelativeGap float64
+
+	// IntegerTol is the largest distance of a value from the nearest
+	// integer for which it is considered integral. If IntegerTol is 0,
+	// a default value of 1e-6 is used.
+	IntegerTol float64
+
+	// MaxNodes is the maximum number of solved linear programs. If MaxNodes
+	// is 0, the number of nodes is not limited.
+	MaxNodes int
+
+	// Tol is the tolerance passed to Simplex for the linear programs.
+	Tol float64
+}
+
+// MILPSolution is the solution of a mixed-integer linear program.
+type MILPSolution struct {
+	// Status is Optimal if the optimal solution was found within the gap,
+	// Infeasible if there is no feasible integer solution, Unbounded if the
+	// linear relaxation is unbounded, and NodeLimit if the search was
+	// stopped by MaxNodes.
+	Status Status
+
+	// F is the objective value of the best integer solution X. F is NaN
+	// and X is nil if no integer solution has been found.
+	F float64
+	X []float64
+
+	// Bound is the lower bound on the optimal objective value proved by the
+	// search.
+	Bound float64
+
+	// Nodes is the number of solved linear programs.
+	Nodes int
+}
+
+// MILP solves a mixed-integer linear program in standard form
+//  minimize	cᵀ x
+//  s.t. 		A*x = b
+//  			x >= 0
+//  			x_i integer for each i with integer[i] true
+// by branch and bound. The linear relaxation of the problem is solved by
+// Simplex, and the feasible region of a node with a fractional integer
+// variable x_i = v is split into the regions with x_i ≤ ⌊v⌋ and x_i ≥ ⌈v⌉.
+// A node is pruned if its relaxation is infeasible or its optimal
+// value is not lower than that of the best integer solution found.
+//
+// The inputs c, A and b must satisfy the conditions of Simplex, and
+// len(integer) must equal len(c). If settings is nil, the default settings
+// are used. An error is returned if Simplex fails for numerical reasons.
+func MILP(c []float64, A mat.Matrix, b []float64, integer []bool, settings *MILPSettings) (*MILPSolution, error) {
+	m, n := A.Dims()
+	if len(c) != n || len(b) != m || len(integer) != n {
+		panic(badShape)
+	}
+	if settings == nil {
+		settings = &MILPSettings{}
+	}
+	absGap := settings.AbsoluteGap
+	if absGap == 0 {
+		absGap = 1e-9
+	}
+	intTol := settings.IntegerTol
+	if intTol == 0 {
+		intTol = 1e-6
+	}
+
+	sol := &MILPSolution{
+		Status: Optimal,
+		F:      math.NaN(),
+		Bound:  math.Inf(-1),
+	}
+	best := math.Inf(1)
+	// pruned is the lowest bound of the nodes pruned because they could
+	// not improve the incumbent by more than the gap.
+	pruned := math.Inf(1)
+	nodes := &nodeQueue{depthFirst: settings.Selection == DepthFirst}
+	heap.Push(nodes, &milpNode{
+		bound: math.Inf(-1),
+		lower: make([]float64, n),
+		upper: constant(n, math.Inf(1)),
+	})
+	for nodes.Len() > 0 {
+		if settings.MaxNodes > 0 && sol.Nodes >= settings.MaxNodes {
+			sol.Status = NodeLimit
+			break
+		}
+		node := heap.Pop(nodes).(*milpNode)
+		if node.bound >= best-gap(best, absGap, settings.RelativeGap) {
+			pruned = math.Min(pruned, node.bound)
+			continue
+		}
+
+		sol.Nodes++
+		f, x, err := node.solve(c, A, b, settings.Tol)
+		switch err {
+		case nil:
+		case ErrInfeasible:
+			continue
+		case ErrUnbounded:
+			if sol.Nodes == 1 {
+				sol.Status = Unbounded
+				sol.F = math.Inf(-1)
+				return sol, nil
+			}
+			// The relaxation of a child node is bounded if the root
+			// relaxation is bounded.
+			continue
+		default:
+			return nil, err
+		}
+		if f >= best-gap(best, absGap, settings.RelativeGap) {
+			pruned = math.Min(pruned, f)
+			continue
+		}
+
+		j := branchVariable(x, integer, intTol, settings.Branching)
+		if j < 0 {
+			// The solution of the relaxation is integral.
+			best = f
+			sol.X = x
+			for i, isInt := range integer {
+				if isInt {
+					x[i] = math.Round(x[i])
+				}
+			}
+			sol.F = floats.Dot(c, x)
+			continue
+		}
+		down := node.child(f)
+		down.upper[j] = math.Floor(x[j])
+		heap.Push(nodes, down)
+		up := node.child(f)
+		up.lower[j] = math.Ceil(x[j])
+		heap.Push(nodes, up)
+	}
+
+	// The bound is the lowest bound of the open and pruned nodes, or the
+	// optimal value if the search is complete.
+	sol.Bound = math.Min(best, pruned)
+	for _, node := range nodes.nodes {
+		sol.Bound = math.Min(sol.Bound, node.bound)
+	}
+	if sol.X == nil && sol.Status == Optimal {
+		sol.Status = Infeasible
+	}
+	return sol, nil
+}
+
+// gap returns the allowed difference between the objective value best of the
+// incumbent and the bound on the optimal value.
+func gap(best, abs, rel float64) float64 {
+	if math.IsInf(best, 1) {
+		return 0
+	}
+	return math.Max(abs, rel*math.Abs(best))
+}
+
+// branchVariable returns the index of the integer variable in x to branch on,
+// or -1 if all integer variables are integral.
+func branchVariable(x []float64, integer []bool, tol float64, rule BranchingRule) int {
+	idx := -1
+	var bestDist float64
+	for i, v := range x {
+		if !integer[i] {
+			continue
+		}
+		frac := v - math.Floor(v)
+		dist := math.Min(frac, 1-frac)
+		if dist <= tol {
+			continue
+		}
+		switch rule {
+		default:
+			panic("lp: unknown branching rule")
+		case FirstFractional:
+			return i
+		case MostFractional:
+			if dist > bestDist {
+				idx = i
+				bestDist = dist
+			}
+		}
+	}
+	return idx
+}
+
+// milpNode is a node of the branch-and-bound tree, which holds the bounds on
+// the variables of its region and a lower bound on its optimal value.
+type milpNode struct {
+	bound float64
+	lower []float64
+	upper []float64
+	order int // Creation order of the node
+}
+
+func (node *milpNode) child(bound float64) *milpNode {
+	child := &milpNode{
+		bound: bound,
+		lower: make([]float64, len(node.lower)),
+		upper: make([]float64, len(node.upper)),
+	}
+	copy(child.lower, node.lower)
+	copy(child.upper, node.upper)
+	return child
+}
+
+// solve solves the linear relaxation of the node. The variables are shifted
+// by their lower bounds, the variables with equal bounds are eliminated, and
+// every finite upper bound is an equality constraint with a slack variable,
+// which avoids degenerate vertices from redundant bound constraints.
+func (node *milpNode) solve(c []float64, A mat.Matrix, b []float64, tol float64) (float64, []float64, error) {
+	m, n := A.Dims()
+	var cols []int // Indices of the free variables
+	var rows int
+	bShift := make([]float64, m)
+	copy(bShift, b)
+	var fShift float64
+	for i, lo := range node.lower {
+		up := node.upper[i]
+		if lo > up {
+			return math.NaN(), nil, ErrInfeasible
+		}
+		if lo != 0 {
+			for k := 0; k < m; k++ {
+				bShift[k] -= A.At(k, i) * lo
+			}
+			fShift += c[i] * lo
+		}
+		if lo == up {
+			continue
+		}
+		cols = append(cols, i)
+		if !math.IsInf(up, 1) {
+			rows++
+		}
+	}
+	if len(cols) == 0 {
+		for _, v := range bShift {
+			if math.Abs(v) > phaseIZeroTol {
+				return math.NaN(), nil, ErrInfeasible
+			}
+		}
+		x := make([]float64, n)
+		copy(x, node.lower)
+		return fShift, x, nil
+	}
+
+	// Drop the rows that only contain eliminated variables.
+	var keep []int
+	for k := 0; k < m; k++ {
+		var nonzero bool
+		for _, i := range cols {
+			if A.At(k, i) != 0 {
+				nonzero = true
+				break
+			}
+		}
+		if nonzero {
+			keep = append(keep, k)
+		} else if math.Abs(bShift[k]) > phaseIZeroTol {
+			return math.NaN(), nil, ErrInfeasible
+		}
+	}
+
+	nFree := len(cols)
+	mNew := len(keep) + rows
+	aNew := mat.NewDense(mNew, nFree+rows, nil)
+	bNew := make([]float64, mNew)
+	cNew := make([]float64, nFree+rows)
+	for r, k := range keep {
+		for j, i := range cols {
+			aNew.Set(r, j, A.At(k, i))
+		}
+		bNew[r] = bShift[k]
+	}
+	row := len(keep)
+	for j, i := range cols {
+		cNew[j] = c[i]
+		if up := node.upper[i]; !math.IsInf(up, 1) {
+			// x_i - lower_i + s = upper_i - lower_i.
+			aNew.Set(row, j, 1)
+			aNew.Set(row, nFree+row-len(keep), 1)
+			bNew[row] = up - node.lower[i]
+			row++
+		}
+	}
+	f, xNew, _, err := simplex(nil, cNew, aNew, bNew, tol)
+	if err != nil {
+		return f, nil, err
+	}
+	x := make([]float64, n)
+	copy(x, node.lower)
+	for j, i := range cols {
+		x[i] += xNew[j]
+	}
+	return f + fShift, x, nil
+}
+
+// nodeQueue is a priority queue of open nodes ordered by their bound, or by
+// their creation order if depthFirst is true.
+type nodeQueue struct {
+	nodes      []*milpNode
+	depthFirst bool
+	created    int
+}
+
+func (q *nodeQueue) Len() int { return len(q.nodes) }
+
+func (q *nodeQueue) Less(i, j int) bool {
+	if q.depthFirst || q.nodes[i].bound == q.nodes[j].bound {
+		return q.nodes[i].order > q.nodes[j].order
+	}
+	return q.nodes[i].bound < q.nodes[j].bound
+}
+
+func (q *nodeQueue) Swap(i, j int) { q.nodes[i], q.nodes[j] = q.nodes[j], q.nodes[i] }
+
+func (q *nodeQueue) Push(x interface{}) {
+	node := x.(*milpNode)
+	node.order = q.created
+	q.created++
+	q.nodes = append(q.nodes, node)
+}
+
+func (q *nodeQueue) Pop() interface{} {
+	n := len(q.nodes)
+	node := q.nodes[n-1]
+	q.nodes[n-1] = nil
+	q.nodes = q.nodes[:n-1]
+	return node
+}
+
+func constant(n int, v float64) []float64 {
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = v
+	}
+	return x
+}
@@ -0,0 +1,198 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var milpSettings = []MILPSettings{
+	{},
+	{Branching: FirstFractional},
+	{Selection: DepthFirst},
+	{Branching: FirstFractional, Selection: DepthFirst},
+}
+
+// inequalityMILP returns the standard form of the problem
+//  minimize cᵀ x s.t. G x ≤ h, x ≥ 0, x integer,
+// where the slack variables are continuous.
+func inequalityMILP(c []float64, g *mat.Dense, h []float64) (cNew []float64, aNew *mat.Dense, integer []bool) {
+	m, n := g.Dims()
+	cNew = make([]float64, n+m)
+	copy(cNew, c)
+	aNew = mat.NewDense(m, n+m, nil)
+	aNew.Slice(0, m, 0, n).(*mat.Dense).Copy(g)
+	for i := 0; i < m; i++ {
+		aNew.Set(i, n+i, 1)
+	}
+	integer = make([]bool, n+m)
+	for i := 0; i < n; i++ {
+		integer[i] = true
+	}
+	return cNew, aNew, integer
+}
+
+// bruteForceMILP returns the optimal value of the problem
+//  minimize cᵀ x s.t. G x ≤ h, x integer in [0, max]
+// by enumeration.
+func bruteForceMILP(c []float64, g *mat.Dense, h []float64, max int) float64 {
+	n := len(c)
+	x := make([]float64, n)
+	gx := make([]float64, len(h))
+	best := math.Inf(1)
+	for {
+		mat.NewVecDense(len(gx), gx).MulVec(g, mat.NewVecDense(n, x))
+		feasible := true
+		for i, v := range gx {
+			if v > h[i]+1e-9 {
+				feasible = false
+				break
+			}
+		}
+		if feasible {
+			best = math.Min(best, floats.Dot(c, x))
+		}
+		// Advance to the next integer point.
+		i := 0
+		for ; i < n; i++ {
+			x[i]++
+			if x[i] <= float64(max) {
+				break
+			}
+			x[i] = 0
+		}
+		if i == n {
+			return best
+		}
+	}
+}
+
+func TestMILPKnapsack(t *testing.T) {
+	t.Parallel()
+	values := []float64{10, 13, 7, 8, 9, 4, 6}
+	weights := []float64{5, 7, 4, 4, 5, 2, 3}
+	const capacity = 15
+	n := len(values)
+
+	// The variables are bounded by one with additional constraints.
+	g := mat.NewDense(n+1, n, nil)
+	h := make([]float64, n+1)
+	g.SetRow(0, weights)
+	h[0] = capacity
+	for i := 0; i < n; i++ {
+		g.Set(i+1, i, 1)
+		h[i+1] = 1
+	}
+	c := make([]float64, n)
+	floats.ScaleTo(c, -1, values)
+	want := bruteForceMILP(c, g, h, 1)
+
+	cNew, aNew, integer := inequalityMILP(c, g, h)
+	for _, settings := range milpSettings {
+		settings := settings
+		sol, err := MILP(cNew, aNew, h, integer, &settings)
+		if err != nil {
+			t.Errorf("%+v: unexpected error: %v", settings, err)
+			continue
+		}
+		if sol.Status != Optimal {
+			t.Errorf("%+v: unexpected status: %v", settings, sol.Status)
+		}
+		if math.Abs(sol.F-want) > 1e-8 {
+			t.Errorf("%+v: unexpected optimum: got %v, want %v", settings, sol.F, want)
+		}
+		if sol.Bound > sol.F || sol.F-sol.Bound > 1e-8 {
+			t.Errorf("%+v: bound %v inconsistent with optimum %v", settings, sol.Bound, sol.F)
+		}
+		for i := 0; i < n; i++ {
+			if sol.X[i] != 0 && sol.X[i] != 1 {
+				t.Errorf("%+v: non-binary solution %v", settings, sol.X[:n])
+				break
+			}
+		}
+		if w := floats.Dot(weights, sol.X[:n]); w > capacity {
+			t.Errorf("%+v: capacity exceeded: %v", settings, w)
+		}
+	}
+
+	sol, err := MILP(cNew, aNew, h, integer, &MILPSettings{MaxNodes: 2})
+	if err != nil {
+		t.Fatalf("unexpected error with node limit: %v", err)
+	}
+	if sol.Status != NodeLimit || sol.Nodes != 2 {
+		t.Errorf("unexpected result with node limit: status %v, nodes %d", sol.Status, sol.Nodes)
+	}
+	if sol.Bound > want+1e-8 {
+		t.Errorf("bound %v with node limit larger than the optimum %v", sol.Bound, want)
+	}
+}
+
+func TestMILPRandom(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for test := 0; test < 20; test++ {
+		n := 2 + rnd.Intn(3)
+		m := 1 + rnd.Intn(3)
+		c := make([]float64, n)
+		for i := range c {
+			c[i] = -float64(1 + rnd.Intn(10))
+		}
+		// Bound all variables by 3 so that the problem is bounded and can
+		// be solved by enumeration.
+		g := mat.NewDense(m+n, n, nil)
+		h := make([]float64, m+n)
+		for i := 0; i < m; i++ {
+			for j := 0; j < n; j++ {
+				g.Set(i, j, float64(rnd.Intn(7)))
+			}
+			h[i] = float64(3 + rnd.Intn(10))
+		}
+		for j := 0; j < n; j++ {
+			g.Set(m+j, j, 1)
+			h[m+j] = 3
+		}
+		want := bruteForceMILP(c, g, h, 3)
+
+		cNew, aNew, integer := inequalityMILP(c, g, h)
+		for _, settings := range milpSettings {
+			settings := settings
+			sol, err := MILP(cNew, aNew, h, integer, &settings)
+			if err != nil {
+				t.Errorf("test %d %+v: unexpected error: %v", test, settings, err)
+				continue
+			}
+			if sol.Status != Optimal || math.Abs(sol.F-want) > 1e-8 {
+				t.Errorf("test %d %+v: unexpected result: status %v, F %v, want %v", test, settings, sol.Status, sol.F, want)
+			}
+		}
+	}
+}
+
+func TestMILPStatus(t *testing.T) {
+	t.Parallel()
+	// 2 x = 1 has no integer solution.
+	sol, err := MILP([]float64{1}, mat.NewDense(1, 1, []float64{2}), []float64{1}, []bool{true}, nil)
+	if err != nil || sol.Status != Infeasible || sol.X != nil {
+		t.Errorf("unexpected result for infeasible problem: %+v, %v", sol, err)
+	}
+
+	// The relaxation has an integral solution.
+	sol, err = MILP([]float64{1, 2}, mat.NewDense(1, 2, []float64{1, 1}), []float64{3}, []bool{true, true}, nil)
+	if err != nil || sol.Status != Optimal || sol.Nodes != 1 || sol.F != 3 {
+		t.Errorf("unexpected result for integral relaxation: %+v, %v", sol, err)
+	}
+
+	// x - y = 0.5 with minimized -x is unbounded.
+	sol, err = MILP([]float64{-1, 0}, mat.NewDense(1, 2, []float64{1, -1}), []float64{0.5}, []bool{true, false}, nil)
+	if err != nil || sol.Status != Unbounded {
+		t.Errorf("unexpected result for unbounded problem: %+v, %v", sol, err)
+	}
+}
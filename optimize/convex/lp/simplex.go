@@ -88,6 +88,125 @@ func Simplex(c []float64, A mat.Matrix, b []float64, tol float64, initialBasic [
 	return ans, x, err
 }
 
+// Status is the status of a linear program solved by SimplexSolution or of
+// a mixed-integer linear program solved by MILP.
+type Status int
+
+const (
+	// Optimal indicates that an optimal solution was found.
+	Optimal Status = iota
+	// Infeasible indicates that no x satisfies the constraints.
+	Infeasible
+	// Unbounded indicates that the objective function is unbounded below
+	// on the feasible set.
+	Unbounded
+	// NodeLimit indicates that the branch-and-bound search of MILP was
+	// stopped by the limit on the number of nodes.
+	NodeLimit
+)
+
+func (s Status) String() string {
+	switch s {
+	case Optimal:
+		return "Optimal"
+	case Infeasible:
+		return "Infeasible"
+	case Unbounded:
+		return "Unbounded"
+	case NodeLimit:
+		return "NodeLimit"
+	}
+	return "Status(?)"
+}
+
+// Solution is the primal and dual solution of a standard-form linear program.
+type Solution struct {
+	Status Status
+
+	// F is the optimal value of the objective function. F is -Inf if the
+	// problem is unbounded and NaN if it is infeasible.
+	F float64
+	// X is the optimal primal solution. X is nil if the problem is not
+	// solved to optimality.
+	X []float64
+	// Dual is the optimal solution y of the dual linear program
+	//  maximize	bᵀ y
+	//  s.t.		Aᵀ y ≤ c,
+	// which holds the sensitivities ∂F/∂b of the optimal value to the
+	// right-hand side of the constraints. Dual is nil if the problem is not
+	// solved to optimality.
+	Dual []float64
+	// ReducedCost holds the reduced costs c - Aᵀ y, which are the
+	// sensitivities ∂F/∂x of the optimal value to the variables. The
+	// reduced costs are non-negative at the optimum and zero for the basic
+	// variables. ReducedCost is nil if the problem is not solved to
+	// optimality.
+	ReducedCost []float64
+	// Basic holds the indices of the basic variables of the optimal vertex.
+	// It can be used as the initial basis for solving a modified problem.
+	Basic []int
+}
+
+// SimplexSolution solves a linear program in standard form like Simplex and
+// returns the optimal primal and dual solutions. Unlike Simplex, SimplexSolution
+// does not return an error for an infeasible or unbounded problem, and instead
+// reports it in the Status of the solution. An error is returned if the problem
+// is malformed, for example if A does not have full row rank, or if the
+// Simplex algorithm fails for numerical reasons.
+//
+// The dual solution satisfies the complementary slackness conditions
+//  x_i (c - Aᵀ y)_i = 0
+// so that the optimal values of the primal and the dual programs are equal.
+func SimplexSolution(c []float64, A mat.Matrix, b []float64, tol float64, initialBasic []int) (*Solution, error) {
+	f, x, basic, err := simplex(initialBasic, c, A, b, tol)
+	switch err {
+	case nil:
+	case ErrInfeasible:
+		return &Solution{Status: Infeasible, F: math.NaN()}, nil
+	case ErrUnbounded:
+		return &Solution{Status: Unbounded, F: math.Inf(-1)}, nil
+	default:
+		return nil, err
+	}
+	m, n := A.Dims()
+	if basic == nil {
+		// The problem is exactly constrained and all variables are basic.
+		basic = make([]int, n)
+		for i := range basic {
+			basic[i] = i
+		}
+	}
+
+	// Solve A_Bᵀ y = c_B for the dual variables.
+	ab := mat.NewDense(m, m, nil)
+	extractColumns(ab, A, basic)
+	cb := make([]float64, m)
+	for i, idx := range basic {
+		cb[i] = c[idx]
+	}
+	dual := make([]float64, m)
+	y := mat.NewVecDense(m, dual)
+	err = y.SolveVec(ab.T(), mat.NewVecDense(m, cb))
+	if err != nil {
+		return nil, ErrLinSolve
+	}
+	rc := make([]float64, n)
+	r := mat.NewVecDense(n, rc)
+	r.MulVec(A.T(), y)
+	floats.SubTo(rc, c, rc)
+	for _, idx := range basic {
+		rc[idx] = 0
+	}
+	return &Solution{
+		Status:      Optimal,
+		F:           f,
+		X:           x,
+		Dual:        dual,
+		ReducedCost: rc,
+		Basic:       basic,
+	}, nil
+}
+
 func simplex(initialBasic []int, c []float64, A mat.Matrix, b []float64, tol float64) (float64, []float64, []int, error) {
 	err := verifyInputs(initialBasic, c, A, b)
 	if err != nil {
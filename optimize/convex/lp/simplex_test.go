@@ -5,6 +5,7 @@
 package lp
 
 import (
+	"math"
 	"testing"
 
 	"golang.org/x/exp/rand"
@@ -185,6 +186,8 @@ func testSimplex(t *testing.T, initialBasic []int, c []float64, a mat.Matrix, b
 		}
 	}
 
+	testSimplexSolution(t, initialBasic, c, a, b, convergenceTol, primalOpt, errPrimal)
+
 	primalInfeasible := errPrimal == ErrInfeasible
 	primalUnbounded := errPrimal == ErrUnbounded
 	primalBounded := errPrimal == nil
@@ -267,3 +270,49 @@ func testSimplex(t *testing.T, initialBasic []int, c []float64, a mat.Matrix, b
 		}
 	}
 }
+
+// testSimplexSolution checks that SimplexSolution agrees with the result of
+// simplex and that the dual solution is optimal.
+func testSimplexSolution(t *testing.T, initialBasic []int, c []float64, a mat.Matrix, b []float64, convergenceTol, primalOpt float64, errPrimal error) {
+	sol, err := SimplexSolution(c, a, b, convergenceTol, initialBasic)
+	switch errPrimal {
+	case nil:
+	case ErrInfeasible:
+		if err != nil || sol.Status != Infeasible {
+			t.Errorf("SimplexSolution: primal infeasible but status %v and error %v", sol, err)
+		}
+		return
+	case ErrUnbounded:
+		if err != nil || sol.Status != Unbounded {
+			t.Errorf("SimplexSolution: primal unbounded but status %v and error %v", sol, err)
+		}
+		return
+	default:
+		if err != errPrimal {
+			t.Errorf("SimplexSolution: error mismatch: got %v, want %v", err, errPrimal)
+		}
+		return
+	}
+	if err != nil {
+		t.Errorf("SimplexSolution: unexpected error: %v", err)
+		return
+	}
+	if sol.Status != Optimal || sol.F != primalOpt {
+		t.Errorf("SimplexSolution: mismatch with simplex: status %v, F %v, want %v", sol.Status, sol.F, primalOpt)
+	}
+
+	// Check the dual feasibility and the equality of the optimal values.
+	scale := math.Max(1, floats.Norm(c, math.Inf(1)))
+	tol := 1e-8 * scale
+	for i, r := range sol.ReducedCost {
+		if r < -tol {
+			t.Errorf("SimplexSolution: negative reduced cost %v at %d", r, i)
+		}
+		if math.Abs(r*sol.X[i]) > tol*math.Max(1, math.Abs(sol.X[i])) {
+			t.Errorf("SimplexSolution: complementary slackness violated at %d: x = %v, reduced cost %v", i, sol.X[i], r)
+		}
+	}
+	if dualOpt := floats.Dot(b, sol.Dual); !floats.EqualWithinAbsOrRel(dualOpt, sol.F, 1e-8, 1e-8) {
+		t.Errorf("SimplexSolution: primal and dual value mismatch: primal %v, dual %v", sol.F, dualOpt)
+	}
+}
@@ -27,3 +27,36 @@ func ExampleSimplex() {
 	// opt: -8
 	// x: [2 3 0 0]
 }
+
+func ExampleSimplexSolution() {
+	// Maximize the profit 3 x0 + 5 x1 from two products subject to the
+	// limited capacity of three machines
+	//  x0 ≤ 4
+	//  2 x1 ≤ 12
+	//  3 x0 + 2 x1 ≤ 18,
+	// which are converted to equalities with slack variables x2, x3 and x4.
+	c := []float64{-3, -5, 0, 0, 0}
+	A := mat.NewDense(3, 5, []float64{
+		1, 0, 1, 0, 0,
+		0, 2, 0, 1, 0,
+		3, 2, 0, 0, 1,
+	})
+	b := []float64{4, 12, 18}
+
+	sol, err := lp.SimplexSolution(c, A, b, 0, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("status: %v\n", sol.Status)
+	fmt.Printf("profit: %.4g\n", -sol.F)
+	fmt.Printf("x: %.4g\n", sol.X[:2])
+	// The dual values are the changes of the objective per unit of
+	// additional capacity of each machine, so the first machine is not
+	// a bottleneck.
+	fmt.Printf("dual: %.4g\n", sol.Dual)
+	// Output:
+	// status: Optimal
+	// profit: 36
+	// x: [2 6]
+	// dual: [0 -1.5 -1]
+}
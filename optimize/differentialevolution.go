@@ -0,0 +1,409 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ Method   = (*DifferentialEvolution)(nil)
+	_ Statuser = (*DifferentialEvolution)(nil)
+	_ Bounder  = (*DifferentialEvolution)(nil)
+)
+
+// DEStrategy is the mutation strategy of DifferentialEvolution.
+type DEStrategy int
+
+const (
+	// DERand1Bin mutates a random member of the population with the scaled
+	// difference of two other random members, and uses binomial crossover.
+	DERand1Bin DEStrategy = iota
+	// DEBest1Bin mutates the best member of the population with the scaled
+	// difference of two random members, and uses binomial crossover.
+	DEBest1Bin
+)
+
+// DifferentialEvolution is a global optimizer that evolves a population of
+// locations. At each generation, a trial location is created for every
+// member x_i of the population by combining x_i with the mutant
+//  v = x_b + F (x_r1 - x_r2)
+// where x_r1 and x_r2 are distinct random members and the base x_b is a
+// random member or the best member depending on the Strategy. Every
+// variable of the trial location is taken from the mutant with probability
+// CR, and at least one variable is always taken from the mutant. The trial
+// location replaces x_i if its function value is not larger. See
+//  Storn, R., Price, K.: Differential evolution - a simple and efficient
+//  heuristic for global optimization over continuous spaces. Journal of
+//  Global Optimization 11 (1997), 341-359
+// for more information.
+//
+// The initial population contains the initial location, and the other
+// members are drawn uniformly from the bounds of the Problem. The variables
+// without finite bounds are drawn from a normal distribution centered at the
+// initial location. A variable of a trial location that is outside the
+// bounds is set to the midpoint between the bound and the variable of x_i.
+//
+// Each major iteration of DifferentialEvolution corresponds to a generation,
+// and the location of the major iteration is the best member of the
+// population. The locations of a generation are evaluated concurrently if
+// Settings.Concurrent is larger than one.
+type DifferentialEvolution struct {
+	// Strategy is the mutation strategy. The default is DERand1Bin.
+	Strategy DEStrategy
+	// Population is the number of members of the population. If Population
+	// is 0, a default value of 10 times the dimension (but at least 4) is
+	// used. Population must be at least 4 if it is not 0, or
+	// DifferentialEvolution will panic.
+	Population int
+	// F is the differential weight that scales the difference of the
+	// members in the mutant. F must be between 0 and 2, and if F is 0,
+	// a default value of 0.8 is used.
+	F float64
+	// CR is the crossover probability. CR must be between 0 and 1, and if
+	// CR is 0, a default value of 0.9 is used.
+	CR float64
+	// InitStdDev is the standard deviation of the variables of the initial
+	// population that do not have finite bounds. If InitStdDev is 0,
+	// a default value of 1 is used.
+	InitStdDev float64
+	// StopSpread sets the threshold for stopping the optimization with
+	// MethodConverge status when the difference between the largest and the
+	// smallest function value in the population is less than StopSpread.
+	// If StopSpread is 0, a default value of 1e-12 is used, and if it is NaN,
+	// the stopping criterion is not used.
+	StopSpread float64
+	// Src allows a random number generator to be supplied for generating
+	// the population and the trial locations. If Src is nil the generator in
+	// golang.org/x/exp/rand is used.
+	Src rand.Source
+
+	bounds []Bound
+
+	status Status
+	rnd    *rand.Rand
+	dim    int
+	pop    int
+	f, cr  float64
+
+	xs *mat.Dense // Population
+	fs []float64  // Function values of the population
+	us *mat.Dense // Trial locations
+	fu []float64  // Function values of the trial locations
+
+	evalX *mat.Dense // Locations that are being evaluated, either xs or us
+	evalF []float64
+
+	bestX []float64
+	bestF float64
+
+	// Synchronization.
+	sentIdx     int
+	receivedIdx int
+	operation   chan<- Task
+}
+
+func (de *DifferentialEvolution) Status() (Status, error) {
+	return de.status, nil
+}
+
+func (*DifferentialEvolution) Uses(has Available) (uses Available, err error) {
+	return has.boundedFunction()
+}
+
+// SetBounds sets the bounds on the variables. It implements the Bounder
+// interface.
+func (de *DifferentialEvolution) SetBounds(bounds []Bound) {
+	de.bounds = bounds
+}
+
+func (de *DifferentialEvolution) Init(dim, tasks int) int {
+	if dim <= 0 {
+		panic(nonpositiveDimension)
+	}
+	if tasks < 0 {
+		panic(negativeTasks)
+	}
+	if de.Strategy != DERand1Bin && de.Strategy != DEBest1Bin {
+		panic("differential evolution: unknown strategy")
+	}
+	de.dim = dim
+	de.pop = de.Population
+	if de.pop == 0 {
+		de.pop = 10 * dim
+		if de.pop < 4 {
+			de.pop = 4
+		}
+	} else if de.pop < 4 {
+		panic("differential evolution: population smaller than 4")
+	}
+	de.f = de.F
+	if de.f == 0 {
+		de.f = 0.8
+	} else if de.f < 0 || de.f > 2 {
+		panic("differential evolution: F must be between 0 and 2")
+	}
+	de.cr = de.CR
+	if de.cr == 0 {
+		de.cr = 0.9
+	} else if de.cr < 0 || de.cr > 1 {
+		panic("differential evolution: CR must be between 0 and 1")
+	}
+	if de.Src != nil {
+		de.rnd = rand.New(de.Src)
+	} else {
+		de.rnd = rand.New(rand.NewSource(rand.Uint64()))
+	}
+
+	de.status = NotTerminated
+	de.xs = mat.NewDense(de.pop, dim, nil)
+	de.us = mat.NewDense(de.pop, dim, nil)
+	de.fs = resize(de.fs, de.pop)
+	de.fu = resize(de.fu, de.pop)
+	de.bestX = resize(de.bestX, dim)
+	de.bestF = math.Inf(1)
+
+	de.sentIdx = 0
+	de.receivedIdx = 0
+	de.operation = nil
+	return min(tasks, de.pop)
+}
+
+// initPopulation generates the initial population around the location x.
+func (de *DifferentialEvolution) initPopulation(x []float64) {
+	randomPopulation(de.xs, x, de.bounds, de.InitStdDev, de.rnd)
+	for i := range de.fs {
+		de.fs[i] = math.NaN()
+	}
+	de.evalX, de.evalF = de.xs, de.fs
+}
+
+// randomPopulation stores the location x in the first row of dst and random
+// locations in the other rows. The variables with finite bounds are drawn
+// uniformly from the bounds, and the other variables are drawn from a normal
+// distribution centered at x with standard deviation std, which defaults to 1
+// if it is 0.
+func randomPopulation(dst *mat.Dense, x []float64, bounds []Bound, std float64, rnd *rand.Rand) {
+	if std == 0 {
+		std = 1
+	}
+	dst.SetRow(0, x)
+	r, _ := dst.Dims()
+	for i := 1; i < r; i++ {
+		row := dst.RawRowView(i)
+		for j := range row {
+			if bounds != nil && !math.IsInf(bounds[j].Min, -1) && !math.IsInf(bounds[j].Max, 1) {
+				b := bounds[j]
+				row[j] = b.Min + (b.Max-b.Min)*rnd.Float64()
+			} else {
+				row[j] = x[j] + std*rnd.NormFloat64()
+			}
+		}
+		project(row, bounds)
+	}
+}
+
+// generate creates the trial locations of the next generation.
+func (de *DifferentialEvolution) generate() {
+	best := bestIndex(de.fs)
+	if best == -1 {
+		best = 0
+	}
+	for i := 0; i < de.pop; i++ {
+		// Choose distinct members different from i.
+		r := [3]int{i, i, i}
+		for k := range r {
+		Choose:
+			for {
+				r[k] = de.rnd.Intn(de.pop)
+				if r[k] == i {
+					continue
+				}
+				for _, v := range r[:k] {
+					if r[k] == v {
+						continue Choose
+					}
+				}
+				break
+			}
+		}
+		base := de.xs.RawRowView(r[0])
+		if de.Strategy == DEBest1Bin {
+			base = de.xs.RawRowView(best)
+		}
+		x := de.xs.RawRowView(i)
+		x1 := de.xs.RawRowView(r[1])
+		x2 := de.xs.RawRowView(r[2])
+		u := de.us.RawRowView(i)
+		jRand := de.rnd.Intn(de.dim)
+		for j := range u {
+			if j != jRand && de.rnd.Float64() >= de.cr {
+				u[j] = x[j]
+				continue
+			}
+			u[j] = base[j] + de.f*(x1[j]-x2[j])
+			if de.bounds != nil {
+				b := de.bounds[j]
+				if u[j] < b.Min {
+					u[j] = (x[j] + b.Min) / 2
+				} else if u[j] > b.Max {
+					u[j] = (x[j] + b.Max) / 2
+				}
+			}
+		}
+	}
+	for i := range de.fu {
+		de.fu[i] = math.NaN()
+	}
+	de.evalX, de.evalF = de.us, de.fu
+}
+
+// selectTrials replaces the members of the population by the trial locations
+// that are not worse.
+func (de *DifferentialEvolution) selectTrials() {
+	for i, f := range de.fu {
+		if f <= de.fs[i] || (math.IsNaN(de.fs[i]) && !math.IsNaN(f)) {
+			de.fs[i] = f
+			de.xs.SetRow(i, de.us.RawRowView(i))
+		}
+	}
+}
+
+// bestIndex returns the index of the smallest value in fs. It returns -1 if
+// all values are NaN.
+func bestIndex(fs []float64) int {
+	best := -1
+	bestVal := math.Inf(1)
+	for i, v := range fs {
+		if math.IsNaN(v) {
+			continue
+		}
+		// Use equality in case somewhere evaluates to +inf.
+		if v <= bestVal {
+			best = i
+			bestVal = v
+		}
+	}
+	return best
+}
+
+// converged returns whether the spread of the function values of the
+// population is below the threshold.
+func (de *DifferentialEvolution) converged() bool {
+	spread := de.StopSpread
+	switch {
+	case math.IsNaN(spread):
+		return false
+	case spread == 0:
+		spread = 1e-12
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, f := range de.fs {
+		if math.IsNaN(f) {
+			return false
+		}
+		lo = math.Min(lo, f)
+		hi = math.Max(hi, f)
+	}
+	return hi-lo < spread
+}
+
+func (de *DifferentialEvolution) sendInitTasks(tasks []Task) {
+	for i, task := range tasks {
+		de.sendTask(i, task)
+	}
+	de.sentIdx = len(tasks)
+}
+
+// sendTask sends the evaluation of the location with index idx.
+func (de *DifferentialEvolution) sendTask(idx int, task Task) {
+	task.ID = idx
+	task.Op = FuncEvaluation
+	copy(task.X, de.evalX.RawRowView(idx))
+	de.operation <- task
+}
+
+// updateBest updates the best location found so far from the population
+// and stores it in task.
+func (de *DifferentialEvolution) updateBest(task Task) Task {
+	if best := bestIndex(de.fs); best != -1 && de.fs[best] < de.bestF {
+		de.bestF = de.fs[best]
+		copy(de.bestX, de.xs.RawRowView(best))
+	}
+	task.F = de.bestF
+	copy(task.X, de.bestX)
+	return task
+}
+
+func (de *DifferentialEvolution) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	de.operation = operation
+	de.initPopulation(tasks[0].X)
+	de.sendInitTasks(tasks)
+
+Loop:
+	for {
+		task := <-result
+		switch task.Op {
+		default:
+			panic("unknown operation")
+		case PostIteration:
+			break Loop
+		case MajorIteration:
+			de.generate()
+			de.sendInitTasks(tasks)
+		case FuncEvaluation:
+			de.receivedIdx++
+			de.evalF[task.ID] = task.F
+			switch {
+			case de.sentIdx < de.pop:
+				de.sendTask(de.sentIdx, task)
+				de.sentIdx++
+			case de.receivedIdx < de.pop:
+				// Wait until all of the generation has been evaluated.
+				continue Loop
+			default:
+				de.sentIdx = 0
+				de.receivedIdx = 0
+				if de.evalX == de.us {
+					de.selectTrials()
+				}
+				task = de.updateBest(task)
+				task.ID = -1
+				task.Op = MajorIteration
+				if de.converged() {
+					de.status = MethodConverge
+					task.Op = MethodDone
+				}
+				operation <- task
+			}
+		}
+	}
+
+	// PostIteration was sent. Collect the remaining evaluations and report
+	// the best location evaluated since the last major iteration if it
+	// improves on the best location.
+	for task := range result {
+		switch task.Op {
+		default:
+			panic("unknown operation")
+		case MajorIteration:
+		case FuncEvaluation:
+			de.evalF[task.ID] = task.F
+		}
+	}
+	if best := bestIndex(de.evalF); best != -1 && de.evalF[best] < de.bestF {
+		task := tasks[0]
+		task.F = de.evalF[best]
+		copy(task.X, de.evalX.RawRowView(best))
+		task.ID = -1
+		task.Op = MajorIteration
+		operation <- task
+	}
+	close(operation)
+}
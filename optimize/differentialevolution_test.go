@@ -0,0 +1,116 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestDifferentialEvolution(t *testing.T) {
+	t.Parallel()
+	rastrigin := Problem{
+		Func:   functions.Rastrigin{}.Func,
+		Bounds: []Bound{{-5.12, 5.12}, {-5.12, 5.12}, {-5.12, 5.12}},
+	}
+	rosenbrock := Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+	}
+	for _, test := range []struct {
+		name   string
+		p      Problem
+		method *DifferentialEvolution
+		x      []float64
+		want   []float64
+	}{
+		{
+			name:   "rastrigin rand/1/bin",
+			p:      rastrigin,
+			method: &DifferentialEvolution{},
+			x:      []float64{3.5, -4, 2},
+			want:   []float64{0, 0, 0},
+		},
+		{
+			name:   "rastrigin best/1/bin",
+			p:      rastrigin,
+			method: &DifferentialEvolution{Strategy: DEBest1Bin, Population: 40, F: 0.5},
+			x:      []float64{3.5, -4, 2},
+			want:   []float64{0, 0, 0},
+		},
+		{
+			name:   "rosenbrock unbounded",
+			p:      rosenbrock,
+			method: &DifferentialEvolution{Strategy: DEBest1Bin, CR: 0.5},
+			x:      []float64{-1.2, 1},
+			want:   []float64{1, 1},
+		},
+	} {
+		var results []*Result
+		for _, concurrent := range []int{0, 4} {
+			test.method.Src = rand.NewSource(1)
+			settings := &Settings{
+				Concurrent: concurrent,
+				Converger:  NeverTerminate{},
+			}
+			result, err := Minimize(test.p, test.x, settings, test.method)
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.name, err)
+				continue
+			}
+			if result.Status != MethodConverge {
+				t.Errorf("%s: unexpected status: got %v, want %v", test.name, result.Status, MethodConverge)
+			}
+			if !floats.EqualApprox(result.X, test.want, 1e-4) {
+				t.Errorf("%s: unexpected optimum: got %v, want %v", test.name, result.X, test.want)
+			}
+			if err := checkBounds(test.p.Bounds, result.X); err != nil {
+				t.Errorf("%s: optimum outside bounds: %v", test.name, result.X)
+			}
+			results = append(results, result)
+		}
+		// The generations do not depend on the order of the evaluations.
+		if len(results) == 2 && (results[0].F != results[1].F || results[0].MajorIterations != results[1].MajorIterations) {
+			t.Errorf("%s: concurrent run differs: got f=%v after %d iterations, want f=%v after %d iterations",
+				test.name, results[1].F, results[1].MajorIterations, results[0].F, results[0].MajorIterations)
+		}
+	}
+}
+
+func TestDifferentialEvolutionLimits(t *testing.T) {
+	t.Parallel()
+	p := Problem{Func: functions.ExtendedRosenbrock{}.Func}
+	method := &DifferentialEvolution{Population: 10, Src: rand.NewSource(1)}
+	result, err := Minimize(p, []float64{-1.2, 1}, &Settings{MajorIterations: 5}, method)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != IterationLimit {
+		t.Errorf("unexpected status: got %v, want %v", result.Status, IterationLimit)
+	}
+	// The initial population and 4 generations of trial locations are
+	// evaluated.
+	if result.FuncEvaluations != 50 {
+		t.Errorf("unexpected number of evaluations: got %d, want 50", result.FuncEvaluations)
+	}
+	if f := p.Func(result.X); f != result.F || math.IsNaN(f) {
+		t.Errorf("unexpected optimum: got f(%v) = %v", result.X, result.F)
+	}
+
+	for _, method := range []*DifferentialEvolution{
+		{Population: 3},
+		{F: 3},
+		{CR: -1},
+		{Strategy: 2},
+	} {
+		if !panics(func() { Minimize(p, []float64{0, 0}, nil, method) }) {
+			t.Errorf("no panic for invalid settings %+v", method)
+		}
+	}
+}
@@ -32,6 +32,11 @@ var (
 	// lies out of allowed bounds.
 	ErrLinesearcherBound = errors.New("linesearch: step out of bounds")
 
+	// ErrStop can be returned by a Recorder at a MajorIteration to stop the
+	// optimization. Minimize then returns with the Stopped status and
+	// without an error.
+	ErrStop = errors.New("optimize: stop requested by recorder")
+
 	// ErrMissingGrad signifies that a Method requires a Gradient function that
 	// is not supplied by Problem.
 	ErrMissingGrad = errors.New("optimize: problem does not provide needed Grad function")
@@ -39,6 +44,46 @@ var (
 	// ErrMissingHess signifies that a Method requires a Hessian function that
 	// is not supplied by Problem.
 	ErrMissingHess = errors.New("optimize: problem does not provide needed Hess function")
+
+	// ErrMissingHessVec signifies that a Method requires a Hessian-vector
+	// product function that is not supplied by Problem.
+	ErrMissingHessVec = errors.New("optimize: problem does not provide needed HessVec function")
+
+	// ErrUnsupportedBounds signifies that a Method does not support the
+	// bound constraints specified by Problem.
+	ErrUnsupportedBounds = errors.New("optimize: method does not support bound constraints")
+
+	// ErrUnsupportedProjection signifies that a Method does not support the
+	// projection of the Problem.
+	ErrUnsupportedProjection = errors.New("optimize: method does not support projection")
+
+	// ErrOutOfBounds signifies that the initial location lies outside the
+	// bounds specified by Problem.
+	ErrOutOfBounds = errors.New("optimize: initial location outside bounds")
+
+	// ErrUnsupportedLinear signifies that a Method does not support the
+	// linear constraints specified by Problem.
+	ErrUnsupportedLinear = errors.New("optimize: method does not support linear constraints")
+
+	// ErrInfeasible signifies that the initial location does not satisfy
+	// the linear constraints specified by Problem.
+	ErrInfeasible = errors.New("optimize: initial location does not satisfy linear constraints")
+
+	// ErrNotWarmStarter signifies that Settings.MethodState is specified
+	// for a Method that does not implement WarmStarter.
+	ErrNotWarmStarter = errors.New("optimize: method does not implement WarmStarter")
+
+	// ErrBadWarmState signifies that the warm-start state passed to
+	// SetWarmState is inconsistent.
+	ErrBadWarmState = errors.New("optimize: inconsistent warm-start state")
+
+	// ErrEvaluationTimeout signifies that an evaluation of the Problem took
+	// longer than Settings.EvaluationTimeout.
+	ErrEvaluationTimeout = errors.New("optimize: evaluation timed out")
+
+	// ErrUnsupportedNonlinear signifies that a Method does not support the
+	// nonlinear constraints specified by Problem.
+	ErrUnsupportedNonlinear = errors.New("optimize: method does not support nonlinear constraints")
 )
 
 // ErrFunc is returned when an initial function value is invalid. The error
@@ -74,5 +119,29 @@ func (err ErrGrad) Error() string {
 	}
 }
 
+// ErrEvaluationPanic is returned when Func, Grad or Hess of the Problem panicked
+// and Settings.RecoverPanics is true. Op is the evaluation during which the
+// panic occurred and Value is the value passed to panic. ErrEvaluationPanic
+// satisfies the error interface.
+type ErrEvaluationPanic struct {
+	Op    Operation
+	Value interface{}
+}
+
+func (err ErrEvaluationPanic) Error() string {
+	return fmt.Sprintf("optimize: panic during %v: %v", err.Op, err.Value)
+}
+
+// ErrSetting is returned when a field of Settings has an invalid value.
+// ErrSetting satisfies the error interface.
+type ErrSetting struct {
+	Field  string // Field is the name of the invalid field.
+	Reason string // Reason describes why the value is invalid.
+}
+
+func (err ErrSetting) Error() string {
+	return fmt.Sprintf("optimize: invalid Settings.%s: %s", err.Field, err.Reason)
+}
+
 // List of shared panic strings
 const badProblem = "optimize: objective function is undefined"
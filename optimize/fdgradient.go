@@ -0,0 +1,57 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"sync/atomic"
+
+	"gonum.org/v1/gonum/diff/fd"
+)
+
+// fdGradient approximates the gradient of an objective function by finite
+// differences and counts the evaluations of the objective function it
+// performs.
+type fdGradient struct {
+	f        func(x []float64) float64
+	settings fd.Settings
+
+	evals int64 // Must be accessed atomically.
+}
+
+// newFDGradient returns a finite difference approximation of the gradient of
+// f using the given settings. If the formula in settings is not specified, the
+// central difference formula is used. The evaluations of f are performed
+// concurrently if concurrent is larger than one.
+func newFDGradient(f func(x []float64) float64, settings *fd.Settings, concurrent int) *fdGradient {
+	g := &fdGradient{
+		f:        f,
+		settings: *settings,
+	}
+	if g.settings.Formula.Stencil == nil {
+		g.settings.Formula = fd.Central
+	}
+	if concurrent > 1 {
+		g.settings.Concurrent = true
+	}
+	// The value at the origin changes between evaluations.
+	g.settings.OriginKnown = false
+	return g
+}
+
+// grad stores the approximate gradient at x into grad.
+func (g *fdGradient) grad(grad, x []float64) {
+	fd.Gradient(grad, g.countedFunc, x, &g.settings)
+}
+
+func (g *fdGradient) countedFunc(x []float64) float64 {
+	atomic.AddInt64(&g.evals, 1)
+	return g.f(x)
+}
+
+// evaluations returns the number of evaluations of the objective function
+// performed since the previous call to evaluations.
+func (g *fdGradient) evaluations() int {
+	return int(atomic.SwapInt64(&g.evals, 0))
+}
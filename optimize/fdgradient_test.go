@@ -0,0 +1,114 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestFDGradient(t *testing.T) {
+	t.Parallel()
+	f := functions.ExtendedRosenbrock{}
+	for _, x := range [][]float64{
+		{-1.2, 1},
+		{-1.2, 1, -1.2, 1},
+		{0.5, 0.2, 0.3, -1, 2},
+	} {
+		g := newFDGradient(f.Func, &fd.Settings{}, 1)
+		got := make([]float64, len(x))
+		g.grad(got, x)
+		want := make([]float64, len(x))
+		f.Grad(want, x)
+		if !floats.EqualApprox(got, want, 1e-6) {
+			t.Errorf("unexpected gradient at %v: got %v, want %v", x, got, want)
+		}
+		// The central formula evaluates the function twice per dimension.
+		if n := g.evaluations(); n != 2*len(x) {
+			t.Errorf("unexpected number of function evaluations: got %d, want %d", n, 2*len(x))
+		}
+		if n := g.evaluations(); n != 0 {
+			t.Errorf("evaluation counter not reset: got %d", n)
+		}
+	}
+}
+
+func TestMinimizeFDGradient(t *testing.T) {
+	t.Parallel()
+	for _, concurrent := range []int{0, 4} {
+		var evals int64
+		p := Problem{
+			Func: func(x []float64) float64 {
+				atomic.AddInt64(&evals, 1)
+				return functions.ExtendedRosenbrock{}.Func(x)
+			},
+		}
+		x := []float64{-1.2, 1}
+		settings := &Settings{
+			FDGradient:        &fd.Settings{},
+			GradientThreshold: 1e-6,
+			Concurrent:        concurrent,
+		}
+		result, err := Minimize(p, x, settings, &BFGS{})
+		if err != nil {
+			t.Fatalf("concurrent %d: unexpected error: %v", concurrent, err)
+		}
+		if result.Status != GradientThreshold {
+			t.Errorf("concurrent %d: unexpected status: got %v, want %v", concurrent, result.Status, GradientThreshold)
+		}
+		if !floats.EqualApprox(result.X, []float64{1, 1}, 1e-5) {
+			t.Errorf("concurrent %d: minimum not found: got %v", concurrent, result.X)
+		}
+		if result.FuncEvaluations != int(evals) {
+			t.Errorf("concurrent %d: unexpected number of function evaluations: got %d, want %d", concurrent, result.FuncEvaluations, evals)
+		}
+		if result.GradEvaluations == 0 {
+			t.Errorf("concurrent %d: no gradient evaluations reported", concurrent)
+		}
+	}
+}
+
+func TestMinimizeFDGradientFallback(t *testing.T) {
+	t.Parallel()
+	var evals int
+	p := Problem{
+		Func: func(x []float64) float64 {
+			evals++
+			return functions.ExtendedRosenbrock{}.Func(x)
+		},
+	}
+	x := []float64{-1.2, 1, -1.2, 1}
+	result, err := Minimize(p, x, &Settings{GradientThreshold: 1e-6}, &LBFGS{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != GradientThreshold {
+		t.Errorf("unexpected status: got %v, want %v", result.Status, GradientThreshold)
+	}
+	if !floats.EqualApprox(result.X, []float64{1, 1, 1, 1}, 1e-5) {
+		t.Errorf("minimum not found: got %v", result.X)
+	}
+	if result.FuncEvaluations != evals {
+		t.Errorf("unexpected number of function evaluations: got %d, want %d", result.FuncEvaluations, evals)
+	}
+	// Every gradient evaluation evaluates the function twice per dimension.
+	if result.FuncEvaluations < 2*len(x)*result.GradEvaluations {
+		t.Errorf("finite difference evaluations not counted: %d function and %d gradient evaluations", result.FuncEvaluations, result.GradEvaluations)
+	}
+
+	// Gradient-free methods do not use the approximation.
+	evals = 0
+	result, err = Minimize(p, x, &Settings{MajorIterations: 10}, &NelderMead{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.GradEvaluations != 0 || result.FuncEvaluations != evals {
+		t.Errorf("unexpected evaluations with NelderMead: %d function and %d gradient evaluations", result.FuncEvaluations, result.GradEvaluations)
+	}
+}
@@ -6,6 +6,8 @@ package optimize
 
 import (
 	"math"
+
+	"gonum.org/v1/gonum/floats"
 )
 
 // Converger returns the convergence of the optimization based on
@@ -16,9 +18,20 @@ type Converger interface {
 	Converged(loc *Location) Status
 }
 
+// StatsConverger is a Converger that also uses the statistics of the
+// optimization. If the Converger in Settings implements StatsConverger,
+// ConvergedStats is called at every MajorIteration instead of Converged.
+// ConvergedStats must not modify the provided Location and Stats.
+type StatsConverger interface {
+	Converger
+	ConvergedStats(loc *Location, stats *Stats) Status
+}
+
 var (
-	_ Converger = NeverTerminate{}
-	_ Converger = (*FunctionConverge)(nil)
+	_ Converger      = NeverTerminate{}
+	_ Converger      = (*FunctionConverge)(nil)
+	_ Converger      = (*StepConverge)(nil)
+	_ StatsConverger = Convergers(nil)
 )
 
 // NeverTerminate implements Converger, always reporting NotTerminated.
@@ -79,3 +92,76 @@ func (fc *FunctionConverge) Converged(l *Location) Status {
 	}
 	return FunctionConvergence
 }
+
+// StepConverge tests for small changes in the location over the last
+// iterations. A StepConvergence status is returned if the change in location
+// between consecutive major iterations satisfies
+//  ‖x - x_prev‖_∞ ≤ StepConverge.Relative * ‖x‖_∞ + StepConverge.Absolute
+// for StepConverge.Iterations consecutive iterations. If
+// StepConverge.Iterations is 0, a value of 1 is used.
+type StepConverge struct {
+	Absolute   float64
+	Relative   float64
+	Iterations int
+
+	prev []float64
+	iter int
+}
+
+func (sc *StepConverge) Init(dim int) {
+	sc.prev = sc.prev[:0]
+	sc.iter = 0
+}
+
+func (sc *StepConverge) Converged(l *Location) Status {
+	if len(sc.prev) == 0 {
+		sc.prev = append(sc.prev, l.X...)
+		return NotTerminated
+	}
+	step := floats.Distance(l.X, sc.prev, math.Inf(1))
+	copy(sc.prev, l.X)
+	if step > sc.Relative*floats.Norm(l.X, math.Inf(1))+sc.Absolute {
+		sc.iter = 0
+		return NotTerminated
+	}
+	sc.iter++
+	if sc.iter < sc.Iterations {
+		return NotTerminated
+	}
+	return StepConvergence
+}
+
+// Convergers combines several Convergers. The optimization has converged when
+// any of the Convergers reports a status other than NotTerminated, and the
+// status of the first such Converger is returned. All Convergers are called
+// at every MajorIteration so that they track the complete history.
+type Convergers []Converger
+
+func (c Convergers) Init(dim int) {
+	for _, conv := range c {
+		conv.Init(dim)
+	}
+}
+
+func (c Convergers) Converged(loc *Location) Status {
+	return c.ConvergedStats(loc, nil)
+}
+
+// ConvergedStats calls ConvergedStats of the Convergers that implement
+// StatsConverger, and Converged of the others. If stats is nil, Converged is
+// called for all Convergers.
+func (c Convergers) ConvergedStats(loc *Location, stats *Stats) Status {
+	status := NotTerminated
+	for _, conv := range c {
+		var s Status
+		if sc, ok := conv.(StatsConverger); ok && stats != nil {
+			s = sc.ConvergedStats(loc, stats)
+		} else {
+			s = conv.Converged(loc)
+		}
+		if status == NotTerminated {
+			status = s
+		}
+	}
+	return status
+}
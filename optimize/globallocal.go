@@ -0,0 +1,306 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+)
+
+// GlobalLocalPhase indicates the phase of a GlobalLocal optimization.
+type GlobalLocalPhase int
+
+const (
+	// GlobalPhase is the exploration phase of GlobalLocal run by the
+	// Global method.
+	GlobalPhase GlobalLocalPhase = iota
+	// LocalPhase is the refinement phase of GlobalLocal run by the
+	// Local method.
+	LocalPhase
+)
+
+func (p GlobalLocalPhase) String() string {
+	switch p {
+	case GlobalPhase:
+		return "GlobalPhase"
+	case LocalPhase:
+		return "LocalPhase"
+	}
+	return "GlobalLocalPhase(?)"
+}
+
+var (
+	_ Method    = (*GlobalLocal)(nil)
+	_ Statuser  = (*GlobalLocal)(nil)
+	_ HessVecer = (*GlobalLocal)(nil)
+)
+
+// GlobalLocal is a hybrid (memetic) optimization method that first runs
+// a global Method to locate a promising basin and then switches to a local
+// Method, started from the best location found by the global phase, for fast
+// final convergence.
+//
+// The global phase ends when Switch reports a status other than NotTerminated
+// at a major iteration of the Global method, or when the Global method itself
+// concludes with MethodDone. All evaluations of both phases are performed by
+// Minimize, so the Stats of the Result aggregate over both phases.
+//
+// GlobalLocal only supports unconstrained problems. Its Uses method returns an
+// error if the Problem has bounds, a projection or constraints.
+type GlobalLocal struct {
+	// Global is the Method used for the exploration phase. Global must
+	// not be nil.
+	Global Method
+	// Local is the Method used for the refinement phase. Local must not
+	// be nil.
+	Local Method
+	// Switch determines when the global phase is concluded. Switch is
+	// passed the Location of every major iteration of the Global method.
+	// If Switch is nil, a default value of
+	//  FunctionConverge {
+	//		Absolute: 1e-6,
+	//		Relative: 1e-6,
+	//		Iterations: 20,
+	//  }
+	// will be used.
+	Switch Converger
+
+	sw     Converger // Switch or its default value.
+	dim    int
+	phase  GlobalLocalPhase
+	final  GlobalLocalPhase
+	bestX  []float64
+	bestF  float64
+	status Status
+	err    error
+}
+
+// Status returns the status of the method.
+func (g *GlobalLocal) Status() (Status, error) {
+	return g.status, g.err
+}
+
+// FinalPhase returns the phase that produced the final location of the most
+// recent optimization run.
+func (g *GlobalLocal) FinalPhase() GlobalLocalPhase {
+	return g.final
+}
+
+// SetHessVec passes the Hessian-vector product function to the phase
+// methods that use it. It implements the HessVecer interface.
+func (g *GlobalLocal) SetHessVec(hessVec func(dst, x, v []float64)) {
+	if m, ok := g.Global.(HessVecer); ok {
+		m.SetHessVec(hessVec)
+	}
+	if m, ok := g.Local.(HessVecer); ok {
+		m.SetHessVec(hessVec)
+	}
+}
+
+func (g *GlobalLocal) Uses(has Available) (uses Available, err error) {
+	if g.Global == nil || g.Local == nil {
+		panic("globallocal: nil method")
+	}
+	switch {
+	case has.Bounds:
+		return Available{}, ErrUnsupportedBounds
+	case has.Project:
+		return Available{}, ErrUnsupportedProjection
+	case has.Linear:
+		return Available{}, ErrUnsupportedLinear
+	case has.Nonlinear:
+		return Available{}, ErrUnsupportedNonlinear
+	}
+	globalUses, err := g.Global.Uses(has)
+	if err != nil {
+		return Available{}, err
+	}
+	localUses, err := g.Local.Uses(has)
+	if err != nil {
+		return Available{}, err
+	}
+	return Available{
+		Grad:    globalUses.Grad || localUses.Grad,
+		Hess:    globalUses.Hess || localUses.Hess,
+		HessVec: globalUses.HessVec || localUses.HessVec,
+	}, nil
+}
+
+func (g *GlobalLocal) Init(dim, tasks int) int {
+	if dim <= 0 {
+		panic(nonpositiveDimension)
+	}
+	if tasks < 0 {
+		panic(negativeTasks)
+	}
+	g.dim = dim
+	g.phase = GlobalPhase
+	g.final = GlobalPhase
+	g.bestX = resize(g.bestX, dim)
+	g.bestF = math.Inf(1)
+	g.status = NotTerminated
+	g.err = nil
+	g.sw = g.Switch
+	if g.sw == nil {
+		g.sw = &FunctionConverge{
+			Absolute:   1e-6,
+			Relative:   1e-6,
+			Iterations: 20,
+		}
+	}
+	g.sw.Init(dim)
+	return g.Global.Init(dim, tasks)
+}
+
+func (g *GlobalLocal) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	// The initial location is used as the starting location of the local
+	// phase if the global phase does not produce any major iteration.
+	copy(g.bestX, tasks[0].X)
+	done := g.runPhase(g.Global, operation, result, tasks)
+	if !done {
+		// Start the local phase from the best location of the global phase.
+		g.phase = LocalPhase
+		g.Local.Init(g.dim, 1)
+		task := tasks[0]
+		task.ID = 0
+		task.Op = NoOperation
+		copy(task.X, g.bestX)
+		if !math.IsInf(g.bestF, 1) {
+			task.Op = FuncEvaluation
+			task.F = g.bestF
+		}
+		task.Gradient = nil
+		task.Hessian = nil
+		g.runPhase(g.Local, operation, result, []Task{task})
+		if statuser, ok := g.Local.(Statuser); ok {
+			g.status, g.err = statuser.Status()
+		}
+	}
+	close(operation)
+}
+
+// runPhase runs method, forwarding the tasks it commands to operation and
+// the results read from result back to the method. runPhase returns when
+// method has closed its operation channel. The returned value indicates
+// whether the caller of GlobalLocal has signaled the end of the optimization.
+func (g *GlobalLocal) runPhase(method Method, operation chan<- Task, result <-chan Task, tasks []Task) (done bool) {
+	innerOperation := make(chan Task, len(tasks))
+	innerResult := make(chan Task, len(tasks))
+	go method.Run(innerOperation, innerResult, tasks)
+
+	var (
+		fromMethod  <-chan Task = innerOperation
+		fromCaller              = result
+		toMethod    []Task      // Tasks waiting to be sent to method.
+		toCaller    []Task      // Tasks waiting to be sent to the caller.
+		outstanding int         // Tasks sent to the caller but not returned.
+		stopping    bool        // PostIteration has been sent to method.
+		closed      bool        // innerResult has been closed.
+	)
+	for fromMethod != nil || len(toCaller) != 0 {
+		// Close the inner result channel once no more results can be
+		// returned to method. If the caller has finished, this happens when
+		// result has been closed, otherwise it happens when all tasks sent
+		// to the caller have been returned.
+		if stopping && !closed && len(toMethod) == 0 {
+			if (done && fromCaller == nil) || (!done && outstanding == 0) {
+				close(innerResult)
+				closed = true
+			}
+		}
+
+		var (
+			methodChan chan<- Task
+			methodTask Task
+			callerChan chan<- Task
+			callerTask Task
+		)
+		if len(toMethod) != 0 {
+			methodChan = innerResult
+			methodTask = toMethod[0]
+		}
+		if len(toCaller) != 0 {
+			callerChan = operation
+			callerTask = toCaller[0]
+		}
+
+		select {
+		case methodChan <- methodTask:
+			toMethod = toMethod[1:]
+		case callerChan <- callerTask:
+			toCaller = toCaller[1:]
+		case task, ok := <-fromMethod:
+			if !ok {
+				fromMethod = nil
+				continue
+			}
+			switch {
+			case stopping:
+				// Evaluations commanded after PostIteration are not
+				// performed. Major iterations are still forwarded if the
+				// caller has finished, otherwise they only update the best
+				// location of the phase.
+				if task.Op != MajorIteration {
+					continue
+				}
+				g.updateBest(task.Location)
+				if done {
+					toCaller = append(toCaller, task)
+					outstanding++
+				}
+			case task.Op == MethodDone:
+				if g.phase == LocalPhase {
+					// The local method has converged, which concludes
+					// the optimization.
+					toCaller = append(toCaller, task)
+					continue
+				}
+				// The global method has converged, switch to the local phase.
+				g.updateBest(task.Location)
+				stopping = true
+				toMethod = append(toMethod, Task{Op: PostIteration})
+			case task.Op == MajorIteration:
+				g.updateBest(task.Location)
+				toCaller = append(toCaller, task)
+				outstanding++
+				if g.phase == GlobalPhase && g.sw.Converged(task.Location) != NotTerminated {
+					stopping = true
+					toMethod = append(toMethod, Task{Op: PostIteration})
+				}
+			default:
+				toCaller = append(toCaller, task)
+				outstanding++
+			}
+		case task, ok := <-fromCaller:
+			if !ok {
+				fromCaller = nil
+				continue
+			}
+			if task.Op == PostIteration {
+				done = true
+				if !stopping {
+					stopping = true
+					toMethod = append(toMethod, task)
+				}
+				continue
+			}
+			outstanding--
+			toMethod = append(toMethod, task)
+		}
+	}
+	return done
+}
+
+// updateBest updates the best location found so far and the phase that
+// produced it.
+func (g *GlobalLocal) updateBest(loc *Location) {
+	if g.phase == GlobalPhase {
+		g.final = GlobalPhase
+	}
+	if loc.F < g.bestF {
+		g.bestF = loc.F
+		copy(g.bestX, loc.X)
+		g.final = g.phase
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestGlobalLocal(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name       string
+		concurrent int
+		switcher   Converger
+	}{
+		{name: "default"},
+		{name: "concurrent", concurrent: 4},
+		{name: "iterations", switcher: &FunctionConverge{Absolute: math.Inf(1), Iterations: 5}},
+	} {
+		p := Problem{
+			Func: functions.ExtendedRosenbrock{}.Func,
+			Grad: functions.ExtendedRosenbrock{}.Grad,
+		}
+		x := []float64{-1.2, 1}
+		method := &GlobalLocal{
+			Global: &CmaEsChol{Src: rand.NewSource(1)},
+			Local:  &BFGS{},
+			Switch: test.switcher,
+		}
+		settings := &Settings{
+			Concurrent: test.concurrent,
+			Converger:  NeverTerminate{},
+		}
+		result, err := Minimize(p, x, settings, method)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if result.Status != GradientThreshold {
+			t.Errorf("%s: unexpected status: got %v, want %v", test.name, result.Status, GradientThreshold)
+		}
+		if !floats.EqualApprox(result.X, []float64{1, 1}, 1e-6) {
+			t.Errorf("%s: minimum not found: got %v", test.name, result.X)
+		}
+		if method.FinalPhase() != LocalPhase {
+			t.Errorf("%s: unexpected final phase: got %v, want %v", test.name, method.FinalPhase(), LocalPhase)
+		}
+		if result.GradEvaluations == 0 {
+			t.Errorf("%s: gradient evaluations of the local phase not counted", test.name)
+		}
+	}
+}
+
+func TestGlobalLocalEarlyTermination(t *testing.T) {
+	t.Parallel()
+	p := Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+		Grad: functions.ExtendedRosenbrock{}.Grad,
+	}
+	x := []float64{-1.2, 1}
+	method := &GlobalLocal{
+		Global: &CmaEsChol{Src: rand.NewSource(1)},
+		Local:  &BFGS{},
+		Switch: NeverTerminate{},
+	}
+	settings := &Settings{
+		Concurrent:      4,
+		FuncEvaluations: 100,
+	}
+	result, err := Minimize(p, x, settings, method)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != FunctionEvaluationLimit {
+		t.Errorf("unexpected status: got %v, want %v", result.Status, FunctionEvaluationLimit)
+	}
+	if method.FinalPhase() != GlobalPhase {
+		t.Errorf("unexpected final phase: got %v, want %v", method.FinalPhase(), GlobalPhase)
+	}
+	if result.GradEvaluations != 0 {
+		t.Errorf("unexpected gradient evaluations in the global phase: %d", result.GradEvaluations)
+	}
+}
+
+func TestGlobalLocalUses(t *testing.T) {
+	t.Parallel()
+	method := &GlobalLocal{Global: &CmaEsChol{}, Local: &BFGS{}}
+	for _, test := range []struct {
+		has  Available
+		want error
+	}{
+		{Available{Grad: true, Bounds: true}, ErrUnsupportedBounds},
+		{Available{Grad: true, Project: true}, ErrUnsupportedProjection},
+		{Available{Grad: true, Linear: true}, ErrUnsupportedLinear},
+		{Available{Grad: true, Nonlinear: true}, ErrUnsupportedNonlinear},
+	} {
+		_, err := method.Uses(test.has)
+		if err != test.want {
+			t.Errorf("unexpected error for %+v: got %v, want %v", test.has, err, test.want)
+		}
+	}
+	uses, err := method.Uses(Available{Grad: true, Hess: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !uses.Grad || uses.Hess {
+		t.Errorf("unexpected available fields: %+v", uses)
+	}
+}
+
+func TestGlobalLocalReuse(t *testing.T) {
+	t.Parallel()
+	p := Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+		Grad: functions.ExtendedRosenbrock{}.Grad,
+	}
+	method := &GlobalLocal{
+		Global: &CmaEsChol{Src: rand.NewSource(1)},
+		Local:  &BFGS{},
+	}
+	for i := 0; i < 2; i++ {
+		result, err := Minimize(p, []float64{-1.2, 1}, &Settings{Converger: NeverTerminate{}}, method)
+		if err != nil {
+			t.Fatalf("run %d: unexpected error: %v", i, err)
+		}
+		if !floats.EqualApprox(result.X, []float64{1, 1}, 1e-6) {
+			t.Errorf("run %d: minimum not found: got %v", i, result.X)
+		}
+		if method.Switch != nil {
+			t.Fatalf("run %d: Switch modified by the optimization", i)
+		}
+	}
+}
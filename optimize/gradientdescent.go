@@ -10,6 +10,7 @@ var (
 	_ Method          = (*GradientDescent)(nil)
 	_ localMethod     = (*GradientDescent)(nil)
 	_ NextDirectioner = (*GradientDescent)(nil)
+	_ NoiseTolerant   = (*GradientDescent)(nil)
 )
 
 // GradientDescent implements the steepest descent optimization method that
@@ -26,7 +27,8 @@ type GradientDescent struct {
 	// if it is NaN the setting is not used.
 	GradStopThreshold float64
 
-	ls *LinesearchMethod
+	ls    *LinesearchMethod
+	noise float64 // Estimate of the noise in the function values
 
 	status Status
 	err    error
@@ -36,6 +38,13 @@ func (g *GradientDescent) Status() (Status, error) {
 	return g.status, g.err
 }
 
+// SetFunctionNoise sets the estimate of the noise in the function values, which
+// is passed to the Linesearcher if it is NoiseTolerant. It implements the
+// NoiseTolerant interface.
+func (g *GradientDescent) SetFunctionNoise(noise float64) {
+	g.noise = noise
+}
+
 func (*GradientDescent) Uses(has Available) (uses Available, err error) {
 	return has.gradient()
 }
@@ -63,6 +72,7 @@ func (g *GradientDescent) initLocal(loc *Location) (Operation, error) {
 		g.ls = &LinesearchMethod{}
 	}
 	g.ls.Linesearcher = g.Linesearcher
+	setLinesearchNoise(g.Linesearcher, g.noise)
 	g.ls.NextDirectioner = g
 
 	return g.ls.Init(loc)
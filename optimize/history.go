@@ -0,0 +1,359 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// RecordFormat specifies the output format of a HistoryRecorder.
+type RecordFormat int
+
+const (
+	// FormatCSV writes comma-separated values with a header row.
+	FormatCSV RecordFormat = iota
+	// FormatJSONLines writes one JSON object per line.
+	FormatJSONLines
+)
+
+// RecordField is a set of fields written by a HistoryRecorder.
+type RecordField uint
+
+const (
+	// FieldIteration is the number of major iterations.
+	FieldIteration RecordField = 1 << iota
+	// FieldF is the function value.
+	FieldF
+	// FieldX is the location.
+	FieldX
+	// FieldGradNorm is the infinity norm of the gradient.
+	FieldGradNorm
+	// FieldStep is the infinity norm of the change in location since the
+	// previous major iteration. It is only available in major iteration
+	// records after the first.
+	FieldStep
+	// FieldRuntime is the runtime of the optimization in seconds.
+	FieldRuntime
+	// FieldEvaluations is the number of function, gradient and Hessian
+	// evaluations.
+	FieldEvaluations
+
+	// DefaultFields are the fields written if HistoryRecorder.Fields is 0.
+	DefaultFields = FieldIteration | FieldF | FieldX | FieldGradNorm
+)
+
+var _ Recorder = (*HistoryRecorder)(nil)
+
+// HistoryRecorder is a Recorder that writes the history of an optimization
+// run, one record per InitIteration, MajorIteration and PostIteration.
+//
+// Each record contains the phase of the run ("init", "major" or "post")
+// followed by the fields selected by Fields. With the DefaultFields, these
+// are the number of major iterations, the function value, the components of
+// X and the infinity norm of the gradient if the gradient is available.
+// In CSV format the columns of all fields are
+//  phase,iteration,f,x0,...,x{n-1},gradnorm,step,runtime,funcevals,gradevals,hessevals
+// with an empty gradnorm if the gradient is not available and an empty step
+// in all but the second and later major iteration records. In JSON lines format the fields are phase,
+// iteration, f, x, gradnorm, step, runtime, funcevals, gradevals and
+// hessevals, where gradnorm and step are omitted if they are not available
+// and non-finite values are written as null.
+//
+// The output is buffered and flushed at PostIteration.
+type HistoryRecorder struct {
+	// Fields selects the fields of the records. If Fields is 0,
+	// DefaultFields is used.
+	Fields RecordField
+
+	w      *bufio.Writer
+	format RecordFormat
+
+	csv    *csv.Writer
+	header bool // Indicator that the CSV header has been written.
+
+	fields RecordField
+	prev   []float64 // Location of the previous major iteration.
+}
+
+// NewHistoryRecorder returns a new HistoryRecorder that writes to w using the
+// given format.
+func NewHistoryRecorder(w io.Writer, format RecordFormat) *HistoryRecorder {
+	switch format {
+	case FormatCSV, FormatJSONLines:
+	default:
+		panic("optimize: unknown record format")
+	}
+	return &HistoryRecorder{
+		w:      bufio.NewWriter(w),
+		format: format,
+	}
+}
+
+func (h *HistoryRecorder) Init() error {
+	h.header = false
+	h.fields = h.Fields
+	if h.fields == 0 {
+		h.fields = DefaultFields
+	}
+	h.prev = h.prev[:0]
+	if h.format == FormatCSV {
+		h.csv = csv.NewWriter(h.w)
+	}
+	return nil
+}
+
+func (h *HistoryRecorder) Record(loc *Location, op Operation, stats *Stats) error {
+	var phase string
+	switch op {
+	case InitIteration:
+		phase = "init"
+	case MajorIteration:
+		phase = "major"
+	case PostIteration:
+		phase = "post"
+	default:
+		return nil
+	}
+
+	r := historyRecord{Phase: phase}
+	if h.fields&FieldIteration != 0 {
+		iter := stats.MajorIterations
+		r.Iteration = &iter
+	}
+	if h.fields&FieldF != 0 {
+		f := jsonFloat(loc.F)
+		r.F = &f
+	}
+	if h.fields&FieldX != 0 {
+		r.X = make([]jsonFloat, len(loc.X))
+		for i, v := range loc.X {
+			r.X[i] = jsonFloat(v)
+		}
+	}
+	if h.fields&FieldGradNorm != 0 && loc.Gradient != nil {
+		norm := jsonFloat(floats.Norm(loc.Gradient, math.Inf(1)))
+		r.GradNorm = &norm
+	}
+	if op == MajorIteration {
+		// The location of the InitIteration record is a placeholder
+		// and is not used as the origin of the first step.
+		if len(h.prev) != 0 && h.fields&FieldStep != 0 {
+			step := jsonFloat(floats.Distance(loc.X, h.prev, math.Inf(1)))
+			r.Step = &step
+		}
+		h.prev = append(h.prev[:0], loc.X...)
+	}
+	if h.fields&FieldRuntime != 0 {
+		runtime := stats.Runtime.Seconds()
+		r.Runtime = &runtime
+	}
+	if h.fields&FieldEvaluations != 0 {
+		r.evaluations = &evaluations{
+			FuncEvaluations: stats.FuncEvaluations,
+			GradEvaluations: stats.GradEvaluations,
+			HessEvaluations: stats.HessEvaluations,
+		}
+	}
+
+	var err error
+	switch h.format {
+	case FormatCSV:
+		err = h.writeCSV(&r, len(loc.X))
+	case FormatJSONLines:
+		err = h.writeJSON(&r)
+	}
+	if err != nil {
+		return err
+	}
+	if op == PostIteration {
+		return h.w.Flush()
+	}
+	return nil
+}
+
+func (h *HistoryRecorder) writeCSV(r *historyRecord, dim int) error {
+	if !h.header {
+		header := []string{"phase"}
+		if h.fields&FieldIteration != 0 {
+			header = append(header, "iteration")
+		}
+		if h.fields&FieldF != 0 {
+			header = append(header, "f")
+		}
+		if h.fields&FieldX != 0 {
+			for i := 0; i < dim; i++ {
+				header = append(header, "x"+strconv.Itoa(i))
+			}
+		}
+		if h.fields&FieldGradNorm != 0 {
+			header = append(header, "gradnorm")
+		}
+		if h.fields&FieldStep != 0 {
+			header = append(header, "step")
+		}
+		if h.fields&FieldRuntime != 0 {
+			header = append(header, "runtime")
+		}
+		if h.fields&FieldEvaluations != 0 {
+			header = append(header, "funcevals", "gradevals", "hessevals")
+		}
+		err := h.csv.Write(header)
+		if err != nil {
+			return err
+		}
+		h.header = true
+	}
+	record := []string{r.Phase}
+	if r.Iteration != nil {
+		record = append(record, strconv.Itoa(*r.Iteration))
+	}
+	if r.F != nil {
+		record = append(record, formatFloat(float64(*r.F)))
+	}
+	for _, v := range r.X {
+		record = append(record, formatFloat(float64(v)))
+	}
+	if h.fields&FieldGradNorm != 0 {
+		record = append(record, formatOptional(r.GradNorm))
+	}
+	if h.fields&FieldStep != 0 {
+		record = append(record, formatOptional(r.Step))
+	}
+	if r.Runtime != nil {
+		record = append(record, formatFloat(*r.Runtime))
+	}
+	if e := r.evaluations; e != nil {
+		record = append(record,
+			strconv.Itoa(e.FuncEvaluations),
+			strconv.Itoa(e.GradEvaluations),
+			strconv.Itoa(e.HessEvaluations),
+		)
+	}
+	err := h.csv.Write(record)
+	if err != nil {
+		return err
+	}
+	h.csv.Flush()
+	return h.csv.Error()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// formatOptional returns the formatted value of v or the empty string if v
+// is nil.
+func formatOptional(v *jsonFloat) string {
+	if v == nil {
+		return ""
+	}
+	return formatFloat(float64(*v))
+}
+
+// historyRecord is the JSON representation of a HistoryRecorder record.
+// The fields that are not selected are nil.
+type historyRecord struct {
+	Phase     string      `json:"phase"`
+	Iteration *int        `json:"iteration,omitempty"`
+	F         *jsonFloat  `json:"f,omitempty"`
+	X         []jsonFloat `json:"x,omitempty"`
+	GradNorm  *jsonFloat  `json:"gradnorm,omitempty"`
+	Step      *jsonFloat  `json:"step,omitempty"`
+	Runtime   *float64    `json:"runtime,omitempty"`
+	*evaluations
+}
+
+// evaluations holds the evaluation counts of a historyRecord.
+type evaluations struct {
+	FuncEvaluations int `json:"funcevals"`
+	GradEvaluations int `json:"gradevals"`
+	HessEvaluations int `json:"hessevals"`
+}
+
+// jsonFloat is a float64 that is marshaled as null if it is not finite.
+type jsonFloat float64
+
+func (v jsonFloat) MarshalJSON() ([]byte, error) {
+	f := float64(v)
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return []byte("null"), nil
+	}
+	return strconv.AppendFloat(nil, f, 'g', -1, 64), nil
+}
+
+func (h *HistoryRecorder) writeJSON(r *historyRecord) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	_, err = h.w.Write(append(b, '\n'))
+	return err
+}
+
+// History is the trajectory of an optimization run as retained with
+// Settings.History. Element i of each slice holds the value at the i-th major
+// iteration, and the evaluation counts are cumulative from the start of the
+// run. The values are with respect to the variables of the Problem also when
+// Settings.Scale or Settings.Preconditioner are set.
+type History struct {
+	// F is the function value.
+	F []float64
+	// GradNorm is the infinity norm of the gradient, or NaN if the gradient
+	// is not available.
+	GradNorm []float64
+	// Step is the infinity norm of the change in location since the previous
+	// major iteration, and NaN at the first major iteration.
+	Step []float64
+
+	FuncEvaluations []int
+	GradEvaluations []int
+	HessEvaluations []int
+}
+
+// Len returns the number of major iterations in the history.
+func (h *History) Len() int {
+	return len(h.F)
+}
+
+// trajectory collects the History of an optimization at the major
+// iterations.
+type trajectory struct {
+	history History
+	scaling *variableScaling // Change of variables to the Problem, or nil.
+
+	loc  Location  // Location with respect to the variables of the Problem.
+	prev []float64 // Location of the previous major iteration.
+}
+
+// add appends loc and the evaluation counts of stats to the history.
+func (t *trajectory) add(loc *Location, stats *Stats) {
+	if t.scaling != nil {
+		t.scaling.locationToUser(&t.loc, loc)
+		loc = &t.loc
+	}
+	h := &t.history
+	h.F = append(h.F, loc.F)
+	gradNorm := math.NaN()
+	if loc.Gradient != nil {
+		gradNorm = floats.Norm(loc.Gradient, math.Inf(1))
+	}
+	h.GradNorm = append(h.GradNorm, gradNorm)
+	step := math.NaN()
+	if t.prev != nil {
+		step = floats.Distance(loc.X, t.prev, math.Inf(1))
+	}
+	h.Step = append(h.Step, step)
+	t.prev = append(t.prev[:0], loc.X...)
+	h.FuncEvaluations = append(h.FuncEvaluations, stats.FuncEvaluations)
+	h.GradEvaluations = append(h.GradEvaluations, stats.GradEvaluations)
+	h.HessEvaluations = append(h.HessEvaluations, stats.HessEvaluations)
+}
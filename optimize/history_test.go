@@ -0,0 +1,341 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"strconv"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestHistoryRecorder(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name    string
+		problem Problem
+		method  Method
+	}{
+		{
+			name: "gradient",
+			problem: Problem{
+				Func: functions.ExtendedRosenbrock{}.Func,
+				Grad: functions.ExtendedRosenbrock{}.Grad,
+			},
+			method: &BFGS{},
+		},
+		{
+			name: "no gradient",
+			problem: Problem{
+				Func: functions.ExtendedRosenbrock{}.Func,
+			},
+			method: &NelderMead{},
+		},
+	} {
+		for _, format := range []RecordFormat{FormatCSV, FormatJSONLines} {
+			var buf bytes.Buffer
+			settings := &Settings{
+				Recorder:        NewHistoryRecorder(&buf, format),
+				MajorIterations: 20,
+			}
+			x := []float64{-1.2, 1}
+			result, err := Minimize(test.problem, x, settings, test.method)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", test.name, err)
+			}
+
+			var phases []string
+			var fs []float64
+			var gradNorm bool
+			switch format {
+			case FormatCSV:
+				records, err := csv.NewReader(&buf).ReadAll()
+				if err != nil {
+					t.Fatalf("%s: unexpected error reading CSV: %v", test.name, err)
+				}
+				for _, r := range records[1:] {
+					if len(r) != len(x)+4 {
+						t.Fatalf("%s: unexpected number of CSV columns: %d", test.name, len(r))
+					}
+					f, err := strconv.ParseFloat(r[2], 64)
+					if err != nil {
+						t.Fatalf("%s: bad function value: %v", test.name, err)
+					}
+					phases = append(phases, r[0])
+					fs = append(fs, f)
+					gradNorm = gradNorm || r[len(r)-1] != ""
+				}
+			case FormatJSONLines:
+				sc := bufio.NewScanner(&buf)
+				for sc.Scan() {
+					var r struct {
+						Phase    string
+						F        *float64
+						X        []float64
+						GradNorm *float64
+					}
+					err := json.Unmarshal(sc.Bytes(), &r)
+					if err != nil {
+						t.Fatalf("%s: unexpected error reading JSON: %v", test.name, err)
+					}
+					if len(r.X) != len(x) {
+						t.Fatalf("%s: unexpected length of x: %d", test.name, len(r.X))
+					}
+					f := 0.0
+					if r.F != nil {
+						f = *r.F
+					}
+					phases = append(phases, r.Phase)
+					fs = append(fs, f)
+					gradNorm = gradNorm || r.GradNorm != nil
+				}
+			}
+
+			// The major iteration that terminates the run is only recorded
+			// as PostIteration.
+			if want := result.MajorIterations + 1; len(phases) != want {
+				t.Errorf("%s: unexpected number of records: got %d, want %d", test.name, len(phases), want)
+				continue
+			}
+			if phases[0] != "init" || phases[len(phases)-1] != "post" {
+				t.Errorf("%s: unexpected boundary phases: %v", test.name, phases)
+			}
+			for i := 2; i < len(fs)-1; i++ {
+				if fs[i] > fs[i-1] {
+					t.Errorf("%s: function value increased at record %d", test.name, i)
+				}
+			}
+			if gradNorm != (test.problem.Grad != nil) {
+				t.Errorf("%s: unexpected gradient norm column", test.name)
+			}
+		}
+	}
+}
+
+func TestHistoryRecorderFields(t *testing.T) {
+	t.Parallel()
+	p := Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+		Grad: functions.ExtendedRosenbrock{}.Grad,
+	}
+	x := []float64{-1.2, 1}
+
+	var buf bytes.Buffer
+	rec := NewHistoryRecorder(&buf, FormatCSV)
+	rec.Fields = FieldF | FieldStep | FieldRuntime | FieldEvaluations
+	_, err := Minimize(p, x, &Settings{Recorder: rec, MajorIterations: 5}, &BFGS{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("unexpected error reading CSV: %v", err)
+	}
+	want := []string{"phase", "f", "step", "runtime", "funcevals", "gradevals", "hessevals"}
+	if !equalStrings(records[0], want) {
+		t.Errorf("unexpected CSV header: got %v, want %v", records[0], want)
+	}
+	var majors int
+	for i, r := range records[1:] {
+		if r[0] == "major" {
+			majors++
+		}
+		if r[0] != "major" || majors == 1 {
+			if r[2] != "" {
+				t.Errorf("unexpected step in %s record %d: %q", r[0], i+1, r[2])
+			}
+		} else {
+			step, err := strconv.ParseFloat(r[2], 64)
+			if err != nil || step < 0 {
+				t.Errorf("bad step in record %d: %q", i+1, r[2])
+			}
+		}
+		if r[0] != "init" && (r[4] == "0" || r[5] == "0" || r[6] != "0") {
+			t.Errorf("unexpected evaluations in record %d: %v", i+1, r[4:])
+		}
+	}
+
+	buf.Reset()
+	rec = NewHistoryRecorder(&buf, FormatJSONLines)
+	rec.Fields = FieldIteration | FieldStep
+	_, err = Minimize(p, x, &Settings{Recorder: rec, MajorIterations: 5}, &BFGS{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sc := bufio.NewScanner(&buf)
+	for line := 0; sc.Scan(); line++ {
+		var r map[string]interface{}
+		err := json.Unmarshal(sc.Bytes(), &r)
+		if err != nil {
+			t.Fatalf("unexpected error reading JSON: %v", err)
+		}
+		// Only major iterations after the first have a step.
+		_, hasStep := r["step"]
+		wantStep := r["phase"] == "major" && r["iteration"] != 1.0
+		if len(r) != 2+btoi(hasStep) || hasStep != wantStep || r["iteration"] == nil {
+			t.Errorf("unexpected fields in record %d: %v", line, r)
+		}
+	}
+}
+
+func TestHistoryRecorderStep(t *testing.T) {
+	t.Parallel()
+	p := Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+		Grad: functions.ExtendedRosenbrock{}.Grad,
+	}
+	var buf bytes.Buffer
+	rec := NewHistoryRecorder(&buf, FormatCSV)
+	rec.Fields = FieldX | FieldStep
+	_, err := Minimize(p, []float64{-1.2, 1}, &Settings{Recorder: rec, MajorIterations: 5}, &BFGS{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("unexpected error reading CSV: %v", err)
+	}
+
+	// The step is measured between consecutive major iterations, and the
+	// first major iteration has no step, rather than a step from the
+	// placeholder location of the init record.
+	var prev []float64
+	for i, r := range records[1:] {
+		if r[0] != "major" {
+			continue
+		}
+		x := make([]float64, 2)
+		for j := range x {
+			x[j], err = strconv.ParseFloat(r[1+j], 64)
+			if err != nil {
+				t.Fatalf("bad location in record %d: %v", i+1, r)
+			}
+		}
+		if prev == nil {
+			if r[3] != "" {
+				t.Errorf("unexpected step in first major record: %q", r[3])
+			}
+		} else {
+			step, err := strconv.ParseFloat(r[3], 64)
+			want := math.Max(math.Abs(x[0]-prev[0]), math.Abs(x[1]-prev[1]))
+			if err != nil || math.Abs(step-want) > 1e-14*math.Max(1, want) {
+				t.Errorf("unexpected step in record %d: got %q, want %v", i+1, r[3], want)
+			}
+		}
+		prev = x
+	}
+	if prev == nil {
+		t.Errorf("no major iteration records")
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, v := range a {
+		if v != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func TestSettingsHistory(t *testing.T) {
+	t.Parallel()
+	p := Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+		Grad: functions.ExtendedRosenbrock{}.Grad,
+	}
+	x := []float64{-1.2, 1}
+
+	result, err := Minimize(p, x, nil, &BFGS{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.History != nil {
+		t.Errorf("unexpected history without Settings.History")
+	}
+
+	for _, test := range []struct {
+		name     string
+		settings Settings
+		method   Method
+		gradNorm bool
+	}{
+		{"BFGS", Settings{History: true}, &BFGS{}, true},
+		{"Scale", Settings{History: true, Scale: []float64{2, 0.5}}, &BFGS{}, true},
+		{"NelderMead", Settings{History: true, MajorIterations: 50}, &NelderMead{}, false},
+	} {
+		rec := &xRecorder{}
+		settings := test.settings
+		settings.Recorder = rec
+		result, err := Minimize(p, x, &settings, test.method)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		h := result.History
+		if h == nil {
+			t.Errorf("%s: history is nil", test.name)
+			continue
+		}
+		n := h.Len()
+		if n != result.MajorIterations {
+			t.Errorf("%s: unexpected history length: got %d, want %d", test.name, n, result.MajorIterations)
+		}
+		if len(h.GradNorm) != n || len(h.Step) != n || len(h.FuncEvaluations) != n ||
+			len(h.GradEvaluations) != n || len(h.HessEvaluations) != n {
+			t.Errorf("%s: history slices have different lengths", test.name)
+			continue
+		}
+		if n == 0 {
+			continue
+		}
+		if h.F[n-1] != result.F {
+			t.Errorf("%s: unexpected final function value: got %v, want %v", test.name, h.F[n-1], result.F)
+		}
+		if !math.IsNaN(h.Step[0]) {
+			t.Errorf("%s: first step is not NaN: %v", test.name, h.Step[0])
+		}
+		if test.gradNorm {
+			if want := floats.Norm(result.Gradient, math.Inf(1)); h.GradNorm[n-1] != want {
+				t.Errorf("%s: unexpected final gradient norm: got %v, want %v", test.name, h.GradNorm[n-1], want)
+			}
+		} else if !math.IsNaN(h.GradNorm[n-1]) {
+			t.Errorf("%s: gradient norm is not NaN without gradient", test.name)
+		}
+		// The recorded steps are with respect to the variables of the Problem.
+		for i := 1; i < n && i < len(rec.xs); i++ {
+			want := floats.Distance(rec.xs[i], rec.xs[i-1], math.Inf(1))
+			if math.Abs(h.Step[i]-want) > 1e-14*math.Max(1, want) {
+				t.Errorf("%s: unexpected step %d: got %v, want %v", test.name, i, h.Step[i], want)
+				break
+			}
+		}
+		for i := 1; i < n; i++ {
+			if h.FuncEvaluations[i] < h.FuncEvaluations[i-1] || h.GradEvaluations[i] < h.GradEvaluations[i-1] {
+				t.Errorf("%s: evaluation counts are not cumulative", test.name)
+				break
+			}
+		}
+		if h.FuncEvaluations[n-1] > result.FuncEvaluations {
+			t.Errorf("%s: more evaluations in history than in result", test.name)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+var (
+	_ Method        = (*ImplicitFiltering)(nil)
+	_ Statuser      = (*ImplicitFiltering)(nil)
+	_ Bounder       = (*ImplicitFiltering)(nil)
+	_ NoiseTolerant = (*ImplicitFiltering)(nil)
+)
+
+// implicitFilteringBacktracks is the maximum number of step reductions in the
+// line search of ImplicitFiltering.
+const implicitFilteringBacktracks = 4
+
+// ImplicitFiltering is a derivative-free optimizer for noisy functions. At
+// every iteration, the gradient at the current location is approximated by
+// central differences on a stencil of size h,
+//  g_i = (f(x + h e_i) - f(x - h e_i)) / 2h,
+// and a backtracking line search is performed along the negative of g with a
+// spectral step length as in SPG. Since the stencil is large compared to the
+// scale of the noise, the noise is filtered out of the approximate gradient.
+// If the line search fails, the best point of the stencil is taken instead.
+// If no point of the stencil decreases the function value by more than twice
+// the noise set by SetFunctionNoise, the stencil size is halved, and the
+// optimization concludes with MethodConverge status when it falls below
+// MinStep. See
+//  Kelley, C.T.: Implicit Filtering. SIAM (2011)
+// for more information.
+//
+// If the Problem has bounds, the stencil points and the trial locations are
+// projected onto the bounds. ImplicitFiltering evaluates the locations
+// sequentially, and it needs 2*dim function evaluations per iteration.
+type ImplicitFiltering struct {
+	// InitStep is the initial size of the stencil. If InitStep is 0, it is
+	// defaulted to 0.1 times the larger of 1 and the infinity norm of the
+	// initial location.
+	InitStep float64
+	// MinStep sets the threshold for stopping the optimization with
+	// MethodConverge status when the stencil size falls below it. If MinStep
+	// is 0, it is defaulted to 1e-6 times the initial stencil size.
+	MinStep float64
+
+	bounds []Bound
+	noise  float64
+	status Status
+
+	dim     int
+	h       float64   // Current stencil size
+	minStep float64   // Threshold of the stencil size
+	x       []float64 // Current location
+	f       float64   // Function value at x
+	grad    []float64 // Approximate gradient at x
+	lambda  float64   // Spectral step length
+
+	prevX    []float64 // Previous location with approximate gradient prevGrad
+	prevGrad []float64
+	hasPrev  bool
+
+	started bool      // Indicator that the function value at x is known
+	search  bool      // Indicator of the line search phase
+	k       int       // Index of the next stencil point or line search trial
+	fPlus   []float64 // Function values at x + h e_i
+	fMinus  []float64 // Function values at x - h e_i
+	bestX   []float64 // Best stencil point
+	bestF   float64
+	step    float64 // Current step of the line search
+}
+
+func (f *ImplicitFiltering) Status() (Status, error) {
+	return f.status, nil
+}
+
+func (*ImplicitFiltering) Uses(has Available) (uses Available, err error) {
+	return has.boundedFunction()
+}
+
+// SetBounds sets the bounds on the variables. It implements the Bounder
+// interface.
+func (f *ImplicitFiltering) SetBounds(bounds []Bound) {
+	f.bounds = bounds
+}
+
+// SetFunctionNoise sets the estimate of the noise in the function values. It
+// implements the NoiseTolerant interface.
+func (f *ImplicitFiltering) SetFunctionNoise(noise float64) {
+	f.noise = noise
+}
+
+func (f *ImplicitFiltering) Init(dim, tasks int) int {
+	if dim <= 0 {
+		panic(nonpositiveDimension)
+	}
+	if tasks < 0 {
+		panic(negativeTasks)
+	}
+	if f.InitStep < 0 {
+		panic("implicitfiltering: negative initial step")
+	}
+	if f.MinStep < 0 {
+		panic("implicitfiltering: negative minimum step")
+	}
+	f.status = NotTerminated
+	f.dim = dim
+	f.x = resize(f.x, dim)
+	f.grad = resize(f.grad, dim)
+	f.prevX = resize(f.prevX, dim)
+	f.prevGrad = resize(f.prevGrad, dim)
+	f.bestX = resize(f.bestX, dim)
+	f.fPlus = resize(f.fPlus, dim)
+	f.fMinus = resize(f.fMinus, dim)
+	f.hasPrev = false
+	f.started = false
+	return 1
+}
+
+func (f *ImplicitFiltering) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	task := tasks[0]
+	copy(f.x, task.X)
+	if task.Op&FuncEvaluation != 0 {
+		f.start(task.F)
+		task.Op = MajorIteration
+	} else {
+		task.Op = FuncEvaluation
+	}
+	operation <- task
+
+Loop:
+	for {
+		task := <-result
+		switch task.Op {
+		default:
+			panic("unknown operation")
+		case PostIteration:
+			break Loop
+		case MajorIteration:
+			f.stencilPoint(operation, task)
+		case FuncEvaluation:
+			f.evaluated(operation, task)
+		}
+	}
+	for range result {
+	}
+	close(operation)
+}
+
+// start sets the function value at the initial location and the stencil
+// size.
+func (f *ImplicitFiltering) start(fx float64) {
+	f.started = true
+	f.f = fx
+	f.h = f.InitStep
+	if f.h == 0 {
+		f.h = 0.1 * math.Max(1, floats.Norm(f.x, math.Inf(1)))
+	}
+	f.minStep = f.MinStep
+	if f.minStep == 0 {
+		f.minStep = 1e-6 * f.h
+	}
+	f.lambda = 1
+	f.search = false
+	f.k = 0
+}
+
+// evaluated processes the function value at the location of task, and sends
+// the next operation.
+func (f *ImplicitFiltering) evaluated(operation chan<- Task, task Task) {
+	if !f.started {
+		f.start(task.F)
+		task.Op = MajorIteration
+		operation <- task
+		return
+	}
+	if f.search {
+		f.searched(operation, task)
+		return
+	}
+	i := f.k / 2
+	if f.k%2 == 0 {
+		f.fPlus[i] = task.F
+	} else {
+		f.fMinus[i] = task.F
+	}
+	if task.F < f.bestF {
+		f.bestF = task.F
+		copy(f.bestX, task.X)
+	}
+	f.k++
+	if f.k < 2*f.dim {
+		f.stencilPoint(operation, task)
+		return
+	}
+
+	if !(f.bestF < f.f-2*f.noise) {
+		// Stencil failure. The stencil is too small to resolve the function
+		// from the noise, or it contains a minimizer.
+		f.h /= 2
+		if f.h < f.minStep {
+			f.status = MethodConverge
+			copy(task.X, f.x)
+			task.F = f.f
+			task.Op = MethodDone
+			operation <- task
+			return
+		}
+		f.k = 0
+		f.stencilPoint(operation, task)
+		return
+	}
+
+	f.gradient()
+	f.search = true
+	f.k = 0
+	f.step = 1
+	f.trial(operation, task)
+}
+
+// stencilPoint sends the evaluation of the next stencil point, starting a new
+// stencil if f.k is zero.
+func (f *ImplicitFiltering) stencilPoint(operation chan<- Task, task Task) {
+	if f.k == 0 {
+		f.search = false
+		f.bestF = math.Inf(1)
+	}
+	copy(task.X, f.x)
+	i := f.k / 2
+	if f.k%2 == 0 {
+		task.X[i] += f.h
+	} else {
+		task.X[i] -= f.h
+	}
+	project(task.X, f.bounds)
+	task.Op = FuncEvaluation
+	operation <- task
+}
+
+// gradient computes the approximate gradient from the stencil and updates the
+// spectral step length.
+func (f *ImplicitFiltering) gradient() {
+	for i, v := range f.x {
+		lo, hi := v-f.h, v+f.h
+		if f.bounds != nil {
+			lo = math.Max(lo, f.bounds[i].Min)
+			hi = math.Min(hi, f.bounds[i].Max)
+		}
+		fp, fm := f.fPlus[i], f.fMinus[i]
+		// Stencil points that are projected onto x have the function value
+		// at x.
+		if hi == v {
+			fp = f.f
+		}
+		if lo == v {
+			fm = f.f
+		}
+		if hi > lo {
+			f.grad[i] = (fp - fm) / (hi - lo)
+		} else {
+			f.grad[i] = 0
+		}
+	}
+	if f.hasPrev {
+		var sDotS, sDotY float64
+		for i, v := range f.x {
+			s := v - f.prevX[i]
+			sDotS += s * s
+			sDotY += s * (f.grad[i] - f.prevGrad[i])
+		}
+		if sDotY > 0 {
+			f.lambda = math.Min(spgMaxLambda, math.Max(spgMinLambda, sDotS/sDotY))
+		}
+	}
+	copy(f.prevX, f.x)
+	copy(f.prevGrad, f.grad)
+	f.hasPrev = true
+}
+
+// trial sends the evaluation of the next trial location of the line search,
+// or moves to the best stencil point if the line search has failed.
+func (f *ImplicitFiltering) trial(operation chan<- Task, task Task) {
+	if f.k < implicitFilteringBacktracks {
+		floats.AddScaledTo(task.X, f.x, -f.step*f.lambda, f.grad)
+		project(task.X, f.bounds)
+		if !floats.Equal(task.X, f.x) {
+			task.Op = FuncEvaluation
+			operation <- task
+			return
+		}
+	}
+	// The line search has failed, move to the best stencil point.
+	f.accept(operation, task, f.bestX, f.bestF)
+}
+
+// searched processes the function value at a trial location of the line
+// search.
+func (f *ImplicitFiltering) searched(operation chan<- Task, task Task) {
+	// The sufficient decrease condition with the projected step.
+	var slope float64
+	for i, v := range task.X {
+		slope += f.grad[i] * (v - f.x[i])
+	}
+	if task.F < f.f && task.F <= f.f+1e-4*slope {
+		if f.bestF < task.F {
+			f.accept(operation, task, f.bestX, f.bestF)
+			return
+		}
+		f.accept(operation, task, task.X, task.F)
+		return
+	}
+	f.k++
+	f.step /= 2
+	f.trial(operation, task)
+}
+
+// accept moves to the location x with function value fx and sends
+// a MajorIteration.
+func (f *ImplicitFiltering) accept(operation chan<- Task, task Task, x []float64, fx float64) {
+	copy(f.x, x)
+	f.f = fx
+	copy(task.X, x)
+	task.F = fx
+	task.Op = MajorIteration
+	f.k = 0
+	operation <- task
+}
@@ -0,0 +1,69 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// wigglyQuadratic is the function
+//  f(x) = Σ_i (x_i - 1)^2 + Noise * sin(1e4 * Σ_i x_i),
+// a quadratic with high-frequency noise of amplitude Noise.
+type wigglyQuadratic struct {
+	Noise float64
+}
+
+func (q wigglyQuadratic) Func(x []float64) float64 {
+	var f, sum float64
+	for _, v := range x {
+		f += (v - 1) * (v - 1)
+		sum += v
+	}
+	return f + q.Noise*math.Sin(1e4*sum)
+}
+
+func TestImplicitFiltering(t *testing.T) {
+	t.Parallel()
+	const noise = 1e-4
+	q := wigglyQuadratic{Noise: noise}
+	for _, test := range []struct {
+		name   string
+		bounds []Bound
+		want   []float64
+		tol    float64
+	}{
+		{
+			name: "Unbounded",
+			want: []float64{1, 1, 1, 1},
+			tol:  1e-2,
+		},
+		{
+			name:   "Bounded",
+			bounds: []Bound{{-1, 0.5}, {-1, 2}, {0, 0.25}, {-1, 2}},
+			want:   []float64{0.5, 1, 0.25, 1},
+			tol:    1e-2,
+		},
+	} {
+		p := Problem{Func: q.Func, Bounds: test.bounds}
+		settings := &Settings{
+			FunctionNoise: noise,
+			Converger:     NeverTerminate{},
+		}
+		res, err := Minimize(p, []float64{0, 0, 0, 0}, settings, &ImplicitFiltering{})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if res.Status != MethodConverge {
+			t.Errorf("%s: unexpected status: got %v, want %v", test.name, res.Status, MethodConverge)
+		}
+		if !floats.EqualApprox(res.X, test.want, test.tol) {
+			t.Errorf("%s: unexpected minimizer: got %v, want %v", test.name, res.X, test.want)
+		}
+	}
+}
@@ -4,6 +4,12 @@
 
 package optimize
 
+import (
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
 // A localMethod can optimize an objective function.
 //
 // It uses a reverse-communication interface between the optimization method
@@ -47,6 +53,14 @@ type localMethod interface {
 	needser
 }
 
+// gradNormer is a localMethod for a constrained problem. The gradient
+// convergence of a gradNormer is checked using the optimality measure returned
+// by gradNorm, which vanishes at a constrained minimum, instead of the norm of
+// the gradient.
+type gradNormer interface {
+	gradNorm(loc *Location) float64
+}
+
 type needser interface {
 	// needs specifies information about the objective function needed by the
 	// optimizer beyond just the function value. The information is used
@@ -65,6 +79,127 @@ type Statuser interface {
 	Status() (Status, error)
 }
 
+// WarmStarter is a Method whose state at the end of an optimization run can
+// be used to start the optimization of a similar problem of the same
+// dimension, for example the next problem in a sequence of problems with
+// slowly changing parameters. Unlike the state of a Checkpointer, the
+// warm-start state does not depend on the final location of the run, so that
+// the new optimization can start from any location.
+//
+// WarmState returns the state at the end of the last optimization run. It is
+// called by Minimize to fill Result.MethodState if Settings.ReturnMethodState
+// is true. SetWarmState sets the state
+// that replaces the default initial state at the next initialization of the
+// Method. It is called by Minimize with Settings.MethodState.
+type WarmStarter interface {
+	WarmState() ([]byte, error)
+	SetWarmState(data []byte) error
+}
+
+// Bounder is a Method that supports bound constraints on the variables.
+// Minimize calls SetBounds with the bounds of the Problem, which are nil if
+// the Problem is unconstrained, before the Method is initialized. The bounds
+// must not be modified.
+type Bounder interface {
+	SetBounds(bounds []Bound)
+}
+
+// Projector is a Method that supports the projection onto a convex feasible
+// set. Minimize calls SetProjection with the projection of the Problem, which
+// is nil if the Problem has no projection, before the Method is initialized.
+type Projector interface {
+	SetProjection(project func(x []float64))
+}
+
+// LinearConstrainer is a Method that supports linear constraints on the
+// variables. Minimize calls SetLinearConstraints with the linear constraints
+// of the Problem, which are nil if the Problem has no linear constraints,
+// before the Method is initialized. The constraints must not be modified.
+type LinearConstrainer interface {
+	SetLinearConstraints(c *LinearConstraints)
+}
+
+// NonlinearConstrainer is a Method that supports nonlinear constraints on the
+// variables. Minimize calls SetNonlinearConstraints with the nonlinear
+// constraints of the Problem, which are nil if the Problem has no nonlinear
+// constraints, before the Method is initialized. The constraint functions
+// are called directly by the Method and their evaluations are not counted
+// in Stats.
+type NonlinearConstrainer interface {
+	SetNonlinearConstraints(c *NonlinearConstraints)
+}
+
+// HessVecer is a Method that uses the Hessian-vector products of the
+// objective function. Minimize calls SetHessVec with the HessVec function of
+// the Problem, which is nil if the Problem does not provide it, before the
+// Method is initialized. The Hessian-vector products are computed directly by
+// the Method and are not counted in Stats.
+type HessVecer interface {
+	SetHessVec(hessVec func(dst, x, v []float64))
+}
+
+// MultiplierReporter is a Method that reports estimates of the Lagrange
+// multipliers of the constraints at the final location. Minimize calls
+// Multipliers after the Method has finished and stores the result in Result.
+type MultiplierReporter interface {
+	Multipliers() *Multipliers
+}
+
+// InverseHessianReporter is a Method that maintains an approximation of the
+// inverse Hessian of the objective function. If Settings.InverseHessian is
+// true, Minimize calls InverseHessian after the Method has finished and stores
+// the result in Result.
+type InverseHessianReporter interface {
+	// InverseHessian returns a copy of the approximation of the inverse
+	// Hessian at the final location loc of the optimization, or nil if no
+	// approximation has been computed. The Method may use loc to update the
+	// approximation with the step to the final location.
+	InverseHessian(loc *Location) *mat.SymDense
+}
+
+// KKTSolver solves the symmetric indefinite linear systems
+//  [ H  Aᵀ ] [ dx ]   [ r ]
+//  [ A  0  ] [ dy ] = [ q ]
+// that arise from the Karush-Kuhn-Tucker conditions of a quadratic model
+// subject to linear equality constraints, where H is a symmetric n×n matrix
+// and A is an m×n matrix. A KKTSolver can exploit the structure of the
+// matrices, for example the sparsity of the constraint Jacobian A, by
+// accessing them through the mat interfaces.
+type KKTSolver interface {
+	// SolveKKT stores the solution of the system in dx and dy. The matrix a
+	// is nil if m is zero. SolveKKT returns an error if the system is
+	// singular or too ill-conditioned to be solved. SolveKKT must not
+	// modify h, a, r and q.
+	SolveKKT(dx, dy []float64, h mat.Symmetric, a mat.Matrix, r, q []float64) error
+}
+
+// CoolingSchedule determines the temperature of SimulatedAnnealing at each
+// major iteration.
+type CoolingSchedule interface {
+	// Temperature returns the temperature at the major iteration iter ≥ 0
+	// for the initial temperature init. The temperature must be positive
+	// and must not increase with iter.
+	Temperature(init float64, iter int) float64
+}
+
+// Neighborer generates the candidate locations of SimulatedAnnealing.
+type Neighborer interface {
+	// Neighbor stores in dst a random neighbor of the location x at the
+	// temperature temp using the random number generator rnd. Neighbor
+	// must not modify x.
+	Neighbor(dst, x []float64, temp float64, rnd *rand.Rand)
+}
+
+// NoiseTolerant is a type that can relax its tests of the decrease of the
+// objective function for function values with noise. Minimize passes
+// Settings.FunctionNoise to a Method that implements NoiseTolerant, and the
+// line search Methods pass it on to a NoiseTolerant Linesearcher.
+type NoiseTolerant interface {
+	// SetFunctionNoise sets the estimate of the absolute noise in the
+	// function values. If noise is zero, the tests are not relaxed.
+	SetFunctionNoise(noise float64)
+}
+
 // Linesearcher is a type that can perform a line search. It tries to find an
 // (approximate) minimum of the objective function along the search direction
 // dir_k starting at the most recent location x_k, i.e., it tries to minimize
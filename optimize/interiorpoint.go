@@ -0,0 +1,610 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ Method               = (*InteriorPoint)(nil)
+	_ localMethod          = (*InteriorPoint)(nil)
+	_ Bounder              = (*InteriorPoint)(nil)
+	_ LinearConstrainer    = (*InteriorPoint)(nil)
+	_ NonlinearConstrainer = (*InteriorPoint)(nil)
+	_ MultiplierReporter   = (*InteriorPoint)(nil)
+	_ gradNormer           = (*InteriorPoint)(nil)
+)
+
+var errKKTSingular = errors.New("optimize: singular KKT system")
+
+// InteriorPoint implements a primal-dual interior-point method for
+// gradient-based minimization subject to nonlinear equality and inequality
+// constraints, linear constraints and bound constraints on the variables.
+//
+// InteriorPoint introduces slack variables s > 0 for the inequality
+// constraints and approximately solves the sequence of barrier problems
+//  minimize f(x) - μ Σ log(s_i)
+//  subject to c_E(x) = 0
+//             c_I(x) + s = 0
+// for a decreasing barrier parameter μ. The barrier parameter is decreased
+// once the optimality measure of the current barrier problem is sufficiently
+// small. The search direction is the primal-dual Newton step for the
+// optimality conditions of the barrier problem, where the Hessian of the
+// Lagrangian is replaced by a damped BFGS approximation. The steps of the
+// slack variables and of the multipliers of the inequality constraints are
+// limited by the fraction-to-boundary rule so that they remain positive, and
+// a backtracking search ensures sufficient decrease of the merit function
+//  f(x) - μ Σ log(s_i) + ν (Σ |c_E(x)| + Σ |c_I(x) + s|)
+// where the penalty parameter ν is increased as needed. See
+//  Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer (2006),
+//  chapter 19
+// for the details of the method.
+//
+// The constraints are taken from the Nonlinear, Linear and Bounds fields of
+// the Problem. The bounds are treated as inequality constraints, so neither
+// the initial location nor the iterates need to satisfy the constraints, and
+// the objective function may be evaluated outside the bounds. The Jacobian of
+// the equality constraints must have full row rank. The Lagrange multipliers
+// of the constraints at the final location are reported in Result.
+//
+// The iterates of InteriorPoint are not necessarily feasible and the
+// objective function may increase between major iterations, so a Converger
+// based on the decrease of the objective function may stop the optimization
+// early.
+type InteriorPoint struct {
+	// DecreaseFactor is the constant in the sufficient decrease condition
+	// of the backtracking search. It must be between zero and one, and if
+	// DecreaseFactor is 0, it will be defaulted to 1e-4.
+	DecreaseFactor float64
+	// GradStopThreshold sets the threshold for stopping if the optimality
+	// measure gets too small. The optimality measure is the maximum of the
+	// infinity norm of the gradient of the Lagrangian, of the largest
+	// constraint violation, and of the largest product of an inequality
+	// constraint and its multiplier. If GradStopThreshold is 0 it is
+	// defaulted to 1e-8, and if it is NaN the setting is not used.
+	GradStopThreshold float64
+	// InitBarrier is the initial value of the barrier parameter. If
+	// InitBarrier is 0, it will be defaulted to 0.1. InitBarrier must not
+	// be negative.
+	InitBarrier float64
+	// KKT solves the linear systems for the search direction, which have
+	// the dimension of the problem plus the number of equality constraints.
+	// If KKT is nil, the systems are solved using dense factorizations.
+	KKT KKTSolver
+
+	status Status
+	err    error
+
+	cons    constraintSet
+	dim     int
+	started bool    // Indicator that the constraints have been initialized
+	mu      float64 // Barrier parameter
+	muMin   float64 // Smallest value of the barrier parameter
+
+	x     []float64     // Location at the last major iteration
+	f     float64       // Function value at the last major iteration
+	grad  []float64     // Gradient at the last major iteration
+	cE    []float64     // Equality constraints at x
+	cI    []float64     // Inequality constraints at x
+	s     []float64     // Slack variables of the inequality constraints
+	y     []float64     // Multipliers of the equality constraints
+	z     []float64     // Multipliers of the inequality constraints
+	hess  *mat.SymDense // Approximation of the Hessian of the Lagrangian
+	first bool          // Indicator that the Hessian approximation has not been updated
+
+	dx, ds, dy, dz []float64 // Search direction
+	penalty        float64   // Penalty parameter of the merit function
+	merit          float64   // Merit function at x
+	step           float64   // Current step along the primal direction
+	stepZ          float64   // Step along dz
+	slope          float64   // Directional derivative of the merit function
+	stalled        bool      // Indicator that the last primal step did not change x
+	iter           int       // Number of backtracking steps in the current search
+
+	cETrial, cITrial, sTrial []float64 // Constraints and slacks at the trial location
+
+	next backtrackState // Next action taken in iterateLocal
+}
+
+func (ip *InteriorPoint) Status() (Status, error) {
+	return ip.status, ip.err
+}
+
+func (*InteriorPoint) Uses(has Available) (uses Available, err error) {
+	return has.nonlinearGradient()
+}
+
+// SetBounds sets the bounds on the variables. It implements the Bounder
+// interface.
+func (ip *InteriorPoint) SetBounds(bounds []Bound) {
+	ip.cons.bounds = bounds
+}
+
+// SetLinearConstraints sets the linear constraints on the variables. It
+// implements the LinearConstrainer interface.
+func (ip *InteriorPoint) SetLinearConstraints(c *LinearConstraints) {
+	ip.cons.linear = c
+}
+
+// SetNonlinearConstraints sets the nonlinear constraints on the variables.
+// It implements the NonlinearConstrainer interface.
+func (ip *InteriorPoint) SetNonlinearConstraints(c *NonlinearConstraints) {
+	ip.cons.nonlinear = c
+}
+
+func (ip *InteriorPoint) Init(dim, tasks int) int {
+	ip.status = NotTerminated
+	ip.err = nil
+	ip.started = false
+	return 1
+}
+
+func (ip *InteriorPoint) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	thresh := ip.GradStopThreshold
+	if thresh == 0 {
+		thresh = 1e-8
+	}
+	ip.muMin = 1e-9
+	if thresh > 0 {
+		ip.muMin = thresh / 10
+	}
+	ip.status, ip.err = localOptimizer{}.run(ip, thresh, operation, result, tasks)
+	close(operation)
+}
+
+// Multipliers returns the multipliers of the constraints at the last major
+// iteration. It implements the MultiplierReporter interface.
+func (ip *InteriorPoint) Multipliers() *Multipliers {
+	if !ip.started {
+		return nil
+	}
+	return ip.cons.multipliers(ip.y, ip.z)
+}
+
+// start initializes the constraints, the slack variables and the multipliers
+// at the initial location x.
+func (ip *InteriorPoint) start(x []float64) {
+	if ip.InitBarrier < 0 {
+		panic("interiorpoint: negative InitBarrier")
+	}
+	ip.mu = ip.InitBarrier
+	if ip.mu == 0 {
+		ip.mu = 0.1
+	}
+	ip.started = true
+	dim := len(x)
+	ip.dim = dim
+	ip.cons.init(dim)
+	mE, mI := ip.cons.mE, ip.cons.mI
+	ip.x = resize(ip.x, dim)
+	ip.cE = resize(ip.cE, mE)
+	ip.cI = resize(ip.cI, mI)
+	ip.s = resize(ip.s, mI)
+	ip.y = resize(ip.y, mE)
+	ip.z = resize(ip.z, mI)
+	ip.cETrial = resize(ip.cETrial, mE)
+	ip.cITrial = resize(ip.cITrial, mI)
+	ip.sTrial = resize(ip.sTrial, mI)
+
+	copy(ip.x, x)
+	ip.cons.eval(ip.cE, ip.cI, x)
+	ip.cons.jacobians(x)
+	for i := range ip.y {
+		ip.y[i] = 0
+	}
+	// Start the slack variables away from zero and the multipliers on the
+	// central path.
+	for i, c := range ip.cI {
+		ip.s[i] = math.Max(-c, 1)
+		ip.z[i] = ip.mu / ip.s[i]
+	}
+}
+
+// lagrangianGrad stores in dst the gradient of the Lagrangian with the
+// multipliers y and z at the last major iteration.
+func (ip *InteriorPoint) lagrangianGrad(dst, y, z []float64) {
+	copy(dst, ip.grad)
+	for i, row := range ip.cons.jE {
+		floats.AddScaled(dst, y[i], row)
+	}
+	for i, row := range ip.cons.jI {
+		floats.AddScaled(dst, z[i], row)
+	}
+}
+
+// barrierError returns the optimality measure of the barrier problem with
+// the barrier parameter mu at the last major iteration.
+func (ip *InteriorPoint) barrierError(mu float64) float64 {
+	gl := make([]float64, ip.dim)
+	ip.lagrangianGrad(gl, ip.y, ip.z)
+	norm := floats.Norm(gl, math.Inf(1))
+	for _, c := range ip.cE {
+		norm = math.Max(norm, math.Abs(c))
+	}
+	for i, c := range ip.cI {
+		norm = math.Max(norm, math.Abs(c+ip.s[i]))
+		norm = math.Max(norm, math.Abs(ip.s[i]*ip.z[i]-mu))
+	}
+	return norm
+}
+
+// gradNorm returns the optimality measure at loc.
+func (ip *InteriorPoint) gradNorm(loc *Location) float64 {
+	if !ip.started {
+		ip.start(loc.X)
+		ip.grad = resize(ip.grad, ip.dim)
+		copy(ip.grad, loc.Gradient)
+	}
+	gl := make([]float64, ip.dim)
+	ip.lagrangianGrad(gl, ip.y, ip.z)
+	norm := floats.Norm(gl, math.Inf(1))
+	for _, c := range ip.cE {
+		norm = math.Max(norm, math.Abs(c))
+	}
+	for i, c := range ip.cI {
+		norm = math.Max(norm, c)
+		norm = math.Max(norm, math.Abs(ip.z[i]*c))
+	}
+	return norm
+}
+
+func (ip *InteriorPoint) initLocal(loc *Location) (Operation, error) {
+	if ip.DecreaseFactor == 0 {
+		ip.DecreaseFactor = 1e-4
+	}
+	if ip.DecreaseFactor <= 0 || ip.DecreaseFactor >= 1 {
+		panic("interiorpoint: DecreaseFactor must be between 0 and 1")
+	}
+	if !ip.started || !floats.Equal(ip.x, loc.X) {
+		ip.start(loc.X)
+	}
+	dim := len(loc.X)
+	ip.grad = resize(ip.grad, dim)
+	ip.dx = resize(ip.dx, dim)
+	ip.ds = resize(ip.ds, ip.cons.mI)
+	ip.dy = resize(ip.dy, ip.cons.mE)
+	ip.dz = resize(ip.dz, ip.cons.mI)
+	ip.hess = resizeSymDense(ip.hess, dim)
+	ip.resetHessian()
+	ip.penalty = 0
+	ip.stalled = false
+
+	copy(ip.grad, loc.Gradient)
+	ip.f = loc.F
+	return ip.initSearch(loc)
+}
+
+// resetHessian sets the Hessian approximation to the identity.
+func (ip *InteriorPoint) resetHessian() {
+	for i := 0; i < ip.dim; i++ {
+		for j := i; j < ip.dim; j++ {
+			if i == j {
+				ip.hess.SetSym(i, i, 1)
+			} else {
+				ip.hess.SetSym(i, j, 0)
+			}
+		}
+	}
+	ip.first = true
+}
+
+func (ip *InteriorPoint) iterateLocal(loc *Location) (Operation, error) {
+	switch ip.next {
+	default:
+		panic("interiorpoint: unexpected state")
+	case backtrackSearch:
+		ip.cons.eval(ip.cETrial, ip.cITrial, loc.X)
+		// Reset the slack variables of the inequality constraints that are
+		// satisfied with a larger margin. This decreases the merit function.
+		for i, c := range ip.cITrial {
+			ip.sTrial[i] = math.Max(ip.sTrial[i], -c)
+		}
+		if ip.meritAt(loc.F, ip.cETrial, ip.cITrial, ip.sTrial) <= ip.merit+ip.DecreaseFactor*ip.step*ip.slope {
+			// The trial location is accepted, evaluate the gradient.
+			ip.next = backtrackGrad
+			return GradEvaluation, nil
+		}
+		ip.iter++
+		if ip.iter >= maxBacktracks {
+			return NoOperation, ErrLinesearcherFailure
+		}
+		ip.step /= 2
+		if !ip.trial(loc) {
+			return ip.restart(loc)
+		}
+		return FuncEvaluation, nil
+	case backtrackGrad:
+		ip.update(loc)
+		ip.stalled = false
+		ip.next = backtrackMajor
+		return MajorIteration, nil
+	case backtrackMajor:
+		return ip.initSearch(loc)
+	}
+}
+
+// meritAt returns the merit function for the function value f, the
+// constraints cE and cI and the slack variables s.
+func (ip *InteriorPoint) meritAt(f float64, cE, cI, s []float64) float64 {
+	merit := f
+	var viol float64
+	for _, c := range cE {
+		viol += math.Abs(c)
+	}
+	for i, c := range cI {
+		merit -= ip.mu * math.Log(s[i])
+		viol += math.Abs(c + s[i])
+	}
+	return merit + ip.penalty*viol
+}
+
+// updateDual takes the steps along the directions of the multipliers.
+func (ip *InteriorPoint) updateDual() {
+	floats.AddScaled(ip.y, ip.step, ip.dy)
+	floats.AddScaled(ip.z, ip.stepZ, ip.dz)
+	// Keep the multipliers of the inequality constraints within a factor
+	// of their values on the central path so that they cannot diverge.
+	const kappa = 1e10
+	for i, s := range ip.s {
+		ip.z[i] = math.Max(ip.mu/(kappa*s), math.Min(ip.z[i], kappa*ip.mu/s))
+	}
+}
+
+// update accepts loc and the steps of the slack variables and multipliers,
+// and updates the Hessian approximation with the change of the gradient of
+// the Lagrangian using the damped BFGS update.
+func (ip *InteriorPoint) update(loc *Location) {
+	dim := ip.dim
+	copy(ip.s, ip.sTrial)
+	ip.updateDual()
+
+	step := mat.NewVecDense(dim, nil)
+	step.SubVec(mat.NewVecDense(dim, loc.X), mat.NewVecDense(dim, ip.x))
+	y := mat.NewVecDense(dim, nil)
+	ip.lagrangianGrad(y.RawVector().Data, ip.y, ip.z)
+	y.ScaleVec(-1, y)
+
+	copy(ip.x, loc.X)
+	copy(ip.grad, loc.Gradient)
+	ip.f = loc.F
+	copy(ip.cE, ip.cETrial)
+	copy(ip.cI, ip.cITrial)
+	ip.cons.jacobians(loc.X)
+
+	gl := make([]float64, dim)
+	ip.lagrangianGrad(gl, ip.y, ip.z)
+	y.AddVec(y, mat.NewVecDense(dim, gl))
+	ip.first = dampedBFGS(ip.hess, step, y, ip.first)
+}
+
+// initSearch decreases the barrier parameter if the barrier problem has been
+// solved sufficiently accurately, computes the search direction at the last
+// major iteration, updates the penalty parameter and sets loc.X to the first
+// trial location of the backtracking search.
+func (ip *InteriorPoint) initSearch(loc *Location) (Operation, error) {
+	// Decrease the barrier parameter superlinearly as described in
+	// Wächter, A., Biegler, L.: On the implementation of an interior-point
+	// filter line-search algorithm for large-scale nonlinear programming.
+	// Mathematical Programming 106 (2006), equation 7.
+	const (
+		kappaEps = 10
+		kappaMu  = 0.2
+		thetaMu  = 1.5
+	)
+	for ip.mu > ip.muMin && ip.barrierError(ip.mu) <= kappaEps*ip.mu {
+		ip.mu = math.Max(ip.muMin, math.Min(kappaMu*ip.mu, math.Pow(ip.mu, thetaMu)))
+	}
+
+	sigma, err := ip.direction()
+	if err != nil {
+		return NoOperation, err
+	}
+
+	// Update the penalty parameter so that the search direction is
+	// a descent direction of the merit function as described in
+	// Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer (2006), equation 19.28.
+	var viol float64
+	for _, c := range ip.cE {
+		viol += math.Abs(c)
+	}
+	for i, c := range ip.cI {
+		viol += math.Abs(c + ip.s[i])
+	}
+	gd := floats.Dot(ip.grad, ip.dx)
+	var sds float64
+	for i, s := range ip.s {
+		gd -= ip.mu * ip.ds[i] / s
+		sds += sigma[i] * ip.ds[i] * ip.ds[i]
+	}
+	bd := mat.NewVecDense(ip.dim, nil)
+	dv := mat.NewVecDense(ip.dim, ip.dx)
+	bd.MulVec(ip.hess, dv)
+	quad := mat.Dot(dv, bd) + sds
+	if viol > 0 {
+		ip.penalty = math.Max(ip.penalty, (gd+0.5*quad)/(0.5*viol))
+	}
+	ip.merit = ip.meritAt(ip.f, ip.cE, ip.cI, ip.s)
+	ip.slope = gd - ip.penalty*viol
+
+	// Apply the fraction-to-boundary rule.
+	tau := math.Max(0.99, 1-ip.mu)
+	ip.step = maxStep(ip.s, ip.ds, tau)
+	ip.stepZ = maxStep(ip.z, ip.dz, tau)
+
+	ip.iter = 0
+	if !ip.trial(loc) {
+		// The primal step does not change x, so take the steps of the
+		// slack variables and the multipliers directly and report the last
+		// major iteration again so that the optimality is checked.
+		if ip.stalled && ip.mu == ip.muMin {
+			return NoOperation, ErrNoProgress
+		}
+		ip.stalled = true
+		for i, c := range ip.cI {
+			ip.s[i] = math.Max(ip.sTrial[i], -c)
+		}
+		ip.updateDual()
+		copy(loc.X, ip.x)
+		copy(loc.Gradient, ip.grad)
+		loc.F = ip.f
+		ip.next = backtrackMajor
+		return MajorIteration, nil
+	}
+	if ip.slope >= 0 {
+		return ip.restart(loc)
+	}
+	ip.next = backtrackSearch
+	return FuncEvaluation, nil
+}
+
+// maxStep returns the largest step in (0, 1] along d such that
+// v + step*d ≥ (1-tau) v.
+func maxStep(v, d []float64, tau float64) float64 {
+	step := 1.0
+	for i, di := range d {
+		if di < 0 {
+			step = math.Min(step, -tau*v[i]/di)
+		}
+	}
+	return step
+}
+
+// restart resets the Hessian approximation and starts a new search. restart is
+// called when the search along the quasi-Newton direction does not make
+// progress.
+func (ip *InteriorPoint) restart(loc *Location) (Operation, error) {
+	if ip.first {
+		return NoOperation, ErrNoProgress
+	}
+	ip.resetHessian()
+	return ip.initSearch(loc)
+}
+
+// trial sets loc.X and the trial slack variables to the location at the
+// current step along the search direction. trial returns false if the
+// location does not differ from the location of the last major iteration.
+func (ip *InteriorPoint) trial(loc *Location) bool {
+	floats.AddScaledTo(loc.X, ip.x, ip.step, ip.dx)
+	floats.AddScaledTo(ip.sTrial, ip.s, ip.step, ip.ds)
+	return !floats.Equal(loc.X, ip.x)
+}
+
+// direction computes the primal-dual search direction at the last major
+// iteration and returns the ratios z_i/s_i. The steps dz and ds are
+// eliminated from the Newton equations, which leaves the system
+//  [ B + J_Iᵀ Σ J_I  J_Eᵀ ] [ dx ]   [ -∇f - J_Eᵀ y - J_Iᵀ (μ S⁻¹ e + Σ (c_I + s)) ]
+//  [ J_E             0    ] [ dy ] = [ -c_E                                        ]
+// with Σ = S⁻¹ Z. If the system is singular, a multiple of the identity is
+// added to its upper left block.
+func (ip *InteriorPoint) direction() (sigma []float64, err error) {
+	dim := ip.dim
+	mE, mI := ip.cons.mE, ip.cons.mI
+	sigma = make([]float64, mI)
+	h := mat.NewSymDense(dim, nil)
+	h.CopySym(ip.hess)
+	r := make([]float64, dim)
+	for i := range r {
+		r[i] = -ip.grad[i]
+	}
+	for i, row := range ip.cons.jE {
+		floats.AddScaled(r, -ip.y[i], row)
+	}
+	for i, row := range ip.cons.jI {
+		sigma[i] = ip.z[i] / ip.s[i]
+		floats.AddScaled(r, -ip.mu/ip.s[i]-sigma[i]*(ip.cI[i]+ip.s[i]), row)
+		h.SymRankOne(h, sigma[i], mat.NewVecDense(dim, row))
+	}
+	q := make([]float64, mE)
+	for i, c := range ip.cE {
+		q[i] = -c
+	}
+	var a mat.Matrix
+	if mE > 0 {
+		jE := mat.NewDense(mE, dim, nil)
+		for i, row := range ip.cons.jE {
+			jE.SetRow(i, row)
+		}
+		a = jE
+	}
+
+	solver := ip.KKT
+	if solver == nil {
+		solver = denseKKT{}
+	}
+	const maxRegularize = 10
+	var delta float64
+	for k := 0; k < maxRegularize; k++ {
+		err = solver.SolveKKT(ip.dx, ip.dy, h, a, r, q)
+		if err == nil {
+			break
+		}
+		if delta == 0 {
+			delta = 1e-8 * math.Max(1, mat.Norm(ip.hess, math.Inf(1)))
+		} else {
+			delta *= 100
+		}
+		for i := 0; i < dim; i++ {
+			h.SetSym(i, i, h.At(i, i)+delta)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, row := range ip.cons.jI {
+		ip.ds[i] = -ip.cI[i] - ip.s[i] - floats.Dot(row, ip.dx)
+		ip.dz[i] = ip.mu/ip.s[i] - ip.z[i] - sigma[i]*ip.ds[i]
+	}
+	return sigma, nil
+}
+
+func (*InteriorPoint) needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return struct {
+		Gradient bool
+		Hessian  bool
+	}{true, false}
+}
+
+// denseKKT is the default KKTSolver of InteriorPoint. It uses the Cholesky
+// factorization if there are no equality constraints and the LU
+// factorization otherwise.
+type denseKKT struct{}
+
+func (denseKKT) SolveKKT(dx, dy []float64, h mat.Symmetric, a mat.Matrix, r, q []float64) error {
+	n := h.Symmetric()
+	if a == nil {
+		var chol mat.Cholesky
+		if ok := chol.Factorize(h); !ok {
+			return errKKTSingular
+		}
+		return chol.SolveVecTo(mat.NewVecDense(n, dx), mat.NewVecDense(n, r))
+	}
+	m, _ := a.Dims()
+	k := mat.NewDense(n+m, n+m, nil)
+	k.Slice(0, n, 0, n).(*mat.Dense).Copy(h)
+	k.Slice(0, n, n, n+m).(*mat.Dense).Copy(a.T())
+	k.Slice(n, n+m, 0, n).(*mat.Dense).Copy(a)
+	rhs := make([]float64, n+m)
+	copy(rhs, r)
+	copy(rhs[n:], q)
+
+	var lu mat.LU
+	lu.Factorize(k)
+	sol := mat.NewVecDense(n+m, nil)
+	if err := lu.SolveVecTo(sol, false, mat.NewVecDense(n+m, rhs)); err != nil {
+		return err
+	}
+	copy(dx, sol.RawVector().Data[:n])
+	copy(dy, sol.RawVector().Data[n:])
+	return nil
+}
@@ -0,0 +1,216 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestInteriorPoint(t *testing.T) {
+	t.Parallel()
+	inf := math.Inf(1)
+	quad := weightedQuadratic([]float64{1, 1}, []float64{1, 2})
+	eq, eqJac := circle(1)
+	hs, hsCons := hs071()
+	for _, test := range []struct {
+		name      string
+		p         Problem
+		nonlinear *NonlinearConstraints
+		linear    *LinearConstraints
+		bounds    []Bound
+		x         []float64
+		want      []float64
+		wantF     float64
+	}{
+		{
+			name:      "equality",
+			p:         quad,
+			nonlinear: &NonlinearConstraints{NumEq: 1, Eq: eq, EqJac: eqJac},
+			x:         []float64{0, 0.5},
+			want:      []float64{1 / math.Sqrt(5), 2 / math.Sqrt(5)},
+		},
+		{
+			name:      "active inequality",
+			p:         quad,
+			nonlinear: &NonlinearConstraints{NumIneq: 1, Ineq: eq, IneqJac: eqJac},
+			x:         []float64{3, -3},
+			want:      []float64{1 / math.Sqrt(5), 2 / math.Sqrt(5)},
+		},
+		{
+			name:      "inactive inequality",
+			p:         weightedQuadratic([]float64{1, 1}, []float64{0.25, 0.5}),
+			nonlinear: &NonlinearConstraints{NumIneq: 1, Ineq: eq, IneqJac: eqJac},
+			x:         []float64{0, 0},
+			want:      []float64{0.25, 0.5},
+		},
+		{
+			name:      "nonlinear and linear",
+			p:         weightedQuadratic([]float64{1, 1, 1}, []float64{1, 1, 1}),
+			nonlinear: &NonlinearConstraints{NumEq: 1, Eq: eq, EqJac: eqJac},
+			linear: &LinearConstraints{
+				A: mat.NewDense(1, 3, []float64{0, 0, 1}),
+				B: []float64{0},
+			},
+			x:    []float64{1, 1, 0},
+			want: []float64{1 / math.Sqrt2, 1 / math.Sqrt2, 0},
+		},
+		{
+			name:      "nonlinear and bounds",
+			p:         quad,
+			nonlinear: &NonlinearConstraints{NumIneq: 1, Ineq: eq, IneqJac: eqJac},
+			bounds:    []Bound{{-inf, inf}, {-inf, 0.5}},
+			x:         []float64{0, 0},
+			want:      []float64{math.Sqrt(0.75), 0.5},
+		},
+		{
+			name: "linear inequality",
+			p:    weightedQuadratic([]float64{1, 1}, []float64{2, 2}),
+			linear: &LinearConstraints{
+				G: mat.NewDense(1, 2, []float64{1, 1}),
+				H: []float64{2},
+			},
+			x:    []float64{0, 0},
+			want: []float64{1, 1},
+		},
+		{
+			name: "rosenbrock",
+			p: Problem{
+				Func: functions.ExtendedRosenbrock{}.Func,
+				Grad: functions.ExtendedRosenbrock{}.Grad,
+			},
+			nonlinear: &NonlinearConstraints{NumIneq: 1, Ineq: eq, IneqJac: eqJac},
+			x:         []float64{-1.2, 1},
+			want:      []float64{0.7864151541684231, 0.6176983125233985},
+		},
+		{
+			name:      "hs071",
+			p:         hs,
+			nonlinear: hsCons,
+			x:         []float64{1, 5, 5, 1},
+			want:      []float64{1, 4.742999, 3.821150, 1.379408},
+			wantF:     17.0140173,
+		},
+	} {
+		p := test.p
+		p.Nonlinear = test.nonlinear
+		if test.linear != nil {
+			p.Linear = test.linear
+		}
+		if test.bounds != nil {
+			p.Bounds = test.bounds
+		}
+		x := make([]float64, len(test.x))
+		copy(x, test.x)
+		result, err := Minimize(p, x, nil, &InteriorPoint{})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if result.Status != GradientThreshold {
+			t.Errorf("%s: unexpected status: got %v, want %v", test.name, result.Status, GradientThreshold)
+		}
+		if !floats.EqualApprox(result.X, test.want, 1e-6) {
+			t.Errorf("%s: unexpected optimum: got %v, want %v", test.name, result.X, test.want)
+		}
+		if test.wantF != 0 && math.Abs(result.F-test.wantF) > 1e-6 {
+			t.Errorf("%s: unexpected optimal value: got %v, want %v", test.name, result.F, test.wantF)
+		}
+		if result.ConstraintViolation > 1e-8 {
+			t.Errorf("%s: unexpected constraint violation: %v", test.name, result.ConstraintViolation)
+		}
+		if result.Complementarity > 1e-8 {
+			t.Errorf("%s: unexpected complementarity: %v", test.name, result.Complementarity)
+		}
+
+		m := result.Multipliers
+		if m == nil {
+			t.Errorf("%s: no multipliers reported", test.name)
+			continue
+		}
+		for _, l := range [][]float64{m.LinearIneq, m.Ineq, m.Lower, m.Upper} {
+			for _, v := range l {
+				if v < 0 {
+					t.Errorf("%s: negative multiplier of inequality constraint: %v", test.name, l)
+				}
+			}
+		}
+	}
+}
+
+// countingKKT is a KKTSolver that counts the solved systems.
+type countingKKT struct {
+	n int
+}
+
+func (c *countingKKT) SolveKKT(dx, dy []float64, h mat.Symmetric, a mat.Matrix, r, q []float64) error {
+	c.n++
+	return denseKKT{}.SolveKKT(dx, dy, h, a, r, q)
+}
+
+func TestInteriorPointKKT(t *testing.T) {
+	t.Parallel()
+	eq, eqJac := circle(1)
+	p := weightedQuadratic([]float64{1, 1}, []float64{1, 2})
+	p.Nonlinear = &NonlinearConstraints{NumEq: 1, Eq: eq, EqJac: eqJac}
+	kkt := &countingKKT{}
+	result, err := Minimize(p, []float64{3, 3}, nil, &InteriorPoint{KKT: kkt})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if kkt.n == 0 {
+		t.Errorf("KKT solver not used")
+	}
+	want := math.Sqrt(5) - 1
+	if got := result.Multipliers.Eq[0]; math.Abs(got-want) > 1e-6 {
+		t.Errorf("unexpected multiplier: got %v, want %v", got, want)
+	}
+
+	// The dense solver must solve the system
+	//  [ 2 0 1 ] [ dx₀ ]   [ 1 ]
+	//  [ 0 2 1 ] [ dx₁ ] = [ 3 ]
+	//  [ 1 1 0 ] [ dy  ]   [ 2 ]
+	h := mat.NewSymDense(2, []float64{2, 0, 0, 2})
+	a := mat.NewDense(1, 2, []float64{1, 1})
+	dx := make([]float64, 2)
+	dy := make([]float64, 1)
+	err = denseKKT{}.SolveKKT(dx, dy, h, a, []float64{1, 3}, []float64{2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !floats.EqualApprox(dx, []float64{0.5, 1.5}, 1e-14) || math.Abs(dy[0]) > 1e-14 {
+		t.Errorf("unexpected solution: got %v, %v, want [0.5 1.5], [0]", dx, dy)
+	}
+	singular := mat.NewSymDense(2, []float64{1, 1, 1, 1})
+	if err := (denseKKT{}).SolveKKT(dx, nil, singular, nil, []float64{1, 3}, nil); err == nil {
+		t.Errorf("no error for singular system")
+	}
+}
+
+func TestMaxStep(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		v, d []float64
+		want float64
+	}{
+		{v: []float64{1, 2}, d: []float64{1, 1}, want: 1},
+		{v: []float64{1, 2}, d: []float64{-2, 1}, want: 0.495},
+		{v: []float64{1, 2}, d: []float64{-0.5, -4}, want: 0.495},
+	} {
+		got := maxStep(test.v, test.d, 0.99)
+		if math.Abs(got-test.want) > 1e-15 {
+			t.Errorf("unexpected step for v=%v, d=%v: got %v, want %v", test.v, test.d, got, test.want)
+		}
+		for i, v := range test.v {
+			if v+got*test.d[i] < 0.01*v-1e-15 {
+				t.Errorf("fraction-to-boundary rule violated for v=%v, d=%v", test.v, test.d)
+			}
+		}
+	}
+}
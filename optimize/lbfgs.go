@@ -5,13 +5,23 @@
 package optimize
 
 import (
+	"bytes"
+	"encoding/gob"
+	"math"
+
 	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
 )
 
 var (
 	_ Method          = (*LBFGS)(nil)
 	_ localMethod     = (*LBFGS)(nil)
 	_ NextDirectioner = (*LBFGS)(nil)
+	_ NoiseTolerant   = (*LBFGS)(nil)
+	_ Checkpointer    = (*LBFGS)(nil)
+	_ WarmStarter     = (*LBFGS)(nil)
+
+	_ InverseHessianReporter = (*LBFGS)(nil)
 )
 
 // LBFGS implements the limited-memory BFGS method for gradient-based
@@ -40,7 +50,8 @@ type LBFGS struct {
 	status Status
 	err    error
 
-	ls *LinesearchMethod
+	ls    *LinesearchMethod
+	noise float64 // Estimate of the noise in the function values
 
 	dim  int       // Dimension of the problem
 	x    []float64 // Location at the last major iteration
@@ -52,12 +63,24 @@ type LBFGS struct {
 	s      [][]float64 // Last Store values of s
 	rho    []float64   // Last Store values of rho
 	a      []float64   // Cache of Hessian updates
+
+	warm *lbfgsWarmState // Initial history set by SetWarmState.
+
+	started bool // Indicator that the initial direction has been computed.
+	resume  bool // Indicator that the state has been restored from a checkpoint.
 }
 
 func (l *LBFGS) Status() (Status, error) {
 	return l.status, l.err
 }
 
+// SetFunctionNoise sets the estimate of the noise in the function values, which
+// is passed to the Linesearcher if it is NoiseTolerant. It implements the
+// NoiseTolerant interface.
+func (l *LBFGS) SetFunctionNoise(noise float64) {
+	l.noise = noise
+}
+
 func (*LBFGS) Uses(has Available) (uses Available, err error) {
 	return has.gradient()
 }
@@ -65,6 +88,7 @@ func (*LBFGS) Uses(has Available) (uses Available, err error) {
 func (l *LBFGS) Init(dim, tasks int) int {
 	l.status = NotTerminated
 	l.err = nil
+	l.started = false
 	return 1
 }
 
@@ -85,8 +109,18 @@ func (l *LBFGS) initLocal(loc *Location) (Operation, error) {
 		l.ls = &LinesearchMethod{}
 	}
 	l.ls.Linesearcher = l.Linesearcher
+	setLinesearchNoise(l.Linesearcher, l.noise)
 	l.ls.NextDirectioner = l
 
+	if l.resume {
+		l.resume = false
+		if len(loc.X) != l.dim {
+			panic("lbfgs: checkpoint size mismatch")
+		}
+		l.started = true
+		l.warm = nil
+		return l.ls.resume(loc)
+	}
 	return l.ls.Init(loc)
 }
 
@@ -98,6 +132,7 @@ func (l *LBFGS) InitDirection(loc *Location, dir []float64) (stepSize float64) {
 	dim := len(loc.X)
 	l.dim = dim
 	l.oldest = 0
+	l.started = true
 
 	l.a = resize(l.a, l.Store)
 	l.rho = resize(l.rho, l.Store)
@@ -111,6 +146,26 @@ func (l *LBFGS) InitDirection(loc *Location, dir []float64) (stepSize float64) {
 	copy(l.grad, loc.Gradient)
 
 	copy(dir, loc.Gradient)
+	if warm := l.warm; warm != nil {
+		l.warm = nil
+		if warm.Dim != dim {
+			panic("lbfgs: warm state size mismatch")
+		}
+		// Start with the history of the previous run and scale the initial
+		// Hessian using its most recent element.
+		recent := (warm.Oldest + warm.Store - 1) % warm.Store
+		if rho := warm.Rho[recent]; rho > 0 && !math.IsInf(rho, 1) {
+			l.oldest = warm.Oldest
+			copy(l.rho, warm.Rho)
+			for i := range l.y {
+				copy(l.y[i], warm.Y[i])
+				copy(l.s[i], warm.S[i])
+			}
+			y := l.y[recent]
+			l.twoLoop(dir, 1/(rho*floats.Dot(y, y)))
+			return 1
+		}
+	}
 	floats.Scale(-1, dir)
 	return 1 / floats.Norm(dir, 2)
 }
@@ -158,6 +213,14 @@ func (l *LBFGS) NextDirection(loc *Location, dir []float64) (stepSize float64) {
 	copy(l.grad, loc.Gradient)
 	copy(dir, loc.Gradient)
 
+	l.twoLoop(dir, sDotY/floats.Dot(y, y))
+	return 1
+}
+
+// twoLoop replaces the gradient in dir by the search direction -H*g, where H
+// is the inverse Hessian approximation of the history with the initial
+// approximation gamma*I.
+func (l *LBFGS) twoLoop(dir []float64, gamma float64) {
 	// Start with the most recent element and go backward,
 	for i := 0; i < l.Store; i++ {
 		idx := l.oldest - i - 1
@@ -169,7 +232,6 @@ func (l *LBFGS) NextDirection(loc *Location, dir []float64) (stepSize float64) {
 	}
 
 	// Scale the initial Hessian.
-	gamma := sDotY / floats.Dot(y, y)
 	floats.Scale(gamma, dir)
 
 	// Start with the oldest element and go forward.
@@ -184,8 +246,6 @@ func (l *LBFGS) NextDirection(loc *Location, dir []float64) (stepSize float64) {
 
 	// dir contains H^{-1} * g, so flip the direction for minimization.
 	floats.Scale(-1, dir)
-
-	return 1
 }
 
 func (*LBFGS) needs() struct {
@@ -197,3 +257,186 @@ func (*LBFGS) needs() struct {
 		Hessian  bool
 	}{true, false}
 }
+
+// lbfgsState is the checkpointed state of LBFGS.
+type lbfgsState struct {
+	Started bool
+	Store   int
+	Dim     int
+	X       []float64
+	Grad    []float64
+	Oldest  int
+	Y       [][]float64
+	S       [][]float64
+	Rho     []float64
+}
+
+// MarshalState returns the state of the limited-memory history and of the last
+// major iteration. It implements the Checkpointer interface.
+func (l *LBFGS) MarshalState() ([]byte, error) {
+	st := lbfgsState{Started: l.started}
+	if l.started {
+		st.Store = l.Store
+		st.Dim = l.dim
+		st.X = l.x
+		st.Grad = l.grad
+		st.Oldest = l.oldest
+		st.Y = l.y
+		st.S = l.s
+		st.Rho = l.rho
+	}
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(st)
+	return buf.Bytes(), err
+}
+
+// UnmarshalState restores the state returned by MarshalState, including the
+// value of Store. It implements the Checkpointer interface.
+func (l *LBFGS) UnmarshalState(data []byte) error {
+	var st lbfgsState
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&st)
+	if err != nil {
+		return err
+	}
+	l.resume = st.Started
+	if !st.Started {
+		return nil
+	}
+	if st.Store <= 0 || st.Oldest < 0 || st.Oldest >= st.Store ||
+		len(st.X) != st.Dim || len(st.Grad) != st.Dim ||
+		len(st.Y) != st.Store || len(st.S) != st.Store || len(st.Rho) != st.Store {
+		return ErrBadCheckpoint
+	}
+	l.Store = st.Store
+	l.dim = st.Dim
+	l.oldest = st.Oldest
+	l.x = resize(l.x, st.Dim)
+	copy(l.x, st.X)
+	l.grad = resize(l.grad, st.Dim)
+	copy(l.grad, st.Grad)
+	l.rho = resize(l.rho, st.Store)
+	copy(l.rho, st.Rho)
+	l.a = resize(l.a, st.Store)
+	l.y = l.initHistory(l.y)
+	l.s = l.initHistory(l.s)
+	for i := 0; i < st.Store; i++ {
+		if len(st.Y[i]) != st.Dim || len(st.S[i]) != st.Dim {
+			return ErrBadCheckpoint
+		}
+		copy(l.y[i], st.Y[i])
+		copy(l.s[i], st.S[i])
+	}
+	return nil
+}
+
+// lbfgsWarmState is the warm-start state of LBFGS.
+type lbfgsWarmState struct {
+	Store  int
+	Dim    int
+	Oldest int
+	Y      [][]float64
+	S      [][]float64
+	Rho    []float64
+}
+
+// InverseHessian returns the dense inverse Hessian approximation of the
+// limited-memory history updated with the step to the final location loc if
+// it has positive curvature, or nil if there is no approximation. The
+// approximation of the history is formed by applying the two-loop recursion
+// to the unit vectors, which costs O(Store*dim^2) operations. It implements
+// the InverseHessianReporter interface.
+func (l *LBFGS) InverseHessian(loc *Location) *mat.SymDense {
+	if !l.started {
+		return nil
+	}
+	dim := l.dim
+	var s, y mat.VecDense
+	var sDotY float64
+	if len(loc.X) == dim && len(loc.Gradient) == dim {
+		s.SubVec(mat.NewVecDense(dim, loc.X), mat.NewVecDense(dim, l.x))
+		y.SubVec(mat.NewVecDense(dim, loc.Gradient), mat.NewVecDense(dim, l.grad))
+		sDotY = mat.Dot(&s, &y)
+	}
+	final := sDotY > 0
+
+	// Scale the initial approximation of the history using its most recent
+	// element or the final step.
+	var gamma float64
+	recent := (l.oldest + l.Store - 1) % l.Store
+	rho := l.rho[recent]
+	switch {
+	case final:
+		gamma = sDotY / mat.Dot(&y, &y)
+	case rho > 0 && !math.IsInf(rho, 1):
+		gamma = 1 / (rho * floats.Dot(l.y[recent], l.y[recent]))
+	default:
+		return nil
+	}
+	h := mat.NewDense(l.dim, l.dim, nil)
+	col := make([]float64, l.dim)
+	for j := range col {
+		for i := range col {
+			col[i] = 0
+		}
+		col[j] = -1
+		// twoLoop stores -H*col = H*e_j in col.
+		l.twoLoop(col, gamma)
+		h.SetCol(j, col)
+	}
+	invHess := mat.NewSymDense(l.dim, nil)
+	for i := 0; i < l.dim; i++ {
+		for j := i; j < l.dim; j++ {
+			invHess.SetSym(i, j, (h.At(i, j)+h.At(j, i))/2)
+		}
+	}
+	if final {
+		var tmp mat.VecDense
+		updateInverseBFGS(invHess, &s, &y, &tmp)
+	}
+	return invHess
+}
+
+// WarmState returns the limited-memory history at the end of the last run.
+// It implements the WarmStarter interface.
+func (l *LBFGS) WarmState() ([]byte, error) {
+	var st lbfgsWarmState
+	if l.started {
+		st.Store = l.Store
+		st.Dim = l.dim
+		st.Oldest = l.oldest
+		st.Y = l.y
+		st.S = l.s
+		st.Rho = l.rho
+	}
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(st)
+	return buf.Bytes(), err
+}
+
+// SetWarmState sets the limited-memory history returned by WarmState as the
+// initial history of the next run, which must have the same dimension. The
+// value of Store is restored with the history. It implements the WarmStarter
+// interface.
+func (l *LBFGS) SetWarmState(data []byte) error {
+	var st lbfgsWarmState
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&st)
+	if err != nil {
+		return err
+	}
+	if st.Store == 0 {
+		l.warm = nil
+		return nil
+	}
+	if st.Store < 0 || st.Dim <= 0 || st.Oldest < 0 || st.Oldest >= st.Store ||
+		len(st.Y) != st.Store || len(st.S) != st.Store || len(st.Rho) != st.Store {
+		return ErrBadWarmState
+	}
+	for i := 0; i < st.Store; i++ {
+		if len(st.Y[i]) != st.Dim || len(st.S[i]) != st.Dim {
+			return ErrBadWarmState
+		}
+	}
+	l.Store = st.Store
+	l.warm = &st
+	return nil
+}
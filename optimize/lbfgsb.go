@@ -0,0 +1,325 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+var (
+	_ Method      = (*LBFGSB)(nil)
+	_ localMethod = (*LBFGSB)(nil)
+	_ Bounder     = (*LBFGSB)(nil)
+	_ gradNormer  = (*LBFGSB)(nil)
+)
+
+// LBFGSB implements a limited-memory quasi-Newton method for gradient-based
+// minimization subject to bound constraints on the variables.
+//
+// At every iteration the variables are split into a binding set of variables
+// that lie at (or within a small distance of) a bound with the gradient
+// pointing out of the feasible region, and a free set. The search direction
+// is the limited-memory BFGS direction computed from the gradient restricted
+// to the free variables, and the scaled steepest descent direction for the
+// binding variables. A backtracking search along the projection of the search
+// direction onto the bounds then ensures sufficient decrease of the objective
+// function. See
+//  Kim, D., Sra, S., Dhillon, I.: Tackling box-constrained optimization via a
+//  new projected quasi-Newton approach. SIAM J. Sci. Comput. 32(6) (2010)
+// for the details of the method.
+//
+// The bounds are taken from the Bounds field of the Problem. If the Problem
+// has no bounds, LBFGSB is a limited-memory BFGS method with a backtracking
+// line search.
+type LBFGSB struct {
+	// Store is the size of the limited-memory storage.
+	// If Store is 0, it will be defaulted to 15.
+	Store int
+	// DecreaseFactor is the constant in the sufficient decrease condition
+	// of the backtracking search. It must be between zero and one, and if
+	// DecreaseFactor is 0, it will be defaulted to 1e-4.
+	DecreaseFactor float64
+	// GradStopThreshold sets the threshold for stopping if the norm of the
+	// projected gradient gets too small. If GradStopThreshold is 0 it is
+	// defaulted to 1e-12, and if it is NaN the setting is not used.
+	GradStopThreshold float64
+
+	status Status
+	err    error
+
+	bounds []Bound
+
+	dim   int
+	x     []float64 // Location at the last major iteration
+	f     float64   // Function value at the last major iteration
+	grad  []float64 // Gradient at the last major iteration
+	dir   []float64 // Search direction
+	step  float64   // Current step along dir
+	slope float64   // Directional derivative along the projected trial step
+	iter  int       // Number of backtracking steps in the current search
+
+	next backtrackState // Next action taken in iterateLocal
+
+	// History
+	oldest int         // Index of the oldest element of the history
+	count  int         // Number of valid elements of the history
+	y      [][]float64 // Last Store values of y
+	s      [][]float64 // Last Store values of s
+	rho    []float64   // Last Store values of rho
+	a      []float64   // Cache of Hessian updates
+	gamma  float64     // Scaling of the initial inverse Hessian
+}
+
+// backtrackState is the state of the backtracking search of LBFGSB and
+// ActiveSet.
+type backtrackState int
+
+const (
+	backtrackSearch backtrackState = iota // A function value at the trial location has been evaluated.
+	backtrackGrad                         // The gradient at the accepted location has been evaluated.
+	backtrackMajor                        // The accepted location did not converge the optimization.
+)
+
+const (
+	// maxBacktracks is the maximum number of backtracking steps in a search
+	// of LBFGSB.
+	maxBacktracks = 100
+
+	// dlamchE is the machine epsilon.
+	dlamchE = 1.0 / (1 << 53)
+)
+
+func (l *LBFGSB) Status() (Status, error) {
+	return l.status, l.err
+}
+
+func (*LBFGSB) Uses(has Available) (uses Available, err error) {
+	return has.boundedGradient()
+}
+
+// SetBounds sets the bounds on the variables. It implements the Bounder
+// interface.
+func (l *LBFGSB) SetBounds(bounds []Bound) {
+	l.bounds = bounds
+}
+
+func (l *LBFGSB) Init(dim, tasks int) int {
+	l.status = NotTerminated
+	l.err = nil
+	return 1
+}
+
+func (l *LBFGSB) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	l.status, l.err = localOptimizer{}.run(l, l.GradStopThreshold, operation, result, tasks)
+	close(operation)
+}
+
+func (l *LBFGSB) initLocal(loc *Location) (Operation, error) {
+	if l.Store == 0 {
+		l.Store = 15
+	}
+	if l.DecreaseFactor == 0 {
+		l.DecreaseFactor = 1e-4
+	}
+	if l.DecreaseFactor <= 0 || l.DecreaseFactor >= 1 {
+		panic("lbfgsb: DecreaseFactor must be between 0 and 1")
+	}
+	if l.bounds != nil && len(l.bounds) != len(loc.X) {
+		panic("lbfgsb: bounds length mismatch")
+	}
+
+	dim := len(loc.X)
+	l.dim = dim
+	l.x = resize(l.x, dim)
+	l.grad = resize(l.grad, dim)
+	l.dir = resize(l.dir, dim)
+	l.a = resize(l.a, l.Store)
+	l.rho = resize(l.rho, l.Store)
+	l.y = l.initHistory(l.y)
+	l.s = l.initHistory(l.s)
+	l.oldest = 0
+	l.count = 0
+	l.gamma = 1
+
+	l.accept(loc)
+	return l.initSearch(loc)
+}
+
+func (l *LBFGSB) initHistory(hist [][]float64) [][]float64 {
+	c := cap(hist)
+	if c < l.Store {
+		n := make([][]float64, l.Store-c)
+		hist = append(hist[:c], n...)
+	}
+	hist = hist[:l.Store]
+	for i := range hist {
+		hist[i] = resize(hist[i], l.dim)
+	}
+	return hist
+}
+
+func (l *LBFGSB) iterateLocal(loc *Location) (Operation, error) {
+	switch l.next {
+	default:
+		panic("lbfgsb: unexpected state")
+	case backtrackSearch:
+		if loc.F <= l.f+l.DecreaseFactor*l.slope {
+			// The trial location is accepted, evaluate the gradient.
+			l.next = backtrackGrad
+			return GradEvaluation, nil
+		}
+		l.iter++
+		if l.iter >= maxBacktracks {
+			return NoOperation, ErrLinesearcherFailure
+		}
+		l.step /= 2
+		if !l.trial(loc) {
+			return l.restart(loc)
+		}
+		return FuncEvaluation, nil
+	case backtrackGrad:
+		l.update(loc)
+		l.next = backtrackMajor
+		return MajorIteration, nil
+	case backtrackMajor:
+		return l.initSearch(loc)
+	}
+}
+
+// gradNorm returns the infinity norm of the projected gradient at loc.
+func (l *LBFGSB) gradNorm(loc *Location) float64 {
+	return projGradNorm(loc.X, loc.Gradient, l.bounds)
+}
+
+// accept stores loc as the location of the last major iteration.
+func (l *LBFGSB) accept(loc *Location) {
+	copy(l.x, loc.X)
+	copy(l.grad, loc.Gradient)
+	l.f = loc.F
+}
+
+// update updates the limited-memory history with the step from the last major
+// iteration to loc, and accepts loc. The update is skipped unless the curvature
+// s·y is sufficiently positive relative to the lengths of s and y, so that the
+// inverse Hessian approximation remains positive definite.
+func (l *LBFGSB) update(loc *Location) {
+	y := l.y[l.oldest]
+	floats.SubTo(y, loc.Gradient, l.grad)
+	s := l.s[l.oldest]
+	floats.SubTo(s, loc.X, l.x)
+	sDotY := floats.Dot(s, y)
+	yDotY := floats.Dot(y, y)
+	if sDotY > dlamchE*floats.Norm(s, 2)*math.Sqrt(yDotY) {
+		l.rho[l.oldest] = 1 / sDotY
+		l.gamma = sDotY / yDotY
+		l.oldest = (l.oldest + 1) % l.Store
+		if l.count < l.Store {
+			l.count++
+		}
+	}
+	l.accept(loc)
+}
+
+// initSearch computes the search direction at the last major iteration and
+// sets loc.X to the first trial location of the backtracking search.
+func (l *LBFGSB) initSearch(loc *Location) (Operation, error) {
+	l.direction()
+	l.iter = 0
+	l.step = 1
+	if l.count == 0 {
+		l.step = math.Min(1, 1/floats.Norm(l.dir, 2))
+	}
+	if !l.trial(loc) {
+		return l.restart(loc)
+	}
+	l.next = backtrackSearch
+	return FuncEvaluation, nil
+}
+
+// restart discards the limited-memory history and starts a new search along
+// the steepest descent direction. restart is called when the search along the
+// quasi-Newton direction does not make progress.
+func (l *LBFGSB) restart(loc *Location) (Operation, error) {
+	if l.count == 0 {
+		return NoOperation, ErrNoProgress
+	}
+	l.count = 0
+	l.gamma = 1
+	return l.initSearch(loc)
+}
+
+// trial sets loc.X to the projection of the location at the current step
+// along the search direction, and computes the directional derivative along
+// the projected step. trial returns false if the projected location does not
+// differ from the location of the last major iteration.
+func (l *LBFGSB) trial(loc *Location) bool {
+	floats.AddScaledTo(loc.X, l.x, l.step, l.dir)
+	project(loc.X, l.bounds)
+	if floats.Equal(loc.X, l.x) {
+		return false
+	}
+	l.slope = 0
+	for i, g := range l.grad {
+		l.slope += g * (loc.X[i] - l.x[i])
+	}
+	return true
+}
+
+// direction computes the search direction at the last major iteration.
+func (l *LBFGSB) direction() {
+	dir := l.dir
+
+	// Determine the binding variables. A variable is binding if it lies
+	// within eps of a bound and the gradient points out of the feasible
+	// region, where eps is the norm of the projected gradient.
+	eps := projGradNorm(l.x, l.grad, l.bounds)
+	copy(dir, l.grad)
+	for i, b := range l.bounds {
+		if (l.x[i] <= b.Min+eps && l.grad[i] > 0) || (l.x[i] >= b.Max-eps && l.grad[i] < 0) {
+			dir[i] = 0
+		}
+	}
+
+	// Compute the quasi-Newton direction for the free variables using the
+	// two-loop recursion as described in
+	// Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer (2006), chapter 7, page 178.
+	for i := 0; i < l.count; i++ {
+		idx := l.oldest - i - 1
+		if idx < 0 {
+			idx += l.Store
+		}
+		l.a[idx] = l.rho[idx] * floats.Dot(l.s[idx], dir)
+		floats.AddScaled(dir, -l.a[idx], l.y[idx])
+	}
+	floats.Scale(l.gamma, dir)
+	for i := l.count - 1; i >= 0; i-- {
+		idx := l.oldest - i - 1
+		if idx < 0 {
+			idx += l.Store
+		}
+		beta := l.rho[idx] * floats.Dot(l.y[idx], dir)
+		floats.AddScaled(dir, l.a[idx]-beta, l.s[idx])
+	}
+
+	// The binding variables follow the scaled steepest descent direction.
+	for i, b := range l.bounds {
+		if (l.x[i] <= b.Min+eps && l.grad[i] > 0) || (l.x[i] >= b.Max-eps && l.grad[i] < 0) {
+			dir[i] = l.gamma * l.grad[i]
+		}
+	}
+	floats.Scale(-1, dir)
+}
+
+func (*LBFGSB) needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return struct {
+		Gradient bool
+		Hessian  bool
+	}{true, false}
+}
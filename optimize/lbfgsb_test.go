@@ -0,0 +1,162 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestLBFGSB(t *testing.T) {
+	t.Parallel()
+	inf := math.Inf(1)
+	center := []float64{3, -2, 0.5, -5}
+	quad := Problem{
+		Func: func(x []float64) float64 {
+			var f float64
+			for i, v := range x {
+				f += float64(i+1) * (v - center[i]) * (v - center[i])
+			}
+			return f
+		},
+		Grad: func(grad, x []float64) {
+			for i, v := range x {
+				grad[i] = 2 * float64(i+1) * (v - center[i])
+			}
+		},
+	}
+	rosen := Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+		Grad: functions.ExtendedRosenbrock{}.Grad,
+	}
+
+	for _, test := range []struct {
+		name   string
+		p      Problem
+		bounds []Bound
+		x      []float64
+		want   []float64
+	}{
+		{
+			name:   "quadratic",
+			p:      quad,
+			bounds: []Bound{{-1, 1}, {-1, 1}, {-1, 1}, {-inf, -6}},
+			x:      []float64{0, 0, 0, -10},
+			want:   []float64{1, -1, 0.5, -6},
+		},
+		{
+			name:   "quadratic at bounds",
+			p:      quad,
+			bounds: []Bound{{-1, 1}, {-1, 1}, {-1, 1}, {-inf, -6}},
+			x:      []float64{1, -1, 1, -6},
+			want:   []float64{1, -1, 0.5, -6},
+		},
+		{
+			name: "quadratic unbounded",
+			p:    quad,
+			x:    []float64{0, 0, 0, 0},
+			want: center,
+		},
+		{
+			name:   "rosenbrock",
+			p:      rosen,
+			bounds: []Bound{{-2, 0.5}, {-2, 2}},
+			x:      []float64{-1.2, 1},
+			want:   []float64{0.5, 0.25},
+		},
+		{
+			name:   "rosenbrock inactive bounds",
+			p:      rosen,
+			bounds: []Bound{{-2, 2}, {-inf, inf}},
+			x:      []float64{-1.2, 1},
+			want:   []float64{1, 1},
+		},
+	} {
+		p := test.p
+		p.Bounds = test.bounds
+		x := make([]float64, len(test.x))
+		copy(x, test.x)
+		result, err := Minimize(p, x, nil, &LBFGSB{})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if result.Status != GradientThreshold {
+			t.Errorf("%s: unexpected status: got %v, want %v", test.name, result.Status, GradientThreshold)
+		}
+		if !floats.EqualApprox(result.X, test.want, 1e-6) {
+			t.Errorf("%s: unexpected optimum: got %v, want %v", test.name, result.X, test.want)
+		}
+		if !floats.Equal(x, test.x) {
+			t.Errorf("%s: initial location modified", test.name)
+		}
+		if test.bounds != nil {
+			if err := checkBounds(test.bounds, result.X); err != nil {
+				t.Errorf("%s: optimum outside bounds: %v", test.name, result.X)
+			}
+		}
+	}
+}
+
+func TestMinimizeBounds(t *testing.T) {
+	t.Parallel()
+	p := Problem{
+		Func:   functions.ExtendedRosenbrock{}.Func,
+		Grad:   functions.ExtendedRosenbrock{}.Grad,
+		Bounds: []Bound{{-2, 0.5}, {-2, 2}},
+	}
+	want := []float64{0.5, 0.25}
+
+	// The default method supports the bounds.
+	result, err := Minimize(p, []float64{-1.2, 1}, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !floats.EqualApprox(result.X, want, 1e-6) {
+		t.Errorf("unexpected optimum with default method: got %v, want %v", result.X, want)
+	}
+
+	// The gradient convergence of Settings uses the projected gradient.
+	result, err = Minimize(p, []float64{-1.2, 1}, &Settings{
+		GradientThreshold: 1e-6,
+		Converger:         NeverTerminate{},
+	}, &LBFGSB{GradStopThreshold: math.NaN()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != GradientThreshold {
+		t.Errorf("unexpected status: got %v, want %v", result.Status, GradientThreshold)
+	}
+	if norm := floats.Norm(result.Gradient, math.Inf(1)); norm < 1e-6 {
+		t.Errorf("unexpected gradient norm at constrained optimum: %v", norm)
+	}
+
+	_, err = Minimize(p, []float64{1, 1}, nil, &LBFGSB{})
+	if err != ErrOutOfBounds {
+		t.Errorf("unexpected error for infeasible initial location: got %v, want %v", err, ErrOutOfBounds)
+	}
+
+	if !panics(func() { Minimize(p, []float64{-1.2, 1}, nil, &LBFGS{}) }) {
+		t.Errorf("no panic for bounds with unconstrained method")
+	}
+	p.Bounds = p.Bounds[:1]
+	if !panics(func() { Minimize(p, []float64{-1.2, 1}, nil, &LBFGSB{}) }) {
+		t.Errorf("no panic for bounds length mismatch")
+	}
+}
+
+func panics(f func()) (b bool) {
+	defer func() {
+		err := recover()
+		if err != nil {
+			b = true
+		}
+	}()
+	f()
+	return
+}
@@ -0,0 +1,97 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// linearTol is the relative tolerance used for checking that a location
+// satisfies linear constraints.
+const linearTol = 1e-8
+
+// LinearConstraints represents the linear equality and inequality constraints
+//  A * x = B
+//  G * x ≤ H
+// on the variables x of a problem of dimension n. A is an m×n matrix and B
+// has length m, and G is a k×n matrix and H has length k. If there are no
+// equality constraints, A and B must be nil, and if there are no inequality
+// constraints, G and H must be nil.
+type LinearConstraints struct {
+	A mat.Matrix
+	B []float64
+
+	G mat.Matrix
+	H []float64
+}
+
+// equality returns the rows of A and the elements of B.
+func (c *LinearConstraints) equality() (rows [][]float64, rhs []float64) {
+	return constraintRows(c.A, c.B)
+}
+
+// inequality returns the rows of G and the elements of H.
+func (c *LinearConstraints) inequality() (rows [][]float64, rhs []float64) {
+	return constraintRows(c.G, c.H)
+}
+
+func constraintRows(a mat.Matrix, b []float64) (rows [][]float64, rhs []float64) {
+	if a == nil {
+		return nil, nil
+	}
+	r, _ := a.Dims()
+	rows = make([][]float64, r)
+	for i := range rows {
+		rows[i] = mat.Row(nil, i, a)
+	}
+	return rows, b
+}
+
+// checkLinear checks that the linear constraints c are valid for a problem
+// with the dimension of x, and that x satisfies them. checkLinear panics if
+// the constraints are not valid and returns ErrInfeasible if x does not
+// satisfy the constraints.
+func checkLinear(c *LinearConstraints, x []float64) error {
+	if c == nil {
+		return nil
+	}
+	for _, v := range []struct {
+		a mat.Matrix
+		b []float64
+	}{
+		{c.A, c.B},
+		{c.G, c.H},
+	} {
+		if v.a == nil {
+			if v.b != nil {
+				panic("optimize: nil constraint matrix with non-nil right-hand side")
+			}
+			continue
+		}
+		r, n := v.a.Dims()
+		if n != len(x) {
+			panic("optimize: constraint matrix column mismatch")
+		}
+		if r != len(v.b) {
+			panic("optimize: constraint right-hand side length mismatch")
+		}
+	}
+	rows, rhs := c.equality()
+	for i, row := range rows {
+		if math.Abs(floats.Dot(row, x)-rhs[i]) > linearTol*(1+math.Abs(rhs[i])) {
+			return ErrInfeasible
+		}
+	}
+	rows, rhs = c.inequality()
+	for i, row := range rows {
+		if floats.Dot(row, x)-rhs[i] > linearTol*(1+math.Abs(rhs[i])) {
+			return ErrInfeasible
+		}
+	}
+	return nil
+}
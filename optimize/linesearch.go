@@ -29,7 +29,35 @@ type LinesearchMethod struct {
 	lastOp   Operation // Operation returned from the previous call to Iterate.
 }
 
+// historyLinesearcher is a Linesearcher that keeps information across the line
+// searches of an optimization, which is cleared when a new optimization is
+// started.
+type historyLinesearcher interface {
+	Linesearcher
+	resetHistory()
+}
+
+// setLinesearchNoise passes the estimate of the noise in the function values
+// to ls if it is NoiseTolerant.
+func setLinesearchNoise(ls Linesearcher, noise float64) {
+	if nt, ok := ls.(NoiseTolerant); ok {
+		nt.SetFunctionNoise(noise)
+	}
+}
+
 func (ls *LinesearchMethod) Init(loc *Location) (Operation, error) {
+	return ls.init(loc, true)
+}
+
+// resume initializes the LinesearchMethod to continue an optimization from
+// the major iteration at loc. The NextDirectioner must have been restored to
+// its state at that major iteration, and is asked for the next direction
+// instead of the initial one.
+func (ls *LinesearchMethod) resume(loc *Location) (Operation, error) {
+	return ls.init(loc, false)
+}
+
+func (ls *LinesearchMethod) init(loc *Location, first bool) (Operation, error) {
 	if loc.Gradient == nil {
 		panic("linesearch: gradient is nil")
 	}
@@ -38,8 +66,11 @@ func (ls *LinesearchMethod) Init(loc *Location) (Operation, error) {
 	ls.x = resize(ls.x, dim)
 	ls.dir = resize(ls.dir, dim)
 
-	ls.first = true
+	ls.first = first
 	ls.nextMajor = false
+	if h, ok := ls.Linesearcher.(historyLinesearcher); ok && first {
+		h.resetHistory()
+	}
 
 	// Indicate that all fields of loc are valid.
 	ls.eval = FuncEvaluation | GradEvaluation
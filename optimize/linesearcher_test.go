@@ -42,6 +42,51 @@ func TestBacktracking(t *testing.T) {
 	testLinesearcher(t, ls, d, 0, false)
 }
 
+func TestNonmonotoneBacktracking(t *testing.T) {
+	t.Parallel()
+	d := 0.001
+	// With a Memory of 1 the nonmonotone condition is the Armijo condition,
+	// which is checked by testLinesearcher.
+	ls := &NonmonotoneBacktracking{
+		Memory:         1,
+		DecreaseFactor: d,
+	}
+	testLinesearcher(t, ls, d, 0, false)
+}
+
+func TestLinesearcherNoise(t *testing.T) {
+	t.Parallel()
+	for _, ls := range []Linesearcher{
+		&Backtracking{},
+		&NonmonotoneBacktracking{},
+		&Bisection{},
+		&MoreThuente{},
+	} {
+		// A step that increases the function value by less than twice the
+		// noise satisfies the relaxed sufficient decrease condition.
+		ls.(NoiseTolerant).SetFunctionNoise(1e-3)
+		op := ls.Init(0, -1e-6, 1)
+		if op&FuncEvaluation == 0 {
+			t.Fatalf("%T: function evaluation expected", ls)
+		}
+		op, _, err := ls.Iterate(1e-3, 0)
+		if err != nil {
+			t.Errorf("%T: unexpected error: %v", ls, err)
+			continue
+		}
+		if op != MajorIteration && op != GradEvaluation {
+			t.Errorf("%T: step within the noise not accepted", ls)
+		}
+
+		ls.(NoiseTolerant).SetFunctionNoise(0)
+		ls.Init(0, -1e-6, 1)
+		op, _, _ = ls.Iterate(1e-3, 0)
+		if op == MajorIteration || op == GradEvaluation {
+			t.Errorf("%T: increasing step accepted without noise", ls)
+		}
+	}
+}
+
 type funcGrader interface {
 	Func([]float64) float64
 	Grad([]float64, []float64)
@@ -25,7 +25,7 @@ func (l localOptimizer) run(method localMethod, gradThresh float64, operation ch
 		l.finish(operation, result)
 		return NotTerminated, nil
 	}
-	status, err := l.checkStartingLocation(task, gradThresh)
+	status, err := l.checkStartingLocation(method, task, gradThresh)
 	if err != nil {
 		l.finishMethodDone(operation, result, task)
 		return status, err
@@ -55,7 +55,7 @@ Loop:
 		case MajorIteration:
 			// The last operation was a MajorIteration. Check if the gradient
 			// is below the threshold.
-			if status := l.checkGradientConvergence(r.Gradient, gradThresh); status != NotTerminated {
+			if status := l.checkGradientConvergence(method, r.Location, gradThresh); status != NotTerminated {
 				l.finishMethodDone(operation, result, task)
 				return GradientThreshold, nil
 			}
@@ -100,7 +100,7 @@ func (l localOptimizer) initialLocation(operation chan<- Task, result <-chan Tas
 	return <-result
 }
 
-func (l localOptimizer) checkStartingLocation(task Task, gradThresh float64) (Status, error) {
+func (l localOptimizer) checkStartingLocation(method localMethod, task Task, gradThresh float64) (Status, error) {
 	if math.IsInf(task.F, 1) || math.IsNaN(task.F) {
 		return Failure, ErrFunc(task.F)
 	}
@@ -109,18 +109,31 @@ func (l localOptimizer) checkStartingLocation(task Task, gradThresh float64) (St
 			return Failure, ErrGrad{Grad: v, Index: i}
 		}
 	}
-	status := l.checkGradientConvergence(task.Gradient, gradThresh)
+	if g := task.SparseGradient; g != nil && task.Gradient == nil {
+		for k, v := range g.Value {
+			if math.IsInf(v, 0) || math.IsNaN(v) {
+				return Failure, ErrGrad{Grad: v, Index: g.Index[k]}
+			}
+		}
+	}
+	status := l.checkGradientConvergence(method, task.Location, gradThresh)
 	return status, nil
 }
 
-func (localOptimizer) checkGradientConvergence(gradient []float64, gradThresh float64) Status {
-	if gradient == nil || math.IsNaN(gradThresh) {
+func (localOptimizer) checkGradientConvergence(method localMethod, loc *Location, gradThresh float64) Status {
+	if loc.Gradient == nil || math.IsNaN(gradThresh) {
 		return NotTerminated
 	}
 	if gradThresh == 0 {
 		gradThresh = defaultGradientAbsTol
 	}
-	if norm := floats.Norm(gradient, math.Inf(1)); norm < gradThresh {
+	var norm float64
+	if gn, ok := method.(gradNormer); ok {
+		norm = gn.gradNorm(loc)
+	} else {
+		norm = floats.Norm(loc.Gradient, math.Inf(1))
+	}
+	if norm < gradThresh {
 		return GradientThreshold
 	}
 	return NotTerminated
@@ -0,0 +1,10 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package lsq implements methods for solving nonlinear least-squares
+// problems,
+//  minimize ½ |r(x)|²,
+// where r is a vector of residuals, for example the differences between
+// a model with parameters x and observed data in curve fitting.
+package lsq // import "gonum.org/v1/gonum/optimize/lsq"
@@ -0,0 +1,38 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsq_test
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"gonum.org/v1/gonum/optimize/lsq"
+)
+
+func ExampleMinimize() {
+	// Fit the model y = a exp(b t) to observations.
+	t := []float64{0, 1, 2, 3, 4, 5}
+	y := []float64{2.03, 3.28, 5.49, 8.98, 14.81, 24.43}
+	p := lsq.Problem{
+		Residual: func(dst, x []float64) {
+			for i, ti := range t {
+				dst[i] = x[0]*math.Exp(x[1]*ti) - y[i]
+			}
+		},
+		Residuals: len(t),
+	}
+	res, err := lsq.Minimize(p, []float64{1, 0}, nil, &lsq.LevenbergMarquardt{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for i, name := range []string{"a", "b"} {
+		stdErr := math.Sqrt(res.Covariance.At(i, i))
+		fmt.Printf("%s = %.3f ± %.3f\n", name, res.X[i], stdErr)
+	}
+	// Output:
+	// a = 2.009 ± 0.009
+	// b = 0.500 ± 0.001
+}
@@ -0,0 +1,96 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsq
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+var _ Method = (*GaussNewton)(nil)
+
+// GaussNewton is the Gauss-Newton method with line search for nonlinear least
+// squares. The search direction d at each iteration is the solution of the
+// linear least-squares problem
+//  minimize |J d + r|,
+// which is computed from a QR factorization of the Jacobian J, and the step
+// along d is found by the Linesearcher applied to the cost ½ |r(x)|².
+//
+// GaussNewton converges quickly on problems with a well-conditioned Jacobian
+// and small residuals at the minimum. LevenbergMarquardt is more robust on
+// problems with a rank-deficient Jacobian, where the Gauss-Newton direction
+// is not defined. GaussNewton terminates with Failure status and the
+// mat.Condition error if the Jacobian is singular or too ill-conditioned.
+type GaussNewton struct {
+	// Linesearcher performs the line searches. If Linesearcher is nil,
+	// a default value of &optimize.Backtracking{} is used.
+	Linesearcher optimize.Linesearcher
+
+	ls   optimize.Linesearcher
+	dir  *mat.VecDense
+	negR *mat.VecDense
+	x    []float64
+	dx   []float64
+	r    []float64
+	jac  *mat.Dense
+	g    *mat.VecDense
+}
+
+func (gn *GaussNewton) init(s *state) {
+	gn.ls = gn.Linesearcher
+	if gn.ls == nil {
+		gn.ls = &optimize.Backtracking{}
+	}
+	gn.dir = mat.NewVecDense(s.n, nil)
+	gn.negR = mat.NewVecDense(s.m, nil)
+	gn.x = make([]float64, s.n)
+	gn.dx = make([]float64, s.n)
+	gn.r = make([]float64, s.m)
+	gn.jac = mat.NewDense(s.m, s.n, nil)
+	gn.g = mat.NewVecDense(s.n, nil)
+}
+
+func (gn *GaussNewton) iterate(s *state) (optimize.Status, error) {
+	gn.negR.ScaleVec(-1, mat.NewVecDense(s.m, s.r))
+	err := gn.dir.SolveVec(s.jac, gn.negR)
+	if err != nil {
+		return optimize.Failure, err
+	}
+	dir := gn.dir.RawVector().Data
+	deriv := floats.Dot(s.grad, dir)
+	if deriv >= 0 || math.IsNaN(deriv) {
+		return optimize.Failure, optimize.ErrNonDescentDirection
+	}
+
+	op := gn.ls.Init(s.cost, deriv, 1)
+	step := 1.0
+	for {
+		floats.ScaleTo(gn.dx, step, dir)
+		if s.isSmall(gn.dx) {
+			return optimize.StepConvergence, nil
+		}
+		floats.AddTo(gn.x, s.x, gn.dx)
+		// The residuals are evaluated even if the Linesearcher only needs
+		// the derivative since they are needed for computing it.
+		cost := s.residual(gn.r, gn.x)
+		var deriv float64
+		if op&optimize.GradEvaluation != 0 {
+			s.jacobianAt(gn.jac, gn.x, gn.r)
+			gn.g.MulVec(gn.jac.T(), mat.NewVecDense(s.m, gn.r))
+			deriv = mat.Dot(gn.g, gn.dir)
+		}
+		op, step, err = gn.ls.Iterate(cost, deriv)
+		if err != nil {
+			return optimize.Failure, err
+		}
+		if op == optimize.MajorIteration {
+			s.accept(gn.dx, gn.r, cost)
+			return optimize.NotTerminated, nil
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsq
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+var _ Method = (*LevenbergMarquardt)(nil)
+
+// errDamping is returned when the damping parameter of LevenbergMarquardt
+// overflows without finding a step that decreases the cost.
+var errDamping = errors.New("lsq: no decrease of the cost for any damping")
+
+// LevenbergMarquardt is the Levenberg-Marquardt method for nonlinear least
+// squares. The step dx at each iteration solves the damped normal equations
+//  (JᵀJ + λ D) dx = -Jᵀr,
+// where D is a diagonal scaling matrix. The damping parameter λ acts as the
+// inverse radius of a trust region: it is decreased when the quadratic model
+// predicts the reduction of the cost well, and increased when a step is
+// rejected. The damping is updated as in
+//  Nielsen, H. B.: Damping parameter in Marquardt's method. Technical Report
+//  IMM-REP-1999-05, Technical University of Denmark (1999)
+// and the scaling follows
+//  Moré, J. J.: The Levenberg-Marquardt algorithm: implementation and theory.
+//  Numerical Analysis, Lecture Notes in Mathematics 630 (1978), 105-116
+// in that D holds the largest diagonal of JᵀJ seen so far, which makes the
+// method invariant to the scaling of the parameters.
+type LevenbergMarquardt struct {
+	// InitDamping is the initial damping parameter relative to the largest
+	// diagonal entry of the damping matrix. If InitDamping is 0, a default
+	// value of 1e-3 is used.
+	InitDamping float64
+	// Unscaled specifies that the damping matrix D is the identity matrix
+	// instead of the scaling matrix.
+	Unscaled bool
+
+	tau    float64
+	lambda float64
+	nu     float64
+	diag   []float64
+
+	jtj  *mat.SymDense
+	a    *mat.SymDense
+	chol mat.Cholesky
+	dx   []float64
+	jdx  []float64
+	x    []float64
+	r    []float64
+}
+
+func (lm *LevenbergMarquardt) init(s *state) {
+	lm.tau = lm.InitDamping
+	if lm.tau == 0 {
+		lm.tau = 1e-3
+	}
+	if lm.tau < 0 {
+		panic("lsq: negative initial damping")
+	}
+	n := s.n
+	lm.diag = make([]float64, n)
+	lm.jtj = mat.NewSymDense(n, nil)
+	lm.a = mat.NewSymDense(n, nil)
+	lm.dx = make([]float64, n)
+	lm.jdx = make([]float64, s.m)
+	lm.x = make([]float64, n)
+	lm.r = make([]float64, s.m)
+
+	// The damping parameter is set at the first iteration from the scaling
+	// at the initial location.
+	lm.lambda = math.NaN()
+	lm.nu = 2
+}
+
+// updateScaling computes JᵀJ and updates the diagonal of the damping matrix.
+func (lm *LevenbergMarquardt) updateScaling(s *state) {
+	lm.jtj.SymOuterK(1, s.jac.T())
+	for i := range lm.diag {
+		if lm.Unscaled {
+			lm.diag[i] = 1
+			continue
+		}
+		lm.diag[i] = math.Max(lm.diag[i], lm.jtj.At(i, i))
+		if lm.diag[i] == 0 {
+			// The cost does not depend on the parameter locally.
+			lm.diag[i] = 1
+		}
+	}
+}
+
+func (lm *LevenbergMarquardt) iterate(s *state) (optimize.Status, error) {
+	lm.updateScaling(s)
+	if math.IsNaN(lm.lambda) {
+		lm.lambda = lm.tau * floats.Max(lm.diag)
+	}
+	g := mat.NewVecDense(s.n, s.grad)
+	dx := mat.NewVecDense(s.n, lm.dx)
+	jdx := mat.NewVecDense(s.m, lm.jdx)
+	for {
+		if math.IsInf(lm.lambda, 1) {
+			return optimize.Failure, errDamping
+		}
+		lm.a.CopySym(lm.jtj)
+		for i, d := range lm.diag {
+			lm.a.SetSym(i, i, lm.a.At(i, i)+lm.lambda*d)
+		}
+		if !lm.chol.Factorize(lm.a) {
+			lm.reject()
+			continue
+		}
+		lm.chol.SolveVecTo(dx, g)
+		dx.ScaleVec(-1, dx)
+		if s.isSmall(lm.dx) {
+			return optimize.StepConvergence, nil
+		}
+
+		// The reduction of the cost predicted by the linear model of
+		// the residuals.
+		jdx.MulVec(s.jac, dx)
+		pred := -mat.Dot(g, dx) - 0.5*mat.Dot(jdx, jdx)
+
+		floats.AddTo(lm.x, s.x, lm.dx)
+		cost := s.residual(lm.r, lm.x)
+		rho := (s.cost - cost) / pred
+		if pred > 0 && rho > 0 && !math.IsNaN(cost) {
+			s.accept(lm.dx, lm.r, cost)
+			lm.lambda *= math.Max(1.0/3, 1-math.Pow(2*rho-1, 3))
+			lm.nu = 2
+			return optimize.NotTerminated, nil
+		}
+		lm.reject()
+	}
+}
+
+// reject increases the damping after a rejected step.
+func (lm *LevenbergMarquardt) reject() {
+	lm.lambda *= lm.nu
+	lm.nu *= 2
+}
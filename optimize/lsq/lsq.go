@@ -0,0 +1,314 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsq
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+var (
+	// ErrMissingResidual signifies that the Problem does not provide
+	// the Residual function.
+	ErrMissingResidual = errors.New("lsq: problem does not provide Residual function")
+
+	// ErrNoResiduals signifies that the Problem has no residuals.
+	ErrNoResiduals = errors.New("lsq: non-positive number of residuals")
+)
+
+// Problem describes a nonlinear least-squares problem.
+type Problem struct {
+	// Residual evaluates the residuals at x and stores them in dst, which
+	// has length Residuals. Residual must not modify x.
+	Residual func(dst, x []float64)
+
+	// Jacobian evaluates the Jacobian of the residuals at x and stores it
+	// in dst, which is a Residuals×len(x) matrix. Jacobian must not modify x.
+	// If Jacobian is nil, it is approximated by forward differences of
+	// Residual.
+	Jacobian func(dst *mat.Dense, x []float64)
+
+	// Residuals is the number of residuals.
+	Residuals int
+}
+
+// Settings controls the termination of Minimize.
+type Settings struct {
+	// GradientThreshold stops the optimization with GradientThreshold
+	// status if the infinity norm of the gradient Jᵀr of the cost is less
+	// than or equal to this value. If GradientThreshold is 0, a default
+	// value of 1e-10 is used, and if it is negative the check is disabled.
+	GradientThreshold float64
+
+	// StepTolerance stops the optimization with StepConvergence status if
+	// the infinity norm of the step is less than or equal to StepTolerance
+	// times the infinity norm of the location plus StepTolerance. If
+	// StepTolerance is 0, a default value of 1e-10 is used, and if it is
+	// negative the check is disabled.
+	StepTolerance float64
+
+	// FunctionTolerance stops the optimization with FunctionConvergence
+	// status if the relative decrease of the cost in a major iteration is
+	// less than or equal to this value. If FunctionTolerance is 0, a default
+	// value of 1e-14 is used, and if it is negative the check is disabled.
+	FunctionTolerance float64
+
+	// MajorIterations is the maximum number of major iterations. If it is
+	// zero, this setting has no effect.
+	MajorIterations int
+
+	// FuncEvaluations is the maximum number of evaluations of Residual,
+	// including those for approximating the Jacobian. The limit is checked
+	// at major iterations. If it is zero, this setting has no effect.
+	FuncEvaluations int
+}
+
+// Result represents the answer of a least-squares optimization.
+type Result struct {
+	// X is the location of the minimum.
+	X []float64
+	// Residual holds the residuals at X.
+	Residual []float64
+	// Cost is ½ |r(X)|².
+	Cost float64
+	// Covariance is the estimate of the covariance matrix of the
+	// parameters X,
+	//  s² (JᵀJ)⁻¹,
+	// where J is the Jacobian at X and s² = |r(X)|² / (m - n) is the
+	// estimated variance of the residuals. The standard errors of the
+	// parameters are the square roots of the diagonal. Covariance is nil if
+	// there are no more residuals than parameters, or if JᵀJ is singular or
+	// too ill-conditioned to be inverted accurately.
+	Covariance *mat.SymDense
+
+	Status optimize.Status
+
+	MajorIterations     int // Number of major iterations
+	FuncEvaluations     int // Number of evaluations of Residual
+	JacobianEvaluations int // Number of evaluations of Jacobian
+}
+
+// Method is a method for nonlinear least-squares problems. Method is
+// implemented by LevenbergMarquardt and GaussNewton.
+type Method interface {
+	// init initializes the method at the initial location of s.
+	init(s *state)
+	// iterate moves s to a location with lower cost. iterate updates
+	// the location, the residuals and the cost of s, but not the Jacobian.
+	// If iterate cannot find a lower cost, it returns a status other than
+	// NotTerminated.
+	iterate(s *state) (optimize.Status, error)
+}
+
+// Minimize minimizes the sum of squares of the residuals of the Problem
+// starting at the location x using the given Method. If settings is nil, the
+// default settings are used, and if method is nil, LevenbergMarquardt is
+// used. The initial location x is not modified.
+func Minimize(p Problem, x []float64, settings *Settings, method Method) (*Result, error) {
+	if p.Residual == nil {
+		return nil, ErrMissingResidual
+	}
+	if p.Residuals <= 0 {
+		return nil, ErrNoResiduals
+	}
+	if len(x) == 0 {
+		return nil, optimize.ErrZeroDimensional
+	}
+	if settings == nil {
+		settings = &Settings{}
+	}
+	if method == nil {
+		method = &LevenbergMarquardt{}
+	}
+
+	s := newState(&p, x, settings)
+	s.cost = s.residual(s.r, s.x)
+	if math.IsInf(s.cost, 0) || math.IsNaN(s.cost) {
+		return nil, optimize.ErrFunc(s.cost)
+	}
+	s.jacobian()
+	method.init(s)
+
+	status := optimize.NotTerminated
+	var err error
+	var iter int
+	for {
+		if floats.Norm(s.grad, math.Inf(1)) <= s.gradThresh {
+			status = optimize.GradientThreshold
+			break
+		}
+		if settings.MajorIterations > 0 && iter >= settings.MajorIterations {
+			status = optimize.IterationLimit
+			break
+		}
+		if settings.FuncEvaluations > 0 && s.evals >= settings.FuncEvaluations {
+			status = optimize.FunctionEvaluationLimit
+			break
+		}
+		prev := s.cost
+		status, err = method.iterate(s)
+		if status != optimize.NotTerminated || err != nil {
+			break
+		}
+		iter++
+		s.jacobian()
+		if s.smallStep() {
+			status = optimize.StepConvergence
+			break
+		}
+		if prev-s.cost <= s.funcTol*prev {
+			status = optimize.FunctionConvergence
+			break
+		}
+	}
+	if status == optimize.NotTerminated {
+		status = optimize.Failure
+	}
+
+	return &Result{
+		X:                   s.x,
+		Residual:            s.r,
+		Cost:                s.cost,
+		Covariance:          covariance(s.jac, s.cost),
+		Status:              status,
+		MajorIterations:     iter,
+		FuncEvaluations:     s.evals,
+		JacobianEvaluations: s.jacEvals,
+	}, err
+}
+
+// state is the state of a least-squares optimization shared by Minimize and
+// the Method.
+type state struct {
+	p    *Problem
+	m, n int
+
+	x    []float64
+	r    []float64
+	cost float64
+	jac  *mat.Dense
+	grad []float64 // Jᵀr
+	step []float64 // Most recent accepted step
+
+	gradThresh float64
+	stepTol    float64
+	funcTol    float64
+
+	evals    int
+	jacEvals int
+}
+
+func newState(p *Problem, x []float64, settings *Settings) *state {
+	m, n := p.Residuals, len(x)
+	s := &state{
+		p:    p,
+		m:    m,
+		n:    n,
+		x:    make([]float64, n),
+		r:    make([]float64, m),
+		jac:  mat.NewDense(m, n, nil),
+		grad: make([]float64, n),
+		step: make([]float64, n),
+
+		gradThresh: defaultTol(settings.GradientThreshold, 1e-10),
+		stepTol:    defaultTol(settings.StepTolerance, 1e-10),
+		funcTol:    defaultTol(settings.FunctionTolerance, 1e-14),
+	}
+	copy(s.x, x)
+	return s
+}
+
+// defaultTol returns the tolerance tol, def if tol is 0, and -Inf if tol is
+// negative so that the corresponding check is disabled.
+func defaultTol(tol, def float64) float64 {
+	switch {
+	case tol == 0:
+		return def
+	case tol < 0:
+		return math.Inf(-1)
+	}
+	return tol
+}
+
+// residual evaluates the residuals at x, stores them in dst and returns the
+// cost ½ |r(x)|².
+func (s *state) residual(dst, x []float64) float64 {
+	s.evals++
+	s.p.Residual(dst, x)
+	norm := floats.Norm(dst, 2)
+	return 0.5 * norm * norm
+}
+
+// jacobian evaluates the Jacobian and the gradient of the cost at the current
+// location.
+func (s *state) jacobian() {
+	s.jacobianAt(s.jac, s.x, s.r)
+	grad := mat.NewVecDense(s.n, s.grad)
+	grad.MulVec(s.jac.T(), mat.NewVecDense(s.m, s.r))
+}
+
+// jacobianAt evaluates the Jacobian at x, where the residuals are r, and stores
+// it in dst.
+func (s *state) jacobianAt(dst *mat.Dense, x, r []float64) {
+	if s.p.Jacobian != nil {
+		s.jacEvals++
+		s.p.Jacobian(dst, x)
+		return
+	}
+	fd.Jacobian(dst, func(y, x []float64) {
+		s.evals++
+		s.p.Residual(y, x)
+	}, x, &fd.JacobianSettings{
+		OriginValue: r,
+	})
+}
+
+// isSmall returns whether the step dx from the current location is small
+// according to the step tolerance.
+func (s *state) isSmall(dx []float64) bool {
+	return floats.Norm(dx, math.Inf(1)) <= s.stepTol*(floats.Norm(s.x, math.Inf(1))+s.stepTol)
+}
+
+// smallStep returns whether the most recent accepted step is small.
+func (s *state) smallStep() bool {
+	return s.isSmall(s.step)
+}
+
+// accept moves the location by dx and sets the residuals and the cost at the
+// new location.
+func (s *state) accept(dx, r []float64, cost float64) {
+	floats.Add(s.x, dx)
+	copy(s.step, dx)
+	copy(s.r, r)
+	s.cost = cost
+}
+
+// covariance returns the estimate of the covariance matrix of the parameters
+// from the Jacobian and the cost at the minimum, or nil if it cannot be
+// estimated.
+func covariance(jac *mat.Dense, cost float64) *mat.SymDense {
+	m, n := jac.Dims()
+	if m <= n {
+		return nil
+	}
+	var jtj mat.SymDense
+	jtj.SymOuterK(1, jac.T())
+	var chol mat.Cholesky
+	if !chol.Factorize(&jtj) {
+		return nil
+	}
+	var cov mat.SymDense
+	err := chol.InverseTo(&cov)
+	if err != nil {
+		return nil
+	}
+	cov.ScaleSym(2*cost/float64(m-n), &cov)
+	return &cov
+}
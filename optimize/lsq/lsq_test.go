@@ -0,0 +1,243 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsq
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+// rosenbrock is the Rosenbrock function written as a least-squares problem.
+var rosenbrock = Problem{
+	Residual: func(dst, x []float64) {
+		dst[0] = 10 * (x[1] - x[0]*x[0])
+		dst[1] = 1 - x[0]
+	},
+	Jacobian: func(dst *mat.Dense, x []float64) {
+		dst.Set(0, 0, -20*x[0])
+		dst.Set(0, 1, 10)
+		dst.Set(1, 0, -1)
+		dst.Set(1, 1, 0)
+	},
+	Residuals: 2,
+}
+
+// expDecay returns the problem of fitting the model a exp(-b t) + c to noisy
+// observations of the model with parameters a=5, b=0.3 and c=1.
+func expDecay() Problem {
+	rnd := rand.New(rand.NewSource(1))
+	t := make([]float64, 50)
+	y := make([]float64, len(t))
+	for i := range t {
+		t[i] = float64(i) / 5
+		y[i] = 5*math.Exp(-0.3*t[i]) + 1 + 0.01*rnd.NormFloat64()
+	}
+	return Problem{
+		Residual: func(dst, x []float64) {
+			for i, ti := range t {
+				dst[i] = x[0]*math.Exp(-x[1]*ti) + x[2] - y[i]
+			}
+		},
+		Jacobian: func(dst *mat.Dense, x []float64) {
+			for i, ti := range t {
+				e := math.Exp(-x[1] * ti)
+				dst.Set(i, 0, e)
+				dst.Set(i, 1, -x[0]*ti*e)
+				dst.Set(i, 2, 1)
+			}
+		},
+		Residuals: len(t),
+	}
+}
+
+func TestMinimize(t *testing.T) {
+	t.Parallel()
+	decay := expDecay()
+	decayFD := decay
+	decayFD.Jacobian = nil
+	for _, test := range []struct {
+		name   string
+		p      Problem
+		method Method
+		x      []float64
+		want   []float64
+		tol    float64
+	}{
+		{"rosenbrock", rosenbrock, &LevenbergMarquardt{}, []float64{-1.2, 1}, []float64{1, 1}, 1e-10},
+		{"rosenbrock unscaled", rosenbrock, &LevenbergMarquardt{Unscaled: true}, []float64{-1.2, 1}, []float64{1, 1}, 1e-10},
+		{"rosenbrock default", rosenbrock, nil, []float64{-1.2, 1}, []float64{1, 1}, 1e-10},
+		{"decay", decay, &LevenbergMarquardt{}, []float64{1, 1, 0}, []float64{5, 0.3, 1}, 1e-2},
+		{"decay fd", decayFD, &LevenbergMarquardt{}, []float64{1, 1, 0}, []float64{5, 0.3, 1}, 1e-2},
+		{"rosenbrock gauss-newton", rosenbrock, &GaussNewton{}, []float64{-1.2, 1}, []float64{1, 1}, 1e-10},
+		{"decay gauss-newton", decay, &GaussNewton{}, []float64{4, 0.5, 0}, []float64{5, 0.3, 1}, 1e-2},
+		{"decay gauss-newton fd", decayFD, &GaussNewton{}, []float64{4, 0.5, 0}, []float64{5, 0.3, 1}, 1e-2},
+		{"decay gauss-newton morethuente", decay, &GaussNewton{Linesearcher: &optimize.MoreThuente{}}, []float64{4, 0.5, 0}, []float64{5, 0.3, 1}, 1e-2},
+		{"decay gauss-newton bisection", decay, &GaussNewton{Linesearcher: &optimize.Bisection{}}, []float64{4, 0.5, 0}, []float64{5, 0.3, 1}, 1e-2},
+	} {
+		x := make([]float64, len(test.x))
+		copy(x, test.x)
+		res, err := Minimize(test.p, x, nil, test.method)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !floats.Equal(x, test.x) {
+			t.Errorf("%s: initial location modified", test.name)
+		}
+		if res.Status.Early() {
+			t.Errorf("%s: unexpected status: %v", test.name, res.Status)
+		}
+		if !floats.EqualApprox(res.X, test.want, test.tol) {
+			t.Errorf("%s: unexpected minimum: got %v, want %v", test.name, res.X, test.want)
+		}
+		r := make([]float64, test.p.Residuals)
+		test.p.Residual(r, res.X)
+		if !floats.Equal(r, res.Residual) {
+			t.Errorf("%s: mismatched residuals", test.name)
+		}
+		if test.p.Jacobian == nil && res.JacobianEvaluations != 0 {
+			t.Errorf("%s: unexpected Jacobian evaluations: %d", test.name, res.JacobianEvaluations)
+		}
+	}
+}
+
+func TestCovariance(t *testing.T) {
+	t.Parallel()
+	// For a linear model the minimum and the covariance are known in
+	// closed form.
+	rnd := rand.New(rand.NewSource(1))
+	const m, n = 20, 3
+	a := mat.NewDense(m, n, nil)
+	b := mat.NewVecDense(m, nil)
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			a.Set(i, j, rnd.NormFloat64())
+		}
+		b.SetVec(i, rnd.NormFloat64())
+	}
+	p := Problem{
+		Residual: func(dst, x []float64) {
+			r := mat.NewVecDense(m, dst)
+			r.MulVec(a, mat.NewVecDense(n, x))
+			r.SubVec(r, b)
+		},
+		Jacobian: func(dst *mat.Dense, _ []float64) {
+			dst.Copy(a)
+		},
+		Residuals: m,
+	}
+	res, err := Minimize(p, make([]float64, n), nil, &LevenbergMarquardt{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var want mat.VecDense
+	err = want.SolveVec(a, b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !floats.EqualApprox(res.X, want.RawVector().Data, 1e-8) {
+		t.Errorf("unexpected minimum: got %v, want %v", res.X, want.RawVector().Data)
+	}
+
+	var ata mat.SymDense
+	ata.SymOuterK(1, a.T())
+	var chol mat.Cholesky
+	chol.Factorize(&ata)
+	var wantCov mat.SymDense
+	chol.InverseTo(&wantCov)
+	s2 := floats.Dot(res.Residual, res.Residual) / (m - n)
+	wantCov.ScaleSym(s2, &wantCov)
+	if res.Covariance == nil {
+		t.Fatal("missing covariance")
+	}
+	if !mat.EqualApprox(res.Covariance, &wantCov, 1e-8) {
+		t.Errorf("unexpected covariance:\ngot  %v\nwant %v", mat.Formatted(res.Covariance), mat.Formatted(&wantCov))
+	}
+
+	// The covariance cannot be estimated without redundant residuals.
+	res, err = Minimize(rosenbrock, []float64{-1.2, 1}, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Covariance != nil {
+		t.Errorf("unexpected covariance for square problem")
+	}
+}
+
+func TestMinimizeErrors(t *testing.T) {
+	t.Parallel()
+	_, err := Minimize(Problem{Residuals: 1}, []float64{0}, nil, nil)
+	if err != ErrMissingResidual {
+		t.Errorf("unexpected error for missing residual: got %v, want %v", err, ErrMissingResidual)
+	}
+	_, err = Minimize(Problem{Residual: rosenbrock.Residual}, []float64{0, 0}, nil, nil)
+	if err != ErrNoResiduals {
+		t.Errorf("unexpected error for no residuals: got %v, want %v", err, ErrNoResiduals)
+	}
+	_, err = Minimize(rosenbrock, nil, nil, nil)
+	if err != optimize.ErrZeroDimensional {
+		t.Errorf("unexpected error for empty location: got %v, want %v", err, optimize.ErrZeroDimensional)
+	}
+	nan := Problem{
+		Residual:  func(dst, _ []float64) { dst[0] = math.NaN() },
+		Residuals: 1,
+	}
+	_, err = Minimize(nan, []float64{0}, nil, nil)
+	if _, ok := err.(optimize.ErrFunc); !ok {
+		t.Errorf("unexpected error for NaN residual: got %v, want optimize.ErrFunc", err)
+	}
+
+	res, err := Minimize(rosenbrock, []float64{-1.2, 1}, &Settings{MajorIterations: 3}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != optimize.IterationLimit || res.MajorIterations != 3 {
+		t.Errorf("unexpected termination: got %v after %d iterations, want %v after 3", res.Status, res.MajorIterations, optimize.IterationLimit)
+	}
+}
+
+func TestGaussNewtonSingular(t *testing.T) {
+	t.Parallel()
+	// The residuals only depend on x[0] + x[1], so the Jacobian is singular
+	// and the Gauss-Newton direction is not defined.
+	p := Problem{
+		Residual: func(dst, x []float64) {
+			dst[0] = x[0] + x[1] - 1
+			dst[1] = 2*(x[0]+x[1]) - 3
+			dst[2] = math.Sin(x[0] + x[1])
+		},
+		Jacobian: func(dst *mat.Dense, x []float64) {
+			c := math.Cos(x[0] + x[1])
+			for j := 0; j < 2; j++ {
+				dst.Set(0, j, 1)
+				dst.Set(1, j, 2)
+				dst.Set(2, j, c)
+			}
+		},
+		Residuals: 3,
+	}
+	res, err := Minimize(p, []float64{0, 0}, nil, &GaussNewton{})
+	if err == nil {
+		t.Errorf("no error for singular Jacobian: got status %v at %v", res.Status, res.X)
+	}
+	if res != nil && res.Status != optimize.Failure {
+		t.Errorf("unexpected status: got %v, want %v", res.Status, optimize.Failure)
+	}
+
+	res, err = Minimize(p, []float64{0, 0}, nil, &LevenbergMarquardt{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status.Early() {
+		t.Errorf("unexpected status: %v", res.Status)
+	}
+}
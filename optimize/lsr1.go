@@ -0,0 +1,336 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+var (
+	_ Method      = (*LSR1)(nil)
+	_ localMethod = (*LSR1)(nil)
+)
+
+// LSR1 implements a limited-memory symmetric rank-one (SR1) quasi-Newton
+// method with a trust region for gradient-based unconstrained minimization.
+//
+// LSR1 approximates the Hessian by the SR1 updates of a scaled identity
+// matrix with the steps and gradient changes of the last Store iterations.
+// Unlike the BFGS approximation of LBFGS, the SR1 approximation is not
+// necessarily positive definite, so it can represent indefinite curvature,
+// and the approximation is used in a trust region method as described for
+// TrustRegion. The quadratic model is approximately minimized within the
+// trust region by the truncated conjugate gradient method of Steihaug, which
+// only needs products of the approximate Hessian with vectors and follows
+// directions of negative curvature to the boundary of the region. See
+// chapters 4.1, 6.2 and 7.2 of
+//  Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer (2006)
+// for more information.
+//
+// The cost of LSR1 per iteration is O(Store^2 * dim) for the update of the
+// approximation and O(Store * dim) for each conjugate gradient iteration.
+type LSR1 struct {
+	// Store is the size of the limited-memory storage.
+	// If Store is 0, it will be defaulted to 15.
+	Store int
+	// InitRadius is the initial radius of the trust region. If InitRadius
+	// is 0, a default value of 1 is used.
+	InitRadius float64
+	// MaxRadius is the maximum radius of the trust region. If MaxRadius is
+	// 0, the radius is not limited.
+	MaxRadius float64
+	// Eta is the minimum ratio of the actual to the predicted reduction of
+	// the objective function for a step to be accepted. Eta must be in
+	// [0, 0.25), and if Eta is 0, a default value of 1e-4 is used.
+	Eta float64
+	// GradStopThreshold sets the threshold for stopping if the gradient norm
+	// gets too small. If GradStopThreshold is 0 it is defaulted to 1e-12, and
+	// if it is NaN the setting is not used.
+	GradStopThreshold float64
+
+	status Status
+	err    error
+
+	state  trustRegionState
+	radius float64
+	maxRad float64
+	eta    float64
+
+	dim  int
+	x    []float64 // Location of the most recent major iteration
+	f    float64
+	grad []float64
+	pred float64 // Reduction predicted by the model for the trial step
+
+	// History
+	oldest int         // Index of the oldest element of the history
+	count  int         // Number of valid elements of the history
+	s      [][]float64 // Last Store steps
+	y      [][]float64 // Last Store changes of the gradient
+	gamma  float64     // Scaling of the initial Hessian approximation
+
+	// SR1 updates of the approximation computed from the history in
+	// chronological order. B = gamma*I + Σ_i w[i] * u[i] * u[i]ᵀ.
+	u [][]float64
+	w []float64
+	n int // Number of the SR1 updates
+
+	// Workspace of the conjugate gradient iterations
+	p, r, d, bd, tmp []float64
+}
+
+func (l *LSR1) Status() (Status, error) {
+	return l.status, l.err
+}
+
+func (*LSR1) Uses(has Available) (uses Available, err error) {
+	return has.gradient()
+}
+
+func (l *LSR1) Init(dim, tasks int) int {
+	l.status = NotTerminated
+	l.err = nil
+	return 1
+}
+
+func (l *LSR1) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	l.status, l.err = localOptimizer{}.run(l, l.GradStopThreshold, operation, result, tasks)
+	close(operation)
+}
+
+func (l *LSR1) initLocal(loc *Location) (Operation, error) {
+	if l.Store == 0 {
+		l.Store = 15
+	}
+	if l.Store < 0 {
+		panic("lsr1: negative Store")
+	}
+	l.radius = l.InitRadius
+	if l.radius == 0 {
+		l.radius = 1
+	}
+	if l.radius < 0 {
+		panic("lsr1: negative initial radius")
+	}
+	l.maxRad = l.MaxRadius
+	if l.maxRad == 0 {
+		l.maxRad = math.Inf(1)
+	}
+	if l.maxRad < l.radius {
+		panic("lsr1: maximum radius less than initial radius")
+	}
+	l.eta = l.Eta
+	if l.eta == 0 {
+		l.eta = 1e-4
+	}
+	if l.eta < 0 || l.eta >= 0.25 {
+		panic("lsr1: Eta not in [0, 0.25)")
+	}
+
+	dim := len(loc.X)
+	l.dim = dim
+	l.x = resize(l.x, dim)
+	l.grad = resize(l.grad, dim)
+	l.p = resize(l.p, dim)
+	l.r = resize(l.r, dim)
+	l.d = resize(l.d, dim)
+	l.bd = resize(l.bd, dim)
+	l.tmp = resize(l.tmp, dim)
+	l.s = l.initHistory(l.s)
+	l.y = l.initHistory(l.y)
+	l.u = l.initHistory(l.u)
+	l.w = resize(l.w, l.Store)
+	l.oldest = 0
+	l.count = 0
+	l.n = 0
+	l.gamma = 1
+
+	l.accept(loc)
+	return l.trial(loc)
+}
+
+func (l *LSR1) initHistory(hist [][]float64) [][]float64 {
+	c := cap(hist)
+	if c < l.Store {
+		n := make([][]float64, l.Store-c)
+		hist = append(hist[:c], n...)
+	}
+	hist = hist[:l.Store]
+	for i := range hist {
+		hist[i] = resize(hist[i], l.dim)
+	}
+	return hist
+}
+
+func (l *LSR1) iterateLocal(loc *Location) (Operation, error) {
+	switch l.state {
+	default:
+		panic("lsr1: unknown state")
+	case trustRegionTrial:
+		if finiteValues(loc) {
+			// The gradient at a trial location is informative about the
+			// curvature even if the step is rejected.
+			l.update(loc)
+		}
+		// The reductions are shifted by a multiple of the rounding error
+		// of f as in TrustRegion.
+		delta := 10 * dlamchE * math.Max(1, math.Abs(l.f))
+		rho := (l.f - loc.F + delta) / (l.pred + delta)
+		norm := floats.Norm(l.p, 2)
+		if rho < 0.25 || math.IsNaN(rho) {
+			l.radius = 0.25 * math.Min(norm, l.radius)
+		} else if rho > 0.75 && norm > 0.99*l.radius {
+			l.radius = math.Min(2*l.radius, l.maxRad)
+		}
+		if rho > l.eta {
+			l.accept(loc)
+			l.state = trustRegionMajor
+			return MajorIteration, nil
+		}
+		if l.radius <= 1e-16*(1+floats.Norm(l.x, 2)) {
+			// Restore the location of the last major iteration.
+			copy(loc.X, l.x)
+			loc.F = l.f
+			copy(loc.Gradient, l.grad)
+			return NoOperation, ErrNoProgress
+		}
+		return l.trial(loc)
+	case trustRegionMajor:
+		return l.trial(loc)
+	}
+}
+
+// accept stores the location of a major iteration.
+func (l *LSR1) accept(loc *Location) {
+	copy(l.x, loc.X)
+	l.f = loc.F
+	copy(l.grad, loc.Gradient)
+}
+
+// update adds the trial step l.p and the change of the gradient at the trial
+// location loc.X to the history if the SR1 update with them is well defined,
+// and recomputes the SR1 updates of the approximation.
+func (l *LSR1) update(loc *Location) {
+	y := l.tmp
+	floats.SubTo(y, loc.Gradient, l.grad)
+	s := l.p
+	if !l.sr1Update(l.r, s, y) {
+		return
+	}
+	copy(l.s[l.oldest], s)
+	copy(l.y[l.oldest], y)
+	l.oldest = (l.oldest + 1) % l.Store
+	if l.count < l.Store {
+		l.count++
+	}
+	// Scale the initial Hessian approximation to the curvature along the
+	// most recent step if it is positive.
+	if ys := floats.Dot(y, s); ys > 0 {
+		l.gamma = floats.Dot(y, y) / ys
+	}
+
+	l.n = 0
+	for i := 0; i < l.count; i++ {
+		idx := l.oldest - l.count + i
+		if idx < 0 {
+			idx += l.Store
+		}
+		if l.sr1Update(l.u[l.n], l.s[idx], l.y[idx]) {
+			l.w[l.n] = 1 / floats.Dot(l.u[l.n], l.s[idx])
+			l.n++
+		}
+	}
+}
+
+// sr1Update stores in u the vector y - B*s of the SR1 update of the current
+// approximation B with the step s and the change of the gradient y, and
+// returns whether the update is well defined. The update is skipped if the
+// denominator uᵀs is too small, which includes the case that B already
+// satisfies the secant condition.
+func (l *LSR1) sr1Update(u, s, y []float64) bool {
+	l.mulB(u, s)
+	floats.SubTo(u, y, u)
+	us := floats.Dot(u, s)
+	return math.Abs(us) > 1e-8*floats.Norm(s, 2)*floats.Norm(u, 2)
+}
+
+// mulB stores in dst the product of the Hessian approximation with v.
+func (l *LSR1) mulB(dst, v []float64) {
+	floats.ScaleTo(dst, l.gamma, v)
+	for i := 0; i < l.n; i++ {
+		floats.AddScaled(dst, l.w[i]*floats.Dot(l.u[i], v), l.u[i])
+	}
+}
+
+// trial computes the step within the trust region, sets the trial location in
+// loc, and returns the evaluation of the function and the gradient.
+func (l *LSR1) trial(loc *Location) (Operation, error) {
+	l.steihaug()
+	floats.AddTo(loc.X, l.x, l.p)
+	l.state = trustRegionTrial
+	return FuncEvaluation | GradEvaluation, nil
+}
+
+// steihaug stores in l.p the approximate minimizer of the quadratic model
+// within the trust region computed by the truncated conjugate gradient
+// method, and in l.pred its predicted reduction.
+func (l *LSR1) steihaug() {
+	p, r, d, bd := l.p, l.r, l.d, l.bd
+	for i := range p {
+		p[i] = 0
+	}
+	copy(r, l.grad)
+	floats.ScaleTo(d, -1, l.grad)
+	gnorm := floats.Norm(l.grad, 2)
+	tol := math.Min(0.5, math.Sqrt(gnorm)) * gnorm
+	rr := gnorm * gnorm
+	for k := 0; k < 2*l.dim; k++ {
+		l.mulB(bd, d)
+		dBd := floats.Dot(d, bd)
+		if dBd <= 0 {
+			// Follow the direction of negative curvature to the boundary.
+			floats.AddScaled(p, boundaryStep(p, d, l.radius), d)
+			break
+		}
+		alpha := rr / dBd
+		floats.AddScaledTo(l.tmp, p, alpha, d)
+		if floats.Norm(l.tmp, 2) >= l.radius {
+			floats.AddScaled(p, boundaryStep(p, d, l.radius), d)
+			break
+		}
+		copy(p, l.tmp)
+		floats.AddScaled(r, alpha, bd)
+		rrNew := floats.Dot(r, r)
+		if math.Sqrt(rrNew) < tol {
+			break
+		}
+		floats.Scale(rrNew/rr, d)
+		floats.Sub(d, r)
+		rr = rrNew
+	}
+	l.mulB(bd, p)
+	l.pred = -floats.Dot(l.grad, p) - 0.5*floats.Dot(p, bd)
+}
+
+// boundaryStep returns the non-negative step t such that |p + t*d| equals the
+// radius, where |p| is at most the radius.
+func boundaryStep(p, d []float64, radius float64) float64 {
+	a := floats.Dot(d, d)
+	b := floats.Dot(p, d)
+	c := floats.Dot(p, p) - radius*radius
+	return (-b + math.Sqrt(b*b-a*c)) / a
+}
+
+func (*LSR1) needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return struct {
+		Gradient bool
+		Hessian  bool
+	}{true, false}
+}
@@ -5,10 +5,12 @@
 package optimize
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"time"
 
+	"gonum.org/v1/gonum/diff/fd"
 	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/mat"
 )
@@ -31,6 +33,8 @@ type Task struct {
 	ID int
 	Op Operation
 	*Location
+
+	err error // Error of a failed evaluation.
 }
 
 // Location represents a location in the optimization procedure.
@@ -52,6 +56,11 @@ type Location struct {
 	// a new mat.SymDense will be allocated, if it is empty
 	// it will be resized to match the length of X.
 	Hessian *mat.SymDense
+	// SparseGradient holds the gradient at X in sparse form
+	// when it is evaluated by Problem.SparseGrad for a Method
+	// that uses sparse gradients directly. Gradient is nil in
+	// that case.
+	SparseGradient *SparseGradient
 }
 
 // Method is a type which can search for an optimum of an objective function.
@@ -134,6 +143,10 @@ type Method interface {
 // The second argument specifies the initial location for the optimization.
 // Some Methods do not require an initial location, but initX must still be
 // specified for the dimension of the optimization problem.
+// If p.Bounds is not nil, initX must lie within the bounds, otherwise Minimize
+// returns ErrOutOfBounds. If p.Linear is not nil, initX must satisfy the linear
+// constraints, otherwise Minimize returns ErrInfeasible. The initial location
+// does not need to satisfy the nonlinear constraints p.Nonlinear.
 //
 // The third argument contains the settings for the minimization. If settings
 // is nil, the zero value will be used, see the documentation of the Settings
@@ -146,10 +159,13 @@ type Method interface {
 // an appropriate default is chosen based on the properties of the other arguments
 // (dimension, gradient-free or gradient-based, etc.). If method is not nil,
 // Minimize panics if the Problem is not consistent with the Method (Uses
-// returns an error).
+// returns an error), except that the gradient of a Problem without Grad is
+// approximated by finite differences if the Method needs it, see the
+// documentation of Settings.FDGradient.
 //
 // Minimize returns a Result struct and any error that occurred. See the
-// documentation of Result for more information.
+// documentation of Result for more information. If settings is not valid as
+// reported by Settings.Validate, Minimize returns a nil Result and the error.
 //
 // See the documentation for Method for the details on implementing a method.
 //
@@ -158,19 +174,105 @@ type Method interface {
 // function evaluations. The Settings input struct can be used to limit this,
 // for example by modifying the maximum function evaluations or gradient tolerance.
 func Minimize(p Problem, initX []float64, settings *Settings, method Method) (*Result, error) {
+	return minimizeFrom(context.Background(), p, initX, settings, method, nil)
+}
+
+// MinimizeContext performs Minimize and stops the optimization when ctx is
+// done. The context is checked after every evaluation and major iteration,
+// so evaluations that are in progress when ctx is done are completed. If the
+// optimization is stopped by ctx, the Result holds the location of the last
+// major iteration, its Status is Cancelled, and the returned error is
+// ctx.Err(). If ctx is already done, MinimizeContext returns a nil Result and
+// ctx.Err() without evaluating the function.
+//
+// See Minimize for the description of the other arguments.
+func MinimizeContext(ctx context.Context, p Problem, initX []float64, settings *Settings, method Method) (*Result, error) {
+	return minimizeFrom(ctx, p, initX, settings, method, nil)
+}
+
+// minimizeFrom performs MinimizeContext continuing from the statistics in
+// prev. If prev is nil, the optimization starts afresh.
+func minimizeFrom(ctx context.Context, p Problem, initX []float64, settings *Settings, method Method, prev *Stats) (*Result, error) {
 	startTime := time.Now()
+	if settings == nil {
+		settings = &Settings{}
+	}
+	err := settings.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// Sparse gradients are passed on to a Method that uses them directly
+	// unless the variables are scaled, and are otherwise expanded to the
+	// dense form.
+	sparse := p.Grad == nil && p.SparseGrad != nil && usesSparseGrad(method) &&
+		settings.Scale == nil && settings.Preconditioner == nil
+	if p.Grad == nil && p.SparseGrad != nil && !sparse {
+		p.Grad = denseGrad(p.SparseGrad)
+	}
+	scaling, err := newVariableScaling(settings, &p, len(initX))
+	if err != nil {
+		return nil, err
+	}
+	if scaling != nil {
+		// Minimize with respect to the scaled variables, and record the
+		// locations with respect to the variables of the Problem.
+		p = scaling.problem(p)
+		z := make([]float64, len(initX))
+		scaling.toMethod(z, initX)
+		initX = z
+		s := *settings
+		s.InitValues = scaling.initValues(settings.InitValues)
+		if settings.Recorder != nil {
+			s.Recorder = &scaledRecorder{Recorder: settings.Recorder, scaling: scaling}
+		}
+		settings = &s
+	}
+	var fdGrad *fdGradient
+	if settings.FDGradient != nil && p.Grad == nil && !sparse && p.Func != nil {
+		fdGrad = newFDGradient(p.Func, settings.FDGradient, settings.Concurrent)
+		p.Grad = fdGrad.grad
+	}
 	if method == nil {
 		method = getDefaultMethod(&p)
 	}
-	if settings == nil {
-		settings = &Settings{}
+	if p.Grad == nil && p.Func != nil && needsGradient(p, method) {
+		// The Method needs the gradient, so approximate it with the default
+		// finite difference settings.
+		fdGrad = newFDGradient(p.Func, &fd.Settings{}, settings.Concurrent)
+		p.Grad = fdGrad.grad
+	}
+	if settings.MethodState != nil {
+		warm, ok := method.(WarmStarter)
+		if !ok {
+			return nil, ErrNotWarmStarter
+		}
+		err = warm.SetWarmState(settings.MethodState)
+		if err != nil {
+			return nil, err
+		}
 	}
 	stats := &Stats{}
+	if prev != nil {
+		*stats = *prev
+		startTime = startTime.Add(-prev.Runtime)
+	}
 	dim := len(initX)
-	err := checkOptimization(p, dim, settings.Recorder)
+	err = checkOptimization(p, dim, settings.Recorder)
+	if err != nil {
+		return nil, err
+	}
+	err = checkBounds(p.Bounds, initX)
 	if err != nil {
 		return nil, err
 	}
+	err = checkLinear(p.Linear, initX)
+	if err != nil {
+		return nil, err
+	}
+	checkNonlinear(p.Nonlinear)
 
 	optLoc := newLocation(dim) // This must have an allocated X field.
 	optLoc.F = math.Inf(1)
@@ -178,6 +280,20 @@ func Minimize(p Problem, initX []float64, settings *Settings, method Method) (*R
 	initOp, initLoc := getInitLocation(dim, initX, settings.InitValues)
 
 	converger := settings.Converger
+	if _, ok := method.(stochasticMethod); ok {
+		// The function values and gradients are noisy, so the optimization
+		// is stopped only by the limits.
+		if !hasLimit(p, settings) {
+			return nil, ErrSetting{Field: "MajorIterations", Reason: "stochastic method requires an iteration, evaluation or runtime limit"}
+		}
+		if converger == nil {
+			converger = NeverTerminate{}
+		}
+		s := *settings
+		s.GradientThreshold = 0
+		s.RelGradientThreshold = 0
+		settings = &s
+	}
 	if converger == nil {
 		converger = defaultFunctionConverge()
 	}
@@ -193,42 +309,180 @@ func Minimize(p Problem, initX []float64, settings *Settings, method Method) (*R
 		}
 	}
 
+	init := initialValues{F: math.NaN(), GradNorm: math.NaN()}
+	if initOp&FuncEvaluation != 0 {
+		init.F = initLoc.F
+	}
+	if initOp&GradEvaluation != 0 {
+		init.GradNorm = projGradNorm(initLoc.X, initLoc.Gradient, p.Bounds)
+	}
+
+	var traj *trajectory
+	if settings.History {
+		traj = &trajectory{scaling: scaling}
+	}
+
 	// Run optimization
 	var status Status
-	status, err = minimize(&p, method, settings, converger, stats, initOp, initLoc, optLoc, startTime)
+	status, err = minimize(ctx, &p, fdGrad, method, settings, converger, stats, initOp, initLoc, optLoc, &init, traj, startTime)
 
 	// Cleanup and collect results
 	if settings.Recorder != nil && err == nil {
 		err = settings.Recorder.Record(optLoc, PostIteration, stats)
 	}
 	stats.Runtime = time.Since(startTime)
+	var mult *Multipliers
+	var comp float64
+	if reporter, ok := method.(MultiplierReporter); ok {
+		mult = reporter.Multipliers()
+	}
+	if mult != nil && (p.Bounds != nil || p.Linear != nil || p.Nonlinear != nil) {
+		cons := constraintSet{
+			bounds:    p.Bounds,
+			linear:    p.Linear,
+			nonlinear: p.Nonlinear,
+		}
+		cons.init(len(optLoc.X))
+		comp = cons.complementarity(optLoc.X, mult)
+	}
+	var invHess *mat.SymDense
+	if reporter, ok := method.(InverseHessianReporter); ok && settings.InverseHessian {
+		invHess = reporter.InverseHessian(optLoc)
+	}
+	if scaling != nil {
+		var loc Location
+		scaling.locationToUser(&loc, optLoc)
+		optLoc = &loc
+		scaling.multipliersToUser(mult)
+		if invHess != nil {
+			scaling.invHessToUser(invHess, invHess)
+		}
+	}
+	var hist *History
+	if traj != nil {
+		hist = &traj.history
+	}
+	var state []byte
+	if warm, ok := method.(WarmStarter); ok && settings.ReturnMethodState {
+		var stateErr error
+		state, stateErr = warm.WarmState()
+		if err == nil {
+			err = stateErr
+		}
+	}
 	return &Result{
-		Location: *optLoc,
-		Stats:    *stats,
-		Status:   status,
+		Location:        *optLoc,
+		Stats:           *stats,
+		Status:          status,
+		InitialValue:    init.F,
+		Multipliers:     mult,
+		Complementarity: comp,
+		InverseHessian:  invHess,
+		History:         hist,
+		MethodState:     state,
 	}, err
 }
 
 func getDefaultMethod(p *Problem) Method {
+	if p.Nonlinear != nil && p.Grad != nil {
+		return &SQP{}
+	}
+	if p.Linear != nil && p.Grad != nil {
+		return &ActiveSet{}
+	}
+	if p.Bounds != nil && p.Grad != nil {
+		return &LBFGSB{}
+	}
 	if p.Grad != nil {
 		return &LBFGS{}
 	}
 	return &NelderMead{}
 }
 
+// needsGradient returns whether method cannot be used for p because p does not
+// provide the gradient.
+func needsGradient(p Problem, method Method) bool {
+	_, err := method.Uses(availFromProblem(p))
+	return err == ErrMissingGrad
+}
+
+// initialValues holds the values of the objective function and of the
+// infinity norm of its gradient at the initial location. A value is NaN
+// if it has not been evaluated.
+type initialValues struct {
+	F        float64
+	GradNorm float64
+}
+
 // minimize performs an optimization. minimize updates the settings and optLoc,
-// and returns the final Status and error.
-func minimize(prob *Problem, method Method, settings *Settings, converger Converger, stats *Stats, initOp Operation, initLoc, optLoc *Location, startTime time.Time) (Status, error) {
+// and returns the final Status and error. The NaN fields of init are set
+// from the first corresponding evaluation at the initial location. If fdGrad
+// is not nil, the evaluations of the objective function it performs are added
+// to the statistics. If traj is not nil, the major iterations are added to
+// it. The optimization is stopped with Cancelled status when ctx is done.
+func minimize(ctx context.Context, prob *Problem, fdGrad *fdGradient, method Method, settings *Settings, converger Converger, stats *Stats, initOp Operation, initLoc, optLoc *Location, init *initialValues, traj *trajectory, startTime time.Time) (Status, error) {
 	dim := len(optLoc.X)
+	// Keep a copy of the initial location since the Method may modify initLoc.
+	initX := make([]float64, dim)
+	copy(initX, initLoc.X)
 	nTasks := settings.Concurrent
 	if nTasks == 0 {
 		nTasks = 1
 	}
 	has := availFromProblem(*prob)
-	_, initErr := method.Uses(has)
+	uses, initErr := method.Uses(has)
 	if initErr != nil {
 		panic(fmt.Sprintf("optimize: specified method inconsistent with Problem: %v", initErr))
 	}
+	if has.Bounds && !uses.Bounds {
+		panic("optimize: method does not use the bounds of Problem")
+	}
+	if bounder, ok := method.(Bounder); ok {
+		bounder.SetBounds(prob.Bounds)
+	} else if uses.Bounds {
+		panic("optimize: method uses bounds but is not a Bounder")
+	}
+	if has.Project && !uses.Project {
+		panic("optimize: method does not use the projection of Problem")
+	}
+	if projector, ok := method.(Projector); ok {
+		projector.SetProjection(prob.Project)
+	} else if uses.Project {
+		panic("optimize: method uses a projection but is not a Projector")
+	}
+	if has.Linear && !uses.Linear {
+		panic("optimize: method does not use the linear constraints of Problem")
+	}
+	if constrainer, ok := method.(LinearConstrainer); ok {
+		constrainer.SetLinearConstraints(prob.Linear)
+	} else if uses.Linear {
+		panic("optimize: method uses linear constraints but is not a LinearConstrainer")
+	}
+	if has.Nonlinear && !uses.Nonlinear {
+		panic("optimize: method does not use the nonlinear constraints of Problem")
+	}
+	if constrainer, ok := method.(NonlinearConstrainer); ok {
+		constrainer.SetNonlinearConstraints(prob.Nonlinear)
+	} else if uses.Nonlinear {
+		panic("optimize: method uses nonlinear constraints but is not a NonlinearConstrainer")
+	}
+	if nt, ok := method.(NoiseTolerant); ok {
+		nt.SetFunctionNoise(settings.FunctionNoise)
+	}
+	if hessVecer, ok := method.(HessVecer); ok {
+		hessVecer.SetHessVec(prob.HessVec)
+	} else if uses.HessVec {
+		panic("optimize: method uses HessVec but is not a HessVecer")
+	}
+	var cons *constraintSet
+	if has.Bounds || has.Linear || has.Nonlinear {
+		cons = &constraintSet{
+			bounds:    prob.Bounds,
+			linear:    prob.Linear,
+			nonlinear: prob.Nonlinear,
+		}
+		cons.init(dim)
+	}
 	newNTasks := method.Init(dim, nTasks)
 	if newNTasks > nTasks {
 		panic("optimize: too many tasks returned by Method")
@@ -319,7 +573,7 @@ func minimize(prob *Problem, method Method, settings *Settings, converger Conver
 	worker := func() {
 		x := make([]float64, dim)
 		for task := range workerChan {
-			evaluate(prob, task.Location, task.Op, x)
+			task.err = evaluateSafely(prob, task.Location, task.Op, x, settings)
 			statsChan <- task
 		}
 		// Signal successful worker completion.
@@ -346,6 +600,22 @@ func minimize(prob *Problem, method Method, settings *Settings, converger Conver
 				panic("minimize: evaluation task expected")
 			}
 			updateEvaluationStats(stats, task.Op)
+			if fdGrad != nil {
+				stats.FuncEvaluations += fdGrad.evaluations()
+			}
+			if task.err != nil {
+				status = EvaluationFailure
+				err = task.err
+				break
+			}
+			if floats.Equal(task.X, initX) {
+				if math.IsNaN(init.F) && task.Op&FuncEvaluation != 0 {
+					init.F = task.F
+				}
+				if math.IsNaN(init.GradNorm) && task.Op&GradEvaluation != 0 {
+					init.GradNorm = projGradNorm(task.X, task.Gradient, prob.Bounds)
+				}
+			}
 			status, err = checkEvaluationLimits(prob, stats, settings)
 		case signalDone:
 			workersDone++
@@ -356,16 +626,30 @@ func minimize(prob *Problem, method Method, settings *Settings, converger Conver
 		case NoOperation:
 			// Just send the task back.
 		case MajorIteration:
-			status = performMajorIteration(optLoc, task.Location, stats, converger, startTime, settings)
+			if cons != nil {
+				stats.ConstraintViolation = cons.maxViolation(task.X)
+			}
+			status = performMajorIteration(optLoc, task.Location, stats, converger, startTime, settings, prob.Bounds, init.GradNorm)
+			if traj != nil {
+				traj.add(task.Location, stats)
+			}
 		case MethodDone:
 			methodDone = true
 			status = MethodConverge
 		}
+		if status == NotTerminated && err == nil && ctx.Err() != nil {
+			status = Cancelled
+			err = ctx.Err()
+		}
 		if settings.Recorder != nil && status == NotTerminated && err == nil {
 			stats.Runtime = time.Since(startTime)
 			// Allow err to be overloaded if the Recorder fails.
 			err = settings.Recorder.Record(task.Location, task.Op, stats)
-			if err != nil {
+			switch {
+			case err == ErrStop && task.Op == MajorIteration:
+				status = Stopped
+				err = nil
+			case err != nil:
 				status = Failure
 			}
 		}
@@ -404,6 +688,13 @@ func minimize(prob *Problem, method Method, settings *Settings, converger Conver
 	return finalStatus, finalError
 }
 
+// hasLimit returns whether the optimization of p is limited by the settings
+// or can be terminated by p.Status.
+func hasLimit(p Problem, settings *Settings) bool {
+	return p.Status != nil || settings.MajorIterations > 0 || settings.Runtime > 0 ||
+		settings.FuncEvaluations > 0 || settings.GradEvaluations > 0
+}
+
 func defaultFunctionConverge() *FunctionConverge {
 	return &FunctionConverge{
 		Absolute:   1e-10,
@@ -489,7 +780,14 @@ func evaluate(p *Problem, loc *Location, op Operation, x []float64) {
 	if op&FuncEvaluation != 0 {
 		loc.F = p.Func(x)
 	}
-	if op&GradEvaluation != 0 {
+	if op&GradEvaluation != 0 && p.Grad == nil && p.SparseGrad != nil {
+		if loc.SparseGradient == nil {
+			loc.SparseGradient = &SparseGradient{}
+		}
+		loc.SparseGradient.Reset()
+		p.SparseGrad(loc.SparseGradient, x)
+		loc.Gradient = nil
+	} else if op&GradEvaluation != 0 {
 		// Make sure we have a destination in which to place the gradient.
 		if len(loc.Gradient) == 0 {
 			if cap(loc.Gradient) < len(x) {
@@ -512,6 +810,75 @@ func evaluate(p *Problem, loc *Location, op Operation, x []float64) {
 	}
 }
 
+// evaluateSafely performs evaluate subject to Settings.EvaluationTimeout and
+// Settings.RecoverPanics, and returns the error of an evaluation that timed out
+// or panicked.
+func evaluateSafely(p *Problem, loc *Location, op Operation, x []float64, settings *Settings) error {
+	if settings.EvaluationTimeout == 0 {
+		if settings.RecoverPanics {
+			return evaluateRecover(p, loc, op, x)
+		}
+		evaluate(p, loc, op, x)
+		return nil
+	}
+	// Evaluate into a separate Location, so that an evaluation that does not
+	// finish in time cannot modify loc after it is returned to the Method.
+	sep := &Location{X: make([]float64, len(loc.X))}
+	copy(sep.X, loc.X)
+	sepX := make([]float64, len(x))
+	done := make(chan error, 1)
+	go func() {
+		if settings.RecoverPanics {
+			done <- evaluateRecover(p, sep, op, sepX)
+			return
+		}
+		evaluate(p, sep, op, sepX)
+		done <- nil
+	}()
+	timer := time.NewTimer(settings.EvaluationTimeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			return err
+		}
+		if op&FuncEvaluation != 0 {
+			loc.F = sep.F
+		}
+		if op&GradEvaluation != 0 && sep.SparseGradient != nil {
+			loc.SparseGradient = copySparseGradient(loc.SparseGradient, sep.SparseGradient)
+			loc.Gradient = nil
+		} else if op&GradEvaluation != 0 {
+			loc.Gradient = resize(loc.Gradient, len(sep.Gradient))
+			copy(loc.Gradient, sep.Gradient)
+		}
+		if op&HessEvaluation != 0 {
+			switch {
+			case loc.Hessian == nil:
+				loc.Hessian = mat.NewSymDense(len(x), nil)
+			case loc.Hessian.IsEmpty():
+				loc.Hessian.ReuseAsSym(len(x))
+			}
+			loc.Hessian.CopySym(sep.Hessian)
+		}
+		return nil
+	case <-timer.C:
+		return ErrEvaluationTimeout
+	}
+}
+
+// evaluateRecover performs evaluate and returns an ErrEvaluationPanic if the
+// evaluation panicked.
+func evaluateRecover(p *Problem, loc *Location, op Operation, x []float64) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = ErrEvaluationPanic{Op: op, Value: r}
+		}
+	}()
+	evaluate(p, loc, op, x)
+	return nil
+}
+
 // updateEvaluationStats updates the statistics based on the operation.
 func updateEvaluationStats(stats *Stats, op Operation) {
 	if op&FuncEvaluation != 0 {
@@ -532,15 +899,24 @@ func updateEvaluationStats(stats *Stats, op Operation) {
 // the convergence criteria given by settings. Otherwise a corresponding status is
 // returned.
 // Unlike checkLimits, checkConvergence is called only at MajorIterations.
-func checkLocationConvergence(loc *Location, settings *Settings, converger Converger) Status {
+// If bounds is not nil, the gradient norm is the norm of the projected gradient.
+// initGradNorm is the gradient norm at the initial location, or NaN if it is
+// not known.
+func checkLocationConvergence(loc *Location, stats *Stats, settings *Settings, converger Converger, bounds []Bound, initGradNorm float64) Status {
 	if math.IsInf(loc.F, -1) {
 		return FunctionNegativeInfinity
 	}
-	if loc.Gradient != nil && settings.GradientThreshold > 0 {
-		norm := floats.Norm(loc.Gradient, math.Inf(1))
+	if loc.Gradient != nil && (settings.GradientThreshold > 0 || settings.RelGradientThreshold > 0) {
+		norm := projGradNorm(loc.X, loc.Gradient, bounds)
 		if norm < settings.GradientThreshold {
 			return GradientThreshold
 		}
+		if settings.RelGradientThreshold > 0 && norm <= settings.RelGradientThreshold*initGradNorm {
+			return RelativeGradientThreshold
+		}
+	}
+	if sc, ok := converger.(StatsConverger); ok {
+		return sc.ConvergedStats(loc, stats)
 	}
 	return converger.Converged(loc)
 }
@@ -581,7 +957,7 @@ func checkIterationLimits(loc *Location, stats *Stats, settings *Settings) Statu
 // performMajorIteration does all of the steps needed to perform a MajorIteration.
 // It increments the iteration count, updates the optimal location, and checks
 // the necessary convergence criteria.
-func performMajorIteration(optLoc, loc *Location, stats *Stats, converger Converger, startTime time.Time, settings *Settings) Status {
+func performMajorIteration(optLoc, loc *Location, stats *Stats, converger Converger, startTime time.Time, settings *Settings, bounds []Bound, initGradNorm float64) Status {
 	optLoc.F = loc.F
 	copy(optLoc.X, loc.X)
 	if loc.Gradient == nil {
@@ -592,9 +968,14 @@ func performMajorIteration(optLoc, loc *Location, stats *Stats, converger Conver
 		}
 		copy(optLoc.Gradient, loc.Gradient)
 	}
+	if loc.SparseGradient == nil {
+		optLoc.SparseGradient = nil
+	} else {
+		optLoc.SparseGradient = copySparseGradient(optLoc.SparseGradient, loc.SparseGradient)
+	}
 	stats.MajorIterations++
 	stats.Runtime = time.Since(startTime)
-	status := checkLocationConvergence(optLoc, settings, converger)
+	status := checkLocationConvergence(optLoc, stats, settings, converger, bounds, initGradNorm)
 	if status != NotTerminated {
 		return status
 	}
@@ -6,7 +6,10 @@ package optimize
 
 import "math"
 
-var _ Linesearcher = (*MoreThuente)(nil)
+var (
+	_ Linesearcher  = (*MoreThuente)(nil)
+	_ NoiseTolerant = (*MoreThuente)(nil)
+)
 
 // MoreThuente is a Linesearcher that finds steps that satisfy both the
 // sufficient decrease and curvature conditions (the strong Wolfe conditions).
@@ -39,6 +42,8 @@ type MoreThuente struct {
 	// to 1e20.
 	MaximumStep float64
 
+	noise float64 // Estimate of the noise in the function values.
+
 	bracketed bool    // Indicates if a minimum has been bracketed.
 	fInit     float64 // Function value at step = 0.
 	gInit     float64 // Derivative value at step = 0.
@@ -136,7 +141,7 @@ func (mt *MoreThuente) Iterate(f, g float64) (Operation, float64, error) {
 	}
 
 	gTest := mt.DecreaseFactor * mt.gInit
-	fTest := mt.fInit + mt.step*gTest
+	fTest := mt.fInit + 2*mt.noise + mt.step*gTest
 
 	if mt.bracketed {
 		if mt.step <= mt.lower || mt.step >= mt.upper || mt.upper-mt.lower <= mt.StepTolerance*mt.upper {
@@ -216,6 +221,13 @@ func (mt *MoreThuente) Iterate(f, g float64) (Operation, float64, error) {
 	return FuncEvaluation | GradEvaluation, mt.step, nil
 }
 
+// SetFunctionNoise sets the estimate of the noise in the function values. The
+// sufficient decrease condition is relaxed by twice the noise. It implements
+// the NoiseTolerant interface.
+func (mt *MoreThuente) SetFunctionNoise(noise float64) {
+	mt.noise = noise
+}
+
 // nextStep computes the next safeguarded step and updates the interval that
 // contains a step that satisfies the sufficient decrease and curvature
 // conditions.
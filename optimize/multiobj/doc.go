@@ -0,0 +1,13 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package multiobj implements methods for multi-objective optimization
+// problems,
+//  minimize (f_1(x), ..., f_m(x)),
+// where the objective functions are in conflict, so that there is no single
+// location minimizing all of them. A location x dominates a location y if
+// f_i(x) ≤ f_i(y) for all i and f_i(x) < f_i(y) for some i, and the methods
+// approximate the Pareto front of the locations that are not dominated by
+// any other location.
+package multiobj // import "gonum.org/v1/gonum/optimize/multiobj"
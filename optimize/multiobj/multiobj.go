@@ -0,0 +1,316 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package multiobj
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"gonum.org/v1/gonum/optimize"
+)
+
+var (
+	// ErrMissingFuncs signifies that the Problem does not provide any
+	// objective functions.
+	ErrMissingFuncs = errors.New("multiobj: problem does not provide Funcs")
+
+	// ErrMissingBounds signifies that the Problem does not provide finite
+	// bounds on all variables.
+	ErrMissingBounds = errors.New("multiobj: problem does not provide finite Bounds")
+)
+
+// Problem describes a multi-objective optimization problem.
+type Problem struct {
+	// Funcs are the objective functions, which must not modify x. A NaN
+	// function value is treated as +Inf.
+	Funcs []func(x []float64) float64
+
+	// Bounds are the finite bounds on the variables, which also determine
+	// the dimension of the problem.
+	Bounds []optimize.Bound
+}
+
+// Settings controls the termination of Minimize and the computation of
+// the hypervolume of the result.
+type Settings struct {
+	// MajorIterations is the maximum number of major iterations, which are
+	// the generations of the population. If MajorIterations is 0, a default
+	// value of 100 is used, and if it is negative, this setting has no
+	// effect.
+	MajorIterations int
+
+	// FuncEvaluations is the maximum number of evaluations of the objective
+	// functions at a location. The limit is checked at major iterations.
+	// If it is zero, this setting has no effect.
+	FuncEvaluations int
+
+	// Runtime is the maximum runtime, checked at major iterations. If it
+	// is zero, this setting has no effect.
+	Runtime time.Duration
+
+	// Reference is the reference point for computing the hypervolume of
+	// the Pareto front. If Reference is nil, the nadir point of the front
+	// moved by a tenth of the range of each objective on the front is used.
+	Reference []float64
+}
+
+// Point is a location and its objective function values.
+type Point struct {
+	X []float64
+	F []float64
+}
+
+// Result represents the answer of a multi-objective optimization.
+type Result struct {
+	// Front holds the members of the final population that are not
+	// dominated by any other member, sorted by increasing value of the
+	// first objective.
+	Front []Point
+
+	// Hypervolume is the volume of the region of objective values that is
+	// dominated by the Front and bounded by Reference, which measures both
+	// the convergence and the spread of the Front.
+	Hypervolume float64
+	Reference   []float64
+
+	Status optimize.Status
+
+	// Stats holds the statistics of the optimization. FuncEvaluations
+	// counts the evaluations of all objective functions at a location as
+	// one evaluation.
+	optimize.Stats
+}
+
+// Method is a method for multi-objective optimization problems. Method is
+// implemented by NSGAII.
+type Method interface {
+	// init initializes the method and the population of s.
+	init(s *state)
+	// iterate replaces the population of s by the next generation.
+	iterate(s *state)
+}
+
+// Minimize approximates the Pareto front of the Problem using the given
+// Method. If settings is nil, the default settings are used, and if method
+// is nil, NSGAII is used.
+func Minimize(p Problem, settings *Settings, method Method) (*Result, error) {
+	startTime := time.Now()
+	if len(p.Funcs) == 0 {
+		return nil, ErrMissingFuncs
+	}
+	if len(p.Bounds) == 0 {
+		return nil, ErrMissingBounds
+	}
+	for _, b := range p.Bounds {
+		if math.IsInf(b.Min, 0) || math.IsInf(b.Max, 0) || math.IsNaN(b.Min) || math.IsNaN(b.Max) {
+			return nil, ErrMissingBounds
+		}
+		if b.Min > b.Max {
+			panic("multiobj: lower bound larger than upper bound")
+		}
+	}
+	if settings == nil {
+		settings = &Settings{}
+	}
+	if settings.Reference != nil && len(settings.Reference) != len(p.Funcs) {
+		panic("multiobj: reference point length mismatch")
+	}
+	if method == nil {
+		method = &NSGAII{}
+	}
+	maxIter := settings.MajorIterations
+	if maxIter == 0 {
+		maxIter = 100
+	}
+
+	s := &state{
+		p:   &p,
+		dim: len(p.Bounds),
+	}
+	method.init(s)
+
+	var status optimize.Status
+	for {
+		if maxIter > 0 && s.stats.MajorIterations >= maxIter {
+			status = optimize.IterationLimit
+			break
+		}
+		if settings.FuncEvaluations > 0 && s.stats.FuncEvaluations >= settings.FuncEvaluations {
+			status = optimize.FunctionEvaluationLimit
+			break
+		}
+		if settings.Runtime > 0 && time.Since(startTime) >= settings.Runtime {
+			status = optimize.RuntimeLimit
+			break
+		}
+		method.iterate(s)
+		s.stats.MajorIterations++
+	}
+
+	front := nonDominated(s.pop)
+	sort.Slice(front, func(i, j int) bool {
+		return front[i].F[0] < front[j].F[0]
+	})
+	fs := make([][]float64, len(front))
+	for i, pt := range front {
+		fs[i] = pt.F
+	}
+	ref := settings.Reference
+	if ref == nil {
+		ref = nadir(fs)
+	}
+	s.stats.Runtime = time.Since(startTime)
+
+	return &Result{
+		Front:       front,
+		Hypervolume: Hypervolume(fs, ref),
+		Reference:   ref,
+		Status:      status,
+		Stats:       s.stats,
+	}, nil
+}
+
+// state is the state of a multi-objective optimization shared by Minimize
+// and the Method.
+type state struct {
+	p   *Problem
+	dim int
+
+	pop []Point // Current population
+
+	stats optimize.Stats
+}
+
+// eval evaluates the objective functions at x and returns the Point.
+func (s *state) eval(x []float64) Point {
+	s.stats.FuncEvaluations++
+	f := make([]float64, len(s.p.Funcs))
+	for i, fn := range s.p.Funcs {
+		f[i] = fn(x)
+		if math.IsNaN(f[i]) {
+			f[i] = math.Inf(1)
+		}
+	}
+	return Point{X: x, F: f}
+}
+
+// Dominates returns whether the objective values a dominate the objective
+// values b, that is a_i ≤ b_i for all i and a_i < b_i for some i.
+func Dominates(a, b []float64) bool {
+	if len(a) != len(b) {
+		panic("multiobj: slice length mismatch")
+	}
+	var less bool
+	for i, v := range a {
+		if v > b[i] {
+			return false
+		}
+		if v < b[i] {
+			less = true
+		}
+	}
+	return less
+}
+
+// nonDominated returns the points that are not dominated by any other point.
+func nonDominated(pts []Point) []Point {
+	var front []Point
+	for i, p := range pts {
+		dominated := false
+		for j, q := range pts {
+			if i != j && Dominates(q.F, p.F) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			front = append(front, p)
+		}
+	}
+	return front
+}
+
+// nadir returns the nadir point of the objective values fs moved by a tenth
+// of the range of each objective.
+func nadir(fs [][]float64) []float64 {
+	if len(fs) == 0 {
+		return nil
+	}
+	m := len(fs[0])
+	ref := make([]float64, m)
+	for j := 0; j < m; j++ {
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, f := range fs {
+			lo = math.Min(lo, f[j])
+			hi = math.Max(hi, f[j])
+		}
+		ref[j] = hi + 0.1*(hi-lo)
+	}
+	return ref
+}
+
+// Hypervolume returns the volume of the region of objective values that is
+// dominated by at least one of the objective values in fs and that dominates
+// the reference point ref. The objective values that do not dominate ref do
+// not contribute to the hypervolume.
+//
+// Hypervolume computes the volume exactly by slicing along the objectives,
+// so its cost grows as len(fs)^len(ref). It is intended for a moderate
+// number of objectives.
+func Hypervolume(fs [][]float64, ref []float64) float64 {
+	pts := make([][]float64, 0, len(fs))
+	for _, f := range fs {
+		if len(f) != len(ref) {
+			panic("multiobj: slice length mismatch")
+		}
+		inside := true
+		for j, v := range f {
+			if !(v < ref[j]) {
+				inside = false
+				break
+			}
+		}
+		if inside {
+			pts = append(pts, f)
+		}
+	}
+	return hypervolume(pts, ref, len(ref))
+}
+
+// hypervolume returns the hypervolume of pts with respect to ref in the
+// first m objectives. All pts must dominate ref.
+func hypervolume(pts [][]float64, ref []float64, m int) float64 {
+	if len(pts) == 0 {
+		return 0
+	}
+	if m == 1 {
+		lo := ref[0]
+		for _, p := range pts {
+			lo = math.Min(lo, p[0])
+		}
+		return ref[0] - lo
+	}
+	sorted := make([][]float64, len(pts))
+	copy(sorted, pts)
+	k := m - 1
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i][k] < sorted[j][k]
+	})
+	// The slice between the k-th objective values of consecutive points is
+	// dominated by the points below it.
+	var vol float64
+	for i, p := range sorted {
+		upper := ref[k]
+		if i+1 < len(sorted) {
+			upper = sorted[i+1][k]
+		}
+		if h := upper - p[k]; h > 0 {
+			vol += h * hypervolume(sorted[:i+1], ref, k)
+		}
+	}
+	return vol
+}
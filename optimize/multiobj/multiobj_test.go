@@ -0,0 +1,161 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package multiobj
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize"
+)
+
+func bounds(n int, min, max float64) []optimize.Bound {
+	b := make([]optimize.Bound, n)
+	for i := range b {
+		b[i] = optimize.Bound{Min: min, Max: max}
+	}
+	return b
+}
+
+// zdt1 returns the ZDT1 problem of Zitzler, Deb and Thiele, whose Pareto
+// front is f_2 = 1 - √f_1 for f_1 in [0, 1].
+func zdt1(n int) Problem {
+	g := func(x []float64) float64 {
+		return 1 + 9*floats.Sum(x[1:])/float64(len(x)-1)
+	}
+	return Problem{
+		Funcs: []func([]float64) float64{
+			func(x []float64) float64 { return x[0] },
+			func(x []float64) float64 {
+				gx := g(x)
+				return gx * (1 - math.Sqrt(x[0]/gx))
+			},
+		},
+		Bounds: bounds(n, 0, 1),
+	}
+}
+
+func TestNSGAIIZDT1(t *testing.T) {
+	t.Parallel()
+	ref := []float64{1.1, 1.1}
+	// The hypervolume of the Pareto front with respect to ref.
+	want := 0.1*1.1 + 0.1 + 2.0/3
+	res, err := Minimize(zdt1(30), &Settings{MajorIterations: 250, Reference: ref}, &NSGAII{Src: rand.NewSource(1)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != optimize.IterationLimit || res.MajorIterations != 250 {
+		t.Errorf("unexpected status %v after %d iterations", res.Status, res.MajorIterations)
+	}
+	if res.FuncEvaluations != 251*100 {
+		t.Errorf("unexpected number of evaluations: got %d, want %d", res.FuncEvaluations, 251*100)
+	}
+	if len(res.Front) < 50 {
+		t.Errorf("front too small: %d points", len(res.Front))
+	}
+	for i, pt := range res.Front {
+		if dist := pt.F[1] - (1 - math.Sqrt(pt.F[0])); dist > 0.05 {
+			t.Errorf("point %d too far from the Pareto front: %v", i, pt.F)
+		}
+		if i > 0 && pt.F[0] < res.Front[i-1].F[0] {
+			t.Errorf("front not sorted at %d", i)
+		}
+	}
+	if res.Hypervolume > want || res.Hypervolume < want-0.02 {
+		t.Errorf("unexpected hypervolume: got %v, want close to %v", res.Hypervolume, want)
+	}
+}
+
+func TestNSGAIISchaffer(t *testing.T) {
+	t.Parallel()
+	// The Pareto optimal locations of Schaffer's problem are x in [0, 2].
+	p := Problem{
+		Funcs: []func([]float64) float64{
+			func(x []float64) float64 { return x[0] * x[0] },
+			func(x []float64) float64 { return (x[0] - 2) * (x[0] - 2) },
+		},
+		Bounds: bounds(1, -10, 10),
+	}
+	res, err := Minimize(p, &Settings{FuncEvaluations: 2000}, &NSGAII{Population: 40, Src: rand.NewSource(1)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != optimize.FunctionEvaluationLimit {
+		t.Errorf("unexpected status: %v", res.Status)
+	}
+	if len(res.Front) != 40 {
+		t.Errorf("unexpected front size: got %d, want 40", len(res.Front))
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, pt := range res.Front {
+		lo = math.Min(lo, pt.X[0])
+		hi = math.Max(hi, pt.X[0])
+	}
+	if lo < -1e-3 || hi > 2+1e-3 || lo > 0.05 || hi < 1.95 {
+		t.Errorf("front does not span the Pareto set: [%v, %v]", lo, hi)
+	}
+	if len(res.Reference) != 2 || res.Hypervolume <= 0 {
+		t.Errorf("unexpected hypervolume %v for reference %v", res.Hypervolume, res.Reference)
+	}
+}
+
+func TestMinimizeErrors(t *testing.T) {
+	t.Parallel()
+	_, err := Minimize(Problem{Bounds: bounds(1, 0, 1)}, nil, nil)
+	if err != ErrMissingFuncs {
+		t.Errorf("unexpected error for missing Funcs: got %v, want %v", err, ErrMissingFuncs)
+	}
+	f := []func([]float64) float64{func(x []float64) float64 { return x[0] }}
+	_, err = Minimize(Problem{Funcs: f}, nil, nil)
+	if err != ErrMissingBounds {
+		t.Errorf("unexpected error for missing Bounds: got %v, want %v", err, ErrMissingBounds)
+	}
+	_, err = Minimize(Problem{Funcs: f, Bounds: bounds(1, 0, math.Inf(1))}, nil, nil)
+	if err != ErrMissingBounds {
+		t.Errorf("unexpected error for infinite Bounds: got %v, want %v", err, ErrMissingBounds)
+	}
+}
+
+func TestDominates(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		a, b []float64
+		want bool
+	}{
+		{a: []float64{1, 2}, b: []float64{2, 3}, want: true},
+		{a: []float64{1, 3}, b: []float64{2, 3}, want: true},
+		{a: []float64{1, 3}, b: []float64{1, 3}, want: false},
+		{a: []float64{1, 4}, b: []float64{2, 3}, want: false},
+		{a: []float64{2, 3}, b: []float64{1, 2}, want: false},
+	} {
+		if got := Dominates(test.a, test.b); got != test.want {
+			t.Errorf("unexpected result for %v and %v: got %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestHypervolume(t *testing.T) {
+	t.Parallel()
+	for i, test := range []struct {
+		fs   [][]float64
+		ref  []float64
+		want float64
+	}{
+		{fs: nil, ref: []float64{1, 1}, want: 0},
+		{fs: [][]float64{{0, 0}}, ref: []float64{1, 2}, want: 2},
+		{fs: [][]float64{{1, 3}, {2, 2}, {3, 1}}, ref: []float64{4, 4}, want: 6},
+		// Dominated points and points outside the reference do not contribute.
+		{fs: [][]float64{{1, 3}, {2, 2}, {3, 1}, {3, 3}, {5, 0}}, ref: []float64{4, 4}, want: 6},
+		{fs: [][]float64{{0, 0, 0}}, ref: []float64{1, 2, 3}, want: 6},
+		{fs: [][]float64{{0, 1, 1}, {1, 0, 1}, {1, 1, 0}}, ref: []float64{2, 2, 2}, want: 4},
+	} {
+		if got := Hypervolume(test.fs, test.ref); math.Abs(got-test.want) > 1e-14 {
+			t.Errorf("case %d: unexpected hypervolume: got %v, want %v", i, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package multiobj
+
+import (
+	"math"
+	"sort"
+
+	"golang.org/x/exp/rand"
+)
+
+var _ Method = (*NSGAII)(nil)
+
+// NSGAII is the non-dominated sorting genetic algorithm II described in
+//  Deb, K., Pratap, A., Agarwal, S., Meyarivan, T.: A fast and elitist
+//  multiobjective genetic algorithm: NSGA-II. IEEE Trans. Evol. Comput. 6
+//  (2002), 182-197
+// At each generation, an offspring population is created from parents
+// chosen by binary tournaments using simulated binary crossover and
+// polynomial mutation. The parent and offspring populations are combined
+// and sorted into fronts of non-dominated members, and the next population
+// is filled with the best fronts. The members of the last front that fits
+// only partially are chosen by their crowding distance, which is larger for
+// members in sparse regions of the front and keeps the population spread
+// along the Pareto front.
+//
+// The initial population is drawn uniformly from the bounds of the Problem.
+type NSGAII struct {
+	// Population is the size of the population. If Population is 0,
+	// a default value of 100 is used. Population must be even and at least
+	// 4 if it is not 0, or NSGAII will panic.
+	Population int
+	// CrossoverProb is the probability that two parents are combined by
+	// crossover. If CrossoverProb is 0, a default value of 0.9 is used.
+	CrossoverProb float64
+	// CrossoverIndex is the distribution index of the simulated binary
+	// crossover. Larger values create offspring closer to the parents. If
+	// CrossoverIndex is 0, a default value of 20 is used.
+	CrossoverIndex float64
+	// MutationProb is the probability that a variable of an offspring is
+	// mutated. If MutationProb is 0, a default value of one over the
+	// dimension is used.
+	MutationProb float64
+	// MutationIndex is the distribution index of the polynomial mutation.
+	// If MutationIndex is 0, a default value of 20 is used.
+	MutationIndex float64
+	// Src allows a random number generator to be supplied for generating
+	// the population. If Src is nil the generator in golang.org/x/exp/rand
+	// is used.
+	Src rand.Source
+
+	rnd    *rand.Rand
+	n      int
+	pc, pm float64
+	etaC   float64
+	etaM   float64
+
+	rank     []int     // Front index of the members of the population
+	crowding []float64 // Crowding distances of the members of the population
+}
+
+func (ga *NSGAII) init(s *state) {
+	ga.n = ga.Population
+	if ga.n == 0 {
+		ga.n = 100
+	}
+	if ga.n < 4 || ga.n%2 != 0 {
+		panic("multiobj: population not even or smaller than 4")
+	}
+	ga.pc = ga.CrossoverProb
+	if ga.pc == 0 {
+		ga.pc = 0.9
+	}
+	if ga.pc < 0 || ga.pc > 1 {
+		panic("multiobj: crossover probability not in [0, 1]")
+	}
+	ga.pm = ga.MutationProb
+	if ga.pm == 0 {
+		ga.pm = 1 / float64(s.dim)
+	}
+	if ga.pm < 0 || ga.pm > 1 {
+		panic("multiobj: mutation probability not in [0, 1]")
+	}
+	ga.etaC = ga.CrossoverIndex
+	if ga.etaC == 0 {
+		ga.etaC = 20
+	}
+	ga.etaM = ga.MutationIndex
+	if ga.etaM == 0 {
+		ga.etaM = 20
+	}
+	if ga.etaC < 0 || ga.etaM < 0 {
+		panic("multiobj: negative distribution index")
+	}
+	if ga.Src != nil {
+		ga.rnd = rand.New(ga.Src)
+	} else {
+		ga.rnd = rand.New(rand.NewSource(rand.Uint64()))
+	}
+
+	s.pop = make([]Point, ga.n)
+	for i := range s.pop {
+		x := make([]float64, s.dim)
+		for j, b := range s.p.Bounds {
+			x[j] = b.Min + ga.rnd.Float64()*(b.Max-b.Min)
+		}
+		s.pop[i] = s.eval(x)
+	}
+	ga.rank = make([]int, ga.n)
+	ga.crowding = make([]float64, ga.n)
+	for k, front := range sortFronts(s.pop) {
+		dist := crowdingDistances(s.pop, front)
+		for i, idx := range front {
+			ga.rank[idx] = k
+			ga.crowding[idx] = dist[i]
+		}
+	}
+}
+
+func (ga *NSGAII) iterate(s *state) {
+	all := make([]Point, 0, 2*ga.n)
+	all = append(all, s.pop...)
+	for len(all) < 2*ga.n {
+		x1 := s.pop[ga.tournament()].X
+		x2 := s.pop[ga.tournament()].X
+		c1, c2 := ga.crossover(s, x1, x2)
+		ga.mutate(s, c1)
+		ga.mutate(s, c2)
+		all = append(all, s.eval(c1), s.eval(c2))
+	}
+
+	// Fill the next population with the best fronts, and choose the members
+	// of the last front with the largest crowding distances.
+	next := make([]Point, 0, ga.n)
+	ga.rank = ga.rank[:0]
+	ga.crowding = ga.crowding[:0]
+	for k, front := range sortFronts(all) {
+		dist := crowdingDistances(all, front)
+		if len(next)+len(front) > ga.n {
+			sort.Sort(byCrowding{front, dist})
+			front = front[:ga.n-len(next)]
+		}
+		for i, idx := range front {
+			next = append(next, all[idx])
+			ga.rank = append(ga.rank, k)
+			ga.crowding = append(ga.crowding, dist[i])
+		}
+		if len(next) == ga.n {
+			break
+		}
+	}
+	s.pop = next
+}
+
+// tournament returns the index of the winner of a binary tournament by the
+// crowded comparison of two random members of the population.
+func (ga *NSGAII) tournament() int {
+	i := ga.rnd.Intn(ga.n)
+	j := ga.rnd.Intn(ga.n)
+	switch {
+	case ga.rank[i] < ga.rank[j]:
+		return i
+	case ga.rank[j] < ga.rank[i]:
+		return j
+	case ga.crowding[i] > ga.crowding[j]:
+		return i
+	case ga.crowding[j] > ga.crowding[i]:
+		return j
+	}
+	if ga.rnd.Intn(2) == 0 {
+		return i
+	}
+	return j
+}
+
+// crossover returns two offspring of the parents x1 and x2 created by the
+// simulated binary crossover for bounded variables.
+func (ga *NSGAII) crossover(s *state, x1, x2 []float64) (c1, c2 []float64) {
+	c1 = make([]float64, s.dim)
+	c2 = make([]float64, s.dim)
+	copy(c1, x1)
+	copy(c2, x2)
+	if ga.rnd.Float64() > ga.pc {
+		return c1, c2
+	}
+	exp := 1 / (ga.etaC + 1)
+	for j, b := range s.p.Bounds {
+		if ga.rnd.Float64() > 0.5 || math.Abs(x1[j]-x2[j]) <= 1e-14 {
+			continue
+		}
+		y1, y2 := math.Min(x1[j], x2[j]), math.Max(x1[j], x2[j])
+		u := ga.rnd.Float64()
+		spread := func(beta float64) float64 {
+			alpha := 2 - math.Pow(beta, -(ga.etaC+1))
+			if u <= 1/alpha {
+				return math.Pow(u*alpha, exp)
+			}
+			return math.Pow(1/(2-u*alpha), exp)
+		}
+		betaq := spread(1 + 2*(y1-b.Min)/(y2-y1))
+		v1 := 0.5 * ((y1 + y2) - betaq*(y2-y1))
+		betaq = spread(1 + 2*(b.Max-y2)/(y2-y1))
+		v2 := 0.5 * ((y1 + y2) + betaq*(y2-y1))
+		v1 = math.Min(math.Max(v1, b.Min), b.Max)
+		v2 = math.Min(math.Max(v2, b.Min), b.Max)
+		if ga.rnd.Intn(2) == 0 {
+			v1, v2 = v2, v1
+		}
+		c1[j], c2[j] = v1, v2
+	}
+	return c1, c2
+}
+
+// mutate applies the polynomial mutation for bounded variables to x.
+func (ga *NSGAII) mutate(s *state, x []float64) {
+	exp := 1 / (ga.etaM + 1)
+	for j, b := range s.p.Bounds {
+		if ga.rnd.Float64() >= ga.pm || b.Min == b.Max {
+			continue
+		}
+		width := b.Max - b.Min
+		u := ga.rnd.Float64()
+		var deltaq float64
+		if u < 0.5 {
+			xy := 1 - (x[j]-b.Min)/width
+			val := 2*u + (1-2*u)*math.Pow(xy, ga.etaM+1)
+			deltaq = math.Pow(val, exp) - 1
+		} else {
+			xy := 1 - (b.Max-x[j])/width
+			val := 2*(1-u) + 2*(u-0.5)*math.Pow(xy, ga.etaM+1)
+			deltaq = 1 - math.Pow(val, exp)
+		}
+		x[j] = math.Min(math.Max(x[j]+deltaq*width, b.Min), b.Max)
+	}
+}
+
+// sortFronts returns the indices of pts sorted into fronts by the fast
+// non-dominated sorting. The points of the first front are not dominated by
+// any point, and the points of each following front are only dominated by
+// points of the preceding fronts.
+func sortFronts(pts []Point) [][]int {
+	n := len(pts)
+	dominated := make([][]int, n) // Indices of the points dominated by each point
+	count := make([]int, n)       // Number of points dominating each point
+	var front []int
+	for i := range pts {
+		for j := i + 1; j < n; j++ {
+			switch {
+			case Dominates(pts[i].F, pts[j].F):
+				dominated[i] = append(dominated[i], j)
+				count[j]++
+			case Dominates(pts[j].F, pts[i].F):
+				dominated[j] = append(dominated[j], i)
+				count[i]++
+			}
+		}
+	}
+	for i, c := range count {
+		if c == 0 {
+			front = append(front, i)
+		}
+	}
+	var fronts [][]int
+	for len(front) > 0 {
+		fronts = append(fronts, front)
+		var next []int
+		for _, i := range front {
+			for _, j := range dominated[i] {
+				count[j]--
+				if count[j] == 0 {
+					next = append(next, j)
+				}
+			}
+		}
+		front = next
+	}
+	return fronts
+}
+
+// crowdingDistances returns the crowding distances of the points of pts with
+// the indices in front. The crowding distance is the sum over the objectives
+// of the normalized distance between the neighbors of a point, and it is
+// infinite for the extreme points of the front.
+func crowdingDistances(pts []Point, front []int) []float64 {
+	dist := make([]float64, len(front))
+	order := make([]int, len(front))
+	for k := range pts[front[0]].F {
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(a, b int) bool {
+			return pts[front[order[a]]].F[k] < pts[front[order[b]]].F[k]
+		})
+		lo := pts[front[order[0]]].F[k]
+		hi := pts[front[order[len(order)-1]]].F[k]
+		dist[order[0]] = math.Inf(1)
+		dist[order[len(order)-1]] = math.Inf(1)
+		if hi == lo || math.IsInf(hi-lo, 0) {
+			continue
+		}
+		for i := 1; i < len(order)-1; i++ {
+			next := pts[front[order[i+1]]].F[k]
+			prev := pts[front[order[i-1]]].F[k]
+			dist[order[i]] += (next - prev) / (hi - lo)
+		}
+	}
+	return dist
+}
+
+// byCrowding sorts the indices of a front by decreasing crowding distance.
+type byCrowding struct {
+	idx  []int
+	dist []float64
+}
+
+func (b byCrowding) Len() int           { return len(b.idx) }
+func (b byCrowding) Less(i, j int) bool { return b.dist[i] > b.dist[j] }
+func (b byCrowding) Swap(i, j int) {
+	b.idx[i], b.idx[j] = b.idx[j], b.idx[i]
+	b.dist[i], b.dist[j] = b.dist[j], b.dist[i]
+}
@@ -0,0 +1,265 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// StartSampling specifies how MultiStart samples the starting locations.
+type StartSampling int
+
+const (
+	// UniformStarts samples the starting locations independently and
+	// uniformly from the domain.
+	UniformStarts StartSampling = iota
+	// LatinHypercubeStarts samples the starting locations by Latin
+	// hypercube sampling of the domain, which places exactly one starting
+	// location in each of Starts equal slices of the domain along every
+	// dimension.
+	LatinHypercubeStarts
+)
+
+func (s StartSampling) String() string {
+	switch s {
+	case UniformStarts:
+		return "UniformStarts"
+	case LatinHypercubeStarts:
+		return "LatinHypercubeStarts"
+	}
+	return "StartSampling(?)"
+}
+
+// MultiStart is a global optimization driver that runs a local Method from
+// many starting locations sampled from a box-shaped domain, and collects the
+// distinct local minima found by the runs. The runs are independent, so they
+// may be performed concurrently.
+//
+// The starting locations are generated before any run is performed, and the
+// results are combined in the order of the starting locations, so the result
+// of MultiStart does not depend on Concurrent when Src is set.
+type MultiStart struct {
+	// Local returns the Method used for a local run. Local is called once
+	// for every run, so that concurrent runs do not share the state of the
+	// Method. If Local is nil, the default Method of Minimize is used.
+	Local func() Method
+	// Starts is the number of local runs. If Starts is 0, a default value
+	// of 20 is used. Starts cannot be negative, or MultiStart will panic.
+	Starts int
+	// Sampling specifies how the starting locations are sampled.
+	Sampling StartSampling
+	// Domain is the box from which the starting locations are sampled. If
+	// Domain is nil, the bounds of the Problem are used. The bounds of the
+	// domain must be finite, or MultiStart will panic.
+	Domain []Bound
+	// Concurrent is the number of local runs performed concurrently. If
+	// Concurrent is 0 or 1, the runs are performed sequentially.
+	Concurrent int
+	// BasinTol is the distance in the infinity norm below which two local
+	// minima are considered the same. If BasinTol is 0, a default value
+	// of 1e-4 is used.
+	BasinTol float64
+	// Src allows a random number generator to be supplied for sampling the
+	// starting locations. If Src is nil the generator in
+	// golang.org/x/exp/rand is used.
+	Src rand.Source
+}
+
+// LocalMinimum is a local minimum found by MultiStart.
+type LocalMinimum struct {
+	Location
+	// Status is the status of the best local run that converged to the
+	// minimum.
+	Status Status
+	// Runs is the number of local runs that converged to the minimum.
+	Runs int
+}
+
+// MultiStartResult is the result of MultiStart.
+type MultiStartResult struct {
+	// Result is the result of the local run that found the lowest function
+	// value, except that Stats holds the sum of the statistics of all
+	// local runs, and Stats.Runtime holds the total runtime of MultiStart.
+	Result
+	// Minima holds the distinct local minima found by the runs sorted in
+	// increasing order of the function value.
+	Minima []LocalMinimum
+	// Failed is the number of local runs that returned an error.
+	Failed int
+}
+
+// Minimize minimizes the objective function of the Problem of dimension dim
+// by local runs from sampled starting locations. The settings are used for
+// each local run, and Settings.InitValues must be nil. If Concurrent is larger
+// than one, Settings.Converger and Settings.Recorder must also be nil since
+// they would be shared by concurrent runs.
+//
+// The local runs that return an error are excluded from the result. If all
+// local runs return an error, Minimize returns the error of the first run.
+func (ms *MultiStart) Minimize(p Problem, dim int, settings *Settings) (*MultiStartResult, error) {
+	startTime := time.Now()
+	if dim <= 0 {
+		panic(nonpositiveDimension)
+	}
+	if settings == nil {
+		settings = &Settings{}
+	}
+	if settings.InitValues != nil {
+		panic("multistart: InitValues must be nil")
+	}
+	if ms.Concurrent > 1 && (settings.Converger != nil || settings.Recorder != nil) {
+		panic("multistart: Converger and Recorder cannot be shared by concurrent runs")
+	}
+	starts := ms.Starts
+	if starts == 0 {
+		starts = 20
+	} else if starts < 0 {
+		panic("multistart: negative number of starts")
+	}
+	domain := ms.Domain
+	if domain == nil {
+		domain = p.Bounds
+	}
+	if len(domain) != dim {
+		panic("multistart: domain dimension mismatch")
+	}
+	for _, b := range domain {
+		if math.IsInf(b.Min, 0) || math.IsInf(b.Max, 0) || b.Min > b.Max {
+			panic("multistart: domain must be a finite box")
+		}
+	}
+	basinTol := ms.BasinTol
+	if basinTol == 0 {
+		basinTol = 1e-4
+	}
+	var rnd *rand.Rand
+	if ms.Src != nil {
+		rnd = rand.New(ms.Src)
+	} else {
+		rnd = rand.New(rand.NewSource(rand.Uint64()))
+	}
+
+	xs := sampleStarts(starts, domain, ms.Sampling, rnd)
+	results := make([]*Result, starts)
+	errs := make([]error, starts)
+	run := func(i int) {
+		var method Method
+		if ms.Local != nil {
+			method = ms.Local()
+		}
+		s := *settings
+		results[i], errs[i] = Minimize(p, xs[i], &s, method)
+	}
+	if ms.Concurrent <= 1 {
+		for i := range xs {
+			run(i)
+		}
+	} else {
+		var wg sync.WaitGroup
+		jobs := make(chan int)
+		for w := 0; w < min(ms.Concurrent, starts); w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range jobs {
+					run(i)
+				}
+			}()
+		}
+		for i := range xs {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+	}
+
+	res := &MultiStartResult{}
+	var stats Stats
+	best := -1
+	for i, r := range results {
+		if r != nil {
+			stats.MajorIterations += r.MajorIterations
+			stats.FuncEvaluations += r.FuncEvaluations
+			stats.GradEvaluations += r.GradEvaluations
+			stats.HessEvaluations += r.HessEvaluations
+		}
+		if errs[i] != nil || r == nil {
+			res.Failed++
+			continue
+		}
+		if best < 0 || r.F < results[best].F {
+			best = i
+		}
+		res.Minima = addMinimum(res.Minima, r, basinTol)
+	}
+	if best < 0 {
+		return nil, errs[0]
+	}
+	sort.SliceStable(res.Minima, func(i, j int) bool {
+		return res.Minima[i].F < res.Minima[j].F
+	})
+	res.Result = *results[best]
+	constraintViolation := res.ConstraintViolation
+	res.Stats = stats
+	res.ConstraintViolation = constraintViolation
+	res.Runtime = time.Since(startTime)
+	return res, nil
+}
+
+// addMinimum adds the final location of the local run r to the minima. If the
+// location is within tol of a known minimum, the runs of the minimum are
+// incremented and its location is replaced if r has a lower function value.
+func addMinimum(minima []LocalMinimum, r *Result, tol float64) []LocalMinimum {
+	for i := range minima {
+		m := &minima[i]
+		if floats.Distance(m.X, r.X, math.Inf(1)) <= tol {
+			m.Runs++
+			if r.F < m.F {
+				m.Location = r.Location
+				m.Status = r.Status
+			}
+			return minima
+		}
+	}
+	return append(minima, LocalMinimum{
+		Location: r.Location,
+		Status:   r.Status,
+		Runs:     1,
+	})
+}
+
+// sampleStarts returns n locations sampled from the box domain.
+func sampleStarts(n int, domain []Bound, sampling StartSampling, rnd *rand.Rand) [][]float64 {
+	xs := make([][]float64, n)
+	for i := range xs {
+		xs[i] = make([]float64, len(domain))
+	}
+	switch sampling {
+	default:
+		panic("multistart: unknown sampling")
+	case UniformStarts:
+		for _, x := range xs {
+			for j, b := range domain {
+				x[j] = b.Min + rnd.Float64()*(b.Max-b.Min)
+			}
+		}
+	case LatinHypercubeStarts:
+		for j, b := range domain {
+			perm := rnd.Perm(n)
+			for i, x := range xs {
+				u := (float64(perm[i]) + rnd.Float64()) / float64(n)
+				x[j] = b.Min + u*(b.Max-b.Min)
+			}
+		}
+	}
+	return xs
+}
@@ -0,0 +1,66 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestMultiStart(t *testing.T) {
+	t.Parallel()
+	// The one-dimensional Rastrigin function has 11 local minima close to
+	// the integers in [-5, 5], and a Latin hypercube design of 33 starting
+	// locations places three of them in the basin of every minimum.
+	p := Problem{
+		Func: functions.Rastrigin{}.Func,
+		Grad: func(grad, x []float64) {
+			grad[0] = 2*x[0] + 20*math.Pi*math.Sin(2*math.Pi*x[0])
+		},
+		Bounds: []Bound{{-5.5, 5.5}},
+	}
+	var results []*MultiStartResult
+	for _, concurrent := range []int{0, 4} {
+		ms := &MultiStart{
+			Local:      func() Method { return &LBFGSB{} },
+			Starts:     33,
+			Sampling:   LatinHypercubeStarts,
+			Concurrent: concurrent,
+			Src:        rand.NewSource(1),
+		}
+		res, err := ms.Minimize(p, 1, &Settings{GradientThreshold: 1e-10})
+		if err != nil {
+			t.Fatalf("concurrent %d: unexpected error: %v", concurrent, err)
+		}
+		if math.Abs(res.X[0]) > 1e-8 || res.F > 1e-12 {
+			t.Errorf("concurrent %d: global minimum not found: got f(%v) = %v", concurrent, res.X, res.F)
+		}
+		if len(res.Minima) != 11 {
+			t.Errorf("concurrent %d: unexpected number of minima: got %d, want 11", concurrent, len(res.Minima))
+		}
+		runs := res.Failed
+		for i, m := range res.Minima {
+			runs += m.Runs
+			if i > 0 && m.F < res.Minima[i-1].F {
+				t.Errorf("concurrent %d: minima not sorted", concurrent)
+			}
+		}
+		if runs != 33 {
+			t.Errorf("concurrent %d: unexpected number of runs: got %d, want 33", concurrent, runs)
+		}
+		if res.Minima[0].F != res.F {
+			t.Errorf("concurrent %d: best minimum mismatch: got %v, want %v", concurrent, res.Minima[0].F, res.F)
+		}
+		results = append(results, res)
+	}
+	if len(results[0].Minima) != len(results[1].Minima) || results[0].FuncEvaluations != results[1].FuncEvaluations {
+		t.Errorf("concurrent run differs: got %d minima after %d evaluations, want %d minima after %d evaluations",
+			len(results[1].Minima), results[1].FuncEvaluations, len(results[0].Minima), results[0].FuncEvaluations)
+	}
+}
@@ -7,15 +7,35 @@ package optimize
 import (
 	"math"
 
+	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/mat"
 )
 
 const maxNewtonModifications = 20
 
+// HessianModification is the strategy of Newton for modifying a Hessian that
+// is not positive definite.
+type HessianModification int
+
+const (
+	// IdentityShift adds successively larger multiples of the identity to
+	// the Hessian until its Cholesky factorization succeeds.
+	IdentityShift HessianModification = iota
+	// EigenvalueModification replaces each eigenvalue λ of a Hessian that
+	// is not positive definite by max(|λ|, δ), where δ is the square root of the machine epsilon times
+	// the largest absolute eigenvalue, or one if it is smaller than one. The
+	// eigenvalue decomposition is more expensive than the Cholesky
+	// factorization, but it preserves the curvature along the eigenvectors
+	// with positive eigenvalues and reverses the negative curvature, which
+	// leads to better steps near saddle points.
+	EigenvalueModification
+)
+
 var (
 	_ Method          = (*Newton)(nil)
 	_ localMethod     = (*Newton)(nil)
 	_ NextDirectioner = (*Newton)(nil)
+	_ NoiseTolerant   = (*Newton)(nil)
 )
 
 // Newton implements a modified Newton's method for Hessian-based unconstrained
@@ -31,7 +51,8 @@ var (
 // a line search.
 //
 // Away from a minimizer H_k may not be positive definite and d_k may not be a
-// descent direction. Newton implements a Hessian modification strategy that
+// descent direction. Newton implements Hessian modification strategies that
+// make H_k positive definite, selected by Modification. By default, Newton
 // adds successively larger multiples of identity to H_k until it becomes
 // positive definite. Note that the repeated trial factorization of the
 // modified Hessian involved in this process can be computationally expensive.
@@ -51,6 +72,9 @@ type Newton struct {
 	// information in H.
 	// Increase must be greater than 1. If Increase is 0, it is defaulted to 5.
 	Increase float64
+	// Modification is the strategy for modifying a Hessian that is not
+	// positive definite. The default is IdentityShift.
+	Modification HessianModification
 	// GradStopThreshold sets the threshold for stopping if the gradient norm
 	// gets too small. If GradStopThreshold is 0 it is defaulted to 1e-12, and
 	// if it is NaN the setting is not used.
@@ -59,17 +83,30 @@ type Newton struct {
 	status Status
 	err    error
 
-	ls *LinesearchMethod
+	ls    *LinesearchMethod
+	noise float64 // Estimate of the noise in the function values
 
 	hess *mat.SymDense // Storage for a copy of the Hessian matrix.
 	chol mat.Cholesky  // Storage for the Cholesky factorization.
 	tau  float64
+
+	eigen  mat.EigenSym // Storage for the eigenvalue decomposition.
+	values []float64
+	vecs   mat.Dense
+	tmp    []float64
 }
 
 func (n *Newton) Status() (Status, error) {
 	return n.status, n.err
 }
 
+// SetFunctionNoise sets the estimate of the noise in the function values, which
+// is passed to the Linesearcher if it is NoiseTolerant. It implements the
+// NoiseTolerant interface.
+func (n *Newton) SetFunctionNoise(noise float64) {
+	n.noise = noise
+}
+
 func (*Newton) Uses(has Available) (uses Available, err error) {
 	return has.hessian()
 }
@@ -92,6 +129,11 @@ func (n *Newton) initLocal(loc *Location) (Operation, error) {
 	if n.Increase <= 1 {
 		panic("optimize: Newton.Increase must be greater than 1")
 	}
+	switch n.Modification {
+	case IdentityShift, EigenvalueModification:
+	default:
+		panic("optimize: unknown Newton.Modification")
+	}
 	if n.Linesearcher == nil {
 		n.Linesearcher = &Bisection{}
 	}
@@ -99,6 +141,7 @@ func (n *Newton) initLocal(loc *Location) (Operation, error) {
 		n.ls = &LinesearchMethod{}
 	}
 	n.ls.Linesearcher = n.Linesearcher
+	setLinesearchNoise(n.Linesearcher, n.noise)
 	n.ls.NextDirectioner = n
 	return n.ls.Init(loc)
 }
@@ -115,6 +158,10 @@ func (n *Newton) InitDirection(loc *Location, dir []float64) (stepSize float64)
 }
 
 func (n *Newton) NextDirection(loc *Location, dir []float64) (stepSize float64) {
+	if n.Modification == EigenvalueModification {
+		return n.eigenDirection(loc, dir)
+	}
+
 	// This method implements Algorithm 3.3 (Cholesky with Added Multiple of
 	// the Identity) from Nocedal, Wright (2006), 2nd edition.
 
@@ -178,3 +225,46 @@ func (n *Newton) needs() struct {
 		Hessian  bool
 	}{true, true}
 }
+
+// eigenDirection stores in dir the Newton direction for the Hessian, whose
+// eigenvalues are modified as described in section 3.4 of Nocedal, Wright
+// (2006), 2nd edition, if it is not positive definite.
+func (n *Newton) eigenDirection(loc *Location, dir []float64) (stepSize float64) {
+	dim := len(loc.X)
+	d := mat.NewVecDense(dim, dir)
+	grad := mat.NewVecDense(dim, loc.Gradient)
+	if n.chol.Factorize(loc.Hessian) {
+		err := n.chol.SolveVecTo(d, grad)
+		if err == nil {
+			d.ScaleVec(-1, d)
+			return 1
+		}
+	}
+	if !n.eigen.Factorize(loc.Hessian, true) {
+		// The decomposition failed, so return the negative gradient as the
+		// descent direction.
+		copy(dir, loc.Gradient)
+		floats.Scale(-1, dir)
+		return 1
+	}
+	n.values = n.eigen.Values(resize(n.values, dim))
+	n.vecs.Reset()
+	n.eigen.VectorsTo(&n.vecs)
+
+	var maxAbs float64
+	for _, v := range n.values {
+		maxAbs = math.Max(maxAbs, math.Abs(v))
+	}
+	delta := math.Sqrt(dlamchE) * math.Max(1, maxAbs)
+
+	// dir = -Q diag(1/max(|λ_i|, δ)) Qᵀ g.
+	n.tmp = resize(n.tmp, dim)
+	q := &n.vecs
+	t := mat.NewVecDense(dim, n.tmp)
+	t.MulVec(q.T(), grad)
+	for i, v := range n.values {
+		n.tmp[i] /= -math.Max(math.Abs(v), delta)
+	}
+	d.MulVec(q, t)
+	return 1
+}
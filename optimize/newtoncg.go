@@ -0,0 +1,176 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+var (
+	_ Method          = (*NewtonCG)(nil)
+	_ localMethod     = (*NewtonCG)(nil)
+	_ NextDirectioner = (*NewtonCG)(nil)
+	_ NoiseTolerant   = (*NewtonCG)(nil)
+	_ HessVecer       = (*NewtonCG)(nil)
+)
+
+// NewtonCG implements the truncated Newton method, also known as the
+// line search Newton-CG method, for unconstrained minimization. It only needs
+// the products of the Hessian with vectors provided by Problem.HessVec, which
+// makes it suitable for problems whose Hessian is too large to be formed or
+// factorized.
+//
+// At each iteration, the Newton equations
+//  H_k d_k = -∇f_k
+// are solved approximately by the conjugate gradient method, which is
+// terminated when the relative residual is less than the forcing term
+//  min(0.5, sqrt(|∇f_k|)),
+// or when a direction of non-positive curvature of H_k is encountered. The
+// step along d_k is found by a line search. The method is described in
+// Algorithm 7.1 of
+//  Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer (2006)
+//
+// The Hessian-vector products are not counted in the evaluation statistics.
+type NewtonCG struct {
+	// Linesearcher is used for selecting suitable steps along the descent
+	// direction d. If Linesearcher == nil, an appropriate default is chosen.
+	Linesearcher Linesearcher
+	// MaxCGIterations is the maximum number of conjugate gradient iterations
+	// per major iteration. If MaxCGIterations is 0, it is defaulted to the
+	// dimension of the problem.
+	MaxCGIterations int
+	// GradStopThreshold sets the threshold for stopping if the gradient norm
+	// gets too small. If GradStopThreshold is 0 it is defaulted to 1e-12, and
+	// if it is NaN the setting is not used.
+	GradStopThreshold float64
+
+	status Status
+	err    error
+
+	hessVec func(dst, x, v []float64)
+	ls      *LinesearchMethod
+	noise   float64 // Estimate of the noise in the function values
+
+	r  []float64 // Residual of the Newton equations
+	p  []float64 // Conjugate gradient search direction
+	hp []float64 // Hessian-vector product H p
+}
+
+func (n *NewtonCG) Status() (Status, error) {
+	return n.status, n.err
+}
+
+// SetFunctionNoise sets the estimate of the noise in the function values, which
+// is passed to the Linesearcher if it is NoiseTolerant. It implements the
+// NoiseTolerant interface.
+func (n *NewtonCG) SetFunctionNoise(noise float64) {
+	n.noise = noise
+}
+
+func (*NewtonCG) Uses(has Available) (uses Available, err error) {
+	return has.hessVec()
+}
+
+// SetHessVec sets the function that computes the Hessian-vector products.
+// It implements the HessVecer interface.
+func (n *NewtonCG) SetHessVec(hessVec func(dst, x, v []float64)) {
+	n.hessVec = hessVec
+}
+
+func (n *NewtonCG) Init(dim, tasks int) int {
+	n.status = NotTerminated
+	n.err = nil
+	return 1
+}
+
+func (n *NewtonCG) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	n.status, n.err = localOptimizer{}.run(n, n.GradStopThreshold, operation, result, tasks)
+	close(operation)
+}
+
+func (n *NewtonCG) initLocal(loc *Location) (Operation, error) {
+	if n.hessVec == nil {
+		panic("newtoncg: nil HessVec")
+	}
+	if n.MaxCGIterations < 0 {
+		panic("newtoncg: negative MaxCGIterations")
+	}
+	if n.Linesearcher == nil {
+		n.Linesearcher = &Bisection{}
+	}
+	if n.ls == nil {
+		n.ls = &LinesearchMethod{}
+	}
+	n.ls.Linesearcher = n.Linesearcher
+	setLinesearchNoise(n.Linesearcher, n.noise)
+	n.ls.NextDirectioner = n
+	return n.ls.Init(loc)
+}
+
+func (n *NewtonCG) iterateLocal(loc *Location) (Operation, error) {
+	return n.ls.Iterate(loc)
+}
+
+func (n *NewtonCG) InitDirection(loc *Location, dir []float64) (stepSize float64) {
+	dim := len(loc.X)
+	n.r = resize(n.r, dim)
+	n.p = resize(n.p, dim)
+	n.hp = resize(n.hp, dim)
+	return n.NextDirection(loc, dir)
+}
+
+func (n *NewtonCG) NextDirection(loc *Location, dir []float64) (stepSize float64) {
+	maxIter := n.MaxCGIterations
+	if maxIter == 0 {
+		maxIter = len(loc.X)
+	}
+
+	gnorm := floats.Norm(loc.Gradient, 2)
+	tol := math.Min(0.5, math.Sqrt(gnorm)) * gnorm
+
+	// Solve H d = -g starting from d = 0, for which the residual
+	// r = H d + g is equal to g.
+	for i := range dir {
+		dir[i] = 0
+	}
+	copy(n.r, loc.Gradient)
+	floats.ScaleTo(n.p, -1, n.r)
+	rr := floats.Dot(n.r, n.r)
+	for k := 0; k < maxIter; k++ {
+		n.hessVec(n.hp, loc.X, n.p)
+		curv := floats.Dot(n.p, n.hp)
+		if curv <= 0 || math.IsNaN(curv) {
+			// The direction p has non-positive curvature.
+			if k == 0 {
+				// Fall back to steepest descent.
+				copy(dir, n.p)
+			}
+			break
+		}
+		alpha := rr / curv
+		floats.AddScaled(dir, alpha, n.p)
+		floats.AddScaled(n.r, alpha, n.hp)
+		rrNew := floats.Dot(n.r, n.r)
+		if math.Sqrt(rrNew) <= tol {
+			break
+		}
+		floats.AddScaledTo(n.p, n.r, -rrNew/rr, n.p)
+		floats.Scale(-1, n.p)
+		rr = rrNew
+	}
+	return 1
+}
+
+func (n *NewtonCG) needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return struct {
+		Gradient bool
+		Hessian  bool
+	}{true, false}
+}
@@ -0,0 +1,75 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nonlin
+
+import (
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+var _ Method = (*Broyden)(nil)
+
+// Broyden is Broyden's method for systems of nonlinear equations. It
+// approximates the Jacobian by rank-one updates
+//  B_{k+1} = B_k + (y_k - B_k s_k) s_kᵀ / s_kᵀs_k,
+// where s_k is the step and y_k is the change of F, so that the Jacobian is
+// only evaluated at the initial location and when the approximation no longer
+// produces a step that decreases the norm of F. The step at each iteration
+// solves
+//  B_k d_k = -F(x_k)
+// and its length is found by a backtracking line search. See Chapter 8 of
+//  Dennis, J. E., Schnabel, R. B.: Numerical Methods for Unconstrained
+//  Optimization and Nonlinear Equations. SIAM (1996)
+// for more information.
+type Broyden struct {
+	b     *mat.Dense // Approximation of the Jacobian
+	fresh bool       // Indicator that b is the Jacobian at the current location
+	lu    mat.LU
+
+	d  *mat.VecDense
+	bs *mat.VecDense
+	y  []float64
+}
+
+func (br *Broyden) init(s *state) {
+	br.b = mat.NewDense(s.n, s.n, nil)
+	br.d = mat.NewVecDense(s.n, nil)
+	br.bs = mat.NewVecDense(s.n, nil)
+	br.y = make([]float64, s.n)
+	s.jacobian(br.b)
+	br.fresh = true
+}
+
+func (br *Broyden) iterate(s *state) (optimize.Status, error) {
+	for {
+		br.lu.Factorize(br.b)
+		err := br.lu.SolveVecTo(br.d, false, mat.NewVecDense(s.n, s.f))
+		if err == nil {
+			br.d.ScaleVec(-1, br.d)
+			if s.search(br.d.RawVector().Data) {
+				break
+			}
+			err = errNoDecrease
+		}
+		if br.fresh {
+			return optimize.Failure, err
+		}
+		// Restart the approximation from the Jacobian.
+		s.jacobian(br.b)
+		br.fresh = true
+	}
+
+	// Update the approximation with the accepted step.
+	floats.SubTo(br.y, s.trialF, s.f)
+	step := mat.NewVecDense(s.n, s.step)
+	br.bs.MulVec(br.b, step)
+	floats.Sub(br.y, br.bs.RawVector().Data)
+	br.b.RankOne(br.b, 1/mat.Dot(step, step), mat.NewVecDense(s.n, br.y), step)
+	br.fresh = false
+
+	s.accept()
+	return optimize.NotTerminated, nil
+}
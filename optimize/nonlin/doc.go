@@ -0,0 +1,10 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package nonlin implements methods for solving systems of nonlinear
+// equations,
+//  F(x) = 0,
+// where F maps a vector x of n unknowns to a vector of n values, for example
+// the equilibrium conditions of a physical or economic model.
+package nonlin // import "gonum.org/v1/gonum/optimize/nonlin"
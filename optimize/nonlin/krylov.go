@@ -0,0 +1,234 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nonlin
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+var _ Method = (*NewtonKrylov)(nil)
+
+// NewtonKrylov is the inexact Newton method for systems of nonlinear
+// equations in which the Newton equations
+//  J(x_k) d_k = -F(x_k)
+// are solved approximately by the GMRES method. GMRES only needs the products
+// of the Jacobian with vectors, which are approximated by the finite
+// differences
+//  J v ≈ (F(x + ε v) - F(x)) / ε
+// if the Problem does not provide the Jacobian, so that the Jacobian is never
+// formed. This makes NewtonKrylov suitable for large systems.
+//
+// GMRES is stopped when the norm of the residual of the Newton equations is
+// less than η_k |F(x_k)|. The forcing term η_k is chosen as in
+//  Eisenstat, S. C., Walker, H. F.: Choosing the forcing terms in an inexact
+//  Newton method. SIAM J. Sci. Comput. 17 (1996), 16-32
+// unless it is fixed by the Forcing field. The length of the step is found by
+// a backtracking line search, and if the line search fails, the Newton
+// equations are solved again with a smaller forcing term.
+type NewtonKrylov struct {
+	// KrylovDim is the maximum dimension of the Krylov subspace built by
+	// GMRES at each iteration. If KrylovDim is 0, a default value of the
+	// smaller of the dimension of the problem and 30 is used.
+	KrylovDim int
+	// Forcing is the fixed forcing term in (0, 1). If Forcing is 0, the
+	// forcing term is adapted to the convergence of the iterations.
+	Forcing float64
+
+	m       int
+	eta     float64
+	oldNorm float64
+
+	jac *mat.Dense // Jacobian if provided by the Problem
+	xe  []float64  // Perturbed location
+	fe  []float64  // Function value at xe
+
+	// GMRES storage.
+	v  [][]float64 // Orthonormal basis of the Krylov subspace
+	h  [][]float64 // Columns of the Hessenberg matrix
+	cs []float64   // Givens rotations
+	sn []float64
+	g  []float64 // Rotated right-hand side
+	y  []float64
+	d  []float64 // Newton step
+}
+
+func (nk *NewtonKrylov) init(s *state) {
+	if nk.Forcing < 0 || nk.Forcing >= 1 {
+		panic("nonlin: Forcing not in [0, 1)")
+	}
+	if nk.KrylovDim < 0 {
+		panic("nonlin: negative KrylovDim")
+	}
+	nk.m = nk.KrylovDim
+	if nk.m == 0 {
+		nk.m = min(s.n, 30)
+	}
+	nk.eta = nk.Forcing
+	if nk.eta == 0 {
+		nk.eta = 0.5
+	}
+	nk.oldNorm = s.norm
+
+	if s.p.Jacobian != nil {
+		nk.jac = mat.NewDense(s.n, s.n, nil)
+	}
+	nk.xe = make([]float64, s.n)
+	nk.fe = make([]float64, s.n)
+	nk.v = make([][]float64, nk.m+1)
+	for i := range nk.v {
+		nk.v[i] = make([]float64, s.n)
+	}
+	nk.h = make([][]float64, nk.m)
+	for j := range nk.h {
+		nk.h[j] = make([]float64, j+2)
+	}
+	nk.cs = make([]float64, nk.m)
+	nk.sn = make([]float64, nk.m)
+	nk.g = make([]float64, nk.m+1)
+	nk.y = make([]float64, nk.m)
+	nk.d = make([]float64, s.n)
+}
+
+func (nk *NewtonKrylov) iterate(s *state) (optimize.Status, error) {
+	if nk.jac != nil {
+		s.jacobian(nk.jac)
+	}
+	for {
+		nk.gmres(s)
+		if s.search(nk.d) {
+			break
+		}
+		// The inexact Newton step may not be a descent direction for the
+		// norm of F, so solve the Newton equations more accurately.
+		if nk.eta <= minEta {
+			return optimize.Failure, errNoDecrease
+		}
+		nk.eta = math.Max(0.01*nk.eta, minEta)
+	}
+	s.accept()
+	nk.updateForcing(s.norm)
+	return optimize.NotTerminated, nil
+}
+
+// updateForcing sets the forcing term for the next iteration from the new
+// norm of F using Choice 2 of Eisenstat and Walker with their safeguard.
+func (nk *NewtonKrylov) updateForcing(norm float64) {
+	if nk.Forcing != 0 {
+		nk.eta = nk.Forcing
+		return
+	}
+	const (
+		gamma  = 0.9
+		maxEta = 0.9
+	)
+	ratio := norm / nk.oldNorm
+	eta := gamma * ratio * ratio
+	if safe := gamma * nk.eta * nk.eta; safe > 0.1 {
+		eta = math.Max(eta, safe)
+	}
+	nk.eta = math.Min(eta, maxEta)
+	nk.oldNorm = norm
+}
+
+// jacVec stores in dst the product of the Jacobian at the current location
+// with v.
+func (nk *NewtonKrylov) jacVec(s *state, dst, v []float64) {
+	if nk.jac != nil {
+		mat.NewVecDense(s.n, dst).MulVec(nk.jac, mat.NewVecDense(s.n, v))
+		return
+	}
+	vnorm := floats.Norm(v, 2)
+	if vnorm == 0 {
+		for i := range dst {
+			dst[i] = 0
+		}
+		return
+	}
+	eps := math.Sqrt(dlamchE) * math.Max(1, floats.Norm(s.x, 2)) / vnorm
+	floats.AddScaledTo(nk.xe, s.x, eps, v)
+	s.eval(nk.fe, nk.xe)
+	floats.SubTo(dst, nk.fe, s.f)
+	floats.Scale(1/eps, dst)
+}
+
+// gmres stores in nk.d the approximate solution of J d = -F computed by GMRES
+// from the initial guess d = 0.
+func (nk *NewtonKrylov) gmres(s *state) {
+	for i := range nk.d {
+		nk.d[i] = 0
+	}
+	beta := s.norm
+	tol := nk.eta * beta
+	floats.ScaleTo(nk.v[0], -1/beta, s.f)
+	for i := range nk.g {
+		nk.g[i] = 0
+	}
+	nk.g[0] = beta
+
+	var k int
+	for j := 0; j < nk.m; j++ {
+		k = j + 1
+		w := nk.v[j+1]
+		nk.jacVec(s, w, nk.v[j])
+		// Orthogonalize w against the basis by the modified Gram-Schmidt
+		// process.
+		h := nk.h[j]
+		for i := 0; i <= j; i++ {
+			h[i] = floats.Dot(w, nk.v[i])
+			floats.AddScaled(w, -h[i], nk.v[i])
+		}
+		h[j+1] = floats.Norm(w, 2)
+
+		// Apply the previous rotations to the new column and compute the
+		// rotation that eliminates its subdiagonal element.
+		for i := 0; i < j; i++ {
+			h[i], h[i+1] = nk.cs[i]*h[i]+nk.sn[i]*h[i+1], -nk.sn[i]*h[i]+nk.cs[i]*h[i+1]
+		}
+		r := math.Hypot(h[j], h[j+1])
+		if r == 0 {
+			// The Jacobian is singular on the Krylov subspace.
+			k = j
+			break
+		}
+		nk.cs[j], nk.sn[j] = h[j]/r, h[j+1]/r
+		hNext := h[j+1]
+		h[j], h[j+1] = r, 0
+		nk.g[j], nk.g[j+1] = nk.cs[j]*nk.g[j], -nk.sn[j]*nk.g[j]
+
+		if math.Abs(nk.g[j+1]) <= tol || hNext == 0 {
+			break
+		}
+		floats.Scale(1/hNext, w)
+	}
+
+	// Solve the triangular system R y = g and form d = V y.
+	for i := k - 1; i >= 0; i-- {
+		sum := nk.g[i]
+		for j := i + 1; j < k; j++ {
+			sum -= nk.h[j][i] * nk.y[j]
+		}
+		nk.y[i] = sum / nk.h[i][i]
+	}
+	for i := 0; i < k; i++ {
+		floats.AddScaled(nk.d, nk.y[i], nk.v[i])
+	}
+}
+
+// minEta is the smallest forcing term used after a failed line search.
+const minEta = 1e-10
+
+// dlamchE is the machine epsilon.
+const dlamchE = 1.0 / (1 << 53)
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
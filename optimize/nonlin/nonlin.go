@@ -0,0 +1,279 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nonlin
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+var (
+	// ErrMissingFunc signifies that the Problem does not provide the
+	// function F.
+	ErrMissingFunc = errors.New("nonlin: problem does not provide Func function")
+
+	// ErrNonFinite signifies that the function value at the initial
+	// location has an infinite or NaN element.
+	ErrNonFinite = errors.New("nonlin: initial function value is not finite")
+
+	// errNoDecrease is returned when a Method cannot find a step that
+	// decreases the norm of the function value.
+	errNoDecrease = errors.New("nonlin: no decrease of the function norm along the step")
+)
+
+// Problem describes a system of nonlinear equations F(x) = 0 with as many
+// equations as unknowns.
+type Problem struct {
+	// Func evaluates F at x and stores the result in dst, which has the
+	// same length as x. Func must not modify x.
+	Func func(dst, x []float64)
+
+	// Jacobian evaluates the Jacobian of F at x and stores it in dst,
+	// which is a square matrix with the dimension of x. Jacobian must not
+	// modify x. If Jacobian is nil, it is approximated by finite
+	// differences of Func.
+	Jacobian func(dst *mat.Dense, x []float64)
+}
+
+// Settings controls the termination of Solve.
+type Settings struct {
+	// FunctionThreshold stops the iterations with FunctionThreshold status
+	// if the infinity norm of F is less than or equal to this value. If
+	// FunctionThreshold is 0, a default value of 1e-10 is used.
+	FunctionThreshold float64
+
+	// StepTolerance stops the iterations with StepConvergence status if
+	// the infinity norm of the step is less than or equal to StepTolerance
+	// times the infinity norm of the location plus StepTolerance. If
+	// StepTolerance is 0, a default value of 1e-12 is used, and if it is
+	// negative the check is disabled.
+	StepTolerance float64
+
+	// MajorIterations is the maximum number of major iterations. If it is
+	// zero, this setting has no effect.
+	MajorIterations int
+
+	// FuncEvaluations is the maximum number of evaluations of Func,
+	// including those for approximating the Jacobian and its products with
+	// vectors. The limit is checked at major iterations. If it is zero,
+	// this setting has no effect.
+	FuncEvaluations int
+
+	// Runtime is the maximum runtime, checked at major iterations. If it
+	// is zero, this setting has no effect.
+	Runtime time.Duration
+}
+
+// Result represents the solution of a system of nonlinear equations.
+type Result struct {
+	// X is the final location.
+	X []float64
+	// F holds the function value at X.
+	F []float64
+
+	Status optimize.Status
+
+	// Stats holds the statistics of the iterations. The evaluations of
+	// the Jacobian are counted in GradEvaluations.
+	optimize.Stats
+}
+
+// Method is a method for solving systems of nonlinear equations. Method is
+// implemented by Broyden and NewtonKrylov.
+type Method interface {
+	// init initializes the method at the initial location of s.
+	init(s *state)
+	// iterate moves s to a location with a smaller norm of F. If iterate
+	// cannot find such a location, it returns a status other than
+	// NotTerminated.
+	iterate(s *state) (optimize.Status, error)
+}
+
+// Solve finds a root of the function of the Problem starting at the location
+// x using the given Method. If settings is nil, the default settings are used,
+// and if method is nil, Broyden is used. The initial location x is not
+// modified.
+//
+// The methods are globalized by a backtracking line search on the norm of F,
+// so they may converge from starting locations far from a root. They may
+// however stop at a local minimum of the norm of F that is not a root, in
+// which case Solve returns a Status other than FunctionThreshold.
+func Solve(p Problem, x []float64, settings *Settings, method Method) (*Result, error) {
+	startTime := time.Now()
+	if p.Func == nil {
+		return nil, ErrMissingFunc
+	}
+	if len(x) == 0 {
+		return nil, optimize.ErrZeroDimensional
+	}
+	if settings == nil {
+		settings = &Settings{}
+	}
+	if method == nil {
+		method = &Broyden{}
+	}
+
+	s := newState(&p, x, settings)
+	s.norm = s.eval(s.f, s.x)
+	if math.IsInf(s.norm, 0) || math.IsNaN(s.norm) {
+		return nil, ErrNonFinite
+	}
+	method.init(s)
+
+	status := optimize.NotTerminated
+	var err error
+	for {
+		if floats.Norm(s.f, math.Inf(1)) <= s.funcThresh {
+			status = optimize.FunctionThreshold
+			break
+		}
+		if settings.MajorIterations > 0 && s.stats.MajorIterations >= settings.MajorIterations {
+			status = optimize.IterationLimit
+			break
+		}
+		if settings.FuncEvaluations > 0 && s.stats.FuncEvaluations >= settings.FuncEvaluations {
+			status = optimize.FunctionEvaluationLimit
+			break
+		}
+		if settings.Runtime > 0 && time.Since(startTime) >= settings.Runtime {
+			status = optimize.RuntimeLimit
+			break
+		}
+		status, err = method.iterate(s)
+		if status != optimize.NotTerminated || err != nil {
+			break
+		}
+		s.stats.MajorIterations++
+		if s.isSmall(s.step) {
+			status = optimize.StepConvergence
+			break
+		}
+	}
+	if status == optimize.NotTerminated {
+		status = optimize.Failure
+	}
+	s.stats.Runtime = time.Since(startTime)
+
+	return &Result{
+		X:      s.x,
+		F:      s.f,
+		Status: status,
+		Stats:  s.stats,
+	}, err
+}
+
+// state is the state of the solution of a system shared by Solve and the
+// Method.
+type state struct {
+	p *Problem
+	n int
+
+	x    []float64
+	f    []float64
+	norm float64   // |F(x)|
+	step []float64 // Most recent accepted step
+
+	// Location and function value of the most recent trial step.
+	trialX    []float64
+	trialF    []float64
+	trialNorm float64
+
+	funcThresh float64
+	stepTol    float64
+
+	stats optimize.Stats
+}
+
+func newState(p *Problem, x []float64, settings *Settings) *state {
+	n := len(x)
+	s := &state{
+		p:      p,
+		n:      n,
+		x:      make([]float64, n),
+		f:      make([]float64, n),
+		step:   make([]float64, n),
+		trialX: make([]float64, n),
+		trialF: make([]float64, n),
+
+		funcThresh: settings.FunctionThreshold,
+		stepTol:    settings.StepTolerance,
+	}
+	copy(s.x, x)
+	if s.funcThresh == 0 {
+		s.funcThresh = 1e-10
+	}
+	switch {
+	case s.stepTol == 0:
+		s.stepTol = 1e-12
+	case s.stepTol < 0:
+		s.stepTol = math.Inf(-1)
+	}
+	return s
+}
+
+// eval evaluates F at x, stores it in dst and returns its norm.
+func (s *state) eval(dst, x []float64) float64 {
+	s.stats.FuncEvaluations++
+	s.p.Func(dst, x)
+	return floats.Norm(dst, 2)
+}
+
+// jacobian evaluates the Jacobian at the current location and stores it in
+// dst.
+func (s *state) jacobian(dst *mat.Dense) {
+	if s.p.Jacobian != nil {
+		s.stats.GradEvaluations++
+		s.p.Jacobian(dst, s.x)
+		return
+	}
+	fd.Jacobian(dst, func(y, x []float64) {
+		s.stats.FuncEvaluations++
+		s.p.Func(y, x)
+	}, s.x, &fd.JacobianSettings{
+		OriginValue: s.f,
+	})
+}
+
+// isSmall returns whether the step dx from the current location is small
+// according to the step tolerance.
+func (s *state) isSmall(dx []float64) bool {
+	return floats.Norm(dx, math.Inf(1)) <= s.stepTol*(floats.Norm(s.x, math.Inf(1))+s.stepTol)
+}
+
+// search performs a backtracking line search along the direction d from the
+// current location and stores the accepted step in the trial location. A step
+// t d is accepted if
+//  |F(x + t d)| ≤ (1 - 1e-4 t) |F(x)|.
+// search returns false if the step became small without being accepted.
+func (s *state) search(d []float64) bool {
+	const (
+		decrease = 1e-4
+		contract = 0.5
+	)
+	for t := 1.0; ; t *= contract {
+		floats.ScaleTo(s.step, t, d)
+		if s.isSmall(s.step) || t < 1e-10 {
+			return false
+		}
+		floats.AddTo(s.trialX, s.x, s.step)
+		s.trialNorm = s.eval(s.trialF, s.trialX)
+		if s.trialNorm <= (1-decrease*t)*s.norm {
+			return true
+		}
+	}
+}
+
+// accept moves the location to the trial location found by search.
+func (s *state) accept() {
+	copy(s.x, s.trialX)
+	copy(s.f, s.trialF)
+	s.norm = s.trialNorm
+}
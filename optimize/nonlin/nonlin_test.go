@@ -0,0 +1,179 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nonlin
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+type solveTest struct {
+	name string
+	p    Problem
+	x    []float64
+}
+
+func rosenbrock(dst, x []float64) {
+	dst[0] = 10 * (x[1] - x[0]*x[0])
+	dst[1] = 1 - x[0]
+}
+
+func rosenbrockJac(dst *mat.Dense, x []float64) {
+	dst.Set(0, 0, -20*x[0])
+	dst.Set(0, 1, 10)
+	dst.Set(1, 0, -1)
+	dst.Set(1, 1, 0)
+}
+
+func powellBadlyScaled(dst, x []float64) {
+	dst[0] = 1e4*x[0]*x[1] - 1
+	dst[1] = math.Exp(-x[0]) + math.Exp(-x[1]) - 1.0001
+}
+
+func powellBadlyScaledJac(dst *mat.Dense, x []float64) {
+	dst.Set(0, 0, 1e4*x[1])
+	dst.Set(0, 1, 1e4*x[0])
+	dst.Set(1, 0, -math.Exp(-x[0]))
+	dst.Set(1, 1, -math.Exp(-x[1]))
+}
+
+func broydenTridiagonal(dst, x []float64) {
+	n := len(x)
+	for i := range x {
+		dst[i] = (3-2*x[i])*x[i] + 1
+		if i > 0 {
+			dst[i] -= x[i-1]
+		}
+		if i < n-1 {
+			dst[i] -= 2 * x[i+1]
+		}
+	}
+}
+
+func constant(n int, v float64) []float64 {
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = v
+	}
+	return x
+}
+
+var solveTests = []solveTest{
+	{
+		name: "Rosenbrock",
+		p:    Problem{Func: rosenbrock},
+		x:    []float64{-1.2, 1},
+	},
+	{
+		name: "RosenbrockJacobian",
+		p:    Problem{Func: rosenbrock, Jacobian: rosenbrockJac},
+		x:    []float64{-1.2, 1},
+	},
+	{
+		name: "PowellBadlyScaled",
+		p:    Problem{Func: powellBadlyScaled, Jacobian: powellBadlyScaledJac},
+		x:    []float64{0, 1},
+	},
+	{
+		name: "BroydenTridiagonal",
+		p:    Problem{Func: broydenTridiagonal},
+		x:    constant(100, -1),
+	},
+}
+
+func TestSolve(t *testing.T) {
+	t.Parallel()
+	for _, method := range []struct {
+		name string
+		new  func() Method
+	}{
+		{name: "Broyden", new: func() Method { return &Broyden{} }},
+		{name: "NewtonKrylov", new: func() Method { return &NewtonKrylov{} }},
+		{name: "NewtonKrylovForcing", new: func() Method { return &NewtonKrylov{Forcing: 1e-4} }},
+	} {
+		for _, test := range solveTests {
+			name := fmt.Sprintf("%s/%s", method.name, test.name)
+			res, err := Solve(test.p, test.x, nil, method.new())
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", name, err)
+				continue
+			}
+			if res.Status != optimize.FunctionThreshold {
+				t.Errorf("%s: unexpected status: got %v, want %v", name, res.Status, optimize.FunctionThreshold)
+			}
+			f := make([]float64, len(test.x))
+			test.p.Func(f, res.X)
+			if !floats.Equal(f, res.F) {
+				t.Errorf("%s: function value mismatch at the result", name)
+			}
+			if norm := floats.Norm(f, math.Inf(1)); norm > 1e-10 {
+				t.Errorf("%s: function norm too large: %v", name, norm)
+			}
+			if res.MajorIterations == 0 {
+				t.Errorf("%s: no major iterations", name)
+			}
+			if test.p.Jacobian == nil && res.GradEvaluations != 0 {
+				t.Errorf("%s: unexpected Jacobian evaluations: %d", name, res.GradEvaluations)
+			}
+		}
+	}
+}
+
+func TestSolveLimits(t *testing.T) {
+	t.Parallel()
+	p := Problem{Func: broydenTridiagonal}
+	x := constant(100, -1)
+	res, err := Solve(p, x, &Settings{MajorIterations: 2}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != optimize.IterationLimit || res.MajorIterations != 2 {
+		t.Errorf("unexpected iteration limit result: status %v, iterations %d", res.Status, res.MajorIterations)
+	}
+	if x[0] != -1 {
+		t.Errorf("initial location modified")
+	}
+}
+
+func TestSolveNoRoot(t *testing.T) {
+	t.Parallel()
+	// The norm of F has a local minimum at x = 0 where F is not zero.
+	p := Problem{Func: func(dst, x []float64) {
+		dst[0] = x[0]*x[0] + 1
+	}}
+	for _, method := range []Method{&Broyden{}, &NewtonKrylov{}} {
+		res, err := Solve(p, []float64{1}, nil, method)
+		if res == nil {
+			t.Fatalf("%T: unexpected nil result with error %v", method, err)
+		}
+		if res.Status == optimize.FunctionThreshold {
+			t.Errorf("%T: unexpected convergence to a root", method)
+		}
+	}
+}
+
+func TestSolveErrors(t *testing.T) {
+	t.Parallel()
+	_, err := Solve(Problem{}, []float64{1}, nil, nil)
+	if err != ErrMissingFunc {
+		t.Errorf("unexpected error for missing Func: got %v, want %v", err, ErrMissingFunc)
+	}
+	_, err = Solve(Problem{Func: rosenbrock}, nil, nil, nil)
+	if err != optimize.ErrZeroDimensional {
+		t.Errorf("unexpected error for empty location: got %v, want %v", err, optimize.ErrZeroDimensional)
+	}
+	p := Problem{Func: func(dst, x []float64) {
+		dst[0] = math.Log(x[0])
+	}}
+	_, err = Solve(p, []float64{0}, nil, nil)
+	if err != ErrNonFinite {
+		t.Errorf("unexpected error for non-finite value: got %v, want %v", err, ErrNonFinite)
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// NonlinearConstraints represents the nonlinear equality and inequality
+// constraints
+//  c_E(x) = 0
+//  c_I(x) ≤ 0
+// on the variables x of a problem of dimension n, where c_E has NumEq
+// components and c_I has NumIneq components.
+type NonlinearConstraints struct {
+	// NumEq is the number of equality constraints.
+	NumEq int
+	// Eq evaluates the equality constraints at x and stores the result
+	// in dst, which has length NumEq. Eq must not modify x.
+	Eq func(dst, x []float64)
+	// EqJac evaluates the Jacobian of the equality constraints at x and
+	// stores the result in jac, which is a NumEq×n matrix. EqJac must not
+	// modify x.
+	EqJac func(jac *mat.Dense, x []float64)
+
+	// NumIneq is the number of inequality constraints.
+	NumIneq int
+	// Ineq evaluates the inequality constraints at x and stores the result
+	// in dst, which has length NumIneq. Ineq must not modify x.
+	Ineq func(dst, x []float64)
+	// IneqJac evaluates the Jacobian of the inequality constraints at x and
+	// stores the result in jac, which is a NumIneq×n matrix. IneqJac must
+	// not modify x.
+	IneqJac func(jac *mat.Dense, x []float64)
+}
+
+// checkNonlinear panics if the nonlinear constraints c are not valid.
+func checkNonlinear(c *NonlinearConstraints) {
+	if c == nil {
+		return
+	}
+	if c.NumEq < 0 || c.NumIneq < 0 {
+		panic("optimize: negative number of nonlinear constraints")
+	}
+	if c.NumEq > 0 && (c.Eq == nil || c.EqJac == nil) {
+		panic("optimize: missing nonlinear equality constraint function")
+	}
+	if c.NumIneq > 0 && (c.Ineq == nil || c.IneqJac == nil) {
+		panic("optimize: missing nonlinear inequality constraint function")
+	}
+}
+
+// Multipliers holds the Lagrange multipliers of the constraints of a Problem.
+// The Lagrangian of the Problem is
+//  L(x) = f(x) + LinearEqᵀ (A x - B) + LinearIneqᵀ (G x - H)
+//       + Eqᵀ c_E(x) + Ineqᵀ c_I(x) + Lowerᵀ (Min - x) + Upperᵀ (x - Max)
+// where the multipliers of the inequality constraints and of the bounds are
+// non-negative and vanish for inactive constraints. A field is nil if the
+// Problem does not have the corresponding constraints. The multipliers of
+// infinite bounds are zero.
+type Multipliers struct {
+	LinearEq   []float64
+	LinearIneq []float64
+	Eq         []float64
+	Ineq       []float64
+	Lower      []float64
+	Upper      []float64
+}
+
+// constraintSet evaluates the bound, linear and nonlinear constraints of
+// a Problem as the equality constraints
+//  c_E(x) = 0
+// and the inequality constraints
+//  c_I(x) ≤ 0.
+// The equality constraints are the rows of the linear equality constraints
+// followed by the nonlinear equality constraints. The inequality constraints
+// are the rows of the linear inequality constraints, the nonlinear inequality
+// constraints, and the finite lower and upper bounds.
+type constraintSet struct {
+	bounds    []Bound
+	linear    *LinearConstraints
+	nonlinear *NonlinearConstraints
+
+	dim                  int
+	linEq, linIneq       [][]float64
+	linEqRhs, linIneqRhs []float64
+	lower, upper         []int // Indices of variables with finite bounds
+	mE, mI               int   // Number of equality and inequality constraints
+
+	jE  [][]float64 // Jacobian of the equality constraints
+	jI  [][]float64 // Jacobian of the inequality constraints
+	jac mat.Dense   // Storage for the nonlinear Jacobians
+}
+
+// init initializes the constraints for a problem of dimension dim.
+func (c *constraintSet) init(dim int) {
+	c.dim = dim
+	c.linEq, c.linEqRhs = nil, nil
+	c.linIneq, c.linIneqRhs = nil, nil
+	if c.linear != nil {
+		c.linEq, c.linEqRhs = c.linear.equality()
+		c.linIneq, c.linIneqRhs = c.linear.inequality()
+	}
+	if c.bounds != nil && len(c.bounds) != dim {
+		panic("optimize: bounds length mismatch")
+	}
+	c.lower, c.upper = c.lower[:0], c.upper[:0]
+	for i, b := range c.bounds {
+		if !math.IsInf(b.Min, -1) {
+			c.lower = append(c.lower, i)
+		}
+		if !math.IsInf(b.Max, 1) {
+			c.upper = append(c.upper, i)
+		}
+	}
+	c.mE = len(c.linEq)
+	c.mI = len(c.linIneq) + len(c.lower) + len(c.upper)
+	if c.nonlinear != nil {
+		c.mE += c.nonlinear.NumEq
+		c.mI += c.nonlinear.NumIneq
+	}
+
+	// The Jacobians of the linear constraints and of the bounds are
+	// constant.
+	c.jE = make([][]float64, c.mE)
+	copy(c.jE, c.linEq)
+	for i := len(c.linEq); i < c.mE; i++ {
+		c.jE[i] = make([]float64, dim)
+	}
+	c.jI = make([][]float64, c.mI)
+	copy(c.jI, c.linIneq)
+	k := len(c.linIneq)
+	if c.nonlinear != nil {
+		for i := 0; i < c.nonlinear.NumIneq; i++ {
+			c.jI[k] = make([]float64, dim)
+			k++
+		}
+	}
+	for _, i := range c.lower {
+		c.jI[k] = make([]float64, dim)
+		c.jI[k][i] = -1
+		k++
+	}
+	for _, i := range c.upper {
+		c.jI[k] = make([]float64, dim)
+		c.jI[k][i] = 1
+		k++
+	}
+}
+
+// eval evaluates the equality and inequality constraints at x and stores
+// the results in cE and cI.
+func (c *constraintSet) eval(cE, cI, x []float64) {
+	for i, row := range c.linEq {
+		cE[i] = floats.Dot(row, x) - c.linEqRhs[i]
+	}
+	for i, row := range c.linIneq {
+		cI[i] = floats.Dot(row, x) - c.linIneqRhs[i]
+	}
+	k := len(c.linIneq)
+	if c.nonlinear != nil {
+		if c.nonlinear.NumEq > 0 {
+			c.nonlinear.Eq(cE[len(c.linEq):], x)
+		}
+		if c.nonlinear.NumIneq > 0 {
+			c.nonlinear.Ineq(cI[k:k+c.nonlinear.NumIneq], x)
+		}
+		k += c.nonlinear.NumIneq
+	}
+	for _, i := range c.lower {
+		cI[k] = c.bounds[i].Min - x[i]
+		k++
+	}
+	for _, i := range c.upper {
+		cI[k] = x[i] - c.bounds[i].Max
+		k++
+	}
+}
+
+// jacobians evaluates the Jacobians of the nonlinear constraints at x and
+// stores them in jE and jI.
+func (c *constraintSet) jacobians(x []float64) {
+	if c.nonlinear == nil {
+		return
+	}
+	if n := c.nonlinear.NumEq; n > 0 {
+		c.jac.Reset()
+		c.jac.ReuseAs(n, c.dim)
+		c.nonlinear.EqJac(&c.jac, x)
+		for i := 0; i < n; i++ {
+			mat.Row(c.jE[len(c.linEq)+i], i, &c.jac)
+		}
+	}
+	if n := c.nonlinear.NumIneq; n > 0 {
+		c.jac.Reset()
+		c.jac.ReuseAs(n, c.dim)
+		c.nonlinear.IneqJac(&c.jac, x)
+		for i := 0; i < n; i++ {
+			mat.Row(c.jI[len(c.linIneq)+i], i, &c.jac)
+		}
+	}
+}
+
+// maxViolation returns the largest violation of the constraints at x.
+func (c *constraintSet) maxViolation(x []float64) float64 {
+	cE := make([]float64, c.mE)
+	cI := make([]float64, c.mI)
+	c.eval(cE, cI, x)
+	var v float64
+	for _, e := range cE {
+		v = math.Max(v, math.Abs(e))
+	}
+	for _, e := range cI {
+		v = math.Max(v, e)
+	}
+	return v
+}
+
+// multipliers returns the Multipliers corresponding to the multipliers lamE
+// and lamI of the equality and inequality constraints.
+func (c *constraintSet) multipliers(lamE, lamI []float64) *Multipliers {
+	var m Multipliers
+	var nEq, nIneq int
+	if c.linear != nil {
+		nEq = len(c.linEq)
+		nIneq = len(c.linIneq)
+		if c.linear.A != nil {
+			m.LinearEq = append([]float64{}, lamE[:nEq]...)
+		}
+		if c.linear.G != nil {
+			m.LinearIneq = append([]float64{}, lamI[:nIneq]...)
+		}
+	}
+	if c.nonlinear != nil {
+		if c.nonlinear.NumEq > 0 {
+			m.Eq = append([]float64{}, lamE[nEq:]...)
+		}
+		if c.nonlinear.NumIneq > 0 {
+			m.Ineq = append([]float64{}, lamI[nIneq:nIneq+c.nonlinear.NumIneq]...)
+			nIneq += c.nonlinear.NumIneq
+		}
+	}
+	if c.bounds != nil {
+		m.Lower = make([]float64, c.dim)
+		for _, i := range c.lower {
+			m.Lower[i] = lamI[nIneq]
+			nIneq++
+		}
+		m.Upper = make([]float64, c.dim)
+		for _, i := range c.upper {
+			m.Upper[i] = lamI[nIneq]
+			nIneq++
+		}
+	}
+	return &m
+}
+
+// complementarity returns the largest product of the magnitudes of an
+// inequality constraint at x and of its multiplier in m. complementarity
+// returns NaN if the multipliers do not match the constraints.
+func (c *constraintSet) complementarity(x []float64, m *Multipliers) float64 {
+	lamI := append([]float64{}, m.LinearIneq...)
+	lamI = append(lamI, m.Ineq...)
+	if len(m.Lower) == c.dim && len(m.Upper) == c.dim {
+		for _, i := range c.lower {
+			lamI = append(lamI, m.Lower[i])
+		}
+		for _, i := range c.upper {
+			lamI = append(lamI, m.Upper[i])
+		}
+	}
+	if len(lamI) != c.mI {
+		return math.NaN()
+	}
+	cE := make([]float64, c.mE)
+	cI := make([]float64, c.mI)
+	c.eval(cE, cI, x)
+	var comp float64
+	for i, v := range cI {
+		comp = math.Max(comp, math.Abs(v*lamI[i]))
+	}
+	return comp
+}
@@ -0,0 +1,134 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import "gonum.org/v1/gonum/floats"
+
+const defaultNonmonotoneMemory = 10
+
+var (
+	_ Linesearcher  = (*NonmonotoneBacktracking)(nil)
+	_ NoiseTolerant = (*NonmonotoneBacktracking)(nil)
+)
+
+// NonmonotoneBacktracking is a Linesearcher that uses backtracking to find a
+// point that satisfies the nonmonotone Armijo condition of Grippo, Lampariello
+// and Lucidi,
+//  f(x_k + step*d_k) <= max_{0<=j<M} f(x_{k-j}) + DecreaseFactor * step * ∇f_kᵀ d_k,
+// in which the sufficient decrease is measured relative to the largest of the
+// function values at the starts of the last M line searches instead of the
+// most recent one. If the condition has not been met, the step size is
+// decreased by ContractionFactor. See
+//  Grippo, L., Lampariello, F., Lucidi, S.: A nonmonotone line search
+//  technique for Newton's method. SIAM J. Numer. Anal. 23(4) (1986), 707-716
+// for more information.
+//
+// Allowing the function value to increase occasionally lets methods such as
+// GradientDescent and Newton take longer steps through narrow curved valleys.
+// Like Backtracking, NonmonotoneBacktracking only evaluates the function at
+// the trial steps and is not appropriate for optimizers that require the Wolfe
+// conditions to be met.
+//
+// The history of function values is cleared when a new optimization is
+// started by a LinesearchMethod. Both DecreaseFactor and ContractionFactor
+// must be between zero and one, and NonmonotoneBacktracking will panic
+// otherwise. If either DecreaseFactor or ContractionFactor are zero, it will
+// be set to a reasonable default.
+type NonmonotoneBacktracking struct {
+	// Memory is the number M of the last function values used in the
+	// decrease condition. If Memory is 0, it will be defaulted to 10, and
+	// a Memory of 1 gives the same steps as Backtracking.
+	Memory            int
+	DecreaseFactor    float64 // Constant factor in the sufficient decrease condition.
+	ContractionFactor float64 // Step size multiplier at each iteration (step *= ContractionFactor).
+
+	fHist []float64 // Function values at the starts of the last line searches
+	count int       // Number of line searches since the history was cleared
+
+	stepSize float64
+	refF     float64 // Reference function value of the current line search
+	initG    float64
+
+	noise float64 // Estimate of the noise in the function values
+
+	lastOp Operation
+}
+
+func (b *NonmonotoneBacktracking) Init(f, g float64, step float64) Operation {
+	if step <= 0 {
+		panic("nonmonotone: bad step size")
+	}
+	if g >= 0 {
+		panic("nonmonotone: initial derivative is non-negative")
+	}
+
+	if b.Memory == 0 {
+		b.Memory = defaultNonmonotoneMemory
+	}
+	if b.Memory < 0 {
+		panic("nonmonotone: negative Memory")
+	}
+	if b.ContractionFactor == 0 {
+		b.ContractionFactor = defaultBacktrackingContraction
+	}
+	if b.DecreaseFactor == 0 {
+		b.DecreaseFactor = defaultBacktrackingDecrease
+	}
+	if b.ContractionFactor <= 0 || b.ContractionFactor >= 1 {
+		panic("nonmonotone: ContractionFactor must be between 0 and 1")
+	}
+	if b.DecreaseFactor <= 0 || b.DecreaseFactor >= 1 {
+		panic("nonmonotone: DecreaseFactor must be between 0 and 1")
+	}
+
+	if len(b.fHist) != b.Memory {
+		b.fHist = resize(b.fHist, b.Memory)
+		b.count = 0
+	}
+	b.fHist[b.count%b.Memory] = f
+	b.count++
+	n := b.count
+	if n > b.Memory {
+		n = b.Memory
+	}
+	b.refF = floats.Max(b.fHist[:n])
+
+	b.stepSize = step
+	b.initG = g
+
+	b.lastOp = FuncEvaluation
+	return b.lastOp
+}
+
+func (b *NonmonotoneBacktracking) Iterate(f, _ float64) (Operation, float64, error) {
+	if b.lastOp != FuncEvaluation {
+		panic("nonmonotone: Init has not been called")
+	}
+
+	if ArmijoConditionMet(f, b.refF+2*b.noise, b.initG, b.stepSize, b.DecreaseFactor) {
+		b.lastOp = MajorIteration
+		return b.lastOp, b.stepSize, nil
+	}
+	b.stepSize *= b.ContractionFactor
+	if b.stepSize < minimumBacktrackingStepSize {
+		b.lastOp = NoOperation
+		return b.lastOp, b.stepSize, ErrLinesearcherFailure
+	}
+	b.lastOp = FuncEvaluation
+	return b.lastOp, b.stepSize, nil
+}
+
+// SetFunctionNoise sets the estimate of the noise in the function values. The
+// sufficient decrease condition is relaxed by twice the noise. It implements
+// the NoiseTolerant interface.
+func (b *NonmonotoneBacktracking) SetFunctionNoise(noise float64) {
+	b.noise = noise
+}
+
+// resetHistory clears the function values of the previous line searches. It
+// implements the historyLinesearcher interface.
+func (b *NonmonotoneBacktracking) resetHistory() {
+	b.count = 0
+}
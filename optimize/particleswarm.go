@@ -0,0 +1,386 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ Method   = (*ParticleSwarm)(nil)
+	_ Statuser = (*ParticleSwarm)(nil)
+	_ Bounder  = (*ParticleSwarm)(nil)
+)
+
+// PSOVariant is the velocity update rule of ParticleSwarm.
+type PSOVariant int
+
+const (
+	// PSOInertia updates the velocity v of a particle at x as
+	//  v = w v + c_1 r_1 (p - x) + c_2 r_2 (g - x)
+	// where w is the inertia weight, p is the best location of the
+	// particle, g is the best location of its neighborhood, and r_1 and
+	// r_2 are uniform random numbers in [0, 1) drawn for every variable.
+	PSOInertia PSOVariant = iota
+	// PSOConstriction updates the velocity v of a particle at x as
+	//  v = χ (v + c_1 r_1 (p - x) + c_2 r_2 (g - x))
+	// where the constriction factor is
+	//  χ = 2 / |2 - φ - sqrt(φ² - 4φ)|
+	// with φ = c_1 + c_2 > 4.
+	PSOConstriction
+)
+
+// PSOTopology is the neighborhood structure of the swarm of ParticleSwarm.
+type PSOTopology int
+
+const (
+	// PSOGlobal connects every particle to the whole swarm.
+	PSOGlobal PSOTopology = iota
+	// PSORing connects every particle to itself and to its two neighbors
+	// on a ring.
+	PSORing
+)
+
+// ParticleSwarm is a global optimizer that moves a swarm of particles
+// through the search space. Every particle is attracted by the best location
+// it has visited and by the best location visited by the particles in its
+// neighborhood, as determined by the Topology. See
+//  Kennedy, J., Eberhart, R.: Particle swarm optimization. Proceedings of
+//  the IEEE International Conference on Neural Networks (1995), 1942-1948
+//  Clerc, M., Kennedy, J.: The particle swarm - explosion, stability, and
+//  convergence in a multidimensional complex space. IEEE Transactions on
+//  Evolutionary Computation 6 (2002), 58-73
+// for more information.
+//
+// The initial swarm contains the initial location, and the other particles
+// are drawn uniformly from the bounds of the Problem. The variables without
+// finite bounds are drawn from a normal distribution centered at the initial
+// location. Particles that leave the bounds are projected onto the bounds and
+// the corresponding components of their velocities are set to zero.
+//
+// Each major iteration of ParticleSwarm corresponds to a move of the swarm,
+// and the location of the major iteration is the best location found so far.
+// The particles are evaluated concurrently if Settings.Concurrent is larger
+// than one.
+type ParticleSwarm struct {
+	// Variant is the velocity update rule. The default is PSOInertia.
+	Variant PSOVariant
+	// Topology is the neighborhood structure. The default is PSOGlobal.
+	Topology PSOTopology
+	// Particles is the number of particles of the swarm. If Particles is 0,
+	// a default value of 10 + 2 sqrt(dim) is used. Particles cannot be
+	// negative, or ParticleSwarm will panic.
+	Particles int
+	// Inertia is the inertia weight w of PSOInertia. If Inertia is 0,
+	// a default value of 0.7298 is used.
+	Inertia float64
+	// Cognitive and Social are the acceleration coefficients c_1 and c_2.
+	// If they are 0, default values of 1.49618 for PSOInertia and 2.05 for
+	// PSOConstriction are used.
+	Cognitive, Social float64
+	// InitStdDev is the standard deviation of the variables of the initial
+	// swarm that do not have finite bounds. If InitStdDev is 0, a default
+	// value of 1 is used.
+	InitStdDev float64
+	// StopSpread sets the threshold for stopping the optimization with
+	// MethodConverge status when the difference between the largest and the
+	// smallest function value at the best locations of the particles is less
+	// than StopSpread. If StopSpread is 0, a default value of 1e-12 is used,
+	// and if it is NaN, the stopping criterion is not used.
+	StopSpread float64
+	// Src allows a random number generator to be supplied for generating
+	// the swarm and its moves. If Src is nil the generator in
+	// golang.org/x/exp/rand is used.
+	Src rand.Source
+
+	bounds []Bound
+
+	status         Status
+	rnd            *rand.Rand
+	dim            int
+	n              int
+	w, c1, c2, chi float64
+
+	xs *mat.Dense // Locations of the particles
+	fs []float64  // Function values at xs
+	vs *mat.Dense // Velocities of the particles
+	ps *mat.Dense // Best locations of the particles
+	fp []float64  // Function values at ps
+
+	bestX []float64
+	bestF float64
+
+	// Synchronization.
+	sentIdx     int
+	receivedIdx int
+	operation   chan<- Task
+}
+
+func (pso *ParticleSwarm) Status() (Status, error) {
+	return pso.status, nil
+}
+
+func (*ParticleSwarm) Uses(has Available) (uses Available, err error) {
+	return has.boundedFunction()
+}
+
+// SetBounds sets the bounds on the variables. It implements the Bounder
+// interface.
+func (pso *ParticleSwarm) SetBounds(bounds []Bound) {
+	pso.bounds = bounds
+}
+
+func (pso *ParticleSwarm) Init(dim, tasks int) int {
+	if dim <= 0 {
+		panic(nonpositiveDimension)
+	}
+	if tasks < 0 {
+		panic(negativeTasks)
+	}
+	if pso.Topology != PSOGlobal && pso.Topology != PSORing {
+		panic("particle swarm: unknown topology")
+	}
+	pso.dim = dim
+	pso.n = pso.Particles
+	if pso.n == 0 {
+		pso.n = 10 + int(2*math.Sqrt(float64(dim)))
+	} else if pso.n < 0 {
+		panic("particle swarm: negative number of particles")
+	}
+
+	pso.c1, pso.c2 = pso.Cognitive, pso.Social
+	switch pso.Variant {
+	default:
+		panic("particle swarm: unknown variant")
+	case PSOInertia:
+		pso.w = pso.Inertia
+		if pso.w == 0 {
+			pso.w = 0.7298
+		}
+		if pso.c1 == 0 {
+			pso.c1 = 1.49618
+		}
+		if pso.c2 == 0 {
+			pso.c2 = 1.49618
+		}
+		pso.chi = 1
+	case PSOConstriction:
+		if pso.c1 == 0 {
+			pso.c1 = 2.05
+		}
+		if pso.c2 == 0 {
+			pso.c2 = 2.05
+		}
+		phi := pso.c1 + pso.c2
+		if phi <= 4 {
+			panic("particle swarm: sum of acceleration coefficients not larger than 4")
+		}
+		pso.chi = 2 / math.Abs(2-phi-math.Sqrt(phi*phi-4*phi))
+		pso.w = 1
+	}
+	if pso.Src != nil {
+		pso.rnd = rand.New(pso.Src)
+	} else {
+		pso.rnd = rand.New(rand.NewSource(rand.Uint64()))
+	}
+
+	pso.status = NotTerminated
+	pso.xs = mat.NewDense(pso.n, dim, nil)
+	pso.vs = mat.NewDense(pso.n, dim, nil)
+	pso.ps = mat.NewDense(pso.n, dim, nil)
+	pso.fs = resize(pso.fs, pso.n)
+	pso.fp = resize(pso.fp, pso.n)
+	for i := range pso.fp {
+		pso.fp[i] = math.NaN()
+	}
+	pso.bestX = resize(pso.bestX, dim)
+	pso.bestF = math.Inf(1)
+
+	pso.sentIdx = 0
+	pso.receivedIdx = 0
+	pso.operation = nil
+	return min(tasks, pso.n)
+}
+
+// initSwarm generates the initial locations and velocities of the swarm
+// around the location x.
+func (pso *ParticleSwarm) initSwarm(x []float64) {
+	randomPopulation(pso.xs, x, pso.bounds, pso.InitStdDev, pso.rnd)
+	// The initial velocities are half the distances to other random
+	// locations.
+	randomPopulation(pso.vs, x, pso.bounds, pso.InitStdDev, pso.rnd)
+	pso.vs.Sub(pso.vs, pso.xs)
+	pso.vs.Scale(0.5, pso.vs)
+	for i := range pso.fs {
+		pso.fs[i] = math.NaN()
+	}
+}
+
+// neighborhoodBest returns the index of the particle with the best location
+// in the neighborhood of particle i.
+func (pso *ParticleSwarm) neighborhoodBest(i, global int) int {
+	if pso.Topology == PSOGlobal {
+		return global
+	}
+	best := i
+	for _, j := range []int{(i + pso.n - 1) % pso.n, (i + 1) % pso.n} {
+		if pso.fp[j] < pso.fp[best] || math.IsNaN(pso.fp[best]) {
+			best = j
+		}
+	}
+	return best
+}
+
+// move updates the velocities and the locations of the particles.
+func (pso *ParticleSwarm) move() {
+	global := bestIndex(pso.fp)
+	if global == -1 {
+		global = 0
+	}
+	for i := 0; i < pso.n; i++ {
+		x := pso.xs.RawRowView(i)
+		v := pso.vs.RawRowView(i)
+		p := pso.ps.RawRowView(i)
+		g := pso.ps.RawRowView(pso.neighborhoodBest(i, global))
+		for j := range x {
+			r1 := pso.rnd.Float64()
+			r2 := pso.rnd.Float64()
+			v[j] = pso.chi * (pso.w*v[j] + pso.c1*r1*(p[j]-x[j]) + pso.c2*r2*(g[j]-x[j]))
+			x[j] += v[j]
+			if pso.bounds != nil {
+				b := pso.bounds[j]
+				if x[j] < b.Min || x[j] > b.Max {
+					x[j] = math.Max(b.Min, math.Min(x[j], b.Max))
+					v[j] = 0
+				}
+			}
+		}
+	}
+	for i := range pso.fs {
+		pso.fs[i] = math.NaN()
+	}
+}
+
+// updateBest updates the best locations of the particles and the best
+// location found so far, and stores the latter in task.
+func (pso *ParticleSwarm) updateBest(task Task) Task {
+	for i, f := range pso.fs {
+		if f < pso.fp[i] || (math.IsNaN(pso.fp[i]) && !math.IsNaN(f)) {
+			pso.fp[i] = f
+			pso.ps.SetRow(i, pso.xs.RawRowView(i))
+		}
+	}
+	if best := bestIndex(pso.fp); best != -1 && pso.fp[best] < pso.bestF {
+		pso.bestF = pso.fp[best]
+		copy(pso.bestX, pso.ps.RawRowView(best))
+	}
+	task.F = pso.bestF
+	copy(task.X, pso.bestX)
+	return task
+}
+
+// converged returns whether the spread of the function values at the best
+// locations of the particles is below the threshold.
+func (pso *ParticleSwarm) converged() bool {
+	spread := pso.StopSpread
+	switch {
+	case math.IsNaN(spread):
+		return false
+	case spread == 0:
+		spread = 1e-12
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, f := range pso.fp {
+		if math.IsNaN(f) {
+			return false
+		}
+		lo = math.Min(lo, f)
+		hi = math.Max(hi, f)
+	}
+	return hi-lo < spread
+}
+
+func (pso *ParticleSwarm) sendInitTasks(tasks []Task) {
+	for i, task := range tasks {
+		pso.sendTask(i, task)
+	}
+	pso.sentIdx = len(tasks)
+}
+
+// sendTask sends the evaluation of the particle with index idx.
+func (pso *ParticleSwarm) sendTask(idx int, task Task) {
+	task.ID = idx
+	task.Op = FuncEvaluation
+	copy(task.X, pso.xs.RawRowView(idx))
+	pso.operation <- task
+}
+
+func (pso *ParticleSwarm) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	pso.operation = operation
+	pso.initSwarm(tasks[0].X)
+	pso.sendInitTasks(tasks)
+
+Loop:
+	for {
+		task := <-result
+		switch task.Op {
+		default:
+			panic("unknown operation")
+		case PostIteration:
+			break Loop
+		case MajorIteration:
+			pso.move()
+			pso.sendInitTasks(tasks)
+		case FuncEvaluation:
+			pso.receivedIdx++
+			pso.fs[task.ID] = task.F
+			switch {
+			case pso.sentIdx < pso.n:
+				pso.sendTask(pso.sentIdx, task)
+				pso.sentIdx++
+			case pso.receivedIdx < pso.n:
+				// Wait until the whole swarm has been evaluated.
+				continue Loop
+			default:
+				pso.sentIdx = 0
+				pso.receivedIdx = 0
+				task = pso.updateBest(task)
+				task.ID = -1
+				task.Op = MajorIteration
+				if pso.converged() {
+					pso.status = MethodConverge
+					task.Op = MethodDone
+				}
+				operation <- task
+			}
+		}
+	}
+
+	// PostIteration was sent. Collect the remaining evaluations and report
+	// the best location evaluated since the last major iteration if it
+	// improves on the best location.
+	for task := range result {
+		switch task.Op {
+		default:
+			panic("unknown operation")
+		case MajorIteration:
+		case FuncEvaluation:
+			pso.fs[task.ID] = task.F
+		}
+	}
+	if best := bestIndex(pso.fs); best != -1 && pso.fs[best] < pso.bestF {
+		task := tasks[0]
+		task.F = pso.fs[best]
+		copy(task.X, pso.xs.RawRowView(best))
+		task.ID = -1
+		task.Op = MajorIteration
+		operation <- task
+	}
+	close(operation)
+}
@@ -0,0 +1,120 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestParticleSwarm(t *testing.T) {
+	t.Parallel()
+	rastrigin := Problem{
+		Func:   functions.Rastrigin{}.Func,
+		Bounds: []Bound{{-5.12, 5.12}, {-5.12, 5.12}},
+	}
+	rosenbrock := Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+	}
+	for _, test := range []struct {
+		name   string
+		p      Problem
+		method *ParticleSwarm
+		x      []float64
+		want   []float64
+	}{
+		{
+			name:   "rastrigin inertia global",
+			p:      rastrigin,
+			method: &ParticleSwarm{Particles: 30},
+			x:      []float64{3.5, -4},
+			want:   []float64{0, 0},
+		},
+		{
+			name:   "rastrigin constriction ring",
+			p:      rastrigin,
+			method: &ParticleSwarm{Variant: PSOConstriction, Topology: PSORing, Particles: 30},
+			x:      []float64{3.5, -4},
+			want:   []float64{0, 0},
+		},
+		{
+			name:   "rosenbrock inertia ring",
+			p:      rosenbrock,
+			method: &ParticleSwarm{Topology: PSORing},
+			x:      []float64{-1.2, 1},
+			want:   []float64{1, 1},
+		},
+		{
+			name:   "rosenbrock constriction global",
+			p:      rosenbrock,
+			method: &ParticleSwarm{Variant: PSOConstriction},
+			x:      []float64{-1.2, 1},
+			want:   []float64{1, 1},
+		},
+	} {
+		var results []*Result
+		for _, concurrent := range []int{0, 4} {
+			test.method.Src = rand.NewSource(1)
+			rec := &opRecorder{}
+			settings := &Settings{
+				Concurrent: concurrent,
+				Converger:  NeverTerminate{},
+				Recorder:   rec,
+			}
+			result, err := Minimize(test.p, test.x, settings, test.method)
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.name, err)
+				continue
+			}
+			if result.Status != MethodConverge {
+				t.Errorf("%s: unexpected status: got %v, want %v", test.name, result.Status, MethodConverge)
+			}
+			if !floats.EqualApprox(result.X, test.want, 1e-4) {
+				t.Errorf("%s: unexpected optimum: got %v, want %v", test.name, result.X, test.want)
+			}
+			if err := checkBounds(test.p.Bounds, result.X); err != nil {
+				t.Errorf("%s: optimum outside bounds: %v", test.name, result.X)
+			}
+			if rec.ops[MajorIteration] != result.MajorIterations {
+				t.Errorf("%s: recorded major iterations mismatch: got %d, want %d", test.name, rec.ops[MajorIteration], result.MajorIterations)
+			}
+			if n := test.method.n; result.FuncEvaluations != n*(result.MajorIterations+1) {
+				t.Errorf("%s: unexpected number of evaluations: got %d, want %d", test.name, result.FuncEvaluations, n*(result.MajorIterations+1))
+			}
+			results = append(results, result)
+		}
+		// The moves do not depend on the order of the evaluations.
+		if len(results) == 2 && (results[0].F != results[1].F || results[0].MajorIterations != results[1].MajorIterations) {
+			t.Errorf("%s: concurrent run differs: got f=%v after %d iterations, want f=%v after %d iterations",
+				test.name, results[1].F, results[1].MajorIterations, results[0].F, results[0].MajorIterations)
+		}
+	}
+}
+
+func TestParticleSwarmPanics(t *testing.T) {
+	t.Parallel()
+	p := Problem{Func: functions.ExtendedRosenbrock{}.Func}
+	for _, method := range []*ParticleSwarm{
+		{Particles: -1},
+		{Variant: PSOConstriction, Cognitive: 1, Social: 1},
+		{Variant: 2},
+		{Topology: 2},
+	} {
+		if !panics(func() { Minimize(p, []float64{0, 0}, nil, method) }) {
+			t.Errorf("no panic for invalid settings %+v", method)
+		}
+	}
+
+	pso := &ParticleSwarm{Variant: PSOConstriction}
+	pso.Init(2, 1)
+	if math.Abs(pso.chi-0.7298437881) > 1e-9 {
+		t.Errorf("unexpected constriction factor: got %v, want 0.7298437881", pso.chi)
+	}
+}
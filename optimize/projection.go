@@ -0,0 +1,87 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// BoxProjection returns the projection onto the box described by bounds for
+// use as Problem.Project.
+func BoxProjection(bounds []Bound) func(x []float64) {
+	for _, b := range bounds {
+		if math.IsNaN(b.Min) || math.IsNaN(b.Max) || b.Min > b.Max {
+			panic("optimize: invalid bound")
+		}
+	}
+	bounds = append([]Bound(nil), bounds...)
+	return func(x []float64) {
+		if len(x) != len(bounds) {
+			panic("optimize: bounds length mismatch")
+		}
+		project(x, bounds)
+	}
+}
+
+// BallProjection returns the projection onto the Euclidean ball with the
+// given center and radius for use as Problem.Project. If center is nil, the
+// ball is centered at the origin.
+func BallProjection(center []float64, radius float64) func(x []float64) {
+	if !(radius >= 0) {
+		panic("optimize: negative radius")
+	}
+	center = append([]float64(nil), center...)
+	return func(x []float64) {
+		if center == nil {
+			if norm := floats.Norm(x, 2); norm > radius {
+				floats.Scale(radius/norm, x)
+			}
+			return
+		}
+		if len(x) != len(center) {
+			panic("optimize: center length mismatch")
+		}
+		norm := floats.Distance(x, center, 2)
+		if norm <= radius {
+			return
+		}
+		for i, c := range center {
+			x[i] = c + (x[i]-c)*radius/norm
+		}
+	}
+}
+
+// SimplexProjection returns the projection onto the simplex
+//  {x : x_i ≥ 0, Σ_i x_i = sum}
+// for use as Problem.Project. The projection is computed as described in
+//  Duchi, J., Shalev-Shwartz, S., Singer, Y., Chandra, T.: Efficient
+//  projections onto the l1-ball for learning in high dimensions. Proc. 25th
+//  Int. Conf. Mach. Learn. (2008), 272-279
+// SimplexProjection panics if sum is not positive.
+func SimplexProjection(sum float64) func(x []float64) {
+	if !(sum > 0) {
+		panic("optimize: simplex sum not positive")
+	}
+	return func(x []float64) {
+		u := append([]float64(nil), x...)
+		sort.Sort(sort.Reverse(sort.Float64Slice(u)))
+		// Find the largest number of positive components of the projection
+		// and the corresponding shift of x.
+		var cum, theta float64
+		for j, v := range u {
+			cum += v
+			t := (cum - sum) / float64(j+1)
+			if v-t > 0 {
+				theta = t
+			}
+		}
+		for i, v := range x {
+			x[i] = math.Max(v-theta, 0)
+		}
+	}
+}
@@ -0,0 +1,399 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	errQPInfeasible = errors.New("optimize: quadratic program is infeasible")
+	errQPDependent  = errors.New("optimize: equality constraints are linearly dependent")
+	errQPNotConvex  = errors.New("optimize: quadratic program is not strictly convex")
+)
+
+// qp solves the strictly convex quadratic program
+//  minimize ½ xᵀ G x + aᵀ x
+//  subject to E x + e = 0
+//             C x + c ≤ 0
+// using the dual active-set method described in
+//  Goldfarb, D., Idnani, A.: A numerically stable dual method for solving
+//  strictly convex quadratic programs. Math. Program. 27, 1–33 (1983).
+//
+// The rows of E and C are given by the slices eq and ineq. The solution is
+// stored in x, and the Lagrange multipliers of the equality and inequality
+// constraints are stored in lamE and lamI, so that
+//  G x + a + Eᵀ lamE + Cᵀ lamI = 0
+// with lamI ≥ 0. qp returns errQPInfeasible if the constraints are
+// inconsistent.
+func qp(x, lamE, lamI []float64, g mat.Symmetric, a []float64, eq [][]float64, e []float64, ineq [][]float64, c []float64) error {
+	n := g.Symmetric()
+	me := len(eq)
+	mi := len(ineq)
+
+	var chol mat.Cholesky
+	if !chol.Factorize(g) {
+		return errQPNotConvex
+	}
+	// J = L^{-T} where G = L Lᵀ.
+	var l mat.TriDense
+	chol.LTo(&l)
+	var linv mat.TriDense
+	err := linv.InverseTri(&l)
+	if err != nil {
+		return errQPNotConvex
+	}
+	j := make([]float64, n*n)
+	var c1, c2 float64
+	for i := 0; i < n; i++ {
+		c1 += g.At(i, i)
+		c2 += linv.At(i, i)
+		for k := 0; k < n; k++ {
+			j[i*n+k] = linv.At(k, i)
+		}
+	}
+
+	// The unconstrained minimum.
+	xv := mat.NewVecDense(n, x)
+	err = chol.SolveVecTo(xv, mat.NewVecDense(n, a))
+	if err != nil {
+		return errQPNotConvex
+	}
+	floats.Scale(-1, x)
+
+	s := &qpState{
+		n:     n,
+		me:    me,
+		r:     make([]float64, n*n),
+		rNorm: 1,
+		j:     j,
+		d:     make([]float64, n),
+		z:     make([]float64, n),
+		rv:    make([]float64, me+mi+1),
+		u:     make([]float64, me+mi+1),
+		act:   make([]int, me+mi+1),
+	}
+
+	// Add the equality constraints to the active set. The constraints are
+	// represented in the form nᵀ x + b ≥ 0 (or = 0) of the original paper.
+	np := make([]float64, n)
+	for i := 0; i < me; i++ {
+		copy(np, eq[i])
+		s.computeD(np)
+		s.updateZ()
+		s.updateR()
+		var t2 float64
+		if floats.Dot(s.z, s.z) > dlamchE {
+			t2 = (-floats.Dot(np, x) - e[i]) / floats.Dot(s.z, np)
+		}
+		floats.AddScaled(x, t2, s.z)
+		s.u[s.iq] = t2
+		for k := 0; k < s.iq; k++ {
+			s.u[k] -= t2 * s.rv[k]
+		}
+		s.act[s.iq] = -i - 1
+		if !s.addConstraint() {
+			return errQPDependent
+		}
+	}
+
+	// The inequality constraints -C x - c ≥ 0.
+	slack := make([]float64, mi)
+	iai := make([]int, mi)
+	excl := make([]bool, mi)
+	uOld := make([]float64, me+mi+1)
+	actOld := make([]int, me+mi+1)
+	xOld := make([]float64, n)
+	for i := range iai {
+		iai[i] = i
+	}
+	ineqValue := func(i int) float64 {
+		return -floats.Dot(ineq[i], x) - c[i]
+	}
+
+	const maxIter = 1000
+	for iter := 0; ; iter++ {
+		if iter > maxIter*(me+mi+1) {
+			return errQPInfeasible
+		}
+		// Step 1: choose a violated constraint.
+		for i := me; i < s.iq; i++ {
+			iai[s.act[i]] = -1
+		}
+		var psi float64
+		for i := range slack {
+			excl[i] = true
+			slack[i] = ineqValue(i)
+			psi += math.Min(0, slack[i])
+		}
+		if math.Abs(psi) <= float64(mi)*dlamchE*c1*c2*100 {
+			break
+		}
+		copy(uOld, s.u[:s.iq])
+		copy(actOld, s.act[:s.iq])
+		copy(xOld, x)
+
+	step2:
+		// Step 2: check for feasibility and determine a new S-pair.
+		ip := -1
+		var ss float64
+		for i, v := range slack {
+			if v < ss && iai[i] != -1 && excl[i] {
+				ss = v
+				ip = i
+			}
+		}
+		if ip < 0 {
+			break
+		}
+		for i, v := range ineq[ip] {
+			np[i] = -v
+		}
+		s.u[s.iq] = 0
+		s.act[s.iq] = ip
+
+		for {
+			// Step 2a: determine the step direction.
+			s.computeD(np)
+			s.updateZ()
+			s.updateR()
+
+			// Step 2b: compute the step length. t1 is the partial
+			// step length in the dual space and t2 is the full step
+			// length in the primal space.
+			drop := -1
+			t1 := math.Inf(1)
+			for k := me; k < s.iq; k++ {
+				if s.rv[k] > 0 && s.u[k]/s.rv[k] < t1 {
+					t1 = s.u[k] / s.rv[k]
+					drop = s.act[k]
+				}
+			}
+			t2 := math.Inf(1)
+			if floats.Dot(s.z, s.z) > dlamchE {
+				t2 = -slack[ip] / floats.Dot(s.z, np)
+			}
+			t := math.Min(t1, t2)
+
+			// Step 2c: determine the new S-pair and take the step.
+			if math.IsInf(t, 1) {
+				return errQPInfeasible
+			}
+			if math.IsInf(t2, 1) {
+				// Step in the dual space only.
+				for k := 0; k < s.iq; k++ {
+					s.u[k] -= t * s.rv[k]
+				}
+				s.u[s.iq] += t
+				iai[drop] = drop
+				s.deleteConstraint(drop)
+				continue
+			}
+
+			// Step in the primal and dual space.
+			floats.AddScaled(x, t, s.z)
+			for k := 0; k < s.iq; k++ {
+				s.u[k] -= t * s.rv[k]
+			}
+			s.u[s.iq] += t
+			if t == t2 {
+				// Full step, add the constraint to the active set.
+				if !s.addConstraint() {
+					excl[ip] = false
+					s.deleteConstraint(ip)
+					for i := range iai {
+						iai[i] = i
+					}
+					for i := me; i < s.iq; i++ {
+						s.act[i] = actOld[i]
+						s.u[i] = uOld[i]
+						iai[s.act[i]] = -1
+					}
+					copy(x, xOld)
+					goto step2
+				}
+				iai[ip] = -1
+				break
+			}
+			// Partial step, drop the blocking constraint.
+			iai[drop] = drop
+			s.deleteConstraint(drop)
+			slack[ip] = ineqValue(ip)
+		}
+	}
+
+	for i := range lamE {
+		lamE[i] = 0
+	}
+	for i := range lamI {
+		lamI[i] = 0
+	}
+	for k := 0; k < s.iq; k++ {
+		if s.act[k] < 0 {
+			lamE[-s.act[k]-1] = -s.u[k]
+		} else {
+			lamI[s.act[k]] = s.u[k]
+		}
+	}
+	return nil
+}
+
+// qpState holds the factorizations of the active set of qp.
+type qpState struct {
+	n     int
+	me    int
+	iq    int       // Number of active constraints
+	r     []float64 // Upper triangular factor R of the active set, n×n row-major
+	rNorm float64   // Estimate of the norm of R
+	j     []float64 // Orthogonal factor J, n×n row-major
+	d     []float64 // d = Jᵀ np
+	z     []float64 // Step direction in the primal space
+	rv    []float64 // Negative step direction in the dual space
+	u     []float64 // Multipliers of the active set
+	act   []int     // Active set
+}
+
+func (s *qpState) computeD(np []float64) {
+	n := s.n
+	for i := 0; i < n; i++ {
+		var sum float64
+		for k := 0; k < n; k++ {
+			sum += s.j[k*n+i] * np[k]
+		}
+		s.d[i] = sum
+	}
+}
+
+func (s *qpState) updateZ() {
+	n := s.n
+	for i := 0; i < n; i++ {
+		var sum float64
+		for k := s.iq; k < n; k++ {
+			sum += s.j[i*n+k] * s.d[k]
+		}
+		s.z[i] = sum
+	}
+}
+
+func (s *qpState) updateR() {
+	n := s.n
+	for i := s.iq - 1; i >= 0; i-- {
+		var sum float64
+		for k := i + 1; k < s.iq; k++ {
+			sum += s.r[i*n+k] * s.rv[k]
+		}
+		s.rv[i] = (s.d[i] - sum) / s.r[i*n+i]
+	}
+}
+
+// addConstraint updates the factorizations after the addition of
+// a constraint to the active set. It returns false if the constraint is
+// linearly dependent on the active set.
+func (s *qpState) addConstraint() bool {
+	n := s.n
+	for k := n - 1; k > s.iq; k-- {
+		cc := s.d[k-1]
+		ss := s.d[k]
+		h := math.Hypot(cc, ss)
+		if h == 0 {
+			continue
+		}
+		s.d[k] = 0
+		ss /= h
+		cc /= h
+		if cc < 0 {
+			cc = -cc
+			ss = -ss
+			s.d[k-1] = -h
+		} else {
+			s.d[k-1] = h
+		}
+		xny := ss / (1 + cc)
+		for i := 0; i < n; i++ {
+			t1 := s.j[i*n+k-1]
+			t2 := s.j[i*n+k]
+			s.j[i*n+k-1] = t1*cc + t2*ss
+			s.j[i*n+k] = xny*(t1+s.j[i*n+k-1]) - t2
+		}
+	}
+	s.iq++
+	for i := 0; i < s.iq; i++ {
+		s.r[i*n+s.iq-1] = s.d[i]
+	}
+	if math.Abs(s.d[s.iq-1]) <= 10*float64(n)*dlamchE*s.rNorm {
+		return false
+	}
+	s.rNorm = math.Max(s.rNorm, math.Abs(s.d[s.iq-1]))
+	return true
+}
+
+// deleteConstraint removes the inequality constraint l from the active set
+// and updates the factorizations.
+func (s *qpState) deleteConstraint(l int) {
+	n := s.n
+	qq := -1
+	for i := s.me; i < s.iq; i++ {
+		if s.act[i] == l {
+			qq = i
+			break
+		}
+	}
+	if qq < 0 {
+		panic("optimize: constraint not in the active set")
+	}
+	for i := qq; i < s.iq-1; i++ {
+		s.act[i] = s.act[i+1]
+		s.u[i] = s.u[i+1]
+		for k := 0; k < n; k++ {
+			s.r[k*n+i] = s.r[k*n+i+1]
+		}
+	}
+	s.act[s.iq-1] = s.act[s.iq]
+	s.u[s.iq-1] = s.u[s.iq]
+	s.act[s.iq] = 0
+	s.u[s.iq] = 0
+	for k := 0; k < s.iq; k++ {
+		s.r[k*n+s.iq-1] = 0
+	}
+	s.iq--
+	if s.iq == 0 {
+		return
+	}
+	for k := qq; k < s.iq; k++ {
+		cc := s.r[k*n+k]
+		ss := s.r[(k+1)*n+k]
+		h := math.Hypot(cc, ss)
+		if h == 0 {
+			continue
+		}
+		cc /= h
+		ss /= h
+		s.r[(k+1)*n+k] = 0
+		if cc < 0 {
+			s.r[k*n+k] = -h
+			cc = -cc
+			ss = -ss
+		} else {
+			s.r[k*n+k] = h
+		}
+		xny := ss / (1 + cc)
+		for i := k + 1; i < s.iq; i++ {
+			t1 := s.r[k*n+i]
+			t2 := s.r[(k+1)*n+i]
+			s.r[k*n+i] = t1*cc + t2*ss
+			s.r[(k+1)*n+i] = xny*(t1+s.r[k*n+i]) - t2
+		}
+		for i := 0; i < n; i++ {
+			t1 := s.j[i*n+k]
+			t2 := s.j[i*n+k+1]
+			s.j[i*n+k] = t1*cc + t2*ss
+			s.j[i*n+k+1] = xny*(s.j[i*n+k]+t1) - t2
+		}
+	}
+}
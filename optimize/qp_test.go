@@ -0,0 +1,118 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestQP(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name     string
+		g        *mat.SymDense
+		a        []float64
+		eq       [][]float64
+		e        []float64
+		ineq     [][]float64
+		c        []float64
+		want     []float64
+		wantLamE []float64
+		wantLamI []float64
+		wantErr  error
+	}{
+		{
+			name: "unconstrained",
+			g:    mat.NewSymDense(2, []float64{2, 0, 0, 4}),
+			a:    []float64{-2, -4},
+			want: []float64{1, 1},
+		},
+		{
+			name:     "equality",
+			g:        mat.NewSymDense(2, []float64{2, 0, 0, 2}),
+			a:        []float64{-2, -4},
+			eq:       [][]float64{{1, 1}},
+			e:        []float64{-1},
+			want:     []float64{0, 1},
+			wantLamE: []float64{2},
+		},
+		{
+			name:     "active and inactive inequality",
+			g:        mat.NewSymDense(2, []float64{2, 0, 0, 2}),
+			a:        []float64{-2, -4},
+			ineq:     [][]float64{{1, 1}, {-1, 0}},
+			c:        []float64{-1, -5},
+			want:     []float64{0, 1},
+			wantLamI: []float64{2, 0},
+		},
+		{
+			name:     "degenerate inequality",
+			g:        mat.NewSymDense(2, []float64{2, 0, 0, 2}),
+			a:        []float64{0, 0},
+			ineq:     [][]float64{{-1, 0}, {-1, 0}, {0, -1}},
+			c:        []float64{1, 1, 1},
+			want:     []float64{1, 1},
+			wantLamI: nil,
+		},
+		{
+			name:    "infeasible",
+			g:       mat.NewSymDense(1, []float64{1}),
+			a:       []float64{0},
+			ineq:    [][]float64{{1}, {-1}},
+			c:       []float64{1, 1},
+			wantErr: errQPInfeasible,
+		},
+		{
+			name:    "dependent equality",
+			g:       mat.NewSymDense(2, []float64{1, 0, 0, 1}),
+			a:       []float64{0, 0},
+			eq:      [][]float64{{1, 1}, {2, 2}},
+			e:       []float64{1, 2},
+			wantErr: errQPDependent,
+		},
+	} {
+		n := len(test.a)
+		x := make([]float64, n)
+		lamE := make([]float64, len(test.eq))
+		lamI := make([]float64, len(test.ineq))
+		err := qp(x, lamE, lamI, test.g, test.a, test.eq, test.e, test.ineq, test.c)
+		if err != test.wantErr {
+			t.Errorf("%s: unexpected error: got %v, want %v", test.name, err, test.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if !floats.EqualApprox(x, test.want, 1e-12) {
+			t.Errorf("%s: unexpected solution: got %v, want %v", test.name, x, test.want)
+		}
+		if test.wantLamE != nil && !floats.EqualApprox(lamE, test.wantLamE, 1e-12) {
+			t.Errorf("%s: unexpected equality multipliers: got %v, want %v", test.name, lamE, test.wantLamE)
+		}
+		if test.wantLamI != nil && !floats.EqualApprox(lamI, test.wantLamI, 1e-12) {
+			t.Errorf("%s: unexpected inequality multipliers: got %v, want %v", test.name, lamI, test.wantLamI)
+		}
+
+		// Check the stationarity of the Lagrangian.
+		var r mat.VecDense
+		r.MulVec(test.g, mat.NewVecDense(n, x))
+		floats.Add(r.RawVector().Data, test.a)
+		for i, row := range test.eq {
+			floats.AddScaled(r.RawVector().Data, lamE[i], row)
+		}
+		for i, row := range test.ineq {
+			if lamI[i] < 0 {
+				t.Errorf("%s: negative inequality multiplier: %v", test.name, lamI)
+			}
+			floats.AddScaled(r.RawVector().Data, lamI[i], row)
+		}
+		if !floats.EqualApprox(r.RawVector().Data, make([]float64, n), 1e-12) {
+			t.Errorf("%s: gradient of Lagrangian not zero: %v", test.name, r.RawVector().Data)
+		}
+	}
+}
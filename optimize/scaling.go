@@ -0,0 +1,361 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// variableScaling is the linear change of variables
+//  x = T z
+// between the variables x of a Problem and the variables z seen by the Method.
+// T is the diagonal matrix of Settings.Scale or Settings.Preconditioner.
+type variableScaling struct {
+	dim   int
+	scale []float64  // Diagonal of T, or nil if T is dense.
+	t     *mat.Dense // T if scale is nil.
+	tInv  *mat.Dense // Inverse of T if scale is nil.
+}
+
+// newVariableScaling returns the change of variables of the settings for a
+// Problem of dimension dim, or nil if the variables are not scaled.
+func newVariableScaling(settings *Settings, p *Problem, dim int) (*variableScaling, error) {
+	if settings.Scale == nil && settings.Preconditioner == nil {
+		return nil, nil
+	}
+	if settings.Scale != nil && settings.Preconditioner != nil {
+		return nil, ErrSetting{Field: "Preconditioner", Reason: "cannot be combined with Scale"}
+	}
+	field := "Scale"
+	if settings.Preconditioner != nil {
+		field = "Preconditioner"
+	}
+	if p.Project != nil {
+		return nil, ErrSetting{Field: field, Reason: "cannot be used with Problem.Project"}
+	}
+	s := &variableScaling{dim: dim}
+	if settings.Scale != nil {
+		if len(settings.Scale) != dim {
+			return nil, ErrSetting{Field: "Scale", Reason: "length does not match the dimension of the problem"}
+		}
+		s.scale = make([]float64, dim)
+		copy(s.scale, settings.Scale)
+		return s, nil
+	}
+	if p.Bounds != nil {
+		return nil, ErrSetting{Field: "Preconditioner", Reason: "cannot be used with Problem.Bounds"}
+	}
+	r, c := settings.Preconditioner.Dims()
+	if r != dim || c != dim {
+		return nil, ErrSetting{Field: "Preconditioner", Reason: "dimensions do not match the dimension of the problem"}
+	}
+	s.t = mat.DenseCopyOf(settings.Preconditioner)
+	s.tInv = mat.NewDense(dim, dim, nil)
+	if err := s.tInv.Inverse(s.t); err != nil {
+		return nil, ErrSetting{Field: "Preconditioner", Reason: "matrix is singular"}
+	}
+	return s, nil
+}
+
+// toUser stores in dst the variables x = T z.
+func (s *variableScaling) toUser(dst, z []float64) {
+	if s.scale != nil {
+		for i, v := range z {
+			dst[i] = s.scale[i] * v
+		}
+		return
+	}
+	mulVec(dst, s.t, false, z)
+}
+
+// toMethod stores in dst the variables z = T⁻¹ x.
+func (s *variableScaling) toMethod(dst, x []float64) {
+	if s.scale != nil {
+		for i, v := range x {
+			dst[i] = v / s.scale[i]
+		}
+		return
+	}
+	mulVec(dst, s.tInv, false, x)
+}
+
+// gradToMethod stores in dst the gradient Tᵀ g with respect to z of a function
+// with the gradient g with respect to x.
+func (s *variableScaling) gradToMethod(dst, g []float64) {
+	if s.scale != nil {
+		for i, v := range g {
+			dst[i] = s.scale[i] * v
+		}
+		return
+	}
+	mulVec(dst, s.t, true, g)
+}
+
+// gradToUser stores in dst the gradient T⁻ᵀ g with respect to x of a function
+// with the gradient g with respect to z.
+func (s *variableScaling) gradToUser(dst, g []float64) {
+	if s.scale != nil {
+		for i, v := range g {
+			dst[i] = v / s.scale[i]
+		}
+		return
+	}
+	mulVec(dst, s.tInv, true, g)
+}
+
+// hessToMethod stores in dst the Hessian Tᵀ H T with respect to z of a
+// function with the Hessian H with respect to x.
+func (s *variableScaling) hessToMethod(dst, h *mat.SymDense) {
+	if s.scale != nil {
+		scaleSym(dst, h, s.scale, false)
+		return
+	}
+	congruence(dst, h, s.t)
+}
+
+// hessToUser stores in dst the Hessian T⁻ᵀ H T⁻¹ with respect to x of a
+// function with the Hessian H with respect to z.
+func (s *variableScaling) hessToUser(dst, h *mat.SymDense) {
+	if s.scale != nil {
+		scaleSym(dst, h, s.scale, true)
+		return
+	}
+	congruence(dst, h, s.tInv)
+}
+
+// invHessToUser stores in dst the inverse Hessian T H Tᵀ with respect to x of
+// a function with the inverse Hessian H with respect to z.
+func (s *variableScaling) invHessToUser(dst, h *mat.SymDense) {
+	if s.scale != nil {
+		scaleSym(dst, h, s.scale, false)
+		return
+	}
+	congruence(dst, h, mat.DenseCopyOf(s.t.T()))
+}
+
+// jacToMethod returns the Jacobian J T with respect to z of a function with the
+// Jacobian J with respect to x. If the number of columns of J does not match
+// the dimension, J is returned unchanged for the dimension checks to fail.
+func (s *variableScaling) jacToMethod(jac mat.Matrix) mat.Matrix {
+	if _, c := jac.Dims(); c != s.dim {
+		return jac
+	}
+	var dst mat.Dense
+	s.mulJac(&dst, jac)
+	return &dst
+}
+
+// mulJac stores J T in dst.
+func (s *variableScaling) mulJac(dst *mat.Dense, jac mat.Matrix) {
+	if s.scale != nil {
+		dst.Apply(func(_, j int, v float64) float64 {
+			return v * s.scale[j]
+		}, jac)
+		return
+	}
+	dst.Mul(jac, s.t)
+}
+
+// problem returns the Problem p with respect to the variables z.
+func (s *variableScaling) problem(p Problem) Problem {
+	dim := s.dim
+	q := Problem{
+		Status: p.Status,
+	}
+	if p.Func != nil {
+		q.Func = func(z []float64) float64 {
+			x := make([]float64, dim)
+			s.toUser(x, z)
+			return p.Func(x)
+		}
+	}
+	if p.Grad != nil {
+		q.Grad = func(grad, z []float64) {
+			x := make([]float64, dim)
+			s.toUser(x, z)
+			g := make([]float64, dim)
+			p.Grad(g, x)
+			s.gradToMethod(grad, g)
+		}
+	}
+	if p.Hess != nil {
+		q.Hess = func(hess *mat.SymDense, z []float64) {
+			x := make([]float64, dim)
+			s.toUser(x, z)
+			h := mat.NewSymDense(dim, nil)
+			p.Hess(h, x)
+			s.hessToMethod(hess, h)
+		}
+	}
+	if p.HessVec != nil {
+		q.HessVec = func(dst, z, v []float64) {
+			x := make([]float64, dim)
+			s.toUser(x, z)
+			tv := make([]float64, dim)
+			s.toUser(tv, v)
+			hv := make([]float64, dim)
+			p.HessVec(hv, x, tv)
+			s.gradToMethod(dst, hv)
+		}
+	}
+	if p.Bounds != nil {
+		// Bounds are only allowed with diagonal scaling.
+		q.Bounds = make([]Bound, len(p.Bounds))
+		for i, b := range p.Bounds {
+			q.Bounds[i] = b
+			if i < dim {
+				q.Bounds[i] = Bound{Min: b.Min / s.scale[i], Max: b.Max / s.scale[i]}
+			}
+		}
+	}
+	if l := p.Linear; l != nil {
+		q.Linear = &LinearConstraints{B: l.B, H: l.H}
+		if l.A != nil {
+			q.Linear.A = s.jacToMethod(l.A)
+		}
+		if l.G != nil {
+			q.Linear.G = s.jacToMethod(l.G)
+		}
+	}
+	if c := p.Nonlinear; c != nil {
+		q.Nonlinear = &NonlinearConstraints{
+			NumEq:   c.NumEq,
+			NumIneq: c.NumIneq,
+		}
+		q.Nonlinear.Eq, q.Nonlinear.EqJac = s.constraint(c.Eq, c.EqJac, c.NumEq)
+		q.Nonlinear.Ineq, q.Nonlinear.IneqJac = s.constraint(c.Ineq, c.IneqJac, c.NumIneq)
+	}
+	return q
+}
+
+// constraint returns the nonlinear constraint function and its Jacobian with
+// respect to z.
+func (s *variableScaling) constraint(f func(dst, x []float64), jac func(jac *mat.Dense, x []float64), n int) (func(dst, z []float64), func(jac *mat.Dense, z []float64)) {
+	if f == nil {
+		return nil, nil
+	}
+	dim := s.dim
+	fz := func(dst, z []float64) {
+		x := make([]float64, dim)
+		s.toUser(x, z)
+		f(dst, x)
+	}
+	if jac == nil {
+		return fz, nil
+	}
+	jz := func(dst *mat.Dense, z []float64) {
+		x := make([]float64, dim)
+		s.toUser(x, z)
+		j := mat.NewDense(n, dim, nil)
+		jac(j, x)
+		s.mulJac(dst, j)
+	}
+	return fz, jz
+}
+
+// initValues returns the known values at the initial location with respect
+// to z.
+func (s *variableScaling) initValues(iv *Location) *Location {
+	if iv == nil {
+		return nil
+	}
+	loc := &Location{F: iv.F}
+	if iv.Gradient != nil {
+		loc.Gradient = make([]float64, len(iv.Gradient))
+		s.gradToMethod(loc.Gradient, iv.Gradient)
+	}
+	if iv.Hessian != nil {
+		loc.Hessian = mat.NewSymDense(iv.Hessian.Symmetric(), nil)
+		s.hessToMethod(loc.Hessian, iv.Hessian)
+	}
+	return loc
+}
+
+// locationToUser stores in dst the location src with respect to x.
+func (s *variableScaling) locationToUser(dst, src *Location) {
+	dst.X = resize(dst.X, len(src.X))
+	s.toUser(dst.X, src.X)
+	dst.F = src.F
+	if src.Gradient == nil {
+		dst.Gradient = nil
+	} else {
+		dst.Gradient = resize(dst.Gradient, len(src.Gradient))
+		s.gradToUser(dst.Gradient, src.Gradient)
+	}
+	if src.Hessian == nil {
+		dst.Hessian = nil
+	} else {
+		n := src.Hessian.Symmetric()
+		if dst.Hessian == nil || dst.Hessian.Symmetric() != n {
+			dst.Hessian = mat.NewSymDense(n, nil)
+		}
+		s.hessToUser(dst.Hessian, src.Hessian)
+	}
+}
+
+// multipliersToUser transforms the multipliers of the bounds in place to the
+// bounds with respect to x.
+func (s *variableScaling) multipliersToUser(mult *Multipliers) {
+	if mult == nil || s.scale == nil {
+		return
+	}
+	for _, m := range [][]float64{mult.Lower, mult.Upper} {
+		for i := range m {
+			m[i] /= s.scale[i]
+		}
+	}
+}
+
+// scaledRecorder is a Recorder that records the locations of an optimization
+// in scaled variables with respect to the variables of the Problem.
+type scaledRecorder struct {
+	Recorder
+	scaling *variableScaling
+	loc     Location
+}
+
+func (r *scaledRecorder) Record(loc *Location, op Operation, stats *Stats) error {
+	r.scaling.locationToUser(&r.loc, loc)
+	return r.Recorder.Record(&r.loc, op, stats)
+}
+
+// mulVec stores in dst the product of a or aᵀ with x.
+func mulVec(dst []float64, a *mat.Dense, trans bool, x []float64) {
+	d := mat.NewVecDense(len(dst), dst)
+	if trans {
+		d.MulVec(a.T(), mat.NewVecDense(len(x), x))
+		return
+	}
+	d.MulVec(a, mat.NewVecDense(len(x), x))
+}
+
+// scaleSym stores in dst the matrix D h D, or D⁻¹ h D⁻¹ if inv is true, where D
+// is the diagonal matrix with the elements of scale.
+func scaleSym(dst, h *mat.SymDense, scale []float64, inv bool) {
+	n := h.Symmetric()
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			d := scale[i] * scale[j]
+			if inv {
+				dst.SetSym(i, j, h.At(i, j)/d)
+			} else {
+				dst.SetSym(i, j, h.At(i, j)*d)
+			}
+		}
+	}
+}
+
+// congruence stores in dst the matrix tᵀ h t.
+func congruence(dst, h *mat.SymDense, t *mat.Dense) {
+	var ht, r mat.Dense
+	ht.Mul(h, t)
+	r.Mul(t.T(), &ht)
+	n := h.Symmetric()
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			dst.SetSym(i, j, (r.At(i, j)+r.At(j, i))/2)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// badlyScaledQuadratic is the function
+//  f(x) = Σ_i ((x_i - c_i) / s_i)^2,
+// whose minimizer c has components of very different magnitudes.
+type badlyScaledQuadratic struct {
+	c, s []float64
+}
+
+func (q badlyScaledQuadratic) Func(x []float64) float64 {
+	var f float64
+	for i, v := range x {
+		d := (v - q.c[i]) / q.s[i]
+		f += d * d
+	}
+	return f
+}
+
+func (q badlyScaledQuadratic) Grad(grad, x []float64) {
+	for i, v := range x {
+		grad[i] = 2 * (v - q.c[i]) / (q.s[i] * q.s[i])
+	}
+}
+
+func (q badlyScaledQuadratic) Hess(hess *mat.SymDense, x []float64) {
+	for i := range x {
+		for j := i; j < len(x); j++ {
+			hess.SetSym(i, j, 0)
+		}
+		hess.SetSym(i, i, 2/(q.s[i]*q.s[i]))
+	}
+}
+
+// xRecorder records the locations of the major iterations.
+type xRecorder struct {
+	xs [][]float64
+}
+
+func (*xRecorder) Init() error { return nil }
+
+func (r *xRecorder) Record(loc *Location, op Operation, _ *Stats) error {
+	if op == MajorIteration {
+		r.xs = append(r.xs, append([]float64(nil), loc.X...))
+	}
+	return nil
+}
+
+func TestScale(t *testing.T) {
+	t.Parallel()
+	s := []float64{1e-3, 1, 1e3}
+	q := badlyScaledQuadratic{c: []float64{2e-3, -1, 5e3}, s: s}
+	p := Problem{Func: q.Func, Grad: q.Grad, Hess: q.Hess}
+	initX := []float64{0, 0, 0}
+
+	// Without scaling, gradient descent barely moves the large components.
+	res, err := Minimize(p, initX, &Settings{MajorIterations: 100}, &GradientDescent{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if floats.EqualApprox(res.X, q.c, 1e-3) {
+		t.Errorf("unexpected convergence without scaling")
+	}
+
+	tmat := mat.NewDiagDense(len(s), s)
+	for _, test := range []struct {
+		name     string
+		settings Settings
+		method   Method
+	}{
+		{"Scale", Settings{Scale: s}, &GradientDescent{}},
+		{"ScaleBFGS", Settings{Scale: s}, &BFGS{}},
+		{"ScaleNewton", Settings{Scale: s}, &Newton{}},
+		{"Preconditioner", Settings{Preconditioner: tmat}, &GradientDescent{}},
+		{"PreconditionerNewton", Settings{Preconditioner: tmat}, &Newton{}},
+	} {
+		rec := &xRecorder{}
+		settings := test.settings
+		settings.Recorder = rec
+		settings.MajorIterations = 100
+		res, err := Minimize(p, initX, &settings, test.method)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if res.Status == IterationLimit {
+			t.Errorf("%s: iteration limit reached", test.name)
+		}
+		if !floats.EqualApprox(res.X, q.c, 1e-6*5e3) {
+			t.Errorf("%s: unexpected minimizer: got %v, want %v", test.name, res.X, q.c)
+		}
+		if want := make([]float64, 3); !floats.EqualApprox(res.Gradient, want, 1e-8) {
+			t.Errorf("%s: gradient not in the variables of the problem: %v", test.name, res.Gradient)
+		}
+		if len(rec.xs) == 0 || !floats.Equal(rec.xs[len(rec.xs)-1], res.X) {
+			t.Errorf("%s: recorded location does not match the result", test.name)
+		}
+	}
+
+	// The bounds are scaled with the variables.
+	bounded := p
+	bounded.Bounds = []Bound{{-1, 1}, {0, 1}, {-1e4, 1e3}}
+	res, err = Minimize(bounded, initX, &Settings{Scale: s}, &LBFGSB{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []float64{2e-3, 0, 1e3}
+	if !floats.EqualApprox(res.X, want, 1e-6) {
+		t.Errorf("unexpected bounded minimizer: got %v, want %v", res.X, want)
+	}
+
+	for _, settings := range []Settings{
+		{Scale: []float64{1, 0, 1}},
+		{Scale: []float64{1, 1}},
+		{Scale: s, Preconditioner: tmat},
+		{Preconditioner: mat.NewDense(3, 3, nil)},
+	} {
+		_, err := Minimize(p, initX, &settings, nil)
+		if _, ok := err.(ErrSetting); !ok {
+			t.Errorf("expected ErrSetting for %+v, got %v", settings, err)
+		}
+	}
+}
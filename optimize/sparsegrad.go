@@ -0,0 +1,84 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import "sync"
+
+// SparseGradient is a gradient stored in sparse form as pairs of indices and
+// values. Index and Value must have the same length, and components of the
+// gradient whose index is not listed in Index are zero. An index may be
+// listed only once.
+type SparseGradient struct {
+	Index []int
+	Value []float64
+}
+
+// Reset sets the number of stored components to zero, retaining the allocated
+// storage.
+func (g *SparseGradient) Reset() {
+	g.Index = g.Index[:0]
+	g.Value = g.Value[:0]
+}
+
+// Append appends the component with the given index and value.
+func (g *SparseGradient) Append(i int, v float64) {
+	g.Index = append(g.Index, i)
+	g.Value = append(g.Value, v)
+}
+
+// DenseTo stores the gradient in dense form into dst. DenseTo panics if the
+// lengths of Index and Value are not equal or if an index is out of range
+// for dst.
+func (g *SparseGradient) DenseTo(dst []float64) {
+	if len(g.Index) != len(g.Value) {
+		panic("optimize: sparse gradient length mismatch")
+	}
+	for i := range dst {
+		dst[i] = 0
+	}
+	for k, i := range g.Index {
+		dst[i] = g.Value[k]
+	}
+}
+
+// sparseGradUser is a Method that can use gradients in sparse form from
+// Location.SparseGradient instead of Location.Gradient.
+type sparseGradUser interface {
+	// usesSparseGrad returns whether the Method, as configured, uses
+	// sparse gradients.
+	usesSparseGrad() bool
+}
+
+// usesSparseGrad returns whether method uses sparse gradients directly.
+func usesSparseGrad(method Method) bool {
+	s, ok := method.(sparseGradUser)
+	return ok && s.usesSparseGrad()
+}
+
+// copySparseGradient copies src into dst, allocating dst if it is nil,
+// and returns dst.
+func copySparseGradient(dst, src *SparseGradient) *SparseGradient {
+	if dst == nil {
+		dst = &SparseGradient{}
+	}
+	dst.Index = append(dst.Index[:0], src.Index...)
+	dst.Value = append(dst.Value[:0], src.Value...)
+	return dst
+}
+
+// denseGrad returns a function with the signature of Problem.Grad that
+// evaluates the gradient using sparseGrad and expands it to the dense form.
+func denseGrad(sparseGrad func(grad *SparseGradient, x []float64)) func(grad, x []float64) {
+	pool := sync.Pool{
+		New: func() interface{} { return &SparseGradient{} },
+	}
+	return func(grad, x []float64) {
+		g := pool.Get().(*SparseGradient)
+		g.Reset()
+		sparseGrad(g, x)
+		g.DenseTo(grad)
+		pool.Put(g)
+	}
+}
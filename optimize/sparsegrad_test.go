@@ -0,0 +1,135 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestSparseGradient(t *testing.T) {
+	t.Parallel()
+	g := &SparseGradient{}
+	g.Append(3, 2)
+	g.Append(0, -1)
+	dst := []float64{5, 5, 5, 5, 5}
+	g.DenseTo(dst)
+	want := []float64{-1, 0, 0, 2, 0}
+	if !floats.Equal(dst, want) {
+		t.Errorf("unexpected dense gradient: got %v, want %v", dst, want)
+	}
+	g.Reset()
+	if len(g.Index) != 0 || len(g.Value) != 0 {
+		t.Errorf("sparse gradient not reset")
+	}
+}
+
+func TestMinimizeSparseGrad(t *testing.T) {
+	t.Parallel()
+	f := functions.ExtendedRosenbrock{}
+	dense := make([]float64, 0)
+	p := Problem{
+		Func: f.Func,
+		SparseGrad: func(grad *SparseGradient, x []float64) {
+			dense = resize(dense, len(x))
+			f.Grad(dense, x)
+			for i, v := range dense {
+				if v != 0 {
+					grad.Append(i, v)
+				}
+			}
+		},
+	}
+	x := []float64{-1.2, 1, -1.2, 1}
+	result, err := Minimize(p, x, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.GradEvaluations == 0 {
+		t.Error("sparse gradient not used by the default method")
+	}
+	if !floats.EqualApprox(result.X, []float64{1, 1, 1, 1}, 1e-8) {
+		t.Errorf("minimum not found: got %v", result.X)
+	}
+}
+
+// sparseGradRecorder records whether any evaluated location has a dense
+// gradient.
+type sparseGradRecorder struct {
+	dense  bool
+	sparse int
+}
+
+func (r *sparseGradRecorder) Init() error { return nil }
+
+func (r *sparseGradRecorder) Record(loc *Location, op Operation, _ *Stats) error {
+	if loc.Gradient != nil {
+		r.dense = true
+	}
+	if op == MajorIteration && loc.SparseGradient != nil {
+		r.sparse++
+	}
+	return nil
+}
+
+func TestMinimizeSparseGradDirect(t *testing.T) {
+	t.Parallel()
+	const n = 1000
+	active := []int{3, 250, 999}
+	f := func(x []float64) float64 {
+		var f float64
+		for _, i := range active {
+			d := x[i] - float64(i)
+			f += d * d
+		}
+		return f
+	}
+	grad := func(grad []float64, x []float64) {
+		for i := range grad {
+			grad[i] = 0
+		}
+		for _, i := range active {
+			grad[i] = 2 * (x[i] - float64(i))
+		}
+	}
+	sparseGrad := func(grad *SparseGradient, x []float64) {
+		if len(x) != n {
+			panic("bad length")
+		}
+		for _, i := range active {
+			grad.Append(i, 2*(x[i]-float64(i)))
+		}
+	}
+	for _, test := range []struct {
+		name   string
+		method func() Method
+	}{
+		{"SGD", func() Method { return &SGD{LearningRate: 0.1} }},
+		{"AdaGrad", func() Method { return &AdaGrad{LearningRate: 1} }},
+	} {
+		rec := &sparseGradRecorder{}
+		settings := &Settings{MajorIterations: 50, Recorder: rec}
+		result, err := Minimize(Problem{Func: f, SparseGrad: sparseGrad}, make([]float64, n), settings, test.method())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if rec.dense || result.Gradient != nil {
+			t.Errorf("%s: sparse gradient was densified", test.name)
+		}
+		if rec.sparse == 0 || result.SparseGradient == nil {
+			t.Errorf("%s: sparse gradient not passed to the method", test.name)
+		}
+
+		want, err := Minimize(Problem{Func: f, Grad: grad}, make([]float64, n), &Settings{MajorIterations: 50}, test.method())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if !floats.Equal(result.X, want.X) {
+			t.Errorf("%s: sparse and dense gradients give different results", test.name)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+var (
+	_ Method      = (*SPG)(nil)
+	_ localMethod = (*SPG)(nil)
+	_ Bounder     = (*SPG)(nil)
+	_ Projector   = (*SPG)(nil)
+	_ gradNormer  = (*SPG)(nil)
+)
+
+// SPG implements the spectral projected gradient method for gradient-based
+// minimization over a closed convex feasible set, which is given by the
+// projection of the Problem or by its bounds.
+//
+// At every iteration the search direction is
+//  d = P(x - λ ∇f(x)) - x,
+// where P is the projection onto the feasible set and λ is the spectral step
+// length of Barzilai and Borwein computed from the last step. Since the
+// feasible set is convex, all locations along d up to a unit step are
+// feasible, and a nonmonotone backtracking search along d ensures sufficient
+// decrease relative to the largest of the last function values. See
+//  Birgin, E., Martínez, J., Raydan, M.: Nonmonotone spectral projected
+//  gradient methods on convex sets. SIAM J. Optim. 10(4) (2000), 1196-1211
+// for the details of the method.
+//
+// SPG only needs the projection onto the feasible set, so it is much cheaper
+// per iteration than the general constrained methods if the projection is
+// cheap, as it is for boxes, balls and simplices. If the initial location is
+// not feasible, it is replaced by its projection.
+type SPG struct {
+	// Memory is the number of the last function values used in the
+	// nonmonotone decrease condition. If Memory is 0, it will be defaulted
+	// to 10, and a Memory of 1 gives a monotone search.
+	Memory int
+	// DecreaseFactor is the constant in the sufficient decrease condition
+	// of the backtracking search. It must be between zero and one, and if
+	// DecreaseFactor is 0, it will be defaulted to 1e-4.
+	DecreaseFactor float64
+	// GradStopThreshold sets the threshold for stopping if the norm of the
+	// projected gradient gets too small. If GradStopThreshold is 0 it is
+	// defaulted to 1e-12, and if it is NaN the setting is not used.
+	GradStopThreshold float64
+
+	status Status
+	err    error
+
+	bounds  []Bound
+	project func(x []float64)
+
+	dim      int
+	x        []float64 // Location at the last major iteration
+	f        float64   // Function value at the last major iteration
+	grad     []float64 // Gradient at the last major iteration
+	dir      []float64 // Search direction
+	tmp      []float64
+	lambda   float64   // Spectral step length
+	step     float64   // Current step along dir
+	slope    float64   // Directional derivative along dir
+	iter     int       // Number of backtracking steps in the current search
+	fHist    []float64 // Last Memory function values at major iterations
+	count    int       // Number of accepted major iterations
+	feasible bool      // Indicator that x has been projected onto the feasible set
+
+	next backtrackState // Next action taken in iterateLocal
+}
+
+const (
+	// spgMinLambda and spgMaxLambda are the safeguards of the spectral step
+	// length of SPG.
+	spgMinLambda = 1e-30
+	spgMaxLambda = 1e30
+)
+
+func (s *SPG) Status() (Status, error) {
+	return s.status, s.err
+}
+
+func (*SPG) Uses(has Available) (uses Available, err error) {
+	return has.projectedGradient()
+}
+
+// SetBounds sets the bounds on the variables. It implements the Bounder
+// interface.
+func (s *SPG) SetBounds(bounds []Bound) {
+	s.bounds = bounds
+}
+
+// SetProjection sets the projection onto the feasible set. It implements the
+// Projector interface.
+func (s *SPG) SetProjection(project func(x []float64)) {
+	s.project = project
+}
+
+func (s *SPG) Init(dim, tasks int) int {
+	s.status = NotTerminated
+	s.err = nil
+	return 1
+}
+
+func (s *SPG) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	s.status, s.err = localOptimizer{}.run(s, s.GradStopThreshold, operation, result, tasks)
+	close(operation)
+}
+
+func (s *SPG) initLocal(loc *Location) (Operation, error) {
+	if s.Memory == 0 {
+		s.Memory = 10
+	}
+	if s.Memory < 0 {
+		panic("spg: negative Memory")
+	}
+	if s.DecreaseFactor == 0 {
+		s.DecreaseFactor = 1e-4
+	}
+	if s.DecreaseFactor <= 0 || s.DecreaseFactor >= 1 {
+		panic("spg: DecreaseFactor must be between 0 and 1")
+	}
+	if s.bounds != nil && len(s.bounds) != len(loc.X) {
+		panic("spg: bounds length mismatch")
+	}
+
+	dim := len(loc.X)
+	s.dim = dim
+	s.x = resize(s.x, dim)
+	s.grad = resize(s.grad, dim)
+	s.dir = resize(s.dir, dim)
+	s.tmp = resize(s.tmp, dim)
+	s.fHist = resize(s.fHist, s.Memory)
+	s.count = 0
+
+	copy(s.tmp, loc.X)
+	s.proj(s.tmp)
+	if !floats.Equal(s.tmp, loc.X) {
+		// Evaluate the objective function at the projection of the
+		// infeasible initial location.
+		s.feasible = false
+		copy(loc.X, s.tmp)
+		s.next = backtrackGrad
+		return FuncEvaluation | GradEvaluation, nil
+	}
+	s.feasible = true
+	s.accept(loc)
+	s.initLambda()
+	return s.initSearch(loc)
+}
+
+func (s *SPG) iterateLocal(loc *Location) (Operation, error) {
+	switch s.next {
+	default:
+		panic("spg: unexpected state")
+	case backtrackSearch:
+		if loc.F <= s.maxF()+s.DecreaseFactor*s.step*s.slope {
+			// The trial location is accepted, evaluate the gradient.
+			s.next = backtrackGrad
+			return GradEvaluation, nil
+		}
+		s.iter++
+		if s.iter >= maxBacktracks {
+			return NoOperation, ErrLinesearcherFailure
+		}
+		// Minimizer of the quadratic interpolating the function value and
+		// the directional derivative at x and the trial value, safeguarded
+		// to lie between a tenth and nine tenths of the current step.
+		step := -0.5 * s.step * s.step * s.slope / (loc.F - s.f - s.step*s.slope)
+		switch {
+		case math.IsNaN(step):
+			step = s.step / 2
+		case step < 0.1*s.step:
+			step = 0.1 * s.step
+		case step > 0.9*s.step:
+			step = 0.9 * s.step
+		}
+		s.step = step
+		floats.AddScaledTo(loc.X, s.x, s.step, s.dir)
+		if floats.Equal(loc.X, s.x) {
+			return NoOperation, ErrNoProgress
+		}
+		return FuncEvaluation, nil
+	case backtrackGrad:
+		if s.feasible {
+			s.update(loc)
+		} else {
+			s.feasible = true
+			s.accept(loc)
+			s.initLambda()
+		}
+		s.next = backtrackMajor
+		return MajorIteration, nil
+	case backtrackMajor:
+		return s.initSearch(loc)
+	}
+}
+
+// gradNorm returns the infinity norm of the projected gradient at loc.
+func (s *SPG) gradNorm(loc *Location) float64 {
+	// gradNorm is also called at the initial location before initLocal.
+	s.tmp = resize(s.tmp, len(loc.X))
+	floats.SubTo(s.tmp, loc.X, loc.Gradient)
+	s.proj(s.tmp)
+	return floats.Distance(s.tmp, loc.X, math.Inf(1))
+}
+
+// proj projects x in place onto the feasible set.
+func (s *SPG) proj(x []float64) {
+	if s.project != nil {
+		s.project(x)
+		return
+	}
+	project(x, s.bounds)
+}
+
+// accept stores loc as the location of the last major iteration and adds its
+// function value to the history.
+func (s *SPG) accept(loc *Location) {
+	copy(s.x, loc.X)
+	copy(s.grad, loc.Gradient)
+	s.f = loc.F
+	s.fHist[s.count%s.Memory] = loc.F
+	s.count++
+}
+
+// maxF returns the largest of the last function values.
+func (s *SPG) maxF() float64 {
+	n := s.count
+	if n > s.Memory {
+		n = s.Memory
+	}
+	return floats.Max(s.fHist[:n])
+}
+
+// initLambda sets the initial spectral step length to the inverse of the norm
+// of the projected gradient at the last major iteration.
+func (s *SPG) initLambda() {
+	floats.SubTo(s.tmp, s.x, s.grad)
+	s.proj(s.tmp)
+	norm := floats.Distance(s.tmp, s.x, math.Inf(1))
+	s.lambda = math.Min(spgMaxLambda, math.Max(spgMinLambda, 1/norm))
+}
+
+// update computes the spectral step length from the step from the last major
+// iteration to loc, and accepts loc.
+func (s *SPG) update(loc *Location) {
+	var sDotS, sDotY float64
+	for i, x := range loc.X {
+		si := x - s.x[i]
+		sDotS += si * si
+		sDotY += si * (loc.Gradient[i] - s.grad[i])
+	}
+	if sDotY <= 0 {
+		s.lambda = spgMaxLambda
+	} else {
+		s.lambda = math.Min(spgMaxLambda, math.Max(spgMinLambda, sDotS/sDotY))
+	}
+	s.accept(loc)
+}
+
+// initSearch computes the search direction at the last major iteration and
+// sets loc.X to the first trial location of the backtracking search.
+func (s *SPG) initSearch(loc *Location) (Operation, error) {
+	floats.AddScaledTo(s.dir, s.x, -s.lambda, s.grad)
+	s.proj(s.dir)
+	floats.Sub(s.dir, s.x)
+	s.slope = floats.Dot(s.grad, s.dir)
+	s.iter = 0
+	s.step = 1
+	floats.AddTo(loc.X, s.x, s.dir)
+	if floats.Equal(loc.X, s.x) {
+		return NoOperation, ErrNoProgress
+	}
+	s.next = backtrackSearch
+	return FuncEvaluation, nil
+}
+
+func (*SPG) needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return struct {
+		Gradient bool
+		Hessian  bool
+	}{true, false}
+}
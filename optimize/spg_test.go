@@ -0,0 +1,135 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestSPG(t *testing.T) {
+	t.Parallel()
+	w := []float64{1, 10, 0.1, 5}
+	a := []float64{2, -1, 0.5, 3}
+	bounds := []Bound{{0, 1}, {0, 1}, {0, 1}, {0, 1}}
+	for _, test := range []struct {
+		name    string
+		problem Problem
+		x       []float64
+		want    []float64 // Known minimizer, or nil.
+	}{
+		{
+			name: "box",
+			problem: Problem{
+				Func:    weightedQuadratic(w, a).Func,
+				Grad:    weightedQuadratic(w, a).Grad,
+				Project: BoxProjection(bounds),
+			},
+			x:    []float64{0.5, 0.5, 0.5, 0.5},
+			want: []float64{1, 0, 0.5, 1},
+		},
+		{
+			name: "bounds",
+			problem: Problem{
+				Func:   weightedQuadratic(w, a).Func,
+				Grad:   weightedQuadratic(w, a).Grad,
+				Bounds: bounds,
+			},
+			x:    []float64{0.5, 0.5, 0.5, 0.5},
+			want: []float64{1, 0, 0.5, 1},
+		},
+		{
+			name: "ball",
+			problem: Problem{
+				Func:    weightedQuadratic(w, a).Func,
+				Grad:    weightedQuadratic(w, a).Grad,
+				Project: BallProjection([]float64{0, 0, 0, 1}, 1),
+			},
+			x: []float64{0, 0, 0, 1},
+		},
+		{
+			name: "simplex infeasible start",
+			problem: Problem{
+				Func:    weightedQuadratic(w, a).Func,
+				Grad:    weightedQuadratic(w, a).Grad,
+				Project: SimplexProjection(1),
+			},
+			x: []float64{5, 5, -5, 5},
+		},
+		{
+			name: "unconstrained",
+			problem: Problem{
+				Func: functions.ExtendedRosenbrock{}.Func,
+				Grad: functions.ExtendedRosenbrock{}.Grad,
+			},
+			x:    []float64{-1.2, 1},
+			want: []float64{1, 1},
+		},
+		{
+			name: "rosenbrock ball",
+			problem: Problem{
+				Func:    functions.ExtendedRosenbrock{}.Func,
+				Grad:    functions.ExtendedRosenbrock{}.Grad,
+				Project: BallProjection(nil, 0.5),
+			},
+			x: []float64{0, 0},
+		},
+	} {
+		method := &SPG{}
+		result, err := Minimize(test.problem, test.x, nil, method)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if result.Status != GradientThreshold {
+			t.Errorf("%s: unexpected status: %v", test.name, result.Status)
+		}
+		if test.want != nil && !floats.EqualApprox(result.X, test.want, 1e-6) {
+			t.Errorf("%s: unexpected minimizer: got %v, want %v", test.name, result.X, test.want)
+		}
+		// The projected gradient vanishes at the minimizer of a convex
+		// problem.
+		if test.problem.Project != nil {
+			x := make([]float64, len(result.X))
+			floats.SubTo(x, result.X, result.Gradient)
+			test.problem.Project(x)
+			if d := floats.Distance(x, result.X, math.Inf(1)); d > 1e-10 {
+				t.Errorf("%s: projected gradient too large: %v", test.name, d)
+			}
+			copy(x, result.X)
+			test.problem.Project(x)
+			if !floats.Equal(x, result.X) {
+				t.Errorf("%s: minimizer not feasible: %v", test.name, result.X)
+			}
+		}
+	}
+}
+
+func TestProjections(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name    string
+		project func(x []float64)
+		x, want []float64
+	}{
+		{"box", BoxProjection([]Bound{{0, 1}, {-1, math.Inf(1)}}), []float64{2, -3}, []float64{1, -1}},
+		{"ball inside", BallProjection([]float64{1, 1}, 2), []float64{2, 0}, []float64{2, 0}},
+		{"ball outside", BallProjection([]float64{1, 1}, 2), []float64{1, 5}, []float64{1, 3}},
+		{"ball origin", BallProjection(nil, 1), []float64{3, 4}, []float64{0.6, 0.8}},
+		{"simplex uniform", SimplexProjection(1), []float64{0.5, 0.5, 0.5}, []float64{1.0 / 3, 1.0 / 3, 1.0 / 3}},
+		{"simplex vertex", SimplexProjection(1), []float64{2, 0, 0}, []float64{1, 0, 0}},
+		{"simplex scaled", SimplexProjection(2), []float64{3, 1, -1}, []float64{2, 0, 0}},
+		{"simplex interior", SimplexProjection(2), []float64{1, 0.5, 0.5}, []float64{1, 0.5, 0.5}},
+	} {
+		x := append([]float64(nil), test.x...)
+		test.project(x)
+		if !floats.EqualApprox(x, test.want, 1e-14) {
+			t.Errorf("%s: unexpected projection of %v: got %v, want %v", test.name, test.x, x, test.want)
+		}
+	}
+}
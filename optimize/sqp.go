@@ -0,0 +1,443 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ Method               = (*SQP)(nil)
+	_ localMethod          = (*SQP)(nil)
+	_ Bounder              = (*SQP)(nil)
+	_ LinearConstrainer    = (*SQP)(nil)
+	_ NonlinearConstrainer = (*SQP)(nil)
+	_ MultiplierReporter   = (*SQP)(nil)
+	_ gradNormer           = (*SQP)(nil)
+)
+
+// SQP implements a sequential quadratic programming method for gradient-based
+// minimization subject to nonlinear equality and inequality constraints,
+// linear constraints and bound constraints on the variables.
+//
+// At every iteration SQP minimizes a quadratic model of the Lagrangian
+// subject to the linearization of the constraints. The model uses a damped
+// BFGS approximation of the Hessian of the Lagrangian. If the linearized
+// constraints are inconsistent, the linearizations of the violated
+// constraints are relaxed. A backtracking search along the solution of the
+// quadratic program then ensures sufficient decrease of the l1 merit function
+//  f(x) + μ (Σ |c_E(x)| + Σ max(0, c_I(x)))
+// where the penalty parameter μ is increased as needed. See
+//  Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer (2006),
+//  chapter 18
+// for the details of the method.
+//
+// The constraints are taken from the Nonlinear, Linear and Bounds fields of
+// the Problem. The initial location does not need to satisfy the nonlinear
+// constraints. The Jacobian of the equality constraints must have full row
+// rank. SQP is suitable for problems of moderate dimension, as it uses dense
+// factorizations of the Hessian approximation. The Lagrange multipliers of
+// the constraints at the final location are reported in Result.
+//
+// The iterates of SQP are not necessarily feasible and the objective function
+// may increase between major iterations, so a Converger based on the decrease
+// of the objective function may stop the optimization early.
+type SQP struct {
+	// DecreaseFactor is the constant in the sufficient decrease condition
+	// of the backtracking search. It must be between zero and one, and if
+	// DecreaseFactor is 0, it will be defaulted to 1e-4.
+	DecreaseFactor float64
+	// GradStopThreshold sets the threshold for stopping if the optimality
+	// measure gets too small. The optimality measure is the maximum of the
+	// infinity norm of the gradient of the Lagrangian, of the largest
+	// constraint violation, and of the largest product of an inequality
+	// constraint and its multiplier. If GradStopThreshold is 0 it is
+	// defaulted to 1e-10, and if it is NaN the setting is not used.
+	GradStopThreshold float64
+
+	status Status
+	err    error
+
+	cons    constraintSet
+	dim     int
+	started bool // Indicator that the constraints have been initialized
+
+	x     []float64     // Location at the last major iteration
+	f     float64       // Function value at the last major iteration
+	grad  []float64     // Gradient at the last major iteration
+	cE    []float64     // Equality constraints at x
+	cI    []float64     // Inequality constraints at x
+	lamE  []float64     // Multipliers of the equality constraints
+	lamI  []float64     // Multipliers of the inequality constraints
+	hess  *mat.SymDense // Approximation of the Hessian of the Lagrangian
+	first bool          // Indicator that the Hessian approximation has not been updated
+
+	dir     []float64 // Search direction
+	qpLamE  []float64 // Multipliers of the quadratic program
+	qpLamI  []float64
+	penalty float64 // Penalty parameter of the merit function
+	merit   float64 // Merit function at x
+	step    float64 // Current step along dir
+	slope   float64 // Directional derivative of the merit function along dir
+	stalled bool    // Indicator that the last search direction was zero
+	iter    int     // Number of backtracking steps in the current search
+
+	cETrial, cITrial []float64 // Constraints at the trial location
+
+	next backtrackState // Next action taken in iterateLocal
+}
+
+func (s *SQP) Status() (Status, error) {
+	return s.status, s.err
+}
+
+func (*SQP) Uses(has Available) (uses Available, err error) {
+	return has.nonlinearGradient()
+}
+
+// SetBounds sets the bounds on the variables. It implements the Bounder
+// interface.
+func (s *SQP) SetBounds(bounds []Bound) {
+	s.cons.bounds = bounds
+}
+
+// SetLinearConstraints sets the linear constraints on the variables. It
+// implements the LinearConstrainer interface.
+func (s *SQP) SetLinearConstraints(c *LinearConstraints) {
+	s.cons.linear = c
+}
+
+// SetNonlinearConstraints sets the nonlinear constraints on the variables.
+// It implements the NonlinearConstrainer interface.
+func (s *SQP) SetNonlinearConstraints(c *NonlinearConstraints) {
+	s.cons.nonlinear = c
+}
+
+func (s *SQP) Init(dim, tasks int) int {
+	s.status = NotTerminated
+	s.err = nil
+	s.started = false
+	return 1
+}
+
+func (s *SQP) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	thresh := s.GradStopThreshold
+	if thresh == 0 {
+		thresh = 1e-10
+	}
+	s.status, s.err = localOptimizer{}.run(s, thresh, operation, result, tasks)
+	close(operation)
+}
+
+// Multipliers returns the estimates of the Lagrange multipliers of the
+// constraints at the last major iteration. It implements the
+// MultiplierReporter interface.
+func (s *SQP) Multipliers() *Multipliers {
+	if !s.started {
+		return nil
+	}
+	return s.cons.multipliers(s.lamE, s.lamI)
+}
+
+// start initializes the constraints and evaluates them at the initial
+// location x.
+func (s *SQP) start(x []float64) {
+	s.started = true
+	dim := len(x)
+	s.dim = dim
+	s.cons.init(dim)
+	mE, mI := s.cons.mE, s.cons.mI
+	s.x = resize(s.x, dim)
+	s.cE = resize(s.cE, mE)
+	s.cI = resize(s.cI, mI)
+	s.cETrial = resize(s.cETrial, mE)
+	s.cITrial = resize(s.cITrial, mI)
+	s.lamE = resize(s.lamE, mE)
+	s.lamI = resize(s.lamI, mI)
+	s.qpLamE = resize(s.qpLamE, mE)
+	s.qpLamI = resize(s.qpLamI, mI)
+	for i := range s.lamE {
+		s.lamE[i] = 0
+	}
+	for i := range s.lamI {
+		s.lamI[i] = 0
+	}
+
+	copy(s.x, x)
+	s.cons.eval(s.cE, s.cI, x)
+	s.cons.jacobians(x)
+}
+
+// violation returns the l1 norm of the violation of the constraints with
+// values cE and cI.
+func violation(cE, cI []float64) float64 {
+	var v float64
+	for _, c := range cE {
+		v += math.Abs(c)
+	}
+	for _, c := range cI {
+		v += math.Max(0, c)
+	}
+	return v
+}
+
+// lagrangianGrad stores in dst the gradient of the Lagrangian with the
+// multipliers lamE and lamI at the last major iteration.
+func (s *SQP) lagrangianGrad(dst, lamE, lamI []float64) {
+	copy(dst, s.grad)
+	for i, row := range s.cons.jE {
+		floats.AddScaled(dst, lamE[i], row)
+	}
+	for i, row := range s.cons.jI {
+		floats.AddScaled(dst, lamI[i], row)
+	}
+}
+
+// gradNorm returns the optimality measure at loc.
+func (s *SQP) gradNorm(loc *Location) float64 {
+	if !s.started {
+		s.start(loc.X)
+		s.grad = resize(s.grad, s.dim)
+		copy(s.grad, loc.Gradient)
+	}
+	gl := make([]float64, s.dim)
+	s.lagrangianGrad(gl, s.lamE, s.lamI)
+	norm := floats.Norm(gl, math.Inf(1))
+	for _, c := range s.cE {
+		norm = math.Max(norm, math.Abs(c))
+	}
+	for i, c := range s.cI {
+		norm = math.Max(norm, c)
+		norm = math.Max(norm, math.Abs(s.lamI[i]*c))
+	}
+	return norm
+}
+
+func (s *SQP) initLocal(loc *Location) (Operation, error) {
+	if s.DecreaseFactor == 0 {
+		s.DecreaseFactor = 1e-4
+	}
+	if s.DecreaseFactor <= 0 || s.DecreaseFactor >= 1 {
+		panic("sqp: DecreaseFactor must be between 0 and 1")
+	}
+	if !s.started || !floats.Equal(s.x, loc.X) {
+		s.start(loc.X)
+	}
+	dim := len(loc.X)
+	s.grad = resize(s.grad, dim)
+	s.dir = resize(s.dir, dim)
+	s.hess = resizeSymDense(s.hess, dim)
+	s.resetHessian()
+	s.penalty = 0
+	s.stalled = false
+
+	copy(s.grad, loc.Gradient)
+	s.f = loc.F
+	return s.initSearch(loc)
+}
+
+// resetHessian sets the Hessian approximation to the identity.
+func (s *SQP) resetHessian() {
+	for i := 0; i < s.dim; i++ {
+		for j := i; j < s.dim; j++ {
+			if i == j {
+				s.hess.SetSym(i, i, 1)
+			} else {
+				s.hess.SetSym(i, j, 0)
+			}
+		}
+	}
+	s.first = true
+}
+
+func (s *SQP) iterateLocal(loc *Location) (Operation, error) {
+	switch s.next {
+	default:
+		panic("sqp: unexpected state")
+	case backtrackSearch:
+		s.cons.eval(s.cETrial, s.cITrial, loc.X)
+		merit := loc.F + s.penalty*violation(s.cETrial, s.cITrial)
+		if merit <= s.merit+s.DecreaseFactor*s.step*s.slope {
+			// The trial location is accepted, evaluate the gradient.
+			s.next = backtrackGrad
+			return GradEvaluation, nil
+		}
+		s.iter++
+		if s.iter >= maxBacktracks {
+			return NoOperation, ErrLinesearcherFailure
+		}
+		s.step /= 2
+		if !s.trial(loc) {
+			return s.restart(loc)
+		}
+		return FuncEvaluation, nil
+	case backtrackGrad:
+		s.update(loc)
+		s.stalled = false
+		s.next = backtrackMajor
+		return MajorIteration, nil
+	case backtrackMajor:
+		return s.initSearch(loc)
+	}
+}
+
+// update accepts loc and the multipliers of the last quadratic program, and
+// updates the Hessian approximation with the change of the gradient of the
+// Lagrangian using the damped BFGS update.
+func (s *SQP) update(loc *Location) {
+	dim := s.dim
+	copy(s.lamE, s.qpLamE)
+	copy(s.lamI, s.qpLamI)
+
+	step := mat.NewVecDense(dim, nil)
+	step.SubVec(mat.NewVecDense(dim, loc.X), mat.NewVecDense(dim, s.x))
+	y := mat.NewVecDense(dim, nil)
+	s.lagrangianGrad(y.RawVector().Data, s.lamE, s.lamI)
+	y.ScaleVec(-1, y)
+
+	copy(s.x, loc.X)
+	copy(s.grad, loc.Gradient)
+	s.f = loc.F
+	copy(s.cE, s.cETrial)
+	copy(s.cI, s.cITrial)
+	s.cons.jacobians(loc.X)
+
+	gl := make([]float64, dim)
+	s.lagrangianGrad(gl, s.lamE, s.lamI)
+	y.AddVec(y, mat.NewVecDense(dim, gl))
+	s.first = dampedBFGS(s.hess, step, y, s.first)
+}
+
+// initSearch solves the quadratic program at the last major iteration,
+// updates the penalty parameter and sets loc.X to the first trial location
+// of the backtracking search.
+func (s *SQP) initSearch(loc *Location) (Operation, error) {
+	relax, err := s.direction()
+	if err != nil {
+		return NoOperation, err
+	}
+
+	// Update the penalty parameter so that the search direction is
+	// a descent direction of the merit function as described in
+	// Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer (2006), equation 18.36.
+	viol := violation(s.cE, s.cI)
+	for i, row := range s.cons.jE {
+		s.cETrial[i] = s.cE[i] + floats.Dot(row, s.dir)
+	}
+	for i, row := range s.cons.jI {
+		s.cITrial[i] = s.cI[i] + floats.Dot(row, s.dir)
+	}
+	delta := viol - violation(s.cETrial, s.cITrial)
+	if relax == 0 {
+		delta = viol
+	}
+	gd := floats.Dot(s.grad, s.dir)
+	bd := mat.NewVecDense(s.dim, nil)
+	dv := mat.NewVecDense(s.dim, s.dir)
+	bd.MulVec(s.hess, dv)
+	dBd := mat.Dot(dv, bd)
+	if delta > 0 {
+		s.penalty = math.Max(s.penalty, (gd+0.5*dBd)/(0.5*delta))
+	}
+	lamNorm := math.Max(floats.Norm(s.qpLamE, math.Inf(1)), floats.Norm(s.qpLamI, math.Inf(1)))
+	s.penalty = math.Max(s.penalty, lamNorm)
+	s.merit = s.f + s.penalty*viol
+	s.slope = gd - s.penalty*math.Max(delta, 0)
+
+	s.iter = 0
+	s.step = 1
+	if !s.trial(loc) {
+		// The quadratic program has a zero solution, so its multipliers
+		// are the multipliers at the last major iteration. Report the
+		// last major iteration again so that the optimality is checked.
+		if s.stalled {
+			return NoOperation, ErrNoProgress
+		}
+		s.stalled = true
+		copy(s.lamE, s.qpLamE)
+		copy(s.lamI, s.qpLamI)
+		copy(loc.X, s.x)
+		copy(loc.Gradient, s.grad)
+		loc.F = s.f
+		s.next = backtrackMajor
+		return MajorIteration, nil
+	}
+	if s.slope >= 0 {
+		return s.restart(loc)
+	}
+	s.next = backtrackSearch
+	return FuncEvaluation, nil
+}
+
+// restart resets the Hessian approximation and starts a new search. restart is
+// called when the search along the quasi-Newton direction does not make
+// progress.
+func (s *SQP) restart(loc *Location) (Operation, error) {
+	if s.first {
+		return NoOperation, ErrNoProgress
+	}
+	s.resetHessian()
+	return s.initSearch(loc)
+}
+
+// trial sets loc.X to the location at the current step along the search
+// direction. trial returns false if the location does not differ from the
+// location of the last major iteration.
+func (s *SQP) trial(loc *Location) bool {
+	floats.AddScaledTo(loc.X, s.x, s.step, s.dir)
+	// Remove the rounding errors that would place the location outside
+	// the bounds.
+	project(loc.X, s.cons.bounds)
+	return !floats.Equal(loc.X, s.x)
+}
+
+// direction computes the search direction at the last major iteration by
+// solving the quadratic program
+//  minimize gᵀ d + ½ dᵀ B d
+//  subject to J_E d + (1-ξ) c_E = 0
+//             J_I d + c̃_I ≤ 0
+// where c̃_I is (1-ξ) c_I for the violated inequality constraints and c_I
+// otherwise. The relaxation ξ is zero unless the linearized constraints are
+// inconsistent. direction returns the relaxation that was used.
+func (s *SQP) direction() (relax float64, err error) {
+	eE := make([]float64, s.cons.mE)
+	eI := make([]float64, s.cons.mI)
+	const maxRelax = 10
+	for k := 0; k <= maxRelax; k++ {
+		if k > 0 {
+			relax = 1 - math.Pow(0.5, float64(k))
+		}
+		if k == maxRelax {
+			relax = 1
+		}
+		for i, c := range s.cE {
+			eE[i] = (1 - relax) * c
+		}
+		for i, c := range s.cI {
+			if c > 0 {
+				c *= 1 - relax
+			}
+			eI[i] = c
+		}
+		err = qp(s.dir, s.qpLamE, s.qpLamI, s.hess, s.grad, s.cons.jE, eE, s.cons.jI, eI)
+		if err != errQPInfeasible {
+			return relax, err
+		}
+	}
+	return relax, err
+}
+
+func (*SQP) needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return struct {
+		Gradient bool
+		Hessian  bool
+	}{true, false}
+}
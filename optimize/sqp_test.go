@@ -0,0 +1,247 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+// circle returns the constraint ‖x‖² - r² for use as an equality or
+// inequality constraint.
+func circle(r float64) (c func(dst, x []float64), jac func(jac *mat.Dense, x []float64)) {
+	c = func(dst, x []float64) {
+		dst[0] = floats.Dot(x, x) - r*r
+	}
+	jac = func(jac *mat.Dense, x []float64) {
+		for i, v := range x {
+			jac.Set(0, i, 2*v)
+		}
+	}
+	return c, jac
+}
+
+// hs071 returns problem 71 of
+//  Hock, W., Schittkowski, K.: Test Examples for Nonlinear Programming Codes.
+//  Lecture Notes in Economics and Mathematical Systems 187. Springer (1981).
+func hs071() (Problem, *NonlinearConstraints) {
+	p := Problem{
+		Func: func(x []float64) float64 {
+			return x[0]*x[3]*(x[0]+x[1]+x[2]) + x[2]
+		},
+		Grad: func(grad, x []float64) {
+			grad[0] = x[3]*(x[0]+x[1]+x[2]) + x[0]*x[3]
+			grad[1] = x[0] * x[3]
+			grad[2] = x[0]*x[3] + 1
+			grad[3] = x[0] * (x[0] + x[1] + x[2])
+		},
+		Bounds: []Bound{{1, 5}, {1, 5}, {1, 5}, {1, 5}},
+	}
+	eq, eqJac := circle(math.Sqrt(40))
+	c := &NonlinearConstraints{
+		NumEq:   1,
+		Eq:      eq,
+		EqJac:   eqJac,
+		NumIneq: 1,
+		Ineq: func(dst, x []float64) {
+			dst[0] = 25 - x[0]*x[1]*x[2]*x[3]
+		},
+		IneqJac: func(jac *mat.Dense, x []float64) {
+			jac.Set(0, 0, -x[1]*x[2]*x[3])
+			jac.Set(0, 1, -x[0]*x[2]*x[3])
+			jac.Set(0, 2, -x[0]*x[1]*x[3])
+			jac.Set(0, 3, -x[0]*x[1]*x[2])
+		},
+	}
+	return p, c
+}
+
+func TestSQP(t *testing.T) {
+	t.Parallel()
+	inf := math.Inf(1)
+	quad := weightedQuadratic([]float64{1, 1}, []float64{1, 2})
+	eq, eqJac := circle(1)
+	hs, hsCons := hs071()
+	for _, test := range []struct {
+		name      string
+		p         Problem
+		nonlinear *NonlinearConstraints
+		linear    *LinearConstraints
+		bounds    []Bound
+		x         []float64
+		want      []float64
+		wantF     float64
+	}{
+		{
+			name:      "equality",
+			p:         quad,
+			nonlinear: &NonlinearConstraints{NumEq: 1, Eq: eq, EqJac: eqJac},
+			x:         []float64{0, 0.5},
+			want:      []float64{1 / math.Sqrt(5), 2 / math.Sqrt(5)},
+		},
+		{
+			name:      "active inequality",
+			p:         quad,
+			nonlinear: &NonlinearConstraints{NumIneq: 1, Ineq: eq, IneqJac: eqJac},
+			x:         []float64{3, -3},
+			want:      []float64{1 / math.Sqrt(5), 2 / math.Sqrt(5)},
+		},
+		{
+			name:      "inactive inequality",
+			p:         weightedQuadratic([]float64{1, 1}, []float64{0.25, 0.5}),
+			nonlinear: &NonlinearConstraints{NumIneq: 1, Ineq: eq, IneqJac: eqJac},
+			x:         []float64{0, 0},
+			want:      []float64{0.25, 0.5},
+		},
+		{
+			name:      "nonlinear and linear",
+			p:         weightedQuadratic([]float64{1, 1, 1}, []float64{1, 1, 1}),
+			nonlinear: &NonlinearConstraints{NumEq: 1, Eq: eq, EqJac: eqJac},
+			linear: &LinearConstraints{
+				A: mat.NewDense(1, 3, []float64{0, 0, 1}),
+				B: []float64{0},
+			},
+			x:    []float64{1, 1, 0},
+			want: []float64{1 / math.Sqrt2, 1 / math.Sqrt2, 0},
+		},
+		{
+			name:      "nonlinear and bounds",
+			p:         quad,
+			nonlinear: &NonlinearConstraints{NumIneq: 1, Ineq: eq, IneqJac: eqJac},
+			bounds:    []Bound{{-inf, inf}, {-inf, 0.5}},
+			x:         []float64{0, 0},
+			want:      []float64{math.Sqrt(0.75), 0.5},
+		},
+		{
+			name: "rosenbrock",
+			p: Problem{
+				Func: functions.ExtendedRosenbrock{}.Func,
+				Grad: functions.ExtendedRosenbrock{}.Grad,
+			},
+			nonlinear: &NonlinearConstraints{NumIneq: 1, Ineq: eq, IneqJac: eqJac},
+			x:         []float64{-1.2, 1},
+			want:      []float64{0.7864151541684231, 0.6176983125233985},
+		},
+		{
+			name:      "hs071",
+			p:         hs,
+			nonlinear: hsCons,
+			x:         []float64{1, 5, 5, 1},
+			want:      []float64{1, 4.742999, 3.821150, 1.379408},
+			wantF:     17.0140173,
+		},
+	} {
+		p := test.p
+		p.Nonlinear = test.nonlinear
+		if test.linear != nil {
+			p.Linear = test.linear
+		}
+		if test.bounds != nil {
+			p.Bounds = test.bounds
+		}
+		x := make([]float64, len(test.x))
+		copy(x, test.x)
+		result, err := Minimize(p, x, nil, &SQP{})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if result.Status != GradientThreshold {
+			t.Errorf("%s: unexpected status: got %v, want %v", test.name, result.Status, GradientThreshold)
+		}
+		if !floats.EqualApprox(result.X, test.want, 1e-6) {
+			t.Errorf("%s: unexpected optimum: got %v, want %v", test.name, result.X, test.want)
+		}
+		if test.wantF != 0 && math.Abs(result.F-test.wantF) > 1e-6 {
+			t.Errorf("%s: unexpected optimal value: got %v, want %v", test.name, result.F, test.wantF)
+		}
+		if err := checkBounds(p.Bounds, result.X); err != nil {
+			t.Errorf("%s: optimum outside bounds: %v", test.name, result.X)
+		}
+		if result.ConstraintViolation > 1e-10 {
+			t.Errorf("%s: unexpected constraint violation: %v", test.name, result.ConstraintViolation)
+		}
+
+		// Check the stationarity of the Lagrangian with the reported
+		// multipliers.
+		m := result.Multipliers
+		if m == nil {
+			t.Errorf("%s: no multipliers reported", test.name)
+			continue
+		}
+		dim := len(x)
+		gl := make([]float64, dim)
+		p.Grad(gl, result.X)
+		addJac := func(lambda []float64, jac func(*mat.Dense, []float64)) {
+			if len(lambda) == 0 {
+				return
+			}
+			j := mat.NewDense(len(lambda), dim, nil)
+			jac(j, result.X)
+			var v mat.VecDense
+			v.MulVec(j.T(), mat.NewVecDense(len(lambda), lambda))
+			floats.Add(gl, v.RawVector().Data)
+		}
+		addJac(m.Eq, test.nonlinear.EqJac)
+		addJac(m.Ineq, test.nonlinear.IneqJac)
+		if p.Linear != nil {
+			addJac(m.LinearEq, func(j *mat.Dense, _ []float64) { j.Copy(p.Linear.A) })
+		}
+		for i := range m.Lower {
+			gl[i] += m.Upper[i] - m.Lower[i]
+		}
+		for _, l := range [][]float64{m.Ineq, m.Lower, m.Upper} {
+			for _, v := range l {
+				if v < 0 {
+					t.Errorf("%s: negative multiplier of inequality constraint: %v", test.name, l)
+				}
+			}
+		}
+		if norm := floats.Norm(gl, math.Inf(1)); norm > 1e-6 {
+			t.Errorf("%s: gradient of Lagrangian not zero: %v", test.name, gl)
+		}
+	}
+}
+
+func TestMinimizeNonlinear(t *testing.T) {
+	t.Parallel()
+	eq, eqJac := circle(1)
+	p := weightedQuadratic([]float64{1, 1}, []float64{1, 2})
+	p.Nonlinear = &NonlinearConstraints{NumEq: 1, Eq: eq, EqJac: eqJac}
+
+	// The default method supports nonlinear constraints.
+	result, err := Minimize(p, []float64{3, 3}, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []float64{1 / math.Sqrt(5), 2 / math.Sqrt(5)}
+	if !floats.EqualApprox(result.X, want, 1e-6) {
+		t.Errorf("unexpected optimum with default method: got %v, want %v", result.X, want)
+	}
+	if result.Multipliers == nil || math.Abs(result.Multipliers.Eq[0]-(math.Sqrt(5)-1)) > 1e-6 {
+		t.Errorf("unexpected multipliers: got %v, want [%v]", result.Multipliers, math.Sqrt(5)-1)
+	}
+
+	if !panics(func() { Minimize(p, []float64{1, 1}, nil, &ActiveSet{}) }) {
+		t.Errorf("no panic for nonlinear constraints with linearly constrained method")
+	}
+	p.Nonlinear.EqJac = nil
+	if !panics(func() { Minimize(p, []float64{1, 1}, nil, &SQP{}) }) {
+		t.Errorf("no panic for missing Jacobian")
+	}
+
+	p.Nonlinear = nil
+	result, err = Minimize(p, []float64{0, 0}, nil, &LBFGS{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Multipliers != nil {
+		t.Errorf("unexpected multipliers from method without constraints: %v", result.Multipliers)
+	}
+}
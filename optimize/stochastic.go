@@ -0,0 +1,741 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math"
+)
+
+var (
+	_ Method = (*SGD)(nil)
+	_ Method = (*Adam)(nil)
+	_ Method = (*AdaGrad)(nil)
+	_ Method = (*RMSProp)(nil)
+
+	_ stochasticMethod = (*SGD)(nil)
+	_ stochasticMethod = (*Adam)(nil)
+	_ stochasticMethod = (*AdaGrad)(nil)
+	_ stochasticMethod = (*RMSProp)(nil)
+
+	_ Checkpointer = (*SGD)(nil)
+	_ Checkpointer = (*Adam)(nil)
+	_ Checkpointer = (*AdaGrad)(nil)
+	_ Checkpointer = (*RMSProp)(nil)
+
+	_ sparseGradUser = (*SGD)(nil)
+	_ sparseGradUser = (*AdaGrad)(nil)
+
+	_ LearningRateSchedule = ConstantRate{}
+	_ LearningRateSchedule = StepDecay{}
+	_ LearningRateSchedule = ExponentialDecay{}
+	_ LearningRateSchedule = InverseTimeDecay{}
+)
+
+// stochasticMethod is a Method that uses noisy estimates of the objective
+// function and its gradient, such as the values on a random minibatch of
+// training data. The function values and gradients of a stochasticMethod are
+// not used for checking the convergence in Minimize.
+type stochasticMethod interface {
+	stochastic()
+}
+
+// LearningRateSchedule determines the learning rate of a stochastic gradient
+// Method at each iteration.
+type LearningRateSchedule interface {
+	// LearningRate returns the learning rate at the iteration iter, which
+	// starts from zero, given the base learning rate of the Method.
+	LearningRate(base float64, iter int) float64
+}
+
+// ConstantRate is a LearningRateSchedule that always returns the base
+// learning rate.
+type ConstantRate struct{}
+
+func (ConstantRate) LearningRate(base float64, iter int) float64 {
+	return base
+}
+
+// StepDecay is a LearningRateSchedule that multiplies the learning rate by
+// Factor every Steps iterations,
+//  base * Factor^⌊iter/Steps⌋.
+type StepDecay struct {
+	Factor float64
+	Steps  int
+}
+
+func (s StepDecay) LearningRate(base float64, iter int) float64 {
+	if s.Steps <= 0 {
+		panic("optimize: non-positive number of decay steps")
+	}
+	return base * math.Pow(s.Factor, float64(iter/s.Steps))
+}
+
+// ExponentialDecay is a LearningRateSchedule that decreases the learning rate
+// continuously by Factor over Steps iterations,
+//  base * Factor^(iter/Steps).
+type ExponentialDecay struct {
+	Factor float64
+	Steps  int
+}
+
+func (e ExponentialDecay) LearningRate(base float64, iter int) float64 {
+	if e.Steps <= 0 {
+		panic("optimize: non-positive number of decay steps")
+	}
+	return base * math.Pow(e.Factor, float64(iter)/float64(e.Steps))
+}
+
+// InverseTimeDecay is a LearningRateSchedule that decreases the learning
+// rate inversely to the number of iterations,
+//  base / (1 + Decay * iter).
+// The learning rates of InverseTimeDecay satisfy the Robbins-Monro conditions
+// for the convergence of SGD.
+type InverseTimeDecay struct {
+	Decay float64
+}
+
+func (d InverseTimeDecay) LearningRate(base float64, iter int) float64 {
+	return base / (1 + d.Decay*float64(iter))
+}
+
+// stochasticGradient implements the iterations common to the stochastic
+// gradient methods. Every iteration evaluates the function and the gradient
+// at the current location, reports the location as a MajorIteration, and
+// moves to the next location.
+type stochasticGradient struct {
+	schedule LearningRateSchedule
+	iter     int
+	op       Operation
+
+	started bool // Indicator that the iterations have started.
+	resume  bool // Indicator that the state has been restored from a checkpoint.
+}
+
+// init initializes the iterations and returns whether they continue from
+// a restored checkpoint, in which case the state of the method must not be
+// reset.
+func (s *stochasticGradient) init(schedule LearningRateSchedule) (resume bool) {
+	if schedule == nil {
+		schedule = ConstantRate{}
+	}
+	s.schedule = schedule
+	s.op = MajorIteration
+	s.started = true
+	if s.resume {
+		s.resume = false
+		return true
+	}
+	s.iter = 0
+	return false
+}
+
+// iterate calls update with the learning rate of the current iteration after
+// a MajorIteration and returns the next Operation. If the gradient is only
+// available in sparse form, sparse is called instead of update.
+func (s *stochasticGradient) iterate(loc *Location, base float64, update func(x, grad []float64, rate float64), sparse func(x []float64, grad *SparseGradient, rate float64)) Operation {
+	if s.op != MajorIteration {
+		s.op = MajorIteration
+		return s.op
+	}
+	rate := s.schedule.LearningRate(base, s.iter)
+	if loc.Gradient == nil && loc.SparseGradient != nil {
+		sparse(loc.X, loc.SparseGradient, rate)
+	} else {
+		update(loc.X, loc.Gradient, rate)
+	}
+	s.iter++
+	s.op = FuncEvaluation | GradEvaluation
+	return s.op
+}
+
+// stochasticState is the checkpointed state of a stochastic gradient method.
+type stochasticState struct {
+	Started bool
+	Iter    int
+	Scalars []float64
+	Vectors [][]float64
+}
+
+// marshalState returns the encoded state of the iterations and of the method
+// given by scalars and vecs.
+func (s *stochasticGradient) marshalState(scalars []float64, vecs ...[]float64) ([]byte, error) {
+	st := stochasticState{Started: s.started}
+	if s.started {
+		st.Iter = s.iter
+		st.Scalars = scalars
+		st.Vectors = vecs
+	}
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(st)
+	return buf.Bytes(), err
+}
+
+// unmarshalState restores the state of the iterations from data and returns
+// the state of the method, which must have the given numbers of scalars and
+// vectors of equal length if the iterations have started.
+func (s *stochasticGradient) unmarshalState(data []byte, scalars, vecs int) (stochasticState, error) {
+	var st stochasticState
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&st)
+	if err != nil {
+		return st, err
+	}
+	s.resume = st.Started
+	if !st.Started {
+		return st, nil
+	}
+	if len(st.Scalars) != scalars || len(st.Vectors) != vecs {
+		return st, ErrBadCheckpoint
+	}
+	for _, v := range st.Vectors {
+		if len(v) == 0 || len(v) != len(st.Vectors[0]) {
+			return st, ErrBadCheckpoint
+		}
+	}
+	s.iter = st.Iter
+	return st, nil
+}
+
+// checkResume panics if the dimension of the restored state v does not match
+// the dimension of the problem.
+func checkResume(v []float64, dim int) {
+	if len(v) != dim {
+		panic("optimize: checkpoint size mismatch")
+	}
+}
+
+// stochasticNeeds are the needs of the stochastic gradient methods.
+func stochasticNeeds() struct{ Gradient, Hessian bool } {
+	return struct{ Gradient, Hessian bool }{true, false}
+}
+
+// SGD implements stochastic gradient descent with momentum. SGD is intended
+// for problems where the objective function is the average of many terms,
+// such as the loss of a model over training data, and Problem.Func and
+// Problem.Grad return the values of a random subset of the terms, so that
+// both are noisy estimates of the full objective. Each iteration moves the
+// location by
+//  v_{k+1} = μ v_k - α_k g_k,
+//  x_{k+1} = x_k + v_{k+1},
+// where μ is Momentum, α_k is the learning rate and g_k is the gradient at x_k,
+// or at x_k + μ v_k if Nesterov is true. In the latter case, the locations
+// of the major iterations are the extrapolated locations x_k + μ v_k.
+//
+// If Momentum is 0, SGD uses gradients given by Problem.SparseGrad directly
+// and only updates the variables with non-zero gradient components. The
+// optimization driver still does work proportional to the number of
+// variables at each iteration.
+//
+// The function values and gradients of stochastic gradient methods are not
+// used to check the convergence: the default Converger is NeverTerminate and
+// the gradient thresholds of Settings are ignored. The optimization must be
+// stopped by the iteration, evaluation or runtime limits of Settings, and the
+// result is the location of the last major iteration.
+type SGD struct {
+	// LearningRate is the base learning rate. If LearningRate is 0,
+	// a default value of 0.01 is used.
+	LearningRate float64
+	// Schedule determines the learning rate at each iteration. If Schedule
+	// is nil, the learning rate is constant.
+	Schedule LearningRateSchedule
+	// Momentum is the momentum coefficient in [0, 1). If Momentum is 0,
+	// plain stochastic gradient descent is used.
+	Momentum float64
+	// Nesterov specifies whether the gradient is evaluated at the location
+	// extrapolated by the momentum.
+	Nesterov bool
+
+	sg       stochasticGradient
+	rate     float64
+	velocity []float64
+
+	status Status
+	err    error
+}
+
+func (*SGD) stochastic() {}
+
+func (s *SGD) Status() (Status, error) {
+	return s.status, s.err
+}
+
+func (*SGD) Uses(has Available) (uses Available, err error) {
+	return has.gradient()
+}
+
+func (s *SGD) Init(dim, tasks int) int {
+	s.status = NotTerminated
+	s.err = nil
+	s.sg.started = false
+	return 1
+}
+
+func (s *SGD) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	s.status, s.err = localOptimizer{}.run(s, math.NaN(), operation, result, tasks)
+	close(operation)
+}
+
+func (s *SGD) initLocal(loc *Location) (Operation, error) {
+	s.rate = s.LearningRate
+	if s.rate == 0 {
+		s.rate = 0.01
+	}
+	if s.rate < 0 {
+		panic("optimize: negative learning rate")
+	}
+	if s.Momentum < 0 || s.Momentum >= 1 {
+		panic("optimize: momentum not in [0, 1)")
+	}
+	if s.sg.init(s.Schedule) {
+		checkResume(s.velocity, len(loc.X))
+	} else {
+		s.velocity = resize(s.velocity, len(loc.X))
+		for i := range s.velocity {
+			s.velocity[i] = 0
+		}
+	}
+	return s.iterateLocal(loc)
+}
+
+func (s *SGD) iterateLocal(loc *Location) (Operation, error) {
+	return s.sg.iterate(loc, s.rate, s.update, s.updateSparse), nil
+}
+
+func (s *SGD) usesSparseGrad() bool {
+	return s.Momentum == 0
+}
+
+// updateSparse performs the update without momentum, for which the velocity
+// is not needed.
+func (s *SGD) updateSparse(x []float64, grad *SparseGradient, rate float64) {
+	for k, i := range grad.Index {
+		x[i] -= rate * grad.Value[k]
+	}
+}
+
+func (s *SGD) update(x, grad []float64, rate float64) {
+	mu := s.Momentum
+	for i, g := range grad {
+		if !s.Nesterov {
+			s.velocity[i] = mu*s.velocity[i] - rate*g
+			x[i] += s.velocity[i]
+			continue
+		}
+		// The location holds the extrapolated point x_k + μ v_k. Move it
+		// to x_{k+1} + μ v_{k+1}.
+		v := mu*s.velocity[i] - rate*g
+		x[i] += -mu*s.velocity[i] + (1+mu)*v
+		s.velocity[i] = v
+	}
+}
+
+// MarshalState returns the state of the iterations. It implements the
+// Checkpointer interface.
+func (s *SGD) MarshalState() ([]byte, error) {
+	return s.sg.marshalState(nil, s.velocity)
+}
+
+// UnmarshalState restores the state returned by MarshalState. It implements
+// the Checkpointer interface.
+func (s *SGD) UnmarshalState(data []byte) error {
+	st, err := s.sg.unmarshalState(data, 0, 1)
+	if err != nil || !st.Started {
+		return err
+	}
+	s.velocity = st.Vectors[0]
+	return nil
+}
+
+func (*SGD) needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return stochasticNeeds()
+}
+
+// Adam implements the adaptive moment estimation method
+//  Kingma, D. P., Ba, J.: Adam: A method for stochastic optimization.
+//  3rd International Conference on Learning Representations (2015)
+// which scales the step in each variable by running estimates of the first
+// and second moments of the gradient,
+//  m_{k+1} = β_1 m_k + (1-β_1) g_k,
+//  v_{k+1} = β_2 v_k + (1-β_2) g_k²,
+//  x_{k+1} = x_k - α_k m̂_{k+1} / (√v̂_{k+1} + ε),
+// where m̂ and v̂ are the moment estimates corrected for their initial bias.
+// See the documentation of SGD for the use of stochastic gradient methods.
+type Adam struct {
+	// LearningRate is the base learning rate. If LearningRate is 0,
+	// a default value of 0.001 is used.
+	LearningRate float64
+	// Schedule determines the learning rate at each iteration. If Schedule
+	// is nil, the learning rate is constant.
+	Schedule LearningRateSchedule
+	// Beta1 and Beta2 are the decay rates of the moment estimates in
+	// [0, 1). If they are 0, default values of 0.9 and 0.999 are used.
+	Beta1 float64
+	Beta2 float64
+	// Epsilon prevents division by zero. If Epsilon is 0, a default value
+	// of 1e-8 is used.
+	Epsilon float64
+
+	sg     stochasticGradient
+	rate   float64
+	beta1  float64
+	beta2  float64
+	eps    float64
+	m, v   []float64
+	b1, b2 float64 // Powers of the decay rates for the bias correction
+
+	status Status
+	err    error
+}
+
+func (*Adam) stochastic() {}
+
+func (a *Adam) Status() (Status, error) {
+	return a.status, a.err
+}
+
+func (*Adam) Uses(has Available) (uses Available, err error) {
+	return has.gradient()
+}
+
+func (a *Adam) Init(dim, tasks int) int {
+	a.status = NotTerminated
+	a.err = nil
+	a.sg.started = false
+	return 1
+}
+
+func (a *Adam) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	a.status, a.err = localOptimizer{}.run(a, math.NaN(), operation, result, tasks)
+	close(operation)
+}
+
+func (a *Adam) initLocal(loc *Location) (Operation, error) {
+	a.rate = a.LearningRate
+	if a.rate == 0 {
+		a.rate = 0.001
+	}
+	if a.rate < 0 {
+		panic("optimize: negative learning rate")
+	}
+	a.beta1 = a.Beta1
+	if a.beta1 == 0 {
+		a.beta1 = 0.9
+	}
+	a.beta2 = a.Beta2
+	if a.beta2 == 0 {
+		a.beta2 = 0.999
+	}
+	if a.beta1 < 0 || a.beta1 >= 1 || a.beta2 < 0 || a.beta2 >= 1 {
+		panic("optimize: decay rate not in [0, 1)")
+	}
+	a.eps = a.Epsilon
+	if a.eps == 0 {
+		a.eps = 1e-8
+	}
+	dim := len(loc.X)
+	if a.sg.init(a.Schedule) {
+		checkResume(a.m, dim)
+	} else {
+		a.m = resize(a.m, dim)
+		a.v = resize(a.v, dim)
+		for i := range a.m {
+			a.m[i] = 0
+			a.v[i] = 0
+		}
+		a.b1, a.b2 = 1, 1
+	}
+	return a.iterateLocal(loc)
+}
+
+func (a *Adam) iterateLocal(loc *Location) (Operation, error) {
+	return a.sg.iterate(loc, a.rate, a.update, nil), nil
+}
+
+func (a *Adam) update(x, grad []float64, rate float64) {
+	a.b1 *= a.beta1
+	a.b2 *= a.beta2
+	for i, g := range grad {
+		a.m[i] = a.beta1*a.m[i] + (1-a.beta1)*g
+		a.v[i] = a.beta2*a.v[i] + (1-a.beta2)*g*g
+		m := a.m[i] / (1 - a.b1)
+		v := a.v[i] / (1 - a.b2)
+		x[i] -= rate * m / (math.Sqrt(v) + a.eps)
+	}
+}
+
+// MarshalState returns the state of the iterations and of the moment
+// estimates. It implements the Checkpointer interface.
+func (a *Adam) MarshalState() ([]byte, error) {
+	return a.sg.marshalState([]float64{a.b1, a.b2}, a.m, a.v)
+}
+
+// UnmarshalState restores the state returned by MarshalState. It implements
+// the Checkpointer interface.
+func (a *Adam) UnmarshalState(data []byte) error {
+	st, err := a.sg.unmarshalState(data, 2, 2)
+	if err != nil || !st.Started {
+		return err
+	}
+	a.b1, a.b2 = st.Scalars[0], st.Scalars[1]
+	a.m, a.v = st.Vectors[0], st.Vectors[1]
+	return nil
+}
+
+func (*Adam) needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return stochasticNeeds()
+}
+
+// AdaGrad implements the adaptive gradient method
+//  Duchi, J., Hazan, E., Singer, Y.: Adaptive subgradient methods for online
+//  learning and stochastic optimization. J. Mach. Learn. Res. 12 (2011),
+//  2121-2159
+// which scales the step in each variable by the accumulated squares of the
+// gradient,
+//  s_{k+1} = s_k + g_k²,
+//  x_{k+1} = x_k - α_k g_k / (√s_{k+1} + ε).
+// The steps of AdaGrad decrease with the iterations, so it is usually used
+// with a constant learning rate. See the documentation of SGD for the use of
+// stochastic gradient methods.
+//
+// AdaGrad uses gradients given by Problem.SparseGrad directly and only
+// updates the variables with non-zero gradient components, for which it is
+// well suited.
+type AdaGrad struct {
+	// LearningRate is the base learning rate. If LearningRate is 0,
+	// a default value of 0.01 is used.
+	LearningRate float64
+	// Schedule determines the learning rate at each iteration. If Schedule
+	// is nil, the learning rate is constant.
+	Schedule LearningRateSchedule
+	// Epsilon prevents division by zero. If Epsilon is 0, a default value
+	// of 1e-8 is used.
+	Epsilon float64
+
+	sg   stochasticGradient
+	rate float64
+	eps  float64
+	sum  []float64
+
+	status Status
+	err    error
+}
+
+func (*AdaGrad) stochastic() {}
+
+func (a *AdaGrad) Status() (Status, error) {
+	return a.status, a.err
+}
+
+func (*AdaGrad) Uses(has Available) (uses Available, err error) {
+	return has.gradient()
+}
+
+func (a *AdaGrad) Init(dim, tasks int) int {
+	a.status = NotTerminated
+	a.err = nil
+	a.sg.started = false
+	return 1
+}
+
+func (a *AdaGrad) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	a.status, a.err = localOptimizer{}.run(a, math.NaN(), operation, result, tasks)
+	close(operation)
+}
+
+func (a *AdaGrad) initLocal(loc *Location) (Operation, error) {
+	a.rate = a.LearningRate
+	if a.rate == 0 {
+		a.rate = 0.01
+	}
+	if a.rate < 0 {
+		panic("optimize: negative learning rate")
+	}
+	a.eps = a.Epsilon
+	if a.eps == 0 {
+		a.eps = 1e-8
+	}
+	if a.sg.init(a.Schedule) {
+		checkResume(a.sum, len(loc.X))
+	} else {
+		a.sum = resize(a.sum, len(loc.X))
+		for i := range a.sum {
+			a.sum[i] = 0
+		}
+	}
+	return a.iterateLocal(loc)
+}
+
+func (a *AdaGrad) iterateLocal(loc *Location) (Operation, error) {
+	return a.sg.iterate(loc, a.rate, a.update, a.updateSparse), nil
+}
+
+func (*AdaGrad) usesSparseGrad() bool {
+	return true
+}
+
+func (a *AdaGrad) updateSparse(x []float64, grad *SparseGradient, rate float64) {
+	for k, i := range grad.Index {
+		g := grad.Value[k]
+		a.sum[i] += g * g
+		x[i] -= rate * g / (math.Sqrt(a.sum[i]) + a.eps)
+	}
+}
+
+func (a *AdaGrad) update(x, grad []float64, rate float64) {
+	for i, g := range grad {
+		a.sum[i] += g * g
+		x[i] -= rate * g / (math.Sqrt(a.sum[i]) + a.eps)
+	}
+}
+
+// MarshalState returns the state of the iterations and of the accumulated
+// squared gradients. It implements the Checkpointer interface.
+func (a *AdaGrad) MarshalState() ([]byte, error) {
+	return a.sg.marshalState(nil, a.sum)
+}
+
+// UnmarshalState restores the state returned by MarshalState. It implements
+// the Checkpointer interface.
+func (a *AdaGrad) UnmarshalState(data []byte) error {
+	st, err := a.sg.unmarshalState(data, 0, 1)
+	if err != nil || !st.Started {
+		return err
+	}
+	a.sum = st.Vectors[0]
+	return nil
+}
+
+func (*AdaGrad) needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return stochasticNeeds()
+}
+
+// RMSProp implements the root mean square propagation method of Hinton,
+// which scales the step in each variable by a running average of the squares
+// of the gradient,
+//  s_{k+1} = ρ s_k + (1-ρ) g_k²,
+//  x_{k+1} = x_k - α_k g_k / (√s_{k+1} + ε),
+// where ρ is Decay. Unlike in AdaGrad, the steps do not vanish as the
+// iterations proceed. See the documentation of SGD for the use of stochastic
+// gradient methods.
+type RMSProp struct {
+	// LearningRate is the base learning rate. If LearningRate is 0,
+	// a default value of 0.001 is used.
+	LearningRate float64
+	// Schedule determines the learning rate at each iteration. If Schedule
+	// is nil, the learning rate is constant.
+	Schedule LearningRateSchedule
+	// Decay is the decay rate of the running average in [0, 1). If Decay
+	// is 0, a default value of 0.9 is used.
+	Decay float64
+	// Epsilon prevents division by zero. If Epsilon is 0, a default value
+	// of 1e-8 is used.
+	Epsilon float64
+
+	sg    stochasticGradient
+	rate  float64
+	decay float64
+	eps   float64
+	avg   []float64
+
+	status Status
+	err    error
+}
+
+func (*RMSProp) stochastic() {}
+
+func (r *RMSProp) Status() (Status, error) {
+	return r.status, r.err
+}
+
+func (*RMSProp) Uses(has Available) (uses Available, err error) {
+	return has.gradient()
+}
+
+func (r *RMSProp) Init(dim, tasks int) int {
+	r.status = NotTerminated
+	r.err = nil
+	r.sg.started = false
+	return 1
+}
+
+func (r *RMSProp) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	r.status, r.err = localOptimizer{}.run(r, math.NaN(), operation, result, tasks)
+	close(operation)
+}
+
+func (r *RMSProp) initLocal(loc *Location) (Operation, error) {
+	r.rate = r.LearningRate
+	if r.rate == 0 {
+		r.rate = 0.001
+	}
+	if r.rate < 0 {
+		panic("optimize: negative learning rate")
+	}
+	r.decay = r.Decay
+	if r.decay == 0 {
+		r.decay = 0.9
+	}
+	if r.decay < 0 || r.decay >= 1 {
+		panic("optimize: decay rate not in [0, 1)")
+	}
+	r.eps = r.Epsilon
+	if r.eps == 0 {
+		r.eps = 1e-8
+	}
+	if r.sg.init(r.Schedule) {
+		checkResume(r.avg, len(loc.X))
+	} else {
+		r.avg = resize(r.avg, len(loc.X))
+		for i := range r.avg {
+			r.avg[i] = 0
+		}
+	}
+	return r.iterateLocal(loc)
+}
+
+func (r *RMSProp) iterateLocal(loc *Location) (Operation, error) {
+	return r.sg.iterate(loc, r.rate, r.update, nil), nil
+}
+
+func (r *RMSProp) update(x, grad []float64, rate float64) {
+	for i, g := range grad {
+		r.avg[i] = r.decay*r.avg[i] + (1-r.decay)*g*g
+		x[i] -= rate * g / (math.Sqrt(r.avg[i]) + r.eps)
+	}
+}
+
+// MarshalState returns the state of the iterations and of the running
+// average of the squared gradients. It implements the Checkpointer interface.
+func (r *RMSProp) MarshalState() ([]byte, error) {
+	return r.sg.marshalState(nil, r.avg)
+}
+
+// UnmarshalState restores the state returned by MarshalState. It implements
+// the Checkpointer interface.
+func (r *RMSProp) UnmarshalState(data []byte) error {
+	st, err := r.sg.unmarshalState(data, 0, 1)
+	if err != nil || !st.Started {
+		return err
+	}
+	r.avg = st.Vectors[0]
+	return nil
+}
+
+func (*RMSProp) needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return stochasticNeeds()
+}
@@ -0,0 +1,109 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// noisyQuadratic returns a Problem whose function and gradient are those of
+//  ½ Σ d_i (x_i - 1)²
+// perturbed by normal noise with standard deviation sigma.
+func noisyQuadratic(d []float64, sigma float64, src rand.Source) Problem {
+	rnd := rand.New(src)
+	return Problem{
+		Func: func(x []float64) float64 {
+			var f float64
+			for i, v := range x {
+				f += 0.5 * d[i] * (v - 1) * (v - 1)
+			}
+			return f + sigma*rnd.NormFloat64()
+		},
+		Grad: func(grad, x []float64) {
+			for i, v := range x {
+				grad[i] = d[i]*(v-1) + sigma*rnd.NormFloat64()
+			}
+		},
+	}
+}
+
+func TestStochasticGradient(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name   string
+		method Method
+	}{
+		{name: "SGD", method: &SGD{LearningRate: 0.1, Schedule: InverseTimeDecay{Decay: 0.01}}},
+		{name: "SGDMomentum", method: &SGD{LearningRate: 0.02, Momentum: 0.9, Schedule: InverseTimeDecay{Decay: 0.01}}},
+		{name: "SGDNesterov", method: &SGD{LearningRate: 0.02, Momentum: 0.9, Nesterov: true, Schedule: InverseTimeDecay{Decay: 0.01}}},
+		{name: "Adam", method: &Adam{LearningRate: 0.05, Schedule: StepDecay{Factor: 0.5, Steps: 500}}},
+		{name: "AdaGrad", method: &AdaGrad{LearningRate: 0.5}},
+		{name: "RMSProp", method: &RMSProp{LearningRate: 0.01, Schedule: ExponentialDecay{Factor: 0.1, Steps: 1000}}},
+	} {
+		d := []float64{1, 2, 5, 10}
+		p := noisyQuadratic(d, 0.1, rand.NewSource(1))
+		const iters = 3000
+		res, err := Minimize(p, []float64{-1, 3, 0, 2}, &Settings{MajorIterations: iters}, test.method)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if res.Status != IterationLimit || res.MajorIterations != iters {
+			t.Errorf("%s: unexpected status %v after %d iterations", test.name, res.Status, res.MajorIterations)
+		}
+		// The initial location is evaluated once, and then once at every
+		// iteration except the first.
+		if res.GradEvaluations != iters {
+			t.Errorf("%s: unexpected number of gradient evaluations: got %d, want %d", test.name, res.GradEvaluations, iters)
+		}
+		if dist := floats.Distance(res.X, []float64{1, 1, 1, 1}, math.Inf(1)); !(dist <= 0.05) {
+			t.Errorf("%s: location far from the minimum: %v", test.name, res.X)
+		}
+	}
+}
+
+func TestStochasticGradientLimit(t *testing.T) {
+	t.Parallel()
+	p := noisyQuadratic([]float64{1}, 0.1, rand.NewSource(1))
+	_, err := Minimize(p, []float64{0}, nil, &Adam{})
+	if _, ok := err.(ErrSetting); !ok {
+		t.Errorf("unexpected error without limits: %v", err)
+	}
+
+	// The gradient threshold must not stop the optimization even if the
+	// noisy gradient is small.
+	p = noisyQuadratic([]float64{1}, 0, rand.NewSource(1))
+	res, err := Minimize(p, []float64{1}, &Settings{GradientThreshold: 1, MajorIterations: 10}, &SGD{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Status != IterationLimit {
+		t.Errorf("unexpected status: got %v, want %v", res.Status, IterationLimit)
+	}
+}
+
+func TestLearningRateSchedule(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		schedule LearningRateSchedule
+		iter     int
+		want     float64
+	}{
+		{schedule: ConstantRate{}, iter: 100, want: 2},
+		{schedule: StepDecay{Factor: 0.5, Steps: 10}, iter: 9, want: 2},
+		{schedule: StepDecay{Factor: 0.5, Steps: 10}, iter: 25, want: 0.5},
+		{schedule: ExponentialDecay{Factor: 0.25, Steps: 10}, iter: 5, want: 1},
+		{schedule: InverseTimeDecay{Decay: 0.1}, iter: 30, want: 0.5},
+	} {
+		if got := test.schedule.LearningRate(2, test.iter); math.Abs(got-test.want) > 1e-14 {
+			t.Errorf("%T: unexpected learning rate at %d: got %v, want %v", test.schedule, test.iter, got, test.want)
+		}
+	}
+}
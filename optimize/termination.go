@@ -25,6 +25,10 @@ const (
 	FunctionEvaluationLimit
 	GradientEvaluationLimit
 	HessianEvaluationLimit
+	Cancelled
+	Stopped
+	EvaluationFailure
+	RelativeGradientThreshold
 )
 
 func (s Status) String() string {
@@ -104,6 +108,24 @@ var statuses = []struct {
 		early: true,
 		err:   errors.New("optimize: maximum number of Hessian evaluations reached"),
 	},
+	{
+		name:  "Cancelled",
+		early: true,
+		err:   errors.New("optimize: optimization cancelled"),
+	},
+	{
+		name:  "Stopped",
+		early: true,
+		err:   errors.New("optimize: optimization stopped by recorder"),
+	},
+	{
+		name:  "EvaluationFailure",
+		early: true,
+		err:   errors.New("optimize: evaluation of the problem failed"),
+	},
+	{
+		name: "RelativeGradientThreshold",
+	},
 }
 
 // NewStatus returns a unique Status variable to represent a custom status.
@@ -0,0 +1,355 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ Method      = (*TrustRegion)(nil)
+	_ localMethod = (*TrustRegion)(nil)
+)
+
+// trustRegionState is the state of a TrustRegion iteration.
+type trustRegionState int
+
+const (
+	trustRegionTrial  trustRegionState = iota // Evaluating the function at a trial step
+	trustRegionAccept                         // Evaluating the derivatives at an accepted step
+	trustRegionMajor                          // Major iteration at an accepted step
+)
+
+// TrustRegion implements a trust-region Newton method for unconstrained
+// minimization. At each iteration it minimizes the quadratic model
+//  m(p) = f + ∇fᵀp + ½ pᵀB p
+// within the trust region |p| ≤ Δ, and accepts the step if the actual
+// reduction of the objective function is a sufficient fraction of the
+// reduction predicted by the model. The radius Δ is decreased after a poor
+// prediction and increased after a good prediction of a step on the boundary
+// of the region. The model is approximately minimized by the dogleg method,
+// see Chapter 4 of
+//  Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer (2006)
+// for more information.
+//
+// If the Problem provides the Hessian, B is the Hessian of the objective
+// function, and otherwise B is approximated by symmetric rank-one (SR1)
+// updates of the gradients. Unlike Newton, TrustRegion does not require B to
+// be positive definite: if B is indefinite, the Newton point of the dogleg
+// path is computed from B with an added multiple of the identity, and the
+// step is never worse than the Cauchy point of the true model, which follows
+// directions of negative curvature to the boundary of the region.
+type TrustRegion struct {
+	// InitRadius is the initial radius of the trust region. If InitRadius
+	// is 0, a default value of 1 is used.
+	InitRadius float64
+	// MaxRadius is the maximum radius of the trust region. If MaxRadius is
+	// 0, the radius is not limited.
+	MaxRadius float64
+	// Eta is the minimum ratio of the actual to the predicted reduction of
+	// the objective function for a step to be accepted. Eta must be in
+	// [0, 0.25), and if Eta is 0, a default value of 1e-4 is used.
+	Eta float64
+	// GradStopThreshold sets the threshold for stopping if the gradient norm
+	// gets too small. If GradStopThreshold is 0 it is defaulted to 1e-12, and
+	// if it is NaN the setting is not used.
+	GradStopThreshold float64
+
+	status Status
+	err    error
+
+	exact  bool // Indicator that B is the exact Hessian
+	first  bool // Indicator that B has not been updated
+	state  trustRegionState
+	radius float64
+	maxRad float64
+	eta    float64
+
+	x    []float64 // Location of the most recent major iteration
+	f    float64
+	grad []float64
+	b    *mat.SymDense // Model Hessian
+	pred float64       // Reduction predicted by the model for the trial step
+
+	p    []float64
+	pU   []float64
+	bp   []float64
+	mod  *mat.SymDense
+	chol mat.Cholesky
+}
+
+func (tr *TrustRegion) Status() (Status, error) {
+	return tr.status, tr.err
+}
+
+func (tr *TrustRegion) Uses(has Available) (uses Available, err error) {
+	tr.exact = has.Hess
+	if has.Hess {
+		return has.hessian()
+	}
+	return has.gradient()
+}
+
+func (tr *TrustRegion) Init(dim, tasks int) int {
+	tr.status = NotTerminated
+	tr.err = nil
+	return 1
+}
+
+func (tr *TrustRegion) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	tr.status, tr.err = localOptimizer{}.run(tr, tr.GradStopThreshold, operation, result, tasks)
+	close(operation)
+}
+
+func (tr *TrustRegion) initLocal(loc *Location) (Operation, error) {
+	tr.radius = tr.InitRadius
+	if tr.radius == 0 {
+		tr.radius = 1
+	}
+	if tr.radius < 0 {
+		panic("trustregion: negative initial radius")
+	}
+	tr.maxRad = tr.MaxRadius
+	if tr.maxRad == 0 {
+		tr.maxRad = math.Inf(1)
+	}
+	if tr.maxRad < tr.radius {
+		panic("trustregion: maximum radius less than initial radius")
+	}
+	tr.eta = tr.Eta
+	if tr.eta == 0 {
+		tr.eta = 1e-4
+	}
+	if tr.eta < 0 || tr.eta >= 0.25 {
+		panic("trustregion: Eta not in [0, 0.25)")
+	}
+
+	dim := len(loc.X)
+	tr.x = resize(tr.x, dim)
+	tr.grad = resize(tr.grad, dim)
+	tr.p = resize(tr.p, dim)
+	tr.pU = resize(tr.pU, dim)
+	tr.bp = resize(tr.bp, dim)
+	tr.b = resizeSymDense(tr.b, dim)
+	tr.mod = resizeSymDense(tr.mod, dim)
+	if !tr.exact {
+		tr.first = true
+		for i := 0; i < dim; i++ {
+			for j := i; j < dim; j++ {
+				tr.b.SetSym(i, j, 0)
+			}
+			tr.b.SetSym(i, i, 1)
+		}
+	}
+	tr.accept(loc)
+	return tr.trial(loc)
+}
+
+func (tr *TrustRegion) iterateLocal(loc *Location) (Operation, error) {
+	switch tr.state {
+	default:
+		panic("trustregion: unknown state")
+	case trustRegionTrial:
+		if !tr.exact && finiteValues(loc) {
+			// The gradient at a trial location is informative about the
+			// curvature even if the step is rejected.
+			tr.updateSR1(loc)
+		}
+		// The reductions are shifted by a multiple of the rounding error
+		// of f so that the ratio tends to 1 rather than to noise when
+		// both are at the level of the rounding error close to
+		// a minimum.
+		delta := 10 * dlamchE * math.Max(1, math.Abs(tr.f))
+		rho := (tr.f - loc.F + delta) / (tr.pred + delta)
+		norm := floats.Norm(tr.p, 2)
+		if rho < 0.25 || math.IsNaN(rho) {
+			tr.radius = 0.25 * math.Min(norm, tr.radius)
+		} else if rho > 0.75 && norm > 0.99*tr.radius {
+			tr.radius = math.Min(2*tr.radius, tr.maxRad)
+		}
+		if rho > tr.eta {
+			if !tr.exact {
+				tr.accept(loc)
+				tr.state = trustRegionMajor
+				return MajorIteration, nil
+			}
+			tr.state = trustRegionAccept
+			return GradEvaluation | HessEvaluation, nil
+		}
+		if tr.radius <= 1e-16*(1+floats.Norm(tr.x, 2)) {
+			// Restore the location of the last major iteration.
+			copy(loc.X, tr.x)
+			loc.F = tr.f
+			copy(loc.Gradient, tr.grad)
+			return NoOperation, ErrNoProgress
+		}
+		return tr.trial(loc)
+	case trustRegionAccept:
+		tr.accept(loc)
+		tr.state = trustRegionMajor
+		return MajorIteration, nil
+	case trustRegionMajor:
+		return tr.trial(loc)
+	}
+}
+
+// accept stores the location of a major iteration.
+func (tr *TrustRegion) accept(loc *Location) {
+	copy(tr.x, loc.X)
+	tr.f = loc.F
+	copy(tr.grad, loc.Gradient)
+	if tr.exact {
+		tr.b.CopySym(loc.Hessian)
+	}
+}
+
+// updateSR1 updates the model Hessian with the trial step tr.p and the
+// gradient at the trial location loc.X.
+func (tr *TrustRegion) updateSR1(loc *Location) {
+	dim := len(tr.x)
+	s := tr.p
+	// v = y - B s, where y is the change of the gradient.
+	v := tr.pU
+	floats.SubTo(v, loc.Gradient, tr.grad)
+	if tr.first {
+		// Scale the initial identity matrix to the curvature along
+		// the first step as in (6.20) of Nocedal and Wright.
+		tr.first = false
+		if ys := floats.Dot(v, s); ys > 0 {
+			tr.b.ScaleSym(floats.Dot(v, v)/ys, tr.b)
+		}
+	}
+	bs := mat.NewVecDense(dim, tr.bp)
+	bs.MulVec(tr.b, mat.NewVecDense(dim, s))
+	floats.Sub(v, tr.bp)
+	vs := floats.Dot(v, s)
+	// Skip the update if the denominator is too small, which includes the
+	// case that B already satisfies the secant condition.
+	if math.Abs(vs) <= 1e-8*floats.Norm(s, 2)*floats.Norm(v, 2) {
+		return
+	}
+	tr.b.SymRankOne(tr.b, 1/vs, mat.NewVecDense(dim, v))
+}
+
+// trial computes the dogleg step within the trust region, sets the trial
+// location in loc, and returns the evaluation of the function, and of the
+// gradient if B is approximated.
+func (tr *TrustRegion) trial(loc *Location) (Operation, error) {
+	tr.dogleg()
+	floats.AddTo(loc.X, tr.x, tr.p)
+	tr.state = trustRegionTrial
+	if tr.exact {
+		return FuncEvaluation, nil
+	}
+	return FuncEvaluation | GradEvaluation, nil
+}
+
+// model returns the reduction of the quadratic model for the step p.
+func (tr *TrustRegion) model(p []float64) float64 {
+	dim := len(p)
+	bp := mat.NewVecDense(dim, tr.bp)
+	bp.MulVec(tr.b, mat.NewVecDense(dim, p))
+	return -floats.Dot(tr.grad, p) - 0.5*floats.Dot(p, tr.bp)
+}
+
+// dogleg stores in tr.p the dogleg step and in tr.pred its predicted
+// reduction.
+func (tr *TrustRegion) dogleg() {
+	dim := len(tr.x)
+	g := mat.NewVecDense(dim, tr.grad)
+	gnorm := floats.Norm(tr.grad, 2)
+	delta := tr.radius
+
+	// The Cauchy point minimizes the model along the steepest descent
+	// direction within the trust region.
+	gBg := mat.Inner(g, tr.b, g)
+	cauchy := tr.pU
+	var unconstrained bool
+	if gBg <= 0 {
+		floats.ScaleTo(cauchy, -delta/gnorm, tr.grad)
+	} else {
+		t := gnorm * gnorm / gBg
+		if t*gnorm >= delta {
+			floats.ScaleTo(cauchy, -delta/gnorm, tr.grad)
+		} else {
+			floats.ScaleTo(cauchy, -t, tr.grad)
+			unconstrained = true
+		}
+	}
+
+	// The Newton point minimizes the model of the positive definite
+	// modification of B.
+	tr.mod.CopySym(tr.b)
+	var tau float64
+	for k := 0; !tr.chol.Factorize(tr.mod); k++ {
+		if k == maxNewtonModifications {
+			// The modification failed, use the Cauchy point.
+			copy(tr.p, cauchy)
+			tr.pred = tr.model(tr.p)
+			return
+		}
+		tau = math.Max(5*tau, 1e-3*math.Max(1, floats.Norm(tr.b.RawSymmetric().Data, math.Inf(1))))
+		for i := 0; i < dim; i++ {
+			tr.mod.SetSym(i, i, tr.b.At(i, i)+tau)
+		}
+	}
+	pB := mat.NewVecDense(dim, tr.p)
+	err := tr.chol.SolveVecTo(pB, g)
+	if err != nil && !unconstrained {
+		copy(tr.p, cauchy)
+		tr.pred = tr.model(tr.p)
+		return
+	}
+	pB.ScaleVec(-1, pB)
+
+	switch {
+	case floats.Norm(tr.p, 2) <= delta:
+		// The Newton point lies within the trust region.
+	case !unconstrained:
+		copy(tr.p, cauchy)
+	default:
+		// Find the intersection of the segment from the Cauchy point to
+		// the Newton point with the boundary of the trust region.
+		d := tr.p
+		floats.Sub(d, cauchy)
+		a := floats.Dot(d, d)
+		b := floats.Dot(cauchy, d)
+		c := floats.Dot(cauchy, cauchy) - delta*delta
+		t := (-b + math.Sqrt(b*b-a*c)) / a
+		floats.AddScaledTo(tr.p, cauchy, t, d)
+	}
+	tr.pred = tr.model(tr.p)
+
+	// Fall back to the Cauchy point if the modification of B made the
+	// dogleg step worse.
+	if tau > 0 {
+		if predC := tr.model(cauchy); predC > tr.pred {
+			copy(tr.p, cauchy)
+			tr.pred = predC
+		}
+	}
+}
+
+func (tr *TrustRegion) needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return struct {
+		Gradient bool
+		Hessian  bool
+	}{true, tr.exact}
+}
+
+// finiteValues returns whether the function value and the gradient in loc are
+// finite.
+func finiteValues(loc *Location) bool {
+	if math.IsInf(loc.F, 0) || math.IsNaN(loc.F) || floats.HasNaN(loc.Gradient) {
+		return false
+	}
+	return !math.IsInf(floats.Norm(loc.Gradient, math.Inf(1)), 0)
+}
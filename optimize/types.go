@@ -6,8 +6,10 @@ package optimize
 
 import (
 	"fmt"
+	"math"
 	"time"
 
+	"gonum.org/v1/gonum/diff/fd"
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -91,6 +93,45 @@ type Result struct {
 	Location
 	Stats
 	Status Status
+
+	// InitialValue is the value of the objective function at the initial
+	// location, either supplied by Settings.InitValues or evaluated during
+	// the run. InitialValue is NaN if the objective function was never
+	// evaluated at the initial location.
+	InitialValue float64
+
+	// Multipliers holds the Lagrange multipliers of the constraints of the
+	// Problem at the final location. Multipliers is nil if the Method does
+	// not implement MultiplierReporter.
+	Multipliers *Multipliers
+
+	// Complementarity is the largest product of the magnitude of an
+	// inequality constraint or bound of the Problem at the final location
+	// and of its multiplier. Complementarity vanishes at a constrained
+	// minimum, and it is zero if Multipliers is nil.
+	Complementarity float64
+
+	// InverseHessian is the approximation of the inverse Hessian of the
+	// objective function at the final location maintained by quasi-Newton
+	// Methods such as BFGS and LBFGS. If the objective function is the
+	// negative log-likelihood of a model, the square roots of the diagonal
+	// elements of InverseHessian estimate the standard errors of the
+	// parameters, although quasi-Newton approximations may be inaccurate in
+	// directions that the optimization has not explored. InverseHessian is
+	// nil unless Settings.InverseHessian is true, and it is nil if the Method
+	// does not implement InverseHessianReporter or has no approximation.
+	InverseHessian *mat.SymDense
+
+	// History is the trajectory of the optimization if Settings.History is
+	// true, and nil otherwise.
+	History *History
+
+	// MethodState is the warm-start state of the Method at the end of the
+	// run, which can be passed in Settings.MethodState to warm-start the
+	// optimization of a similar problem. MethodState is nil unless
+	// Settings.ReturnMethodState is true, and it is nil if the Method does
+	// not implement WarmStarter.
+	MethodState []byte
 }
 
 // Stats contains the statistics of the run.
@@ -100,6 +141,11 @@ type Stats struct {
 	GradEvaluations int           // Number of evaluations of Grad
 	HessEvaluations int           // Number of evaluations of Hess
 	Runtime         time.Duration // Total runtime of the optimization
+
+	// ConstraintViolation is the largest violation of the bound, linear
+	// and nonlinear constraints of the Problem at the optimum location
+	// of the last major iteration. It is zero for unconstrained problems.
+	ConstraintViolation float64
 }
 
 // complementEval returns an evaluating operation that evaluates fields of loc
@@ -127,10 +173,58 @@ type Problem struct {
 	// be the same length as x. Grad must not modify x.
 	Grad func(grad, x []float64)
 
+	// SparseGrad evaluates the gradient at x in sparse form and stores the
+	// result in grad, which has been reset to contain no components.
+	// SparseGrad is only used if Grad is nil. The Methods that use sparse
+	// gradients directly, SGD without momentum and AdaGrad, receive the
+	// gradient in Location.SparseGradient and only update the variables with
+	// non-zero gradient components, which avoids forming and storing a dense
+	// gradient. The rest of the optimization, such as copying the location
+	// between iterations, still takes time proportional to the number of
+	// variables. For other Methods, or if the variables are scaled, the
+	// sparse gradient is expanded to the dense Location.Gradient. SparseGrad
+	// must not modify x.
+	SparseGrad func(grad *SparseGradient, x []float64)
+
 	// Hess evaluates the Hessian at x and stores the result in-place in hess which
 	// will have dimensions matching the length of x. Hess must not modify x.
 	Hess func(hess *mat.SymDense, x []float64)
 
+	// HessVec evaluates the product of the Hessian at x with the vector v
+	// and stores the result in dst, which will be the same length as x.
+	// HessVec allows Methods such as NewtonCG to use second-order
+	// information without forming the Hessian. HessVec must not modify x
+	// and v.
+	HessVec func(dst, x, v []float64)
+
+	// Bounds specifies lower and upper bounds on the variables. If Bounds is
+	// not nil, it must have the same length as the initial location, which must
+	// lie within the bounds, and the Method must support bound constraints by
+	// implementing Bounder. The Min and Max fields of a Bound may be infinite
+	// to leave the variable unbounded from below or from above.
+	Bounds []Bound
+
+	// Project projects x in place onto a closed convex feasible set, for
+	// example a ball or a simplex, that is, it replaces x by the closest
+	// feasible point. If Project is not nil, the Method must support
+	// projections by implementing Projector, and Bounds, Linear and
+	// Nonlinear must be nil. The initial location does not need to be
+	// feasible. BoxProjection, BallProjection and SimplexProjection return
+	// projections onto common feasible sets.
+	Project func(x []float64)
+
+	// Linear specifies linear equality and inequality constraints on the
+	// variables. If Linear is not nil, the initial location must satisfy the
+	// constraints, and the Method must support linear constraints by
+	// implementing LinearConstrainer.
+	Linear *LinearConstraints
+
+	// Nonlinear specifies nonlinear equality and inequality constraints on
+	// the variables. The initial location does not need to satisfy the
+	// nonlinear constraints, and the Method must support nonlinear
+	// constraints by implementing NonlinearConstrainer.
+	Nonlinear *NonlinearConstraints
+
 	// Status reports the status of the objective function being optimized and any
 	// error. This can be used to terminate early, for example when the function is
 	// not able to evaluate itself. The user can use one of the pre-provided Status
@@ -138,40 +232,165 @@ type Problem struct {
 	Status func() (Status, error)
 }
 
-// Available describes the functions available to call in Problem.
+// Bound represents the closed interval [Min, Max] of allowed values of
+// a variable.
+type Bound struct {
+	Min float64
+	Max float64
+}
+
+// Available describes the functions available to call in Problem, and
+// the constraints of the Problem.
 type Available struct {
-	Grad bool
-	Hess bool
+	Grad      bool
+	Hess      bool
+	HessVec   bool
+	Bounds    bool
+	Project   bool
+	Linear    bool
+	Nonlinear bool
 }
 
 func availFromProblem(prob Problem) Available {
-	return Available{Grad: prob.Grad != nil, Hess: prob.Hess != nil}
+	return Available{
+		Grad:      prob.Grad != nil || prob.SparseGrad != nil,
+		Hess:      prob.Hess != nil,
+		HessVec:   prob.HessVec != nil,
+		Bounds:    prob.Bounds != nil,
+		Project:   prob.Project != nil,
+		Linear:    prob.Linear != nil,
+		Nonlinear: prob.Nonlinear != nil,
+	}
+}
+
+// unconstrained returns an error if the Problem described by the receiver
+// has constraints.
+func (has Available) unconstrained() error {
+	if has.Bounds {
+		return ErrUnsupportedBounds
+	}
+	if has.Project {
+		return ErrUnsupportedProjection
+	}
+	if has.Linear {
+		return ErrUnsupportedLinear
+	}
+	if has.Nonlinear {
+		return ErrUnsupportedNonlinear
+	}
+	return nil
 }
 
 // function tests if the Problem described by the receiver is suitable for an
 // unconstrained Method that only calls the function, and returns the result.
 func (has Available) function() (uses Available, err error) {
-	// TODO(btracey): This needs to be modified when optimize supports
-	// constrained optimization.
+	if err := has.unconstrained(); err != nil {
+		return Available{}, err
+	}
 	return Available{}, nil
 }
 
+// boundedFunction tests if the Problem described by the receiver is suitable
+// for a Method that only calls the function and supports bound constraints,
+// and returns the result.
+func (has Available) boundedFunction() (uses Available, err error) {
+	if has.Project {
+		return Available{}, ErrUnsupportedProjection
+	}
+	if has.Linear {
+		return Available{}, ErrUnsupportedLinear
+	}
+	if has.Nonlinear {
+		return Available{}, ErrUnsupportedNonlinear
+	}
+	return Available{Bounds: has.Bounds}, nil
+}
+
 // gradient tests if the Problem described by the receiver is suitable for an
 // unconstrained gradient-based Method, and returns the result.
 func (has Available) gradient() (uses Available, err error) {
-	// TODO(btracey): This needs to be modified when optimize supports
-	// constrained optimization.
+	if err := has.unconstrained(); err != nil {
+		return Available{}, err
+	}
 	if !has.Grad {
 		return Available{}, ErrMissingGrad
 	}
 	return Available{Grad: true}, nil
 }
 
+// boundedGradient tests if the Problem described by the receiver is suitable
+// for a gradient-based Method that supports bound constraints, and returns
+// the result.
+func (has Available) boundedGradient() (uses Available, err error) {
+	if has.Project {
+		return Available{}, ErrUnsupportedProjection
+	}
+	if has.Linear {
+		return Available{}, ErrUnsupportedLinear
+	}
+	if has.Nonlinear {
+		return Available{}, ErrUnsupportedNonlinear
+	}
+	if !has.Grad {
+		return Available{}, ErrMissingGrad
+	}
+	return Available{Grad: true, Bounds: has.Bounds}, nil
+}
+
+// linearGradient tests if the Problem described by the receiver is suitable
+// for a gradient-based Method that supports bound and linear constraints, and
+// returns the result.
+func (has Available) linearGradient() (uses Available, err error) {
+	if has.Project {
+		return Available{}, ErrUnsupportedProjection
+	}
+	if has.Nonlinear {
+		return Available{}, ErrUnsupportedNonlinear
+	}
+	if !has.Grad {
+		return Available{}, ErrMissingGrad
+	}
+	return Available{Grad: true, Bounds: has.Bounds, Linear: has.Linear}, nil
+}
+
+// nonlinearGradient tests if the Problem described by the receiver is
+// suitable for a gradient-based Method that supports bound, linear and
+// nonlinear constraints, and returns the result.
+func (has Available) nonlinearGradient() (uses Available, err error) {
+	if has.Project {
+		return Available{}, ErrUnsupportedProjection
+	}
+	if !has.Grad {
+		return Available{}, ErrMissingGrad
+	}
+	return Available{Grad: true, Bounds: has.Bounds, Linear: has.Linear, Nonlinear: has.Nonlinear}, nil
+}
+
+// projectedGradient tests if the Problem described by the receiver is
+// suitable for a gradient-based Method that supports either bound constraints
+// or a projection, and returns the result.
+func (has Available) projectedGradient() (uses Available, err error) {
+	if has.Bounds && has.Project {
+		return Available{}, ErrUnsupportedBounds
+	}
+	if has.Linear {
+		return Available{}, ErrUnsupportedLinear
+	}
+	if has.Nonlinear {
+		return Available{}, ErrUnsupportedNonlinear
+	}
+	if !has.Grad {
+		return Available{}, ErrMissingGrad
+	}
+	return Available{Grad: true, Bounds: has.Bounds, Project: has.Project}, nil
+}
+
 // hessian tests if the Problem described by the receiver is suitable for an
 // unconstrained Hessian-based Method, and returns the result.
 func (has Available) hessian() (uses Available, err error) {
-	// TODO(btracey): This needs to be modified when optimize supports
-	// constrained optimization.
+	if err := has.unconstrained(); err != nil {
+		return Available{}, err
+	}
 	if !has.Grad {
 		return Available{}, ErrMissingGrad
 	}
@@ -181,6 +400,22 @@ func (has Available) hessian() (uses Available, err error) {
 	return Available{Grad: true, Hess: true}, nil
 }
 
+// hessVec tests if the Problem described by the receiver is suitable for an
+// unconstrained Method based on Hessian-vector products, and returns the
+// result.
+func (has Available) hessVec() (uses Available, err error) {
+	if err := has.unconstrained(); err != nil {
+		return Available{}, err
+	}
+	if !has.Grad {
+		return Available{}, ErrMissingGrad
+	}
+	if !has.HessVec {
+		return Available{}, ErrMissingHessVec
+	}
+	return Available{Grad: true, HessVec: true}, nil
+}
+
 // Settings represents settings of the optimization run. It contains initial
 // settings, convergence information, and Recorder information. Convergence
 // settings are only checked at MajorIterations, while Evaluation thresholds
@@ -200,8 +435,23 @@ type Settings struct {
 	// the gradient, and so to fully disable this setting the Method may need to
 	// be modified.
 	// This setting has no effect if the gradient is not used by the Method.
+	// If the Problem has bounds, the norm of the projected gradient
+	// P(x - ∇f(x)) - x, where P is the projection onto the bounds, is used
+	// instead of the norm of the gradient.
+	// If the Problem has linear or nonlinear constraints, the norm of the
+	// gradient is used, which in general does not vanish at a constrained
+	// minimum, and the convergence is determined by the Method.
 	GradientThreshold float64
 
+	// RelGradientThreshold stops optimization with RelativeGradientThreshold
+	// status if the infinity norm of the gradient is less than or equal to
+	// this value times the infinity norm of the gradient at the initial
+	// location. The check is combined with GradientThreshold, so that either
+	// criterion may stop the optimization. If RelGradientThreshold is 0, the
+	// relative gradient norm is not checked.
+	// This setting has no effect if the gradient is not used by the Method.
+	RelGradientThreshold float64
+
 	// Converger checks if the optimization has converged based on the (history
 	// of) locations found during the optimization. Minimize will pass the
 	// Location at every MajorIteration to the Converger.
@@ -212,7 +462,15 @@ type Settings struct {
 	//		Iterations: 100,
 	//  }
 	// will be used. NeverTerminated can be used to always return a
-	// NotTerminated status.
+	// NotTerminated status. A Converger that implements StatsConverger also
+	// receives the Stats of the optimization, and several criteria, such as
+	// FunctionConverge and StepConverge, can be combined with Convergers.
+	//
+	// For the stochastic gradient methods SGD, Adam, AdaGrad and RMSProp,
+	// the default Converger is NeverTerminate, GradientThreshold and
+	// RelGradientThreshold are ignored, and Minimize returns an ErrSetting
+	// if neither the iterations, the evaluations nor the runtime are
+	// limited.
 	Converger Converger
 
 	// MajorIterations is the maximum number of iterations allowed.
@@ -250,9 +508,115 @@ type Settings struct {
 	// The default value is 0.
 	HessEvaluations int
 
+	// Recorder records the progress of the optimization. If the Recorder
+	// returns ErrStop at a MajorIteration, the optimization is stopped with
+	// the Stopped status, and any other error stops it with the Failure
+	// status.
 	Recorder Recorder
 
+	// History specifies that the function value, the gradient norm, the step
+	// length and the number of evaluations at every major iteration are
+	// retained and returned in Result.History. The trajectory is recorded
+	// in addition to the Recorder, and it needs memory proportional to the
+	// number of major iterations.
+	History bool
+
+	// InverseHessian specifies that the approximation of the inverse Hessian
+	// maintained by a Method that implements InverseHessianReporter is
+	// returned in Result.InverseHessian. The approximation is a dense n×n
+	// matrix, which for LBFGS must be formed from its limited-memory history
+	// at a cost of O(n²) memory and time, so it is only computed if requested.
+	InverseHessian bool
+
+	// FDGradient, if not nil, enables the approximation of the gradient by
+	// finite differences of Problem.Func when Problem.Grad is nil. This
+	// allows gradient-based Methods to be used for problems where only the
+	// objective function is available. If the Formula of FDGradient is not
+	// specified, the central difference formula is used with its default
+	// step size, which can be overridden by setting FDGradient.Step. The
+	// evaluations of Func performed for the approximation are included in
+	// Stats.FuncEvaluations. OriginKnown and OriginValue are ignored.
+	//
+	// If the method passed to Minimize is nil, the default Method is chosen
+	// as if Problem.Grad was specified.
+	//
+	// If FDGradient is nil, Problem.Grad is nil and the Method needs the
+	// gradient, the gradient is approximated by the central difference
+	// formula with its default step, as if FDGradient was &fd.Settings{}.
+	FDGradient *fd.Settings
+
+	// MethodState, if not nil, is the warm-start state of a previous run
+	// from Result.MethodState, which is passed to the SetWarmState method of
+	// the Method before the optimization. For example, the quasi-Newton
+	// methods start with the approximation of the Hessian of the previous
+	// run instead of a multiple of the identity. If MethodState is not nil
+	// and the Method does not implement WarmStarter, Minimize returns
+	// ErrNotWarmStarter.
+	MethodState []byte
+
+	// ReturnMethodState specifies that the warm-start state of a Method that
+	// implements WarmStarter is returned in Result.MethodState at the end of
+	// the run. The state of the quasi-Newton methods holds their Hessian
+	// approximation, which for BFGS has n² elements, so it is only encoded
+	// if requested.
+	ReturnMethodState bool
+
+	// FunctionNoise is an estimate of the absolute noise in the values of
+	// Problem.Func, for example the standard deviation of the results of a
+	// stochastic simulation. It is passed to Methods that implement
+	// NoiseTolerant, which relax their tests of sufficient decrease by twice
+	// FunctionNoise, so that the noise does not cause spurious line search
+	// failures. Other Methods ignore FunctionNoise. ImplicitFiltering is
+	// designed for noisy functions. The default value is 0.
+	FunctionNoise float64
+
+	// Scale, if not nil, holds positive scale factors of the variables for
+	// problems whose variables differ greatly in magnitude or sensitivity.
+	// The Method then minimizes with respect to the scaled variables
+	//  z_i = x_i / Scale[i],
+	// which should be of similar magnitude near the minimum, and it sees the
+	// gradient of the Problem multiplied elementwise by Scale. The bounds of
+	// the Problem are scaled accordingly.
+	//
+	// The Locations passed to the Recorder, InitValues and the Location of
+	// the Result are with respect to the variables of the Problem, while
+	// GradientThreshold, RelGradientThreshold and the Converger apply to the
+	// scaled variables. Scale cannot be used with Problem.Project.
+	Scale []float64
+
+	// Preconditioner, if not nil, is an invertible dim×dim matrix T, and the
+	// Method minimizes with respect to the variables z of the change of
+	// variables x = T z as described for Scale. The Method sees the gradient
+	// Tᵀ∇f(x) and the Hessian Tᵀ∇²f(x)T, so a gradient step in z is the
+	// gradient step in x preconditioned by T Tᵀ, which should approximate the
+	// inverse Hessian of the Problem. Preconditioner cannot be combined with
+	// Scale, Problem.Bounds or Problem.Project.
+	Preconditioner mat.Matrix
+
+	// EvaluationTimeout is the maximum duration of a single evaluation of
+	// Func, Grad or Hess of the Problem. If an evaluation takes longer, the
+	// optimization is stopped with the EvaluationFailure status and
+	// ErrEvaluationTimeout. The evaluation that timed out cannot be
+	// interrupted and continues in the background, but its results are
+	// discarded. If EvaluationTimeout is 0, the duration is not limited.
+	EvaluationTimeout time.Duration
+
+	// RecoverPanics specifies that panics in Func, Grad or Hess of the
+	// Problem are recovered, and the optimization is stopped with the
+	// EvaluationFailure status and an ErrEvaluationPanic instead of crashing
+	// the program. Panics in goroutines started by these functions are not
+	// recovered.
+	RecoverPanics bool
+
 	// Concurrent represents how many concurrent evaluations are possible.
+	// Methods that evaluate several locations per iteration, such as
+	// GuessAndCheck, CmaEsChol, DifferentialEvolution and ParticleSwarm,
+	// dispatch the evaluations to up to Concurrent goroutines, while the
+	// local Methods evaluate one location at a time. If Concurrent is larger
+	// than one and FDGradient is used, the evaluations of Func for the
+	// finite difference approximation of each gradient are also performed
+	// concurrently. Func, Grad and Hess must then be safe for concurrent use.
+	// If Concurrent is 0, the evaluations are sequential.
 	Concurrent int
 }
 
@@ -271,3 +635,73 @@ func resizeSymDense(m *mat.SymDense, dim int) *mat.SymDense {
 	}
 	return mat.NewSymDense(dim, m.RawSymmetric().Data[:dim*dim])
 }
+
+// Validate checks that the values of the fields of s are consistent, and
+// returns an ErrSetting naming the first invalid field found. Validate is
+// called by Minimize before the optimization is started.
+func (s *Settings) Validate() error {
+	if iv := s.InitValues; iv != nil {
+		if iv.X != nil {
+			return ErrSetting{Field: "InitValues.X", Reason: "location must not be specified"}
+		}
+		if iv.Gradient != nil && iv.Hessian != nil && len(iv.Gradient) != iv.Hessian.Symmetric() {
+			return ErrSetting{Field: "InitValues.Hessian", Reason: "dimension does not match the length of InitValues.Gradient"}
+		}
+	}
+	for _, v := range []struct {
+		field string
+		value float64
+	}{
+		{"GradientThreshold", s.GradientThreshold},
+		{"RelGradientThreshold", s.RelGradientThreshold},
+		{"FDGradient.Step", fdStep(s)},
+		{"FunctionNoise", s.FunctionNoise},
+	} {
+		if v.value < 0 || math.IsNaN(v.value) {
+			return ErrSetting{Field: v.field, Reason: fmt.Sprintf("value %v is negative or NaN", v.value)}
+		}
+	}
+	for _, v := range []struct {
+		field string
+		value int
+	}{
+		{"MajorIterations", s.MajorIterations},
+		{"FuncEvaluations", s.FuncEvaluations},
+		{"GradEvaluations", s.GradEvaluations},
+		{"HessEvaluations", s.HessEvaluations},
+		{"Concurrent", s.Concurrent},
+	} {
+		if v.value < 0 {
+			return ErrSetting{Field: v.field, Reason: fmt.Sprintf("value %v is negative", v.value)}
+		}
+	}
+	for _, v := range s.Scale {
+		if !(v > 0) || math.IsInf(v, 1) {
+			return ErrSetting{Field: "Scale", Reason: fmt.Sprintf("value %v is not positive and finite", v)}
+		}
+	}
+	if s.Runtime < 0 {
+		return ErrSetting{Field: "Runtime", Reason: fmt.Sprintf("value %v is negative", s.Runtime)}
+	}
+	if s.EvaluationTimeout < 0 {
+		return ErrSetting{Field: "EvaluationTimeout", Reason: fmt.Sprintf("value %v is negative", s.EvaluationTimeout)}
+	}
+	if fc, ok := s.Converger.(*FunctionConverge); ok {
+		switch {
+		case fc.Absolute < 0 || math.IsNaN(fc.Absolute):
+			return ErrSetting{Field: "Converger.Absolute", Reason: fmt.Sprintf("value %v is negative or NaN", fc.Absolute)}
+		case fc.Relative < 0 || math.IsNaN(fc.Relative):
+			return ErrSetting{Field: "Converger.Relative", Reason: fmt.Sprintf("value %v is negative or NaN", fc.Relative)}
+		case fc.Iterations < 0:
+			return ErrSetting{Field: "Converger.Iterations", Reason: fmt.Sprintf("value %v is negative", fc.Iterations)}
+		}
+	}
+	return nil
+}
+
+func fdStep(s *Settings) float64 {
+	if s.FDGradient == nil {
+		return 0
+	}
+	return s.FDGradient.Step
+}
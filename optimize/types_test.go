@@ -0,0 +1,528 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestSettingsValidate(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		settings Settings
+		field    string
+	}{
+		{settings: Settings{}},
+		{
+			settings: Settings{
+				InitValues: &Location{
+					F:        1,
+					Gradient: make([]float64, 2),
+					Hessian:  mat.NewSymDense(2, nil),
+				},
+				GradientThreshold: 1e-6,
+				Converger:         defaultFunctionConverge(),
+				MajorIterations:   10,
+				Runtime:           time.Second,
+				FuncEvaluations:   10,
+				GradEvaluations:   10,
+				HessEvaluations:   10,
+				FDGradient:        &fd.Settings{Step: 1e-6},
+				Concurrent:        2,
+			},
+		},
+		{settings: Settings{InitValues: &Location{X: []float64{1}}}, field: "InitValues.X"},
+		{
+			settings: Settings{InitValues: &Location{
+				Gradient: make([]float64, 2),
+				Hessian:  mat.NewSymDense(3, nil),
+			}},
+			field: "InitValues.Hessian",
+		},
+		{settings: Settings{GradientThreshold: -1}, field: "GradientThreshold"},
+		{settings: Settings{GradientThreshold: math.NaN()}, field: "GradientThreshold"},
+		{settings: Settings{FDGradient: &fd.Settings{Step: -1}}, field: "FDGradient.Step"},
+		{settings: Settings{MajorIterations: -1}, field: "MajorIterations"},
+		{settings: Settings{FuncEvaluations: -1}, field: "FuncEvaluations"},
+		{settings: Settings{GradEvaluations: -1}, field: "GradEvaluations"},
+		{settings: Settings{HessEvaluations: -1}, field: "HessEvaluations"},
+		{settings: Settings{Concurrent: -1}, field: "Concurrent"},
+		{settings: Settings{Runtime: -time.Second}, field: "Runtime"},
+		{settings: Settings{EvaluationTimeout: -time.Second}, field: "EvaluationTimeout"},
+		{settings: Settings{FunctionNoise: -1}, field: "FunctionNoise"},
+		{settings: Settings{Scale: []float64{1, -1}}, field: "Scale"},
+		{settings: Settings{Converger: &FunctionConverge{Absolute: -1}}, field: "Converger.Absolute"},
+		{settings: Settings{Converger: &FunctionConverge{Relative: -1}}, field: "Converger.Relative"},
+		{settings: Settings{Converger: &FunctionConverge{Iterations: -1}}, field: "Converger.Iterations"},
+	} {
+		err := test.settings.Validate()
+		if test.field == "" {
+			if err != nil {
+				t.Errorf("unexpected error for valid settings: %v", err)
+			}
+			continue
+		}
+		e, ok := err.(ErrSetting)
+		if !ok {
+			t.Errorf("unexpected error for invalid %s: %v", test.field, err)
+			continue
+		}
+		if e.Field != test.field {
+			t.Errorf("unexpected invalid field: got %s, want %s", e.Field, test.field)
+		}
+	}
+}
+
+func TestMinimizeInvalidSettings(t *testing.T) {
+	t.Parallel()
+	p := Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+		Grad: functions.ExtendedRosenbrock{}.Grad,
+	}
+	result, err := Minimize(p, []float64{-1.2, 1}, &Settings{GradientThreshold: -1}, nil)
+	if _, ok := err.(ErrSetting); !ok {
+		t.Errorf("unexpected error: got %v, want ErrSetting", err)
+	}
+	if result != nil {
+		t.Errorf("unexpected non-nil result")
+	}
+}
+
+func TestMinimizeContext(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	const cancelAt = 50
+	var evals int
+	p := Problem{
+		Func: func(x []float64) float64 {
+			evals++
+			if evals == cancelAt {
+				cancel()
+			}
+			return functions.ExtendedRosenbrock{}.Func(x)
+		},
+	}
+	result, err := MinimizeContext(ctx, p, []float64{-1.2, 1}, nil, &NelderMead{})
+	if err != context.Canceled {
+		t.Errorf("unexpected error: got %v, want %v", err, context.Canceled)
+	}
+	if result == nil {
+		t.Fatal("unexpected nil result")
+	}
+	if result.Status != Cancelled {
+		t.Errorf("unexpected status: got %v, want %v", result.Status, Cancelled)
+	}
+	if result.FuncEvaluations != cancelAt {
+		t.Errorf("unexpected number of evaluations: got %d, want %d", result.FuncEvaluations, cancelAt)
+	}
+	if math.IsInf(result.F, 1) {
+		t.Error("no location of a major iteration in the result")
+	}
+
+	// An optimization with a done context is not started.
+	evals = 0
+	result, err = MinimizeContext(ctx, p, []float64{-1.2, 1}, nil, &NelderMead{})
+	if err != context.Canceled || result != nil || evals != 0 {
+		t.Errorf("unexpected result with done context: %v, %v after %d evaluations", result, err, evals)
+	}
+}
+
+func TestRelGradientThreshold(t *testing.T) {
+	t.Parallel()
+	// A badly scaled quadratic for which the gradient norm at the initial
+	// location is of order 1e12. An absolute threshold of 1e-6 is out of
+	// reach in floating point arithmetic.
+	const scale = 1e12
+	p := Problem{
+		Func: func(x []float64) float64 {
+			return scale * 0.5 * (x[0]*x[0] + 100*x[1]*x[1])
+		},
+		Grad: func(grad, x []float64) {
+			grad[0] = scale * x[0]
+			grad[1] = scale * 100 * x[1]
+		},
+	}
+	x0 := []float64{1, 1}
+	initNorm := 100 * scale
+
+	abs, err := Minimize(p, x0, &Settings{
+		GradientThreshold: 1e-6,
+		Converger:         NeverTerminate{},
+		MajorIterations:   1000,
+	}, &GradientDescent{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if abs.Status == GradientThreshold {
+		t.Errorf("unexpected status with absolute threshold: got %v", abs.Status)
+	}
+
+	const relThresh = 1e-3
+	rel, err := Minimize(p, x0, &Settings{
+		GradientThreshold:    1e-6,
+		RelGradientThreshold: relThresh,
+		Converger:            NeverTerminate{},
+		MajorIterations:      1000,
+	}, &GradientDescent{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rel.Status != RelativeGradientThreshold {
+		t.Fatalf("unexpected status with relative threshold: got %v, want %v", rel.Status, RelativeGradientThreshold)
+	}
+	if norm := floats.Norm(rel.Gradient, math.Inf(1)); norm > relThresh*initNorm {
+		t.Errorf("gradient norm not below relative threshold: got %v, want <= %v", norm, relThresh*initNorm)
+	}
+	if rel.MajorIterations >= abs.MajorIterations {
+		t.Errorf("relative threshold did not stop the run early: %d >= %d major iterations", rel.MajorIterations, abs.MajorIterations)
+	}
+}
+
+func TestMinimizeWarmStart(t *testing.T) {
+	t.Parallel()
+	// A sequence of badly scaled quadratics whose minimizers differ only by
+	// a shift share the Hessian, so that a warm-started quasi-Newton method
+	// needs fewer iterations than a cold start.
+	const dim = 10
+	rnd := rand.New(rand.NewSource(1))
+	a := mat.NewSymDense(dim, nil)
+	for i := 0; i < dim; i++ {
+		a.SetSym(i, i, math.Pow(10, 2*float64(i)/(dim-1)))
+		for j := i + 1; j < dim; j++ {
+			a.SetSym(i, j, 0.1*rnd.NormFloat64())
+		}
+	}
+	quadratic := func(shift float64) Problem {
+		c := make([]float64, dim)
+		for i := range c {
+			c[i] = shift * float64(i+1)
+		}
+		d := make([]float64, dim)
+		return Problem{
+			Func: func(x []float64) float64 {
+				floats.SubTo(d, x, c)
+				dv := mat.NewVecDense(dim, d)
+				return 0.5 * mat.Inner(dv, a, dv)
+			},
+			Grad: func(grad, x []float64) {
+				floats.SubTo(d, x, c)
+				mat.NewVecDense(dim, grad).MulVec(a, mat.NewVecDense(dim, d))
+			},
+		}
+	}
+	x0 := make([]float64, dim)
+	for _, test := range []struct {
+		name   string
+		method func() Method
+	}{
+		{"BFGS", func() Method { return &BFGS{} }},
+		{"LBFGS", func() Method { return &LBFGS{} }},
+	} {
+		first, err := Minimize(quadratic(1), x0, nil, test.method())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if first.MethodState != nil {
+			t.Errorf("%s: unexpected method state when not requested", test.name)
+		}
+		first, err = Minimize(quadratic(1), x0, &Settings{ReturnMethodState: true}, test.method())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if first.MethodState == nil {
+			t.Fatalf("%s: no method state in result", test.name)
+		}
+
+		cold, err := Minimize(quadratic(1.1), x0, nil, test.method())
+		if err != nil {
+			t.Fatalf("%s: unexpected error for cold start: %v", test.name, err)
+		}
+		warm, err := Minimize(quadratic(1.1), x0, &Settings{MethodState: first.MethodState}, test.method())
+		if err != nil {
+			t.Fatalf("%s: unexpected error for warm start: %v", test.name, err)
+		}
+		if warm.Status != GradientThreshold {
+			t.Errorf("%s: unexpected status for warm start: %v", test.name, warm.Status)
+		}
+		if !floats.EqualApprox(warm.X, cold.X, 1e-6) {
+			t.Errorf("%s: warm start found a different minimum: got %v, want %v", test.name, warm.X, cold.X)
+		}
+		if warm.MajorIterations >= cold.MajorIterations {
+			t.Errorf("%s: warm start did not reduce the iterations: %d warm, %d cold",
+				test.name, warm.MajorIterations, cold.MajorIterations)
+		}
+	}
+
+	_, err := Minimize(quadratic(1), x0, &Settings{MethodState: []byte{}}, &GradientDescent{})
+	if err != ErrNotWarmStarter {
+		t.Errorf("unexpected error for method without warm start: got %v, want %v", err, ErrNotWarmStarter)
+	}
+}
+
+// iterationConverger converges after a number of major iterations.
+type iterationConverger struct {
+	iters int
+}
+
+func (iterationConverger) Init(dim int) {}
+
+func (iterationConverger) Converged(loc *Location) Status { return NotTerminated }
+
+func (c iterationConverger) ConvergedStats(loc *Location, stats *Stats) Status {
+	if stats.MajorIterations >= c.iters {
+		return MethodConverge
+	}
+	return NotTerminated
+}
+
+func TestConvergers(t *testing.T) {
+	t.Parallel()
+	p := Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+		Grad: functions.ExtendedRosenbrock{}.Grad,
+	}
+	x0 := []float64{-1.2, 1}
+
+	settings := &Settings{
+		Converger: Convergers{&FunctionConverge{Iterations: 1000}, iterationConverger{iters: 7}},
+	}
+	result, err := Minimize(p, x0, settings, &BFGS{GradStopThreshold: math.NaN()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != MethodConverge || result.MajorIterations != 7 {
+		t.Errorf("unexpected result of StatsConverger: status %v after %d iterations", result.Status, result.MajorIterations)
+	}
+
+	settings = &Settings{
+		Converger: &StepConverge{Absolute: 1e-8, Iterations: 2},
+	}
+	result, err = Minimize(p, x0, settings, &BFGS{GradStopThreshold: math.NaN()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != StepConvergence {
+		t.Errorf("unexpected status of StepConverge: %v", result.Status)
+	}
+	if !floats.EqualApprox(result.X, []float64{1, 1}, 1e-3) {
+		t.Errorf("unexpected location of StepConverge: got %v, want [1 1]", result.X)
+	}
+}
+
+func TestCallbackRecorder(t *testing.T) {
+	t.Parallel()
+	p := Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+		Grad: functions.ExtendedRosenbrock{}.Grad,
+	}
+	x0 := []float64{-1.2, 1}
+
+	const stopAt = 5
+	var calls int
+	settings := &Settings{
+		Recorder: CallbackRecorder(func(loc *Location, stats *Stats) error {
+			calls++
+			if stats.MajorIterations != calls {
+				t.Errorf("unexpected iteration in call %d: %d", calls, stats.MajorIterations)
+			}
+			if stats.MajorIterations == stopAt {
+				return ErrStop
+			}
+			return nil
+		}),
+	}
+	result, err := Minimize(p, x0, settings, &BFGS{})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if result.Status != Stopped || result.MajorIterations != stopAt || calls != stopAt {
+		t.Errorf("unexpected result: status %v after %d iterations and %d calls", result.Status, result.MajorIterations, calls)
+	}
+
+	errCallback := errors.New("callback failure")
+	settings.Recorder = CallbackRecorder(func(loc *Location, stats *Stats) error {
+		return errCallback
+	})
+	result, err = Minimize(p, x0, settings, &BFGS{})
+	if err != errCallback || result.Status != Failure {
+		t.Errorf("unexpected result for failing callback: status %v, error %v", result.Status, err)
+	}
+}
+
+func TestEvaluationFailure(t *testing.T) {
+	t.Parallel()
+	rosen := functions.ExtendedRosenbrock{}
+	initX := []float64{-1.2, 1}
+
+	// failingProblem returns a Problem whose Func calls fail after n calls.
+	failingProblem := func(n int32, fail func()) Problem {
+		var calls int32
+		return Problem{
+			Func: func(x []float64) float64 {
+				if atomic.AddInt32(&calls, 1) > n {
+					fail()
+				}
+				return rosen.Func(x)
+			},
+			Grad: rosen.Grad,
+		}
+	}
+
+	p := failingProblem(10, func() { panic("bad evaluation") })
+	res, err := Minimize(p, initX, &Settings{RecoverPanics: true}, &BFGS{})
+	if e, ok := err.(ErrEvaluationPanic); !ok || e.Value != "bad evaluation" || e.Op&FuncEvaluation == 0 {
+		t.Errorf("unexpected error for panic: %v", err)
+	}
+	if res == nil || res.Status != EvaluationFailure {
+		t.Fatalf("unexpected result for panic: %v", res)
+	}
+	if res.F >= rosen.Func(initX) {
+		t.Errorf("result is not the last major iteration: got F=%v", res.F)
+	}
+
+	p = failingProblem(10, func() { time.Sleep(time.Second) })
+	res, err = Minimize(p, initX, &Settings{EvaluationTimeout: 50 * time.Millisecond}, &BFGS{})
+	if err != ErrEvaluationTimeout {
+		t.Errorf("unexpected error for timeout: got %v, want %v", err, ErrEvaluationTimeout)
+	}
+	if res == nil || res.Status != EvaluationFailure {
+		t.Fatalf("unexpected result for timeout: %v", res)
+	}
+
+	// Evaluations that finish in time do not affect the optimization.
+	wood := functions.Wood{}
+	initX = []float64{-3, -1, -3, -1}
+	p = Problem{Func: wood.Func, Grad: wood.Grad, Hess: wood.Hess}
+	want, err := Minimize(p, initX, nil, &Newton{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings := &Settings{EvaluationTimeout: time.Minute, RecoverPanics: true}
+	res, err = Minimize(p, initX, settings, &Newton{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !floats.Equal(res.X, want.X) || res.Stats.MajorIterations != want.Stats.MajorIterations {
+		t.Errorf("unexpected result with timeout: got %v, want %v", res.X, want.X)
+	}
+}
+
+func TestInverseHessian(t *testing.T) {
+	t.Parallel()
+	// The quadratic f(x) = 1/2 xᵀ A x - bᵀ x has the inverse Hessian A⁻¹.
+	a := mat.NewSymDense(3, []float64{
+		4, 1, 0.5,
+		1, 3, 0.2,
+		0.5, 0.2, 2,
+	})
+	b := mat.NewVecDense(3, []float64{1, -2, 3})
+	p := Problem{
+		Func: func(x []float64) float64 {
+			xv := mat.NewVecDense(len(x), x)
+			return 0.5*mat.Inner(xv, a, xv) - mat.Dot(b, xv)
+		},
+		Grad: func(grad, x []float64) {
+			g := mat.NewVecDense(len(grad), grad)
+			g.MulVec(a, mat.NewVecDense(len(x), x))
+			g.SubVec(g, b)
+		},
+	}
+	var want mat.Dense
+	err := want.Inverse(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name     string
+		method   Method
+		settings *Settings
+	}{
+		{"BFGS", &BFGS{Linesearcher: &MoreThuente{CurvatureFactor: 1e-4}}, &Settings{InverseHessian: true}},
+		{"LBFGS", &LBFGS{Linesearcher: &MoreThuente{CurvatureFactor: 1e-4}}, &Settings{InverseHessian: true}},
+		{"BFGSScale", &BFGS{Linesearcher: &MoreThuente{CurvatureFactor: 1e-4}}, &Settings{Scale: []float64{2, 1, 0.5}, GradientThreshold: 1e-10, InverseHessian: true}},
+	} {
+		res, err := Minimize(p, []float64{5, -5, 5}, test.settings, test.method)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if res.InverseHessian == nil {
+			t.Errorf("%s: missing inverse Hessian", test.name)
+			continue
+		}
+		if !mat.EqualApprox(res.InverseHessian, &want, 1e-2) {
+			t.Errorf("%s: unexpected inverse Hessian:\ngot\n%v\nwant\n%v", test.name,
+				mat.Formatted(res.InverseHessian), mat.Formatted(&want))
+		}
+	}
+
+	res, err := Minimize(p, []float64{5, -5, 5}, nil, &LBFGS{Linesearcher: &MoreThuente{CurvatureFactor: 1e-4}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.InverseHessian != nil {
+		t.Errorf("unexpected inverse Hessian when not requested")
+	}
+	res, err = Minimize(p, []float64{5, -5, 5}, &Settings{InverseHessian: true}, &NelderMead{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.InverseHessian != nil {
+		t.Errorf("unexpected inverse Hessian for NelderMead")
+	}
+}
+
+func TestStatusValues(t *testing.T) {
+	t.Parallel()
+	// The values of the original statuses must not change when statuses
+	// are added.
+	for _, test := range []struct {
+		status Status
+		name   string
+		value  int
+	}{
+		{NotTerminated, "NotTerminated", 0},
+		{Success, "Success", 1},
+		{FunctionThreshold, "FunctionThreshold", 2},
+		{FunctionConvergence, "FunctionConvergence", 3},
+		{GradientThreshold, "GradientThreshold", 4},
+		{StepConvergence, "StepConvergence", 5},
+		{FunctionNegativeInfinity, "FunctionNegativeInfinity", 6},
+		{MethodConverge, "MethodConverge", 7},
+		{Failure, "Failure", 8},
+		{IterationLimit, "IterationLimit", 9},
+		{RuntimeLimit, "RuntimeLimit", 10},
+		{FunctionEvaluationLimit, "FunctionEvaluationLimit", 11},
+		{GradientEvaluationLimit, "GradientEvaluationLimit", 12},
+		{HessianEvaluationLimit, "HessianEvaluationLimit", 13},
+		{Cancelled, "Cancelled", 14},
+		{Stopped, "Stopped", 15},
+		{EvaluationFailure, "EvaluationFailure", 16},
+		{RelativeGradientThreshold, "RelativeGradientThreshold", 17},
+	} {
+		if int(test.status) != test.value {
+			t.Errorf("unexpected value of %s: got %d, want %d", test.name, int(test.status), test.value)
+		}
+		if test.status.String() != test.name {
+			t.Errorf("unexpected name of status %d: got %s, want %s", test.value, test.status, test.name)
+		}
+	}
+	if RelativeGradientThreshold.Early() {
+		t.Errorf("RelativeGradientThreshold is early")
+	}
+}
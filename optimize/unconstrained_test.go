@@ -996,6 +996,100 @@ func newVariablyDimensioned(dim int, gradTol float64) unconstrainedTest {
 	}
 }
 
+var newtonCGTests = []unconstrainedTest{
+	{
+		name: "Beale",
+		p: Problem{
+			Func:    functions.Beale{}.Func,
+			Grad:    functions.Beale{}.Grad,
+			HessVec: hessVecFromHess(functions.Beale{}.Hess),
+		},
+		x: []float64{1, 1},
+	},
+	{
+		name: "BrownBadlyScaled",
+		p: Problem{
+			Func:    functions.BrownBadlyScaled{}.Func,
+			Grad:    functions.BrownBadlyScaled{}.Grad,
+			HessVec: hessVecFromHess(functions.BrownBadlyScaled{}.Hess),
+		},
+		x:       []float64{1, 1},
+		gradTol: 1e-9,
+	},
+	{
+		name: "PowellBadlyScaled",
+		p: Problem{
+			Func:    functions.PowellBadlyScaled{}.Func,
+			Grad:    functions.PowellBadlyScaled{}.Grad,
+			HessVec: hessVecFromHess(functions.PowellBadlyScaled{}.Hess),
+		},
+		x:       []float64{0, 1},
+		gradTol: 1e-10,
+	},
+	{
+		name: "Watson",
+		p: Problem{
+			Func:    functions.Watson{}.Func,
+			Grad:    functions.Watson{}.Grad,
+			HessVec: hessVecFromHess(functions.Watson{}.Hess),
+		},
+		x: []float64{0, 0, 0, 0, 0, 0},
+	},
+	{
+		name: "Wood",
+		p: Problem{
+			Func:    functions.Wood{}.Func,
+			Grad:    functions.Wood{}.Grad,
+			HessVec: hessVecFromHess(functions.Wood{}.Hess),
+		},
+		x: []float64{-3, -1, -3, -1},
+	},
+	newExtendedRosenbrockHessVec(100, 1e-10),
+}
+
+// hessVecFromHess returns a Hessian-vector product function that forms the
+// Hessian with hess.
+func hessVecFromHess(hess func(*mat.SymDense, []float64)) func(dst, x, v []float64) {
+	return func(dst, x, v []float64) {
+		h := mat.NewSymDense(len(x), nil)
+		hess(h, x)
+		mat.NewVecDense(len(dst), dst).MulVec(h, mat.NewVecDense(len(v), v))
+	}
+}
+
+func newExtendedRosenbrockHessVec(dim int, gradTol float64) unconstrainedTest {
+	x := make([]float64, dim)
+	for i := range x {
+		x[i] = -1.2
+		if i%2 == 1 {
+			x[i] = 1
+		}
+	}
+	return unconstrainedTest{
+		name: "ExtendedRosenbrock",
+		p: Problem{
+			Func:    functions.ExtendedRosenbrock{}.Func,
+			Grad:    functions.ExtendedRosenbrock{}.Grad,
+			HessVec: extendedRosenbrockHessVec,
+		},
+		x:       x,
+		gradTol: gradTol,
+	}
+}
+
+// extendedRosenbrockHessVec computes the product of the Hessian of the
+// ExtendedRosenbrock function with v without forming the Hessian.
+func extendedRosenbrockHessVec(dst, x, v []float64) {
+	for i := range dst {
+		dst[i] = 0
+	}
+	for i := 0; i < len(x)-1; i++ {
+		a, b := x[i], x[i+1]
+		dst[i] += (2-400*b+1200*a*a)*v[i] - 400*a*v[i+1]
+		dst[i+1] += -400*a*v[i] + 200*v[i+1]
+	}
+}
+
 func TestLocal(t *testing.T) {
 	t.Parallel()
 	var tests []unconstrainedTest
@@ -1176,6 +1270,62 @@ func TestNewton(t *testing.T) {
 	testLocal(t, newtonTests, &Newton{})
 }
 
+func TestNewtonNonmonotone(t *testing.T) {
+	t.Parallel()
+	var tests []unconstrainedTest
+	for _, test := range newtonTests {
+		// The nonmonotone line search accepts the full Newton steps along
+		// the narrow valley of PowellBadlyScaled, which needs over a hundred
+		// thousand iterations to reach the gradient tolerance.
+		if test.name != "PowellBadlyScaled" {
+			tests = append(tests, test)
+		}
+	}
+	testLocal(t, tests, &Newton{
+		Linesearcher: &NonmonotoneBacktracking{},
+	})
+}
+
+func TestNewtonEigenvalueModification(t *testing.T) {
+	t.Parallel()
+	var tests []unconstrainedTest
+	for _, test := range newtonTests {
+		// The Hessian of PowellBadlyScaled is numerically singular along
+		// the valley of the minimizer, where the modified eigenvalues do
+		// not resolve the step to the gradient tolerance.
+		if test.name != "PowellBadlyScaled" {
+			tests = append(tests, test)
+		}
+	}
+	testLocal(t, tests, &Newton{Modification: EigenvalueModification})
+}
+
+func TestNewtonCG(t *testing.T) {
+	t.Parallel()
+	testLocal(t, newtonCGTests, &NewtonCG{})
+}
+
+func TestTrustRegion(t *testing.T) {
+	t.Parallel()
+	testLocal(t, newtonTests, &TrustRegion{})
+}
+
+func TestTrustRegionSR1(t *testing.T) {
+	t.Parallel()
+	var tests []unconstrainedTest
+	tests = append(tests, gradientDescentTests...)
+	tests = append(tests, bfgsTests...)
+	testLocal(t, tests, &TrustRegion{})
+}
+
+func TestLSR1(t *testing.T) {
+	t.Parallel()
+	var tests []unconstrainedTest
+	tests = append(tests, gradientDescentTests...)
+	tests = append(tests, bfgsTests...)
+	testLocal(t, tests, &LSR1{})
+}
+
 func testLocal(t *testing.T, tests []unconstrainedTest, method Method) {
 	for cas, test := range tests {
 		if test.long && testing.Short() {
@@ -1226,6 +1376,13 @@ func testLocal(t *testing.T, tests []unconstrainedTest, method Method) {
 			continue
 		}
 
+		// Check that the function value at the initial location is
+		// reported in result.InitialValue.
+		if initF := test.p.Func(test.x); result.InitialValue != initF {
+			t.Errorf("Case %d: InitialValue %v not equal to the initial function value %v for:\n%v",
+				cas, result.InitialValue, initF, test)
+		}
+
 		// Check that the function value at the found optimum location is
 		// equal to result.F.
 		optF := test.p.Func(result.X)
@@ -1294,6 +1451,9 @@ func testLocal(t *testing.T, tests []unconstrainedTest, method Method) {
 		if result.F != result2.F || !floats.Equal(result.X, result2.X) {
 			t.Errorf("Different minimum second time for:\n%v", test)
 		}
+		if result2.InitialValue != settings.InitValues.F {
+			t.Errorf("InitialValue not taken from InitValues for:\n%v", test)
+		}
 
 		// Check that providing initial data reduces the number of evaluations exactly by one.
 		if result.FuncEvaluations != result2.FuncEvaluations+1 {
@@ -1368,3 +1528,33 @@ func TestNelderMeadOneD(t *testing.T) {
 		t.Errorf("Wrong value of shrink")
 	}
 }
+
+func TestLBFGSBUnconstrained(t *testing.T) {
+	t.Parallel()
+	var tests []unconstrainedTest
+	tests = append(tests, gradientDescentTests...)
+	tests = append(tests, quasiNewtonTests...)
+	tests = append(tests, lbfgsTests...)
+	testLocal(t, tests, &LBFGSB{})
+}
+
+func TestActiveSetUnconstrained(t *testing.T) {
+	t.Parallel()
+	var tests []unconstrainedTest
+	tests = append(tests, gradientDescentTests...)
+	testLocal(t, tests, &ActiveSet{})
+}
+
+func TestSQPUnconstrained(t *testing.T) {
+	t.Parallel()
+	var tests []unconstrainedTest
+	tests = append(tests, gradientDescentTests...)
+	testLocal(t, tests, &SQP{GradStopThreshold: 1e-12})
+}
+
+func TestInteriorPointUnconstrained(t *testing.T) {
+	t.Parallel()
+	var tests []unconstrainedTest
+	tests = append(tests, gradientDescentTests...)
+	testLocal(t, tests, &InteriorPoint{GradStopThreshold: 1e-12})
+}
@@ -0,0 +1,458 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrNoBracket signifies that an interval does not bracket a minimum or
+// a root of a univariate function, or that no bracketing interval was found.
+var ErrNoBracket = errors.New("optimize: interval does not bracket a solution")
+
+const (
+	// invPhi is the inverse of the golden ratio.
+	invPhi = 0.6180339887498949
+	// maxBracketExpansions is the maximum number of expansions of an
+	// interval by the bracketing functions.
+	maxBracketExpansions = 50
+)
+
+// UnivariateSettings controls the termination of the univariate minimization
+// and root-finding functions.
+type UnivariateSettings struct {
+	// Tolerance is the absolute tolerance on the location of the solution.
+	// The iterations stop when the bracketing interval is smaller than
+	// approximately Tolerance plus a multiple of the machine precision
+	// relative to the location. If Tolerance is 0, a default value of 1e-12
+	// is used.
+	Tolerance float64
+	// MaxIterations is the maximum number of iterations. If MaxIterations
+	// is 0, a default value of 100 is used.
+	MaxIterations int
+}
+
+func (s *UnivariateSettings) values() (tol float64, maxIter int) {
+	if s == nil {
+		return 1e-12, 100
+	}
+	tol, maxIter = s.Tolerance, s.MaxIterations
+	if tol == 0 {
+		tol = 1e-12
+	}
+	if tol < 0 {
+		panic("optimize: negative univariate tolerance")
+	}
+	if maxIter == 0 {
+		maxIter = 100
+	}
+	if maxIter < 0 {
+		panic("optimize: negative univariate MaxIterations")
+	}
+	return tol, maxIter
+}
+
+// UnivariateResult is the result of a univariate minimization or root finding.
+type UnivariateResult struct {
+	// X is the location of the solution and F is the function value at X.
+	X, F float64
+	// Status is StepConvergence if the bracketing interval became smaller
+	// than the tolerance, FunctionThreshold if a root was found exactly,
+	// and IterationLimit if MaxIterations was reached.
+	Status          Status
+	Iterations      int
+	FuncEvaluations int
+}
+
+// BracketMinimum searches for three points lo < mid < hi such that the value
+// of f at mid is lower than at one end and not higher than at the other, so
+// that a continuous function f has a local minimum in [lo, hi]. The search
+// starts from the points a and b, which must be different, and steps downhill
+// with increasing step sizes.
+// BracketMinimum returns ErrNoBracket if no bracket is found after a maximum
+// number of steps, for example because f is unbounded below.
+func BracketMinimum(f func(float64) float64, a, b float64) (lo, mid, hi float64, err error) {
+	if a == b {
+		panic("optimize: equal initial points")
+	}
+	fa, fb := f(a), f(b)
+	if fb > fa {
+		a, b = b, a
+		fa, fb = fb, fa
+	}
+	// The function decreases from a to b.
+	c := b + (b-a)/invPhi
+	fc := f(c)
+	for i := 0; fc < fb; i++ {
+		if i == maxBracketExpansions || math.IsInf(c, 0) || math.IsNaN(fc) {
+			return 0, 0, 0, ErrNoBracket
+		}
+		a, b = b, c
+		fa, fb = fb, fc
+		c = b + (b-a)/invPhi
+		fc = f(c)
+	}
+	if math.IsNaN(fa) || math.IsNaN(fb) || math.IsNaN(fc) {
+		return 0, 0, 0, ErrNoBracket
+	}
+	if fb == fa {
+		// The initial points have equal values, so a minimum can only
+		// be bracketed by a point between them.
+		c, b = b, 0.5*(a+b)
+		if f(b) >= fa {
+			return 0, 0, 0, ErrNoBracket
+		}
+	}
+	if a > c {
+		a, c = c, a
+	}
+	return a, b, c, nil
+}
+
+// BracketRoot searches for an interval [lo, hi] such that f(lo) and f(hi) have
+// opposite signs, so that a continuous function f has a root in [lo, hi]. The
+// search starts from the interval between a and b, which must be different,
+// and expands the interval on the side where |f| is smaller. BracketRoot
+// returns ErrNoBracket if no bracket is found after a maximum number of
+// expansions.
+func BracketRoot(f func(float64) float64, a, b float64) (lo, hi float64, err error) {
+	if a == b {
+		panic("optimize: equal initial points")
+	}
+	if a > b {
+		a, b = b, a
+	}
+	fa, fb := f(a), f(b)
+	for i := 0; ; i++ {
+		if math.IsNaN(fa) || math.IsNaN(fb) {
+			return 0, 0, ErrNoBracket
+		}
+		if math.Signbit(fa) != math.Signbit(fb) || fa == 0 || fb == 0 {
+			return a, b, nil
+		}
+		if i == maxBracketExpansions {
+			return 0, 0, ErrNoBracket
+		}
+		w := (b - a) / invPhi
+		if math.Abs(fa) < math.Abs(fb) {
+			a -= w
+			fa = f(a)
+		} else {
+			b += w
+			fb = f(b)
+		}
+	}
+}
+
+// GoldenSection finds a local minimum of f in the interval [lo, hi] by golden
+// section search. GoldenSection only assumes that f is unimodal on the
+// interval, and it reduces the interval by a constant factor at every
+// iteration. Brent usually converges much faster for smooth functions.
+func GoldenSection(f func(float64) float64, lo, hi float64, settings *UnivariateSettings) (*UnivariateResult, error) {
+	if lo >= hi {
+		panic("optimize: empty interval")
+	}
+	tol, maxIter := settings.values()
+	res := &UnivariateResult{Status: IterationLimit}
+
+	x1 := hi - invPhi*(hi-lo)
+	x2 := lo + invPhi*(hi-lo)
+	f1, f2 := f(x1), f(x2)
+	res.FuncEvaluations = 2
+	for res.Iterations < maxIter {
+		if hi-lo <= tol+2*sqrtEps*math.Abs(x1) {
+			res.Status = StepConvergence
+			break
+		}
+		res.Iterations++
+		if f1 < f2 || math.IsNaN(f2) {
+			hi, x2, f2 = x2, x1, f1
+			x1 = hi - invPhi*(hi-lo)
+			f1 = f(x1)
+		} else {
+			lo, x1, f1 = x1, x2, f2
+			x2 = lo + invPhi*(hi-lo)
+			f2 = f(x2)
+		}
+		res.FuncEvaluations++
+	}
+	res.X, res.F = x1, f1
+	if f2 < f1 {
+		res.X, res.F = x2, f2
+	}
+	return res, nil
+}
+
+// Brent finds a local minimum of f in the interval [lo, hi] by Brent's method,
+// which combines golden section search with successive parabolic
+// interpolation. See
+//  Brent, R. P.: Algorithms for Minimization without Derivatives.
+//  Prentice-Hall (1973), Chapter 5
+// for more information.
+func Brent(f func(float64) float64, lo, hi float64, settings *UnivariateSettings) (*UnivariateResult, error) {
+	if lo >= hi {
+		panic("optimize: empty interval")
+	}
+	tol, maxIter := settings.values()
+	res := &UnivariateResult{Status: IterationLimit}
+	const c = 1 - invPhi
+
+	// x is the location of the lowest value found, w of the second lowest
+	// and v is the previous value of w.
+	x := lo + c*(hi-lo)
+	w, v := x, x
+	fx := f(x)
+	fw, fv := fx, fx
+	res.FuncEvaluations = 1
+	var d, e float64
+	for res.Iterations < maxIter {
+		mid := 0.5 * (lo + hi)
+		tol1 := sqrtEps*math.Abs(x) + tol/3
+		tol2 := 2 * tol1
+		if math.Abs(x-mid) <= tol2-0.5*(hi-lo) {
+			res.Status = StepConvergence
+			break
+		}
+		res.Iterations++
+
+		golden := true
+		if math.Abs(e) > tol1 {
+			// Fit a parabola through x, v and w.
+			r := (x - w) * (fx - fv)
+			q := (x - v) * (fx - fw)
+			p := (x-v)*q - (x-w)*r
+			q = 2 * (q - r)
+			if q > 0 {
+				p = -p
+			} else {
+				q = -q
+			}
+			if math.Abs(p) < math.Abs(0.5*q*e) && p > q*(lo-x) && p < q*(hi-x) {
+				// Take the parabolic interpolation step.
+				e = d
+				d = p / q
+				u := x + d
+				// f must not be evaluated too close to the ends.
+				if u-lo < tol2 || hi-u < tol2 {
+					d = math.Copysign(tol1, mid-x)
+				}
+				golden = false
+			}
+		}
+		if golden {
+			if x < mid {
+				e = hi - x
+			} else {
+				e = lo - x
+			}
+			d = c * e
+		}
+
+		// f must not be evaluated too close to x.
+		u := x + d
+		if math.Abs(d) < tol1 {
+			u = x + math.Copysign(tol1, d)
+		}
+		fu := f(u)
+		res.FuncEvaluations++
+
+		if fu <= fx {
+			if u < x {
+				hi = x
+			} else {
+				lo = x
+			}
+			v, fv = w, fw
+			w, fw = x, fx
+			x, fx = u, fu
+			continue
+		}
+		if u < x {
+			lo = u
+		} else {
+			hi = u
+		}
+		if fu <= fw || w == x {
+			v, fv = w, fw
+			w, fw = u, fu
+		} else if fu <= fv || v == x || v == w {
+			v, fv = u, fu
+		}
+	}
+	res.X, res.F = x, fx
+	return res, nil
+}
+
+// BrentRoot finds a root of f in the interval [lo, hi] by Brent's method,
+// which combines bisection with secant and inverse quadratic interpolation
+// steps. The values of f at lo and hi must have opposite signs, otherwise
+// BrentRoot returns ErrNoBracket. See
+//  Brent, R. P.: Algorithms for Minimization without Derivatives.
+//  Prentice-Hall (1973), Chapter 4
+// for more information.
+func BrentRoot(f func(float64) float64, lo, hi float64, settings *UnivariateSettings) (*UnivariateResult, error) {
+	tol, maxIter := settings.values()
+	a, b := lo, hi
+	fa, fb := f(a), f(b)
+	res := &UnivariateResult{Status: IterationLimit, FuncEvaluations: 2}
+	if fa == 0 {
+		res.X, res.F, res.Status = a, fa, FunctionThreshold
+		return res, nil
+	}
+	if fb == 0 {
+		res.X, res.F, res.Status = b, fb, FunctionThreshold
+		return res, nil
+	}
+	if math.Signbit(fa) == math.Signbit(fb) || math.IsNaN(fa) || math.IsNaN(fb) {
+		return nil, ErrNoBracket
+	}
+
+	// b is the best approximation of the root, the root lies between b
+	// and c, and a is the previous value of b.
+	c, fc := a, fa
+	d := b - a
+	e := d
+	for res.Iterations < maxIter {
+		if math.Abs(fc) < math.Abs(fb) {
+			a, b, c = b, c, b
+			fa, fb, fc = fb, fc, fb
+		}
+		tol1 := 2*dlamchE*math.Abs(b) + 0.5*tol
+		m := 0.5 * (c - b)
+		if fb == 0 {
+			res.Status = FunctionThreshold
+			break
+		}
+		if math.Abs(m) <= tol1 {
+			res.Status = StepConvergence
+			break
+		}
+		res.Iterations++
+
+		if math.Abs(e) >= tol1 && math.Abs(fa) > math.Abs(fb) {
+			// Attempt an interpolation step.
+			var p, q float64
+			s := fb / fa
+			if a == c {
+				// Secant step.
+				p = 2 * m * s
+				q = 1 - s
+			} else {
+				// Inverse quadratic interpolation step.
+				q = fa / fc
+				r := fb / fc
+				p = s * (2*m*q*(q-r) - (b-a)*(r-1))
+				q = (q - 1) * (r - 1) * (s - 1)
+			}
+			if p > 0 {
+				q = -q
+			} else {
+				p = -p
+			}
+			if 2*p < math.Min(3*m*q-math.Abs(tol1*q), math.Abs(e*q)) {
+				e = d
+				d = p / q
+			} else {
+				// The interpolation failed, use bisection.
+				d = m
+				e = m
+			}
+		} else {
+			d = m
+			e = m
+		}
+		a, fa = b, fb
+		if math.Abs(d) > tol1 {
+			b += d
+		} else {
+			b += math.Copysign(tol1, m)
+		}
+		fb = f(b)
+		res.FuncEvaluations++
+		if math.Signbit(fb) == math.Signbit(fc) {
+			c, fc = a, fa
+			d = b - a
+			e = d
+		}
+	}
+	res.X, res.F = b, fb
+	return res, nil
+}
+
+// Ridders finds a root of f in the interval [lo, hi] by Ridders' method, which
+// fits an exponential function through the ends and the midpoint of the
+// interval. The values of f at lo and hi must have opposite signs, otherwise
+// Ridders returns ErrNoBracket. See
+//  Ridders, C.: A new algorithm for computing a single root of a real
+//  continuous function. IEEE Trans. Circuits Syst. 26 (1979), 979-980
+// for more information.
+func Ridders(f func(float64) float64, lo, hi float64, settings *UnivariateSettings) (*UnivariateResult, error) {
+	tol, maxIter := settings.values()
+	a, b := lo, hi
+	fa, fb := f(a), f(b)
+	res := &UnivariateResult{Status: IterationLimit, FuncEvaluations: 2}
+	if fa == 0 {
+		res.X, res.F, res.Status = a, fa, FunctionThreshold
+		return res, nil
+	}
+	if fb == 0 {
+		res.X, res.F, res.Status = b, fb, FunctionThreshold
+		return res, nil
+	}
+	if math.Signbit(fa) == math.Signbit(fb) || math.IsNaN(fa) || math.IsNaN(fb) {
+		return nil, ErrNoBracket
+	}
+
+	x, fx := a, fa
+	if math.Abs(fb) < math.Abs(fa) {
+		x, fx = b, fb
+	}
+	for res.Iterations < maxIter {
+		if math.Abs(b-a) <= tol+2*dlamchE*math.Abs(x) {
+			res.Status = StepConvergence
+			break
+		}
+		res.Iterations++
+
+		m := 0.5 * (a + b)
+		fm := f(m)
+		res.FuncEvaluations++
+		if fm == 0 {
+			x, fx = m, fm
+			res.Status = FunctionThreshold
+			break
+		}
+		s := math.Sqrt(fm*fm - fa*fb)
+		xNew := m + (m-a)*math.Copysign(1, fa-fb)*fm/s
+		fNew := f(xNew)
+		res.FuncEvaluations++
+		x, fx = xNew, fNew
+		if fNew == 0 {
+			res.Status = FunctionThreshold
+			break
+		}
+
+		// Keep the smallest interval with a sign change.
+		switch {
+		case math.Signbit(fm) != math.Signbit(fNew):
+			a, fa = m, fm
+			b, fb = xNew, fNew
+			if a > b {
+				a, b = b, a
+				fa, fb = fb, fa
+			}
+		case math.Signbit(fa) != math.Signbit(fNew):
+			b, fb = xNew, fNew
+		default:
+			a, fa = xNew, fNew
+		}
+	}
+	res.X, res.F = x, fx
+	return res, nil
+}
+
+// sqrtEps is the square root of the machine epsilon.
+var sqrtEps = math.Sqrt(dlamchE)
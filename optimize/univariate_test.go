@@ -0,0 +1,224 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+)
+
+type univariateFunc func(float64) float64
+
+func (f univariateFunc) counted(n *int) func(float64) float64 {
+	return func(x float64) float64 {
+		*n++
+		return f(x)
+	}
+}
+
+var univariateMinTests = []struct {
+	name   string
+	f      univariateFunc
+	lo, hi float64
+	x      float64
+}{
+	{
+		name: "Quadratic",
+		f:    func(x float64) float64 { return (x - 1) * (x - 1) },
+		lo:   -3, hi: 5,
+		x: 1,
+	},
+	{
+		name: "Cosine",
+		f:    math.Cos,
+		lo:   2, hi: 4,
+		x: math.Pi,
+	},
+	{
+		name: "Quartic",
+		f:    func(x float64) float64 { return math.Pow(x-2, 4) + x - 2 },
+		lo:   0, hi: 4,
+		x: 2 - math.Cbrt(0.25),
+	},
+	{
+		name: "Abs",
+		f:    func(x float64) float64 { return math.Abs(x - 0.3) },
+		lo:   -1, hi: 2,
+		x: 0.3,
+	},
+}
+
+func TestUnivariateMinimize(t *testing.T) {
+	t.Parallel()
+	for _, method := range []struct {
+		name string
+		f    func(func(float64) float64, float64, float64, *UnivariateSettings) (*UnivariateResult, error)
+		tol  float64
+	}{
+		{name: "GoldenSection", f: GoldenSection, tol: 1e-7},
+		{name: "Brent", f: Brent, tol: 1e-7},
+	} {
+		for _, test := range univariateMinTests {
+			var n int
+			res, err := method.f(test.f.counted(&n), test.lo, test.hi, nil)
+			if err != nil {
+				t.Errorf("%s %s: unexpected error: %v", method.name, test.name, err)
+				continue
+			}
+			if res.Status != StepConvergence {
+				t.Errorf("%s %s: unexpected status: got %v, want %v", method.name, test.name, res.Status, StepConvergence)
+			}
+			if math.Abs(res.X-test.x) > method.tol {
+				t.Errorf("%s %s: unexpected location: got %v, want %v", method.name, test.name, res.X, test.x)
+			}
+			if res.F != test.f(res.X) {
+				t.Errorf("%s %s: function value mismatch", method.name, test.name)
+			}
+			if res.FuncEvaluations != n {
+				t.Errorf("%s %s: evaluation count mismatch: got %d, want %d", method.name, test.name, res.FuncEvaluations, n)
+			}
+		}
+	}
+}
+
+var univariateRootTests = []struct {
+	name   string
+	f      univariateFunc
+	lo, hi float64
+	x      float64
+}{
+	{
+		name: "Linear",
+		f:    func(x float64) float64 { return 2*x - 3 },
+		lo:   0, hi: 10,
+		x: 1.5,
+	},
+	{
+		name: "Cubic",
+		f:    func(x float64) float64 { return x*x*x - 2*x - 5 },
+		lo:   2, hi: 3,
+		x: 2.0945514815423265,
+	},
+	{
+		name: "Exp",
+		f:    func(x float64) float64 { return math.Exp(x) - 10 },
+		lo:   5, hi: -5,
+		x: math.Log(10),
+	},
+	{
+		name: "Step",
+		f: func(x float64) float64 {
+			if x < math.Sqrt2 {
+				return -1
+			}
+			return 1
+		},
+		lo: 0, hi: 4,
+		x: math.Sqrt2,
+	},
+	{
+		name: "Sine",
+		f:    math.Sin,
+		lo:   3, hi: 4,
+		x: math.Pi,
+	},
+}
+
+func TestUnivariateRoot(t *testing.T) {
+	t.Parallel()
+	for _, method := range []struct {
+		name string
+		f    func(func(float64) float64, float64, float64, *UnivariateSettings) (*UnivariateResult, error)
+	}{
+		{name: "BrentRoot", f: BrentRoot},
+		{name: "Ridders", f: Ridders},
+	} {
+		for _, test := range univariateRootTests {
+			var n int
+			res, err := method.f(test.f.counted(&n), test.lo, test.hi, nil)
+			if err != nil {
+				t.Errorf("%s %s: unexpected error: %v", method.name, test.name, err)
+				continue
+			}
+			if res.Status != StepConvergence && res.Status != FunctionThreshold {
+				t.Errorf("%s %s: unexpected status: %v", method.name, test.name, res.Status)
+			}
+			if math.Abs(res.X-test.x) > 1e-11 {
+				t.Errorf("%s %s: unexpected root: got %v, want %v", method.name, test.name, res.X, test.x)
+			}
+			if res.F != test.f(res.X) {
+				t.Errorf("%s %s: function value mismatch", method.name, test.name)
+			}
+			if res.FuncEvaluations != n {
+				t.Errorf("%s %s: evaluation count mismatch: got %d, want %d", method.name, test.name, res.FuncEvaluations, n)
+			}
+		}
+
+		_, err := method.f(math.Cos, 0, 1, nil)
+		if err != ErrNoBracket {
+			t.Errorf("%s: unexpected error for interval without a sign change: got %v, want %v", method.name, err, ErrNoBracket)
+		}
+		res, err := method.f(math.Cos, 1, 2, &UnivariateSettings{MaxIterations: 2})
+		if err != nil || res.Status != IterationLimit || res.Iterations != 2 {
+			t.Errorf("%s: unexpected result for iteration limit: %+v, %v", method.name, res, err)
+		}
+	}
+}
+
+func TestBracketMinimum(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		f    func(float64) float64
+		a, b float64
+	}{
+		{f: func(x float64) float64 { return (x - 100) * (x - 100) }, a: 0, b: 1},
+		{f: func(x float64) float64 { return (x + 100) * (x + 100) }, a: 0, b: 1},
+		{f: math.Cos, a: 0, b: 0.1},
+		{f: func(x float64) float64 { return x * x }, a: -1, b: 1},
+	} {
+		lo, mid, hi, err := BracketMinimum(test.f, test.a, test.b)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		if !(lo < mid && mid < hi) {
+			t.Errorf("unordered bracket: %v, %v, %v", lo, mid, hi)
+		}
+		fm := test.f(mid)
+		flo, fhi := test.f(lo), test.f(hi)
+		if !(fm < flo && fm <= fhi) && !(fm <= flo && fm < fhi) {
+			t.Errorf("invalid bracket: f(%v)=%v, f(%v)=%v, f(%v)=%v", lo, flo, mid, fm, hi, fhi)
+		}
+	}
+	_, _, _, err := BracketMinimum(func(x float64) float64 { return -x }, 0, 1)
+	if err != ErrNoBracket {
+		t.Errorf("unexpected error for unbounded function: got %v, want %v", err, ErrNoBracket)
+	}
+}
+
+func TestBracketRoot(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		f    func(float64) float64
+		a, b float64
+	}{
+		{f: func(x float64) float64 { return x - 1000 }, a: 0, b: 1},
+		{f: func(x float64) float64 { return x + 1000 }, a: 1, b: 0},
+		{f: func(x float64) float64 { return math.Atan(x - 50) }, a: 0, b: 1},
+	} {
+		lo, hi, err := BracketRoot(test.f, test.a, test.b)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		if lo >= hi || math.Signbit(test.f(lo)) == math.Signbit(test.f(hi)) {
+			t.Errorf("invalid bracket [%v, %v]", lo, hi)
+		}
+	}
+	_, _, err := BracketRoot(func(x float64) float64 { return x*x + 1 }, 0, 1)
+	if err != ErrNoBracket {
+		t.Errorf("unexpected error for function without a root: got %v, want %v", err, ErrNoBracket)
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// BinRule specifies a rule for choosing the width of the bins of a histogram
+// from the data.
+type BinRule int
+
+const (
+	// FreedmanDiaconis chooses the bin width 2*IQR*n^(-1/3), where IQR is
+	// the interquartile range of the data. The rule is robust to outliers.
+	FreedmanDiaconis BinRule = iota
+	// Sturges chooses ceil(log2(n))+1 bins, which is suited to data that
+	// are close to normally distributed and not too numerous.
+	Sturges
+	// Scott chooses the bin width 3.49*σ*n^(-1/3), where σ is the standard
+	// deviation of the data, which is optimal for normally distributed
+	// data.
+	Scott
+)
+
+// Bins returns the dividers of the bins chosen by the given rule for the data
+// in x with the given weights, spanning the range of x in equally wide bins.
+// The number of data points n used by the rules is the sum of the weights. If
+// weights is nil then all of the weights are 1. If weights is not nil, then
+// len(x) must equal len(weights). Bins will panic if x is empty.
+//
+// If the bin width chosen by the rule is zero, as for Freedman–Diaconis when
+// more than half the data are equal, the Sturges rule is used instead. If all
+// the values of x are equal, a single bin of unit width centered on the value
+// is returned.
+func Bins(rule BinRule, x, weights []float64) (dividers []float64) {
+	if len(x) == 0 {
+		panic("stat: zero length data")
+	}
+	if weights != nil && len(x) != len(weights) {
+		panic("stat: slice length mismatch")
+	}
+	lo, hi := floats.Min(x), floats.Max(x)
+	if lo == hi {
+		return []float64{lo - 0.5, hi + 0.5}
+	}
+	n := float64(len(x))
+	if weights != nil {
+		n = floats.Sum(weights)
+	}
+
+	var width float64
+	switch rule {
+	case FreedmanDiaconis:
+		s := make([]float64, len(x))
+		copy(s, x)
+		var w []float64
+		if weights != nil {
+			w = make([]float64, len(weights))
+			copy(w, weights)
+		}
+		SortWeighted(s, w)
+		iqr := Quantile(0.75, LinInterp, s, w) - Quantile(0.25, LinInterp, s, w)
+		width = 2 * iqr * math.Cbrt(1/n)
+	case Sturges:
+	case Scott:
+		width = 3.49 * StdDev(x, weights) * math.Cbrt(1/n)
+	default:
+		panic("stat: unknown bin rule")
+	}
+	var k int
+	if width > 0 {
+		k = int(math.Ceil((hi - lo) / width))
+	} else {
+		k = int(math.Ceil(math.Log2(n))) + 1
+	}
+	if k < 1 {
+		k = 1
+	}
+	return floats.Span(make([]float64, k+1), lo, hi)
+}
+
+// Hist is a histogram of weighted data points accumulated into bins. Unlike
+// the Histogram function, the data need not be sorted and may be added in any
+// number of calls.
+//
+// The bins are defined by increasing dividers, with the data point x placed
+// in bin j if dividers[j] <= x < dividers[j+1], except that the last bin also
+// includes its upper divider. Data points outside the range of the dividers
+// are counted separately as underflow and overflow.
+type Hist struct {
+	dividers []float64
+	counts   []float64
+
+	under, over float64
+}
+
+// NewHist returns a new empty histogram with the given bin dividers, which
+// must be sorted in increasing order. NewHist will panic if fewer than two
+// dividers are given or they are not increasing.
+func NewHist(dividers []float64) *Hist {
+	if len(dividers) < 2 {
+		panic("stat: fewer than two dividers")
+	}
+	for i := 1; i < len(dividers); i++ {
+		if !(dividers[i-1] < dividers[i]) {
+			panic("stat: dividers are not increasing")
+		}
+	}
+	d := make([]float64, len(dividers))
+	copy(d, dividers)
+	return &Hist{
+		dividers: d,
+		counts:   make([]float64, len(d)-1),
+	}
+}
+
+// NewHistFrom returns a histogram of the data in x with the given weights, with
+// bins chosen by the given rule as returned by Bins. If weights is nil then all
+// of the weights are 1. If weights is not nil, then len(x) must equal
+// len(weights).
+func NewHistFrom(rule BinRule, x, weights []float64) *Hist {
+	h := NewHist(Bins(rule, x, weights))
+	h.Fill(x, weights)
+	return h
+}
+
+// Add adds the data point x with the given weight to the histogram.
+func (h *Hist) Add(x, weight float64) {
+	last := len(h.dividers) - 1
+	switch {
+	case x < h.dividers[0]:
+		h.under += weight
+	case x > h.dividers[last]:
+		h.over += weight
+	case x == h.dividers[last]:
+		h.counts[last-1] += weight
+	default:
+		// Find the first divider greater than x.
+		j := sort.SearchFloat64s(h.dividers, math.Nextafter(x, math.Inf(1)))
+		h.counts[j-1] += weight
+	}
+}
+
+// Fill adds the data points in x with the given weights to the histogram. If
+// weights is nil then all of the weights are 1. If weights is not nil, then
+// len(x) must equal len(weights).
+func (h *Hist) Fill(x, weights []float64) {
+	if weights != nil && len(x) != len(weights) {
+		panic("stat: slice length mismatch")
+	}
+	for i, v := range x {
+		w := 1.0
+		if weights != nil {
+			w = weights[i]
+		}
+		h.Add(v, w)
+	}
+}
+
+// Reset sets all the counts of the histogram to zero.
+func (h *Hist) Reset() {
+	for i := range h.counts {
+		h.counts[i] = 0
+	}
+	h.under, h.over = 0, 0
+}
+
+// Dividers returns the bin dividers of the histogram. If dst is nil, a new
+// slice is allocated, otherwise dst must have length one more than the number
+// of bins.
+func (h *Hist) Dividers(dst []float64) []float64 {
+	return histCopy(dst, h.dividers)
+}
+
+// Counts returns the weighted counts of the data points in each bin. If dst
+// is nil, a new slice is allocated, otherwise dst must have length equal to
+// the number of bins.
+func (h *Hist) Counts(dst []float64) []float64 {
+	return histCopy(dst, h.counts)
+}
+
+// Cumulative returns the cumulative weighted counts of the bins, so that
+// dst[j] is the sum of the counts of the bins up to and including bin j. If
+// dst is nil, a new slice is allocated, otherwise dst must have length equal
+// to the number of bins.
+func (h *Hist) Cumulative(dst []float64) []float64 {
+	dst = histCopy(dst, h.counts)
+	floats.CumSum(dst, dst)
+	return dst
+}
+
+// Density returns the probability density estimated by the histogram, the
+// count of each bin divided by the bin width and the total count of the bins.
+// If dst is nil, a new slice is allocated, otherwise dst must have length
+// equal to the number of bins.
+func (h *Hist) Density(dst []float64) []float64 {
+	dst = histCopy(dst, h.counts)
+	total := h.Total()
+	for j := range dst {
+		dst[j] /= total * (h.dividers[j+1] - h.dividers[j])
+	}
+	return dst
+}
+
+func histCopy(dst, src []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, len(src))
+	}
+	if len(dst) != len(src) {
+		panic("stat: slice length mismatch")
+	}
+	copy(dst, src)
+	return dst
+}
+
+// Total returns the sum of the counts of the bins, excluding the data points
+// outside the range of the dividers.
+func (h *Hist) Total() float64 {
+	return floats.Sum(h.counts)
+}
+
+// Outliers returns the weighted counts of the data points that were below the
+// lowest divider and above the highest divider.
+func (h *Hist) Outliers() (under, over float64) {
+	return h.under, h.over
+}
+
+// Quantile returns an estimate of the p quantile of the binned data, assuming
+// that the data points are uniformly distributed within each bin. Data points
+// outside the range of the dividers are ignored. Quantile returns NaN if the
+// bins are empty and will panic if p is not in [0, 1].
+func (h *Hist) Quantile(p float64) float64 {
+	if !(p >= 0 && p <= 1) {
+		panic("stat: percentile out of bounds")
+	}
+	total := h.Total()
+	if total == 0 {
+		return math.NaN()
+	}
+	target := p * total
+	var cum float64
+	for j, c := range h.counts {
+		if c > 0 && cum+c >= target {
+			frac := (target - cum) / c
+			return h.dividers[j] + frac*(h.dividers[j+1]-h.dividers[j])
+		}
+		cum += c
+	}
+	return h.dividers[len(h.dividers)-1]
+}
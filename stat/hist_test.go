@@ -0,0 +1,135 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestBins(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	x := make([]float64, 1000)
+	for i := range x {
+		x[i] = rnd.NormFloat64()
+	}
+	lo, hi := floats.Min(x), floats.Max(x)
+	n := float64(len(x))
+	s := append([]float64(nil), x...)
+	sort.Float64s(s)
+	iqr := Quantile(0.75, LinInterp, s, nil) - Quantile(0.25, LinInterp, s, nil)
+	for _, test := range []struct {
+		rule BinRule
+		want int
+	}{
+		{rule: Sturges, want: 11},
+		{rule: Scott, want: int(math.Ceil((hi - lo) / (3.49 * StdDev(x, nil) / math.Cbrt(n))))},
+		{rule: FreedmanDiaconis, want: int(math.Ceil((hi - lo) / (2 * iqr / math.Cbrt(n))))},
+	} {
+		d := Bins(test.rule, x, nil)
+		if len(d)-1 != test.want {
+			t.Errorf("rule %d: unexpected number of bins: got %d, want %d", test.rule, len(d)-1, test.want)
+		}
+		if d[0] != lo || d[len(d)-1] != hi {
+			t.Errorf("rule %d: bins do not span the data: [%v, %v]", test.rule, d[0], d[len(d)-1])
+		}
+	}
+
+	// Weights change the effective number of data points.
+	w := make([]float64, len(x))
+	for i := range w {
+		w[i] = 4
+	}
+	if got := len(Bins(Sturges, x, w)) - 1; got != 13 {
+		t.Errorf("unexpected number of weighted Sturges bins: got %d, want 13", got)
+	}
+
+	// Degenerate data.
+	if d := Bins(FreedmanDiaconis, []float64{2, 2, 2}, nil); !floats.Equal(d, []float64{1.5, 2.5}) {
+		t.Errorf("unexpected bins for constant data: %v", d)
+	}
+	if d := Bins(FreedmanDiaconis, []float64{0, 1, 1, 1, 1, 1, 1, 1, 1, 2}, nil); len(d)-1 != 5 {
+		t.Errorf("unexpected fallback to Sturges: got %d bins, want 5", len(d)-1)
+	}
+}
+
+func TestHist(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	x := make([]float64, 500)
+	weights := make([]float64, len(x))
+	for i := range x {
+		x[i] = 10 * rnd.Float64()
+		weights[i] = rnd.Float64()
+	}
+	dividers := []float64{0, 1, 2.5, 4, 7, 10}
+	for _, w := range [][]float64{nil, weights} {
+		h := NewHist(dividers)
+		h.Fill(x, w)
+
+		s := append([]float64(nil), x...)
+		var sw []float64
+		if w != nil {
+			sw = append([]float64(nil), w...)
+		}
+		SortWeighted(s, sw)
+		want := Histogram(nil, dividers, s, sw)
+		got := h.Counts(nil)
+		if !floats.EqualApprox(got, want, 1e-12) {
+			t.Errorf("weighted=%t: unexpected counts:\ngot  %v\nwant %v", w != nil, got, want)
+		}
+		cum := h.Cumulative(nil)
+		if math.Abs(cum[len(cum)-1]-h.Total()) > 1e-12 {
+			t.Errorf("weighted=%t: cumulative count does not end at total", w != nil)
+		}
+		dens := h.Density(nil)
+		var area float64
+		for j, v := range dens {
+			area += v * (dividers[j+1] - dividers[j])
+		}
+		if math.Abs(area-1) > 1e-12 {
+			t.Errorf("weighted=%t: density does not integrate to one: %v", w != nil, area)
+		}
+	}
+
+	h := NewHist([]float64{0, 1, 2})
+	h.Fill([]float64{-1, 0, 0.5, 1, 2, 3, 4}, []float64{1, 2, 3, 4, 5, 6, 7})
+	if got := h.Counts(nil); !floats.Equal(got, []float64{5, 9}) {
+		t.Errorf("unexpected counts with edge values: %v", got)
+	}
+	if under, over := h.Outliers(); under != 1 || over != 13 {
+		t.Errorf("unexpected outliers: under=%v over=%v", under, over)
+	}
+	h.Reset()
+	if h.Total() != 0 || !math.IsNaN(h.Quantile(0.5)) {
+		t.Errorf("histogram not empty after reset")
+	}
+
+	// Quantiles of binned uniform data are close to those of the data.
+	u := make([]float64, 100000)
+	for i := range u {
+		u[i] = rnd.Float64()
+	}
+	h = NewHistFrom(FreedmanDiaconis, u, nil)
+	sort.Float64s(u)
+	for _, p := range []float64{0, 0.1, 0.5, 0.9, 1} {
+		got := h.Quantile(p)
+		want := Quantile(p, LinInterp, u, nil)
+		if math.Abs(got-want) > 1e-2 {
+			t.Errorf("unexpected quantile %v: got %v, want %v", p, got, want)
+		}
+	}
+
+	if !panics(func() { NewHist([]float64{0, 0}) }) {
+		t.Errorf("expected panic for non-increasing dividers")
+	}
+	if !panics(func() { h.Quantile(1.5) }) {
+		t.Errorf("expected panic for quantile out of range")
+	}
+}
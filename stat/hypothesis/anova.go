@@ -0,0 +1,328 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// ANOVAResult is the result of a one-way analysis of variance.
+type ANOVAResult struct {
+	// Statistic is the F statistic, the ratio of the between-group
+	// and within-group mean squares.
+	Statistic float64
+	// DoFBetween and DoFWithin are the numbers of degrees of freedom
+	// between and within the groups.
+	DoFBetween, DoFWithin float64
+	// SSBetween and SSWithin are the sums of squares between and
+	// within the groups.
+	SSBetween, SSWithin float64
+	// PValue is the probability under the null hypothesis of a
+	// statistic at least as large as the one observed.
+	PValue float64
+	// EtaSquared is the effect size, the fraction of the total sum
+	// of squares explained by the groups.
+	EtaSquared float64
+}
+
+// OneWayANOVA performs a one-way analysis of variance of the null hypothesis
+// that the samples in groups are drawn from normal distributions with equal
+// means and a common variance. OneWayANOVA will panic if there are fewer than
+// two groups, any group is empty, or there are no more observations than
+// groups.
+func OneWayANOVA(groups [][]float64) ANOVAResult {
+	k := len(groups)
+	if k < 2 {
+		panic("hypothesis: too few groups")
+	}
+	var n int
+	var total float64
+	for _, g := range groups {
+		if len(g) == 0 {
+			panic("hypothesis: empty group")
+		}
+		n += len(g)
+		for _, v := range g {
+			total += v
+		}
+	}
+	if n <= k {
+		panic("hypothesis: too few samples")
+	}
+	grand := total / float64(n)
+	var ssb, ssw float64
+	for _, g := range groups {
+		mean := stat.Mean(g, nil)
+		d := mean - grand
+		ssb += float64(len(g)) * d * d
+		for _, v := range g {
+			d := v - mean
+			ssw += d * d
+		}
+	}
+	dfb, dfw := float64(k-1), float64(n-k)
+	f := (ssb / dfb) / (ssw / dfw)
+	return ANOVAResult{
+		Statistic:  f,
+		DoFBetween: dfb,
+		DoFWithin:  dfw,
+		SSBetween:  ssb,
+		SSWithin:   ssw,
+		PValue:     distuv.F{D1: dfb, D2: dfw}.Survival(f),
+		EtaSquared: ssb / (ssb + ssw),
+	}
+}
+
+// TukeyPair is the comparison of a pair of groups in Tukey's honest
+// significant difference test.
+type TukeyPair struct {
+	// I and J are the indices of the compared groups, with I < J.
+	I, J int
+	// Diff is the difference between the means of group J and group I.
+	Diff float64
+	// Lower and Upper are the bounds of the simultaneous confidence
+	// interval for the difference.
+	Lower, Upper float64
+	// PValue is the p-value of the difference adjusted for multiple
+	// comparisons.
+	PValue float64
+}
+
+// TukeyHSD performs Tukey's honest significant difference test comparing
+// the means of all pairs of the samples in groups, using the within-group
+// variance of a one-way analysis of variance. The p-values and simultaneous
+// confidence intervals at the confidence level conf, which must be in (0, 1),
+// are computed from the studentized range distribution. For groups of
+// unequal size the Tukey–Kramer adjustment is used. The comparisons are
+// returned in the order (0, 1), (0, 2), ..., (1, 2), ....
+//
+// TukeyHSD will panic under the same conditions as OneWayANOVA.
+func TukeyHSD(groups [][]float64, conf float64) []TukeyPair {
+	if !(0 < conf && conf < 1) {
+		panic("hypothesis: confidence level out of range")
+	}
+	anova := OneWayANOVA(groups)
+	k := float64(len(groups))
+	msw := anova.SSWithin / anova.DoFWithin
+	crit := qtukey(conf, k, anova.DoFWithin)
+
+	means := make([]float64, len(groups))
+	for i, g := range groups {
+		means[i] = stat.Mean(g, nil)
+	}
+	pairs := make([]TukeyPair, 0, len(groups)*(len(groups)-1)/2)
+	for i := range groups {
+		for j := i + 1; j < len(groups); j++ {
+			diff := means[j] - means[i]
+			se := math.Sqrt(msw / 2 * (1/float64(len(groups[i])) + 1/float64(len(groups[j]))))
+			pairs = append(pairs, TukeyPair{
+				I:      i,
+				J:      j,
+				Diff:   diff,
+				Lower:  diff - crit*se,
+				Upper:  diff + crit*se,
+				PValue: clamp01(1 - ptukey(math.Abs(diff)/se, k, anova.DoFWithin)),
+			})
+		}
+	}
+	return pairs
+}
+
+// qtukey returns the quantile of the studentized range distribution for
+// nmeans means and df degrees of freedom at probability p.
+func qtukey(p, nmeans, df float64) float64 {
+	lo, hi := 0.0, 1.0
+	for ptukey(hi, nmeans, df) < p {
+		lo = hi
+		hi *= 2
+	}
+	for i := 0; i < 100 && hi-lo > 1e-10*hi; i++ {
+		mid := (lo + hi) / 2
+		if ptukey(mid, nmeans, df) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// ptukey returns the cumulative distribution function of the studentized
+// range distribution for nmeans means and df degrees of freedom at q.
+//
+// The algorithm is described in
+//  Copenhaver, M. D. and Holland, B. S. (1988). Computation of the
+//  distribution of the maximum studentized range statistic with application
+//  to multiple significance testing of simple effects. Journal of
+//  Statistical Computation and Simulation, 30, 1-15.
+func ptukey(q, nmeans, df float64) float64 {
+	if q <= 0 {
+		return 0
+	}
+	if math.IsInf(q, 1) {
+		return 1
+	}
+	if df > 25000 {
+		return wprob(q, nmeans)
+	}
+
+	// Integrate the range probability against the density of the
+	// scaled chi distribution of the standard error using Gauss–Legendre
+	// quadrature over subintervals.
+	f2 := df / 2
+	lg, _ := math.Lgamma(f2)
+	f2lf := f2*math.Log(df) - df*math.Ln2 - lg
+	f21 := f2 - 1
+	ff4 := df / 4
+	var ulen float64
+	switch {
+	case df <= 100:
+		ulen = 1
+	case df <= 800:
+		ulen = 0.5
+	case df <= 5000:
+		ulen = 0.25
+	default:
+		ulen = 0.125
+	}
+	f2lf += math.Log(ulen)
+
+	var ans float64
+	for i := 1; i <= 50; i++ {
+		var otsum float64
+		twa1 := float64(2*i-1) * ulen
+		for jj := 0; jj < 2*len(legendre16X); jj++ {
+			j := jj % len(legendre16X)
+			xu := legendre16X[j] * ulen
+			if jj < len(legendre16X) {
+				xu = -xu
+			}
+			t1 := f2lf + f21*math.Log(twa1+xu) - (xu+twa1)*ff4
+			if t1 < -30 {
+				continue
+			}
+			wprb := wprob(q*math.Sqrt((xu+twa1)/2), nmeans)
+			otsum += wprb * legendre16W[j] * math.Exp(t1)
+		}
+		// At least 1/ulen intervals are summed to avoid missing
+		// the area in the left tail.
+		if float64(i)*ulen >= 1 && otsum <= 1e-14 {
+			break
+		}
+		ans += otsum
+	}
+	return math.Min(ans, 1)
+}
+
+// wprob returns the probability that the range of nmeans independent
+// standard normal variables is less than w.
+func wprob(w, nmeans float64) float64 {
+	const (
+		bb   = 8.0
+		wlar = 3.0
+	)
+	qsqz := w / 2
+	if qsqz >= bb {
+		return 1
+	}
+
+	// The probability that all the variables lie within w/2 of zero.
+	prw := 2*distuv.UnitNormal.CDF(qsqz) - 1
+	if prw >= math.Exp(-50/nmeans) {
+		prw = math.Pow(prw, nmeans)
+	} else {
+		prw = 0
+	}
+
+	wincr := 3.0
+	if w > wlar {
+		wincr = 2
+	}
+	blb := qsqz
+	binc := (bb - qsqz) / wincr
+	bub := blb + binc
+	var einsum float64
+	cc1 := nmeans - 1
+	for wi := 1.0; wi <= wincr; wi++ {
+		var elsum float64
+		a := (bub + blb) / 2
+		b := (bub - blb) / 2
+		// Visit the nodes in increasing order so that the
+		// remaining nodes can be skipped once the normal
+		// density is negligible.
+		for jj := 0; jj < 2*len(legendre12X); jj++ {
+			var xx float64
+			j := jj
+			if jj < len(legendre12X) {
+				xx = -legendre12X[j]
+			} else {
+				j = 2*len(legendre12X) - 1 - jj
+				xx = legendre12X[j]
+			}
+			ac := a + b*xx
+			qexpo := ac * ac
+			if qexpo > 60 {
+				break
+			}
+			rinsum := distuv.UnitNormal.CDF(ac) - distuv.UnitNormal.CDF(ac-w)
+			if rinsum >= math.Exp(-30/cc1) {
+				elsum += legendre12W[j] * math.Exp(-qexpo/2) * math.Pow(rinsum, cc1)
+			}
+		}
+		einsum += elsum * 2 * b * nmeans / math.Sqrt(2*math.Pi)
+		blb = bub
+		bub += binc
+	}
+
+	prw += einsum
+	if prw <= math.Exp(-30) {
+		return 0
+	}
+	return math.Min(prw, 1)
+}
+
+// Nodes and weights of Gauss–Legendre quadrature of orders 12 and 16 for
+// the positive half of [-1, 1], in decreasing order of the nodes.
+var (
+	legendre12X = []float64{
+		0.981560634246719250690549090149,
+		0.904117256370474856678465866119,
+		0.769902674194304687036893833213,
+		0.587317954286617447296702418941,
+		0.367831498998180193752691536644,
+		0.125233408511468915472441369464,
+	}
+	legendre12W = []float64{
+		0.047175336386511827194615961485,
+		0.106939325995318430960254718194,
+		0.160078328543346226334652529543,
+		0.203167426723065921749064455810,
+		0.233492536538354808760849898925,
+		0.249147045813402785000562436043,
+	}
+	legendre16X = []float64{
+		0.989400934991649932596154173450,
+		0.944575023073232576077988415535,
+		0.865631202387831743880467897712,
+		0.755404408355003033895101194847,
+		0.617876244402643748446671764049,
+		0.458016777657227386342419442984,
+		0.281603550779258913230460501460,
+		0.950125098376374401853193354250e-1,
+	}
+	legendre16W = []float64{
+		0.271524594117540948517805724560e-1,
+		0.622535239386478928628438369944e-1,
+		0.951585116824927848099251076022e-1,
+		0.124628971255533872052476282192,
+		0.149595988816576732081501730547,
+		0.169156519395002538189312079030,
+		0.182603415044923588866763667969,
+		0.189450610455068496285396723208,
+	}
+)
@@ -0,0 +1,131 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestOneWayANOVA(t *testing.T) {
+	// The group means are 2, 5 and 8 about a grand mean of 5, so the
+	// between-group sum of squares is 3*(9+0+9) = 54 and the within-group
+	// sum of squares is 3*2 = 6. The survival function of the F(2, d)
+	// distribution is (1 + 2f/d)^(-d/2).
+	groups := [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}
+	got := OneWayANOVA(groups)
+	want := ANOVAResult{
+		Statistic:  27,
+		DoFBetween: 2,
+		DoFWithin:  6,
+		SSBetween:  54,
+		SSWithin:   6,
+		PValue:     0.001,
+		EtaSquared: 0.9,
+	}
+	for _, v := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"statistic", got.Statistic, want.Statistic},
+		{"between degrees of freedom", got.DoFBetween, want.DoFBetween},
+		{"within degrees of freedom", got.DoFWithin, want.DoFWithin},
+		{"between sum of squares", got.SSBetween, want.SSBetween},
+		{"within sum of squares", got.SSWithin, want.SSWithin},
+		{"p-value", got.PValue, want.PValue},
+		{"effect size", got.EtaSquared, want.EtaSquared},
+	} {
+		if math.Abs(v.got-v.want) > 1e-12 {
+			t.Errorf("unexpected %s: got %v, want %v", v.name, v.got, v.want)
+		}
+	}
+
+	for _, g := range [][][]float64{
+		{{1, 2, 3}},
+		{{1, 2}, {}},
+		{{1}, {2}},
+	} {
+		if !panics(func() { OneWayANOVA(g) }) {
+			t.Errorf("expected panic for groups %v", g)
+		}
+	}
+}
+
+func TestStudentizedRange(t *testing.T) {
+	// With two means the studentized range is √2 times the absolute
+	// value of a Student's t variable.
+	for _, df := range []float64{3, 10, 50} {
+		dist := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: df}
+		for _, q := range []float64{0.5, 1, 2.5, 4, 6} {
+			got := ptukey(q, 2, df)
+			want := 2*dist.CDF(q/math.Sqrt2) - 1
+			if math.Abs(got-want) > 1e-8 {
+				t.Errorf("unexpected CDF for two means, df=%v, q=%v: got %v, want %v", df, q, got, want)
+			}
+		}
+	}
+
+	// Critical values from tables of the studentized range at the 5% level.
+	for _, test := range []struct {
+		nmeans, df, want float64
+	}{
+		{nmeans: 3, df: 10, want: 3.877},
+		{nmeans: 4, df: 20, want: 3.958},
+		{nmeans: 5, df: 60, want: 3.977},
+	} {
+		got := qtukey(0.95, test.nmeans, test.df)
+		if math.Abs(got-test.want) > 1e-3 {
+			t.Errorf("unexpected critical value for %v means and df=%v: got %v, want %v", test.nmeans, test.df, got, test.want)
+		}
+	}
+}
+
+func TestTukeyHSD(t *testing.T) {
+	groups := [][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}, {1.5, 2.5}}
+	anova := OneWayANOVA(groups)
+	msw := anova.SSWithin / anova.DoFWithin
+	crit := qtukey(0.95, 4, anova.DoFWithin)
+	pairs := TukeyHSD(groups, 0.95)
+	if len(pairs) != 6 {
+		t.Fatalf("unexpected number of pairs: got %d, want 6", len(pairs))
+	}
+	var k int
+	for i := range groups {
+		for j := i + 1; j < len(groups); j++ {
+			p := pairs[k]
+			k++
+			if p.I != i || p.J != j {
+				t.Errorf("unexpected pair order: got (%d, %d), want (%d, %d)", p.I, p.J, i, j)
+				continue
+			}
+			diff := mean(groups[j]) - mean(groups[i])
+			se := math.Sqrt(msw / 2 * (1/float64(len(groups[i])) + 1/float64(len(groups[j]))))
+			if math.Abs(p.Diff-diff) > 1e-14 || math.Abs(p.Lower-(diff-crit*se)) > 1e-12 || math.Abs(p.Upper-(diff+crit*se)) > 1e-12 {
+				t.Errorf("unexpected interval for pair (%d, %d): got %+v", i, j, p)
+			}
+			// The interval excludes zero exactly when the
+			// difference is significant.
+			if (p.PValue < 0.05) != (p.Lower > 0 || p.Upper < 0) {
+				t.Errorf("p-value inconsistent with interval for pair (%d, %d): %+v", i, j, p)
+			}
+		}
+	}
+	if p := pairs[0].PValue; p > 0.05 {
+		t.Errorf("expected significant difference between first groups: p=%v", p)
+	}
+	if p := pairs[2].PValue; p < 0.5 {
+		t.Errorf("expected no significant difference between first and last groups: p=%v", p)
+	}
+}
+
+func mean(x []float64) float64 {
+	var s float64
+	for _, v := range x {
+		s += v
+	}
+	return s / float64(len(x))
+}
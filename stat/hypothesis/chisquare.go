@@ -0,0 +1,127 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// ChiSquareResult is the result of a Pearson's chi-square test.
+type ChiSquareResult struct {
+	// Statistic is the chi-square statistic.
+	Statistic float64
+	// DoF is the number of degrees of freedom of the reference
+	// chi-square distribution.
+	DoF float64
+	// PValue is the probability under the null hypothesis of a
+	// statistic at least as large as the one observed.
+	PValue float64
+}
+
+// ChiSquareGoodness performs Pearson's chi-square goodness-of-fit test of
+// the null hypothesis that the observed counts in obs are drawn from the
+// categorical distribution with the category probabilities in p. If p is nil
+// all categories are equally likely, otherwise len(p) must equal len(obs) and
+// p is normalized to sum to one. The statistic is
+//  Σ (obs_i - e_i)^2 / e_i,
+// where e_i is the expected count of category i, and is compared against a
+// chi-square distribution with len(obs)-1 degrees of freedom.
+//
+// The chi-square approximation is poor when any expected count is small,
+// typically less than five. ChiSquareGoodness will panic if obs has fewer
+// than two elements or any element of p is not positive.
+func ChiSquareGoodness(obs, p []float64) ChiSquareResult {
+	if len(obs) < 2 {
+		panic("hypothesis: too few categories")
+	}
+	if p != nil && len(p) != len(obs) {
+		panic("hypothesis: slice length mismatch")
+	}
+	n := floats.Sum(obs)
+	var sump float64
+	if p != nil {
+		for _, v := range p {
+			if !(v > 0) {
+				panic("hypothesis: non-positive probability")
+			}
+		}
+		sump = floats.Sum(p)
+	}
+	var chi2 float64
+	for i, o := range obs {
+		e := n / float64(len(obs))
+		if p != nil {
+			e = n * p[i] / sump
+		}
+		d := o - e
+		chi2 += d * d / e
+	}
+	return chiSquareResult(chi2, float64(len(obs)-1))
+}
+
+// ChiSquareIndependence performs Pearson's chi-square test of the null
+// hypothesis that the row and column variables of the contingency table of
+// counts in table are independent. The statistic is
+//  Σ (obs_ij - e_ij)^2 / e_ij,
+// where e_ij is the count expected from the row and column totals, and is
+// compared against a chi-square distribution with (r-1)*(c-1) degrees of
+// freedom for an r×c table.
+//
+// If yates is true and the table is 2×2, Yates' continuity correction is
+// applied, reducing each absolute difference |obs_ij - e_ij| by 0.5, or to
+// zero if it is smaller. ChiSquareIndependence will panic if the table has
+// fewer than two rows or columns, or if any row or column total is zero.
+func ChiSquareIndependence(table mat.Matrix, yates bool) ChiSquareResult {
+	r, c := table.Dims()
+	if r < 2 || c < 2 {
+		panic("hypothesis: too few categories")
+	}
+	rows := make([]float64, r)
+	cols := make([]float64, c)
+	var n float64
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			v := table.At(i, j)
+			rows[i] += v
+			cols[j] += v
+			n += v
+		}
+	}
+	for _, v := range rows {
+		if v == 0 {
+			panic("hypothesis: zero marginal total")
+		}
+	}
+	for _, v := range cols {
+		if v == 0 {
+			panic("hypothesis: zero marginal total")
+		}
+	}
+	correct := yates && r == 2 && c == 2
+	var chi2 float64
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			e := rows[i] * cols[j] / n
+			d := math.Abs(table.At(i, j) - e)
+			if correct {
+				d -= math.Min(0.5, d)
+			}
+			chi2 += d * d / e
+		}
+	}
+	return chiSquareResult(chi2, float64((r-1)*(c-1)))
+}
+
+func chiSquareResult(chi2, dof float64) ChiSquareResult {
+	return ChiSquareResult{
+		Statistic: chi2,
+		DoF:       dof,
+		PValue:    distuv.ChiSquared{K: dof}.Survival(chi2),
+	}
+}
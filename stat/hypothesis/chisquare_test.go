@@ -0,0 +1,98 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestChiSquareGoodness(t *testing.T) {
+	// Reference values computed with R's chisq.test.
+	obs := []float64{89, 37, 30, 28, 2}
+	got := ChiSquareGoodness(obs, []float64{40, 20, 20, 15, 5})
+	if !floats.EqualWithinAbsOrRel(got.Statistic, 9.9901, 1e-4, 1e-4) || got.DoF != 4 ||
+		!floats.EqualWithinAbsOrRel(got.PValue, 0.04059, 1e-4, 1e-4) {
+		t.Errorf("unexpected result: got %+v", got)
+	}
+
+	// Equal probabilities by default.
+	got = ChiSquareGoodness([]float64{10, 20, 30}, nil)
+	if math.Abs(got.Statistic-10) > 1e-14 || got.DoF != 2 || math.Abs(got.PValue-math.Exp(-5)) > 1e-14 {
+		t.Errorf("unexpected result for equal probabilities: got %+v", got)
+	}
+
+	if !panics(func() { ChiSquareGoodness([]float64{1}, nil) }) {
+		t.Errorf("expected panic for single category")
+	}
+	if !panics(func() { ChiSquareGoodness([]float64{1, 2}, []float64{1, 0}) }) {
+		t.Errorf("expected panic for zero probability")
+	}
+}
+
+func TestChiSquareIndependence(t *testing.T) {
+	table := mat.NewDense(2, 2, []float64{3, 1, 1, 3})
+	for _, test := range []struct {
+		yates bool
+		want  ChiSquareResult
+	}{
+		{yates: false, want: ChiSquareResult{Statistic: 2, DoF: 1, PValue: 0.1572992}},
+		{yates: true, want: ChiSquareResult{Statistic: 0.5, DoF: 1, PValue: 0.4795001}},
+	} {
+		got := ChiSquareIndependence(table, test.yates)
+		if math.Abs(got.Statistic-test.want.Statistic) > 1e-14 || got.DoF != test.want.DoF ||
+			math.Abs(got.PValue-test.want.PValue) > 1e-7 {
+			t.Errorf("yates=%t: unexpected result: got %+v, want %+v", test.yates, got, test.want)
+		}
+	}
+
+	// Perfectly independent tables have a zero statistic, and the
+	// correction is not applied to larger tables.
+	indep := mat.NewDense(2, 3, []float64{1, 2, 3, 2, 4, 6})
+	for _, yates := range []bool{false, true} {
+		got := ChiSquareIndependence(indep, yates)
+		if math.Abs(got.Statistic) > 1e-14 || got.DoF != 2 || math.Abs(got.PValue-1) > 1e-14 {
+			t.Errorf("yates=%t: unexpected result for independent table: got %+v", yates, got)
+		}
+	}
+
+	if !panics(func() { ChiSquareIndependence(mat.NewDense(2, 2, []float64{1, 0, 1, 0}), false) }) {
+		t.Errorf("expected panic for zero marginal total")
+	}
+}
+
+func TestFisherExact(t *testing.T) {
+	// Fisher's lady tasting tea.
+	table := mat.NewDense(2, 2, []float64{3, 1, 1, 3})
+	for _, test := range []struct {
+		alt  Alternative
+		want float64
+	}{
+		{alt: TwoSided, want: 34.0 / 70},
+		{alt: Greater, want: 17.0 / 70},
+		{alt: Less, want: 69.0 / 70},
+	} {
+		got := FisherExact(table, test.alt)
+		if got.OddsRatio != 9 || math.Abs(got.PValue-test.want) > 1e-14 {
+			t.Errorf("alternative %d: unexpected result: got %+v, want p-value %v", test.alt, got, test.want)
+		}
+	}
+
+	// Reference value computed with R's fisher.test.
+	got := FisherExact(mat.NewDense(2, 2, []float64{10, 2, 3, 15}), TwoSided)
+	if !floats.EqualWithinAbsOrRel(got.PValue, 0.0005367241, 1e-9, 1e-6) {
+		t.Errorf("unexpected p-value: got %v, want 0.0005367241", got.PValue)
+	}
+
+	if !panics(func() { FisherExact(mat.NewDense(2, 2, []float64{1, 2.5, 3, 4}), TwoSided) }) {
+		t.Errorf("expected panic for non-integer table")
+	}
+	if !panics(func() { FisherExact(mat.NewDense(2, 3, nil), TwoSided) }) {
+		t.Errorf("expected panic for non-2×2 table")
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat/combin"
+)
+
+// FisherResult is the result of Fisher's exact test.
+type FisherResult struct {
+	// OddsRatio is the sample odds ratio a*d/(b*c) of the
+	// table [[a, b], [c, d]]. It is infinite if b*c is zero,
+	// or NaN if a*d is also zero.
+	OddsRatio float64
+	// PValue is the probability under the null hypothesis of a
+	// table at least as extreme as the one observed.
+	PValue float64
+}
+
+// FisherExact performs Fisher's exact test of the null hypothesis that the
+// row and column variables of the 2×2 contingency table of counts in table
+// are independent, that is, that the odds ratio is one, against the given
+// alternative for the odds ratio. The p-value is computed from the
+// hypergeometric distribution of the top left count conditional on the row
+// and column totals. For the TwoSided alternative the p-value is the total
+// probability of the tables that are no more likely than the observed table.
+//
+// FisherExact will panic if table is not 2×2 or its elements are not
+// non-negative integers.
+func FisherExact(table mat.Matrix, alt Alternative) FisherResult {
+	r, c := table.Dims()
+	if r != 2 || c != 2 {
+		panic("hypothesis: table not 2×2")
+	}
+	var cells [4]int
+	for i := range cells {
+		v := table.At(i/2, i%2)
+		if v < 0 || v != math.Trunc(v) {
+			panic("hypothesis: table elements not non-negative integers")
+		}
+		cells[i] = int(v)
+	}
+	a, b, cc, d := cells[0], cells[1], cells[2], cells[3]
+	row1, col1, n := a+b, a+cc, a+b+cc+d
+
+	// The top left count is hypergeometric with support [lo, hi].
+	lo := col1 - (n - row1)
+	if lo < 0 {
+		lo = 0
+	}
+	hi := row1
+	if col1 < hi {
+		hi = col1
+	}
+	logDenom := combin.LogGeneralizedBinomial(float64(n), float64(col1))
+	prob := func(k int) float64 {
+		return math.Exp(combin.LogGeneralizedBinomial(float64(row1), float64(k)) +
+			combin.LogGeneralizedBinomial(float64(n-row1), float64(col1-k)) - logDenom)
+	}
+
+	var p float64
+	switch alt {
+	case TwoSided:
+		// Allow for rounding error when comparing with the
+		// probability of the observed table.
+		pa := prob(a) * (1 + 1e-7)
+		for k := lo; k <= hi; k++ {
+			if pk := prob(k); pk <= pa {
+				p += pk
+			}
+		}
+	case Less:
+		for k := lo; k <= a; k++ {
+			p += prob(k)
+		}
+	case Greater:
+		for k := a; k <= hi; k++ {
+			p += prob(k)
+		}
+	default:
+		panic("hypothesis: unknown alternative")
+	}
+	return FisherResult{
+		OddsRatio: float64(a*d) / float64(b*cc),
+		PValue:    clamp01(p),
+	}
+}
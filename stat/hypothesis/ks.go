@@ -0,0 +1,364 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Result is the result of a hypothesis test.
+type Result struct {
+	// Statistic is the test statistic.
+	Statistic float64
+	// PValue is the probability under the null hypothesis of a
+	// statistic at least as extreme as the one observed.
+	PValue float64
+}
+
+// CDFer is a univariate distribution with a cumulative distribution
+// function, such as the distributions in gonum/stat/distuv.
+type CDFer interface {
+	CDF(x float64) float64
+}
+
+const (
+	// ksExactMax is the largest sample size for which the exact
+	// one-sample Kolmogorov–Smirnov distribution is used.
+	ksExactMax = 100
+	// ksExactMax2 is the largest product of the sample sizes for which
+	// the exact two-sample distribution is used.
+	ksExactMax2 = 10000
+)
+
+// OneSampleKS performs a one-sample Kolmogorov–Smirnov test of the null
+// hypothesis that the data in x are drawn from the distribution dist, which
+// must be continuous. For the TwoSided alternative the statistic is the
+// largest absolute difference between the empirical CDF of x and the CDF of
+// dist. For the Greater alternative, that the CDF of the population lies
+// above the CDF of dist, the statistic is the largest difference F_n(t) - F(t),
+// and for Less it is the largest difference F(t) - F_n(t).
+//
+// The p-value is exact when len(x) is less than 100 and x contains no
+// repeated values, and is otherwise computed from the asymptotic
+// distribution of the statistic. OneSampleKS will panic if x is empty.
+func OneSampleKS(x []float64, dist CDFer, alt Alternative) Result {
+	if len(x) == 0 {
+		panic("hypothesis: too few samples")
+	}
+	s := make([]float64, len(x))
+	copy(s, x)
+	sort.Float64s(s)
+	n := float64(len(s))
+	var dPlus, dMinus float64
+	for i, v := range s {
+		f := dist.CDF(v)
+		dPlus = math.Max(dPlus, float64(i+1)/n-f)
+		dMinus = math.Max(dMinus, f-float64(i)/n)
+	}
+	exact := len(s) < ksExactMax && !hasTies(s)
+	var d, p float64
+	switch alt {
+	case TwoSided:
+		d = math.Max(dPlus, dMinus)
+		if exact {
+			p = 1 - ksExactCDF(len(s), d)
+		} else {
+			p = kolmogorovSurvival(math.Sqrt(n) * d)
+		}
+	case Less, Greater:
+		d = dPlus
+		if alt == Less {
+			d = dMinus
+		}
+		if exact {
+			p = ksOneSidedSurvival(len(s), d)
+		} else {
+			p = math.Exp(-2 * n * d * d)
+		}
+	default:
+		panic("hypothesis: unknown alternative")
+	}
+	return Result{Statistic: d, PValue: clamp01(p)}
+}
+
+// TwoSampleKS performs a two-sample Kolmogorov–Smirnov test of the null
+// hypothesis that the data in x and y are drawn from the same continuous
+// distribution. For the TwoSided alternative the statistic is the largest
+// absolute difference between the empirical CDFs of x and y. For the Greater
+// alternative, that the CDF of the population of x lies above that of y, the
+// statistic is the largest difference F_x(t) - F_y(t), and for Less it is the
+// largest difference F_y(t) - F_x(t).
+//
+// The p-value is exact when len(x)*len(y) is less than 10000 and there are no
+// repeated values, and is otherwise computed from the asymptotic
+// distribution of the statistic. TwoSampleKS will panic if x or y is empty.
+func TwoSampleKS(x, y []float64, alt Alternative) Result {
+	if len(x) == 0 || len(y) == 0 {
+		panic("hypothesis: too few samples")
+	}
+	sx := make([]float64, len(x))
+	copy(sx, x)
+	sort.Float64s(sx)
+	sy := make([]float64, len(y))
+	copy(sy, y)
+	sort.Float64s(sy)
+
+	nx, ny := float64(len(sx)), float64(len(sy))
+	var dPlus, dMinus float64
+	var i, j int
+	for i < len(sx) && j < len(sy) {
+		// Advance past all copies of the smallest remaining value
+		// so that ties between samples are handled correctly.
+		v := math.Min(sx[i], sy[j])
+		for i < len(sx) && sx[i] == v {
+			i++
+		}
+		for j < len(sy) && sy[j] == v {
+			j++
+		}
+		diff := float64(i)/nx - float64(j)/ny
+		dPlus = math.Max(dPlus, diff)
+		dMinus = math.Max(dMinus, -diff)
+	}
+
+	merged := make([]float64, 0, len(sx)+len(sy))
+	merged = append(merged, sx...)
+	merged = append(merged, sy...)
+	sort.Float64s(merged)
+	exact := len(sx)*len(sy) < ksExactMax2 && !hasTies(merged)
+
+	neff := nx * ny / (nx + ny)
+	var d, p float64
+	switch alt {
+	case TwoSided:
+		d = math.Max(dPlus, dMinus)
+		if exact {
+			p = 1 - ksExactCDF2(len(sx), len(sy), d, TwoSided)
+		} else {
+			p = kolmogorovSurvival(math.Sqrt(neff) * d)
+		}
+	case Less, Greater:
+		d = dPlus
+		if alt == Less {
+			d = dMinus
+		}
+		if exact {
+			p = 1 - ksExactCDF2(len(sx), len(sy), d, alt)
+		} else {
+			p = math.Exp(-2 * neff * d * d)
+		}
+	default:
+		panic("hypothesis: unknown alternative")
+	}
+	return Result{Statistic: d, PValue: clamp01(p)}
+}
+
+// hasTies returns whether the sorted slice s contains repeated values.
+func hasTies(s []float64) bool {
+	for i := 1; i < len(s); i++ {
+		if s[i] == s[i-1] {
+			return true
+		}
+	}
+	return false
+}
+
+func clamp01(p float64) float64 {
+	return math.Max(0, math.Min(1, p))
+}
+
+// kolmogorovSurvival returns the probability that the Kolmogorov
+// distribution exceeds x.
+func kolmogorovSurvival(x float64) float64 {
+	if x <= 0 {
+		return 1
+	}
+	if x < 1 {
+		// Use the series for the CDF, which converges quickly for small x.
+		var cdf float64
+		z := -math.Pi * math.Pi / (8 * x * x)
+		for k := 1; k < 100; k += 2 {
+			t := math.Exp(float64(k*k) * z)
+			cdf += t
+			if t < 1e-17*cdf {
+				break
+			}
+		}
+		return 1 - math.Sqrt(2*math.Pi)/x*cdf
+	}
+	var p float64
+	sign := 1.0
+	for k := 1; k < 100; k++ {
+		t := math.Exp(-2 * float64(k*k) * x * x)
+		p += sign * t
+		if t < 1e-17 {
+			break
+		}
+		sign = -sign
+	}
+	return 2 * p
+}
+
+// ksOneSidedSurvival returns the exact probability that the one-sided
+// one-sample Kolmogorov–Smirnov statistic for n observations is at least d,
+// using the formula of Birnbaum and Tingey.
+func ksOneSidedSurvival(n int, d float64) float64 {
+	if d <= 0 {
+		return 1
+	}
+	if d >= 1 {
+		return 0
+	}
+	nf := float64(n)
+	lgn, _ := math.Lgamma(nf + 1)
+	var p float64
+	for j := 0; j <= int(math.Floor(nf*(1-d))); j++ {
+		jf := float64(j)
+		a := 1 - d - jf/nf
+		if a <= 0 {
+			continue
+		}
+		lgj, _ := math.Lgamma(jf + 1)
+		lgnj, _ := math.Lgamma(nf - jf + 1)
+		p += math.Exp(lgn - lgj - lgnj + (nf-jf)*math.Log(a) + (jf-1)*math.Log(d+jf/nf))
+	}
+	return d * p
+}
+
+// ksExactCDF returns the exact probability that the two-sided one-sample
+// Kolmogorov–Smirnov statistic for n observations is less than d.
+//
+// The algorithm is described in
+//  Marsaglia, G., Tsang, W. W. and Wang, J. (2003). Evaluating Kolmogorov's
+//  distribution. Journal of Statistical Software, 8(18).
+func ksExactCDF(n int, d float64) float64 {
+	nf := float64(n)
+	if d <= 0.5/nf {
+		return 0
+	}
+	if d >= 1 {
+		return 1
+	}
+	k := int(nf*d) + 1
+	m := 2*k - 1
+	h := float64(k) - nf*d
+
+	hm := mat.NewDense(m, m, nil)
+	for i := 0; i < m; i++ {
+		for j := 0; j < m; j++ {
+			if i-j+1 >= 0 {
+				hm.Set(i, j, 1)
+			}
+		}
+	}
+	for i := 0; i < m; i++ {
+		hm.Set(i, 0, hm.At(i, 0)-math.Pow(h, float64(i+1)))
+		hm.Set(m-1, i, hm.At(m-1, i)-math.Pow(h, float64(m-i)))
+	}
+	if 2*h-1 > 0 {
+		hm.Set(m-1, 0, hm.At(m-1, 0)+math.Pow(2*h-1, float64(m)))
+	}
+	for i := 0; i < m; i++ {
+		for j := 0; j < m; j++ {
+			if i-j+1 > 0 {
+				v := hm.At(i, j)
+				for g := 1; g <= i-j+1; g++ {
+					v /= float64(g)
+				}
+				hm.Set(i, j, v)
+			}
+		}
+	}
+
+	q, e := ksMatPow(hm, n)
+	s := q.At(k-1, k-1)
+	for i := 1; i <= n; i++ {
+		s *= float64(i) / nf
+		if s < 1e-140 {
+			s *= 1e140
+			e -= 140
+		}
+	}
+	return s * math.Pow(10, float64(e))
+}
+
+// ksMatPow returns a and e such that a * 10^e is the nth power of m,
+// rescaling during the computation to avoid overflow.
+func ksMatPow(m *mat.Dense, n int) (*mat.Dense, int) {
+	if n == 1 {
+		return mat.DenseCopyOf(m), 0
+	}
+	half, e := ksMatPow(m, n/2)
+	var v mat.Dense
+	v.Mul(half, half)
+	e *= 2
+	if n%2 == 1 {
+		var w mat.Dense
+		w.Mul(m, &v)
+		v.CloneFrom(&w)
+	}
+	r, _ := v.Dims()
+	if v.At(r/2, r/2) > 1e140 {
+		v.Scale(1e-140, &v)
+		e += 140
+	}
+	return &v, e
+}
+
+// ksExactCDF2 returns the exact probability that the two-sample
+// Kolmogorov–Smirnov statistic for samples of sizes m and n with no ties is
+// less than d, for the two-sided statistic or the one-sided statistic
+// corresponding to alt.
+func ksExactCDF2(m, n int, d float64, alt Alternative) float64 {
+	mf, nf := float64(m), float64(n)
+	// The statistic is a multiple of 1/(m*n), so q lies strictly between
+	// d and the next smaller possible value.
+	q := (0.5 + math.Floor(d*mf*nf-1e-7)) / (mf * nf)
+
+	// exceeds reports whether the lattice point with i observations from
+	// the first sample and j from the second lies outside the region
+	// where the statistic is less than d.
+	exceeds := func(i, j int) bool {
+		diff := float64(i)/mf - float64(j)/nf
+		switch alt {
+		case Greater:
+			return diff > q
+		case Less:
+			return -diff > q
+		default:
+			return math.Abs(diff) > q
+		}
+	}
+
+	// u[j] holds the probability of reaching the lattice point (i, j)
+	// without leaving the region, normalized by the number of paths.
+	u := make([]float64, n+1)
+	for j := range u {
+		if !exceeds(0, j) {
+			u[j] = 1
+		} else {
+			break
+		}
+	}
+	for i := 1; i <= m; i++ {
+		w := float64(i) / float64(i+n)
+		if exceeds(i, 0) {
+			u[0] = 0
+		} else {
+			u[0] *= w
+		}
+		for j := 1; j <= n; j++ {
+			if exceeds(i, j) {
+				u[j] = 0
+			} else {
+				u[j] = w*u[j] + u[j-1]
+			}
+		}
+	}
+	return u[n]
+}
@@ -0,0 +1,121 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestKSExactDistribution(t *testing.T) {
+	// Example from Marsaglia, Tsang and Wang (2003).
+	if got, want := ksExactCDF(10, 0.274), 0.6284796154565043; math.Abs(got-want) > 1e-12 {
+		t.Errorf("unexpected exact CDF: got %v, want %v", got, want)
+	}
+	// For a single observation P(D < d) = 2d - 1 for d in [1/2, 1].
+	for _, d := range []float64{0.55, 0.7, 0.9} {
+		if got, want := ksExactCDF(1, d), 2*d-1; math.Abs(got-want) > 1e-14 {
+			t.Errorf("unexpected exact CDF for one observation at %v: got %v, want %v", d, got, want)
+		}
+		if got, want := ksOneSidedSurvival(1, d), 1-d; math.Abs(got-want) > 1e-14 {
+			t.Errorf("unexpected one-sided survival for one observation at %v: got %v, want %v", d, got, want)
+		}
+	}
+	// The exact distribution approaches the asymptotic distribution.
+	for _, d := range []float64{0.08, 0.12, 0.16} {
+		got := 1 - ksExactCDF(99, d)
+		want := kolmogorovSurvival(math.Sqrt(99) * d)
+		if math.Abs(got-want) > 0.03 {
+			t.Errorf("exact and asymptotic p-values differ at %v: got %v, want %v", d, got, want)
+		}
+	}
+	// Both series for the Kolmogorov distribution agree near the switch.
+	below := kolmogorovSurvival(math.Nextafter(1, 0))
+	if above := kolmogorovSurvival(1); math.Abs(below-above) > 1e-14 {
+		t.Errorf("Kolmogorov survival discontinuous at 1: %v != %v", below, above)
+	}
+}
+
+func TestTwoSampleKS(t *testing.T) {
+	// With x = {1, 2} and y = {3, 4} only two of the six orderings of the
+	// pooled sample reach a two-sided statistic of one, and one ordering
+	// reaches a one-sided statistic of one.
+	x := []float64{2, 1}
+	y := []float64{4, 3}
+	for _, test := range []struct {
+		alt  Alternative
+		want Result
+	}{
+		{alt: TwoSided, want: Result{Statistic: 1, PValue: 1.0 / 3}},
+		{alt: Greater, want: Result{Statistic: 1, PValue: 1.0 / 6}},
+		{alt: Less, want: Result{Statistic: 0, PValue: 1}},
+	} {
+		got := TwoSampleKS(x, y, test.alt)
+		if math.Abs(got.Statistic-test.want.Statistic) > 1e-14 || math.Abs(got.PValue-test.want.PValue) > 1e-14 {
+			t.Errorf("alternative %d: unexpected result: got %+v, want %+v", test.alt, got, test.want)
+		}
+	}
+
+	// Ties between samples are not counted as differences.
+	got := TwoSampleKS([]float64{1, 2, 3}, []float64{1, 2, 3}, TwoSided)
+	if got.Statistic != 0 || got.PValue != 1 {
+		t.Errorf("unexpected result for identical samples: %+v", got)
+	}
+}
+
+func TestKSCalibration(t *testing.T) {
+	// Under the null hypothesis the p-values are close to uniformly
+	// distributed, so about 5% are below 0.05.
+	rnd := rand.New(rand.NewSource(1))
+	unit := distuv.Uniform{Min: 0, Max: 1}
+	const reps = 2000
+	for _, alt := range []Alternative{TwoSided, Less, Greater} {
+		for _, n := range []int{10, 150} {
+			var one, two int
+			x := make([]float64, n)
+			y := make([]float64, n+3)
+			for r := 0; r < reps; r++ {
+				for i := range x {
+					x[i] = rnd.Float64()
+				}
+				for i := range y {
+					y[i] = rnd.Float64()
+				}
+				if OneSampleKS(x, unit, alt).PValue < 0.05 {
+					one++
+				}
+				if TwoSampleKS(x, y, alt).PValue < 0.05 {
+					two++
+				}
+			}
+			// The tests are conservative for discrete statistics.
+			if rate := float64(one) / reps; rate < 0.025 || rate > 0.065 {
+				t.Errorf("alternative %d, n=%d: unexpected one-sample rejection rate: %v", alt, n, rate)
+			}
+			if rate := float64(two) / reps; rate < 0.025 || rate > 0.065 {
+				t.Errorf("alternative %d, n=%d: unexpected two-sample rejection rate: %v", alt, n, rate)
+			}
+		}
+	}
+
+	// A shifted sample is detected.
+	x := make([]float64, 50)
+	for i := range x {
+		x[i] = rnd.NormFloat64() + 1
+	}
+	if p := OneSampleKS(x, distuv.UnitNormal, TwoSided).PValue; p > 1e-3 {
+		t.Errorf("shifted sample not detected: p=%v", p)
+	}
+	if p := OneSampleKS(x, distuv.UnitNormal, Less).PValue; p > 1e-3 {
+		t.Errorf("shifted sample not detected by one-sided test: p=%v", p)
+	}
+	if p := OneSampleKS(x, distuv.UnitNormal, Greater).PValue; p < 0.5 {
+		t.Errorf("shifted sample detected in wrong direction: p=%v", p)
+	}
+}
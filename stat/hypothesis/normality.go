@@ -0,0 +1,207 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// AndersonDarling performs the Anderson–Darling test of the null hypothesis
+// that the data in x are drawn from a normal distribution with unknown mean
+// and variance. The statistic is
+//  A^2 = -n - 1/n * Σ (2i-1) * (log Φ(z_i) + log(1 - Φ(z_{n+1-i}))),
+// where z_i are the sorted data standardized by the sample mean and
+// standard deviation. The p-value is computed from the approximation of
+// D'Agostino and Stephens for the statistic adjusted for the sample size,
+// A^2 * (1 + 0.75/n + 2.25/n^2). AndersonDarling will panic if x has fewer
+// than eight elements.
+func AndersonDarling(x []float64) Result {
+	if len(x) < 8 {
+		panic("hypothesis: too few samples")
+	}
+	s := make([]float64, len(x))
+	copy(s, x)
+	sort.Float64s(s)
+	mean, std := stat.MeanStdDev(s, nil)
+	n := float64(len(s))
+	var sum float64
+	for i, v := range s {
+		lo := (v - mean) / std
+		hi := (s[len(s)-1-i] - mean) / std
+		sum += float64(2*i+1) * (math.Log(distuv.UnitNormal.CDF(lo)) + math.Log(distuv.UnitNormal.Survival(hi)))
+	}
+	a2 := -n - sum/n
+
+	aa := a2 * (1 + 0.75/n + 2.25/(n*n))
+	var p float64
+	switch {
+	case aa < 0.2:
+		p = 1 - math.Exp(-13.436+101.14*aa-223.73*aa*aa)
+	case aa < 0.34:
+		p = 1 - math.Exp(-8.318+42.796*aa-59.938*aa*aa)
+	case aa < 0.6:
+		p = math.Exp(0.9177 - 4.279*aa - 1.38*aa*aa)
+	default:
+		p = math.Exp(1.2937 - 5.709*aa + 0.0186*aa*aa)
+	}
+	return Result{Statistic: a2, PValue: clamp01(p)}
+}
+
+// JarqueBera performs the Jarque–Bera test of the null hypothesis that the
+// data in x are drawn from a normal distribution. The statistic is
+//  n/6 * (S^2 + K^2/4),
+// where S and K are the sample skewness and excess kurtosis computed from the
+// biased central moments, and is compared against a chi-square distribution
+// with two degrees of freedom. The approximation is poor for small samples.
+// JarqueBera will panic if x has fewer than two elements.
+func JarqueBera(x []float64) Result {
+	if len(x) < 2 {
+		panic("hypothesis: too few samples")
+	}
+	n := float64(len(x))
+	mean := stat.Mean(x, nil)
+	var m2, m3, m4 float64
+	for _, v := range x {
+		d := v - mean
+		d2 := d * d
+		m2 += d2
+		m3 += d2 * d
+		m4 += d2 * d2
+	}
+	m2 /= n
+	m3 /= n
+	m4 /= n
+	skew := m3 / math.Pow(m2, 1.5)
+	kurt := m4/(m2*m2) - 3
+	jb := n / 6 * (skew*skew + kurt*kurt/4)
+	return Result{Statistic: jb, PValue: distuv.ChiSquared{K: 2}.Survival(jb)}
+}
+
+// ShapiroWilk performs the Shapiro–Wilk test of the null hypothesis that the
+// data in x are drawn from a normal distribution. The statistic W is the
+// squared correlation between the sorted data and the approximate expected
+// normal order statistics weights. The weights and p-value are computed with
+// the approximations of Royston, which are exact for three observations.
+// ShapiroWilk will panic if x has fewer than three or more than 5000 elements,
+// or if all the elements are equal.
+//
+// The algorithm is described in
+//  Royston, P. (1995). Remark AS R94: A remark on algorithm AS 181: The
+//  W-test for normality. Applied Statistics, 44(4), 547-551.
+func ShapiroWilk(x []float64) Result {
+	n := len(x)
+	if n < 3 || n > 5000 {
+		panic("hypothesis: sample size out of range")
+	}
+	s := make([]float64, n)
+	copy(s, x)
+	sort.Float64s(s)
+	if s[0] == s[n-1] {
+		panic("hypothesis: all samples equal")
+	}
+
+	// Compute the first half of the antisymmetric weights. The weights
+	// of the upper half are the negation of the lower half in reverse.
+	an := float64(n)
+	half := n / 2
+	a := make([]float64, half)
+	if n == 3 {
+		a[0] = math.Sqrt(0.5)
+	} else {
+		var summ2 float64
+		m := make([]float64, half)
+		for i := range m {
+			m[i] = distuv.UnitNormal.Quantile((float64(i+1) - 0.375) / (an + 0.25))
+			summ2 += m[i] * m[i]
+		}
+		summ2 *= 2
+		ssumm2 := math.Sqrt(summ2)
+		rsn := 1 / math.Sqrt(an)
+		a1 := poly(swC1, rsn) - m[0]/ssumm2
+		i1 := 1
+		var fac float64
+		if n > 5 {
+			i1 = 2
+			a2 := -m[1]/ssumm2 + poly(swC2, rsn)
+			fac = math.Sqrt((summ2 - 2*m[0]*m[0] - 2*m[1]*m[1]) / (1 - 2*a1*a1 - 2*a2*a2))
+			a[1] = a2
+		} else {
+			fac = math.Sqrt((summ2 - 2*m[0]*m[0]) / (1 - 2*a1*a1))
+		}
+		a[0] = a1
+		for i := i1; i < half; i++ {
+			a[i] = -m[i] / fac
+		}
+	}
+
+	// W is the squared correlation between the data and the weights.
+	// The weights have zero mean.
+	mean := stat.Mean(s, nil)
+	var saa, sxx, sax float64
+	for i, v := range s {
+		var c float64
+		switch {
+		case i < half:
+			c = -a[i]
+		case n-1-i < half:
+			c = a[n-1-i]
+		}
+		d := v - mean
+		saa += c * c
+		sxx += d * d
+		sax += c * d
+	}
+	w := sax * sax / (saa * sxx)
+	w1 := 1 - w
+
+	if n == 3 {
+		// The exact p-value for three observations.
+		p := 6 / math.Pi * (math.Asin(math.Sqrt(w)) - math.Pi/3)
+		return Result{Statistic: w, PValue: clamp01(p)}
+	}
+	y := math.Log(w1)
+	var mu, sigma float64
+	if n <= 11 {
+		gamma := poly(swG, an)
+		if y >= gamma {
+			return Result{Statistic: w, PValue: 0}
+		}
+		y = -math.Log(gamma - y)
+		mu = poly(swC3, an)
+		sigma = math.Exp(poly(swC4, an))
+	} else {
+		xx := math.Log(an)
+		mu = poly(swC5, xx)
+		sigma = math.Exp(poly(swC6, xx))
+	}
+	p := distuv.Normal{Mu: mu, Sigma: sigma}.Survival(y)
+	return Result{Statistic: w, PValue: p}
+}
+
+// Polynomial coefficients for the Shapiro–Wilk approximations, in
+// increasing order of degree.
+var (
+	swC1 = []float64{0, 0.221157, -0.147981, -2.07119, 4.434685, -2.706056}
+	swC2 = []float64{0, 0.042981, -0.293762, -1.752461, 5.682633, -3.582633}
+	swC3 = []float64{0.544, -0.39978, 0.025054, -6.714e-4}
+	swC4 = []float64{1.3822, -0.77857, 0.062767, -0.0020322}
+	swC5 = []float64{-1.5861, -0.31082, -0.083751, 0.0038915}
+	swC6 = []float64{-0.4803, -0.082676, 0.0030302}
+	swG  = []float64{-2.273, 0.459}
+)
+
+// poly evaluates the polynomial with the coefficients c in increasing order
+// of degree at x.
+func poly(c []float64, x float64) float64 {
+	var v float64
+	for i := len(c) - 1; i >= 0; i-- {
+		v = v*x + c[i]
+	}
+	return v
+}
@@ -0,0 +1,91 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestJarqueBera(t *testing.T) {
+	// The central moments of {1, 2, 3, 4, 10} are m2 = 10, m3 = 36 and
+	// m4 = 278.8.
+	got := JarqueBera([]float64{1, 2, 3, 4, 10})
+	skew := 36 / math.Pow(10, 1.5)
+	kurt := 278.8/100 - 3
+	want := 5.0 / 6 * (skew*skew + kurt*kurt/4)
+	if math.Abs(got.Statistic-want) > 1e-14 || math.Abs(got.PValue-math.Exp(-want/2)) > 1e-14 {
+		t.Errorf("unexpected result: got %+v, want statistic %v and p-value %v", got, want, math.Exp(-want/2))
+	}
+}
+
+func TestShapiroWilk(t *testing.T) {
+	// Weights of eleven men from Shapiro and Wilk (1965), who give
+	// W = 0.79, significant at the 1% level.
+	got := ShapiroWilk([]float64{148, 154, 158, 160, 161, 162, 166, 170, 182, 195, 236})
+	if math.Abs(got.Statistic-0.79) > 0.005 || got.PValue > 0.01 {
+		t.Errorf("unexpected result: got %+v, want statistic about 0.79 and p-value below 0.01", got)
+	}
+
+	// For three observations the weights are ±1/√2 and the p-value is
+	// exact.
+	got = ShapiroWilk([]float64{4, 1, 2})
+	want := 27.0 / 28
+	if math.Abs(got.Statistic-want) > 1e-14 {
+		t.Errorf("unexpected statistic for three observations: got %v, want %v", got.Statistic, want)
+	}
+	if p := 6 / math.Pi * (math.Asin(math.Sqrt(want)) - math.Pi/3); math.Abs(got.PValue-p) > 1e-14 {
+		t.Errorf("unexpected p-value for three observations: got %v, want %v", got.PValue, p)
+	}
+
+	if !panics(func() { ShapiroWilk([]float64{1, 1, 1}) }) {
+		t.Errorf("expected panic for constant data")
+	}
+	if !panics(func() { ShapiroWilk([]float64{1, 2}) }) {
+		t.Errorf("expected panic for too few samples")
+	}
+}
+
+func TestNormalityCalibration(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name string
+		fn   func([]float64) Result
+		n    int
+	}{
+		{name: "Anderson–Darling", fn: AndersonDarling, n: 20},
+		{name: "Shapiro–Wilk", fn: ShapiroWilk, n: 8},
+		{name: "Shapiro–Wilk", fn: ShapiroWilk, n: 50},
+		{name: "Jarque–Bera", fn: JarqueBera, n: 1000},
+	}
+	for _, test := range tests {
+		// Under the null hypothesis about 5% of p-values are below 0.05.
+		const reps = 2000
+		x := make([]float64, test.n)
+		var reject int
+		for r := 0; r < reps; r++ {
+			for i := range x {
+				x[i] = 3*rnd.NormFloat64() + 1
+			}
+			if test.fn(x).PValue < 0.05 {
+				reject++
+			}
+		}
+		if rate := float64(reject) / reps; rate < 0.035 || rate > 0.065 {
+			t.Errorf("%s, n=%d: unexpected rejection rate for normal data: %v", test.name, test.n, rate)
+		}
+
+		// Exponential data are clearly not normal.
+		y := make([]float64, 200)
+		for i := range y {
+			y[i] = rnd.ExpFloat64()
+		}
+		if p := test.fn(y).PValue; p > 1e-3 {
+			t.Errorf("%s: exponential data not rejected: p=%v", test.name, p)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// rankExactMax is the sample size below which exact p-values are used for
+// the rank tests.
+const rankExactMax = 50
+
+// MannWhitneyU performs the Mann–Whitney U test, also known as the Wilcoxon
+// rank-sum test, of the null hypothesis that the distributions of x - mu and
+// y are equal, against the given alternative for the location shift of x
+// relative to y. The statistic is the number of pairs (x_i - mu, y_j) with
+// x_i - mu > y_j, with tied pairs counting one half.
+//
+// The p-value is exact when both samples have fewer than 50 elements and
+// there are no ties. Otherwise it is computed from the normal approximation
+// with the variance corrected for ties, and, if correct is true, with a
+// continuity correction. MannWhitneyU will panic if x or y is empty.
+func MannWhitneyU(x, y []float64, mu float64, alt Alternative, correct bool) Result {
+	if len(x) == 0 || len(y) == 0 {
+		panic("hypothesis: too few samples")
+	}
+	nx, ny := len(x), len(y)
+	all := make([]float64, nx+ny)
+	for i, v := range x {
+		all[i] = v - mu
+	}
+	copy(all[nx:], y)
+	ranks, ties := midranks(all)
+	var rx float64
+	for _, r := range ranks[:nx] {
+		rx += r
+	}
+	u := rx - float64(nx*(nx+1))/2
+
+	if nx < rankExactMax && ny < rankExactMax && ties == 0 {
+		return Result{Statistic: u, PValue: exactPValue(rankSumDist(nx, ny), u, alt)}
+	}
+	fx, fy := float64(nx), float64(ny)
+	n := fx + fy
+	sigma := math.Sqrt(fx * fy / 12 * ((n + 1) - ties/(n*(n-1))))
+	return Result{Statistic: u, PValue: normalPValue(u-fx*fy/2, sigma, alt, correct)}
+}
+
+// WilcoxonSignedRank performs the Wilcoxon signed-rank test of the null
+// hypothesis that the distribution of the differences x_i - y_i - mu is
+// symmetric about zero, against the given alternative for the location of
+// the differences. If y is nil the test is the one-sample test of the
+// location of x, otherwise len(x) must equal len(y). Zero differences are
+// discarded. The statistic is the sum of the ranks of the absolute values of
+// the positive differences, with tied absolute values given their mean rank.
+//
+// The p-value is exact when there are fewer than 50 differences and no ties
+// or zero differences. Otherwise it is computed from the normal
+// approximation with the variance corrected for ties, and, if correct is
+// true, with a continuity correction. WilcoxonSignedRank will panic if there
+// are no non-zero differences.
+func WilcoxonSignedRank(x, y []float64, mu float64, alt Alternative, correct bool) Result {
+	if y != nil && len(x) != len(y) {
+		panic("hypothesis: slice length mismatch")
+	}
+	d := make([]float64, 0, len(x))
+	abs := make([]float64, 0, len(x))
+	for i, v := range x {
+		v -= mu
+		if y != nil {
+			v -= y[i]
+		}
+		if v != 0 {
+			d = append(d, v)
+			abs = append(abs, math.Abs(v))
+		}
+	}
+	zeros := len(x) - len(d)
+	if len(d) == 0 {
+		panic("hypothesis: too few samples")
+	}
+	ranks, ties := midranks(abs)
+	var v float64
+	for i, r := range ranks {
+		if d[i] > 0 {
+			v += r
+		}
+	}
+
+	n := len(d)
+	if n < rankExactMax && ties == 0 && zeros == 0 {
+		return Result{Statistic: v, PValue: exactPValue(signedRankDist(n), v, alt)}
+	}
+	fn := float64(n)
+	sigma := math.Sqrt(fn*(fn+1)*(2*fn+1)/24 - ties/48)
+	return Result{Statistic: v, PValue: normalPValue(v-fn*(fn+1)/4, sigma, alt, correct)}
+}
+
+// midranks returns the ranks of the values in x, with tied values given
+// the mean of their ranks, and the tie term Σ (t^3 - t) over the groups of
+// t tied values.
+func midranks(x []float64) (ranks []float64, ties float64) {
+	idx := make([]int, len(x))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.Slice(idx, func(i, j int) bool { return x[idx[i]] < x[idx[j]] })
+	ranks = make([]float64, len(x))
+	for i := 0; i < len(idx); {
+		j := i + 1
+		for j < len(idx) && x[idx[j]] == x[idx[i]] {
+			j++
+		}
+		r := float64(i+j+1) / 2
+		for _, k := range idx[i:j] {
+			ranks[k] = r
+		}
+		if t := float64(j - i); t > 1 {
+			ties += t*t*t - t
+		}
+		i = j
+	}
+	return ranks, ties
+}
+
+// rankSumDist returns the probabilities of the Mann–Whitney U statistic
+// taking the values 0, 1, ..., m*n for samples of sizes m and n without
+// ties.
+func rankSumDist(m, n int) []float64 {
+	// count[k][s] is the number of ways to choose k of the ranks seen so
+	// far with sum s, offset by k*(k+1)/2 so that s is the U statistic.
+	maxU := m * n
+	count := make([][]float64, m+1)
+	for k := range count {
+		count[k] = make([]float64, maxU+1)
+	}
+	count[0][0] = 1
+	for r := 1; r <= m+n; r++ {
+		for k := min(r, m); k >= 1; k-- {
+			// Choosing rank r as the kth smallest of the first
+			// sample contributes r - k to the U statistic.
+			shift := r - k
+			if shift > n {
+				continue
+			}
+			for s := maxU; s >= shift; s-- {
+				count[k][s] += count[k-1][s-shift]
+			}
+		}
+	}
+	return normalize(count[m])
+}
+
+// signedRankDist returns the probabilities of the Wilcoxon signed-rank
+// statistic taking the values 0, 1, ..., n*(n+1)/2 for n differences without
+// ties.
+func signedRankDist(n int) []float64 {
+	maxV := n * (n + 1) / 2
+	count := make([]float64, maxV+1)
+	count[0] = 1
+	for r := 1; r <= n; r++ {
+		for s := maxV; s >= r; s-- {
+			count[s] += count[s-r]
+		}
+	}
+	return normalize(count)
+}
+
+func normalize(count []float64) []float64 {
+	var sum float64
+	for _, v := range count {
+		sum += v
+	}
+	for i := range count {
+		count[i] /= sum
+	}
+	return count
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// exactPValue returns the p-value of the integer statistic s under the
+// probability mass function pmf for the given alternative.
+func exactPValue(pmf []float64, s float64, alt Alternative) float64 {
+	k := int(s)
+	var lower, upper float64
+	for i, p := range pmf {
+		if i <= k {
+			lower += p
+		}
+		if i >= k {
+			upper += p
+		}
+	}
+	switch alt {
+	case TwoSided:
+		return clamp01(2 * math.Min(lower, upper))
+	case Less:
+		return clamp01(lower)
+	case Greater:
+		return clamp01(upper)
+	default:
+		panic("hypothesis: unknown alternative")
+	}
+}
+
+// normalPValue returns the p-value of the centered statistic z with
+// standard deviation sigma under the normal approximation for the given
+// alternative, optionally applying a continuity correction.
+func normalPValue(z, sigma float64, alt Alternative, correct bool) float64 {
+	var c float64
+	if correct {
+		switch alt {
+		case TwoSided:
+			c = 0.5
+			if z < 0 {
+				c = -0.5
+			} else if z == 0 {
+				c = 0
+			}
+		case Less:
+			c = -0.5
+		case Greater:
+			c = 0.5
+		}
+	}
+	z = (z - c) / sigma
+	switch alt {
+	case TwoSided:
+		return clamp01(2 * math.Min(distuv.UnitNormal.CDF(z), distuv.UnitNormal.Survival(z)))
+	case Less:
+		return distuv.UnitNormal.CDF(z)
+	case Greater:
+		return distuv.UnitNormal.Survival(z)
+	default:
+		panic("hypothesis: unknown alternative")
+	}
+}
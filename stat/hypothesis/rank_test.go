@@ -0,0 +1,118 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+func TestMannWhitneyU(t *testing.T) {
+	// Example from R's wilcox.test documentation.
+	x := []float64{0.80, 0.83, 1.89, 1.04, 1.45, 1.38, 1.91, 1.64, 0.73, 1.46}
+	y := []float64{1.15, 0.88, 0.90, 0.74, 1.21}
+	got := MannWhitneyU(x, y, 0, Greater, true)
+	if got.Statistic != 35 || !floats.EqualWithinAbsOrRel(got.PValue, 0.1272, 1e-4, 1e-4) {
+		t.Errorf("unexpected result: got %+v, want statistic 35 and p-value 0.1272", got)
+	}
+
+	// Swapping the samples reflects the statistic.
+	two := MannWhitneyU(x, y, 0, TwoSided, true)
+	swapped := MannWhitneyU(y, x, 0, TwoSided, true)
+	if swapped.Statistic != 50-two.Statistic || math.Abs(swapped.PValue-two.PValue) > 1e-14 {
+		t.Errorf("unexpected result for swapped samples: got %+v, want statistic %v and p-value %v", swapped, 50-two.Statistic, two.PValue)
+	}
+	if less := MannWhitneyU(y, x, 0, Less, true); math.Abs(less.PValue-got.PValue) > 1e-14 {
+		t.Errorf("unexpected one-sided p-value for swapped samples: got %v, want %v", less.PValue, got.PValue)
+	}
+
+	// Ties use the normal approximation. The pooled sample has two groups
+	// of three tied values, so the variance is 6*7/12 * (14 - 48/(13*12))
+	// and U - 6*7/2 = -12.
+	tx := []float64{1, 2, 2, 3, 4, 5}
+	ty := []float64{2, 3, 3, 6, 7, 8, 9}
+	sigma := math.Sqrt(3.5 * (14 - 48.0/156))
+	for _, test := range []struct {
+		correct bool
+		want    float64
+	}{
+		{correct: true, want: 2 * distuv.UnitNormal.CDF(-11.5/sigma)},
+		{correct: false, want: 2 * distuv.UnitNormal.CDF(-12/sigma)},
+	} {
+		got := MannWhitneyU(tx, ty, 0, TwoSided, test.correct)
+		if got.Statistic != 9 || math.Abs(got.PValue-test.want) > 1e-14 {
+			t.Errorf("correct=%t: unexpected result with ties: got %+v, want statistic 9 and p-value %v", test.correct, got, test.want)
+		}
+	}
+}
+
+func TestWilcoxonSignedRank(t *testing.T) {
+	// Example from R's wilcox.test documentation.
+	x := []float64{1.83, 0.50, 1.62, 2.48, 1.68, 1.88, 1.55, 3.06, 1.30}
+	y := []float64{0.878, 0.647, 0.598, 2.05, 1.06, 1.29, 1.06, 3.14, 1.29}
+	got := WilcoxonSignedRank(x, y, 0, Greater, true)
+	if got.Statistic != 40 || !floats.EqualWithinAbsOrRel(got.PValue, 0.01953, 1e-4, 1e-4) {
+		t.Errorf("unexpected result: got %+v, want statistic 40 and p-value 0.01953", got)
+	}
+
+	// The paired test is the one-sample test of the differences.
+	d := make([]float64, len(x))
+	floats.SubTo(d, x, y)
+	one := WilcoxonSignedRank(d, nil, 0, Greater, true)
+	if one != got {
+		t.Errorf("unexpected one-sample result: got %+v, want %+v", one, got)
+	}
+
+	if !panics(func() { WilcoxonSignedRank([]float64{1, 1}, []float64{1, 1}, 0, TwoSided, true) }) {
+		t.Errorf("expected panic for all zero differences")
+	}
+}
+
+func TestRankExactDistributions(t *testing.T) {
+	// The exact distributions approach the normal approximations.
+	rnd := rand.New(rand.NewSource(1))
+	x := make([]float64, 45)
+	y := make([]float64, 40)
+	for i := range x {
+		x[i] = rnd.NormFloat64() + 0.3
+	}
+	for i := range y {
+		y[i] = rnd.NormFloat64()
+	}
+	exact := MannWhitneyU(x, y, 0, TwoSided, true)
+	u := exact.Statistic
+	approx := normalPValue(u-45*40/2, math.Sqrt(45*40*86/12.0), TwoSided, true)
+	if math.Abs(exact.PValue-approx) > 5e-3 {
+		t.Errorf("exact and approximate Mann–Whitney p-values differ: %v != %v", exact.PValue, approx)
+	}
+	exact = WilcoxonSignedRank(x, nil, 0, TwoSided, true)
+	v := exact.Statistic
+	approx = normalPValue(v-45*46/4.0, math.Sqrt(45*46*91/24.0), TwoSided, true)
+	if math.Abs(exact.PValue-approx) > 5e-3 {
+		t.Errorf("exact and approximate signed-rank p-values differ: %v != %v", exact.PValue, approx)
+	}
+
+	// The probabilities are symmetric and sum to one.
+	for _, pmf := range [][]float64{rankSumDist(3, 5), signedRankDist(6)} {
+		if math.Abs(floats.Sum(pmf)-1) > 1e-14 {
+			t.Errorf("probabilities do not sum to one")
+		}
+		for i := range pmf {
+			if math.Abs(pmf[i]-pmf[len(pmf)-1-i]) > 1e-15 {
+				t.Errorf("probabilities are not symmetric")
+				break
+			}
+		}
+	}
+	// With samples of sizes 1 and 2 each value of U is equally likely.
+	if pmf := rankSumDist(1, 2); !floats.EqualApprox(pmf, []float64{1.0 / 3, 1.0 / 3, 1.0 / 3}, 1e-15) {
+		t.Errorf("unexpected distribution: %v", pmf)
+	}
+}
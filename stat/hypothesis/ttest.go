@@ -0,0 +1,155 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// Alternative specifies the alternative hypothesis of a test.
+type Alternative int
+
+const (
+	// TwoSided is the alternative that the tested quantity differs from
+	// its value under the null hypothesis.
+	TwoSided Alternative = iota
+	// Less is the alternative that the tested quantity is less than its
+	// value under the null hypothesis.
+	Less
+	// Greater is the alternative that the tested quantity is greater than
+	// its value under the null hypothesis.
+	Greater
+)
+
+// TResult is the result of a Student's t-test.
+type TResult struct {
+	// Statistic is the t statistic.
+	Statistic float64
+	// DoF is the number of degrees of freedom of the reference
+	// Student's t distribution.
+	DoF float64
+	// PValue is the probability under the null hypothesis of a
+	// statistic at least as extreme as the one observed.
+	PValue float64
+	// Estimate is the estimated mean or difference in means.
+	Estimate float64
+	// Lower and Upper are the bounds of the confidence interval for the
+	// estimate. For one-sided alternatives one of the bounds is infinite.
+	Lower, Upper float64
+}
+
+// OneSampleT performs a one-sample Student's t-test of the null hypothesis
+// that the data in x are drawn from a normal distribution with mean mu,
+// against the given alternative. The confidence interval for the mean is
+// computed at the confidence level conf, which must be in (0, 1).
+// OneSampleT will panic if x has fewer than two elements.
+func OneSampleT(x []float64, mu float64, alt Alternative, conf float64) TResult {
+	if len(x) < 2 {
+		panic("hypothesis: too few samples")
+	}
+	mean, std := stat.MeanStdDev(x, nil)
+	n := float64(len(x))
+	return tTest(mean, mu, std/math.Sqrt(n), n-1, alt, conf)
+}
+
+// TwoSampleT performs a two-sample Student's t-test of the null hypothesis
+// that the data in x and y are drawn from normal distributions with equal
+// variances whose means differ by mu, against the given alternative for the
+// difference mean(x) - mean(y). The variance is estimated from the pooled
+// samples. The confidence interval for the difference in means is computed
+// at the confidence level conf, which must be in (0, 1). TwoSampleT will
+// panic if x and y together have fewer than three elements or either is
+// empty.
+//
+// When the variances of the populations may differ, WelchT should be used
+// instead.
+func TwoSampleT(x, y []float64, mu float64, alt Alternative, conf float64) TResult {
+	if len(x) == 0 || len(y) == 0 || len(x)+len(y) < 3 {
+		panic("hypothesis: too few samples")
+	}
+	nx, ny := float64(len(x)), float64(len(y))
+	mx, my := stat.Mean(x, nil), stat.Mean(y, nil)
+	var ssx, ssy float64
+	if len(x) > 1 {
+		ssx = stat.Variance(x, nil) * (nx - 1)
+	}
+	if len(y) > 1 {
+		ssy = stat.Variance(y, nil) * (ny - 1)
+	}
+	dof := nx + ny - 2
+	pooled := (ssx + ssy) / dof
+	se := math.Sqrt(pooled * (1/nx + 1/ny))
+	return tTest(mx-my, mu, se, dof, alt, conf)
+}
+
+// WelchT performs Welch's unequal variances t-test of the null hypothesis
+// that the data in x and y are drawn from normal distributions whose means
+// differ by mu, against the given alternative for the difference
+// mean(x) - mean(y). The number of degrees of freedom is given by the
+// Welch–Satterthwaite equation. The confidence interval for the difference
+// in means is computed at the confidence level conf, which must be in (0, 1).
+// WelchT will panic if x or y has fewer than two elements.
+func WelchT(x, y []float64, mu float64, alt Alternative, conf float64) TResult {
+	if len(x) < 2 || len(y) < 2 {
+		panic("hypothesis: too few samples")
+	}
+	nx, ny := float64(len(x)), float64(len(y))
+	mx, vx := stat.MeanVariance(x, nil)
+	my, vy := stat.MeanVariance(y, nil)
+	sx, sy := vx/nx, vy/ny
+	se2 := sx + sy
+	dof := se2 * se2 / (sx*sx/(nx-1) + sy*sy/(ny-1))
+	return tTest(mx-my, mu, math.Sqrt(se2), dof, alt, conf)
+}
+
+// PairedT performs a paired Student's t-test of the null hypothesis that the
+// differences x[i] - y[i] are drawn from a normal distribution with mean mu,
+// against the given alternative. The confidence interval for the mean
+// difference is computed at the confidence level conf, which must be in
+// (0, 1). PairedT will panic if len(x) != len(y) or there are fewer than two
+// pairs.
+func PairedT(x, y []float64, mu float64, alt Alternative, conf float64) TResult {
+	if len(x) != len(y) {
+		panic("hypothesis: slice length mismatch")
+	}
+	d := make([]float64, len(x))
+	for i, v := range x {
+		d[i] = v - y[i]
+	}
+	return OneSampleT(d, mu, alt, conf)
+}
+
+// tTest returns the result of a t-test for the estimate est with standard
+// error se and dof degrees of freedom against the null value mu.
+func tTest(est, mu, se, dof float64, alt Alternative, conf float64) TResult {
+	if !(0 < conf && conf < 1) {
+		panic("hypothesis: confidence level out of range")
+	}
+	t := (est - mu) / se
+	dist := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: dof}
+	res := TResult{
+		Statistic: t,
+		DoF:       dof,
+		Estimate:  est,
+	}
+	switch alt {
+	case TwoSided:
+		res.PValue = 2 * dist.Survival(math.Abs(t))
+		q := dist.Quantile(1 - (1-conf)/2)
+		res.Lower, res.Upper = est-q*se, est+q*se
+	case Less:
+		res.PValue = dist.CDF(t)
+		res.Lower, res.Upper = math.Inf(-1), est+dist.Quantile(conf)*se
+	case Greater:
+		res.PValue = dist.Survival(t)
+		res.Lower, res.Upper = est-dist.Quantile(conf)*se, math.Inf(1)
+	default:
+		panic("hypothesis: unknown alternative")
+	}
+	return res
+}
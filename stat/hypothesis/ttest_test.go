@@ -0,0 +1,113 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hypothesis
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// Student's sleep data, as provided by R's datasets package.
+var (
+	sleep1 = []float64{0.7, -1.6, -0.2, -1.2, -0.1, 3.4, 3.7, 0.8, 0.0, 2.0}
+	sleep2 = []float64{1.9, 0.8, 1.1, 0.1, -0.1, 4.4, 5.5, 1.6, 4.6, 3.4}
+)
+
+func TestTTests(t *testing.T) {
+	// Reference values computed with R's t.test.
+	for _, test := range []struct {
+		name string
+		fn   func() TResult
+		want TResult
+	}{
+		{
+			name: "one sample",
+			fn:   func() TResult { return OneSampleT(sleep1, 0, TwoSided, 0.95) },
+			want: TResult{Statistic: 1.3257, DoF: 9, PValue: 0.2176, Estimate: 0.75, Lower: -0.5297804, Upper: 2.0297804},
+		},
+		{
+			name: "two sample",
+			fn:   func() TResult { return TwoSampleT(sleep1, sleep2, 0, TwoSided, 0.95) },
+			want: TResult{Statistic: -1.8608, DoF: 18, PValue: 0.07919, Estimate: -1.58, Lower: -3.363874, Upper: 0.203874},
+		},
+		{
+			name: "Welch",
+			fn:   func() TResult { return WelchT(sleep1, sleep2, 0, TwoSided, 0.95) },
+			want: TResult{Statistic: -1.8608, DoF: 17.776, PValue: 0.07939, Estimate: -1.58, Lower: -3.3654832, Upper: 0.2054832},
+		},
+		{
+			name: "paired",
+			fn:   func() TResult { return PairedT(sleep1, sleep2, 0, TwoSided, 0.95) },
+			want: TResult{Statistic: -4.0621, DoF: 9, PValue: 0.002833, Estimate: -1.58, Lower: -2.4598858, Upper: -0.7001142},
+		},
+	} {
+		got := test.fn()
+		for _, v := range []struct {
+			name      string
+			got, want float64
+		}{
+			{"statistic", got.Statistic, test.want.Statistic},
+			{"degrees of freedom", got.DoF, test.want.DoF},
+			{"p-value", got.PValue, test.want.PValue},
+			{"estimate", got.Estimate, test.want.Estimate},
+			{"lower bound", got.Lower, test.want.Lower},
+			{"upper bound", got.Upper, test.want.Upper},
+		} {
+			if !floats.EqualWithinAbsOrRel(v.got, v.want, 1e-4, 1e-4) {
+				t.Errorf("%s: unexpected %s: got %v, want %v", test.name, v.name, v.got, v.want)
+			}
+		}
+	}
+}
+
+func TestTTestAlternatives(t *testing.T) {
+	two := WelchT(sleep1, sleep2, 0, TwoSided, 0.9)
+	less := WelchT(sleep1, sleep2, 0, Less, 0.95)
+	greater := WelchT(sleep1, sleep2, 0, Greater, 0.95)
+	if math.Abs(less.PValue-two.PValue/2) > 1e-14 {
+		t.Errorf("one-sided p-value is not half the two-sided p-value for a negative statistic: got %v, want %v", less.PValue, two.PValue/2)
+	}
+	if math.Abs(less.PValue+greater.PValue-1) > 1e-14 {
+		t.Errorf("one-sided p-values do not sum to one: %v + %v", less.PValue, greater.PValue)
+	}
+	if !math.IsInf(less.Lower, -1) || math.Abs(less.Upper-two.Upper) > 1e-12 {
+		t.Errorf("unexpected confidence interval for Less: [%v, %v]", less.Lower, less.Upper)
+	}
+	if !math.IsInf(greater.Upper, 1) || math.Abs(greater.Lower-two.Lower) > 1e-12 {
+		t.Errorf("unexpected confidence interval for Greater: [%v, %v]", greater.Lower, greater.Upper)
+	}
+
+	// Shifting the null hypothesis is the same as shifting the data.
+	shifted := make([]float64, len(sleep1))
+	copy(shifted, sleep1)
+	floats.AddConst(-1, shifted)
+	got := OneSampleT(sleep1, 1, Greater, 0.95)
+	want := OneSampleT(shifted, 0, Greater, 0.95)
+	if math.Abs(got.Statistic-want.Statistic) > 1e-14 || math.Abs(got.PValue-want.PValue) > 1e-14 {
+		t.Errorf("unexpected result for shifted null: got %+v, want statistic %v and p-value %v", got, want.Statistic, want.PValue)
+	}
+
+	for _, fn := range []func(){
+		func() { OneSampleT([]float64{1}, 0, TwoSided, 0.95) },
+		func() { OneSampleT(sleep1, 0, TwoSided, 1) },
+		func() { OneSampleT(sleep1, 0, Alternative(-1), 0.95) },
+		func() { PairedT(sleep1, sleep2[1:], 0, TwoSided, 0.95) },
+		func() { WelchT(sleep1, []float64{1}, 0, TwoSided, 0.95) },
+	} {
+		if !panics(fn) {
+			t.Errorf("expected panic")
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return
+}
@@ -0,0 +1,418 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Kernel is a smoothing kernel used for kernel density estimation.
+type Kernel int
+
+const (
+	// GaussianKernel is the standard normal density.
+	GaussianKernel Kernel = iota
+	// EpanechnikovKernel is the kernel proportional to 1-|u|² on the unit
+	// ball, which is the optimal kernel in the mean integrated squared
+	// error sense.
+	EpanechnikovKernel
+)
+
+// norm returns the normalization constant of the kernel in d dimensions.
+func (k Kernel) norm(d int) float64 {
+	switch k {
+	case GaussianKernel:
+		return math.Pow(2*math.Pi, -float64(d)/2)
+	case EpanechnikovKernel:
+		return float64(d+2) / (2 * unitBallVolume(d))
+	default:
+		panic("stat: unknown kernel")
+	}
+}
+
+// profile returns the unnormalized value of the kernel at a point with the
+// squared norm r2.
+func (k Kernel) profile(r2 float64) float64 {
+	switch k {
+	case GaussianKernel:
+		return math.Exp(-r2 / 2)
+	case EpanechnikovKernel:
+		if r2 >= 1 {
+			return 0
+		}
+		return 1 - r2
+	default:
+		panic("stat: unknown kernel")
+	}
+}
+
+// canonical returns the canonical bandwidth (R(K)/μ₂(K)²)^(1/(d+4)) of the
+// kernel in d dimensions, where R(K) is the integral of K² and μ₂(K) is the
+// second moment of one component. Bandwidths for different kernels are
+// equivalent when scaled by the ratio of their canonical bandwidths.
+func (k Kernel) canonical(d int) float64 {
+	var r, mu2 float64
+	switch k {
+	case GaussianKernel:
+		r = math.Pow(4*math.Pi, -float64(d)/2)
+		mu2 = 1
+	case EpanechnikovKernel:
+		r = 2 * float64(d+2) / (unitBallVolume(d) * float64(d+4))
+		mu2 = 1 / float64(d+4)
+	default:
+		panic("stat: unknown kernel")
+	}
+	return math.Pow(r/(mu2*mu2), 1/float64(d+4))
+}
+
+// unitBallVolume returns the volume of the unit ball in d dimensions.
+func unitBallVolume(d int) float64 {
+	return math.Pow(math.Pi, float64(d)/2) / math.Gamma(float64(d)/2+1)
+}
+
+// KDE is a univariate kernel density estimate,
+//  f(x) = 1/(h*Σ w_i) * Σ w_i K((x - x_i)/h),
+// where K is the kernel and h is the bandwidth.
+type KDE struct {
+	x, weights []float64
+	sumWeights float64
+
+	kernel    Kernel
+	bandwidth float64
+}
+
+// NewKDE returns a kernel density estimate of the data in x with the given
+// kernel and bandwidth. If weights is nil then all of the weights are 1. If
+// weights is not nil, then len(x) must equal len(weights). The data are
+// copied. NewKDE will panic if x is empty or the bandwidth is not positive.
+func NewKDE(kernel Kernel, bandwidth float64, x, weights []float64) *KDE {
+	if len(x) == 0 {
+		panic("stat: zero length data")
+	}
+	if weights != nil && len(x) != len(weights) {
+		panic("stat: slice length mismatch")
+	}
+	if !(bandwidth > 0) {
+		panic("stat: non-positive bandwidth")
+	}
+	kernel.norm(1) // Panic early for an unknown kernel.
+	k := &KDE{
+		x:          make([]float64, len(x)),
+		sumWeights: float64(len(x)),
+		kernel:     kernel,
+		bandwidth:  bandwidth,
+	}
+	copy(k.x, x)
+	if weights != nil {
+		k.weights = make([]float64, len(weights))
+		copy(k.weights, weights)
+		k.sumWeights = floats.Sum(weights)
+	}
+	return k
+}
+
+// Bandwidth returns the bandwidth of the estimate.
+func (k *KDE) Bandwidth() float64 {
+	return k.bandwidth
+}
+
+// Density returns the estimated probability density at x.
+func (k *KDE) Density(x float64) float64 {
+	var f float64
+	for i, v := range k.x {
+		u := (x - v) / k.bandwidth
+		p := k.kernel.profile(u * u)
+		if k.weights != nil {
+			p *= k.weights[i]
+		}
+		f += p
+	}
+	return f * k.kernel.norm(1) / (k.bandwidth * k.sumWeights)
+}
+
+// DensityTo stores the estimated probability density at each of the points
+// in x into dst and returns it. If dst is nil a new slice is allocated,
+// otherwise len(dst) must equal len(x).
+func (k *KDE) DensityTo(dst, x []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, len(x))
+	}
+	if len(dst) != len(x) {
+		panic("stat: slice length mismatch")
+	}
+	for i, v := range x {
+		dst[i] = k.Density(v)
+	}
+	return dst
+}
+
+// Grid stores the estimated probability density at len(dst) equally spaced
+// points from lo to hi inclusive into dst and returns it. The points are
+// those returned by floats.Span. Grid will panic if len(dst) < 2.
+func (k *KDE) Grid(dst []float64, lo, hi float64) []float64 {
+	floats.Span(dst, lo, hi)
+	return k.DensityTo(dst, dst)
+}
+
+// SilvermanBandwidth returns the bandwidth for a kernel density estimate of x
+// with the given kernel chosen by Silverman's rule of thumb,
+//  h = 0.9 * min(σ, IQR/1.34) * n^(-1/5),
+// where σ is the standard deviation, IQR the interquartile range and n the
+// sum of the weights, scaled for kernels other than the Gaussian kernel by
+// the ratio of their canonical bandwidths. If the interquartile range is
+// zero, σ is used alone. If weights is nil then all of the weights are 1. If
+// weights is not nil, then len(x) must equal len(weights).
+//
+// The rule is intended for data that are close to normally distributed and
+// tends to oversmooth multimodal densities.
+func SilvermanBandwidth(kernel Kernel, x, weights []float64) float64 {
+	if weights != nil && len(x) != len(weights) {
+		panic("stat: slice length mismatch")
+	}
+	n := float64(len(x))
+	if weights != nil {
+		n = floats.Sum(weights)
+	}
+	s := make([]float64, len(x))
+	copy(s, x)
+	var w []float64
+	if weights != nil {
+		w = make([]float64, len(weights))
+		copy(w, weights)
+	}
+	SortWeighted(s, w)
+	spread := StdDev(x, weights)
+	iqr := (Quantile(0.75, LinInterp, s, w) - Quantile(0.25, LinInterp, s, w)) / 1.34
+	if iqr > 0 && iqr < spread {
+		spread = iqr
+	}
+	scale := kernel.canonical(1) / GaussianKernel.canonical(1)
+	return scale * 0.9 * spread * math.Pow(n, -0.2)
+}
+
+// CrossValidBandwidth returns the bandwidth for a kernel density estimate of
+// x with the given kernel that maximizes the leave-one-out likelihood
+//  Σ w_i log f_{-i}(x_i),
+// where f_{-i} is the estimate without the point x_i. The bandwidth is
+// searched for within a factor of twenty of the value returned by
+// SilvermanBandwidth. If weights is nil then all of the weights are 1. If
+// weights is not nil, then len(x) must equal len(weights).
+//
+// Likelihood cross-validation favors ever smaller bandwidths when the data
+// contain repeated values, in which case the lower end of the search range
+// is returned. The cost is quadratic in the number of data points.
+func CrossValidBandwidth(kernel Kernel, x, weights []float64) float64 {
+	h0 := SilvermanBandwidth(kernel, x, weights)
+	if !(h0 > 0) {
+		panic("stat: data not spread")
+	}
+	sumWeights := float64(len(x))
+	if weights != nil {
+		sumWeights = floats.Sum(weights)
+	}
+	loglik := func(logh float64) float64 {
+		h := math.Exp(logh)
+		var l float64
+		for i, xi := range x {
+			wi := 1.0
+			if weights != nil {
+				wi = weights[i]
+			}
+			if wi == 0 {
+				continue
+			}
+			var f float64
+			for j, xj := range x {
+				if j == i {
+					continue
+				}
+				u := (xi - xj) / h
+				p := kernel.profile(u * u)
+				if weights != nil {
+					p *= weights[j]
+				}
+				f += p
+			}
+			l += wi * math.Log(f*kernel.norm(1)/(h*(sumWeights-wi)))
+		}
+		return l
+	}
+
+	// Golden section search for the maximum over log h. Ties are broken
+	// toward larger bandwidths so that a search starting from a bandwidth
+	// with zero likelihood for compact kernels moves toward the data.
+	const (
+		invPhi = 0.6180339887498949
+		tol    = 1e-4
+	)
+	a, b := math.Log(h0/20), math.Log(h0*20)
+	c := b - invPhi*(b-a)
+	d := a + invPhi*(b-a)
+	fc, fd := loglik(c), loglik(d)
+	for b-a > tol {
+		if fc > fd {
+			b, d, fd = d, c, fc
+			c = b - invPhi*(b-a)
+			fc = loglik(c)
+		} else {
+			a, c, fc = c, d, fd
+			d = a + invPhi*(b-a)
+			fd = loglik(d)
+		}
+	}
+	return math.Exp((a + b) / 2)
+}
+
+// KDEMV is a multivariate kernel density estimate,
+//  f(x) = 1/(|H|^(1/2)*Σ w_i) * Σ w_i K(H^(-1/2)*(x - x_i)),
+// where K is the radially symmetric kernel and H is the symmetric positive
+// definite bandwidth matrix.
+type KDEMV struct {
+	// z holds the data transformed by the inverse
+	// Cholesky factor of the bandwidth matrix.
+	z          *mat.Dense
+	weights    []float64
+	sumWeights float64
+
+	kernel Kernel
+	chol   mat.Cholesky
+	lower  *mat.TriDense
+	scale  float64
+}
+
+// NewKDEMV returns a kernel density estimate of the data in the rows of x
+// with the given kernel and bandwidth matrix. If weights is nil then all of
+// the weights are 1. If weights is not nil, then the number of rows of x must
+// equal len(weights). NewKDEMV will panic if x has no rows, if the bandwidth
+// matrix does not match the number of columns of x, or if it is not positive
+// definite.
+func NewKDEMV(kernel Kernel, bandwidth mat.Symmetric, x mat.Matrix, weights []float64) *KDEMV {
+	r, c := x.Dims()
+	if r == 0 {
+		panic("stat: zero length data")
+	}
+	if weights != nil && r != len(weights) {
+		panic("stat: slice length mismatch")
+	}
+	if bandwidth.Symmetric() != c {
+		panic("stat: dimension mismatch")
+	}
+	k := &KDEMV{
+		sumWeights: float64(r),
+		kernel:     kernel,
+	}
+	if !k.chol.Factorize(bandwidth) {
+		panic("stat: bandwidth not positive definite")
+	}
+	k.lower = mat.NewTriDense(c, mat.Lower, nil)
+	k.chol.LTo(k.lower)
+	k.scale = kernel.norm(c) / math.Exp(k.chol.LogDet()/2)
+
+	// Zᵀ = L⁻¹ * Xᵀ.
+	var zt mat.Dense
+	zt.Solve(k.lower, x.T())
+	k.z = mat.DenseCopyOf(zt.T())
+	if weights != nil {
+		k.weights = make([]float64, len(weights))
+		copy(k.weights, weights)
+		k.sumWeights = floats.Sum(weights)
+	}
+	return k
+}
+
+// Dim returns the dimension of the estimate.
+func (k *KDEMV) Dim() int {
+	_, c := k.z.Dims()
+	return c
+}
+
+// BandwidthTo stores the bandwidth matrix of the estimate into dst. If dst
+// is empty it is resized to the dimension of the estimate, otherwise it must
+// match.
+func (k *KDEMV) BandwidthTo(dst *mat.SymDense) {
+	k.chol.ToSym(dst)
+}
+
+// Density returns the estimated probability density at x. Density will
+// panic if len(x) does not equal the dimension of the estimate.
+func (k *KDEMV) Density(x []float64) float64 {
+	r, c := k.z.Dims()
+	if len(x) != c {
+		panic("stat: dimension mismatch")
+	}
+	var q mat.VecDense
+	q.SolveVec(k.lower, mat.NewVecDense(c, x))
+	return k.density(q.RawVector().Data, r)
+}
+
+func (k *KDEMV) density(q []float64, r int) float64 {
+	var f float64
+	for i := 0; i < r; i++ {
+		zi := k.z.RawRowView(i)
+		var r2 float64
+		for j, v := range zi {
+			d := q[j] - v
+			r2 += d * d
+		}
+		p := k.kernel.profile(r2)
+		if k.weights != nil {
+			p *= k.weights[i]
+		}
+		f += p
+	}
+	return f * k.scale / k.sumWeights
+}
+
+// DensityTo stores the estimated probability density at each of the rows of
+// x into dst and returns it. If dst is nil a new slice is allocated,
+// otherwise len(dst) must equal the number of rows of x. DensityTo will panic
+// if the number of columns of x does not equal the dimension of the
+// estimate.
+func (k *KDEMV) DensityTo(dst []float64, x mat.Matrix) []float64 {
+	r, c := x.Dims()
+	n, d := k.z.Dims()
+	if c != d {
+		panic("stat: dimension mismatch")
+	}
+	if dst == nil {
+		dst = make([]float64, r)
+	}
+	if len(dst) != r {
+		panic("stat: slice length mismatch")
+	}
+	var qt mat.Dense
+	qt.Solve(k.lower, x.T())
+	q := make([]float64, c)
+	for i := range dst {
+		mat.Col(q, i, &qt)
+		dst[i] = k.density(q, n)
+	}
+	return dst
+}
+
+// SilvermanBandwidthMV stores into dst the bandwidth matrix for a kernel
+// density estimate of the rows of x with the given kernel chosen by
+// Silverman's rule of thumb,
+//  H = (4/((d+2)*n))^(2/(d+4)) * Σ,
+// where Σ is the covariance matrix of x, d the number of columns and n the
+// sum of the weights, scaled for kernels other than the Gaussian kernel by
+// the squared ratio of their canonical bandwidths. If weights is nil then all
+// of the weights are 1. If weights is not nil, then the number of rows of x
+// must equal len(weights). The dst matrix must either be empty or have the
+// same number of columns as x.
+func SilvermanBandwidthMV(dst *mat.SymDense, kernel Kernel, x mat.Matrix, weights []float64) {
+	r, c := x.Dims()
+	n := float64(r)
+	if weights != nil {
+		n = floats.Sum(weights)
+	}
+	CovarianceMatrix(dst, x, weights)
+	d := float64(c)
+	ratio := kernel.canonical(c) / GaussianKernel.canonical(c)
+	dst.ScaleSym(ratio*ratio*math.Pow(4/((d+2)*n), 2/(d+4)), dst)
+}
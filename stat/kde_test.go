@@ -0,0 +1,224 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var kernels = []Kernel{GaussianKernel, EpanechnikovKernel}
+
+func TestKDE(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	x := make([]float64, 50)
+	for i := range x {
+		x[i] = rnd.NormFloat64()
+	}
+	for _, kernel := range kernels {
+		h := SilvermanBandwidth(kernel, x, nil)
+		kde := NewKDE(kernel, h, x, nil)
+
+		// The density integrates to one.
+		const n = 4001
+		f := kde.Grid(make([]float64, n), -10, 10)
+		step := 20.0 / (n - 1)
+		area := step * (floats.Sum(f) - (f[0]+f[n-1])/2)
+		if math.Abs(area-1) > 1e-6 {
+			t.Errorf("kernel %d: density does not integrate to one: %v", kernel, area)
+		}
+
+		// Repeating data points is the same as weighting them.
+		w := make([]float64, len(x))
+		var rep []float64
+		for i := range w {
+			w[i] = float64(1 + i%3)
+			for j := 0; j < int(w[i]); j++ {
+				rep = append(rep, x[i])
+			}
+		}
+		kw := NewKDE(kernel, h, x, w)
+		kr := NewKDE(kernel, h, rep, nil)
+		for _, v := range []float64{-2, -0.3, 0, 0.7, 1.5} {
+			got := kw.Density(v)
+			want := kr.Density(v)
+			if math.Abs(got-want) > 1e-14 {
+				t.Errorf("kernel %d: weighted density mismatch at %v: got %v, want %v", kernel, v, got, want)
+			}
+		}
+	}
+
+	kde := NewKDE(GaussianKernel, 0.5, []float64{1}, nil)
+	got := kde.Density(2)
+	want := math.Exp(-2) / (0.5 * math.Sqrt(2*math.Pi))
+	if math.Abs(got-want) > 1e-15 {
+		t.Errorf("unexpected Gaussian density: got %v, want %v", got, want)
+	}
+	kde = NewKDE(EpanechnikovKernel, 2, []float64{1}, nil)
+	if got, want := kde.Density(2), 0.75*0.75/2; math.Abs(got-want) > 1e-15 {
+		t.Errorf("unexpected Epanechnikov density: got %v, want %v", got, want)
+	}
+	if got := kde.Density(3.5); got != 0 {
+		t.Errorf("unexpected Epanechnikov density outside support: got %v", got)
+	}
+
+	if !panics(func() { NewKDE(GaussianKernel, 0, x, nil) }) {
+		t.Errorf("expected panic for zero bandwidth")
+	}
+	if !panics(func() { NewKDE(Kernel(-1), 1, x, nil) }) {
+		t.Errorf("expected panic for unknown kernel")
+	}
+}
+
+func TestSilvermanBandwidth(t *testing.T) {
+	// The scaled interquartile range is smaller than the standard deviation.
+	x := []float64{1, 2, 3, 4, 5, 50}
+	iqr := Quantile(0.75, LinInterp, x, nil) - Quantile(0.25, LinInterp, x, nil)
+	if iqr/1.34 >= StdDev(x, nil) {
+		t.Fatalf("test data do not exercise the interquartile range")
+	}
+	got := SilvermanBandwidth(GaussianKernel, x, nil)
+	want := 0.9 * iqr / 1.34 * math.Pow(6, -0.2)
+	if math.Abs(got-want) > 1e-14 {
+		t.Errorf("unexpected Gaussian bandwidth: got %v, want %v", got, want)
+	}
+	ratio := SilvermanBandwidth(EpanechnikovKernel, x, nil) / got
+	if math.Abs(ratio-2.2138) > 1e-4 {
+		t.Errorf("unexpected Epanechnikov bandwidth ratio: got %v, want 2.2138", ratio)
+	}
+}
+
+func TestCrossValidBandwidth(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	unimodal := make([]float64, 200)
+	bimodal := make([]float64, 200)
+	for i := range unimodal {
+		unimodal[i] = rnd.NormFloat64()
+		bimodal[i] = 0.5*rnd.NormFloat64() + float64(6*(i%2))
+	}
+	for _, kernel := range kernels {
+		hs := SilvermanBandwidth(kernel, unimodal, nil)
+		hcv := CrossValidBandwidth(kernel, unimodal, nil)
+		if hcv < hs/2 || hcv > 2*hs {
+			t.Errorf("kernel %d: cross-validated bandwidth far from rule of thumb for normal data: got %v, want about %v", kernel, hcv, hs)
+		}
+
+		// The rule of thumb oversmooths multimodal data.
+		hs = SilvermanBandwidth(kernel, bimodal, nil)
+		hcv = CrossValidBandwidth(kernel, bimodal, nil)
+		if hcv > hs/2 {
+			t.Errorf("kernel %d: cross-validated bandwidth not smaller for bimodal data: got %v, rule of thumb %v", kernel, hcv, hs)
+		}
+	}
+}
+
+func TestKDEMV(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	// In one dimension the estimate matches the univariate estimate.
+	x := make([]float64, 30)
+	for i := range x {
+		x[i] = rnd.NormFloat64()
+	}
+	for _, kernel := range kernels {
+		h := 0.7
+		uni := NewKDE(kernel, h, x, nil)
+		mv := NewKDEMV(kernel, mat.NewSymDense(1, []float64{h * h}), mat.NewDense(len(x), 1, x), nil)
+		for _, v := range []float64{-2, -0.3, 0, 0.7, 1.5} {
+			got := mv.Density([]float64{v})
+			want := uni.Density(v)
+			if math.Abs(got-want) > 1e-14 {
+				t.Errorf("kernel %d: one-dimensional density mismatch at %v: got %v, want %v", kernel, v, got, want)
+			}
+		}
+	}
+
+	// Compare with a direct evaluation using the inverse bandwidth matrix.
+	const n, d = 40, 3
+	data := mat.NewDense(n, d, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < d; j++ {
+			data.Set(i, j, rnd.NormFloat64()+float64(j))
+		}
+	}
+	weights := make([]float64, n)
+	for i := range weights {
+		weights[i] = rnd.Float64()
+	}
+	var bw mat.SymDense
+	SilvermanBandwidthMV(&bw, GaussianKernel, data, weights)
+	bw.SetSym(0, 1, bw.At(0, 1)+0.1)
+	var inv mat.Dense
+	if err := inv.Inverse(&bw); err != nil {
+		t.Fatalf("unexpected error inverting bandwidth: %v", err)
+	}
+	kde := NewKDEMV(GaussianKernel, &bw, data, weights)
+	if kde.Dim() != d {
+		t.Errorf("unexpected dimension: got %d, want %d", kde.Dim(), d)
+	}
+	var gotBW mat.SymDense
+	kde.BandwidthTo(&gotBW)
+	if !mat.EqualApprox(&gotBW, &bw, 1e-14) {
+		t.Errorf("unexpected bandwidth matrix")
+	}
+	pts := mat.NewDense(5, d, nil)
+	for i := 0; i < 5; i++ {
+		for j := 0; j < d; j++ {
+			pts.Set(i, j, rnd.NormFloat64()+float64(j))
+		}
+	}
+	dens := kde.DensityTo(nil, pts)
+	norm := 1 / (math.Pow(2*math.Pi, d/2.0) * math.Sqrt(mat.Det(&bw)) * floats.Sum(weights))
+	for p := 0; p < 5; p++ {
+		var want float64
+		for i := 0; i < n; i++ {
+			var diff mat.VecDense
+			diff.SubVec(pts.RowView(p), data.RowView(i))
+			want += weights[i] * math.Exp(-mat.Inner(&diff, &inv, &diff)/2)
+		}
+		want *= norm
+		if math.Abs(dens[p]-want) > 1e-12*want {
+			t.Errorf("unexpected density at point %d: got %v, want %v", p, dens[p], want)
+		}
+		if got := kde.Density(pts.RawRowView(p)); math.Abs(got-dens[p]) > 1e-14*want {
+			t.Errorf("Density and DensityTo mismatch at point %d: got %v, want %v", p, got, dens[p])
+		}
+	}
+
+	// A two-dimensional Epanechnikov estimate integrates to one.
+	data2 := mat.NewDense(10, 2, nil)
+	for i := 0; i < 10; i++ {
+		data2.Set(i, 0, rnd.NormFloat64())
+		data2.Set(i, 1, rnd.NormFloat64())
+	}
+	var bw2 mat.SymDense
+	SilvermanBandwidthMV(&bw2, EpanechnikovKernel, data2, nil)
+	kde = NewKDEMV(EpanechnikovKernel, &bw2, data2, nil)
+	const m = 301
+	grid := floats.Span(make([]float64, m), -8, 8)
+	step := grid[1] - grid[0]
+	var area float64
+	for _, u := range grid {
+		for _, v := range grid {
+			area += kde.Density([]float64{u, v})
+		}
+	}
+	area *= step * step
+	if math.Abs(area-1) > 1e-2 {
+		t.Errorf("two-dimensional density does not integrate to one: %v", area)
+	}
+
+	if !panics(func() { NewKDEMV(GaussianKernel, mat.NewSymDense(2, []float64{1, 2, 2, 1}), data2, nil) }) {
+		t.Errorf("expected panic for indefinite bandwidth")
+	}
+	if !panics(func() { NewKDEMV(GaussianKernel, mat.NewSymDense(1, []float64{1}), data2, nil) }) {
+		t.Errorf("expected panic for dimension mismatch")
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import "math"
+
+// Moments accumulates the weighted mean and the central moments up to the
+// fourth of a stream of values that are added one at a time, so that the
+// summary statistics of data too large to hold in memory can be computed in a
+// single pass. The statistics may be queried at any point. Accumulators of
+// separate parts of a data set, for example computed concurrently, can be
+// combined with Merge.
+//
+// The statistics reported by Moments match those computed by Mean, Variance,
+// Skew and ExKurtosis for the same values and weights, up to rounding. The
+// zero value of Moments is an empty accumulator ready to use.
+//
+// The updates use the numerically stable formulas given in
+//  P. Pébay. Formulas for Robust, One-Pass Parallel Computation of
+//  Covariances and Arbitrary-Order Statistical Moments. Technical Report
+//  SAND2008-6212, Sandia National Laboratories (2008).
+// which generalize the algorithm of Welford.
+type Moments struct {
+	n    int
+	w    float64
+	mean float64
+
+	// m2, m3 and m4 are the weighted sums of the second, third and
+	// fourth powers of the deviations from the mean.
+	m2, m3, m4 float64
+}
+
+// Add adds the value x with weight one to the accumulator.
+func (m *Moments) Add(x float64) {
+	m.AddWeighted(x, 1)
+}
+
+// AddWeighted adds the value x with the weight w to the accumulator.
+// AddWeighted will panic if w is negative.
+func (m *Moments) AddWeighted(x, w float64) {
+	if w < 0 {
+		panic("stat: negative weight")
+	}
+	m.n++
+	m.merge(w, x, 0, 0, 0)
+}
+
+// Merge adds the values accumulated in o to the receiver, so that the
+// receiver holds the statistics of the union of the two data sets. The
+// receiver and o may be the same.
+func (m *Moments) Merge(o *Moments) {
+	m.n += o.n
+	m.merge(o.w, o.mean, o.m2, o.m3, o.m4)
+}
+
+// merge combines the receiver with the statistics of a data set with total
+// weight wb, mean meanb and sums of powers of deviations m2b, m3b and m4b.
+func (m *Moments) merge(wb, meanb, m2b, m3b, m4b float64) {
+	if wb == 0 {
+		return
+	}
+	wa := m.w
+	w := wa + wb
+	d := meanb - m.mean
+	dw := d / w
+	m.m4 += m4b + d*dw*dw*dw*wa*wb*(wa*wa-wa*wb+wb*wb) +
+		6*dw*dw*(wa*wa*m2b+wb*wb*m.m2) + 4*dw*(wa*m3b-wb*m.m3)
+	m.m3 += m3b + d*dw*dw*wa*wb*(wa-wb) + 3*dw*(wa*m2b-wb*m.m2)
+	m.m2 += m2b + d*dw*wa*wb
+	m.mean += dw * wb
+	m.w = w
+}
+
+// Reset empties the accumulator.
+func (m *Moments) Reset() {
+	*m = Moments{}
+}
+
+// Count returns the number of values that have been added.
+func (m *Moments) Count() int {
+	return m.n
+}
+
+// SumWeights returns the sum of the weights of the values that have been
+// added.
+func (m *Moments) SumWeights() float64 {
+	return m.w
+}
+
+// Mean returns the weighted mean of the values. Mean returns NaN if the
+// accumulator is empty.
+func (m *Moments) Mean() float64 {
+	if m.w == 0 {
+		return math.NaN()
+	}
+	return m.mean
+}
+
+// Variance returns the unbiased weighted sample variance of the values,
+// computed as for the Variance function.
+func (m *Moments) Variance() float64 {
+	return m.m2 / (m.w - 1)
+}
+
+// StdDev returns the sample standard deviation of the values, the square root
+// of Variance.
+func (m *Moments) StdDev() float64 {
+	return math.Sqrt(m.Variance())
+}
+
+// Skew returns the skewness of the values, computed as for the Skew function.
+func (m *Moments) Skew() float64 {
+	std := m.StdDev()
+	return m.m3 / (std * std * std) * skewCorrection(m.w)
+}
+
+// ExKurtosis returns the population excess kurtosis of the values, computed as
+// for the ExKurtosis function.
+func (m *Moments) ExKurtosis() float64 {
+	v := m.Variance()
+	mul, offset := kurtosisCorrection(m.w)
+	return m.m4/(v*v)*mul - offset
+}
@@ -0,0 +1,97 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestMoments(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{5, 20, 1000} {
+		x := make([]float64, n)
+		weights := make([]float64, n)
+		for i := range x {
+			x[i] = 1e3 + rnd.ExpFloat64()
+			weights[i] = 0.5 + rnd.Float64()
+		}
+		for _, w := range [][]float64{nil, weights} {
+			var m Moments
+			for i, v := range x {
+				if w == nil {
+					m.Add(v)
+				} else {
+					m.AddWeighted(v, w[i])
+				}
+			}
+			checkMoments(t, "single", &m, x, w)
+
+			// Accumulate the data in three parts and merge.
+			var parts [3]Moments
+			for i, v := range x {
+				p := &parts[i%3]
+				if w == nil {
+					p.Add(v)
+				} else {
+					p.AddWeighted(v, w[i])
+				}
+			}
+			var merged Moments
+			for i := range parts {
+				merged.Merge(&parts[i])
+			}
+			checkMoments(t, "merged", &merged, x, w)
+		}
+	}
+
+	var m Moments
+	if !math.IsNaN(m.Mean()) {
+		t.Errorf("unexpected mean of empty accumulator: %v", m.Mean())
+	}
+	m.Add(1)
+	m.Add(3)
+	m.Merge(&m)
+	if m.Count() != 4 || m.SumWeights() != 4 || m.Mean() != 2 {
+		t.Errorf("unexpected result of self merge: count=%d weights=%v mean=%v", m.Count(), m.SumWeights(), m.Mean())
+	}
+	if got, want := m.Variance(), Variance([]float64{1, 3, 1, 3}, nil); math.Abs(got-want) > 1e-14 {
+		t.Errorf("unexpected variance after self merge: got %v, want %v", got, want)
+	}
+	m.Reset()
+	if m.Count() != 0 || m.SumWeights() != 0 {
+		t.Errorf("accumulator not empty after reset")
+	}
+}
+
+func checkMoments(t *testing.T, name string, m *Moments, x, weights []float64) {
+	t.Helper()
+	if m.Count() != len(x) {
+		t.Errorf("%s n=%d: unexpected count %d", name, len(x), m.Count())
+	}
+	sumWeights := float64(len(x))
+	if weights != nil {
+		sumWeights = floats.Sum(weights)
+	}
+	for _, test := range []struct {
+		stat      string
+		got, want float64
+	}{
+		{stat: "sum of weights", got: m.SumWeights(), want: sumWeights},
+		{stat: "mean", got: m.Mean(), want: Mean(x, weights)},
+		{stat: "variance", got: m.Variance(), want: Variance(x, weights)},
+		{stat: "standard deviation", got: m.StdDev(), want: StdDev(x, weights)},
+		{stat: "skew", got: m.Skew(), want: Skew(x, weights)},
+		{stat: "excess kurtosis", got: m.ExKurtosis(), want: ExKurtosis(x, weights)},
+	} {
+		if !floats.EqualWithinAbsOrRel(test.got, test.want, 1e-10, 1e-8) {
+			t.Errorf("%s n=%d weighted=%t: unexpected %s: got %v, want %v", name, len(x), weights != nil, test.stat, test.got, test.want)
+		}
+	}
+}
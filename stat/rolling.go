@@ -0,0 +1,376 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"container/heap"
+	"math"
+)
+
+// rollingStat specifies the statistics maintained by a Rolling.
+type rollingStat int
+
+const (
+	rollMoments rollingStat = 1 << iota
+	rollMin
+	rollMax
+	rollMedian
+
+	rollAll = rollMoments | rollMin | rollMax | rollMedian
+)
+
+// Rolling computes statistics of the most recent values of a stream in a
+// sliding window of fixed length, for example to smooth a time series. Values
+// are added with Push, which evicts the oldest value once the window is full,
+// and the statistics of the values in the window may be queried at any point.
+//
+// The mean and variance are updated in constant time, the minimum and maximum
+// in amortized constant time and the median in time logarithmic in the length
+// of the window. The statistics are not defined if NaN values are pushed.
+type Rolling struct {
+	track rollingStat
+
+	// buf is a ring buffer holding the values in the window, with the
+	// value pushed at sequence number i held in buf[i%len(buf)].
+	buf []float64
+	// seq is the number of values that have been pushed.
+	seq int
+
+	mean, m2 float64
+
+	min, max monoDeque
+	med      medianHeaps
+}
+
+// NewRolling returns a new Rolling with a window of the given length.
+// NewRolling will panic if window is not positive.
+func NewRolling(window int) *Rolling {
+	return newRolling(window, rollAll)
+}
+
+func newRolling(window int, track rollingStat) *Rolling {
+	if window <= 0 {
+		panic("stat: non-positive window length")
+	}
+	r := &Rolling{
+		track: track,
+		buf:   make([]float64, window),
+	}
+	if track&rollMin != 0 {
+		r.min = newMonoDeque(window, func(a, b float64) bool { return a <= b })
+	}
+	if track&rollMax != 0 {
+		r.max = newMonoDeque(window, func(a, b float64) bool { return a >= b })
+	}
+	if track&rollMedian != 0 {
+		r.med = newMedianHeaps(r.buf)
+	}
+	return r
+}
+
+// Push adds x to the window, evicting the oldest value if the window is full.
+func (r *Rolling) Push(x float64) {
+	w := len(r.buf)
+	slot := r.seq % w
+	full := r.seq >= w
+	old := r.buf[slot]
+
+	if r.track&rollMoments != 0 {
+		if full {
+			// Replace old by x in the window of w values.
+			d := x - old
+			mean := r.mean + d/float64(w)
+			r.m2 += d * (x - mean + old - r.mean)
+			r.mean = mean
+			if r.m2 < 0 {
+				r.m2 = 0
+			}
+		} else {
+			n := float64(r.seq + 1)
+			d := x - r.mean
+			r.mean += d / n
+			r.m2 += d * (x - r.mean)
+		}
+	}
+	if r.track&rollMedian != 0 && full {
+		r.med.remove(slot)
+	}
+	r.buf[slot] = x
+	if r.track&rollMedian != 0 {
+		r.med.insert(slot)
+	}
+	if r.track&rollMin != 0 {
+		r.min.push(r.seq, r.buf)
+	}
+	if r.track&rollMax != 0 {
+		r.max.push(r.seq, r.buf)
+	}
+	r.seq++
+}
+
+// Window returns the length of the window.
+func (r *Rolling) Window() int {
+	return len(r.buf)
+}
+
+// Len returns the number of values in the window, which is the window length
+// once the window is full.
+func (r *Rolling) Len() int {
+	if r.seq < len(r.buf) {
+		return r.seq
+	}
+	return len(r.buf)
+}
+
+// Full returns whether the window is full.
+func (r *Rolling) Full() bool {
+	return r.seq >= len(r.buf)
+}
+
+// Reset empties the window.
+func (r *Rolling) Reset() {
+	*r = *newRolling(len(r.buf), r.track)
+}
+
+// Mean returns the mean of the values in the window. Mean returns NaN if the
+// window is empty.
+func (r *Rolling) Mean() float64 {
+	if r.seq == 0 {
+		return math.NaN()
+	}
+	return r.mean
+}
+
+// Variance returns the unbiased sample variance of the values in the window.
+func (r *Rolling) Variance() float64 {
+	return r.m2 / float64(r.Len()-1)
+}
+
+// Min returns the smallest value in the window. Min returns NaN if the window
+// is empty.
+func (r *Rolling) Min() float64 {
+	return r.min.front(r.buf)
+}
+
+// Max returns the largest value in the window. Max returns NaN if the window
+// is empty.
+func (r *Rolling) Max() float64 {
+	return r.max.front(r.buf)
+}
+
+// Median returns the median of the values in the window, the mean of the two
+// middle values if the window holds an even number of values. Median returns
+// NaN if the window is empty.
+func (r *Rolling) Median() float64 {
+	return r.med.median()
+}
+
+// monoDeque is a double-ended queue of the sequence numbers of the values in
+// a window, holding the candidates for the extreme value of the window in
+// order of their arrival.
+type monoDeque struct {
+	// seqs is a ring buffer of length equal to the window length
+	// holding n sequence numbers from the index head.
+	seqs    []int
+	head, n int
+
+	// keep returns whether the value a that precedes b remains a
+	// candidate once b arrives.
+	keep func(a, b float64) bool
+}
+
+func newMonoDeque(window int, keep func(a, b float64) bool) monoDeque {
+	return monoDeque{seqs: make([]int, window), keep: keep}
+}
+
+// push adds the value with sequence number seq held in buf to the deque.
+func (q *monoDeque) push(seq int, buf []float64) {
+	w := len(q.seqs)
+	// Drop the front if it leaves the window.
+	if q.n > 0 && q.seqs[q.head] <= seq-w {
+		q.head = (q.head + 1) % w
+		q.n--
+	}
+	// Drop values at the back that can no longer be extreme.
+	x := buf[seq%w]
+	for q.n > 0 {
+		back := q.seqs[(q.head+q.n-1)%w]
+		if q.keep(buf[back%w], x) {
+			break
+		}
+		q.n--
+	}
+	q.seqs[(q.head+q.n)%w] = seq
+	q.n++
+}
+
+// front returns the extreme value of the window held in buf.
+func (q *monoDeque) front(buf []float64) float64 {
+	if q.n == 0 {
+		return math.NaN()
+	}
+	return buf[q.seqs[q.head]%len(q.seqs)]
+}
+
+// medianHeaps maintains the median of the values in a window with a max-heap
+// holding the lower half of the values and a min-heap holding the upper half.
+// The heaps hold the indices of the values in the ring buffer of the window.
+type medianHeaps struct {
+	lo, hi *slotHeap
+	inLo   []bool
+}
+
+func newMedianHeaps(buf []float64) medianHeaps {
+	pos := make([]int, len(buf))
+	return medianHeaps{
+		lo:   &slotHeap{vals: buf, pos: pos, max: true},
+		hi:   &slotHeap{vals: buf, pos: pos},
+		inLo: make([]bool, len(buf)),
+	}
+}
+
+// insert adds the value in slot to the heaps.
+func (m *medianHeaps) insert(slot int) {
+	if m.lo.Len() == 0 || m.lo.vals[slot] <= m.lo.vals[m.lo.slots[0]] {
+		m.inLo[slot] = true
+		heap.Push(m.lo, slot)
+	} else {
+		m.inLo[slot] = false
+		heap.Push(m.hi, slot)
+	}
+	m.rebalance()
+}
+
+// remove removes the value in slot from the heaps.
+func (m *medianHeaps) remove(slot int) {
+	if m.inLo[slot] {
+		heap.Remove(m.lo, m.lo.pos[slot])
+	} else {
+		heap.Remove(m.hi, m.hi.pos[slot])
+	}
+	m.rebalance()
+}
+
+// rebalance restores the invariant that lo holds the same number of values
+// as hi or one more.
+func (m *medianHeaps) rebalance() {
+	switch {
+	case m.lo.Len() > m.hi.Len()+1:
+		slot := heap.Pop(m.lo).(int)
+		m.inLo[slot] = false
+		heap.Push(m.hi, slot)
+	case m.hi.Len() > m.lo.Len():
+		slot := heap.Pop(m.hi).(int)
+		m.inLo[slot] = true
+		heap.Push(m.lo, slot)
+	}
+}
+
+func (m *medianHeaps) median() float64 {
+	switch {
+	case m.lo.Len() == 0:
+		return math.NaN()
+	case m.lo.Len() > m.hi.Len():
+		return m.lo.vals[m.lo.slots[0]]
+	default:
+		return (m.lo.vals[m.lo.slots[0]] + m.hi.vals[m.hi.slots[0]]) / 2
+	}
+}
+
+// slotHeap is a heap of indices into vals ordered by their values, recording
+// the position of each index in the heap in pos.
+type slotHeap struct {
+	slots []int
+	vals  []float64
+	pos   []int
+	max   bool
+}
+
+func (h *slotHeap) Len() int { return len(h.slots) }
+func (h *slotHeap) Less(i, j int) bool {
+	if h.max {
+		return h.vals[h.slots[i]] > h.vals[h.slots[j]]
+	}
+	return h.vals[h.slots[i]] < h.vals[h.slots[j]]
+}
+func (h *slotHeap) Swap(i, j int) {
+	h.slots[i], h.slots[j] = h.slots[j], h.slots[i]
+	h.pos[h.slots[i]] = i
+	h.pos[h.slots[j]] = j
+}
+func (h *slotHeap) Push(x interface{}) {
+	slot := x.(int)
+	h.pos[slot] = len(h.slots)
+	h.slots = append(h.slots, slot)
+}
+func (h *slotHeap) Pop() interface{} {
+	slot := h.slots[len(h.slots)-1]
+	h.slots = h.slots[:len(h.slots)-1]
+	return slot
+}
+
+// RollingMean returns the means of the windows of length window that slide
+// over x, so that dst[i] is the mean of x[i:i+window]. If dst is nil, a new
+// slice is allocated, otherwise dst must have length len(x)-window+1.
+// RollingMean will panic if window is not in [1, len(x)].
+func RollingMean(dst, x []float64, window int) []float64 {
+	return rolling(dst, x, window, rollMoments, (*Rolling).Mean)
+}
+
+// RollingVariance returns the unbiased sample variances of the windows of
+// length window that slide over x, so that dst[i] is the variance of
+// x[i:i+window]. If dst is nil, a new slice is allocated, otherwise dst must
+// have length len(x)-window+1. RollingVariance will panic if window is not in
+// [1, len(x)].
+func RollingVariance(dst, x []float64, window int) []float64 {
+	return rolling(dst, x, window, rollMoments, (*Rolling).Variance)
+}
+
+// RollingMin returns the minima of the windows of length window that slide
+// over x, so that dst[i] is the minimum of x[i:i+window]. If dst is nil, a
+// new slice is allocated, otherwise dst must have length len(x)-window+1.
+// RollingMin will panic if window is not in [1, len(x)].
+func RollingMin(dst, x []float64, window int) []float64 {
+	return rolling(dst, x, window, rollMin, (*Rolling).Min)
+}
+
+// RollingMax returns the maxima of the windows of length window that slide
+// over x, so that dst[i] is the maximum of x[i:i+window]. If dst is nil, a
+// new slice is allocated, otherwise dst must have length len(x)-window+1.
+// RollingMax will panic if window is not in [1, len(x)].
+func RollingMax(dst, x []float64, window int) []float64 {
+	return rolling(dst, x, window, rollMax, (*Rolling).Max)
+}
+
+// RollingMedian returns the medians of the windows of length window that
+// slide over x, so that dst[i] is the median of x[i:i+window]. If dst is nil,
+// a new slice is allocated, otherwise dst must have length len(x)-window+1.
+// RollingMedian will panic if window is not in [1, len(x)].
+func RollingMedian(dst, x []float64, window int) []float64 {
+	return rolling(dst, x, window, rollMedian, (*Rolling).Median)
+}
+
+// rolling returns the statistic stat of the windows of the given length that
+// slide over x, maintaining only the statistics specified by track.
+func rolling(dst, x []float64, window int, track rollingStat, stat func(*Rolling) float64) []float64 {
+	if window < 1 || len(x) < window {
+		panic("stat: window length out of range")
+	}
+	n := len(x) - window + 1
+	if dst == nil {
+		dst = make([]float64, n)
+	}
+	if len(dst) != n {
+		panic("stat: slice length mismatch")
+	}
+	r := newRolling(window, track)
+	for i, v := range x {
+		r.Push(v)
+		if i >= window-1 {
+			dst[i-window+1] = stat(r)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,113 @@
+// Copyright ©2019 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stat
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestRolling(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, window := range []int{1, 2, 3, 8, 25} {
+		x := make([]float64, 200)
+		for i := range x {
+			// Repeated values exercise ties in the median heaps.
+			x[i] = float64(rnd.Intn(20)) + 100
+		}
+		r := NewRolling(window)
+		for i, v := range x {
+			r.Push(v)
+			lo := i - window + 1
+			if lo < 0 {
+				lo = 0
+			}
+			win := x[lo : i+1]
+			if r.Len() != len(win) || r.Full() != (len(win) == window) {
+				t.Fatalf("window=%d i=%d: unexpected length %d", window, i, r.Len())
+			}
+			if !floats.EqualWithinAbsOrRel(r.Mean(), Mean(win, nil), 1e-10, 1e-10) {
+				t.Errorf("window=%d i=%d: unexpected mean: got %v, want %v", window, i, r.Mean(), Mean(win, nil))
+			}
+			if len(win) > 1 && !floats.EqualWithinAbsOrRel(r.Variance(), Variance(win, nil), 1e-9, 1e-9) {
+				t.Errorf("window=%d i=%d: unexpected variance: got %v, want %v", window, i, r.Variance(), Variance(win, nil))
+			}
+			if r.Min() != floats.Min(win) {
+				t.Errorf("window=%d i=%d: unexpected min: got %v, want %v", window, i, r.Min(), floats.Min(win))
+			}
+			if r.Max() != floats.Max(win) {
+				t.Errorf("window=%d i=%d: unexpected max: got %v, want %v", window, i, r.Max(), floats.Max(win))
+			}
+			if want := naiveMedian(win); r.Median() != want {
+				t.Errorf("window=%d i=%d: unexpected median: got %v, want %v", window, i, r.Median(), want)
+			}
+		}
+
+		if window > len(x) {
+			continue
+		}
+		n := len(x) - window + 1
+		for _, test := range []struct {
+			name string
+			fn   func(dst, x []float64, window int) []float64
+			want func(x []float64) float64
+		}{
+			{name: "mean", fn: RollingMean, want: func(x []float64) float64 { return Mean(x, nil) }},
+			{name: "variance", fn: RollingVariance, want: func(x []float64) float64 { return Variance(x, nil) }},
+			{name: "min", fn: RollingMin, want: floats.Min},
+			{name: "max", fn: RollingMax, want: floats.Max},
+			{name: "median", fn: RollingMedian, want: naiveMedian},
+		} {
+			got := test.fn(make([]float64, n), x, window)
+			for i, v := range got {
+				want := test.want(x[i : i+window])
+				if window == 1 && test.name == "variance" {
+					if !math.IsNaN(v) {
+						t.Errorf("window=1: expected NaN variance, got %v", v)
+					}
+					continue
+				}
+				if !floats.EqualWithinAbsOrRel(v, want, 1e-9, 1e-9) {
+					t.Errorf("%s window=%d: unexpected value at %d: got %v, want %v", test.name, window, i, v, want)
+				}
+			}
+		}
+	}
+
+	r := NewRolling(3)
+	for _, f := range []func() float64{r.Mean, r.Min, r.Max, r.Median} {
+		if !math.IsNaN(f()) {
+			t.Errorf("expected NaN for empty window")
+		}
+	}
+	r.Push(1)
+	r.Push(2)
+	r.Reset()
+	if r.Len() != 0 || !math.IsNaN(r.Max()) {
+		t.Errorf("window not empty after reset")
+	}
+
+	if !panics(func() { RollingMean(nil, []float64{1, 2}, 3) }) {
+		t.Errorf("expected panic for window longer than data")
+	}
+	if !panics(func() { RollingMean(make([]float64, 2), []float64{1, 2}, 2) }) {
+		t.Errorf("expected panic for mismatched destination length")
+	}
+}
+
+func naiveMedian(x []float64) float64 {
+	s := append([]float64(nil), x...)
+	sort.Float64s(s)
+	n := len(s)
+	if n%2 == 1 {
+		return s[n/2]
+	}
+	return (s[n/2-1] + s[n/2]) / 2
+}